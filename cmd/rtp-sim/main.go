@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/wfunc/slot-game/internal/game/slot"
+)
+
+func main() {
+	// 命令行参数
+	var (
+		engineType  = flag.String("engine", "slot", "引擎类型(slot/cascade/golden_wild)")
+		configPath  = flag.String("config", "", "配置文件路径(slot引擎为SlotConfig JSON，消除引擎为AlgorithmConfig JSON)")
+		cascadePath = flag.String("cascade", "", "消除配置文件路径(CascadeConfig JSON，缺省使用默认配置)")
+		machineID   = flag.String("machine", "classic_fruit", "未指定配置文件时使用的预设机器ID(仅slot引擎)")
+		spins       = flag.Int64("spins", 1000000, "仿真旋转次数")
+		workers     = flag.Int("workers", runtime.NumCPU(), "并行工作协程数")
+		bet         = flag.Int64("bet", 0, "单次下注额(缺省使用配置的默认下注)")
+		seed        = flag.Int64("seed", 0, "主随机种子(0表示使用加密随机数，非0可复现结果)")
+		confidence  = flag.Float64("confidence", 0.95, "RTP置信区间的置信水平")
+		output      = flag.String("out", "", "报告输出前缀(生成 <out>.json 和 <out>.csv)，缺省输出JSON到标准输出")
+		format      = flag.String("format", "both", "输出格式(json/csv/both)，仅在指定 -out 时生效")
+		mode        = flag.String("mode", "", "覆盖配置中的引擎模式(adaptive/pure_math)")
+		theory      = flag.Bool("theory", false, "输出精确理论RTP（全量枚举/卷积，仅slot引擎），不执行仿真")
+		gambleMode  = flag.String("gamble", "", "中奖后的博倍玩法(color/high_low)，缺省直接收分(仅slot引擎)")
+		gambleRound = flag.Int("gamble-rounds", 1, "每次中奖最多博倍次数")
+	)
+	flag.Parse()
+
+	if *theory {
+		if err := printTheoreticalRTP(*configPath, *machineID); err != nil {
+			fmt.Fprintf(os.Stderr, "计算理论RTP失败: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts := &slot.SimulationOptions{
+		Engine:          *engineType,
+		Spins:           *spins,
+		Workers:         *workers,
+		Seed:            *seed,
+		BetAmount:       *bet,
+		ConfidenceLevel: *confidence,
+	}
+	if *gambleMode != "" {
+		opts.Gamble = &slot.GambleStrategy{Mode: slot.GambleMode(*gambleMode), Rounds: *gambleRound}
+	}
+
+	factory, err := buildFactory(opts, *configPath, *cascadePath, *machineID, slot.EngineMode(*mode))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 支持 Ctrl+C 中断
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	fmt.Fprintf(os.Stderr, "开始仿真: 引擎=%s 配置=%s 旋转=%d 协程=%d 下注=%d\n",
+		opts.Engine, opts.ConfigName, opts.Spins, opts.Workers, opts.BetAmount)
+
+	report, err := slot.RunSimulation(ctx, opts, factory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "仿真失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "仿真完成: RTP=%.4f%% [%.4f%%, %.4f%%] 命中率=%.2f%% 波动指数=%.2f 最高赢取=%d次 耗时=%dms\n",
+		report.RTP*100, report.RTPLower*100, report.RTPUpper*100,
+		report.HitFrequency*100, report.VolatilityIndex, report.MaxWinHits, report.DurationMs)
+	if report.GambleRounds > 0 {
+		fmt.Fprintf(os.Stderr, "博倍: 竞猜=%d次 返还率=%.4f%% 理论=%.4f%%\n",
+			report.GambleRounds, report.GambleRTP*100, report.GambleTheoryRTP*100)
+	}
+
+	if err := writeReport(report, *output, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "输出报告失败: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// buildFactory 根据引擎类型加载配置并创建旋转函数工厂
+func buildFactory(opts *slot.SimulationOptions, configPath, cascadePath, machineID string, mode slot.EngineMode) (slot.SimulationSpinnerFactory, error) {
+	switch opts.Engine {
+	case "slot":
+		config := slot.GetConfigByID(machineID)
+		if configPath != "" {
+			config = &slot.SlotConfig{}
+			if err := loadJSON(configPath, config); err != nil {
+				return nil, err
+			}
+		}
+		if mode != "" {
+			config.Mode = mode
+		}
+		if err := slot.ValidateConfig(config); err != nil {
+			return nil, fmt.Errorf("配置校验失败: %w", err)
+		}
+		if opts.BetAmount == 0 {
+			opts.BetAmount = config.DefaultBet
+		}
+		opts.ConfigName = config.MachineID
+		opts.TargetRTP = config.TargetRTP
+		return slot.NewSlotGambleSimulationFactory(config, opts.BetAmount, opts.Gamble), nil
+
+	case "cascade", "golden_wild":
+		if configPath == "" {
+			return nil, fmt.Errorf("%s 引擎需要通过 -config 指定 AlgorithmConfig", opts.Engine)
+		}
+		algorithmConfig := &slot.AlgorithmConfig{}
+		if err := loadJSON(configPath, algorithmConfig); err != nil {
+			return nil, err
+		}
+		if mode != "" {
+			algorithmConfig.Mode = mode
+		}
+		cascadeConfig := slot.GetDefaultCascadeConfig()
+		if cascadePath != "" {
+			if err := loadJSON(cascadePath, cascadeConfig); err != nil {
+				return nil, err
+			}
+		}
+		if opts.BetAmount == 0 {
+			opts.BetAmount = 100
+		}
+		opts.ConfigName = configPath
+		opts.TargetRTP = algorithmConfig.TargetRTP
+		if opts.Engine == "cascade" {
+			return slot.NewCascadeSimulationFactory(algorithmConfig, cascadeConfig, opts.BetAmount), nil
+		}
+		return slot.NewGoldenWildSimulationFactory(algorithmConfig, cascadeConfig, opts.BetAmount), nil
+	}
+	return nil, fmt.Errorf("未知的引擎类型: %s", opts.Engine)
+}
+
+// printTheoreticalRTP 计算并输出slot配置的精确理论RTP
+func printTheoreticalRTP(configPath, machineID string) error {
+	config := slot.GetConfigByID(machineID)
+	if configPath != "" {
+		config = &slot.SlotConfig{}
+		if err := loadJSON(configPath, config); err != nil {
+			return err
+		}
+	}
+
+	result, err := slot.CalculateTheoreticalRTP(config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "理论RTP: 总计=%.4f%% 支付线=%.4f%% Scatter=%.4f%% 奖励=%.4f%% 免费旋转=%.4f%% (%s)\n",
+		result.TotalRTP*100, result.LineRTP*100, result.ScatterRTP*100,
+		result.BonusRTP*100, result.FreeSpinRTP*100, result.Method)
+
+	// 各认证卷轴组的理论RTP
+	reelSets := make([]*slot.TheoreticalRTP, 0, len(config.ReelSets))
+	for _, set := range config.ReelSets {
+		setResult, err := slot.CalculateReelSetRTP(config, set)
+		if err != nil {
+			return fmt.Errorf("卷轴组 %s: %w", set.ID, err)
+		}
+		fmt.Fprintf(os.Stderr, "卷轴组 %s: 理论RTP=%.4f%%\n", set.ID, setResult.TotalRTP*100)
+		reelSets = append(reelSets, setResult)
+	}
+
+	report := map[string]interface{}{
+		"default":   result,
+		"reel_sets": reelSets,
+	}
+
+	// 加注和购买免费游戏按实际扣费折算的理论RTP
+	if config.Ante != nil {
+		anteRTP, err := slot.CalculateAnteRTP(config)
+		if err != nil {
+			return fmt.Errorf("加注: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "加注(%.2f倍): 理论RTP=%.4f%%\n", config.Ante.Multiplier, anteRTP*100)
+		report["ante_rtp"] = anteRTP
+	}
+	if config.BuyFeature != nil {
+		buyRTP, err := slot.CalculateBuyFeatureRTP(config)
+		if err != nil {
+			return fmt.Errorf("购买免费游戏: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "购买免费游戏(%.0f倍): 理论RTP=%.4f%%\n", config.BuyFeature.PriceMultiplier, buyRTP*100)
+		report["buy_feature_rtp"] = buyRTP
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// loadJSON 读取JSON配置文件
+func loadJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return nil
+}
+
+// writeReport 输出报告
+func writeReport(report *slot.SimulationReport, output, format string) error {
+	if output == "" {
+		return report.WriteJSON(os.Stdout)
+	}
+
+	if format == "json" || format == "both" {
+		if err := writeFile(output+".json", report.WriteJSON); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "已生成 %s.json\n", output)
+	}
+	if format == "csv" || format == "both" {
+		if err := writeFile(output+".csv", report.WriteCSV); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "已生成 %s.csv\n", output)
+	}
+	return nil
+}
+
+// writeFile 创建文件并写入报告
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -17,8 +17,10 @@ import (
 	"github.com/wfunc/slot-game/internal/database"
 	"github.com/wfunc/slot-game/internal/errors"
 	"github.com/wfunc/slot-game/internal/game"
+	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/hardware"
 	"github.com/wfunc/slot-game/internal/logger"
+	"github.com/wfunc/slot-game/internal/pb"
 	"github.com/wfunc/slot-game/internal/service"
 	"go.uber.org/zap"
 )
@@ -43,6 +45,7 @@ type Server struct {
 	stm32Controller  *hardware.STM32Controller    // STM32控制器
 	acmController    *hardware.ACMController      // ACM控制器
 	serialLogService *service.SerialLogService    // 串口日志服务
+	themePacks       *slot.ThemePackLoader        // 主题包加载器
 	cleanupTicker    *time.Ticker
 	// mqttClient    *mqtt.Client
 
@@ -176,6 +179,14 @@ func (s *Server) initComponents() error {
 		}
 	}
 
+	// 必中奖池（JPALL）的种子用服务端密钥加密保存，未配置密钥时不能启动
+	if err := slot.SetJackpotSeedKey(s.cfg.Security.Encryption.Key); err != nil {
+		return errors.Wrap(err, errors.ErrConfigMissing, "未配置 security.encryption.key，无法加密必中奖池的种子")
+	}
+	
+	// 加载主题包（失败不影响启动，使用内置主题）
+	s.initThemePacks()
+
 	// 初始化HTTP路由（依赖串口控制器）
 	if err := s.initHTTPRouter(); err != nil {
 		return err
@@ -241,7 +252,10 @@ func (s *Server) initHTTPRouter() error {
 	}
 
 	// 创建路由器（传递串口控制器）
-	s.router = api.NewRouter(db, serviceConfig, s.logger, s.serialController)
+	s.router = api.NewRouter(db, serviceConfig, s.logger, s.serialController, s.themePacks)
+	s.router.SetDevice(s.cfg.Game.Slot.DeviceID, s.cfg.Game.Slot.DeviceNo)
+	s.initAnimalProfit()
+	s.router.Start(s.ctx)
 	
 	// 创建HTTP服务器
 	s.httpServer = &http.Server{
@@ -255,6 +269,52 @@ func (s *Server) initHTTPRouter() error {
 	return nil
 }
 
+// initThemePacks 加载主题包并监听目录变化
+func (s *Server) initThemePacks() {
+	themeDir := s.cfg.Game.Slot.ThemeDir
+	s.logger.Info("加载主题包...", zap.String("dir", themeDir))
+	
+	s.themePacks = slot.NewThemePackLoader(themeDir, slot.NewThemeManager())
+	// 各拉霸机类型的主题需覆盖对应引擎配置的全部符号，未单独设置的类型按麻将校验
+	s.themePacks.RequireSymbols(0, slot.GetMahjongAlgorithmConfig())
+	s.themePacks.RequireSymbols(int32(pb.ESlotType_e_slot_type_mahjong), slot.GetMahjongAlgorithmConfig())
+	s.themePacks.RequireSlotSymbols(int32(pb.ESlotType_e_slot_type_pharaoh), slot.GetPharaohConfig())
+	s.themePacks.RequireSlotSymbols(int32(pb.ESlotType_e_slot_type_777), slot.GetLuckySevenConfig())
+	
+	if err := s.themePacks.LoadAll(); err != nil {
+		s.logger.Warn("部分主题包加载失败", zap.Error(err))
+	}
+	
+	err := s.themePacks.Watch(s.ctx, func(err error) {
+		if err != nil {
+			s.logger.Warn("主题包重新加载失败", zap.Error(err))
+			return
+		}
+		s.logger.Info("主题包已重新加载")
+	})
+	if err != nil {
+		s.logger.Warn("监听主题目录失败，主题包修改后需重启生效", zap.Error(err))
+	}
+}
+
+// initAnimalProfit 加载动物房间的盈亏控制配置（配置无效时使用默认配置）
+func (s *Server) initAnimalProfit() {
+	profit := s.cfg.Game.Animal.Profit
+	err := s.router.SetAnimalProfit(api.AnimalProfitConfig{
+		TargetRTP:       profit.TargetRTP,
+		GlobalTargetRTP: profit.GlobalTargetRTP,
+		HouseEdgeBand:   profit.HouseEdgeBand,
+		MaxAdjust:       profit.MaxAdjust,
+		PumpRTP:         profit.PumpRTP,
+		PumpFactor:      profit.PumpFactor,
+		WarmupBet:       profit.WarmupBet,
+		ExpectedShots:   profit.ExpectedShots,
+	})
+	if err != nil {
+		s.logger.Warn("动物房间盈亏配置无效，使用默认配置", zap.Error(err))
+	}
+}
+
 // initGameEngine 初始化游戏引擎和恢复管理器
 func (s *Server) initGameEngine() error {
 	s.logger.Info("初始化游戏引擎和恢复管理器...")
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wfunc/slot-game/internal/game/animal"
+	ws "github.com/wfunc/slot-game/internal/websocket"
+)
+
+// AnimalProfitHandler 动物房间实时盈亏API
+type AnimalProfitHandler struct {
+	game   *ws.AnimalHandler        // /ws/game 的动物房间
+	binary *ws.BinaryProtocolRouter // 二进制协议的动物房间
+}
+
+// AnimalProfitConfig 动物房间盈亏控制配置（期望打击次数按动物名配置）
+type AnimalProfitConfig struct {
+	TargetRTP       float64            `json:"target_rtp"`
+	GlobalTargetRTP float64            `json:"global_target_rtp"`
+	HouseEdgeBand   float64            `json:"house_edge_band"`
+	MaxAdjust       float64            `json:"max_adjust"`
+	PumpRTP         float64            `json:"pump_rtp"`
+	PumpFactor      float64            `json:"pump_factor"`
+	WarmupBet       uint64             `json:"warmup_bet"`
+	ExpectedShots   map[string]float64 `json:"expected_shots,omitempty"`
+}
+
+// NewAnimalProfitHandler 创建动物房间实时盈亏API
+func NewAnimalProfitHandler(game *ws.AnimalHandler, binary *ws.BinaryProtocolRouter) *AnimalProfitHandler {
+	return &AnimalProfitHandler{
+		game:   game,
+		binary: binary,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *AnimalProfitHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/animal-profit", h.GetProfit)           // 各房间和全局的P值、W值、RTP
+	router.GET("/animal-profit/config", h.GetConfig)    // 当前的盈亏控制配置
+	router.PUT("/animal-profit/config", h.UpdateConfig) // 修改盈亏控制配置（立即应用到所有动物房间）
+}
+
+// GetProfit 获取各动物房间和全局的实时盈亏
+func (h *AnimalProfitHandler) GetProfit(c *gin.Context) {
+	data := gin.H{
+		"global": animal.GlobalProfit().Stats(animal.CurrentProfitConfig().GlobalTargetRTP),
+	}
+	if h.game != nil {
+		data["game"] = h.game.ProfitStats()
+	}
+	if h.binary != nil {
+		data["binary"] = h.binary.ProfitStats()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": data,
+	})
+}
+
+// GetConfig 获取当前的盈亏控制配置
+func (h *AnimalProfitHandler) GetConfig(c *gin.Context) {
+	config := animal.CurrentProfitConfig()
+	shots := make(map[string]float64, len(config.ExpectedShots))
+	for kind, n := range config.ExpectedShots {
+		shots[kind.String()] = n
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": AnimalProfitConfig{
+			TargetRTP:       config.TargetRTP,
+			GlobalTargetRTP: config.GlobalTargetRTP,
+			HouseEdgeBand:   config.HouseEdgeBand,
+			MaxAdjust:       config.MaxAdjust,
+			PumpRTP:         config.PumpRTP,
+			PumpFactor:      config.PumpFactor,
+			WarmupBet:       config.WarmupBet,
+			ExpectedShots:   shots,
+		},
+	})
+}
+
+// UpdateConfig 修改盈亏控制配置
+func (h *AnimalProfitHandler) UpdateConfig(c *gin.Context) {
+	var req AnimalProfitConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的配置", "message": err.Error()})
+		return
+	}
+
+	if err := h.SetConfig(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的配置", "message": err.Error()})
+		return
+	}
+
+	h.GetConfig(c)
+}
+
+// SetConfig 校验并应用盈亏控制配置：之后新建的房间和已有的动物房间都使用该配置
+func (h *AnimalProfitHandler) SetConfig(req AnimalProfitConfig) error {
+	shots, err := animal.ParseExpectedShots(req.ExpectedShots)
+	if err != nil {
+		return err
+	}
+
+	config := animal.ProfitConfig{
+		TargetRTP:       req.TargetRTP,
+		GlobalTargetRTP: req.GlobalTargetRTP,
+		HouseEdgeBand:   req.HouseEdgeBand,
+		MaxAdjust:       req.MaxAdjust,
+		PumpRTP:         req.PumpRTP,
+		PumpFactor:      req.PumpFactor,
+		WarmupBet:       req.WarmupBet,
+		ExpectedShots:   shots,
+	}
+	if err := animal.SetCurrentProfitConfig(config); err != nil {
+		return err
+	}
+
+	config = animal.CurrentProfitConfig()
+	if h.game != nil {
+		h.game.SetProfitConfig(config)
+	}
+	if h.binary != nil {
+		h.binary.SetProfitConfig(config)
+	}
+	return nil
+}
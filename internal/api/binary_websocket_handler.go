@@ -19,10 +19,10 @@ type BinaryWebSocketHandler struct {
 }
 
 // NewBinaryWebSocketHandler 创建二进制 WebSocket处理器
-func NewBinaryWebSocketHandler(db *gorm.DB, logger *zap.Logger) *BinaryWebSocketHandler {
+func NewBinaryWebSocketHandler(db *gorm.DB, logger *zap.Logger, arena *ws.ActivityArena) *BinaryWebSocketHandler {
 	return &BinaryWebSocketHandler{
 		db:     db,
-		router: ws.NewBinaryProtocolRouter(db, logger),
+		router: ws.NewBinaryProtocolRouter(db, logger, arena),
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  4096,
 			WriteBufferSize: 4096,
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/repository"
+	"gorm.io/gorm"
+)
+
+// JackpotHandler JP奖池管理API
+type JackpotHandler struct {
+	db   *gorm.DB
+	repo *repository.JackpotRepository
+}
+
+// NewJackpotHandler 创建JP奖池管理API
+func NewJackpotHandler(db *gorm.DB) *JackpotHandler {
+	return &JackpotHandler{
+		db:   db,
+		repo: repository.NewJackpotRepository(db),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *JackpotHandler) RegisterRoutes(router *gin.RouterGroup) {
+	jackpots := router.Group("/jackpots")
+	{
+		jackpots.GET("", h.ListJackpots)                     // 奖池列表（必中奖池含当前必中点的承诺哈希）
+		jackpots.GET("/history", h.GetHistory)               // 中奖历史（含公开的必中点和种子）
+		jackpots.GET("/history/:id/verify", h.VerifyHistory) // 校验公开的必中点与派奖前的承诺
+	}
+}
+
+// ListJackpots 获取奖池列表
+// 必中点本身不下发，只公开承诺哈希，派奖后可用中奖历史中的必中点和种子校验
+func (h *JackpotHandler) ListJackpots(c *gin.Context) {
+	gameID, ok := h.gameID(c)
+	if !ok {
+		return
+	}
+
+	jackpots, err := h.repo.GetAllJackpots(gameID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查询失败",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    jackpots,
+		"game_id": gameID,
+	})
+}
+
+// GetHistory 获取中奖历史
+func (h *JackpotHandler) GetHistory(c *gin.Context) {
+	gameID, ok := h.gameID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	history, err := h.repo.GetJackpotHistory(gameID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查询失败",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    history,
+		"game_id": gameID,
+		"limit":   limit,
+	})
+}
+
+// VerifyHistory 校验中奖历史公开的必中点：种子派生出的必中点一致，且与派奖前公布的承诺哈希一致
+func (h *JackpotHandler) VerifyHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的记录ID"})
+		return
+	}
+
+	history, err := h.repo.GetJackpotHistoryByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "记录不存在",
+			"message": err.Error(),
+		})
+		return
+	}
+	if history.HitHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "该记录不是必中奖池派奖"})
+		return
+	}
+
+	// 按派奖时保存的金额范围校验，旧记录没有保存时使用奖池当前配置
+	minAmount, maxAmount := history.MinAmount, history.MaxAmount
+	if maxAmount == 0 {
+		minAmount, maxAmount = history.Jackpot.MinAmount, history.Jackpot.MaxAmount
+	}
+	commitment := &slot.JackpotCommitment{
+		HitPoint: history.HitPoint,
+		Seed:     history.HitSeed,
+		Hash:     history.HitHash,
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"id":          history.ID,
+			"type":        history.Jackpot.Type,
+			"hit_point":   history.HitPoint,
+			"hit_seed":    history.HitSeed,
+			"hit_hash":    history.HitHash,
+			"pool_before": history.PoolBefore,
+			"min_amount":  minAmount,
+			"max_amount":  maxAmount,
+			"verified":    commitment.Verify(history.HitHash, minAmount, maxAmount),
+		},
+	})
+}
+
+// gameID 解析查询的游戏ID（缺省为老虎机游戏）
+func (h *JackpotHandler) gameID(c *gin.Context) (uint, bool) {
+	if value := c.Query("game_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的游戏ID"})
+			return 0, false
+		}
+		return uint(id), true
+	}
+
+	var game models.Game
+	if err := h.db.Where("type = ?", "slot").First(&game).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "游戏不存在",
+			"message": err.Error(),
+		})
+		return 0, false
+	}
+	return game.ID, true
+}
@@ -23,10 +23,10 @@ type ProtobufWebSocketHandler struct {
 }
 
 // NewProtobufWebSocketHandler 创建protobuf WebSocket处理器
-func NewProtobufWebSocketHandler(db *gorm.DB, logger *zap.Logger) *ProtobufWebSocketHandler {
+func NewProtobufWebSocketHandler(db *gorm.DB, logger *zap.Logger, arena *ws.ActivityArena) *ProtobufWebSocketHandler {
 	// 创建handlers
 	slotHandler := ws.NewSlotHandler(db)
-	animalHandler := ws.NewAnimalHandler(db, logger)
+	animalHandler := ws.NewAnimalHandlerWithArena(db, logger, arena)
 	bridgeHandler := ws.NewBridgeHandler(logger, db)
 
 	// 注册游戏处理器到桥接处理器
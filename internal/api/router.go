@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wfunc/slot-game/internal/game"
+	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/hardware"
 	"github.com/wfunc/slot-game/internal/middleware"
 	"github.com/wfunc/slot-game/internal/repository"
@@ -19,10 +21,15 @@ type Router struct {
 	engine            *gin.Engine
 	db                *gorm.DB
 	services          *service.Services
+	gameService       *game.GameService
 	authHandler       *AuthHandler
 	slotHandler       *SlotHandler
 	walletHandler     *WalletHandler
 	serialLogHandler  *SerialLogAPI
+	themeHandler      *ThemeHandler
+	jackpotHandler    *JackpotHandler
+	slotStatsHandler  *SlotStatsHandler
+	animalProfit      *AnimalProfitHandler
 	wsHandler         *WebSocketHandler
 	protobufWsHandler *ProtobufWebSocketHandler
 	binaryWsHandler   *BinaryWebSocketHandler
@@ -32,7 +39,7 @@ type Router struct {
 }
 
 // NewRouter 创建路由器
-func NewRouter(db *gorm.DB, config *service.Config, log *zap.Logger, serialController hardware.HardwareController) *Router {
+func NewRouter(db *gorm.DB, config *service.Config, log *zap.Logger, serialController hardware.HardwareController, themePacks *slot.ThemePackLoader) *Router {
 	// 创建Gin引擎
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
@@ -66,14 +73,26 @@ func NewRouter(db *gorm.DB, config *service.Config, log *zap.Logger, serialContr
 	// 创建处理器
 	authHandler := NewAuthHandler(services.Auth, services.User)
 	wsHandler := NewWebSocketHandler(wsHub, log)
-	protobufWsHandler := NewProtobufWebSocketHandler(db, log)
-	binaryWsHandler := NewBinaryWebSocketHandler(db, log)
+	// 两种WebSocket连接共用同一个动物园活动场
+	activityArena := ws.NewActivityArena(db, nil, log)
+	protobufWsHandler := NewProtobufWebSocketHandler(db, log, activityArena)
+	// 拉霸机WebSocket与REST接口共用机台引擎注册表
+	protobufWsHandler.slotHandler.SetMachineRegistry(gameService.SlotRegistry())
+	// 麻将引擎使用主题包加载器注册的主题
+	if themePacks != nil {
+		protobufWsHandler.slotHandler.SetThemeManager(themePacks.Manager())
+	}
+	binaryWsHandler := NewBinaryWebSocketHandler(db, log, activityArena)
 	slotHandler := NewSlotHandler(gameService, repository.NewWalletRepository(db), wsHandler, log)
 	walletHandler := NewWalletHandler(db, log)
 
 	// 创建串口日志处理器
 	serialLogService := service.NewSerialLogService(db)
 	serialLogHandler := NewSerialLogAPI(serialLogService)
+	themeHandler := NewThemeHandler(themePacks)
+	jackpotHandler := NewJackpotHandler(db)
+	slotStatsHandler := NewSlotStatsHandler(db)
+	animalProfit := NewAnimalProfitHandler(protobufWsHandler.animalHandler, binaryWsHandler.router)
 
 	// 创建中间件
 	authMiddleware := middleware.NewAuthMiddleware(services.Auth)
@@ -82,10 +101,15 @@ func NewRouter(db *gorm.DB, config *service.Config, log *zap.Logger, serialContr
 		engine:            engine,
 		db:                db,
 		services:          services,
+		gameService:       gameService,
 		authHandler:       authHandler,
 		slotHandler:       slotHandler,
 		walletHandler:     walletHandler,
 		serialLogHandler:  serialLogHandler,
+		themeHandler:      themeHandler,
+		jackpotHandler:    jackpotHandler,
+		slotStatsHandler:  slotStatsHandler,
+		animalProfit:      animalProfit,
 		wsHandler:         wsHandler,
 		protobufWsHandler: protobufWsHandler,
 		binaryWsHandler:   binaryWsHandler,
@@ -137,6 +161,9 @@ func (r *Router) setupRoutes() {
 			// users.DELETE("/:id", r.userHandler.DeleteUser)
 		}
 
+		// 主题包路由
+		r.themeHandler.RegisterRoutes(v1)
+
 		// 游戏相关路由（需要认证）
 		games := v1.Group("/games")
 		games.Use(r.authMiddleware.RequireAuth())
@@ -154,7 +181,9 @@ func (r *Router) setupRoutes() {
 			slot.POST("/start", r.slotHandler.Start)               // 开始游戏
 			slot.POST("/spin", r.slotHandler.Spin)                 // 执行转动
 			slot.POST("/batch-spin", r.slotHandler.BatchSpin)      // 批量转动
-			slot.POST("/settle", r.slotHandler.Settle)             // 结算游戏
+			slot.POST("/gamble", r.slotHandler.Gamble)             // 博倍竞猜
+			slot.POST("/buy-feature", r.slotHandler.BuyFeature)    // 购买免费游戏
+			slot.POST("/settle", r.slotHandler.Settle)             // 结算游戏（博倍中为收分）
 			slot.GET("/history", r.slotHandler.GetHistory)         // 游戏历史
 			slot.GET("/session/:id", r.slotHandler.GetSessionInfo) // 会话信息
 			slot.GET("/stats", r.slotHandler.GetUserStats)         // 用户统计
@@ -188,6 +217,15 @@ func (r *Router) setupRoutes() {
 			// 串口日志路由
 			r.serialLogHandler.RegisterRoutes(admin)
 
+			// JP奖池路由（必中点承诺与审计）
+			r.jackpotHandler.RegisterRoutes(admin)
+
+			// 老虎机旋转统计路由（按机台、日期、下注档位，RTP漂移告警）
+			r.slotStatsHandler.RegisterRoutes(admin)
+
+			// 动物房间实时盈亏路由（P值、W值、RTP）
+			r.animalProfit.RegisterRoutes(admin)
+
 			// TODO: 实现其他管理员API
 			// admin.GET("/users", r.adminHandler.GetUsers)
 			// admin.PUT("/users/:id/status", r.adminHandler.UpdateUserStatus)
@@ -249,6 +287,22 @@ func (r *Router) healthCheck(c *gin.Context) {
 	})
 }
 
+// SetDevice 设置拉霸机进入房间和彩金推送中下发的设备ID和机台号
+func (r *Router) SetDevice(devID string, devNo uint32) {
+	r.protobufWsHandler.slotHandler.SetDevice(devID, devNo)
+	r.binaryWsHandler.router.GetSlotHandler().SetDevice(devID, devNo)
+}
+
+// SetAnimalProfit 设置动物房间的盈亏控制配置（配置文件加载）
+func (r *Router) SetAnimalProfit(config AnimalProfitConfig) error {
+	return r.animalProfit.SetConfig(config)
+}
+
+// Start 启动后台任务（游戏会话清理、老虎机配置热加载）
+func (r *Router) Start(ctx context.Context) {
+	r.gameService.Start(ctx)
+}
+
 // Run 运行服务器
 func (r *Router) Run(addr string) error {
 	r.log.Info("Starting API server", zap.String("address", addr))
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/wfunc/slot-game/internal/game"
+	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/middleware"
 	"github.com/wfunc/slot-game/internal/models"
 	"github.com/wfunc/slot-game/internal/repository"
@@ -33,7 +34,10 @@ func NewSlotHandler(gameService *game.GameService, walletRepo repository.WalletR
 
 // StartRequest 开始游戏请求
 type StartRequest struct {
-	BetAmount int64 `json:"bet_amount" binding:"required,min=100"`
+	BetAmount int64  `json:"bet_amount" binding:"required,min=100"`
+	SessionID string `json:"session_id"` // 继续已有会话（如购买免费游戏后的免费旋转），为空时创建新会话
+	MachineID string `json:"machine_id"` // 机台ID，为空时沿用会话的机台（新会话使用默认机台）
+	Ante      bool   `json:"ante"`       // 加注：按机台加注比例扣费，提高免费游戏触发率
 }
 
 // StartResponse 开始游戏响应
@@ -71,6 +75,39 @@ type SettleResponse struct {
 	Message   string `json:"message"`
 }
 
+// GambleRequest 博倍请求
+type GambleRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+	Mode      string `json:"mode" binding:"required,oneof=color high_low"`         // 玩法：color猜红黑，high_low猜大小
+	Guess     string `json:"guess" binding:"required,oneof=red black high low"` // 竞猜选项
+}
+
+// GambleResponse 博倍响应
+type GambleResponse struct {
+	Round     interface{} `json:"round"`
+	Gamble    interface{} `json:"gamble"`
+	CanDouble bool        `json:"can_double"`
+	Balance   int64       `json:"balance"`
+	State     string      `json:"state"`
+	TotalWin  int64       `json:"total_win"`
+}
+
+// BuyFeatureRequest 购买免费游戏请求
+type BuyFeatureRequest struct {
+	BetAmount int64  `json:"bet_amount" binding:"required,min=100"` // 免费旋转按该金额派彩
+	SessionID string `json:"session_id"`                            // 为空时创建新会话
+	MachineID string `json:"machine_id"`                            // 机台ID，为空时沿用会话的机台（新会话使用默认机台）
+}
+
+// BuyFeatureResponse 购买免费游戏响应
+type BuyFeatureResponse struct {
+	SessionID string      `json:"session_id"`
+	Price     int64       `json:"price"`      // 实际扣费
+	FreeSpins int         `json:"free_spins"` // 获得的免费次数
+	FreeGame  interface{} `json:"free_game"`
+	Balance   int64       `json:"balance"`
+}
+
 // HistoryResponse 历史记录响应
 type HistoryResponse struct {
 	Records interface{} `json:"records"`
@@ -93,7 +130,7 @@ type SessionInfoResponse struct {
 
 // Start 开始游戏
 // @Summary 开始游戏
-// @Description 扣除投注并创建会话，返回 session_id 与余额
+// @Description 扣除投注（加注时按加注比例，免费旋转不扣费）并创建或继续会话，返回 session_id 与余额
 // @Tags Slot
 // @Security Bearer
 // @Accept json
@@ -124,10 +161,13 @@ func (h *SlotHandler) Start(c *gin.Context) {
 	}
 
 	// 生成会话ID
-	sessionID := generateSessionID()
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = generateSessionID()
+	}
 
 	// 调用游戏服务开始游戏
-	err := h.gameService.StartGame(c.Request.Context(), userID, sessionID, req.BetAmount)
+	err := h.gameService.StartGameWithAnte(c.Request.Context(), userID, sessionID, req.MachineID, req.BetAmount, req.Ante)
 	if err != nil {
 		h.logger.Error("开始游戏失败",
 			zap.Uint("user_id", userID),
@@ -302,6 +342,119 @@ func (h *SlotHandler) Settle(c *gin.Context) {
 	})
 }
 
+// Gamble 博倍竞猜
+// @Summary 博倍
+// @Description 中奖后用本次赢取猜红黑或猜大小，猜中翻倍、猜错清零；调用结算接口收分后才结算到钱包
+// @Tags Slot
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body GambleRequest true "博倍请求"
+// @Success 200 {object} GambleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/slot/gamble [post]
+func (h *SlotHandler) Gamble(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists || userID == 0 {
+		c.JSON(401, gin.H{"error": "未登录"})
+		return
+	}
+
+	var req GambleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	// 执行竞猜
+	result, err := h.gameService.Gamble(c.Request.Context(), req.SessionID, slot.GambleMode(req.Mode), slot.GambleGuess(req.Guess))
+	if err != nil {
+		h.logger.Error("博倍失败",
+			zap.String("session_id", req.SessionID),
+			zap.Error(err))
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 获取当前余额（收分前不变）
+	wallet, err := h.walletRepo.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("获取钱包失败", zap.Error(err))
+		wallet = &models.Wallet{Balance: 0}
+	}
+
+	c.JSON(200, GambleResponse{
+		Round:     result.Round,
+		Gamble:    result.Gamble,
+		CanDouble: result.CanDouble,
+		Balance:   wallet.Balance,
+		State:     result.State,
+		TotalWin:  result.TotalWin,
+	})
+}
+
+// BuyFeature 购买免费游戏
+// @Summary 购买免费游戏
+// @Description 按机台配置的价格倍数扣费后直接进入免费游戏，之后在同一会话内开始游戏不再扣费直到免费次数用完
+// @Tags Slot
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body BuyFeatureRequest true "购买请求"
+// @Success 200 {object} BuyFeatureResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/slot/buy-feature [post]
+func (h *SlotHandler) BuyFeature(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists || userID == 0 {
+		c.JSON(401, gin.H{"error": "未登录"})
+		return
+	}
+
+	var req BuyFeatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	// 验证投注金额
+	if req.BetAmount < 100 || req.BetAmount > 10000 {
+		c.JSON(400, gin.H{"error": "投注金额必须在100-10000之间"})
+		return
+	}
+
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = generateSessionID()
+	}
+
+	result, err := h.gameService.BuyFeature(c.Request.Context(), userID, sessionID, req.MachineID, req.BetAmount)
+	if err != nil {
+		h.logger.Error("购买免费游戏失败",
+			zap.Uint("user_id", userID),
+			zap.String("session_id", sessionID),
+			zap.Error(err))
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	if h.wsHandler != nil {
+		h.wsHandler.SendBalanceUpdate(userID, result.Balance)
+	}
+
+	c.JSON(200, BuyFeatureResponse{
+		SessionID: sessionID,
+		Price:     result.Purchase.Price,
+		FreeSpins: result.Purchase.FreeGame.FreeSpinsTotal,
+		FreeGame:  result.Purchase.FreeGame,
+		Balance:   result.Balance,
+	})
+}
+
 // GetHistory 获取游戏历史
 // @Summary 用户历史记录
 // @Description 获取当前用户的最近游戏历史
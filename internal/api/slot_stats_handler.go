@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/repository"
+	"gorm.io/gorm"
+)
+
+// defaultSlotStatDays 未指定日期范围时查询最近的天数（含今天）
+const defaultSlotStatDays = 7
+
+// SlotStatsHandler 老虎机旋转统计API
+type SlotStatsHandler struct {
+	repo *repository.SlotStatsRepository
+}
+
+// NewSlotStatsHandler 创建老虎机旋转统计API
+func NewSlotStatsHandler(db *gorm.DB) *SlotStatsHandler {
+	return &SlotStatsHandler{
+		repo: repository.NewSlotStatsRepository(db),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *SlotStatsHandler) RegisterRoutes(router *gin.RouterGroup) {
+	stats := router.Group("/slot-stats")
+	{
+		stats.GET("", h.ListBuckets)       // 按机台、日期、下注档位的统计
+		stats.GET("/summary", h.Summarize) // 日期范围内按机台和下注档位汇总
+		stats.GET("/drift", h.GetDrift)    // 实际RTP与理论RTP的漂移检测和告警
+	}
+}
+
+// ListBuckets 获取按日统计
+func (h *SlotStatsHandler) ListBuckets(c *gin.Context) {
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+
+	buckets, err := h.repo.GetBuckets(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查询失败",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   buckets,
+		"filter": filter,
+	})
+}
+
+// Summarize 获取日期范围内的统计汇总
+func (h *SlotStatsHandler) Summarize(c *gin.Context) {
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+
+	summaries, err := h.repo.Summarize(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查询失败",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   summaries,
+		"filter": filter,
+	})
+}
+
+// GetDrift 获取RTP漂移报告（confidence 为置信水平，缺省0.99）
+func (h *SlotStatsHandler) GetDrift(c *gin.Context) {
+	filter, ok := h.filter(c)
+	if !ok {
+		return
+	}
+	confidence, err := strconv.ParseFloat(c.DefaultQuery("confidence", strconv.FormatFloat(slot.DefaultDriftConfidence, 'f', -1, 64)), 64)
+	if err != nil || confidence <= 0 || confidence >= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的置信水平"})
+		return
+	}
+
+	report, err := h.repo.GetRTPDriftReport(filter, confidence)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "查询失败",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": report,
+	})
+}
+
+// filter 解析查询条件（from/to 为 2006-01-02，缺省为最近7天）
+func (h *SlotStatsHandler) filter(c *gin.Context) (repository.SlotStatFilter, bool) {
+	now := time.Now()
+	filter := repository.SlotStatFilter{
+		MachineID: c.Query("machine_id"),
+		From:      c.DefaultQuery("from", now.AddDate(0, 0, 1-defaultSlotStatDays).Format("2006-01-02")),
+		To:        c.DefaultQuery("to", now.Format("2006-01-02")),
+	}
+	if value := c.Query("bet_level"); value != "" {
+		level, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || level <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的下注档位"})
+			return filter, false
+		}
+		filter.BetLevel = level
+	}
+	for _, day := range []string{filter.From, filter.To} {
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的日期", "message": day})
+			return filter, false
+		}
+	}
+	return filter, true
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wfunc/slot-game/internal/game/slot"
+)
+
+// ThemeHandler 主题包API
+type ThemeHandler struct {
+	loader *slot.ThemePackLoader
+}
+
+// NewThemeHandler 创建主题包API
+func NewThemeHandler(loader *slot.ThemePackLoader) *ThemeHandler {
+	return &ThemeHandler{
+		loader: loader,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *ThemeHandler) RegisterRoutes(router *gin.RouterGroup) {
+	themes := router.Group("/themes")
+	{
+		themes.GET("", h.ListThemes)   // 主题包列表（含加载状态）
+		themes.GET("/:id", h.GetTheme) // 主题详情
+	}
+}
+
+// ListThemes 获取主题包列表
+func (h *ThemeHandler) ListThemes(c *gin.Context) {
+	packs := []slot.ThemePackInfo{}
+	if h.loader != nil {
+		packs = h.loader.Packs()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  packs,
+		"count": len(packs),
+	})
+}
+
+// GetTheme 获取主题详情
+func (h *ThemeHandler) GetTheme(c *gin.Context) {
+	if h.loader == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "主题不存在"})
+		return
+	}
+
+	theme, err := h.loader.Theme(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "主题不存在",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": theme,
+	})
+}
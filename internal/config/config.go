@@ -147,6 +147,7 @@ type GameConfig struct {
 	Slot   SlotConfig   `mapstructure:"slot"`
 	Pusher PusherConfig `mapstructure:"pusher"`
 	Coin   CoinConfig   `mapstructure:"coin"`
+	Animal AnimalConfig `mapstructure:"animal"`
 }
 
 // SlotConfig 老虎机配置
@@ -156,6 +157,26 @@ type SlotConfig struct {
 	SpinDuration time.Duration          `mapstructure:"spin_duration"`
 	WinRates     map[string]float64     `mapstructure:"win_rates"`
 	Payouts      map[string]int         `mapstructure:"payouts"`
+	ThemeDir     string                 `mapstructure:"theme_dir"` // 主题包目录
+	DeviceID     string                 `mapstructure:"device_id"` // 设备ID（进入房间和彩金推送下发）
+	DeviceNo     uint32                 `mapstructure:"device_no"` // 机台号
+}
+
+// AnimalConfig 动物园配置
+type AnimalConfig struct {
+	Profit AnimalProfitConfig `mapstructure:"profit"`
+}
+
+// AnimalProfitConfig 动物房间盈亏控制配置（击杀概率模型和RTP目标）
+type AnimalProfitConfig struct {
+	TargetRTP       float64            `mapstructure:"target_rtp"`        // 房间目标RTP
+	GlobalTargetRTP float64            `mapstructure:"global_target_rtp"` // 全局目标RTP
+	HouseEdgeBand   float64            `mapstructure:"house_edge_band"`   // 目标RTP上下的容忍带宽
+	MaxAdjust       float64            `mapstructure:"max_adjust"`        // 单次修正击杀概率的最大比例
+	PumpRTP         float64            `mapstructure:"pump_rtp"`          // 房间RTP超过该值时抽水
+	PumpFactor      float64            `mapstructure:"pump_factor"`       // 抽水期间击杀概率的系数
+	WarmupBet       uint64             `mapstructure:"warmup_bet"`        // 累计下注低于该值时不修正
+	ExpectedShots   map[string]float64 `mapstructure:"expected_shots"`    // 按动物名配置的期望打击次数
 }
 
 // PusherConfig 推币机配置
@@ -358,6 +379,18 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("websocket.write_timeout", "10s")
 	v.SetDefault("websocket.enable_compression", true)
 	
+	// 游戏默认配置
+	v.SetDefault("game.slot.theme_dir", "./config/themes")
+	v.SetDefault("game.slot.device_id", "SLOT001")
+	v.SetDefault("game.slot.device_no", 1)
+	v.SetDefault("game.animal.profit.target_rtp", 0.95)
+	v.SetDefault("game.animal.profit.global_target_rtp", 0.95)
+	v.SetDefault("game.animal.profit.house_edge_band", 0.02)
+	v.SetDefault("game.animal.profit.max_adjust", 0.5)
+	v.SetDefault("game.animal.profit.pump_rtp", 1.10)
+	v.SetDefault("game.animal.profit.pump_factor", 0.3)
+	v.SetDefault("game.animal.profit.warmup_bet", 100000)
+	
 	// 日志默认配置
 	v.SetDefault("log.level", "info")
 	v.SetDefault("log.format", "json")
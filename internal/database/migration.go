@@ -51,12 +51,18 @@ func AutoMigrate() error {
 		// 老虎机相关
 		&models.SlotMachine{},
 		&models.SlotSpin{},
+		&models.SlotConfigVersion{},
 		&models.SlotWinLine{},
+		&models.SlotStatBucket{},
 
 		// JP奖池相关 - 已移除，单独处理
 		// &models.Jackpot{},
 		// &models.JackpotHistory{},
 
+		// 动物活动场
+		&models.ActivityRecord{},
+		&models.ActivityRanking{},
+
 		// 推币机相关
 		&models.PusherMachine{},
 		&models.PusherSession{},
@@ -418,6 +424,9 @@ func createJackpotTables() error {
 		total_in INTEGER DEFAULT 0,
 		total_out INTEGER DEFAULT 0,
 		status TEXT DEFAULT 'active',
+		must_hit_by NUMERIC DEFAULT false,
+		hit_hash TEXT,
+		hit_seed TEXT,
 		UNIQUE(game_id, type)
 	);
 	`
@@ -426,6 +435,13 @@ func createJackpotTables() error {
 			return err
 		}
 	}
+	if err := addMissingColumns("jackpots", []sqliteColumn{
+		{"must_hit_by", "NUMERIC DEFAULT false"},
+		{"hit_hash", "TEXT"},
+		{"hit_seed", "TEXT"},
+	}); err != nil {
+		return err
+	}
 
 	createJackpotHistoriesSQL := `
 	CREATE TABLE IF NOT EXISTS jackpot_histories (
@@ -435,7 +451,16 @@ func createJackpotTables() error {
 		deleted_at DATETIME,
 		jackpot_id INTEGER NOT NULL,
 		user_id INTEGER NOT NULL,
+		session_id INTEGER NOT NULL DEFAULT 0,
+		result_id INTEGER NOT NULL DEFAULT 0,
 		amount INTEGER NOT NULL,
+		pool_before INTEGER DEFAULT 0,
+		pool_after INTEGER DEFAULT 0,
+		min_amount INTEGER DEFAULT 0,
+		max_amount INTEGER DEFAULT 0,
+		hit_point INTEGER DEFAULT 0,
+		hit_seed TEXT,
+		hit_hash TEXT,
 		won_at DATETIME NOT NULL,
 		machine_id TEXT,
 		bet_amount INTEGER
@@ -446,6 +471,21 @@ func createJackpotTables() error {
 			return err
 		}
 	}
+	
+	// 旧版本创建的表缺少派奖记录需要的列，逐列补齐
+	if err := addMissingColumns("jackpot_histories", []sqliteColumn{
+		{"session_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"result_id", "INTEGER NOT NULL DEFAULT 0"},
+		{"pool_before", "INTEGER DEFAULT 0"},
+		{"pool_after", "INTEGER DEFAULT 0"},
+		{"min_amount", "INTEGER DEFAULT 0"},
+		{"max_amount", "INTEGER DEFAULT 0"},
+		{"hit_point", "INTEGER DEFAULT 0"},
+		{"hit_seed", "TEXT"},
+		{"hit_hash", "TEXT"},
+	}); err != nil {
+		return err
+	}
 
 	// 创建索引
 	ensureIndexesForTable("jackpots")
@@ -455,6 +495,26 @@ func createJackpotTables() error {
 	return nil
 }
 
+// sqliteColumn 手动建表的列定义
+type sqliteColumn struct {
+	Name       string
+	Definition string
+}
+
+// addMissingColumns 为旧版本创建的表补齐缺少的列
+func addMissingColumns(tableName string, columns []sqliteColumn) error {
+	for _, column := range columns {
+		if DB.Migrator().HasColumn(tableName, column.Name) {
+			continue
+		}
+		addColumnSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", tableName, column.Name, column.Definition)
+		if err := DB.Exec(addColumnSQL).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ensureIndexesForTable 为表确保索引存在（通用版本）
 func ensureIndexesForTable(tableName string) {
 	if tableName == "jackpots" {
@@ -29,12 +29,31 @@ type ActivityConfig struct {
 	Percent  uint32   // 打动物的概率 1000分比
 	Rewards  []RewardConfig // 奖励配置
 	AgentID  uint32   // 渠道ID
+	IdleTime uint32   // 结算后到下一场活动的空闲时间（秒）
 }
 
-// RewardConfig 奖励配置
+// RewardConfig 奖励配置（Rank 到 MaxRank 名次各奖励 Gold 金豆）
 type RewardConfig struct {
-	Rank uint32
-	Gold uint32
+	Rank    uint32
+	MaxRank uint32 // 为0时只奖励 Rank 名次
+	Gold    uint32
+}
+
+// RewardForRank 名次对应的奖励金豆（没有奖励返回0）
+func (a *Activity) RewardForRank(rank uint32) uint32 {
+	if rank == 0 {
+		return 0
+	}
+	for _, reward := range a.Rewards {
+		maxRank := reward.MaxRank
+		if maxRank < reward.Rank {
+			maxRank = reward.Rank
+		}
+		if rank >= reward.Rank && rank <= maxRank {
+			return reward.Gold
+		}
+	}
+	return 0
 }
 
 // NewActivityManager 创建活动管理器
@@ -45,13 +64,25 @@ func NewActivityManager(logger *zap.Logger) *ActivityManager {
 	}
 }
 
+// SetStateChangeHandler 设置活动状态变化回调（准备、开始、结算、取消）
+// 回调按状态顺序串行执行，参数为活动副本；回调中不能再调用 ScheduleActivity 或 CancelActivity
+func (am *ActivityManager) SetStateChangeHandler(handler func(activity *Activity)) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.onStateChange = handler
+}
+
 // ScheduleActivity 安排新活动
 func (am *ActivityManager) ScheduleActivity(config *ActivityConfig) error {
+	am.transitionMu.Lock()
+	defer am.transitionMu.Unlock()
+
 	am.mu.Lock()
-	defer am.mu.Unlock()
 
 	// 如果有正在进行的活动，返回错误
 	if am.currentActivity != nil && am.currentActivity.Status != string(ActivityStateIdle) {
+		am.mu.Unlock()
 		return fmt.Errorf("活动正在进行中")
 	}
 
@@ -66,15 +97,10 @@ func (am *ActivityManager) ScheduleActivity(config *ActivityConfig) error {
 		Percent:    config.Percent,
 		Status:     string(ActivityStateIdle),
 		Rankings:   make([]*PlayerRank, 0),
-		Rewards:    make([]string, 0),
+		Rewards:    append([]RewardConfig{}, config.Rewards...),
 		AgentID:    config.AgentID,
 	}
 
-	// 将奖励配置转换为字符串（或根据实际需要处理）
-	for _, reward := range config.Rewards {
-		activity.Rewards = append(activity.Rewards, fmt.Sprintf("rank_%d:%d", reward.Rank, reward.Gold))
-	}
-
 	am.currentActivity = activity
 
 	// 安排活动开始
@@ -85,6 +111,7 @@ func (am *ActivityManager) ScheduleActivity(config *ActivityConfig) error {
 		zap.Uint32("pre_time", activity.PreTime),
 		zap.Uint32("work_time", activity.WorkTime))
 
+	am.notifyStateChange(am.copyActivity(activity))
 	return nil
 }
 
@@ -95,42 +122,77 @@ func (am *ActivityManager) scheduleActivityStart() {
 	}
 
 	// 立即进入准备阶段
-	am.currentActivity.Status = string(ActivityStatePrepare)
-	am.currentActivity.StartTime = time.Now()
+	activity := am.currentActivity
+	activity.Status = string(ActivityStatePrepare)
+	activity.StartTime = time.Now()
 
 	// 准备阶段定时器
-	am.scheduler = time.AfterFunc(time.Duration(am.currentActivity.PreTime)*time.Second, func() {
-		am.mu.Lock()
-		defer am.mu.Unlock()
-
-		// 进入活动阶段
-		am.currentActivity.Status = string(ActivityStateActive)
-		am.logger.Info("活动开始", zap.Uint32("activity_id", am.currentActivity.ID))
-
-		// 活动结束定时器
-		am.scheduler = time.AfterFunc(time.Duration(am.currentActivity.WorkTime)*time.Second, func() {
-			am.mu.Lock()
-			defer am.mu.Unlock()
-
-			// 进入结算阶段
-			am.currentActivity.Status = string(ActivityStateSettlement)
-			am.currentActivity.EndTime = time.Now()
-			am.logger.Info("活动结束，开始结算", zap.Uint32("activity_id", am.currentActivity.ID))
-
-			// 结算奖励
-			am.settleActivity()
-		})
+	am.scheduler = time.AfterFunc(time.Duration(activity.PreTime)*time.Second, func() {
+		am.startActivity(activity)
 	})
 }
 
-// settleActivity 结算活动
-func (am *ActivityManager) settleActivity() {
-	if am.currentActivity == nil {
+// startActivity 准备结束，进入活动阶段
+func (am *ActivityManager) startActivity(activity *Activity) {
+	am.transitionMu.Lock()
+	defer am.transitionMu.Unlock()
+
+	am.mu.Lock()
+	if am.currentActivity != activity || activity.Status != string(ActivityStatePrepare) {
+		am.mu.Unlock()
 		return
 	}
 
+	// 进入活动阶段
+	activity.Status = string(ActivityStateActive)
+	am.logger.Info("活动开始", zap.Uint32("activity_id", activity.ID))
+
+	// 活动结束定时器
+	am.scheduler = time.AfterFunc(time.Duration(activity.WorkTime)*time.Second, func() {
+		am.finishActivity(activity)
+	})
+
+	am.notifyStateChange(am.copyActivity(activity))
+}
+
+// finishActivity 活动时间结束，进入结算阶段
+func (am *ActivityManager) finishActivity(activity *Activity) {
+	am.transitionMu.Lock()
+	defer am.transitionMu.Unlock()
+
+	am.mu.Lock()
+	if am.currentActivity != activity || activity.Status != string(ActivityStateActive) {
+		am.mu.Unlock()
+		return
+	}
+
+	// 进入结算阶段
+	activity.Status = string(ActivityStateSettlement)
+	activity.EndTime = time.Now()
+	am.logger.Info("活动结束，开始结算", zap.Uint32("activity_id", activity.ID))
+
+	// 结算奖励
+	am.notifyStateChange(am.settleActivity())
+}
+
+// notifyStateChange 释放 am.mu 后执行状态变化回调（调用方持有 am.mu 和 transitionMu）
+func (am *ActivityManager) notifyStateChange(activity *Activity) {
+	handler := am.onStateChange
+	am.mu.Unlock()
+
+	if handler != nil && activity != nil {
+		handler(activity)
+	}
+}
+
+// settleActivity 结算活动，返回结算时的活动副本（排行榜已排序）
+func (am *ActivityManager) settleActivity() *Activity {
+	if am.currentActivity == nil {
+		return nil
+	}
+
 	// 排序玩家排行榜
-	sort.Slice(am.currentActivity.Rankings, func(i, j int) bool {
+	sort.SliceStable(am.currentActivity.Rankings, func(i, j int) bool {
 		return am.currentActivity.Rankings[i].Value > am.currentActivity.Rankings[j].Value
 	})
 
@@ -143,6 +205,8 @@ func (am *ActivityManager) settleActivity() {
 		zap.Uint32("activity_id", am.currentActivity.ID),
 		zap.Int("player_count", len(am.currentActivity.Rankings)))
 
+	settled := am.copyActivity(am.currentActivity)
+
 	// 活动加入历史
 	am.history = append(am.history, am.currentActivity)
 
@@ -160,6 +224,8 @@ func (am *ActivityManager) settleActivity() {
 		am.scheduler.Stop()
 		am.scheduler = nil
 	}
+
+	return settled
 }
 
 // GetCurrentActivity 获取当前活动
@@ -172,20 +238,25 @@ func (am *ActivityManager) GetCurrentActivity() *Activity {
 	}
 
 	// 返回副本，避免被修改
+	return am.copyActivity(am.currentActivity)
+}
+
+// copyActivity 复制活动
+func (am *ActivityManager) copyActivity(activity *Activity) *Activity {
 	return &Activity{
-		ID:         am.currentActivity.ID,
-		PreTime:    am.currentActivity.PreTime,
-		WorkTime:   am.currentActivity.WorkTime,
-		SendGold:   am.currentActivity.SendGold,
-		MaxPlayers: am.currentActivity.MaxPlayers,
-		BetValues:  append([]uint32{}, am.currentActivity.BetValues...),
-		Percent:    am.currentActivity.Percent,
-		Status:     am.currentActivity.Status,
-		Rankings:   am.copyRankings(am.currentActivity.Rankings),
-		Rewards:    append([]string{}, am.currentActivity.Rewards...),
-		StartTime:  am.currentActivity.StartTime,
-		EndTime:    am.currentActivity.EndTime,
-		AgentID:    am.currentActivity.AgentID,
+		ID:         activity.ID,
+		PreTime:    activity.PreTime,
+		WorkTime:   activity.WorkTime,
+		SendGold:   activity.SendGold,
+		MaxPlayers: activity.MaxPlayers,
+		BetValues:  append([]uint32{}, activity.BetValues...),
+		Percent:    activity.Percent,
+		Status:     activity.Status,
+		Rankings:   am.copyRankings(activity.Rankings),
+		Rewards:    append([]RewardConfig{}, activity.Rewards...),
+		StartTime:  activity.StartTime,
+		EndTime:    activity.EndTime,
+		AgentID:    activity.AgentID,
 	}
 }
 
@@ -205,8 +276,8 @@ func (am *ActivityManager) copyRankings(rankings []*PlayerRank) []*PlayerRank {
 	return result
 }
 
-// UpdatePlayerActivity 更新玩家活动数据
-func (am *ActivityManager) UpdatePlayerActivity(playerID uint32, name string, icon string, vip uint32, betAmount uint64) {
+// UpdatePlayerActivity 更新玩家活动数据（value 累加到排行值）
+func (am *ActivityManager) UpdatePlayerActivity(playerID uint32, name string, icon string, vip uint32, value uint64) {
 	am.mu.Lock()
 	defer am.mu.Unlock()
 
@@ -239,8 +310,8 @@ func (am *ActivityManager) UpdatePlayerActivity(playerID uint32, name string, ic
 		am.currentActivity.Rankings = append(am.currentActivity.Rankings, playerRank)
 	}
 
-	// 更新下注总额
-	playerRank.Value += betAmount
+	// 更新排行值
+	playerRank.Value += value
 }
 
 // GetActivityStatus 获取活动状态
@@ -312,11 +383,15 @@ func (am *ActivityManager) GetActivityHistory(limit int) []*Activity {
 }
 
 // CancelActivity 取消当前活动
+// 进行中的活动立即结算，准备中的活动直接取消，两种情况都会触发状态变化回调
 func (am *ActivityManager) CancelActivity() error {
+	am.transitionMu.Lock()
+	defer am.transitionMu.Unlock()
+
 	am.mu.Lock()
-	defer am.mu.Unlock()
 
 	if am.currentActivity == nil {
+		am.mu.Unlock()
 		return fmt.Errorf("没有正在进行的活动")
 	}
 
@@ -326,18 +401,21 @@ func (am *ActivityManager) CancelActivity() error {
 		am.scheduler = nil
 	}
 
+	var activity *Activity
 	// 结算活动
 	if am.currentActivity.Status == string(ActivityStateActive) {
 		am.currentActivity.Status = string(ActivityStateSettlement)
 		am.currentActivity.EndTime = time.Now()
-		am.settleActivity()
+		activity = am.settleActivity()
 	} else {
 		// 直接取消
 		am.currentActivity.Status = string(ActivityStateIdle)
+		activity = am.copyActivity(am.currentActivity)
 		am.currentActivity = nil
 	}
 
 	am.logger.Info("活动已取消")
+	am.notifyStateChange(activity)
 	return nil
 }
 
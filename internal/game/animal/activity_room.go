@@ -0,0 +1,727 @@
+package animal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrActivityNotActive      = errors.New("animal: activity not active")
+	ErrActivityFull           = errors.New("animal: activity is full")
+	ErrActivityAgentMismatch  = errors.New("animal: activity agent mismatch")
+	ErrActivityAnimalNotFound = errors.New("animal: activity animal not found")
+	ErrInvalidBetValue        = errors.New("animal: invalid bet value")
+)
+
+const (
+	activityRankSize      = 10  // 排行推送和结算推送的名次数
+	activityBroadcastOdds = 100 // 单次击杀积分达到下注的该倍数时全场广播
+	activityMinAnimals    = 18  // 场上最少动物数
+	activityTargetAnimals = 20  // 补充动物的目标数
+)
+
+// activityStateCodes 活动状态对应的协议状态码（1准备，2活动期间，3开奖，4空闲）
+var activityStateCodes = map[ActivityState]uint32{
+	ActivityStatePrepare:    1,
+	ActivityStateActive:     2,
+	ActivityStateSettlement: 3,
+	ActivityStateIdle:       4,
+}
+
+// DefaultActivityConfig 默认活动场配置：准备30秒，活动5分钟，结算后空闲1分钟
+func DefaultActivityConfig() *ActivityConfig {
+	return &ActivityConfig{
+		ID:       1,
+		PreTime:  30,
+		WorkTime: 300,
+		IdleTime: 60,
+		SendGold: 100000,
+		MaxNum:   100,
+		BetVals:  []uint32{100, 200, 500, 1000},
+		Percent:  900,
+		Rewards: []RewardConfig{
+			{Rank: 1, Gold: 50000},
+			{Rank: 2, Gold: 30000},
+			{Rank: 3, Gold: 20000},
+			{Rank: 4, MaxRank: 10, Gold: 5000},
+		},
+	}
+}
+
+// ActivityPlayer 活动场玩家，每场活动第一次进入时发放活动金豆
+type ActivityPlayer struct {
+	PlayerID uint32
+	UserID   uint
+	Name     string
+	Icon     string
+	VIP      uint32
+	Gold     uint64 // 剩余活动金豆
+	Score    uint64 // 积分
+}
+
+// ActivityPayout 玩家在一场活动中的名次和排名奖励
+type ActivityPayout struct {
+	Rank     uint32 // 名次（没有积分为0）
+	PlayerID uint32
+	UserID   uint
+	Name     string
+	Score    uint64
+	Reward   uint64 // 排名奖励金豆
+}
+
+// ActivitySettlement 活动结算结果
+type ActivitySettlement struct {
+	Activity *Activity         // 结算时的活动（排行榜已排序）
+	Payouts  []*ActivityPayout // 所有参与玩家，有名次的在前
+}
+
+// ActivityRoom 活动场房间
+// 由 ActivityManager 驱动准备、活动、结算、空闲循环：准备阶段刷新动物，活动期间打动物累计积分，
+// 结算时按名次发放奖励并把玩家推出活动，空闲时间结束后开始下一场
+type ActivityRoom struct {
+	mu     sync.RWMutex
+	logger *zap.Logger
+
+	id        uint32
+	config    ActivityConfig
+	manager   *ActivityManager
+	generator *AnimalGenerator
+	rand      *rand.Rand
+
+	// 当前活动
+	state     ActivityState
+	stateEnds time.Time // 当前状态结束时间
+	activity  *Activity
+	nextID    uint32
+
+	animals    map[uint32]*AnimalRoute
+	players    map[uint32]*ActivityPlayer
+	spectators spectatorSet // 观战的观众，不参与活动
+
+	ticker    *time.Ticker
+	nextTimer *time.Timer // 空闲结束后开始下一场
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// 消息推送回调
+	pushCallback func(*PushMessage)
+	// 结算回调：发放排名奖励并保存记录，返回玩家结算后的金豆（m_1875）
+	settleCallback func(*ActivitySettlement) map[uint32]uint64
+}
+
+// NewActivityRoom 创建活动场房间（config 为空时使用默认配置）
+func NewActivityRoom(id uint32, config *ActivityConfig, logger *zap.Logger, pushCallback func(*PushMessage), settleCallback func(*ActivitySettlement) map[uint32]uint64) *ActivityRoom {
+	if config == nil {
+		config = DefaultActivityConfig()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	room := &ActivityRoom{
+		logger:         logger,
+		id:             id,
+		config:         *config,
+		manager:        NewActivityManager(logger),
+		generator:      NewAnimalGenerator(id, logger),
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		state:          ActivityStateIdle,
+		nextID:         config.ID,
+		animals:        make(map[uint32]*AnimalRoute),
+		players:        make(map[uint32]*ActivityPlayer),
+		spectators:     newSpectatorSet(DefaultActivityMaxSpectators),
+		ctx:            ctx,
+		cancel:         cancel,
+		pushCallback:   pushCallback,
+		settleCallback: settleCallback,
+	}
+	if room.nextID == 0 {
+		room.nextID = 1
+	}
+	room.manager.SetStateChangeHandler(room.onStateChange)
+
+	return room
+}
+
+// Start 启动房间并立即开始第一场活动
+func (r *ActivityRoom) Start() {
+	r.mu.Lock()
+	r.ticker = time.NewTicker(1 * time.Second)
+	r.mu.Unlock()
+
+	go r.run()
+	r.startNextActivity()
+
+	r.logger.Info("[ActivityRoom] 活动场启动完成", zap.Uint32("room_id", r.id))
+}
+
+// Stop 停止房间，进行中的活动立即结算
+func (r *ActivityRoom) Stop() {
+	r.mu.Lock()
+	r.cancel()
+	if r.ticker != nil {
+		r.ticker.Stop()
+	}
+	if r.nextTimer != nil {
+		r.nextTimer.Stop()
+	}
+	r.mu.Unlock()
+
+	// 没有进行中的活动时返回错误，忽略即可
+	_ = r.manager.CancelActivity()
+
+	r.logger.Info("[ActivityRoom] 活动场已停止", zap.Uint32("room_id", r.id))
+}
+
+// EndActivity 提前结束当前活动：进行中的活动立即结算，准备中的活动直接取消
+func (r *ActivityRoom) EndActivity() error {
+	return r.manager.CancelActivity()
+}
+
+// GetRoomID 获取房间ID
+func (r *ActivityRoom) GetRoomID() uint32 {
+	return r.id
+}
+
+// GetState 获取活动状态和当前状态剩余秒数
+func (r *ActivityRoom) GetState() (ActivityState, uint32) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state, r.remainingUnlocked()
+}
+
+// run 房间主循环
+func (r *ActivityRoom) run() {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			r.logger.Error("[ActivityRoom] 房间运行异常", zap.Any("error", panicErr))
+		}
+	}()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.ticker.C:
+			r.update()
+		}
+	}
+}
+
+// update 移除走完路线的动物并补充动物
+func (r *ActivityRoom) update() {
+	r.mu.Lock()
+	if r.state != ActivityStatePrepare && r.state != ActivityStateActive {
+		r.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	var expired []uint32
+	for id, animal := range r.animals {
+		line := r.generator.GetLineByID(animal.LineID)
+		if line == nil || now.Sub(animal.SpawnAt) >= time.Duration(line.Point)*time.Second {
+			expired = append(expired, id)
+		}
+	}
+	for _, id := range expired {
+		delete(r.animals, id)
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i] < expired[j] })
+
+	var entered []*pb.PRoute
+	if len(r.animals) < activityMinAnimals {
+		entered = r.spawnAnimalsUnlocked(activityTargetAnimals - len(r.animals))
+	}
+	r.mu.Unlock()
+
+	if len(expired) > 0 {
+		r.push(1874, &pb.M_1874Toc{Id: expired})
+	}
+	if len(entered) > 0 {
+		r.push(1876, &pb.M_1876Toc{Animal: entered})
+	}
+}
+
+// spawnAnimalsUnlocked 生成动物（调用方持有锁）
+func (r *ActivityRoom) spawnAnimalsUnlocked(count int) []*pb.PRoute {
+	routes := make([]*pb.PRoute, 0, count)
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		var excludeTypes []pb.EAnimal
+		for _, animal := range r.animals {
+			excludeTypes = append(excludeTypes, animal.Animal)
+		}
+
+		animal := r.generator.GenerateAnimal(excludeTypes)
+		if animal == nil {
+			continue
+		}
+		r.animals[animal.ID] = animal
+		routes = append(routes, r.routeProto(animal, now))
+	}
+	return routes
+}
+
+// routeProto 动物当前路线（路线点按出场后经过的秒数推进）
+func (r *ActivityRoom) routeProto(animal *AnimalRoute, now time.Time) *pb.PRoute {
+	point := animal.Point + uint32(now.Sub(animal.SpawnAt).Seconds())
+	if line := r.generator.GetLineByID(animal.LineID); line != nil && point > uint32(line.Point) {
+		point = uint32(line.Point)
+	}
+	return &pb.PRoute{
+		Id:       proto.Uint32(animal.ID),
+		Bet:      animal.Animal.Enum(),
+		LineId:   proto.Uint32(animal.LineID),
+		Point:    proto.Uint32(point),
+		RedState: proto.Bool(animal.Red),
+		Status:   animal.State.Enum(),
+	}
+}
+
+// getAnimalsUnlocked 场上所有动物（调用方持有锁）
+func (r *ActivityRoom) getAnimalsUnlocked() []*pb.PRoute {
+	ids := r.sortedAnimalIDsUnlocked()
+	now := time.Now()
+	routes := make([]*pb.PRoute, 0, len(ids))
+	for _, id := range ids {
+		routes = append(routes, r.routeProto(r.animals[id], now))
+	}
+	return routes
+}
+
+// clearAnimalsUnlocked 清空场上动物，返回被清除的动物ID（调用方持有锁）
+func (r *ActivityRoom) clearAnimalsUnlocked() []uint32 {
+	ids := r.sortedAnimalIDsUnlocked()
+	r.animals = make(map[uint32]*AnimalRoute)
+	return ids
+}
+
+// remainingUnlocked 当前状态剩余秒数（调用方持有锁）
+func (r *ActivityRoom) remainingUnlocked() uint32 {
+	remaining := time.Until(r.stateEnds)
+	if remaining <= 0 {
+		return 0
+	}
+	return uint32((remaining + time.Second - 1) / time.Second)
+}
+
+// startNextActivity 按房间配置开始下一场活动
+func (r *ActivityRoom) startNextActivity() {
+	r.mu.Lock()
+	if r.ctx.Err() != nil {
+		r.mu.Unlock()
+		return
+	}
+	config := r.config
+	config.ID = r.nextID
+	r.nextID++
+	r.mu.Unlock()
+
+	if err := r.manager.ScheduleActivity(&config); err != nil {
+		r.logger.Warn("[ActivityRoom] 安排活动失败",
+			zap.Uint32("room_id", r.id),
+			zap.Uint32("activity_id", config.ID),
+			zap.Error(err))
+	}
+}
+
+// onStateChange ActivityManager 状态变化回调
+func (r *ActivityRoom) onStateChange(activity *Activity) {
+	switch ActivityState(activity.Status) {
+	case ActivityStatePrepare:
+		r.onPrepare(activity)
+	case ActivityStateActive:
+		r.onActive(activity)
+	case ActivityStateSettlement:
+		r.onSettlement(activity)
+	case ActivityStateIdle:
+		// 准备阶段被取消
+		r.mu.Lock()
+		cleared := r.clearAnimalsUnlocked()
+		r.mu.Unlock()
+		if len(cleared) > 0 {
+			r.push(1874, &pb.M_1874Toc{Id: cleared})
+		}
+		r.enterIdle()
+	}
+}
+
+// onPrepare 准备阶段：重置玩家并刷新动物
+func (r *ActivityRoom) onPrepare(activity *Activity) {
+	r.mu.Lock()
+	r.activity = activity
+	r.state = ActivityStatePrepare
+	r.stateEnds = time.Now().Add(time.Duration(activity.PreTime) * time.Second)
+	r.players = make(map[uint32]*ActivityPlayer)
+	r.animals = make(map[uint32]*AnimalRoute)
+	entered := r.spawnAnimalsUnlocked(activityTargetAnimals)
+	r.mu.Unlock()
+
+	r.logger.Info("[ActivityRoom] 活动准备",
+		zap.Uint32("room_id", r.id),
+		zap.Uint32("activity_id", activity.ID))
+
+	r.pushState(ActivityStatePrepare, activity.PreTime)
+	if len(entered) > 0 {
+		r.push(1876, &pb.M_1876Toc{Animal: entered})
+	}
+}
+
+// onActive 活动开始
+func (r *ActivityRoom) onActive(activity *Activity) {
+	r.mu.Lock()
+	if r.activity != nil {
+		r.activity.Status = activity.Status
+	}
+	r.state = ActivityStateActive
+	r.stateEnds = time.Now().Add(time.Duration(activity.WorkTime) * time.Second)
+	r.mu.Unlock()
+
+	r.pushState(ActivityStateActive, activity.WorkTime)
+}
+
+// onSettlement 活动结算：发放排名奖励，推送结算结果并把玩家推出活动
+func (r *ActivityRoom) onSettlement(activity *Activity) {
+	r.mu.Lock()
+	r.state = ActivityStateSettlement
+	r.stateEnds = time.Now()
+	payouts := r.buildPayoutsUnlocked(activity)
+	cleared := r.clearAnimalsUnlocked()
+	r.mu.Unlock()
+
+	r.pushState(ActivityStateSettlement, 0)
+	if len(cleared) > 0 {
+		r.push(1874, &pb.M_1874Toc{Id: cleared})
+	}
+
+	var gold map[uint32]uint64
+	if r.settleCallback != nil {
+		gold = r.settleCallback(&ActivitySettlement{Activity: activity, Payouts: payouts})
+	}
+
+	rank := activityRankProto(activity.Rankings, activityRankSize)
+	for _, payout := range payouts {
+		r.pushTo(payout.PlayerID, 1875, &pb.M_1875Toc{
+			Gold: proto.Uint64(gold[payout.PlayerID]),
+			Rank: rank,
+		})
+	}
+
+	r.logger.Info("[ActivityRoom] 活动结算",
+		zap.Uint32("room_id", r.id),
+		zap.Uint32("activity_id", activity.ID),
+		zap.Int("player_count", len(payouts)))
+
+	r.enterIdle()
+}
+
+// buildPayoutsUnlocked 按结算排行榜计算每位参与玩家的奖励（调用方持有锁）
+func (r *ActivityRoom) buildPayoutsUnlocked(activity *Activity) []*ActivityPayout {
+	payouts := make([]*ActivityPayout, 0, len(r.players))
+	ranked := make(map[uint32]bool, len(activity.Rankings))
+	for _, rank := range activity.Rankings {
+		ranked[rank.PlayerID] = true
+		payout := &ActivityPayout{
+			Rank:     rank.Rank,
+			PlayerID: rank.PlayerID,
+			Name:     rank.Name,
+			Score:    rank.Value,
+			Reward:   uint64(activity.RewardForRank(rank.Rank)),
+		}
+		if player, ok := r.players[rank.PlayerID]; ok {
+			payout.UserID = player.UserID
+		}
+		payouts = append(payouts, payout)
+	}
+
+	// 没有积分的玩家也推出活动
+	var unranked []*ActivityPayout
+	for _, player := range r.players {
+		if ranked[player.PlayerID] {
+			continue
+		}
+		unranked = append(unranked, &ActivityPayout{
+			PlayerID: player.PlayerID,
+			UserID:   player.UserID,
+			Name:     player.Name,
+		})
+	}
+	sort.Slice(unranked, func(i, j int) bool { return unranked[i].PlayerID < unranked[j].PlayerID })
+	return append(payouts, unranked...)
+}
+
+// enterIdle 进入空闲状态，空闲时间结束后开始下一场
+func (r *ActivityRoom) enterIdle() {
+	r.mu.Lock()
+	idle := r.config.IdleTime
+	r.state = ActivityStateIdle
+	r.stateEnds = time.Now().Add(time.Duration(idle) * time.Second)
+	r.activity = nil
+	r.players = make(map[uint32]*ActivityPlayer)
+	if r.ctx.Err() == nil {
+		r.nextTimer = time.AfterFunc(time.Duration(idle)*time.Second, r.startNextActivity)
+	}
+	r.mu.Unlock()
+
+	r.pushState(ActivityStateIdle, idle)
+}
+
+// Enter 玩家进入活动场（m_1871）
+// 准备和活动期间第一次进入的玩家获得本场活动金豆，空闲时只返回倒计时和场景
+func (r *ActivityRoom) Enter(agentID uint32, player *ActivityPlayer) (*pb.M_1871Toc, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.config.AgentID != 0 && agentID != r.config.AgentID {
+		return nil, ErrActivityAgentMismatch
+	}
+
+	resp := &pb.M_1871Toc{
+		BetVal:  append([]uint32{}, r.config.BetVals...),
+		Odds:    (&OddsSystem{}).GetAnimalOddsRange(pb.EZooType_civilian),
+		Animals: r.getAnimalsUnlocked(),
+		Time:    proto.Uint32(r.remainingUnlocked()),
+		State:   proto.Uint32(activityStateCodes[r.state]),
+		Gold:    proto.Uint64(0),
+		Score:   proto.Uint64(0),
+		Reward:  activityRewardProto(r.config.Rewards),
+	}
+
+	if r.activity != nil && (r.state == ActivityStatePrepare || r.state == ActivityStateActive) {
+		current, ok := r.players[player.PlayerID]
+		if !ok {
+			if r.activity.MaxPlayers > 0 && uint32(len(r.players)) >= r.activity.MaxPlayers {
+				return nil, ErrActivityFull
+			}
+			current = &ActivityPlayer{
+				PlayerID: player.PlayerID,
+				Gold:     uint64(r.activity.SendGold),
+			}
+			r.players[player.PlayerID] = current
+		}
+		current.UserID = player.UserID
+		current.Name = player.Name
+		current.Icon = player.Icon
+		current.VIP = player.VIP
+
+		resp.Gold = proto.Uint64(current.Gold)
+		resp.Score = proto.Uint64(current.Score)
+		resp.Rank = activityRankProto(r.manager.GetActivityRankings(activityRankSize), activityRankSize)
+	}
+
+	r.logger.Info("[ActivityRoom] 玩家进入活动场",
+		zap.Uint32("room_id", r.id),
+		zap.Uint32("player_id", player.PlayerID),
+		zap.String("state", string(r.state)))
+
+	return resp, nil
+}
+
+// Hit 打动物（m_1872）
+// 每次扣除下注的活动金豆；击杀概率为 Percent‰ 除以动物赔率，各动物的期望积分相同
+// 击杀炸弹人时场上动物全部死亡，击杀概率按全部动物的赔率合计折算
+func (r *ActivityRoom) Hit(playerID, animalID, betVal uint32) (*pb.M_1872Toc, error) {
+	r.mu.Lock()
+
+	if r.state != ActivityStateActive || r.activity == nil {
+		r.mu.Unlock()
+		return nil, ErrActivityNotActive
+	}
+	player, ok := r.players[playerID]
+	if !ok {
+		r.mu.Unlock()
+		return nil, ErrPlayerNotInRoom
+	}
+	if !containsBetValue(r.activity.BetValues, betVal) {
+		r.mu.Unlock()
+		return nil, ErrInvalidBetValue
+	}
+	target, ok := r.animals[animalID]
+	if !ok {
+		r.mu.Unlock()
+		return nil, ErrActivityAnimalNotFound
+	}
+	if player.Gold < uint64(betVal) {
+		r.mu.Unlock()
+		return nil, ErrInsufficientFunds
+	}
+	player.Gold -= uint64(betVal)
+
+	pushes := []*PushMessage{{MsgID: 1881, Message: &pb.M_1881Toc{Id: proto.Uint32(animalID)}}}
+
+	victims := []*AnimalRoute{target}
+	effect := pb.EAnimalType_type_normal
+	if target.Animal == pb.EAnimal_bomber {
+		effect = pb.EAnimalType_boom
+		victims = victims[:0]
+		for _, id := range r.sortedAnimalIDsUnlocked() {
+			victims = append(victims, r.animals[id])
+		}
+	}
+
+	multipliers := make([]float64, len(victims))
+	var total float64
+	for i, victim := range victims {
+		multipliers[i] = r.animalMultiplier(victim.Animal)
+		total += multipliers[i]
+	}
+
+	if total > 0 && r.rand.Float64()*1000*total < float64(r.activity.Percent) {
+		die := &pb.M_1877Toc{
+			RoleId: proto.Uint32(playerID),
+			Type:   effect.Enum(),
+		}
+		var gained uint64
+		for i, victim := range victims {
+			score := uint64(float64(betVal) * multipliers[i])
+			gained += score
+			die.Ids = append(die.Ids, &pb.PAnimalOne{
+				Id:     proto.Uint32(victim.ID),
+				Win:    proto.Uint32(uint32(score)),
+				RedBag: proto.Uint32(0),
+			})
+			delete(r.animals, victim.ID)
+		}
+		player.Score += gained
+		r.manager.UpdatePlayerActivity(player.PlayerID, player.Name, player.Icon, player.VIP, gained)
+
+		pushes = append(pushes,
+			&PushMessage{MsgID: 1877, Message: die},
+			&PushMessage{MsgID: 1880, Message: &pb.M_1880Toc{
+				Rank: activityRankProto(r.manager.GetActivityRankings(activityRankSize), activityRankSize),
+			}},
+		)
+		if gained >= uint64(betVal)*activityBroadcastOdds {
+			pushes = append(pushes, &PushMessage{MsgID: 1889, Message: &pb.M_1889Toc{
+				Name:       proto.String(player.Name),
+				Icon:       proto.String(player.Icon),
+				AnimalName: target.Animal.Enum(),
+				Score:      proto.Uint64(gained),
+			}})
+		}
+	}
+
+	resp := &pb.M_1872Toc{
+		Balance: proto.Uint64(player.Gold),
+		Score:   proto.Uint32(uint32(player.Score)),
+	}
+	r.mu.Unlock()
+
+	for _, msg := range pushes {
+		r.push(msg.MsgID, msg.Message)
+	}
+	return resp, nil
+}
+
+// GetRank 获取排行榜（m_1873）：id 为0取当前活动，id 为 n 取往前第 n 场已结束的活动
+func (r *ActivityRoom) GetRank(id, num uint32) *pb.M_1873Toc {
+	limit := int(num)
+	if limit <= 0 {
+		limit = activityRankSize
+	}
+
+	var rankings []*PlayerRank
+	if id == 0 {
+		rankings = r.manager.GetActivityRankings(limit)
+	} else if history := r.manager.GetActivityHistory(int(id)); len(history) == int(id) {
+		rankings = history[0].Rankings
+	}
+
+	return &pb.M_1873Toc{Rank: activityRankProto(rankings, limit)}
+}
+
+// sortedAnimalIDsUnlocked 场上动物ID（调用方持有锁）
+func (r *ActivityRoom) sortedAnimalIDsUnlocked() []uint32 {
+	ids := make([]uint32, 0, len(r.animals))
+	for id := range r.animals {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// animalMultiplier 动物积分倍数（正式场赔率范围内随机，赔率需要除以10）
+func (r *ActivityRoom) animalMultiplier(animal pb.EAnimal) float64 {
+	odds, ok := AnimalOddsNormal[animal]
+	if !ok {
+		return float64(GetAnimalBaseOdds(animal))
+	}
+	low, high := float64(odds[0]), float64(odds[1])
+	return (low + r.rand.Float64()*(high-low)) / 10
+}
+
+// pushState 推送活动状态（m_1878）
+func (r *ActivityRoom) pushState(state ActivityState, seconds uint32) {
+	r.push(1878, &pb.M_1878Toc{
+		Time:  proto.Uint32(seconds),
+		State: proto.Uint32(activityStateCodes[state]),
+	})
+}
+
+// push 推送给活动场所有玩家
+func (r *ActivityRoom) push(msgID uint16, msg proto.Message) {
+	if r.pushCallback != nil {
+		r.pushCallback(&PushMessage{MsgID: msgID, Message: msg})
+	}
+}
+
+// pushTo 推送给指定玩家
+func (r *ActivityRoom) pushTo(playerID uint32, msgID uint16, msg proto.Message) {
+	if r.pushCallback != nil {
+		r.pushCallback(&PushMessage{MsgID: msgID, Targets: []uint32{playerID}, Message: msg})
+	}
+}
+
+// containsBetValue 下注是否为活动档位
+func containsBetValue(betValues []uint32, betVal uint32) bool {
+	for _, value := range betValues {
+		if value == betVal {
+			return true
+		}
+	}
+	return false
+}
+
+// activityRankProto 转换排行榜（最多 limit 名）
+func activityRankProto(rankings []*PlayerRank, limit int) []*pb.PRank {
+	if limit > 0 && len(rankings) > limit {
+		rankings = rankings[:limit]
+	}
+	result := make([]*pb.PRank, 0, len(rankings))
+	for _, rank := range rankings {
+		result = append(result, &pb.PRank{
+			Id:     proto.Uint32(rank.Rank),
+			RoleId: proto.Uint32(rank.PlayerID),
+			Name:   proto.String(rank.Name),
+			Icon:   proto.String(rank.Icon),
+			Val:    proto.Uint64(rank.Value),
+			Vip:    proto.Uint32(rank.VIP),
+		})
+	}
+	return result
+}
+
+// activityRewardProto 转换奖励配置
+func activityRewardProto(rewards []RewardConfig) []*pb.PActivityReward {
+	result := make([]*pb.PActivityReward, 0, len(rewards))
+	for _, reward := range rewards {
+		maxRank := reward.MaxRank
+		if maxRank < reward.Rank {
+			maxRank = reward.Rank
+		}
+		result = append(result, &pb.PActivityReward{
+			Min:  proto.Uint32(reward.Rank),
+			Max:  proto.Uint32(maxRank),
+			Desc: proto.String(fmt.Sprintf("%d金豆", reward.Gold)),
+		})
+	}
+	return result
+}
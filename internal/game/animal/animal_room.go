@@ -3,6 +3,7 @@ package animal
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -11,7 +12,13 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+const (
+	elephantComingTime = 5 * time.Second // 大象延迟进场时间
+)
+
 // AnimalRoom 动物房间（基于Erlang的zoo_room）
+// 房间引擎：tick 沿 PathManager 路径推进动物并维持数量，下注按击杀概率模型（ProfitConfig）、
+// OddsSystem 和 SpecialEffectProcessor 结算，/ws/game 和 /ws/binary 都由它驱动
 type AnimalRoom struct {
 	mu     sync.RWMutex
 	logger *zap.Logger
@@ -24,13 +31,23 @@ type AnimalRoom struct {
 	// 动物管理
 	animals   map[uint32]*AnimalRoute // 当前活跃动物
 	generator *AnimalGenerator        // 动物生成器
+	paths     *PathManager            // 动物移动路径
+
+	// 玩法结算
+	odds           *OddsSystem        // 赔率和命中计算
+	profitControl  *RoomProfitControl // 房间盈亏控制
+	profitConfig   ProfitConfig       // 击杀概率模型和RTP目标
+	jackpot        *JackpotPool       // 彩金池
+	taskManager    *TaskManager       // 任务进度
+	oneBlowManager *OneBlowManager    // 一击必杀
 
 	// 玩家管理
-	players map[uint32]*PlayerSession // 房间内玩家
+	players    map[uint32]*PlayerSession // 房间内玩家
+	spectators spectatorSet              // 观战的观众（不占座位）
 
 	// 游戏状态
 	iceTime     time.Time             // 冰冻结束时间
-	timers      map[uint32]*time.Timer // 动物定时器
+	lastTick    time.Time             // 上次更新时间
 	ticker      *time.Ticker           // 房间更新定时器
 	ctx         context.Context
 	cancel      context.CancelFunc
@@ -54,11 +71,18 @@ func NewAnimalRoom(id uint32, roomType pb.EZooType, logger *zap.Logger, pushCall
 		status:           "normal",
 		animals:          make(map[uint32]*AnimalRoute),
 		players:          make(map[uint32]*PlayerSession),
-		timers:           make(map[uint32]*time.Timer),
+		spectators:       newSpectatorSet(DefaultMaxSpectators),
 		ctx:              ctx,
 		cancel:           cancel,
 		pushCallback:     pushCallback,
 		generator:        NewAnimalGenerator(id, logger),
+		paths:            NewPathManager(),
+		odds:             NewOddsSystem(),
+		profitControl:    &RoomProfitControl{},
+		profitConfig:     CurrentProfitConfig(),
+		jackpot:          NewJackpotPool(),
+		taskManager:      NewTaskManager(),
+		oneBlowManager:   NewOneBlowManager(),
 		generateCooldown: 2 * time.Second, // 每2秒最多生成一只动物
 	}
 
@@ -72,6 +96,7 @@ func (r *AnimalRoom) Start() {
 
 	// 启动房间更新循环
 	r.ticker = time.NewTicker(1 * time.Second) // 1秒更新频率，避免过度生成动物
+	r.lastTick = time.Now()
 	go r.run()
 
 	// 初始生成一些动物
@@ -92,11 +117,6 @@ func (r *AnimalRoom) Stop() {
 		r.ticker.Stop()
 	}
 
-	// 清理所有定时器
-	for _, timer := range r.timers {
-		timer.Stop()
-	}
-
 	r.logger.Info("[AnimalRoom] 房间已停止",
 		zap.Uint32("room_id", r.id))
 }
@@ -113,6 +133,11 @@ func (r *AnimalRoom) GetRoomID() uint32 {
 	return r.id
 }
 
+// GetRoomType 获取房间场类型
+func (r *AnimalRoom) GetRoomType() pb.EZooType {
+	return r.roomType
+}
+
 // run 房间主循环（基于Erlang的zoo_room主循环）
 func (r *AnimalRoom) run() {
 	defer func() {
@@ -131,54 +156,105 @@ func (r *AnimalRoom) run() {
 	}
 }
 
-// update 更新房间状态
+// update 更新房间状态（服务端权威：动物位置、冰冻和离场都只由 tick 决定）
 func (r *AnimalRoom) update() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// 更新动物位置
-	r.updateAnimalPositions()
+	now := time.Now()
+	elapsed := now.Sub(r.lastTick)
+	r.lastTick = now
 
-	// 检查动物是否到达终点
-	r.checkAnimalsReachEnd()
+	// 冰冻结束后解冻
+	r.updateIceState(now)
+
+	// 沿路径移动动物，走完路径的动物离场
+	r.moveAnimals(now, elapsed)
 
 	// 生成新动物维持数量
 	r.maintainAnimalCount()
 }
 
-// updateAnimalPositions 更新动物位置
-func (r *AnimalRoom) updateAnimalPositions() {
-	now := time.Now()
-	for _, animal := range r.animals {
-		if animal.State == pb.EAnimalState_state_normal {
-			// 根据时间计算新位置
-			elapsed := now.Sub(animal.SpawnAt).Seconds()
-			pathLine := r.generator.GetLineByID(animal.LineID)
-			if pathLine != nil {
-				// 简单的线性移动：每秒移动1个点
-				newPoint := animal.Point + uint32(elapsed)
-				if newPoint <= uint32(pathLine.Point) {
-					animal.Point = newPoint
-				}
+// moveAnimals 推进动物路径进度（冰冻的动物不移动），延迟进场的动物到时推送进场
+func (r *AnimalRoom) moveAnimals(now time.Time, elapsed time.Duration) {
+	var finished []uint32
+
+	for animalID, animal := range r.animals {
+		if !animal.EnterAt.IsZero() {
+			if now.Before(animal.EnterAt) {
+				continue
 			}
+			animal.EnterAt = time.Time{}
+			r.recordTrail(animal, now)
+			r.pushAnimalEnter(animal)
+			continue
+		}
+
+		if animal.State == pb.EAnimalState_state_ice {
+			r.recordTrail(animal, now)
+			continue
 		}
+
+		if r.advanceAnimal(animal, elapsed) {
+			finished = append(finished, animalID)
+			continue
+		}
+		r.recordTrail(animal, now)
+	}
+
+	for _, animalID := range finished {
+		r.removeAnimal(animalID)
 	}
 }
 
-// checkAnimalsReachEnd 检查动物是否到达终点
-func (r *AnimalRoom) checkAnimalsReachEnd() {
-	var expiredAnimals []uint32
+// advanceAnimal 按经过的时间推进动物（线路每秒走1个点），返回是否已走完路径
+func (r *AnimalRoom) advanceAnimal(animal *AnimalRoute, elapsed time.Duration) bool {
+	pathLine := r.generator.GetLineByID(animal.LineID)
+	if pathLine == nil || pathLine.Point <= 0 {
+		return true
+	}
 
-	for animalID, animal := range r.animals {
-		pathLine := r.generator.GetLineByID(animal.LineID)
-		if pathLine != nil && animal.Point >= uint32(pathLine.Point) {
-			expiredAnimals = append(expiredAnimals, animalID)
+	animal.Progress += float32(elapsed.Seconds()) / pathLine.Point
+	if animal.Progress >= 1 {
+		animal.Progress = 1
+		return true
+	}
+
+	r.placeOnPath(animal, pathLine)
+	return false
+}
+
+// placeOnPath 根据路径进度更新动物的线路点和坐标
+func (r *AnimalRoom) placeOnPath(animal *AnimalRoute, pathLine *PathLine) {
+	animal.Point = uint32(animal.Progress*pathLine.Point) + 1
+	if path := r.paths.PathForLine(animal.LineID); path != nil {
+		animal.X, animal.Y = path.GetPosition(animal.Progress)
+	}
+}
+
+// updateIceState 冰冻时间结束后解冻所有动物
+func (r *AnimalRoom) updateIceState(now time.Time) {
+	if r.iceTime.IsZero() || now.Before(r.iceTime) {
+		return
+	}
+
+	r.iceTime = time.Time{}
+	for _, animal := range r.animals {
+		if animal.State == pb.EAnimalState_state_ice {
+			animal.State = pb.EAnimalState_state_normal
 		}
 	}
+}
 
-	// 移除到达终点的动物
-	for _, animalID := range expiredAnimals {
-		r.removeAnimal(animalID)
+// freezeAnimals 冰冻全场已进场的动物直到指定时间
+func (r *AnimalRoom) freezeAnimals(until time.Time) {
+	if until.After(r.iceTime) {
+		r.iceTime = until
+	}
+	for _, animal := range r.animals {
+		if animal.EnterAt.IsZero() {
+			animal.State = pb.EAnimalState_state_ice
+		}
 	}
 }
 
@@ -239,54 +315,26 @@ func (r *AnimalRoom) generateNewAnimal() {
 		return
 	}
 
-	// 立即添加到房间（所有动物都立即加入，这样GetAnimals能获取到）
-	r.animals[newAnimal.ID] = newAnimal
+	// 按生成器给出的起始点设置路径进度
+	if pathLine := r.generator.GetLineByID(newAnimal.LineID); pathLine != nil && pathLine.Point > 0 {
+		newAnimal.Progress = float32(newAnimal.Point-1) / pathLine.Point
+		r.placeOnPath(newAnimal, pathLine)
+	}
 
-	// 设置动物离开定时器
-	r.scheduleAnimalRemoval(newAnimal)
+	r.animals[newAnimal.ID] = newAnimal
 
 	// 检查是否是大象，需要延迟推送进场消息
 	if newAnimal.Animal == pb.EAnimal_elephant {
-		// 推送1883消息 - 大象将在5秒后进场
-		r.pushAnimalComing(newAnimal.Animal, 5)
-
-		// 5秒后推送进场消息（不需要再次获取锁）
-		time.AfterFunc(5*time.Second, func() {
-			// 推送动物进入消息
-			r.pushAnimalEnter(newAnimal)
-		})
+		// 推送1883消息 - 大象将在5秒后进场，到时由 tick 推送进场消息
+		newAnimal.EnterAt = time.Now().Add(elephantComingTime)
+		r.pushAnimalComing(newAnimal.Animal, uint32(elephantComingTime/time.Second))
 	} else {
 		// 非大象直接推送进场消息
+		r.recordTrail(newAnimal, time.Now())
 		r.pushAnimalEnter(newAnimal)
 	}
 }
 
-// scheduleAnimalRemoval 安排动物移除（基于Erlang的erlang:send_after逻辑）
-func (r *AnimalRoom) scheduleAnimalRemoval(animal *AnimalRoute) {
-	pathLine := r.generator.GetLineByID(animal.LineID)
-	if pathLine == nil {
-		return
-	}
-
-	// 计算动物在场时间
-	duration := time.Duration(pathLine.Point) * time.Second
-
-	// 设置定时器
-	timer := time.AfterFunc(duration, func() {
-		r.onAnimalTimeout(animal.ID)
-	})
-
-	r.timers[animal.ID] = timer
-}
-
-// onAnimalTimeout 动物超时处理
-func (r *AnimalRoom) onAnimalTimeout(animalID uint32) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	r.removeAnimal(animalID)
-}
-
 // removeAnimal 移除动物（基于Erlang的out_animal）
 func (r *AnimalRoom) removeAnimal(animalID uint32) {
 	animal, exists := r.animals[animalID]
@@ -297,12 +345,6 @@ func (r *AnimalRoom) removeAnimal(animalID uint32) {
 	// 移除动物
 	delete(r.animals, animalID)
 
-	// 清理定时器
-	if timer, exists := r.timers[animalID]; exists {
-		timer.Stop()
-		delete(r.timers, animalID)
-	}
-
 	// 推送动物离开消息
 	r.pushAnimalLeave(animal)
 
@@ -312,11 +354,8 @@ func (r *AnimalRoom) removeAnimal(animalID uint32) {
 		zap.String("animal_type", animal.Animal.String()))
 }
 
-// removeOldestAnimal 移除最老的动物（基于Erlang的动物数量控制）
+// removeOldestAnimal 移除最老的动物（基于Erlang的动物数量控制，调用方持有锁）
 func (r *AnimalRoom) removeOldestAnimal() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	var oldestAnimal *AnimalRoute
 	var oldestID uint32
 
@@ -350,6 +389,10 @@ func (r *AnimalRoom) GetAnimals() []*pb.PRoute {
 func (r *AnimalRoom) getAnimalsUnlocked() []*pb.PRoute {
 	var routes []*pb.PRoute
 	for _, animal := range r.animals {
+		// 尚未进场的动物不下发
+		if !animal.EnterAt.IsZero() {
+			continue
+		}
 		route := &pb.PRoute{
 			Id:       proto.Uint32(animal.ID),
 			Bet:      animal.Animal.Enum(),
@@ -416,6 +459,7 @@ func (r *AnimalRoom) EnterRoom(playerID uint32, name, icon, clientID string) (*p
 		ZooType:   r.roomType,
 		EnteredAt: time.Now(),
 		Skills:    make(map[pb.EAnimalSkillType]*PlayerSkill),
+		SkillEnds: make(map[pb.EAnimalSkillType]time.Time),
 		Seat:      seat,
 		ClientID:  clientID,
 	}
@@ -440,11 +484,13 @@ func (r *AnimalRoom) EnterRoom(playerID uint32, name, icon, clientID string) (*p
 	}
 
 	response := &pb.M_1801Toc{
-		BetVal:  betValues,
-		Odds:    odds,
-		Animals: animals,
-		Players: players,
-		Time:    proto.Uint32(timeLeft),
+		BetVal:   betValues,
+		Odds:     odds,
+		Animals:  animals,
+		Players:  players,
+		RedState: proto.Bool(false),
+		Time:     proto.Uint32(timeLeft),
+		Cj:       proto.String(fmt.Sprintf("%d", r.jackpot.GetCurrentAmount())),
 	}
 	r.logger.Info("[AnimalRoom] 响应数据准备完成", zap.Uint32("player_id", playerID))
 
@@ -538,32 +584,7 @@ func (r *AnimalRoom) getBetValues() []uint32 {
 
 // getAnimalOdds 获取动物赔率
 func (r *AnimalRoom) getAnimalOdds() []*pb.PAnimalOdds {
-	// 基础赔率配置
-	animalOdds := map[pb.EAnimal][]uint32{
-		pb.EAnimal_turtle:   {12, 20},
-		pb.EAnimal_cock:     {15, 25},
-		pb.EAnimal_dog:      {18, 30},
-		pb.EAnimal_monkey:   {20, 35},
-		pb.EAnimal_horse:    {22, 40},
-		pb.EAnimal_ox:       {25, 45},
-		pb.EAnimal_panda:    {50, 100},
-		pb.EAnimal_hippo:    {60, 120},
-		pb.EAnimal_lion:     {80, 150},
-		pb.EAnimal_elephant: {100, 200},
-		pb.EAnimal_pikachu:  {80, 160},
-		pb.EAnimal_bomber:   {200, 400},
-	}
-
-	var odds []*pb.PAnimalOdds
-	for animal, oddsRange := range animalOdds {
-		animalOdd := &pb.PAnimalOdds{
-			Bet:  animal.Enum(),
-			Odds: oddsRange,
-		}
-		odds = append(odds, animalOdd)
-	}
-
-	return odds
+	return r.odds.GetAnimalOddsRange(r.roomType)
 }
 
 // getPlayerList 获取房间玩家列表
@@ -672,50 +693,252 @@ func (r *AnimalRoom) pushPlayerLeave(session *PlayerSession) {
 	}
 }
 
-// killAnimal 击杀动物（基于Erlang原版动物被打死逻辑）
-func (r *AnimalRoom) killAnimal(animalID uint32, damage uint32) bool {
-	animal, exists := r.animals[animalID]
+// ProcessShot 结算玩家的一发子弹（基于Erlang的zoo_room:bet）
+// 击中哪只动物由服务端按弹道和动物在发射时刻的路径位置判定，客户端不能指定目标；
+// 击中后按击杀概率模型（房间和全局RTP修正、抽水保护）判定是否击杀，击杀效果和赢取由 SpecialEffectProcessor 计算，
+// 返回的 GoldAmount 为本次应发放给玩家的金豆，由调用方入账；
+// 发射时刻已超出动物轨迹保留时长的子弹无法判定命中，返回 ErrBulletExpired，不计入下注，由调用方退还
+func (r *AnimalRoom) ProcessShot(playerID, betVal, multiple uint32, trajectory Trajectory) (*BetOutcome, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.players[playerID]
 	if !exists {
-		return false
+		return nil, ErrPlayerNotInRoom
+	}
+	if time.Since(trajectory.FiredAt) > trailWindow {
+		return nil, ErrBulletExpired
 	}
 
-	// 基于Erlang原版：直接击杀逻辑，无HP系统
-	animal.State = pb.EAnimalState_state_ice // 使用冰冻状态表示死亡
+	if multiple == 0 {
+		multiple = 1
+	}
+	betAmount := betVal * multiple
 
-	// 推送动物死亡消息
-	r.pushAnimalDeath(animal)
+	outcome := &BetOutcome{EffectType: pb.EAnimalType_type_normal}
 
-	// 移除动物
-	r.removeAnimal(animalID)
+	// 未击中任何动物时只计入下注，一击必杀直接击杀
+	target := r.traceBullet(session.Seat, trajectory)
+	isOneBlow := r.oneBlowManager.CheckOneBlow(playerID)
+	if target != nil && (isOneBlow || rand.Float64() < r.killProbability(target)) {
+		targetID := target.ID
+		processor := NewSpecialEffectProcessor(r)
 
-	r.logger.Info("[AnimalRoom] 动物被击杀",
-		zap.Uint32("room_id", r.id),
-		zap.Uint32("animal_id", animalID),
-		zap.String("animal_type", animal.Animal.String()),
-		zap.Uint32("damage", damage))
+		switch target.Animal {
+		case pb.EAnimal_pikachu:
+			// 皮卡丘闪电链效果
+			outcome = processor.ProcessPikachuLightning(targetID, target, betVal, multiple, playerID)
+		case pb.EAnimal_bomber:
+			// 炸弹人全屏爆炸
+			outcome = processor.ProcessBomberExplosion(targetID, target, betVal, multiple, playerID)
+		default:
+			// 普通击杀
+			outcome = processor.ProcessNormalKill(targetID, target, betVal, multiple, playerID)
+		}
+
+		if isOneBlow {
+			r.oneBlowManager.ConsumeOneBlow(playerID)
+		}
+	}
+
+	// 彩金池按下注累积，击杀时尝试触发
+	r.jackpot.Accumulate(uint64(betAmount), playerID)
+	if len(outcome.KilledRoutes) > 0 {
+		if triggered, winAmount := r.jackpot.TryTrigger(playerID, uint64(betAmount)); triggered {
+			outcome.JackpotWin = winAmount
+			outcome.GoldAmount += uint32(winAmount)
+		}
+	}
+
+	// 房间和全局盈亏：每次下注计入P，实际发放的金豆计入W
+	r.recordSettlement(uint64(betAmount), uint64(outcome.GoldAmount))
+
+	r.updateTasks(betVal, betAmount, outcome)
+
+	session.TotalWin += uint64(outcome.GoldAmount)
+	outcome.TotalWin = session.TotalWin
+	if target == nil {
+		return outcome, nil
+	}
+	outcome.Animal = target.Animal
+	outcome.TargetID = target.ID
+
+	// 推送玩家打动物，被打死的动物移出房间并推送死亡
+	r.pushPlayerHit(playerID, target.ID)
+	if len(outcome.KilledRoutes) > 0 {
+		for _, killed := range outcome.KilledRoutes {
+			r.killAnimal(killed.ID)
+		}
+		r.pushAnimalDie(playerID, outcome)
+	}
+	if outcome.JackpotWin > 0 {
+		r.pushJackpotWin(outcome.JackpotWin)
+	}
 
-	return true
+	return outcome, nil
 }
 
-// DamageAnimal 对动物造成伤害（公共接口）
-func (r *AnimalRoom) DamageAnimal(animalID uint32, damage uint32) bool {
+// UseSkill 玩家使用技能（m_1806），冰冻技能使全场动物停止移动直到效果结束
+func (r *AnimalRoom) UseSkill(playerID uint32, skill *PlayerSkill) (*pb.M_1806Toc, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	return r.killAnimal(animalID, damage)
+	session, exists := r.players[playerID]
+	if !exists {
+		return nil, ErrPlayerNotInRoom
+	}
+
+	endAt := time.Now().Add(time.Duration(skill.Time) * time.Second)
+	session.SkillEnds[skill.Type] = endAt
+
+	if skill.Type == pb.EAnimalSkillType_skill_ice {
+		r.freezeAnimals(endAt)
+	}
+
+	r.pushSkillUsed(playerID, skill)
+
+	return &pb.M_1806Toc{
+		Skill: &pb.PAnimalSkill{
+			Type:  skill.Type.Enum(),
+			Val:   proto.Uint32(skill.Value),
+			Time:  proto.Uint32(skill.Time),
+			Count: proto.Uint32(skill.Count),
+		},
+	}, nil
 }
 
-// pushAnimalDeath 推送动物死亡消息（复用动物离开消息）
-func (r *AnimalRoom) pushAnimalDeath(animal *AnimalRoute) {
-	if r.pushCallback != nil {
-		msg := &pb.M_1888Toc{
-			Id: proto.Uint32(animal.ID),
+// updateTasks 更新任务进度（击杀、下注和赢取事件）
+func (r *AnimalRoom) updateTasks(betVal, betAmount uint32, outcome *BetOutcome) {
+	for _, killed := range outcome.KilledRoutes {
+		r.taskManager.UpdateProgress(TaskEvent{
+			Type:     "kill",
+			Animal:   killed.Animal,
+			RoomType: r.roomType,
+			Count:    1,
+		})
+	}
+
+	r.taskManager.UpdateProgress(TaskEvent{
+		Type:      "bet",
+		BetAmount: betAmount,
+		BetLevel:  betVal,
+		RoomType:  r.roomType,
+	})
+
+	if outcome.GoldAmount > 0 {
+		r.taskManager.UpdateProgress(TaskEvent{
+			Type:      "win",
+			WinAmount: outcome.GoldAmount,
+			RoomType:  r.roomType,
+			IsJackpot: outcome.JackpotWin > 0,
+		})
+	}
+}
+
+// killAnimal 移除被打死的动物并计入击杀数（死亡已通过1884推送，不再推送离开）
+func (r *AnimalRoom) killAnimal(animalID uint32) {
+	animal, exists := r.animals[animalID]
+	if !exists {
+		return
+	}
+
+	delete(r.animals, animalID)
+	r.profitControl.recordKill(1)
+	globalProfit.recordKill(1)
+
+	r.logger.Info("[AnimalRoom] 动物被击杀",
+		zap.Uint32("room_id", r.id),
+		zap.Uint32("animal_id", animalID),
+		zap.String("animal_type", animal.Animal.String()))
+}
+
+// otherPlayerIDs 房间内除指定玩家外的其他玩家
+func (r *AnimalRoom) otherPlayerIDs(exclude uint32) []uint32 {
+	ids := make([]uint32, 0, len(r.players))
+	for id := range r.players {
+		if id != exclude {
+			ids = append(ids, id)
 		}
+	}
+	return ids
+}
+
+// pushPlayerHit 推送玩家打动物（1899）
+func (r *AnimalRoom) pushPlayerHit(playerID, animalID uint32) {
+	if r.pushCallback != nil {
+		r.pushCallback(&PushMessage{
+			MsgID:   1899,
+			ZooType: r.roomType,
+			Message: &pb.M_1899Toc{
+				RoleId: proto.Uint32(playerID),
+				Id:     proto.Uint32(animalID),
+			},
+		})
+	}
+}
 
+// pushAnimalDie 推送动物被打死（1884）
+func (r *AnimalRoom) pushAnimalDie(playerID uint32, outcome *BetOutcome) {
+	if r.pushCallback != nil {
 		r.pushCallback(&PushMessage{
-			MsgID:   1888, // 使用动物离开消息ID
+			MsgID:   1884,
 			ZooType: r.roomType,
-			Message: msg,
+			Message: &pb.M_1884Toc{
+				RoleId: proto.Uint32(playerID),
+				Type:   outcome.EffectType.Enum(),
+				Ids:    buildKilledList(outcome),
+			},
+		})
+	}
+}
+
+// pushJackpotWin 推送彩金中奖（1811）
+func (r *AnimalRoom) pushJackpotWin(amount uint64) {
+	if r.pushCallback != nil {
+		r.pushCallback(&PushMessage{
+			MsgID:   1811,
+			ZooType: r.roomType,
+			Message: &pb.M_1811Toc{
+				Bonus: proto.String(fmt.Sprintf("%d", amount)),
+			},
+		})
+	}
+}
+
+// pushSkillUsed 推送玩家使用技能（1882），只发给房间内的其他玩家
+func (r *AnimalRoom) pushSkillUsed(playerID uint32, skill *PlayerSkill) {
+	others := r.otherPlayerIDs(playerID)
+	if r.pushCallback == nil || len(others) == 0 {
+		return
+	}
+
+	r.pushCallback(&PushMessage{
+		MsgID:   1882,
+		ZooType: r.roomType,
+		Targets: others,
+		Message: &pb.M_1882Toc{
+			RoleId: proto.Uint32(playerID),
+			Type:   skill.Type.Enum(),
+			Time:   proto.Uint32(skill.Time),
+		},
+	})
+}
+
+// buildKilledList 被打死的动物列表，赢取按击杀数平分
+func buildKilledList(outcome *BetOutcome) []*pb.PAnimalOne {
+	list := make([]*pb.PAnimalOne, 0, len(outcome.KilledRoutes))
+	if len(outcome.KilledRoutes) == 0 {
+		return list
+	}
+
+	perWin := outcome.GoldAmount / uint32(len(outcome.KilledRoutes))
+	for _, route := range outcome.KilledRoutes {
+		list = append(list, &pb.PAnimalOne{
+			Id:     proto.Uint32(route.ID),
+			Win:    proto.Uint32(perWin),
+			RedBag: proto.Uint32(outcome.RedBag),
 		})
 	}
-}
\ No newline at end of file
+
+	return list
+}
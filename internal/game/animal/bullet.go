@@ -17,6 +17,13 @@ type Bullet struct {
 	CreatedAt time.Time // 创建时间
 	Used      bool      // 是否已使用
 	ExpiredAt time.Time // 过期时间
+	Trajectory Trajectory // 弹道（服务端按此判定命中）
+}
+
+// Trajectory 子弹弹道：从玩家座位沿发射角度飞出，发射时间已做延迟补偿
+type Trajectory struct {
+	Angle   float32   // 发射角度（度，屏幕坐标系：0 水平向右，90 竖直向下）
+	FiredAt time.Time // 发射时间
 }
 
 // BulletManager 子弹管理器
@@ -24,13 +31,15 @@ type BulletManager struct {
 	mu      sync.RWMutex
 	bullets map[string]*Bullet  // bulletID -> Bullet
 	playerBullets map[uint32][]*Bullet // playerID -> Bullets
+	onExpire func(*Bullet) // 子弹超时未结算时调用，由调用方退还下注
 }
 
-// NewBulletManager 创建子弹管理器
-func NewBulletManager() *BulletManager {
+// NewBulletManager 创建子弹管理器，onExpire 在子弹超时未结算时调用（可为 nil）
+func NewBulletManager(onExpire func(*Bullet)) *BulletManager {
 	bm := &BulletManager{
 		bullets: make(map[string]*Bullet),
 		playerBullets: make(map[uint32][]*Bullet),
+		onExpire: onExpire,
 	}
 
 	// 启动清理协程，清理过期子弹
@@ -40,7 +49,7 @@ func NewBulletManager() *BulletManager {
 }
 
 // CreateBullet 创建子弹
-func (bm *BulletManager) CreateBullet(playerID uint32, betValue uint32, multiple uint32) *Bullet {
+func (bm *BulletManager) CreateBullet(playerID uint32, betValue uint32, multiple uint32, trajectory Trajectory) *Bullet {
 	bm.mu.Lock()
 	defer bm.mu.Unlock()
 
@@ -51,7 +60,8 @@ func (bm *BulletManager) CreateBullet(playerID uint32, betValue uint32, multiple
 		Multiple:  multiple,
 		CreatedAt: time.Now(),
 		Used:      false,
-		ExpiredAt: time.Now().Add(30 * time.Second), // 子弹30秒后过期
+		ExpiredAt: time.Now().Add(BulletLifetime), // 超时后无法回溯发射时刻的动物位置
+		Trajectory: trajectory,
 	}
 
 	bm.bullets[bullet.ID] = bullet
@@ -156,15 +166,24 @@ func (bm *BulletManager) UseOldestPlayerBullet(playerID uint32) (*Bullet, error)
 	return nil, fmt.Errorf("玩家没有有效子弹")
 }
 
-// cleanupExpiredBullets 清理过期子弹
+// cleanupExpiredBullets 清理过期子弹：超时未结算的子弹标记为已使用并交给 onExpire 退还下注，过期一分钟后删除
 func (bm *BulletManager) cleanupExpiredBullets() {
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
 	for range ticker.C {
 		bm.mu.Lock()
 		now := time.Now()
 
+		// 超时未结算的子弹不再结算
+		var expired []*Bullet
+		for _, bullet := range bm.bullets {
+			if !bullet.Used && now.After(bullet.ExpiredAt) {
+				bullet.Used = true
+				expired = append(expired, bullet)
+			}
+		}
+
 		// 清理过期子弹
 		for id, bullet := range bm.bullets {
 			if now.After(bullet.ExpiredAt.Add(1 * time.Minute)) {
@@ -188,6 +207,12 @@ func (bm *BulletManager) cleanupExpiredBullets() {
 		}
 
 		bm.mu.Unlock()
+
+		if bm.onExpire != nil {
+			for _, bullet := range expired {
+				bm.onExpire(bullet)
+			}
+		}
 	}
 }
 
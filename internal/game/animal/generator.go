@@ -71,12 +71,15 @@ func NewAnimalGenerator(roomID uint32, logger *zap.Logger) *AnimalGenerator {
 	return gen
 }
 
+// leftLinePoints、rightLinePoints 左右两侧线路走完全程的秒数（基于Erlang的left_line和right_line定义），
+// 左侧线路ID为 1..N、从左向右走，右侧线路ID从左侧末尾继续、从右向左走；PathManager 按同一张表生成线路路径
+var (
+	leftLinePoints  = []float32{36, 36, 40, 32, 42, 36, 38, 36, 40, 40, 36, 40, 40, 36, 34, 42, 34, 30, 36, 38, 34, 34}
+	rightLinePoints = []float32{36, 36, 40, 32, 42, 36, 38, 36, 40, 40, 36, 40, 40, 36, 34, 42, 34, 30, 36, 38, 34, 34}
+)
+
 // initializeLines 初始化路径线（基于Erlang的init_line逻辑）
 func (g *AnimalGenerator) initializeLines() {
-	// 基于Erlang的left_line和right_line定义
-	leftLinePoints := []float32{36, 36, 40, 32, 42, 36, 38, 36, 40, 40, 36, 40, 40, 36, 34, 42, 34, 30, 36, 38, 34, 34}
-	rightLinePoints := []float32{36, 36, 40, 32, 42, 36, 38, 36, 40, 40, 36, 40, 40, 36, 34, 42, 34, 30, 36, 38, 34, 34}
-
 	// 创建左侧路径线
 	g.leftLines = make([]*PathLine, len(leftLinePoints))
 	for i, point := range leftLinePoints {
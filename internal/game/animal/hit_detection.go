@@ -0,0 +1,194 @@
+package animal
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/pb"
+)
+
+// 服务端权威命中判定：子弹从玩家座位按发射角度飞出，逐步用动物在该时刻的路径位置
+// （PathManager 坐标 + GetAnimalSize 碰撞箱）判定碰撞，客户端只上报角度和发射时间
+
+var (
+	ErrSeatMismatch  = errors.New("animal: seat mismatch")
+	ErrBulletExpired = errors.New("animal: bullet expired")
+)
+
+const (
+	ScreenWidth  float32 = 800  // 场景宽度
+	ScreenHeight float32 = 600  // 场景高度
+	BulletSpeed  float32 = 1200 // 子弹飞行速度（像素/秒）
+
+	MaxFireLag     = 500 * time.Millisecond // 延迟补偿上限，更早的发射时间按上限回溯
+	BulletLifetime = 2 * time.Second        // 子弹等待结算的时长，覆盖飞过场景对角线（约0.83秒）和上报延迟，超时未结算的子弹退还下注
+	bulletStep     = 10 * time.Millisecond  // 弹道模拟步长（每步约12像素，小于最小碰撞箱）
+
+	// trailWindow 动物轨迹保留时长：子弹在登记后 BulletLifetime 内结算，发射时间最多早于登记 MaxFireLag，
+	// 结算时轨迹必须仍能回溯到发射时刻，另留半秒余量
+	trailWindow = MaxFireLag + BulletLifetime + 500*time.Millisecond
+)
+
+// seatOrigins 座位炮台位置：1、2 号座位在下方，3、4 号座位在上方
+var seatOrigins = map[uint32][2]float32{
+	1: {200, ScreenHeight},
+	2: {600, ScreenHeight},
+	3: {600, 0},
+	4: {200, 0},
+}
+
+// routeSample 动物在某一时刻的路径进度
+type routeSample struct {
+	At       time.Time
+	Progress float32
+}
+
+// SeatOrigin 获取座位的炮台位置
+func SeatOrigin(seat uint32) (x, y float32, ok bool) {
+	origin, ok := seatOrigins[seat]
+	return origin[0], origin[1], ok
+}
+
+// FireTime 延迟补偿后的发射时间：客户端发射时间（毫秒时间戳）最多回溯 MaxFireLag，
+// 未上报或晚于收到请求时使用收到请求的时间
+func FireTime(clientMillis uint64, received time.Time) time.Time {
+	if clientMillis == 0 {
+		return received
+	}
+
+	fired := time.UnixMilli(int64(clientMillis))
+	if fired.After(received) {
+		return received
+	}
+	if earliest := received.Add(-MaxFireLag); fired.Before(earliest) {
+		return earliest
+	}
+	return fired
+}
+
+// CheckSeat 校验发射请求的座位，未上报座位（0）时使用服务端分配的座位
+func (r *AnimalRoom) CheckSeat(playerID, seat uint32) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, exists := r.players[playerID]
+	if !exists {
+		return ErrPlayerNotInRoom
+	}
+	if seat != 0 && seat != session.Seat {
+		return ErrSeatMismatch
+	}
+	return nil
+}
+
+// recordTrail 记录动物当前的路径进度，丢弃超出保留时长的采样（保留一个更早的采样用于插值）
+func (r *AnimalRoom) recordTrail(animal *AnimalRoute, now time.Time) {
+	animal.trail = append(animal.trail, routeSample{At: now, Progress: animal.Progress})
+
+	cutoff := now.Add(-trailWindow)
+	drop := 0
+	for drop+1 < len(animal.trail) && animal.trail[drop+1].At.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		animal.trail = append(animal.trail[:0], animal.trail[drop:]...)
+	}
+}
+
+// progressAt 回溯动物在指定时刻的路径进度：采样之间线性插值，最后一次采样之后按线路速度推算
+// （冰冻的动物停在原地），动物尚未进场或已走完路径时返回 false
+func (r *AnimalRoom) progressAt(animal *AnimalRoute, at time.Time) (float32, bool) {
+	trail := animal.trail
+	if len(trail) == 0 || at.Before(trail[0].At) {
+		return 0, false
+	}
+
+	for i := len(trail) - 1; i >= 0; i-- {
+		sample := trail[i]
+		if sample.At.After(at) {
+			continue
+		}
+
+		if i+1 < len(trail) {
+			next := trail[i+1]
+			span := next.At.Sub(sample.At)
+			if span <= 0 {
+				return sample.Progress, true
+			}
+			t := float32(at.Sub(sample.At)) / float32(span)
+			return sample.Progress + (next.Progress-sample.Progress)*t, true
+		}
+
+		progress := sample.Progress
+		if animal.State != pb.EAnimalState_state_ice {
+			if pathLine := r.generator.GetLineByID(animal.LineID); pathLine != nil && pathLine.Point > 0 {
+				progress += float32(at.Sub(sample.At).Seconds()) / pathLine.Point
+			}
+		}
+		if progress >= 1 {
+			return 0, false
+		}
+		return progress, true
+	}
+	return 0, false
+}
+
+// traceBullet 按弹道模拟子弹飞行，返回第一只被击中的动物，飞出场景仍未命中时返回 nil
+// 每一步使用子弹到达该点时刻的动物位置，同一步碰到多只时取离子弹最近的
+func (r *AnimalRoom) traceBullet(seat uint32, trajectory Trajectory) *AnimalRoute {
+	originX, originY, ok := SeatOrigin(seat)
+	if !ok {
+		return nil
+	}
+
+	radians := float64(trajectory.Angle) * math.Pi / 180
+	dirX, dirY := float32(math.Cos(radians)), float32(math.Sin(radians))
+	stepDistance := BulletSpeed * float32(bulletStep.Seconds())
+
+	for step := 0; ; step++ {
+		travelled := stepDistance * float32(step)
+		x, y := originX+dirX*travelled, originY+dirY*travelled
+		if x < 0 || x > ScreenWidth || y < 0 || y > ScreenHeight {
+			return nil
+		}
+
+		at := trajectory.FiredAt.Add(time.Duration(step) * bulletStep)
+		var hit *AnimalRoute
+		var nearest float32
+		for _, animal := range r.animals {
+			if !animal.EnterAt.IsZero() {
+				continue
+			}
+			progress, ok := r.progressAt(animal, at)
+			if !ok {
+				continue
+			}
+			path := r.paths.PathForLine(animal.LineID)
+			if path == nil {
+				continue
+			}
+
+			animalX, animalY := path.GetPosition(progress)
+			halfSize := GetAnimalSize(animal.Animal) / 2
+			if absFloat(x-animalX) > halfSize || absFloat(y-animalY) > halfSize {
+				continue
+			}
+			d := distance(x, y, animalX, animalY)
+			if hit == nil || d < nearest || (d == nearest && animal.ID < hit.ID) {
+				hit, nearest = animal, d
+			}
+		}
+		if hit != nil {
+			return hit
+		}
+	}
+}
+
+// absFloat 取绝对值
+func absFloat(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
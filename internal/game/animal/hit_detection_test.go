@@ -0,0 +1,326 @@
+package animal
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+)
+
+// newTestRoom 创建未启动的房间（不生成动物），玩家 1 坐 1 号座位
+func newTestRoom(t *testing.T) *AnimalRoom {
+	room := NewAnimalRoom(1, pb.EZooType_civilian, zap.NewNop(), nil)
+	_, seat, err := room.EnterRoom(1, "player", "", "client-1")
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), seat)
+	return room
+}
+
+// placeAnimal 把冰冻的动物放在线路的指定进度上（冰冻的动物不随时间移动，便于计算瞄准角度）
+func placeAnimal(room *AnimalRoom, id, lineID uint32, progress float32, at time.Time) *AnimalRoute {
+	animal := &AnimalRoute{
+		ID:       id,
+		Animal:   pb.EAnimal_turtle,
+		LineID:   lineID,
+		State:    pb.EAnimalState_state_ice,
+		Progress: progress,
+		trail:    []routeSample{{At: at, Progress: progress}},
+	}
+	room.animals[id] = animal
+	return animal
+}
+
+// progressAtX 二分查找线路上横坐标为 x 的进度（线路路径的横坐标单调变化）
+func progressAtX(path *Path, x float32) float32 {
+	startX, _ := path.GetPosition(0)
+	endX, _ := path.GetPosition(1)
+	low, high := float32(0), float32(1)
+	for i := 0; i < 40; i++ {
+		mid := (low + high) / 2
+		midX, _ := path.GetPosition(mid)
+		if (midX < x) == (startX < endX) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// aimAngle 从座位瞄准指定位置的发射角度
+func aimAngle(t *testing.T, seat uint32, x, y float32) float32 {
+	originX, originY, ok := SeatOrigin(seat)
+	require.True(t, ok)
+	return float32(math.Atan2(float64(y-originY), float64(x-originX)) * 180 / math.Pi)
+}
+
+func TestFireTime(t *testing.T) {
+	received := time.UnixMilli(1_700_000_000_000)
+
+	tests := []struct {
+		name   string
+		client uint64
+		want   time.Time
+	}{
+		{"未上报使用收到时间", 0, received},
+		{"晚于收到时间按收到时间", uint64(received.Add(time.Second).UnixMilli()), received},
+		{"延迟补偿范围内使用客户端时间", uint64(received.Add(-200 * time.Millisecond).UnixMilli()), received.Add(-200 * time.Millisecond)},
+		{"恰好等于补偿上限", uint64(received.Add(-MaxFireLag).UnixMilli()), received.Add(-MaxFireLag)},
+		{"超出补偿上限按上限回溯", uint64(received.Add(-5 * time.Second).UnixMilli()), received.Add(-MaxFireLag)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(FireTime(tt.client, received)), "got %v", FireTime(tt.client, received))
+		})
+	}
+}
+
+func TestProgressAt(t *testing.T) {
+	room := newTestRoom(t)
+	start := time.Now()
+	pathLine := room.generator.GetLineByID(1)
+	require.NotNil(t, pathLine)
+	speed := 1 / pathLine.Point // 每秒推进的进度
+
+	tests := []struct {
+		name   string
+		state  pb.EAnimalState
+		trail  []routeSample
+		at     time.Time
+		want   float32
+		wantOK bool
+	}{
+		{
+			name:  "没有采样",
+			state: pb.EAnimalState_state_normal,
+			at:    start,
+		},
+		{
+			name:  "早于最早采样",
+			state: pb.EAnimalState_state_normal,
+			trail: []routeSample{{start, 0.1}, {start.Add(time.Second), 0.2}},
+			at:    start.Add(-time.Millisecond),
+		},
+		{
+			name:   "恰好在采样点",
+			state:  pb.EAnimalState_state_normal,
+			trail:  []routeSample{{start, 0.1}, {start.Add(time.Second), 0.2}},
+			at:     start,
+			want:   0.1,
+			wantOK: true,
+		},
+		{
+			name:   "采样之间线性插值",
+			state:  pb.EAnimalState_state_normal,
+			trail:  []routeSample{{start, 0.1}, {start.Add(time.Second), 0.2}},
+			at:     start.Add(250 * time.Millisecond),
+			want:   0.125,
+			wantOK: true,
+		},
+		{
+			name:   "最后一次采样之后按线路速度推算",
+			state:  pb.EAnimalState_state_normal,
+			trail:  []routeSample{{start, 0.1}, {start.Add(time.Second), 0.2}},
+			at:     start.Add(3 * time.Second),
+			want:   0.2 + 2*speed,
+			wantOK: true,
+		},
+		{
+			name:   "冰冻的动物停在原地",
+			state:  pb.EAnimalState_state_ice,
+			trail:  []routeSample{{start, 0.1}, {start.Add(time.Second), 0.2}},
+			at:     start.Add(3 * time.Second),
+			want:   0.2,
+			wantOK: true,
+		},
+		{
+			name:  "推算后已走完路径",
+			state: pb.EAnimalState_state_normal,
+			trail: []routeSample{{start, 0.99}},
+			at:    start.Add(time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			animal := &AnimalRoute{ID: 1, LineID: 1, State: tt.state, trail: tt.trail}
+			got, ok := room.progressAt(animal, tt.at)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.want, got, 1e-5)
+		})
+	}
+}
+
+func TestRecordTrailKeepsWindow(t *testing.T) {
+	room := newTestRoom(t)
+	start := time.Now()
+	animal := &AnimalRoute{ID: 1, LineID: 1}
+
+	for i := 0; i <= 10; i++ {
+		animal.Progress = float32(i) / 100
+		room.recordTrail(animal, start.Add(time.Duration(i)*time.Second))
+	}
+
+	// 保留时长内的采样加上一个更早的采样，结算期限内发射的子弹都能回溯
+	now := start.Add(10 * time.Second)
+	cutoff := now.Add(-trailWindow)
+	require.Greater(t, len(animal.trail), 1)
+	assert.True(t, animal.trail[0].At.Before(cutoff))
+	assert.False(t, animal.trail[1].At.Before(cutoff))
+	_, ok := room.progressAt(animal, now.Add(-MaxFireLag-BulletLifetime))
+	assert.True(t, ok)
+}
+
+func TestTraceBullet(t *testing.T) {
+	now := time.Now()
+	const lowerLine, upperLine = 16, 6 // 下方和上方的左侧线路
+
+	tests := []struct {
+		name    string
+		seat    uint32
+		angle   float32
+		place   func(room *AnimalRoom)
+		wantHit uint32
+	}{
+		{
+			name:  "1号座位向上打中下方通道的动物",
+			seat:  1,
+			angle: -90,
+			place: func(room *AnimalRoom) {
+				placeAnimal(room, 1, lowerLine, progressAtX(room.paths.PathForLine(lowerLine), 200), now)
+				placeAnimal(room, 2, upperLine, progressAtX(room.paths.PathForLine(upperLine), 200), now)
+			},
+			wantHit: 1,
+		},
+		{
+			name:  "4号座位向下打中上方通道的动物",
+			seat:  4,
+			angle: 90,
+			place: func(room *AnimalRoom) {
+				placeAnimal(room, 1, lowerLine, progressAtX(room.paths.PathForLine(lowerLine), 200), now)
+				placeAnimal(room, 2, upperLine, progressAtX(room.paths.PathForLine(upperLine), 200), now)
+			},
+			wantHit: 2,
+		},
+		{
+			name: "3号座位斜向瞄准",
+			seat: 3,
+			angle: func() float32 {
+				x, y := NewPathManager().PathForLine(lowerLine).GetPosition(0.3)
+				return aimAngle(t, 3, x, y)
+			}(),
+			place: func(room *AnimalRoom) {
+				placeAnimal(room, 5, lowerLine, 0.3, now)
+			},
+			wantHit: 5,
+		},
+		{
+			name:  "弹道上没有动物",
+			seat:  1,
+			angle: -90,
+			place: func(room *AnimalRoom) {
+				placeAnimal(room, 1, lowerLine, progressAtX(room.paths.PathForLine(lowerLine), 600), now)
+			},
+		},
+		{
+			name:  "未进场的动物不能被击中",
+			seat:  1,
+			angle: -90,
+			place: func(room *AnimalRoom) {
+				animal := placeAnimal(room, 1, lowerLine, progressAtX(room.paths.PathForLine(lowerLine), 200), now)
+				animal.EnterAt = now.Add(time.Minute)
+			},
+		},
+		{
+			name:  "未知座位",
+			seat:  9,
+			angle: -90,
+			place: func(room *AnimalRoom) {
+				placeAnimal(room, 1, lowerLine, progressAtX(room.paths.PathForLine(lowerLine), 200), now)
+			},
+		},
+		{
+			name:  "重叠的动物取编号小的",
+			seat:  1,
+			angle: -90,
+			place: func(room *AnimalRoom) {
+				progress := progressAtX(room.paths.PathForLine(lowerLine), 200)
+				placeAnimal(room, 7, lowerLine, progress, now)
+				placeAnimal(room, 3, lowerLine, progress, now)
+			},
+			wantHit: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := newTestRoom(t)
+			tt.place(room)
+
+			hit := room.traceBullet(tt.seat, Trajectory{Angle: tt.angle, FiredAt: now})
+			if tt.wantHit == 0 {
+				assert.Nil(t, hit)
+				return
+			}
+			require.NotNil(t, hit)
+			assert.Equal(t, tt.wantHit, hit.ID)
+		})
+	}
+}
+
+func TestLinePathsFollowLineDirection(t *testing.T) {
+	room := newTestRoom(t)
+
+	for _, lines := range [][]*PathLine{room.generator.leftLines, room.generator.rightLines} {
+		for _, line := range lines {
+			require.NotNil(t, room.paths.PathForLine(line.ID), "线路 %d 没有路径", line.ID)
+		}
+	}
+	assert.Nil(t, room.paths.PathForLine(0))
+	assert.Nil(t, room.paths.PathForLine(uint32(len(leftLinePoints)+len(rightLinePoints)+1)))
+
+	tests := []struct {
+		name        string
+		lineID      uint32
+		rightToLeft bool
+	}{
+		{"左侧第一条线路", 1, false},
+		{"左侧最后一条线路", uint32(len(leftLinePoints)), false},
+		{"右侧第一条线路", uint32(len(leftLinePoints) + 1), true},
+		{"右侧最后一条线路", uint32(len(leftLinePoints) + len(rightLinePoints)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			animal := &AnimalRoute{ID: 1, Animal: pb.EAnimal_turtle, LineID: tt.lineID, Progress: 0.2}
+			room.placeOnPath(animal, room.generator.GetLineByID(tt.lineID))
+			startX := animal.X
+
+			require.False(t, room.advanceAnimal(animal, 5*time.Second))
+			if tt.rightToLeft {
+				assert.Less(t, animal.X, startX, "右侧线路应从右向左移动")
+			} else {
+				assert.Greater(t, animal.X, startX, "左侧线路应从左向右移动")
+			}
+		})
+	}
+}
+
+func TestProcessShotRejectsStaleBullet(t *testing.T) {
+	room := newTestRoom(t)
+
+	// 子弹结算期限内的发射时间都在动物轨迹保留时长内
+	assert.LessOrEqual(t, MaxFireLag+BulletLifetime, trailWindow)
+
+	stale := Trajectory{Angle: -90, FiredAt: time.Now().Add(-trailWindow - time.Second)}
+	_, err := room.ProcessShot(1, 100, 1, stale)
+	assert.ErrorIs(t, err, ErrBulletExpired)
+
+	// 过期的子弹不计入房间盈亏
+	assert.Zero(t, room.profitControl.TotalBet)
+}
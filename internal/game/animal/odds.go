@@ -12,6 +12,11 @@ type OddsSystem struct {
 	mu sync.RWMutex
 }
 
+// NewOddsSystem 创建赔率系统
+func NewOddsSystem() *OddsSystem {
+	return &OddsSystem{}
+}
+
 // AnimalOddsNormal 正式场赔率（需要除以10）
 var AnimalOddsNormal = map[pb.EAnimal][2]float32{
 	pb.EAnimal_turtle:   {7, 14},       // 0.7-1.4
@@ -52,8 +57,8 @@ var AnimalOddsFree = map[pb.EAnimal]float32{
 	pb.EAnimal_bomber:   0.0, // 特殊处理
 }
 
-// CalculateDynamicOdds 计算动态赔率
-func (o *OddsSystem) CalculateDynamicOdds(animal pb.EAnimal, roomType pb.EZooType, vipLevel uint32, profitControl *RoomProfitControl) float32 {
+// CalculateDynamicOdds 计算动态赔率（房间盈亏通过击杀概率控制，不再调整赔率）
+func (o *OddsSystem) CalculateDynamicOdds(animal pb.EAnimal, roomType pb.EZooType, vipLevel uint32) float32 {
 	// 体验场使用固定赔率
 	if roomType == pb.EZooType_free {
 		if odds, exists := AnimalOddsFree[animal]; exists {
@@ -84,65 +89,23 @@ func (o *OddsSystem) CalculateDynamicOdds(animal pb.EAnimal, roomType pb.EZooTyp
 	vipBonus := 1.0 + float32(vipLevel)*0.02
 	odds *= vipBonus
 
-	// 根据房间盈亏调整赔率
-	if profitControl != nil {
-		odds = o.adjustOddsByProfit(odds, profitControl)
-	}
-
 	return odds
 }
 
-// adjustOddsByProfit 根据房间盈亏调整赔率
-func (o *OddsSystem) adjustOddsByProfit(baseOdds float32, profitControl *RoomProfitControl) float32 {
-	profitControl.mu.RLock()
-	totalBet := profitControl.TotalBet
-	totalWin := profitControl.TotalWin
-	profitControl.mu.RUnlock()
-
-	// 计算盈亏差值
-	diff := int64(totalWin) - int64(totalBet)
-
-	// 房间亏损过大（玩家赢太多），降低赔率
-	if diff > 10000000 {
-		return baseOdds * 0.8 // 降低20%赔率
-	}
-
-	// 房间盈利过大（玩家输太多），提高赔率
-	if diff < -10000000 {
-		return baseOdds * 1.2 // 提高20%赔率
-	}
-
-	return baseOdds
-}
-
-// ShouldHit 计算是否命中（考虑房间盈亏）
-func (o *OddsSystem) ShouldHit(profitControl *RoomProfitControl, baseHitRate float32) bool {
-	hitRate := baseHitRate
-
-	if profitControl != nil {
-		profitControl.mu.RLock()
-		totalBet := profitControl.TotalBet
-		totalWin := profitControl.TotalWin
-		profitControl.mu.RUnlock()
-
-		diff := int64(totalWin) - int64(totalBet)
-
-		// 房间亏损过大，降低命中率
-		if diff > 10000000 {
-			// 30分之1的概率强制不中
-			if rand.Intn(30) == 0 {
-				return false
-			}
-			hitRate *= 0.9 // 降低10%命中率
-		}
-
-		// 房间盈利过大，提高命中率
-		if diff < -10000000 {
-			hitRate *= 1.1 // 提高10%命中率
+// ExpectedOdds 动物的期望赔率（击杀概率模型使用）：体验场为固定赔率，正式场取赔率范围的中值
+func (o *OddsSystem) ExpectedOdds(animal pb.EAnimal, roomType pb.EZooType) float64 {
+	if roomType == pb.EZooType_free {
+		if odds, exists := AnimalOddsFree[animal]; exists {
+			return float64(odds)
 		}
+		return 1.0
 	}
 
-	return rand.Float32() < hitRate
+	baseRange, exists := AnimalOddsNormal[animal]
+	if !exists {
+		return 1.0
+	}
+	return float64(baseRange[0]+baseRange[1]) / 20.0
 }
 
 // GetAnimalOddsRange 获取动物赔率范围（用于客户端显示）
@@ -172,35 +135,51 @@ func (o *OddsSystem) GetAnimalOddsRange(roomType pb.EZooType) []*pb.PAnimalOdds
 	return result
 }
 
+// redBagConfig 红包触发概率和红包占赢取的比例范围
+type redBagConfig struct {
+	chance float32
+	min    float32
+	max    float32
+}
+
+// redBagConfigs 基于动物类型的红包概率和倍率
+var redBagConfigs = map[pb.EAnimal]redBagConfig{
+	pb.EAnimal_turtle:   {0.1, 0.05, 0.1},
+	pb.EAnimal_cock:     {0.15, 0.05, 0.1},
+	pb.EAnimal_dog:      {0.2, 0.05, 0.15},
+	pb.EAnimal_monkey:   {0.25, 0.1, 0.2},
+	pb.EAnimal_panda:    {0.3, 0.15, 0.3},
+	pb.EAnimal_elephant: {0.5, 0.2, 0.5},
+	pb.EAnimal_pikachu:  {0.35, 0.15, 0.25},
+}
+
+// defaultRedBagConfig 未单独配置的动物的红包概率和倍率
+var defaultRedBagConfig = redBagConfig{0.2, 0.05, 0.1}
+
+// redBagGoldRate 红包转金豆（1元红包 = 1200金豆）
+const redBagGoldRate = 1200
+
+// getRedBagConfig 获取动物的红包配置
+func getRedBagConfig(animal pb.EAnimal) redBagConfig {
+	if config, exists := redBagConfigs[animal]; exists {
+		return config
+	}
+	return defaultRedBagConfig
+}
+
+// ExpectedRedPacketRate 携带红包的动物被击杀时红包金豆相对赢取的期望倍数
+func (o *OddsSystem) ExpectedRedPacketRate(animal pb.EAnimal) float64 {
+	config := getRedBagConfig(animal)
+	return float64(config.chance) * float64(config.min+config.max) / 2 * redBagGoldRate
+}
+
 // CalculateRedPacket 计算红包奖励
 func (o *OddsSystem) CalculateRedPacket(animal pb.EAnimal, winAmount uint32, hasRedBag bool) (uint32, uint32) {
 	if !hasRedBag {
 		return 0, winAmount
 	}
 
-	// 基于动物类型的红包概率和倍率
-	redBagConfig := map[pb.EAnimal]struct {
-		chance float32
-		min    float32
-		max    float32
-	}{
-		pb.EAnimal_turtle:   {0.1, 0.05, 0.1},
-		pb.EAnimal_cock:     {0.15, 0.05, 0.1},
-		pb.EAnimal_dog:      {0.2, 0.05, 0.15},
-		pb.EAnimal_monkey:   {0.25, 0.1, 0.2},
-		pb.EAnimal_panda:    {0.3, 0.15, 0.3},
-		pb.EAnimal_elephant: {0.5, 0.2, 0.5},
-		pb.EAnimal_pikachu:  {0.35, 0.15, 0.25},
-	}
-
-	config, exists := redBagConfig[animal]
-	if !exists {
-		config = struct {
-			chance float32
-			min    float32
-			max    float32
-		}{0.2, 0.05, 0.1} // 默认配置
-	}
+	config := getRedBagConfig(animal)
 
 	// 检查是否触发红包
 	if rand.Float32() > config.chance {
@@ -211,9 +190,9 @@ func (o *OddsSystem) CalculateRedPacket(animal pb.EAnimal, winAmount uint32, has
 	redBagRatio := config.min + rand.Float32()*(config.max-config.min)
 	redBag := uint32(float32(winAmount) * redBagRatio)
 
-	// 红包转金豆（1元红包 = 1200金豆）
-	goldFromRedBag := redBag * 1200
+	// 红包转金豆
+	goldFromRedBag := redBag * redBagGoldRate
 	totalGold := winAmount + goldFromRedBag
 
 	return redBag, totalGold
-}
\ No newline at end of file
+}
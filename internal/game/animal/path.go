@@ -36,19 +36,50 @@ const (
 
 // PathManager 路径管理器
 type PathManager struct {
-	paths map[uint32]*Path
-	mu    sync.RWMutex
+	paths     map[uint32]*Path
+	linePaths map[uint32]*Path // 线路ID -> 线路路径
+	mu        sync.RWMutex
 }
 
 // NewPathManager 创建路径管理器
 func NewPathManager() *PathManager {
 	pm := &PathManager{
-		paths: make(map[uint32]*Path),
+		paths:     make(map[uint32]*Path),
+		linePaths: make(map[uint32]*Path),
 	}
 	pm.InitDefaultPaths()
+	pm.initLinePaths()
 	return pm
 }
 
+// initLinePaths 按生成器的线路表为每条线路生成路径：左侧线路从左向右走，
+// 右侧线路与同序号的左侧线路共用一条通道、从右向左走
+func (pm *PathManager) initLinePaths() {
+	for i := range leftLinePoints {
+		id := uint32(i + 1)
+		path := &Path{
+			ID:          id,
+			Name:        "左侧线路",
+			ControlType: PathTypeCurve,
+			Points:      linePathPoints(i, len(leftLinePoints), false),
+		}
+		path.calculateLength()
+		pm.linePaths[id] = path
+	}
+
+	for i := range rightLinePoints {
+		id := uint32(len(leftLinePoints) + i + 1)
+		path := &Path{
+			ID:          id,
+			Name:        "右侧线路",
+			ControlType: PathTypeCurve,
+			Points:      linePathPoints(i, len(rightLinePoints), true),
+		}
+		path.calculateLength()
+		pm.linePaths[id] = path
+	}
+}
+
 // InitDefaultPaths 初始化默认路径
 func (pm *PathManager) InitDefaultPaths() {
 	// 路径1: 上方水平移动
@@ -135,6 +166,14 @@ func (pm *PathManager) GetPath(id uint32) *Path {
 	return pm.paths[id]
 }
 
+// PathForLine 获取动物线路对应的移动路径，每个线路ID一条路径、方向与线路一致，未知线路返回 nil
+func (pm *PathManager) PathForLine(lineID uint32) *Path {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	return pm.linePaths[lineID]
+}
+
 // GetRandomPathID 获取随机路径ID
 func (pm *PathManager) GetRandomPathID() uint32 {
 	pm.mu.RLock()
@@ -285,6 +324,43 @@ func distance(x1, y1, x2, y2 float32) float32 {
 	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
 }
 
+// linePathPoints 生成线路路径点：lanes 条通道在场景高度内均匀分布，路径从场景左侧外进场、
+// 中段上下起伏后从右侧离场，rightToLeft 时沿同一通道反向行走
+func linePathPoints(lane, lanes int, rightToLeft bool) []PathPoint {
+	const margin float32 = 60 // 通道距场景上下边缘的距离
+	y := ScreenHeight / 2
+	if lanes > 1 {
+		y = margin + float32(lane)*(ScreenHeight-2*margin)/float32(lanes-1)
+	}
+
+	// 相邻通道起伏方向相反，避免并排的动物重叠
+	wave := float32(20)
+	if lane%2 == 1 {
+		wave = -wave
+	}
+
+	xs := []float32{-50, 150, 400, 650, ScreenWidth + 50}
+	offsets := []float32{0, -wave, 0, wave, 0}
+	direction := float32(0)
+	if rightToLeft {
+		direction = 180
+	}
+
+	points := make([]PathPoint, len(xs))
+	for i, x := range xs {
+		if rightToLeft {
+			x = ScreenWidth - x
+		}
+		points[i] = PathPoint{
+			X:         x,
+			Y:         y + offsets[i],
+			Direction: direction,
+			Speed:     1.0,
+		}
+	}
+	return points
+}
+
 // generateCirclePath 生成圆形路径点
 func generateCirclePath(centerX, centerY, radius float32, segments int) []PathPoint {
 	points := make([]PathPoint, segments)
@@ -0,0 +1,304 @@
+package animal
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/wfunc/slot-game/internal/pb"
+)
+
+// 击杀概率模型：动物的期望打击次数 expected_shots 决定击杀概率 1/expected_shots，
+// 该动物的理论RTP为 赔率/expected_shots；未单独配置时 expected_shots = 赔率/目标RTP。
+// 实际RTP偏离目标超过容忍带宽时按偏离比例修正击杀概率（房间和全局各修正一次），
+// 房间RTP过高时进入抽水保护，直到回落到目标RTP以下
+
+// ProfitConfig 动物房间盈亏控制配置
+type ProfitConfig struct {
+	TargetRTP       float64                // 房间目标RTP
+	GlobalTargetRTP float64                // 全局（所有动物房间）目标RTP
+	HouseEdgeBand   float64                // 目标RTP上下的容忍带宽，带内不修正
+	MaxAdjust       float64                // 单次修正击杀概率的最大比例
+	PumpRTP         float64                // 房间RTP超过该值时进入抽水保护
+	PumpFactor      float64                // 抽水保护期间击杀概率的系数
+	WarmupBet       uint64                 // 累计下注低于该值时样本太少，不做修正
+	ExpectedShots   map[pb.EAnimal]float64 // 按动物配置的期望打击次数
+}
+
+// DefaultProfitConfig 默认配置：目标RTP 95%，±2% 内不修正，房间RTP超过 110% 时抽水
+var DefaultProfitConfig = ProfitConfig{
+	TargetRTP:       0.95,
+	GlobalTargetRTP: 0.95,
+	HouseEdgeBand:   0.02,
+	MaxAdjust:       0.5,
+	PumpRTP:         1.10,
+	PumpFactor:      0.3,
+	WarmupBet:       100000,
+}
+
+// currentProfitConfig 当前生效的盈亏控制配置（配置文件或管理接口设置），新建房间使用该配置
+var (
+	currentProfitConfig   = DefaultProfitConfig
+	currentProfitConfigMu sync.RWMutex
+)
+
+// CurrentProfitConfig 获取当前生效的盈亏控制配置
+func CurrentProfitConfig() ProfitConfig {
+	currentProfitConfigMu.RLock()
+	defer currentProfitConfigMu.RUnlock()
+	return currentProfitConfig
+}
+
+// SetCurrentProfitConfig 校验并设置当前生效的盈亏控制配置（已有房间需另外调用 SetProfitConfig）
+func SetCurrentProfitConfig(config ProfitConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// 复制期望打击次数，调用方之后修改不影响房间
+	shots := make(map[pb.EAnimal]float64, len(config.ExpectedShots))
+	for animal, n := range config.ExpectedShots {
+		shots[animal] = n
+	}
+	config.ExpectedShots = shots
+
+	currentProfitConfigMu.Lock()
+	defer currentProfitConfigMu.Unlock()
+	currentProfitConfig = config
+	return nil
+}
+
+// Validate 校验盈亏控制配置
+func (c *ProfitConfig) Validate() error {
+	switch {
+	case c.TargetRTP <= 0 || c.GlobalTargetRTP <= 0:
+		return fmt.Errorf("animal: target rtp must be positive")
+	case c.HouseEdgeBand < 0:
+		return fmt.Errorf("animal: house edge band must not be negative")
+	case c.MaxAdjust < 0 || c.MaxAdjust > 1:
+		return fmt.Errorf("animal: max adjust must be within [0, 1]")
+	case c.PumpRTP <= c.TargetRTP:
+		return fmt.Errorf("animal: pump rtp must be above target rtp")
+	case c.PumpFactor < 0 || c.PumpFactor > 1:
+		return fmt.Errorf("animal: pump factor must be within [0, 1]")
+	}
+	for animal, shots := range c.ExpectedShots {
+		if shots <= 0 {
+			return fmt.Errorf("animal: expected shots of %s must be positive", animal)
+		}
+	}
+	return nil
+}
+
+// ParseExpectedShots 把按动物名（pb.EAnimal 枚举名）配置的期望打击次数转换为按动物类型
+func ParseExpectedShots(shots map[string]float64) (map[pb.EAnimal]float64, error) {
+	parsed := make(map[pb.EAnimal]float64, len(shots))
+	for name, n := range shots {
+		value, ok := pb.EAnimal_value[name]
+		if !ok {
+			return nil, fmt.Errorf("animal: unknown animal %q", name)
+		}
+		parsed[pb.EAnimal(value)] = n
+	}
+	return parsed, nil
+}
+
+// globalProfit 所有动物房间汇总的盈亏（全局RTP）
+var globalProfit = &RoomProfitControl{}
+
+// GlobalProfit 获取所有动物房间汇总的盈亏
+func GlobalProfit() *RoomProfitControl {
+	return globalProfit
+}
+
+// ProfitStats 盈亏快照（P值、W值和实际RTP）
+type ProfitStats struct {
+	RoomID    uint32  `json:"room_id,omitempty"`
+	RoomType  string  `json:"room_type,omitempty"`
+	Players   uint32  `json:"players"`
+	TotalBet  uint64  `json:"total_bet"`
+	TotalWin  uint64  `json:"total_win"`
+	RTP       float64 `json:"rtp"`
+	TargetRTP float64 `json:"target_rtp"`
+	Shots     uint64  `json:"shots"`
+	Kills     uint64  `json:"kills"`
+	Pumping   bool    `json:"pumping"`
+}
+
+// Record 记录一次下注结算
+func (pc *RoomProfitControl) Record(bet, win uint64) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.TotalBet += bet
+	pc.TotalWin += win
+	pc.Shots++
+}
+
+// recordKill 记录被击杀的动物数
+func (pc *RoomProfitControl) recordKill(count int) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.Kills += uint64(count)
+}
+
+// RTP 实际RTP（W/P），尚无下注时为0
+func (pc *RoomProfitControl) RTP() float64 {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.rtpUnlocked()
+}
+
+// rtpUnlocked 实际RTP（调用方持有锁）
+func (pc *RoomProfitControl) rtpUnlocked() float64 {
+	if pc.TotalBet == 0 {
+		return 0
+	}
+	return float64(pc.TotalWin) / float64(pc.TotalBet)
+}
+
+// Stats 获取盈亏快照
+func (pc *RoomProfitControl) Stats(targetRTP float64) ProfitStats {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	return ProfitStats{
+		TotalBet:  pc.TotalBet,
+		TotalWin:  pc.TotalWin,
+		RTP:       pc.rtpUnlocked(),
+		TargetRTP: targetRTP,
+		Shots:     pc.Shots,
+		Kills:     pc.Kills,
+		Pumping:   pc.Pumping,
+	}
+}
+
+// updatePump 房间RTP超过 PumpRTP 时进入抽水保护，回落到目标RTP以下时退出
+func (pc *RoomProfitControl) updatePump(config *ProfitConfig) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.TotalBet < config.WarmupBet {
+		pc.Pumping = false
+		return
+	}
+
+	rtp := pc.rtpUnlocked()
+	switch {
+	case rtp > config.PumpRTP:
+		pc.Pumping = true
+	case rtp <= config.TargetRTP:
+		pc.Pumping = false
+	}
+}
+
+// correction 实际RTP偏离目标超出带宽时的修正系数：偏高降低击杀概率，偏低提高
+func (pc *RoomProfitControl) correction(targetRTP float64, config *ProfitConfig) float64 {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.TotalBet < config.WarmupBet || targetRTP <= 0 {
+		return 1
+	}
+
+	deviation := pc.rtpUnlocked() - targetRTP
+	if math.Abs(deviation) <= config.HouseEdgeBand {
+		return 1
+	}
+
+	adjust := math.Max(-config.MaxAdjust, math.Min(config.MaxAdjust, deviation/targetRTP))
+	return 1 - adjust
+}
+
+// ExpectedShotsOf 动物的期望打击次数（odds 为该动物的期望赔率）
+func (c *ProfitConfig) ExpectedShotsOf(animal pb.EAnimal, odds float64) float64 {
+	if shots, ok := c.ExpectedShots[animal]; ok && shots > 0 {
+		return shots
+	}
+	if c.TargetRTP <= 0 {
+		return 0
+	}
+	return odds / c.TargetRTP
+}
+
+// KillProbability 击杀概率：1/expected_shots 经房间RTP、全局RTP修正和抽水保护后的结果
+func (c *ProfitConfig) KillProbability(animal pb.EAnimal, odds float64, room, global *RoomProfitControl) float64 {
+	shots := c.ExpectedShotsOf(animal, odds)
+	if shots <= 0 {
+		return 0
+	}
+
+	probability := 1 / shots
+	if room != nil {
+		probability *= room.correction(c.TargetRTP, c)
+		room.mu.RLock()
+		if room.Pumping {
+			probability *= c.PumpFactor
+		}
+		room.mu.RUnlock()
+	}
+	if global != nil {
+		probability *= global.correction(c.GlobalTargetRTP, c)
+	}
+
+	return math.Max(0, math.Min(1, probability))
+}
+
+// SetProfitConfig 设置房间的盈亏控制配置
+func (r *AnimalRoom) SetProfitConfig(config ProfitConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.profitConfig = config
+}
+
+// GetProfitStats 获取房间实时盈亏（P值、W值、RTP）
+func (r *AnimalRoom) GetProfitStats() ProfitStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := r.profitControl.Stats(r.profitConfig.TargetRTP)
+	stats.RoomID = r.id
+	stats.RoomType = r.roomType.String()
+	stats.Players = uint32(len(r.players))
+	return stats
+}
+
+// killProbability 按击杀概率模型计算目标动物的击杀概率
+func (r *AnimalRoom) killProbability(target *AnimalRoute) float64 {
+	return r.profitConfig.KillProbability(target.Animal, r.expectedKillOdds(target), r.profitControl, globalProfit)
+}
+
+// expectedKillOdds 击杀目标的期望赔率（含红包转换的金豆）：炸弹人没有自身赔率，按全场被炸动物的赔率之和计算
+func (r *AnimalRoom) expectedKillOdds(target *AnimalRoute) float64 {
+	if target.Animal != pb.EAnimal_bomber {
+		odds := r.odds.ExpectedOdds(target.Animal, r.roomType)
+		if target.Red {
+			odds *= 1 + r.odds.ExpectedRedPacketRate(target.Animal)
+		}
+		return odds
+	}
+
+	processor := NewSpecialEffectProcessor(r)
+	total := 0.0
+	for id, animal := range r.animals {
+		if id == target.ID || !animal.EnterAt.IsZero() {
+			continue
+		}
+		if animal.Animal == pb.EAnimal_pikachu || animal.Animal == pb.EAnimal_bomber {
+			continue
+		}
+		odds := r.odds.ExpectedOdds(animal.Animal, r.roomType) * float64(processor.getBomberDamageRatio(animal.Animal))
+		if animal.Red {
+			odds *= 1 + r.odds.ExpectedRedPacketRate(animal.Animal)
+		}
+		total += odds
+	}
+	return total
+}
+
+// recordSettlement 结算计入房间和全局盈亏，并更新抽水保护状态
+func (r *AnimalRoom) recordSettlement(bet, win uint64) {
+	r.profitControl.Record(bet, win)
+	globalProfit.Record(bet, win)
+	r.profitControl.updatePump(&r.profitConfig)
+}
@@ -0,0 +1,191 @@
+package animal
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/pb"
+)
+
+const (
+	simAnimal = pb.EAnimal_dog
+	simOdds   = 10.0 // 模拟动物的期望赔率
+	simBet    = 100  // 每发子弹的下注
+)
+
+// simulateShots 按击杀概率模型模拟 shots 发子弹，每发都击中模拟动物，结算计入房间和全局盈亏
+func simulateShots(rng *rand.Rand, config *ProfitConfig, room, global *RoomProfitControl, shots int) {
+	for i := 0; i < shots; i++ {
+		var win uint64
+		if rng.Float64() < config.KillProbability(simAnimal, simOdds, room, global) {
+			win = uint64(simOdds * simBet)
+		}
+		room.Record(simBet, win)
+		global.Record(simBet, win)
+		room.updatePump(config)
+	}
+}
+
+// newProfitControl 创建已有下注历史的盈亏统计
+func newProfitControl(totalBet uint64, rtp float64) *RoomProfitControl {
+	return &RoomProfitControl{TotalBet: totalBet, TotalWin: uint64(float64(totalBet) * rtp)}
+}
+
+func TestKillProbabilityConvergesToTargetRTP(t *testing.T) {
+	const (
+		history = 10_000_000 // 模拟前的累计下注，是模拟下注的一半
+		shots   = 200_000
+	)
+
+	tests := []struct {
+		name     string
+		startRTP float64
+	}{
+		{"从高于目标回落", 1.05},
+		{"从低于目标回升", 0.80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func(config ProfitConfig) float64 {
+				room := newProfitControl(history, tt.startRTP)
+				global := newProfitControl(history, tt.startRTP)
+				simulateShots(rand.New(rand.NewSource(1)), &config, room, global, shots)
+				return room.RTP()
+			}
+
+			corrected := run(DefaultProfitConfig)
+
+			// 关闭修正作为对照：只靠稀释历史，偏离更大
+			uncorrectedConfig := DefaultProfitConfig
+			uncorrectedConfig.MaxAdjust = 0
+			uncorrected := run(uncorrectedConfig)
+
+			target := DefaultProfitConfig.TargetRTP
+			start := math.Abs(tt.startRTP - target)
+			assert.Less(t, math.Abs(corrected-target), start, "RTP should move toward target: %v", corrected)
+			assert.Less(t, math.Abs(corrected-target), math.Abs(uncorrected-target),
+				"correction should beat dilution: corrected=%v uncorrected=%v", corrected, uncorrected)
+			assert.InDelta(t, target, corrected, DefaultProfitConfig.HouseEdgeBand+0.01)
+		})
+	}
+}
+
+func TestCorrection(t *testing.T) {
+	config := DefaultProfitConfig
+	target := config.TargetRTP
+
+	tests := []struct {
+		name     string
+		totalBet uint64
+		rtp      float64
+		want     float64
+	}{
+		{"样本不足不修正", config.WarmupBet - 1, 2.0, 1},
+		{"带宽内不修正", config.WarmupBet, target + config.HouseEdgeBand/2, 1},
+		{"带宽内偏低不修正", config.WarmupBet, target - config.HouseEdgeBand/2, 1},
+		{"偏高降低击杀概率", config.WarmupBet, target + 0.095, 1 - 0.095/target},
+		{"偏低提高击杀概率", config.WarmupBet, target - 0.095, 1 + 0.095/target},
+		{"偏高超出上限按上限修正", config.WarmupBet, 3.0, 1 - config.MaxAdjust},
+		{"偏低超出上限按上限修正", config.WarmupBet, 0, 1 + config.MaxAdjust},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := newProfitControl(tt.totalBet, tt.rtp)
+			assert.InDelta(t, tt.want, room.correction(target, &config), 1e-3)
+		})
+	}
+}
+
+func TestKillProbability(t *testing.T) {
+	config := DefaultProfitConfig
+	config.ExpectedShots = map[pb.EAnimal]float64{pb.EAnimal_tiger: 50}
+	base := 1 / (simOdds / config.TargetRTP)
+
+	tests := []struct {
+		name   string
+		animal pb.EAnimal
+		room   *RoomProfitControl
+		global *RoomProfitControl
+		want   float64
+	}{
+		{"按赔率和目标RTP推算期望打击次数", simAnimal, nil, nil, base},
+		{"单独配置的期望打击次数", pb.EAnimal_tiger, nil, nil, 1.0 / 50},
+		{"房间偏高降低", simAnimal, newProfitControl(config.WarmupBet, 3.0), nil, base * (1 - config.MaxAdjust)},
+		{"全局偏低提高", simAnimal, nil, newProfitControl(config.WarmupBet, 0), base * (1 + config.MaxAdjust)},
+		{"房间和全局各修正一次", simAnimal, newProfitControl(config.WarmupBet, 3.0), newProfitControl(config.WarmupBet, 3.0),
+			base * (1 - config.MaxAdjust) * (1 - config.MaxAdjust)},
+		{"抽水保护", simAnimal, &RoomProfitControl{Pumping: true}, nil, base * config.PumpFactor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := config.KillProbability(tt.animal, simOdds, tt.room, tt.global)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestUpdatePump(t *testing.T) {
+	config := DefaultProfitConfig
+
+	tests := []struct {
+		name     string
+		pumping  bool
+		totalBet uint64
+		rtp      float64
+		want     bool
+	}{
+		{"样本不足不抽水", false, config.WarmupBet - 1, 2.0, false},
+		{"样本不足时退出抽水", true, config.WarmupBet - 1, 2.0, false},
+		{"超过抽水线进入抽水", false, config.WarmupBet, config.PumpRTP + 0.01, true},
+		{"未超过抽水线不抽水", false, config.WarmupBet, config.PumpRTP, false},
+		{"抽水中回落到目标以上继续抽水", true, config.WarmupBet, config.TargetRTP + 0.05, true},
+		{"回落到目标RTP退出抽水", true, config.WarmupBet, config.TargetRTP, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := newProfitControl(tt.totalBet, tt.rtp)
+			room.Pumping = tt.pumping
+			room.updatePump(&config)
+			assert.Equal(t, tt.want, room.Pumping)
+		})
+	}
+}
+
+func TestPumpEngagesAndReleases(t *testing.T) {
+	config := DefaultProfitConfig
+	rng := rand.New(rand.NewSource(1))
+	room := newProfitControl(config.WarmupBet*10, 1.0)
+	global := &RoomProfitControl{}
+
+	// 玩家连续大赢把房间RTP推过抽水线
+	for !room.Pumping {
+		room.Record(simBet, uint64(simOdds*simBet))
+		room.updatePump(&config)
+	}
+	require.Greater(t, room.RTP(), config.PumpRTP)
+
+	pumped := config.KillProbability(simAnimal, simOdds, room, global)
+	room.Pumping = false
+	unpumped := config.KillProbability(simAnimal, simOdds, room, global)
+	room.Pumping = true
+	assert.InDelta(t, unpumped*config.PumpFactor, pumped, 1e-9)
+
+	// 抽水期间RTP回落，回到目标RTP时退出抽水
+	shots := 0
+	for room.Pumping && shots < 100_000 {
+		simulateShots(rng, &config, room, global, 1)
+		shots++
+	}
+	require.False(t, room.Pumping, "pump should release after %d shots, rtp=%v", shots, room.RTP())
+	assert.LessOrEqual(t, room.RTP(), config.TargetRTP)
+
+	// 退出后击杀概率不再打折
+	assert.Greater(t, config.KillProbability(simAnimal, simOdds, room, global), pumped)
+}
@@ -9,11 +9,11 @@ import (
 
 // SpecialEffectProcessor 特殊动物效果处理器
 type SpecialEffectProcessor struct {
-	room *Room
+	room *AnimalRoom
 }
 
 // NewSpecialEffectProcessor 创建特殊效果处理器
-func NewSpecialEffectProcessor(room *Room) *SpecialEffectProcessor {
+func NewSpecialEffectProcessor(room *AnimalRoom) *SpecialEffectProcessor {
 	return &SpecialEffectProcessor{room: room}
 }
 
@@ -26,12 +26,11 @@ func (p *SpecialEffectProcessor) ProcessPikachuLightning(targetID uint32, target
 	}
 
 	// 计算主目标奖励
-	oddsSystem := &OddsSystem{}
+	oddsSystem := p.room.odds
 	odds := oddsSystem.CalculateDynamicOdds(
 		targetAnimal.Animal,
-		p.room.Type,
+		p.room.roomType,
 		0, // TODO: 获取玩家VIP等级
-		p.room.profitControl,
 	)
 
 	mainWin := uint32(float32(betVal*multiple) * odds)
@@ -59,9 +58,8 @@ func (p *SpecialEffectProcessor) ProcessPikachuLightning(targetID uint32, target
 			chainDamageRatio := 1.0 - float32(chainKillCount)*0.2 // 每次递减20%
 			chainOdds := oddsSystem.CalculateDynamicOdds(
 				chainAnimal.Animal,
-				p.room.Type,
+				p.room.roomType,
 				0,
-				p.room.profitControl,
 			)
 
 			chainWin := uint32(float32(betVal*multiple) * chainOdds * chainDamageRatio)
@@ -93,7 +91,7 @@ func (p *SpecialEffectProcessor) ProcessBomberExplosion(targetID uint32, targetA
 		ChainKills: []uint32{},
 	}
 
-	oddsSystem := &OddsSystem{}
+	oddsSystem := p.room.odds
 	totalWin := uint32(0)
 	totalRedBag := uint32(0)
 	totalGold := uint32(0)
@@ -101,8 +99,8 @@ func (p *SpecialEffectProcessor) ProcessBomberExplosion(targetID uint32, targetA
 	// 炸弹人本身没有赔率，只计算爆炸效果
 	// 遍历所有动物，除了特殊动物外全部击杀
 	for id, animal := range p.room.animals {
-		// 跳过炸弹人自己
-		if id == targetID {
+		// 跳过炸弹人自己和尚未进场的动物
+		if id == targetID || !animal.EnterAt.IsZero() {
 			continue
 		}
 
@@ -114,9 +112,8 @@ func (p *SpecialEffectProcessor) ProcessBomberExplosion(targetID uint32, targetA
 		// 计算每个动物的奖励
 		odds := oddsSystem.CalculateDynamicOdds(
 			animal.Animal,
-			p.room.Type,
+			p.room.roomType,
 			0,
-			p.room.profitControl,
 		)
 
 		// 爆炸伤害有一定衰减（根据动物价值）
@@ -160,8 +157,8 @@ func (p *SpecialEffectProcessor) findChainTargets(source *AnimalRoute, maxTarget
 	var candidates []distanceAnimal
 
 	for id, animal := range p.room.animals {
-		// 跳过源动物和已经死亡的动物
-		if id == source.ID || animal == nil {
+		// 跳过源动物、已经死亡和尚未进场的动物
+		if id == source.ID || animal == nil || !animal.EnterAt.IsZero() {
 			continue
 		}
 
@@ -233,12 +230,11 @@ func (p *SpecialEffectProcessor) ProcessNormalKill(targetID uint32, targetAnimal
 	}
 
 	// 计算赔率
-	oddsSystem := &OddsSystem{}
+	oddsSystem := p.room.odds
 	odds := oddsSystem.CalculateDynamicOdds(
 		targetAnimal.Animal,
-		p.room.Type,
+		p.room.roomType,
 		0, // TODO: 获取玩家VIP等级
-		p.room.profitControl,
 	)
 
 	// 计算基础奖励
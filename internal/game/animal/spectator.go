@@ -0,0 +1,158 @@
+package animal
+
+import (
+	"errors"
+
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrSpectatorsFull = errors.New("animal: spectators full")
+
+const (
+	DefaultMaxSpectators         = 50  // 普通动物房间的观战人数上限
+	DefaultActivityMaxSpectators = 200 // 活动场的观战人数上限
+)
+
+// spectatorSet 房间观众（按连接ID区分，不占座位），由所属房间的锁保护
+type spectatorSet struct {
+	ids map[string]struct{}
+	max int // 观战人数上限，0 表示不允许观战
+}
+
+func newSpectatorSet(max int) spectatorSet {
+	return spectatorSet{ids: make(map[string]struct{}), max: max}
+}
+
+// add 加入观众，已在观战时直接返回
+func (s *spectatorSet) add(id string) error {
+	if _, ok := s.ids[id]; ok {
+		return nil
+	}
+	if len(s.ids) >= s.max {
+		return ErrSpectatorsFull
+	}
+	s.ids[id] = struct{}{}
+	return nil
+}
+
+func (s *spectatorSet) remove(id string) bool {
+	if _, ok := s.ids[id]; !ok {
+		return false
+	}
+	delete(s.ids, id)
+	return true
+}
+
+func (s *spectatorSet) count() uint32 {
+	return uint32(len(s.ids))
+}
+
+// SetMaxSpectators 设置观战人数上限（与玩家座位分开计算，0 为关闭观战）
+func (r *AnimalRoom) SetMaxSpectators(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spectators.max = max
+}
+
+// GetSpectatorCount 获取观战人数
+func (r *AnimalRoom) GetSpectatorCount() uint32 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.spectators.count()
+}
+
+// Watch 观众进入房间观战（m_1816），返回当前场景
+// 观众不占座位、不能下注，通过房间广播接收动物进出、死亡和玩家下注推送
+func (r *AnimalRoom) Watch(spectatorID string) (*pb.M_1816Toc, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.spectators.add(spectatorID); err != nil {
+		return nil, err
+	}
+
+	r.logger.Info("[AnimalRoom] 观众进入房间",
+		zap.Uint32("room_id", r.id),
+		zap.String("spectator_id", spectatorID),
+		zap.Uint32("watchers", r.spectators.count()))
+
+	return &pb.M_1816Toc{
+		RoomId:   proto.Uint32(r.id),
+		BetVal:   r.getBetValues(),
+		Odds:     r.getAnimalOdds(),
+		Animals:  r.getAnimalsUnlocked(),
+		Players:  r.getPlayerList(),
+		Watchers: proto.Uint32(r.spectators.count()),
+	}, nil
+}
+
+// Unwatch 观众离开房间
+func (r *AnimalRoom) Unwatch(spectatorID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.spectators.remove(spectatorID) {
+		r.logger.Info("[AnimalRoom] 观众离开房间",
+			zap.Uint32("room_id", r.id),
+			zap.String("spectator_id", spectatorID))
+	}
+}
+
+// SetMaxSpectators 设置观战人数上限（与活动人数上限分开计算，0 为关闭观战）
+func (r *ActivityRoom) SetMaxSpectators(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spectators.max = max
+}
+
+// GetSpectatorCount 获取观战人数
+func (r *ActivityRoom) GetSpectatorCount() uint32 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.spectators.count()
+}
+
+// Look 观战视角进入活动场（m_1879），返回当前场景、倒计时和排行榜
+func (r *ActivityRoom) Look(agentID uint32, spectatorID string) (*pb.M_1879Toc, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.config.AgentID != 0 && agentID != r.config.AgentID {
+		return nil, ErrActivityAgentMismatch
+	}
+	if err := r.spectators.add(spectatorID); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.M_1879Toc{
+		Animals: r.getAnimalsUnlocked(),
+		Time:    proto.Uint32(r.remainingUnlocked()),
+		State:   proto.Uint32(activityStateCodes[r.state]),
+		Reward:  activityRewardProto(r.config.Rewards),
+		AgentId: proto.Uint32(r.config.AgentID),
+	}
+	if r.activity != nil {
+		resp.Rank = activityRankProto(r.manager.GetActivityRankings(activityRankSize), activityRankSize)
+	}
+
+	r.logger.Info("[ActivityRoom] 观众进入活动场",
+		zap.Uint32("room_id", r.id),
+		zap.String("spectator_id", spectatorID),
+		zap.Uint32("watchers", r.spectators.count()))
+
+	return resp, nil
+}
+
+// Unwatch 观众离开活动场
+func (r *ActivityRoom) Unwatch(spectatorID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.spectators.remove(spectatorID) {
+		r.logger.Info("[ActivityRoom] 观众离开活动场",
+			zap.Uint32("room_id", r.id),
+			zap.String("spectator_id", spectatorID))
+	}
+}
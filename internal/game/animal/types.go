@@ -24,7 +24,7 @@ type PushMessage struct {
 	Message proto.Message
 }
 
-// Manager 负责管理所有动物房间和活动场
+// Manager 负责管理跨房间的玩家数据（技能库存、下注记录）和大奖榜，房间玩法由 AnimalRoom 驱动
 
 const (
 	// MAX_PLAYERS_PER_ROOM 每个房间最大玩家数
@@ -34,11 +34,6 @@ const (
 type Manager struct {
 	mu sync.RWMutex
 
-	// 动态房间管理
-	rooms        map[uint32]*Room              // roomID -> Room
-	roomsByType  map[pb.EZooType][]uint32      // zooType -> roomID list
-	nextRoomID   uint32                        // 下一个房间ID
-	
 	players      map[uint32]*Player
 	rewards      []*pb.PAnimalReward
 	rewardCursor uint32
@@ -59,40 +54,9 @@ type RoomProfitControl struct {
 	mu       sync.RWMutex
 	TotalBet uint64 // P值：总投注
 	TotalWin uint64 // W值：总赢取
-}
-
-// Room 表示一个动物房间实例
-type Room struct {
-	ID            uint32      // 房间唯一ID
-	Type          pb.EZooType
-	Config        *RoomConfig
-	BetValues     []uint32
-	MaxPlayer     uint32
-	MinVIP        uint32
-	CurrentPlayers uint32      // 当前玩家数
-
-	animals      map[uint32]*AnimalRoute
-	nextAnimalID uint32
-
-	players map[uint32]*PlayerSession
-
-	// 房间盈亏控制
-	profitControl *RoomProfitControl
-
-	// 彩金池
-	jackpot *JackpotPool
-
-	// 任务管理器
-	taskManager *TaskManager
-
-	// 一击必杀管理器
-	oneBlowManager *OneBlowManager
-
-	// 技能状态
-	iceTime     time.Time // 全场冰冻结束时间
-	skillStates map[pb.EAnimalSkillType]time.Time
-
-	redBag  bool
+	Shots    uint64 // 结算的子弹数
+	Kills    uint64 // 击杀的动物数
+	Pumping  bool   // 是否处于抽水保护
 }
 
 // AnimalRoute 房间中动物当前状态
@@ -105,6 +69,10 @@ type AnimalRoute struct {
 	Red      bool
 	State    pb.EAnimalState
 	SpawnAt  time.Time
+	EnterAt  time.Time // 延迟进场时间（大象等），为零表示已在场上
+	Progress float32   // 路径进度 0-1，由房间 tick 推进
+	X, Y     float32   // 按 PathManager 路径计算的当前位置
+	trail    []routeSample // 最近的路径进度采样（命中判定回溯位置）
 }
 
 // PlayerSession 玩家在房间内的实时状态
@@ -155,6 +123,9 @@ type BetOutcome struct {
 	EffectType   pb.EAnimalType     // 击杀效果类型
 	ChainKills   []uint32           // 连锁击杀的动物ID
 	JackpotWin   uint64             // 彩金中奖金额
+	Animal       pb.EAnimal         // 被击中的动物类型
+	TargetID     uint32             // 服务端按弹道判定命中的动物ID（0 表示未命中）
+	TotalWin     uint64             // 玩家在本房间的累计赢取
 }
 
 // JackpotPool 彩金池系统
@@ -299,7 +270,7 @@ type Activity struct {
 	Percent     uint32         // 打动物的概率 1000分比
 	Status      string         // "prepare", "active", "settlement", "idle"
 	Rankings    []*PlayerRank  // 排行榜
-	Rewards     []string       // 奖励配置
+	Rewards     []RewardConfig // 奖励配置
 	StartTime   time.Time
 	EndTime     time.Time
 	AgentID     uint32         // 渠道ID
@@ -318,10 +289,12 @@ type PlayerRank struct {
 // ActivityManager 活动管理器
 type ActivityManager struct {
 	mu              sync.RWMutex
+	transitionMu    sync.Mutex // 串行化状态切换和状态变化回调
 	currentActivity *Activity
 	history         []*Activity
 	scheduler       *time.Timer
 	logger          *zap.Logger
+	onStateChange   func(activity *Activity)
 }
 
 // OneBlow 一击必杀设置
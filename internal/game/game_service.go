@@ -54,8 +54,14 @@ func NewGameService(config *GameServiceConfig) *GameService {
 	}
 }
 
-// StartGame 开始游戏
+// StartGame 开始游戏（默认机台）
 func (s *GameService) StartGame(ctx context.Context, userID uint, sessionID string, betAmount int64) error {
+	return s.StartGameWithAnte(ctx, userID, sessionID, "", betAmount, false)
+}
+
+// StartGameWithAnte 在指定机台开始游戏（machineID 为空时沿用会话的机台），ante 为真时按加注比例扣费
+// 会话中有剩余免费旋转（含购买的免费游戏）时不扣费
+func (s *GameService) StartGameWithAnte(ctx context.Context, userID uint, sessionID, machineID string, betAmount int64, ante bool) error {
 	// 验证用户
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
@@ -66,13 +72,34 @@ func (s *GameService) StartGame(ctx context.Context, userID uint, sessionID stri
 		return errors.New("用户账户已被禁用")
 	}
 	
+	// 创建或恢复会话
+	session, err := s.sessionManager.RecoverOrCreateSession(ctx, sessionID, userID, machineID)
+	if err != nil {
+		return fmt.Errorf("创建会话失败: %w", err)
+	}
+	if session.UserID != userID {
+		return errors.New("会话不属于该用户")
+	}
+	
+	// 计算实际扣费
+	stake, subType := betAmount, models.BetSubTypeSpin
+	if session.InFreeGame() {
+		stake, ante = 0, false
+	} else if ante {
+		anteConfig := session.SlotEngine.GetConfig().Ante
+		if anteConfig == nil {
+			return slot.ErrAnteUnavailable
+		}
+		stake, subType = anteConfig.Cost(betAmount), models.BetSubTypeAnte
+	}
+	
 	// 检查余额
 	wallet, err := s.walletRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("获取钱包失败: %w", err)
 	}
 	
-	if wallet.Balance < betAmount {
+	if wallet.Balance < stake {
 		return errors.New("余额不足")
 	}
 	
@@ -85,57 +112,151 @@ func (s *GameService) StartGame(ctx context.Context, userID uint, sessionID stri
 		}
 	}()
 	
-	// 扣除投注金额
-	if err := s.walletRepo.WithTx(tx).(repository.WalletRepository).DeductBalance(ctx, userID, betAmount); err != nil {
+	if stake > 0 {
+		if err := s.chargeBet(ctx, tx, wallet, sessionID, stake, subType); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	
+	// 开始游戏
+	if err := session.StartGameWithAnte(ctx, betAmount, ante); err != nil {
 		tx.Rollback()
-		return fmt.Errorf("扣除投注失败: %w", err)
+		return fmt.Errorf("开始游戏失败: %w", err)
 	}
 	
-	// 记录交易
-	transaction := &models.WalletTransaction{
-		UserID:        userID,
-		OrderNo:       fmt.Sprintf("BET-%s-%d", sessionID, time.Now().Unix()),
-		Type:          "bet",
-		Amount:        betAmount,
-		BeforeBalance: wallet.Balance,
-		AfterBalance:  wallet.Balance - betAmount,
-		RefType:       "game",
-		RefID:         sessionID,
-		Description:   "游戏投注",
-		Status:        "success",
+	// 提交事务
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
 	}
 	
-	if err := s.walletRepo.WithTx(tx).(repository.WalletRepository).CreateTransaction(ctx, transaction); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("记录交易失败: %w", err)
+	s.logger.Info("游戏开始",
+		zap.Uint("user_id", userID),
+		zap.String("session_id", sessionID),
+		zap.Int64("bet_amount", betAmount),
+		zap.Int64("stake", stake),
+		zap.Bool("ante", ante))
+	
+	return nil
+}
+
+// BuyFeature 购买免费游戏：按价格扣费后会话直接进入免费游戏（machineID 为空时沿用会话的机台）
+func (s *GameService) BuyFeature(ctx context.Context, userID uint, sessionID, machineID string, betAmount int64) (*BuyFeatureResponse, error) {
+	// 验证用户
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("用户不存在: %w", err)
+	}
+	
+	if !user.IsActive() {
+		return nil, errors.New("用户账户已被禁用")
 	}
 	
 	// 创建或恢复会话
-	session, err := s.sessionManager.RecoverOrCreateSession(ctx, sessionID, userID)
+	session, err := s.sessionManager.RecoverOrCreateSession(ctx, sessionID, userID, machineID)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("创建会话失败: %w", err)
+		return nil, fmt.Errorf("创建会话失败: %w", err)
+	}
+	if session.UserID != userID {
+		return nil, errors.New("会话不属于该用户")
 	}
 	
-	// 开始游戏
-	if err := session.StartGame(ctx, betAmount); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("开始游戏失败: %w", err)
+	buyConfig := session.SlotEngine.GetConfig().BuyFeature
+	if buyConfig == nil {
+		return nil, slot.ErrBuyUnavailable
 	}
+	price := buyConfig.Price(betAmount)
 	
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("提交事务失败: %w", err)
+	// 检查余额
+	wallet, err := s.walletRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("获取钱包失败: %w", err)
 	}
 	
-	s.logger.Info("游戏开始",
+	if wallet.Balance < price {
+		return nil, errors.New("余额不足")
+	}
+	
+	// 扣费提交成功后才开启免费游戏，提交失败不改变引擎状态
+	purchase, err := session.BuyFeatureWith(ctx, betAmount, func(price int64) error {
+		tx := s.db.Begin()
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+				panic(r)
+			}
+		}()
+		
+		if err := s.chargeBet(ctx, tx, wallet, sessionID, price, models.BetSubTypeBuyFeature); err != nil {
+			tx.Rollback()
+			return err
+		}
+		
+		// 提交事务
+		if err := tx.Commit().Error; err != nil {
+			return fmt.Errorf("提交事务失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	s.logger.Info("购买免费游戏",
 		zap.Uint("user_id", userID),
 		zap.String("session_id", sessionID),
-		zap.Int64("bet_amount", betAmount))
+		zap.Int64("bet_amount", betAmount),
+		zap.Int64("price", price),
+		zap.Int("free_spins", purchase.FreeGame.FreeSpinsTotal))
+	
+	response := &BuyFeatureResponse{
+		SessionID: sessionID,
+		Purchase:  purchase,
+		Balance:   wallet.Balance - price,
+		State:     string(session.GetState()),
+		TotalBet:  session.TotalBet,
+	}
+	
+	return response, nil
+}
+
+// chargeBet 在事务中扣除投注并记录交易（子类型区分普通下注、加注和购买免费游戏）
+func (s *GameService) chargeBet(ctx context.Context, tx *gorm.DB, wallet *models.Wallet, sessionID string, amount int64, subType string) error {
+	walletRepo := s.walletRepo.WithTx(tx).(repository.WalletRepository)
+	
+	// 扣除投注金额
+	if err := walletRepo.DeductBalance(ctx, wallet.UserID, amount); err != nil {
+		return fmt.Errorf("扣除投注失败: %w", err)
+	}
 	
+	// 记录交易
+	transaction := &models.WalletTransaction{
+		UserID:        wallet.UserID,
+		OrderNo:       fmt.Sprintf("BET-%s-%d", sessionID, time.Now().UnixNano()),
+		Type:          "bet",
+		SubType:       subType,
+		Amount:        amount,
+		BeforeBalance: wallet.Balance,
+		AfterBalance:  wallet.Balance - amount,
+		RefType:       "game",
+		RefID:         sessionID,
+		Description:   betDescriptions[subType],
+		Status:        "success",
+	}
+	
+	if err := walletRepo.CreateTransaction(ctx, transaction); err != nil {
+		return fmt.Errorf("记录交易失败: %w", err)
+	}
 	return nil
 }
 
+// betDescriptions 投注交易的说明
+var betDescriptions = map[string]string{
+	models.BetSubTypeSpin:       "游戏投注",
+	models.BetSubTypeAnte:       "游戏加注投注",
+	models.BetSubTypeBuyFeature: "购买免费游戏",
+}
+
 // Spin 执行转动
 func (s *GameService) Spin(ctx context.Context, sessionID string) (*SpinResponse, error) {
 	// 获取会话
@@ -150,71 +271,13 @@ func (s *GameService) Spin(ctx context.Context, sessionID string) (*SpinResponse
 		return nil, fmt.Errorf("转动失败: %w", err)
 	}
 	
-	// 如果有中奖，增加余额
-	if result.TotalPayout > 0 {
-		tx := s.db.Begin()
-		
-		// 增加余额
-		if err := s.walletRepo.WithTx(tx).(repository.WalletRepository).AddBalance(ctx, session.UserID, result.TotalPayout); err != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("增加余额失败: %w", err)
-		}
-		
-		// 获取当前余额
-		wallet, _ := s.walletRepo.WithTx(tx).(repository.WalletRepository).GetByUserID(ctx, session.UserID)
-		
-		// 记录中奖交易
-		transaction := &models.WalletTransaction{
-			UserID:        session.UserID,
-			OrderNo:       fmt.Sprintf("WIN-%s-%d", sessionID, time.Now().Unix()),
-			Type:          "win",
-			Amount:        result.TotalPayout,
-			BeforeBalance: wallet.Balance - result.TotalPayout,
-			AfterBalance:  wallet.Balance,
-			RefType:       "game",
-			RefID:         sessionID,
-			Description:   fmt.Sprintf("游戏中奖 - %s", result.GetWinDescription()),
-			Status:        "success",
-		}
-		
-		if err := s.walletRepo.WithTx(tx).(repository.WalletRepository).CreateTransaction(ctx, transaction); err != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("记录中奖交易失败: %w", err)
-		}
-		
-		tx.Commit()
-		
-		// 触发硬件出币（如果串口控制器可用）
-		if s.serialController != nil {
-			// 计算出币数量（每100分出1个币）
-			coinCount := int(result.TotalPayout / 100)
-			if coinCount > 0 {
-				// 异步触发出币，避免阻塞游戏流程
-				go func() {
-					// 根据币数调整推币力度和持续时间
-					// 力度范围: 50-100, 持续时间: 每个币500ms
-					force := 50 + (coinCount * 10)
-					if force > 100 {
-						force = 100
-					}
-					duration := time.Duration(coinCount) * 500 * time.Millisecond
-					
-					if err := s.serialController.PushCoin(force, duration); err != nil {
-						s.logger.Error("硬件出币失败",
-							zap.String("session_id", sessionID),
-							zap.Int("coin_count", coinCount),
-							zap.Int("force", force),
-							zap.Duration("duration", duration),
-							zap.Error(err))
-					} else {
-						s.logger.Info("硬件出币成功",
-							zap.String("session_id", sessionID),
-							zap.Int("coin_count", coinCount),
-							zap.Int("force", force),
-							zap.Duration("duration", duration))
-					}
-				}()
-			}
+	// 机台灯光响应期待效果
+	s.signalAnticipation(session, result.Anticipation)
+	
+	// 如果有中奖，增加余额（可以博倍时收分后再结算）
+	if payout := result.GetTotalPayout(); payout > 0 && result.Gamble == nil {
+		if err := s.creditWin(ctx, session, payout, fmt.Sprintf("游戏中奖 - %s", result.GetWinDescription())); err != nil {
+			return nil, err
 		}
 	}
 	
@@ -238,6 +301,111 @@ func (s *GameService) Spin(ctx context.Context, sessionID string) (*SpinResponse
 	return response, nil
 }
 
+// creditWin 将赢取结算到钱包并触发硬件出币
+func (s *GameService) creditWin(ctx context.Context, session *GameSession, amount int64, description string) error {
+	tx := s.db.Begin()
+	
+	// 增加余额
+	if err := s.walletRepo.WithTx(tx).(repository.WalletRepository).AddBalance(ctx, session.UserID, amount); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("增加余额失败: %w", err)
+	}
+	
+	// 获取当前余额
+	wallet, _ := s.walletRepo.WithTx(tx).(repository.WalletRepository).GetByUserID(ctx, session.UserID)
+	
+	// 记录中奖交易
+	transaction := &models.WalletTransaction{
+		UserID:        session.UserID,
+		OrderNo:       fmt.Sprintf("WIN-%s-%d", session.SessionID, time.Now().UnixNano()),
+		Type:          "win",
+		Amount:        amount,
+		BeforeBalance: wallet.Balance - amount,
+		AfterBalance:  wallet.Balance,
+		RefType:       "game",
+		RefID:         session.SessionID,
+		Description:   description,
+		Status:        "success",
+	}
+	
+	if err := s.walletRepo.WithTx(tx).(repository.WalletRepository).CreateTransaction(ctx, transaction); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("记录中奖交易失败: %w", err)
+	}
+	
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交中奖交易失败: %w", err)
+	}
+	
+	// 触发硬件出币（如果串口控制器可用）
+	if s.serialController != nil {
+		// 计算出币数量（每100分出1个币）
+		coinCount := int(amount / 100)
+		if coinCount > 0 {
+			// 异步触发出币，避免阻塞游戏流程
+			go func() {
+				// 根据币数调整推币力度和持续时间
+				// 力度范围: 50-100, 持续时间: 每个币500ms
+				force := 50 + (coinCount * 10)
+				if force > 100 {
+					force = 100
+				}
+				duration := time.Duration(coinCount) * 500 * time.Millisecond
+				
+				if err := s.serialController.PushCoin(force, duration); err != nil {
+					s.logger.Error("硬件出币失败",
+						zap.String("session_id", session.SessionID),
+						zap.Int("coin_count", coinCount),
+						zap.Int("force", force),
+						zap.Duration("duration", duration),
+						zap.Error(err))
+				} else {
+					s.logger.Info("硬件出币成功",
+						zap.String("session_id", session.SessionID),
+						zap.Int("coin_count", coinCount),
+						zap.Int("force", force),
+						zap.Duration("duration", duration))
+				}
+			}()
+		}
+	}
+	return nil
+}
+
+// anticipationLight 期待效果对应的机台灯光
+// 有慢停卷轴时闪烁（每个慢停卷轴持续1秒），只有差一点中奖时全部灯常亮1秒
+func anticipationLight(anticipation *slot.Anticipation) (pattern, duration byte, ok bool) {
+	switch {
+	case anticipation.IsEmpty():
+		return 0, 0, false
+	case len(anticipation.SlowReels) > 0:
+		return hardware.LightFlash, byte(len(anticipation.SlowReels)), true
+	default:
+		return hardware.LightOn, 1, true
+	}
+}
+
+// signalAnticipation 按期待效果触发机台灯光（串口控制器可用时）
+func (s *GameService) signalAnticipation(session *GameSession, anticipation *slot.Anticipation) {
+	if s.serialController == nil {
+		return
+	}
+	pattern, duration, ok := anticipationLight(anticipation)
+	if !ok {
+		return
+	}
+	
+	// 异步控制灯光，避免阻塞游戏流程
+	go func() {
+		if err := s.serialController.LightControl(pattern, 100, duration); err != nil {
+			s.logger.Error("期待灯光控制失败",
+				zap.String("session_id", session.SessionID),
+				zap.Uint8("pattern", pattern),
+				zap.Error(err))
+		}
+	}()
+}
+
 // Settle 结算游戏
 func (s *GameService) Settle(ctx context.Context, sessionID string) error {
 	// 获取会话
@@ -246,8 +414,11 @@ func (s *GameService) Settle(ctx context.Context, sessionID string) error {
 		return fmt.Errorf("会话不存在: %w", err)
 	}
 	
-	// 执行结算
-	if err := session.Settle(ctx); err != nil {
+	// 执行结算（博倍中收分），博倍的赢取入账成功后才结束本局
+	err = session.SettleWith(ctx, func(payout int64) error {
+		return s.creditWin(ctx, session, payout, "博倍收分")
+	})
+	if err != nil {
 		return fmt.Errorf("结算失败: %w", err)
 	}
 	
@@ -259,6 +430,45 @@ func (s *GameService) Settle(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+// Gamble 博倍竞猜一次
+func (s *GameService) Gamble(ctx context.Context, sessionID string, mode slot.GambleMode, guess slot.GambleGuess) (*GambleResponse, error) {
+	// 获取会话
+	session, err := s.sessionManager.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("会话不存在: %w", err)
+	}
+	
+	// 执行竞猜
+	gamble, round, err := session.Gamble(ctx, mode, guess)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 每次竞猜单独记录（下注为竞猜前金额）
+	if err := s.sessionManager.SaveGambleRecord(ctx, session, gamble, round); err != nil {
+		s.logger.Error("保存博倍记录失败",
+			zap.String("session_id", sessionID),
+			zap.Error(err))
+	}
+	
+	s.logger.Info("博倍竞猜",
+		zap.String("session_id", sessionID),
+		zap.String("guess", string(round.Guess)),
+		zap.Bool("won", round.Won),
+		zap.Int64("stake", gamble.Stake))
+	
+	response := &GambleResponse{
+		SessionID: sessionID,
+		Round:     round,
+		Gamble:    gamble,
+		CanDouble: gamble.CanDouble(),
+		State:     string(session.GetState()),
+		TotalWin:  session.TotalWin,
+	}
+	
+	return response, nil
+}
+
 // GetSessionInfo 获取会话信息
 func (s *GameService) GetSessionInfo(ctx context.Context, sessionID string) (*SessionInfo, error) {
 	stats, err := s.sessionManager.GetSessionStats(sessionID)
@@ -296,7 +506,7 @@ func (s *GameService) EndSession(ctx context.Context, sessionID string) error {
 	// 确保游戏已结算
 	state := session.GetState()
 	if state != StateIdle && state != StateError {
-		if err := session.Settle(ctx); err != nil {
+		if err := s.Settle(ctx, sessionID); err != nil {
 			s.logger.Error("结算失败",
 				zap.String("session_id", sessionID),
 				zap.Error(err))
@@ -380,16 +590,16 @@ func (s *GameService) BatchSpin(ctx context.Context, req *BatchSpinRequest) (*Ba
 		response.SpinResults = append(response.SpinResults, spinResp.Result)
 		response.TotalSpins++
 		response.TotalBet += betAmount
-		response.TotalWin += spinResp.Result.TotalPayout
+		response.TotalWin += spinResp.Result.GetTotalPayout()
 		
 		// 检查是否需要提前停止
-		if req.AutoStop && spinResp.Result.TotalPayout > 0 {
+		if req.AutoStop && spinResp.Result.GetTotalPayout() > 0 {
 			response.StoppedEarly = true
 			response.StopReason = "中奖自动停止"
 			break
 		}
 		
-		if req.StopOnBigWin && spinResp.Result.TotalPayout >= req.BigWinAmount {
+		if req.StopOnBigWin && spinResp.Result.GetTotalPayout() >= req.BigWinAmount {
 			response.StoppedEarly = true
 			response.StopReason = fmt.Sprintf("达到大奖金额 %d", req.BigWinAmount)
 			break
@@ -458,11 +668,19 @@ func (s *GameService) GetUserStats(ctx context.Context, userID uint) (*UserGameS
 	return stats, nil
 }
 
+// SlotRegistry 获取老虎机配置注册表（各机台的引擎）
+func (s *GameService) SlotRegistry() *SlotMachineRegistry {
+	return s.sessionManager.SlotRegistry()
+}
+
 // Start 启动游戏服务
 func (s *GameService) Start(ctx context.Context) {
 	// 启动会话清理任务
 	s.sessionManager.StartCleanupTask(ctx, 5*time.Minute)
 	
+	// 启动老虎机配置热加载任务
+	s.sessionManager.StartConfigReloadTask(ctx, 30*time.Second)
+	
 	s.logger.Info("游戏服务已启动")
 }
 
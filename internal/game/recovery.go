@@ -81,6 +81,7 @@ func (rm *RecoveryManager) getRecoveryStrategy(state GameState) func(context.Con
 		StateSpinning:    rm.recoverSpinning,
 		StateCalculating: rm.recoverCalculating,
 		StateWinning:     rm.recoverWinning,
+		StateGambling:    rm.recoverGambling,
 		StateSettlement:  rm.recoverSettlement,
 		StateError:       rm.recoverError,
 	}
@@ -207,6 +208,19 @@ func (rm *RecoveryManager) recoverWinning(ctx context.Context, sm *StateMachine)
 	return sm.Trigger(ctx, "settle")
 }
 
+// recoverGambling 恢复博倍状态
+// 博倍中断时按当前金额收分，再按结算状态检查是否已结算到钱包
+func (rm *RecoveryManager) recoverGambling(ctx context.Context, sm *StateMachine) error {
+	rm.logger.Info("从博倍状态恢复，按当前金额收分",
+		zap.String("session_id", sm.sessionID),
+		zap.Int64("win_amount", sm.winAmount))
+	
+	if err := sm.Trigger(ctx, "collect"); err != nil {
+		return err
+	}
+	return rm.recoverSettlement(ctx, sm)
+}
+
 // recoverSettlement 恢复结算状态
 func (rm *RecoveryManager) recoverSettlement(ctx context.Context, sm *StateMachine) error {
 	rm.logger.Info("从结算状态恢复，检查结算完成性",
@@ -390,8 +404,8 @@ func (rm *RecoveryManager) CleanupExpiredSessions(ctx context.Context) error {
 				refundCount++
 			}
 			
-		case StateSettlement:
-			// 结算状态需要检查是否已完成结算
+		case StateGambling, StateSettlement:
+			// 结算和博倍状态需要检查是否已完成结算（博倍中按当前金额收分）
 			if stateData.WinAmount > 0 {
 				// 检查是否已有结算记录
 				var existingTransaction models.Transaction
@@ -154,6 +154,40 @@ func TestRecoveryManager_RecoverSettlement_Incomplete(t *testing.T) {
 	assert.Equal(t, "success", transaction.Status)
 }
 
+func TestRecoveryManager_RecoverGambling(t *testing.T) {
+	// 设置测试环境
+	db := setupTestDB(t)
+	logger := zap.NewNop()
+	persister := NewMemoryStatePersister()
+	userID := createTestUser(t, db)
+	
+	rm := NewRecoveryManager(logger, persister, db, 30*time.Minute)
+	
+	// 博倍中断开（当前金额已翻倍到800，尚未收分）
+	sessionID := "test-session-gamble"
+	stateData := &StateMachineData{
+		SessionID:    sessionID,
+		UserID:       userID,
+		CurrentState: StateGambling,
+		BetAmount:    100,
+		WinAmount:    800,
+		LastUpdate:   time.Now(),
+	}
+	
+	ctx := context.Background()
+	sm := NewStateMachine(sessionID, userID, logger, persister)
+	sm.LoadFromData(stateData)
+	
+	// 恢复时按当前金额收分并结算
+	err := rm.getRecoveryStrategy(StateGambling)(ctx, sm)
+	assert.NoError(t, err)
+	
+	walletRepo := repository.NewWalletRepository(db)
+	wallet, err := walletRepo.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10800), wallet.Balance)
+}
+
 func TestRecoveryManager_RecoverSettlement_AlreadyCompleted(t *testing.T) {
 	// 设置测试环境
 	db := setupTestDB(t)
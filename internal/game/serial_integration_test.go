@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/hardware"
 	"go.uber.org/zap"
 )
@@ -200,4 +201,27 @@ func TestGameService_CoinPushCalculation(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestGameService_AnticipationLight(t *testing.T) {
+	testCases := []struct {
+		name             string
+		anticipation     *slot.Anticipation
+		expectedPattern  byte
+		expectedDuration byte
+		expectedOK       bool
+	}{
+		{"无期待效果", nil, 0, 0, false},
+		{"两个卷轴慢停", &slot.Anticipation{SlowReels: []int{3, 4}}, hardware.LightFlash, 2, true},
+		{"只有差一点中奖", &slot.Anticipation{NearMisses: []slot.NearMiss{{Type: slot.NearMissTypeLine}}}, hardware.LightOn, 1, true},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, duration, ok := anticipationLight(tc.anticipation)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedPattern, pattern)
+			assert.Equal(t, tc.expectedDuration, duration)
+		})
+	}
+}
@@ -19,10 +19,15 @@ type SessionManager struct {
 	mu              sync.RWMutex
 	sessions        map[string]*GameSession
 	logger          *zap.Logger
+	db              *gorm.DB
 	persister       StatePersister
 	gameResultRepo  repository.GameResultRepository
 	walletRepo      repository.WalletRepository
-	slotEngine      slot.Engine
+	slotMachineRepo repository.SlotMachineRepository
+	slotSpinRepo    repository.SlotSpinRepository
+	statsRepo       *repository.SlotStatsRepository
+	slotEngine      slot.Engine           // 未指定机台时使用的默认引擎
+	slotRegistry    *SlotMachineRegistry
 	recoveryManager *RecoveryManager
 	sessionTimeout  time.Duration
 	maxSessions     int
@@ -32,6 +37,7 @@ type SessionManager struct {
 type GameSession struct {
 	SessionID    string
 	UserID       uint
+	MachineID    string // 机台ID（引擎从注册表按机台获取）
 	StateMachine *StateMachine
 	SlotEngine   slot.Engine
 	StartTime    time.Time
@@ -40,6 +46,9 @@ type GameSession struct {
 	TotalBet     int64           // 总投注
 	TotalWin     int64           // 总赢取
 	SpinCount    int             // 转动次数
+	GambleState  *slot.GambleState // 进行中的博倍（中奖后可博倍时创建）
+	PendingWin   int64           // 等待收分结算到钱包的赢取
+	Ante         bool            // 本局是否加注
 	mu           sync.RWMutex
 }
 
@@ -56,21 +65,36 @@ func NewSessionManager(config *SessionConfig) *SessionManager {
 	persister := NewDatabaseStatePersister(config.DB)
 	recoveryManager := NewRecoveryManager(config.Logger, persister, config.DB, config.SessionTimeout)
 	
+	// 内置配置的引擎登记到注册表，数据库中有同一机器的配置时热加载替换
+	slotRegistry := NewSlotMachineRegistry(config.DB, config.Logger)
+	slotRegistry.RegisterBuiltin()
+	slotEngine, _ := slotRegistry.Engine(slot.DefaultConfig().MachineID)
+	
 	return &SessionManager{
 		sessions:        make(map[string]*GameSession),
 		logger:          config.Logger,
+		db:              config.DB,
 		persister:       persister,
 		gameResultRepo:  repository.NewGameResultRepository(config.DB),
 		walletRepo:      repository.NewWalletRepository(config.DB),
-		slotEngine:      slot.NewEngine(slot.DefaultConfig()),
+		slotMachineRepo: repository.NewSlotMachineRepository(config.DB),
+		slotSpinRepo:    repository.NewSlotSpinRepository(config.DB),
+		statsRepo:       repository.NewSlotStatsRepository(config.DB),
+		slotEngine:      slotEngine,
+		slotRegistry:    slotRegistry,
 		recoveryManager: recoveryManager,
 		sessionTimeout:  config.SessionTimeout,
 		maxSessions:     config.MaxSessions,
 	}
 }
 
-// CreateSession 创建新会话
-func (sm *SessionManager) CreateSession(ctx context.Context, sessionID string, userID uint) (*GameSession, error) {
+// CreateSession 创建新会话，machineID 为空时使用默认机台
+func (sm *SessionManager) CreateSession(ctx context.Context, sessionID string, userID uint, machineID string) (*GameSession, error) {
+	machineID, engine, err := sm.machineEngine(machineID)
+	if err != nil {
+		return nil, err
+	}
+	
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 	
@@ -99,8 +123,9 @@ func (sm *SessionManager) CreateSession(ctx context.Context, sessionID string, u
 	session := &GameSession{
 		SessionID:    sessionID,
 		UserID:       userID,
+		MachineID:    machineID,
 		StateMachine: stateMachine,
-		SlotEngine:   sm.slotEngine,
+		SlotEngine:   engine,
 		StartTime:    time.Now(),
 		LastActivity: time.Now(),
 	}
@@ -109,11 +134,24 @@ func (sm *SessionManager) CreateSession(ctx context.Context, sessionID string, u
 	
 	sm.logger.Info("创建游戏会话",
 		zap.String("session_id", sessionID),
-		zap.Uint("user_id", userID))
+		zap.Uint("user_id", userID),
+		zap.String("machine_id", machineID))
 	
 	return session, nil
 }
 
+// machineEngine 从注册表获取机台的引擎，machineID 为空时使用默认机台
+func (sm *SessionManager) machineEngine(machineID string) (string, slot.Engine, error) {
+	if machineID == "" {
+		return sm.slotEngine.GetConfig().MachineID, sm.slotEngine, nil
+	}
+	engine, ok := sm.slotRegistry.Engine(machineID)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", repository.ErrSlotMachineNotFound, machineID)
+	}
+	return machineID, engine, nil
+}
+
 // GetSession 获取会话
 func (sm *SessionManager) GetSession(sessionID string) (*GameSession, error) {
 	sm.mu.RLock()
@@ -130,22 +168,31 @@ func (sm *SessionManager) GetSession(sessionID string) (*GameSession, error) {
 	return session, nil
 }
 
-// RecoverOrCreateSession 恢复或创建会话
-func (sm *SessionManager) RecoverOrCreateSession(ctx context.Context, sessionID string, userID uint) (*GameSession, error) {
+// RecoverOrCreateSession 恢复或创建会话，machineID 为空时沿用会话的机台（新会话使用默认机台）
+func (sm *SessionManager) RecoverOrCreateSession(ctx context.Context, sessionID string, userID uint, machineID string) (*GameSession, error) {
 	// 先尝试从内存获取
 	if session, err := sm.GetSession(sessionID); err == nil {
+		if machineID != "" && machineID != session.MachineID {
+			return nil, fmt.Errorf("会话属于其他机台: %s", session.MachineID)
+		}
 		return session, nil
 	}
 	
 	// 尝试恢复会话
 	stateMachine, err := sm.recoveryManager.RecoverSession(ctx, sessionID)
 	if err == nil {
+		machineID, engine, err := sm.machineEngine(machineID)
+		if err != nil {
+			return nil, err
+		}
+		
 		sm.mu.Lock()
 		session := &GameSession{
 			SessionID:    sessionID,
 			UserID:       userID,
+			MachineID:    machineID,
 			StateMachine: stateMachine,
-			SlotEngine:   sm.slotEngine,
+			SlotEngine:   engine,
 			StartTime:    time.Now(),
 			LastActivity: time.Now(),
 		}
@@ -160,7 +207,7 @@ func (sm *SessionManager) RecoverOrCreateSession(ctx context.Context, sessionID
 	}
 	
 	// 创建新会话
-	return sm.CreateSession(ctx, sessionID, userID)
+	return sm.CreateSession(ctx, sessionID, userID, machineID)
 }
 
 // RemoveSession 移除会话
@@ -241,6 +288,16 @@ func (sm *SessionManager) StartCleanupTask(ctx context.Context, interval time.Du
 	}()
 }
 
+// SlotRegistry 获取老虎机配置注册表
+func (sm *SessionManager) SlotRegistry() *SlotMachineRegistry {
+	return sm.slotRegistry
+}
+
+// StartConfigReloadTask 启动老虎机配置热加载任务
+func (sm *SessionManager) StartConfigReloadTask(ctx context.Context, interval time.Duration) {
+	sm.slotRegistry.StartReloadTask(ctx, interval)
+}
+
 // GetActiveSessions 获取活跃会话数
 func (sm *SessionManager) GetActiveSessions() int {
 	sm.mu.RLock()
@@ -282,11 +339,17 @@ func (gs *GameSession) UpdateActivity() {
 
 // StartGame 开始游戏
 func (gs *GameSession) StartGame(ctx context.Context, betAmount int64) error {
+	return gs.StartGameWithAnte(ctx, betAmount, false)
+}
+
+// StartGameWithAnte 开始游戏，ante 为真时本局按加注模式转动
+func (gs *GameSession) StartGameWithAnte(ctx context.Context, betAmount int64, ante bool) error {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 	
 	// 设置投注金额
 	gs.StateMachine.SetBetAmount(betAmount)
+	gs.Ante = ante
 	
 	// 触发投币事件
 	if err := gs.StateMachine.Trigger(ctx, "insert_coin"); err != nil {
@@ -313,19 +376,20 @@ func (gs *GameSession) Spin(ctx context.Context) (*slot.SpinResult, error) {
 	}
 	
 	// 执行老虎机转动
-	result, err := gs.SlotEngine.Spin(gs.UserID, gs.SessionID, gs.StateMachine.betAmount)
+	result, err := gs.SlotEngine.SpinWithAnte(gs.UserID, gs.SessionID, gs.StateMachine.betAmount, gs.Ante)
 	if err != nil {
 		return nil, fmt.Errorf("执行转动失败: %w", err)
 	}
 	gs.SpinResult = result
 	
-	// 更新统计
+	// 更新统计（免费旋转不计投注，加注按加注后的扣费计）
 	gs.SpinCount++
-	gs.TotalBet += gs.StateMachine.betAmount
-	gs.TotalWin += result.TotalPayout
+	gs.TotalBet += result.BetAmount
+	payout := result.GetTotalPayout()
+	gs.TotalWin += payout
 	
 	// 设置中奖金额
-	gs.StateMachine.SetWinAmount(result.TotalPayout)
+	gs.StateMachine.SetWinAmount(payout)
 	
 	// 触发停止转动
 	if err := gs.StateMachine.Trigger(ctx, "stop_spin"); err != nil {
@@ -333,7 +397,7 @@ func (gs *GameSession) Spin(ctx context.Context) (*slot.SpinResult, error) {
 	}
 	
 	// 根据结果触发相应事件
-	if result.TotalPayout > 0 {
+	if payout > 0 {
 		if err := gs.StateMachine.Trigger(ctx, "show_win"); err != nil {
 			return nil, fmt.Errorf("展示中奖失败: %w", err)
 		}
@@ -343,26 +407,102 @@ func (gs *GameSession) Spin(ctx context.Context) (*slot.SpinResult, error) {
 		}
 	}
 	
+	// 可以博倍时赢取暂不结算，收分后再结算到钱包
+	if result.Gamble != nil {
+		gs.GambleState = result.Gamble
+		gs.PendingWin = payout
+	}
+	
 	gs.LastActivity = time.Now()
 	return result, nil
 }
 
-// Settle 结算游戏
-func (gs *GameSession) Settle(ctx context.Context) error {
+// Gamble 博倍竞猜一次：猜中赢取翻倍，猜错赢取清零并进入结算
+func (gs *GameSession) Gamble(ctx context.Context, mode slot.GambleMode, guess slot.GambleGuess) (*slot.GambleState, *slot.GambleRound, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	
+	// 检查状态
+	if !gs.StateMachine.CanTransition("gamble") || !gs.GambleState.IsActive() {
+		return nil, nil, errors.New("当前状态不能博倍")
+	}
+	
+	gamble, round, err := gs.SlotEngine.PlayGamble(gs.SessionID, mode, guess)
+	if err != nil {
+		return nil, nil, fmt.Errorf("博倍失败: %w", err)
+	}
+	gs.GambleState = gamble
+	gs.PendingWin = gamble.Stake
+	gs.TotalWin += round.Win - round.Stake
+	
+	// 设置博倍后的金额
+	gs.StateMachine.SetWinAmount(gamble.Stake)
+	
+	if err := gs.StateMachine.Trigger(ctx, "gamble"); err != nil {
+		return nil, nil, fmt.Errorf("触发博倍失败: %w", err)
+	}
+	
+	// 猜错直接进入结算
+	if !round.Won {
+		if err := gs.StateMachine.Trigger(ctx, "gamble_lose"); err != nil {
+			return nil, nil, fmt.Errorf("处理博倍失败: %w", err)
+		}
+	}
+	
+	gs.LastActivity = time.Now()
+	return gamble, &round, nil
+}
+
+// Settle 结算游戏（收分），返回需要结算到钱包的博倍赢取
+func (gs *GameSession) Settle(ctx context.Context) (int64, error) {
+	var payout int64
+	err := gs.SettleWith(ctx, func(amount int64) error {
+		payout = amount
+		return nil
+	})
+	return payout, err
+}
+
+// SettleWith 结算游戏（收分），博倍赢取先由 pay 结算到钱包再结束本局；
+// pay 失败时保留待结算赢取和当前状态，可重新结算
+func (gs *GameSession) SettleWith(ctx context.Context, pay func(payout int64) error) error {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 	
 	// 检查状态
 	state := gs.StateMachine.GetState()
-	if state != StateWinning && state != StateSettlement {
+	if state != StateWinning && state != StateGambling && state != StateSettlement {
 		return errors.New("当前状态不能结算")
 	}
 	
-	// 如果在中奖展示状态，先触发结算
-	if state == StateWinning {
+	// 结束进行中的博倍
+	if gs.GambleState.IsActive() {
+		gamble, err := gs.SlotEngine.CollectGamble(gs.SessionID)
+		if err != nil {
+			return fmt.Errorf("博倍收分失败: %w", err)
+		}
+		gs.GambleState = gamble
+		gs.PendingWin = gamble.Stake
+	}
+	
+	// 先结算赢取，失败时不结束本局
+	if gs.PendingWin > 0 && pay != nil {
+		if err := pay(gs.PendingWin); err != nil {
+			return err
+		}
+	}
+	gs.PendingWin = 0
+	
+	// 如果在中奖展示或博倍状态，先触发结算
+	switch state {
+	case StateWinning:
 		if err := gs.StateMachine.Trigger(ctx, "settle"); err != nil {
 			return fmt.Errorf("触发结算失败: %w", err)
 		}
+	case StateGambling:
+		if err := gs.StateMachine.Trigger(ctx, "collect"); err != nil {
+			return fmt.Errorf("触发收分失败: %w", err)
+		}
 	}
 	
 	// 完成游戏
@@ -370,10 +510,59 @@ func (gs *GameSession) Settle(ctx context.Context) error {
 		return fmt.Errorf("完成游戏失败: %w", err)
 	}
 	
+	gs.GambleState = nil
 	gs.LastActivity = time.Now()
 	return nil
 }
 
+// BuyFeature 购买免费游戏，之后的转动为免费旋转直到次数用完
+func (gs *GameSession) BuyFeature(ctx context.Context, betAmount int64) (*slot.FeaturePurchase, error) {
+	return gs.BuyFeatureWith(ctx, betAmount, nil)
+}
+
+// BuyFeatureWith 购买免费游戏，先由 charge 扣费落库，成功后才开启免费游戏；
+// charge 失败时不改变会话和引擎状态
+func (gs *GameSession) BuyFeatureWith(ctx context.Context, betAmount int64, charge func(price int64) error) (*slot.FeaturePurchase, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	
+	// 只能在两局之间购买
+	if gs.StateMachine.GetState() != StateIdle {
+		return nil, errors.New("当前状态不能购买免费游戏")
+	}
+	
+	if charge != nil {
+		if err := gs.SlotEngine.CanBuyFeature(gs.SessionID, betAmount); err != nil {
+			return nil, fmt.Errorf("购买免费游戏失败: %w", err)
+		}
+		if err := charge(gs.SlotEngine.GetConfig().BuyFeature.Price(betAmount)); err != nil {
+			return nil, err
+		}
+	}
+	
+	purchase, err := gs.SlotEngine.BuyFeature(gs.UserID, gs.SessionID, betAmount)
+	if err != nil {
+		return nil, fmt.Errorf("购买免费游戏失败: %w", err)
+	}
+	gs.TotalBet += purchase.Price
+	
+	gs.LastActivity = time.Now()
+	return purchase, nil
+}
+
+// InFreeGame 引擎会话中是否有剩余免费旋转
+func (gs *GameSession) InFreeGame() bool {
+	data := gs.SlotEngine.GetSession(gs.SessionID)
+	return data != nil && data.FreeGameState.IsActive()
+}
+
+// GetGamble 获取当前博倍状态
+func (gs *GameSession) GetGamble() *slot.GambleState {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.GambleState
+}
+
 // GetState 获取当前状态
 func (gs *GameSession) GetState() GameState {
 	gs.mu.RLock()
@@ -398,10 +587,110 @@ func (sm *SessionManager) SaveGameRecord(ctx context.Context, session *GameSessi
 		UserID:    session.UserID,
 		RoundID:   fmt.Sprintf("%s-%d", session.SessionID, time.Now().Unix()),
 		BetAmount: session.StateMachine.betAmount,
-		WinAmount: session.SpinResult.TotalPayout,
+		WinAmount: session.SpinResult.GetTotalPayout(),
 		Result:    models.JSONMap(session.SpinResult.ToJSON()),
 		PlayedAt:  time.Now(),
 	}
 	
+	// 游戏结果、旋转明细和旋转统计在同一事务中保存
+	return sm.db.Transaction(func(tx *gorm.DB) error {
+		if err := sm.gameResultRepo.WithTx(tx).(repository.GameResultRepository).Create(ctx, record); err != nil {
+			return err
+		}
+		
+		// 记录旋转明细（卷轴组、种子），供审计核对认证的数学模型
+		if err := sm.saveSlotSpin(ctx, tx, session, record); err != nil {
+			return err
+		}
+		
+		// 累加旋转统计，供RTP漂移检测
+		return sm.statsRepo.Record(tx, slotSpinStat(sm.sessionEngine(session), session, record))
+	})
+}
+
+// slotSpinStat 本次旋转计入统计的数据
+// 档位为名义下注（免费旋转为触发时的下注），下注为实际扣费（免费旋转为0，加注为加注后的扣费）
+func slotSpinStat(engine slot.Engine, session *GameSession, record *models.GameRecord) *repository.SlotSpinStat {
+	result := session.SpinResult
+	stat := &repository.SlotSpinStat{
+		MachineID: engine.GetConfig().MachineID,
+		BetLevel:  session.StateMachine.betAmount,
+		BetAmount: result.BetAmount,
+		WinAmount: result.WinAmount,
+		FreeSpin:  result.IsFreeSpin,
+		PlayedAt:  record.PlayedAt,
+	}
+	if result.IsFreeSpin && result.FreeGame != nil {
+		stat.BetLevel = result.FreeGame.TriggerBet
+	}
+	if !result.IsFreeSpin {
+		stat.TheoreticalRTP = engine.TheoreticalRTP(result.ReelSetID, result.Ante)
+	}
+	if result.FreeSpins > 0 {
+		stat.Features++
+	}
+	if result.BonusGame != nil {
+		stat.Features++
+	}
+	return stat
+}
+
+// SaveGambleRecord 保存一次博倍竞猜记录
+// 竞猜不另下注，押注额只记在明细里；赢取记为本次竞猜的净输赢（猜错为负），
+// 与触发博倍的旋转记录合计即为实际派彩，避免重复计入投注和派彩
+func (sm *SessionManager) SaveGambleRecord(ctx context.Context, session *GameSession, gamble *slot.GambleState, round *slot.GambleRound) error {
+	record := &models.GameRecord{
+		UserID:    session.UserID,
+		RoundID:   fmt.Sprintf("%s-gamble-%d", gamble.ID, len(gamble.Rounds)),
+		BetAmount: 0,
+		WinAmount: round.Win - round.Stake,
+		Result:    models.JSONMap{"gamble": gamble, "round": round, "stake": round.Stake},
+		PlayedAt:  time.Now(),
+	}
 	return sm.gameResultRepo.Create(ctx, record)
+}
+
+// sessionEngine 会话所用的引擎（未指定时为默认引擎）
+func (sm *SessionManager) sessionEngine(session *GameSession) slot.Engine {
+	if session.SlotEngine != nil {
+		return session.SlotEngine
+	}
+	return sm.slotEngine
+}
+
+// saveSlotSpin 保存老虎机旋转明细
+func (sm *SessionManager) saveSlotSpin(ctx context.Context, tx *gorm.DB, session *GameSession, record *models.GameRecord) error {
+	engine := sm.sessionEngine(session)
+	
+	// 机器未在数据库登记时不记录明细
+	machine, err := sm.slotMachineRepo.WithTx(tx).(repository.SlotMachineRepository).FindByMachineID(ctx, engine.GetConfig().MachineID)
+	if errors.Is(err, repository.ErrSlotMachineNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("查询老虎机失败: %w", err)
+	}
+	
+	result := session.SpinResult
+	bonusWon := false
+	for _, feature := range result.Features {
+		if feature.Type == slot.FeatureTypeBonus {
+			bonusWon = true
+		}
+	}
+	
+	spin := &models.SlotSpin{
+		ResultID:   record.ID,
+		MachineID:  machine.ID,
+		SpinNumber: session.SpinCount,
+		ReelStops:  models.JSONMap{"reels": result.Reels},
+		WinLines:   models.JSONMap{"lines": result.WinLines},
+		BonusWon:   bonusWon,
+		FreeSpins:  result.FreeSpins,
+		Multiplier: result.Multiplier,
+		ReelSetID:  result.ReelSetID,
+		Seed:       result.Seed,
+		ConfigHash: result.ConfigHash,
+	}
+	return sm.slotSpinRepo.WithTx(tx).(repository.SlotSpinRepository).Create(ctx, spin)
 }
\ No newline at end of file
@@ -0,0 +1,220 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/repository"
+	"go.uber.org/zap"
+)
+
+func TestGameSession_GambleAndCollect(t *testing.T) {
+	config := slot.GetDefaultConfig()
+	config.Gamble = &slot.GambleConfig{MaxRounds: 3}
+	engine, err := slot.NewSlotEngine(config)
+	require.NoError(t, err)
+	engine.SetRandomGenerator(slot.NewDRBGRandomGenerator(5))
+	
+	ctx := context.Background()
+	session := &GameSession{
+		SessionID:    "gamble-session",
+		UserID:       1,
+		StateMachine: NewStateMachine("gamble-session", 1, zap.NewNop(), nil),
+		SlotEngine:   engine,
+	}
+	
+	// 转到可以博倍的中奖为止，未中奖或不能博倍时直接结算
+	var result *slot.SpinResult
+	for i := 0; i < 500; i++ {
+		require.NoError(t, session.StartGame(ctx, 100))
+		result, err = session.Spin(ctx)
+		require.NoError(t, err)
+		if result.Gamble != nil {
+			break
+		}
+		_, err = session.Settle(ctx)
+		require.NoError(t, err)
+	}
+	require.NotNil(t, result.Gamble, "no gamble offered in 500 spins")
+	assert.Equal(t, StateWinning, session.GetState())
+	assert.Equal(t, result.WinAmount, session.PendingWin)
+	
+	// 博倍一次：猜中继续博倍，猜错进入结算且赢取清零
+	gamble, round, err := session.Gamble(ctx, slot.GambleModeColor, slot.GambleGuessRed)
+	require.NoError(t, err)
+	if round.Won {
+		assert.Equal(t, StateGambling, session.GetState())
+		assert.Equal(t, result.WinAmount*2, gamble.Stake)
+	} else {
+		assert.Equal(t, StateSettlement, session.GetState())
+		assert.Equal(t, int64(0), gamble.Stake)
+		_, _, err = session.Gamble(ctx, slot.GambleModeColor, slot.GambleGuessRed)
+		assert.Error(t, err)
+	}
+	
+	// 入账失败时不结束本局，保留待结算的赢取
+	if gamble.Stake > 0 {
+		state := session.GetState()
+		err = session.SettleWith(ctx, func(int64) error { return errors.New("wallet down") })
+		require.Error(t, err)
+		assert.Equal(t, state, session.GetState())
+		assert.Equal(t, gamble.Stake, session.PendingWin)
+	}
+	
+	// 收分后回到待机，返回需要结算到钱包的金额
+	payout, err := session.Settle(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, gamble.Stake, payout)
+	assert.Equal(t, StateIdle, session.GetState())
+	assert.Nil(t, session.GetGamble())
+	assert.Nil(t, engine.GetGamble("gamble-session"))
+}
+
+func TestGameSession_BuyFeatureAndAnte(t *testing.T) {
+	engine, err := slot.NewSlotEngine(slot.GetPharaohConfig())
+	require.NoError(t, err)
+	engine.SetRandomGenerator(slot.NewDRBGRandomGenerator(11))
+	
+	ctx := context.Background()
+	session := &GameSession{
+		SessionID:    "buy-session",
+		UserID:       1,
+		StateMachine: NewStateMachine("buy-session", 1, zap.NewNop(), nil),
+		SlotEngine:   engine,
+	}
+	
+	// 扣费失败时不开启免费游戏
+	_, err = session.BuyFeatureWith(ctx, 160, func(price int64) error {
+		assert.Equal(t, int64(1600), price)
+		return errors.New("wallet down")
+	})
+	require.Error(t, err)
+	assert.False(t, session.InFreeGame())
+	assert.Equal(t, int64(0), session.TotalBet)
+	
+	// 购买后进入免费游戏，免费旋转不计投注
+	purchase, err := session.BuyFeature(ctx, 160)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1600), purchase.Price)
+	assert.True(t, session.InFreeGame())
+	
+	require.NoError(t, session.StartGameWithAnte(ctx, 160, true))
+	result, err := session.Spin(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.IsFreeSpin)
+	assert.False(t, result.Ante, "free spins are never ante spins")
+	assert.Equal(t, int64(1600), session.TotalBet)
+	
+	// 一局进行中不能购买
+	_, err = session.BuyFeature(ctx, 160)
+	assert.Error(t, err)
+	
+	// 加注旋转按加注后的扣费计入投注
+	other := &GameSession{
+		SessionID:    "ante-session",
+		UserID:       1,
+		StateMachine: NewStateMachine("ante-session", 1, zap.NewNop(), nil),
+		SlotEngine:   engine,
+	}
+	require.NoError(t, other.StartGameWithAnte(ctx, 160, true))
+	result, err = other.Spin(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Ante)
+	assert.Equal(t, int64(200), other.TotalBet)
+}
+
+func TestSessionManager_SaveSlotSpinLookupError(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	sm := NewSessionManager(&SessionConfig{Logger: zap.NewNop(), DB: db, SessionTimeout: time.Minute, MaxSessions: 10})
+	
+	session, err := sm.CreateSession(ctx, "spin-record", 1, "")
+	require.NoError(t, err)
+	session.SpinResult = &slot.SpinResult{}
+	
+	// 查询老虎机出错（表不存在）时返回错误，不能当作机器未登记
+	assert.Error(t, sm.SaveGameRecord(ctx, session))
+	
+	// 机器未登记时只保存游戏记录
+	require.NoError(t, db.AutoMigrate(&models.SlotMachine{}))
+	other, err := sm.CreateSession(ctx, "spin-record-2", 1, "")
+	require.NoError(t, err)
+	other.SpinResult = &slot.SpinResult{}
+	assert.NoError(t, sm.SaveGameRecord(ctx, other))
+}
+
+func TestSessionManager_SaveGameRecordStats(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SlotMachine{}, &models.SlotStatBucket{}))
+	ctx := context.Background()
+	sm := NewSessionManager(&SessionConfig{Logger: zap.NewNop(), DB: db, SessionTimeout: time.Minute, MaxSessions: 10})
+	
+	session, err := sm.CreateSession(ctx, "spin-stats", 1, "")
+	require.NoError(t, err)
+	require.NoError(t, session.StartGame(ctx, 100))
+	result, err := session.Spin(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sm.SaveGameRecord(ctx, session))
+	
+	// 旋转与游戏记录一起计入RTP统计
+	var bucket models.SlotStatBucket
+	require.NoError(t, db.Where("machine_id = ? AND bet_level = ?", session.SlotEngine.GetConfig().MachineID, 100).First(&bucket).Error)
+	assert.Equal(t, int64(1), bucket.Spins)
+	assert.Equal(t, result.BetAmount, bucket.TotalBet)
+	assert.Equal(t, result.WinAmount, bucket.TotalWin)
+	assert.Greater(t, bucket.ExpectedWin, 0.0)
+}
+
+func TestSessionManager_MachineEngines(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Game{}, &models.SlotMachine{}, &models.SlotConfigVersion{}))
+	ctx := context.Background()
+	sm := NewSessionManager(&SessionConfig{Logger: zap.NewNop(), DB: db, SessionTimeout: time.Minute, MaxSessions: 10})
+	
+	game := &models.Game{Name: "水果机", Type: "slot", Status: "active"}
+	require.NoError(t, db.Create(game).Error)
+	for _, machine := range []*models.SlotMachine{
+		{GameID: game.ID, MachineID: "classic_fruit", Reels: 5, Rows: 3, Paylines: 10, PayTable: models.JSONMap{"target_rtp": 0.93}, Status: "active"},
+		{GameID: game.ID + 1, MachineID: "lucky_seven", Reels: 5, Rows: 3, Paylines: 5, PayTable: models.JSONMap{"target_rtp": 0.9}, Status: "active"},
+	} {
+		require.NoError(t, db.Create(machine).Error)
+	}
+	require.NoError(t, sm.SlotRegistry().Reload(ctx))
+	
+	// 两台机器各自按数据库中的配置转动
+	spin := func(sessionID, machineID string) *slot.SpinResult {
+		session, err := sm.CreateSession(ctx, sessionID, 1, machineID)
+		require.NoError(t, err)
+		require.NoError(t, session.StartGame(ctx, 100))
+		result, err := session.Spin(ctx)
+		require.NoError(t, err)
+		return result
+	}
+	fruit := spin("fruit-session", "classic_fruit")
+	seven := spin("seven-session", "lucky_seven")
+	
+	fruitEngine, ok := sm.SlotRegistry().Engine("classic_fruit")
+	require.True(t, ok)
+	sevenEngine, ok := sm.SlotRegistry().Engine("lucky_seven")
+	require.True(t, ok)
+	assert.Equal(t, fruitEngine.ConfigHash(), fruit.ConfigHash)
+	assert.Equal(t, sevenEngine.ConfigHash(), seven.ConfigHash)
+	assert.NotEqual(t, fruit.ConfigHash, seven.ConfigHash)
+	assert.Equal(t, 10, fruitEngine.GetConfig().ActivePaylines)
+	assert.Equal(t, 5, sevenEngine.GetConfig().ActivePaylines)
+	
+	// 未指定机台时沿用会话的机台，指定其他机台或未登记的机台时拒绝
+	session, err := sm.RecoverOrCreateSession(ctx, "seven-session", 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "lucky_seven", session.MachineID)
+	_, err = sm.RecoverOrCreateSession(ctx, "seven-session", 1, "classic_fruit")
+	assert.Error(t, err)
+	_, err = sm.CreateSession(ctx, "unknown-session", 1, "no_such_machine")
+	assert.ErrorIs(t, err, repository.ErrSlotMachineNotFound)
+}
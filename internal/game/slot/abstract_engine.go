@@ -22,6 +22,7 @@ type AbstractGameResult struct {
 	Features    []AbstractFeature      `json:"features"`         // 特殊功能触发
 	Statistics  *AbstractStatistics    `json:"statistics"`       // 统计数据
 	Metadata    map[string]interface{} `json:"metadata"`         // 扩展数据
+	Anticipation *Anticipation         `json:"anticipation,omitempty"` // 期待效果（初始盘面的慢停卷轴和差一点中奖）
 }
 
 // AbstractWinLine 抽象获胜线
@@ -154,6 +155,9 @@ type AlgorithmConfig struct {
 	Algorithm      AlgorithmType `json:"algorithm"`
 	Volatility     float64       `json:"volatility"`
 	HitFrequency   float64       `json:"hit_frequency"`
+	
+	// 引擎模式（纯数学模式下不应用RTP干预）
+	Mode           EngineMode    `json:"mode"`
 }
 
 type AlgorithmType int
@@ -171,6 +175,8 @@ type AbstractFeatureConfig struct {
 	BaseValue      int64     `json:"base_value"`
 	Probability    float64   `json:"probability"`
 	Properties     map[string]interface{} `json:"properties"`
+	FreeGame       *FreeGameConfig        `json:"free_game,omitempty"` // 免费游戏配置（仅免费旋转特性）
+	BonusGame      *BonusGameConfig       `json:"bonus_game,omitempty"` // 选择奖励游戏配置（仅奖励游戏特性）
 }
 
 // AbstractSlotEngine 抽象老虎机引擎实现
@@ -186,7 +192,7 @@ type AbstractSlotEngine struct {
 
 // NewAbstractSlotEngine 创建抽象游戏引擎
 func NewAbstractSlotEngine(config *AlgorithmConfig) *AbstractSlotEngine {
-	return &AbstractSlotEngine{
+	engine := &AbstractSlotEngine{
 		config:        config,
 		rtpController: NewDynamicRTPController(config.TargetRTP),
 		randomGen:     NewCryptoRandomGenerator(),
@@ -194,6 +200,23 @@ func NewAbstractSlotEngine(config *AlgorithmConfig) *AbstractSlotEngine {
 		sessions:      make(map[string]*SessionData),
 		isRunning:     true,
 	}
+	engine.rtpController.SetRandomGenerator(engine.randomGen)
+	return engine
+}
+
+// SetRandomGenerator 注入随机数生成器（同时用于RTP控制器）
+func (e *AbstractSlotEngine) SetRandomGenerator(rng RandomGenerator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.randomGen = rng
+	e.rtpController.SetRandomGenerator(rng)
+}
+
+// GetRandomGenerator 获取当前随机数生成器
+func (e *AbstractSlotEngine) GetRandomGenerator() RandomGenerator {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.randomGen
 }
 
 // CalculateResult 核心算法 - 计算抽象结果
@@ -381,6 +404,11 @@ func (e *AbstractSlotEngine) calculateTotalWin(winLines []AbstractWinLine, featu
 }
 
 func (e *AbstractSlotEngine) applyRTPControl(totalWin, betAmount int64) int64 {
+	// 纯数学模式：赢取完全由转轴结果决定
+	if e.config.Mode.IsPureMath() {
+		return totalWin
+	}
+	
 	// RTP控制逻辑
 	currentRTP := e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
 	shouldTriggerWin := e.rtpController.ShouldTriggerWin(currentRTP, e.config.TargetRTP, betAmount)
@@ -0,0 +1,100 @@
+package slot
+
+import "math"
+
+// defaultFeatureRTPBand 未配置理论RTP范围时加注和购买免费游戏的RTP允许范围（与目标RTP的允许范围一致）
+var defaultFeatureRTPBand = RTPBand{Min: 0.8, Max: 0.99}
+
+// featureRTPBand 加注和购买免费游戏的RTP允许范围
+func featureRTPBand(config *SlotConfig) RTPBand {
+	if config.RTPBand != nil {
+		return *config.RTPBand
+	}
+	return defaultFeatureRTPBand
+}
+
+// AnteConfig 加注模式配置
+// 加注时按下注额的倍数扣费，使用分散符号更密集的卷轴组提高免费游戏触发率，派彩仍按原下注额计算
+type AnteConfig struct {
+	Multiplier float64 `json:"multiplier"`  // 加注倍数（扣费 = 下注额 × 倍数，须大于1）
+	ReelSetID  string  `json:"reel_set_id"` // 加注时使用的卷轴组
+}
+
+// Cost 加注后的实际扣费
+func (c *AnteConfig) Cost(betAmount int64) int64 {
+	if c == nil {
+		return betAmount
+	}
+	return int64(math.Round(float64(betAmount) * c.Multiplier))
+}
+
+// baseBet 由加注后的扣费还原原下注额（倍数大于1时四舍五入的误差小于0.5，还原是精确的）
+func (c *AnteConfig) baseBet(cost int64) int64 {
+	return int64(math.Round(float64(cost) / c.Multiplier))
+}
+
+// reserves 卷轴组是否为加注专用（不参与基础旋转的卷轴组切换，RTP按加注倍数折算后校验）
+func (c *AnteConfig) reserves(reelSetID string) bool {
+	return c != nil && c.ReelSetID == reelSetID
+}
+
+// baseReelSets 去掉加注专用卷轴组后的卷轴组
+func (c *AnteConfig) baseReelSets(sets []ReelSet) []ReelSet {
+	if c == nil {
+		return sets
+	}
+	base := make([]ReelSet, 0, len(sets))
+	for _, set := range sets {
+		if !c.reserves(set.ID) {
+			base = append(base, set)
+		}
+	}
+	return base
+}
+
+// reelSet 加注使用的卷轴组
+func (c *AnteConfig) reelSet(config *SlotConfig) (ReelSet, bool) {
+	for _, set := range config.ReelSets {
+		if set.ID == c.ReelSetID {
+			return set, true
+		}
+	}
+	return ReelSet{}, false
+}
+
+// validate 校验加注配置：倍数大于1、卷轴组存在且加注后的理论RTP在允许范围内
+func (c *AnteConfig) validate(config *SlotConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.Multiplier <= 1 {
+		return ErrInvalidAnte
+	}
+	if _, ok := c.reelSet(config); !ok {
+		return ErrUnknownReelSet
+	}
+	rtp, err := CalculateAnteRTP(config)
+	if err != nil {
+		return err
+	}
+	if band := featureRTPBand(config); rtp < band.Min || rtp > band.Max {
+		return ErrRTPOutOfRange
+	}
+	return nil
+}
+
+// CalculateAnteRTP 加注模式的理论RTP（加注卷轴组的理论RTP ÷ 加注倍数）
+func CalculateAnteRTP(config *SlotConfig) (float64, error) {
+	if config.Ante == nil {
+		return 0, ErrAnteUnavailable
+	}
+	set, ok := config.Ante.reelSet(config)
+	if !ok {
+		return 0, ErrUnknownReelSet
+	}
+	theory, err := CalculateReelSetRTP(config, set)
+	if err != nil {
+		return 0, err
+	}
+	return theory.TotalRTP / config.Ante.Multiplier, nil
+}
@@ -0,0 +1,108 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnteConfig_Validate(t *testing.T) {
+	config := GetPharaohConfig()
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("pharaoh preset invalid: %v", err)
+	}
+	rtp, err := CalculateAnteRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateAnteRTP failed: %v", err)
+	}
+	if rtp < 0.94 || rtp > 0.97 {
+		t.Errorf("ante RTP = %.4f, want about 95.5%%", rtp)
+	}
+
+	tests := []struct {
+		name string
+		ante *AnteConfig
+		want error
+	}{
+		{"multiplier not above 1", &AnteConfig{Multiplier: 1, ReelSetID: "ante"}, ErrInvalidAnte},
+		{"unknown reel set", &AnteConfig{Multiplier: 1.25, ReelSetID: "missing"}, ErrUnknownReelSet},
+		{"too cheap", &AnteConfig{Multiplier: 1.1, ReelSetID: "ante"}, ErrRTPOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetPharaohConfig()
+			config.Ante = tt.ante
+			if err := ValidateConfig(config); err != tt.want {
+				t.Errorf("ValidateConfig = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 配置了理论RTP范围时加注卷轴组按折算后的RTP校验，不参与基础卷轴组的范围校验
+	banded := GetPharaohConfig()
+	banded.RTPBand = &RTPBand{Min: 0.94, Max: 0.97}
+	if err := ValidateConfig(banded); err != nil {
+		t.Errorf("banded config = %v", err)
+	}
+}
+
+func TestAnteConfig_Cost(t *testing.T) {
+	ante := &AnteConfig{Multiplier: 1.25}
+	for _, bet := range []int64{16, 160, 333, 16000} {
+		cost := ante.Cost(bet)
+		if want := int64(math.Round(float64(bet) * 1.25)); cost != want {
+			t.Errorf("Cost(%d) = %d, want %d", bet, cost, want)
+		}
+		if got := ante.baseBet(cost); got != bet {
+			t.Errorf("baseBet(%d) = %d, want %d", cost, got, bet)
+		}
+	}
+
+	var disabled *AnteConfig
+	if disabled.Cost(100) != 100 {
+		t.Error("nil ante should cost the bet")
+	}
+}
+
+func TestSlotEngine_SpinWithAnte(t *testing.T) {
+	engine, err := NewSlotEngine(GetPharaohConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(9))
+	engine.EnableSeededMode()
+
+	result, err := engine.SpinWithAnte(1, "ante", 160, true)
+	if err != nil {
+		t.Fatalf("SpinWithAnte failed: %v", err)
+	}
+	if !result.Ante || result.BetAmount != 200 || result.ReelSetID != "ante" {
+		t.Errorf("ante result = ante %v bet %d reel set %q", result.Ante, result.BetAmount, result.ReelSetID)
+	}
+	if got := engine.GetStatistics().TotalBet; got != 200 {
+		t.Errorf("statistics TotalBet = %d, want 200", got)
+	}
+
+	replay, err := engine.Replay(result)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if replay.WinAmount != result.WinAmount {
+		t.Errorf("replay win = %d, want %d", replay.WinAmount, result.WinAmount)
+	}
+
+	plain, err := engine.Spin(1, "plain", 160)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if plain.Ante || plain.BetAmount != 160 || plain.ReelSetID == "ante" {
+		t.Errorf("plain result = ante %v bet %d reel set %q", plain.Ante, plain.BetAmount, plain.ReelSetID)
+	}
+
+	classic, err := NewSlotEngine(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	if _, err := classic.SpinWithAnte(1, "ante", 100, true); err != ErrAnteUnavailable {
+		t.Errorf("ante without config = %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package slot
+
+import "sort"
+
+// lineMinCount 连线中奖所需的最少连续卷轴数
+const lineMinCount = 3
+
+// NearMissType 差一点中奖的类型
+type NearMissType string
+
+const (
+	NearMissTypeFeature NearMissType = "feature" // 差一个触发符号进入免费游戏或奖励游戏
+	NearMissTypeLine    NearMissType = "line"    // 高赔付符号差一个卷轴连成中奖线
+)
+
+// NearMiss 差一点中奖的符号
+type NearMiss struct {
+	Type      NearMissType `json:"type"`
+	Symbol    Symbol       `json:"symbol,omitempty"` // 经典符号（抽象网格时为空）
+	SymbolID  int          `json:"symbol_id"`        // 符号ID（与主题包、客户端协议同一套ID，未知符号为-1）
+	Positions []Position   `json:"positions"`        // 需要高亮的位置
+	Count     int          `json:"count"`            // 已出现的数量（连线时为连续卷轴数）
+	Required  int          `json:"required"`         // 触发或中奖所需的数量
+}
+
+// Anticipation 旋转的期待效果：需要慢停的卷轴和差一点中奖的符号，供前端动画和机台灯光使用
+type Anticipation struct {
+	SlowReels  []int      `json:"slow_reels,omitempty"`  // 需要慢停的卷轴（从左到右）
+	NearMisses []NearMiss `json:"near_misses,omitempty"` // 差一点中奖的符号
+}
+
+// IsEmpty 是否没有任何期待效果
+func (a *Anticipation) IsEmpty() bool {
+	return a == nil || (len(a.SlowReels) == 0 && len(a.NearMisses) == 0)
+}
+
+// orNil 没有期待效果时返回空，结果中不输出该字段
+func (a *Anticipation) orNil() *Anticipation {
+	if a.IsEmpty() {
+		return nil
+	}
+	return a
+}
+
+// addSlowReels 合并慢停卷轴（去重并按卷轴顺序排列）
+func (a *Anticipation) addSlowReels(reels []int) {
+	for _, reel := range reels {
+		found := false
+		for _, slow := range a.SlowReels {
+			if slow == reel {
+				found = true
+				break
+			}
+		}
+		if !found {
+			a.SlowReels = append(a.SlowReels, reel)
+		}
+	}
+	sort.Ints(a.SlowReels)
+}
+
+// scanTrigger 按卷轴顺序累计触发符号（columns 为各卷轴上的触发位置）
+// 累计到差一个即可触发时，之后的卷轴全部慢停；返回慢停卷轴和全部触发位置
+func scanTrigger(columns [][]Position, required int) ([]int, []Position) {
+	var slowReels []int
+	var positions []Position
+	for reel, found := range columns {
+		if required > 1 && len(positions) >= required-1 {
+			slowReels = append(slowReels, reel)
+		}
+		positions = append(positions, found...)
+	}
+	return slowReels, positions
+}
+
+// symbolNearMiss 经典符号的差一点中奖
+func symbolNearMiss(nearMissType NearMissType, symbol Symbol, positions []Position, count, required int) NearMiss {
+	id, ok := SymbolID(symbol)
+	if !ok {
+		id = -1
+	}
+	return NearMiss{
+		Type:      nearMissType,
+		Symbol:    symbol,
+		SymbolID:  id,
+		Positions: positions,
+		Count:     count,
+		Required:  required,
+	}
+}
+
+// DetectAnticipation 检测慢停卷轴和差一点中奖
+// 免费游戏触发符号和Bonus符号按卷轴顺序累计，差一个触发时之后的卷轴慢停，最终差一个时记录；
+// 高赔付符号从第一个卷轴起连续出现且差一个卷轴中奖（本局该符号未中奖）时记录
+func (m *AdvancedPatternMatcher) DetectAnticipation(reels [][]Symbol, winLines []WinLine) *Anticipation {
+	anticipation := &Anticipation{}
+
+	triggers := []struct {
+		match    func(Symbol) bool
+		required int
+	}{
+		{m.isFreeGameTrigger, m.freeGameTriggerCount()},
+		{m.isBonus, defaultFreeGameTriggerCount},
+	}
+	for _, trigger := range triggers {
+		columns := make([][]Position, len(reels))
+		for reel, column := range reels {
+			for row, symbol := range column {
+				if trigger.match(symbol) {
+					columns[reel] = append(columns[reel], Position{Reel: reel, Row: row})
+				}
+			}
+		}
+		slowReels, positions := scanTrigger(columns, trigger.required)
+		anticipation.addSlowReels(slowReels)
+		if len(positions) > 0 && len(positions) == trigger.required-1 {
+			symbol := reels[positions[0].Reel][positions[0].Row]
+			anticipation.NearMisses = append(anticipation.NearMisses,
+				symbolNearMiss(NearMissTypeFeature, symbol, positions, len(positions), trigger.required))
+		}
+	}
+
+	won := make(map[Symbol]bool)
+	for _, line := range winLines {
+		won[line.Symbol] = true
+	}
+	for _, symbol := range m.premiumSymbols() {
+		if won[symbol] {
+			continue
+		}
+		if positions := m.partialLine(reels, symbol); len(positions) > 0 {
+			anticipation.NearMisses = append(anticipation.NearMisses,
+				symbolNearMiss(NearMissTypeLine, symbol, positions, lineMinCount-1, lineMinCount))
+		}
+	}
+
+	return anticipation.orNil()
+}
+
+// premiumSymbols 高赔付符号：最少连线数的赔率不低于最高赔率一半的派彩符号（按赔付表顺序）
+func (m *AdvancedPatternMatcher) premiumSymbols() []Symbol {
+	best := 0.0
+	for _, payTable := range m.config.PayTables {
+		if payTable.Count == lineMinCount && m.paysWays(payTable.Symbol) && payTable.Multiplier > best {
+			best = payTable.Multiplier
+		}
+	}
+	var symbols []Symbol
+	for _, payTable := range m.config.PayTables {
+		if payTable.Count == lineMinCount && m.paysWays(payTable.Symbol) && best > 0 && payTable.Multiplier*2 >= best {
+			symbols = append(symbols, payTable.Symbol)
+		}
+	}
+	return symbols
+}
+
+// partialLine 符号从第一个卷轴起恰好连续差一个卷轴中奖时的位置（全路径模式按卷轴，支付线模式按各条支付线）
+func (m *AdvancedPatternMatcher) partialLine(reels [][]Symbol, symbol Symbol) []Position {
+	if m.config.WinMode.IsWays() {
+		var positions []Position
+		hasSymbol := false
+		count := 0
+		for reel, column := range reels {
+			matched := false
+			for row, s := range column {
+				if s == symbol || m.isWild(s) {
+					matched = true
+					hasSymbol = hasSymbol || s == symbol
+					positions = append(positions, Position{Reel: reel, Row: row})
+				}
+			}
+			if !matched {
+				break
+			}
+			count++
+		}
+		if count != lineMinCount-1 || !hasSymbol {
+			return nil
+		}
+		return positions
+	}
+
+	var positions []Position
+	seen := make(map[Position]bool)
+	for _, pattern := range m.paylinePatterns {
+		symbols := m.getSymbolsOnLine(reels, pattern)
+		count := 0
+		hasSymbol := false
+		for _, s := range symbols {
+			if s != symbol && !m.isWild(s) {
+				break
+			}
+			hasSymbol = hasSymbol || s == symbol
+			count++
+		}
+		if count != lineMinCount-1 || !hasSymbol {
+			continue
+		}
+		for _, pos := range pattern[:count] {
+			if !seen[pos] {
+				seen[pos] = true
+				positions = append(positions, pos)
+			}
+		}
+	}
+	return positions
+}
+
+// GridAnticipation 计算抽象网格（grid[行][卷轴]）的慢停卷轴和差一点触发的特性
+// 免费旋转和奖励游戏特性按配置的触发符号和最少数量判断
+func GridAnticipation(grid [][]int, features map[AbstractFeatureType]*AbstractFeatureConfig) *Anticipation {
+	anticipation := &Anticipation{}
+
+	for _, featureType := range []AbstractFeatureType{AbstractFeatureTypeFreeSpin, AbstractFeatureTypeBonus} {
+		feature := features[featureType]
+		if feature == nil || len(feature.TriggerSymbols) == 0 {
+			continue
+		}
+		required := feature.MinCount
+		if required <= 0 {
+			required = defaultFreeGameTriggerCount
+		}
+
+		var columns [][]Position
+		for row, cells := range grid {
+			for reel, id := range cells {
+				for len(columns) <= reel {
+					columns = append(columns, nil)
+				}
+				for _, trigger := range feature.TriggerSymbols {
+					if id == trigger {
+						columns[reel] = append(columns[reel], Position{Reel: reel, Row: row})
+						break
+					}
+				}
+			}
+		}
+
+		slowReels, positions := scanTrigger(columns, required)
+		anticipation.addSlowReels(slowReels)
+		if len(positions) > 0 && len(positions) == required-1 {
+			first := positions[0]
+			anticipation.NearMisses = append(anticipation.NearMisses, NearMiss{
+				Type:      NearMissTypeFeature,
+				SymbolID:  grid[first.Row][first.Reel],
+				Positions: positions,
+				Count:     len(positions),
+				Required:  required,
+			})
+		}
+	}
+
+	return anticipation.orNil()
+}
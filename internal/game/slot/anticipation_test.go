@@ -0,0 +1,187 @@
+package slot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectAnticipation(t *testing.T) {
+	matcher := NewAdvancedPatternMatcher(GetDefaultConfig())
+	reels := [][]Symbol{
+		{SymbolCherry, SymbolSeven, SymbolScatter},
+		{SymbolLemon, SymbolSeven, SymbolOrange},
+		{SymbolScatter, SymbolCherry, SymbolPlum},
+		{SymbolGrape, SymbolLemon, SymbolOrange},
+		{SymbolPlum, SymbolGrape, SymbolLemon},
+	}
+
+	anticipation := matcher.DetectAnticipation(reels, matcher.FindWinningLines(reels, matcher.config))
+	if anticipation == nil {
+		t.Fatal("expected anticipation")
+	}
+	if want := []int{3, 4}; !reflect.DeepEqual(anticipation.SlowReels, want) {
+		t.Errorf("SlowReels = %v, want %v", anticipation.SlowReels, want)
+	}
+	if len(anticipation.NearMisses) != 2 {
+		t.Fatalf("NearMisses = %+v", anticipation.NearMisses)
+	}
+
+	feature := anticipation.NearMisses[0]
+	if feature.Type != NearMissTypeFeature || feature.Symbol != SymbolScatter || feature.SymbolID != SYMBOL_SCATTER ||
+		feature.Count != 2 || feature.Required != 3 || len(feature.Positions) != 2 {
+		t.Errorf("feature near miss = %+v", feature)
+	}
+
+	line := anticipation.NearMisses[1]
+	wantPositions := []Position{{Reel: 0, Row: 1}, {Reel: 1, Row: 1}}
+	if line.Type != NearMissTypeLine || line.Symbol != SymbolSeven || !reflect.DeepEqual(line.Positions, wantPositions) {
+		t.Errorf("line near miss = %+v", line)
+	}
+
+	// 该符号已中奖时不算差一点中奖
+	won := matcher.DetectAnticipation(reels, []WinLine{{Symbol: SymbolSeven}})
+	if len(won.NearMisses) != 1 || won.NearMisses[0].Type != NearMissTypeFeature {
+		t.Errorf("near misses with a seven win = %+v", won.NearMisses)
+	}
+
+	// 已触发免费游戏：后续卷轴仍慢停，但不是差一点触发
+	reels[3][0] = SymbolScatter
+	triggered := matcher.DetectAnticipation(reels, []WinLine{{Symbol: SymbolSeven}})
+	if !reflect.DeepEqual(triggered.SlowReels, []int{3, 4}) || len(triggered.NearMisses) != 0 {
+		t.Errorf("triggered anticipation = %+v", triggered)
+	}
+
+	quiet := [][]Symbol{
+		{SymbolCherry, SymbolLemon, SymbolOrange},
+		{SymbolLemon, SymbolOrange, SymbolPlum},
+		{SymbolOrange, SymbolPlum, SymbolGrape},
+		{SymbolPlum, SymbolGrape, SymbolCherry},
+		{SymbolGrape, SymbolCherry, SymbolLemon},
+	}
+	if got := matcher.DetectAnticipation(quiet, nil); got != nil {
+		t.Errorf("quiet reels anticipation = %+v", got)
+	}
+}
+
+func TestDetectAnticipation_Ways(t *testing.T) {
+	config := GetDefaultConfig()
+	config.WinMode = WinModeWays
+	matcher := NewWaysPatternMatcher(config)
+	reels := [][]Symbol{
+		{SymbolCherry, SymbolBar, SymbolLemon},
+		{SymbolWild, SymbolOrange, SymbolPlum},
+		{SymbolGrape, SymbolLemon, SymbolOrange},
+		{SymbolPlum, SymbolGrape, SymbolCherry},
+		{SymbolGrape, SymbolCherry, SymbolLemon},
+	}
+
+	anticipation := matcher.DetectAnticipation(reels, nil)
+	if anticipation == nil || len(anticipation.NearMisses) != 1 {
+		t.Fatalf("anticipation = %+v", anticipation)
+	}
+	line := anticipation.NearMisses[0]
+	wantPositions := []Position{{Reel: 0, Row: 1}, {Reel: 1, Row: 0}}
+	if line.Symbol != SymbolBar || line.Count != 2 || !reflect.DeepEqual(line.Positions, wantPositions) {
+		t.Errorf("ways near miss = %+v", line)
+	}
+}
+
+func TestGridAnticipation(t *testing.T) {
+	features := map[AbstractFeatureType]*AbstractFeatureConfig{
+		AbstractFeatureTypeFreeSpin: {TriggerSymbols: []int{SYMBOL_SCATTER}, MinCount: 3},
+	}
+	grid := [][]int{
+		{SYMBOL_SCATTER, 1, 2, 3, 4},
+		{0, 1, 2, 3, 4},
+		{0, SYMBOL_SCATTER, 2, 3, 4},
+	}
+
+	anticipation := GridAnticipation(grid, features)
+	if anticipation == nil {
+		t.Fatal("expected anticipation")
+	}
+	if want := []int{2, 3, 4}; !reflect.DeepEqual(anticipation.SlowReels, want) {
+		t.Errorf("SlowReels = %v, want %v", anticipation.SlowReels, want)
+	}
+	wantPositions := []Position{{Reel: 0, Row: 0}, {Reel: 1, Row: 2}}
+	if len(anticipation.NearMisses) != 1 || anticipation.NearMisses[0].SymbolID != SYMBOL_SCATTER ||
+		!reflect.DeepEqual(anticipation.NearMisses[0].Positions, wantPositions) {
+		t.Errorf("NearMisses = %+v", anticipation.NearMisses)
+	}
+
+	if got := GridAnticipation(grid, nil); got != nil {
+		t.Errorf("anticipation without features = %+v", got)
+	}
+}
+
+func TestSlotEngine_SpinAnticipation(t *testing.T) {
+	engine, err := NewSlotEngine(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(17))
+	engine.EnableSeededMode()
+
+	found := false
+	for i := 0; i < 300 && !found; i++ {
+		result, err := engine.Spin(1, "anticipation", 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if engine.GetGamble("anticipation") != nil {
+			engine.CollectGamble("anticipation")
+		}
+		want := engine.patternMatcher.DetectAnticipation(result.Reels, result.WinLines)
+		if !reflect.DeepEqual(result.Anticipation, want) {
+			t.Fatalf("spin %d anticipation = %+v, want %+v", i, result.Anticipation, want)
+		}
+		if result.Anticipation == nil {
+			continue
+		}
+		found = true
+
+		replayed, err := engine.Replay(result)
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		if !reflect.DeepEqual(replayed.Anticipation, result.Anticipation) {
+			t.Errorf("replayed anticipation = %+v, want %+v", replayed.Anticipation, result.Anticipation)
+		}
+	}
+	if !found {
+		t.Fatal("no anticipation in 300 spins")
+	}
+}
+
+func TestThemeRenderer_AnticipationAnimations(t *testing.T) {
+	renderer := NewDefaultThemeRenderer()
+	abstract := &AbstractGameResult{
+		ReelResults: [][]int{{0, 1, 2}},
+		Anticipation: &Anticipation{
+			SlowReels: []int{3, 4},
+			NearMisses: []NearMiss{
+				{Type: NearMissTypeFeature, SymbolID: SYMBOL_SCATTER, Positions: []Position{{Reel: 0, Row: 2}}, Count: 2, Required: 3},
+			},
+		},
+	}
+
+	themed, err := renderer.RenderResult(abstract, "classic")
+	if err != nil {
+		t.Fatalf("RenderResult failed: %v", err)
+	}
+	animations := themed.AnticipationAnimations
+	if len(animations) != 3 {
+		t.Fatalf("AnticipationAnimations = %+v", animations)
+	}
+	if animations[1].Type != AnimationTypeAnticipation || animations[1].Properties["reel"] != 4 || animations[1].Properties["order"] != 1 {
+		t.Errorf("slow reel animation = %+v", animations[1])
+	}
+	if animations[2].Type != AnimationTypeNearMiss || !reflect.DeepEqual(animations[2].Target, abstract.Anticipation.NearMisses[0].Positions) {
+		t.Errorf("near miss animation = %+v", animations[2])
+	}
+
+	abstract.Anticipation = nil
+	if themed, _ := renderer.RenderResult(abstract, "classic"); len(themed.AnticipationAnimations) != 0 {
+		t.Errorf("animations without anticipation = %+v", themed.AnticipationAnimations)
+	}
+}
@@ -0,0 +1,315 @@
+package slot
+
+import "math"
+
+// 默认选择奖励游戏参数
+const (
+	defaultBonusBoardSize = 12
+	defaultBonusMaxPicks  = 5
+)
+
+// BonusTileType 选择奖励游戏的格子类型
+type BonusTileType string
+
+const (
+	BonusTileCredit  BonusTileType = "credit"  // 奖金：按倍率×下注额派彩
+	BonusTileCollect BonusTileType = "collect" // 全收：揭开剩余格子并收取其中全部奖金后结束
+	BonusTileEnd     BonusTileType = "end"     // 结束：保留已得奖金并结束
+)
+
+// BonusPrize 奖品表条目
+type BonusPrize struct {
+	Type       BonusTileType `json:"type"`       // 格子类型
+	Multiplier float64       `json:"multiplier"` // 奖金倍率（仅奖金格）
+	Weight     int           `json:"weight"`     // 抽取权重
+}
+
+// BonusGameConfig 选择奖励游戏配置
+// 挂在 FeatureConfig/AbstractFeatureConfig 的奖励游戏功能上，触发后生成隐藏奖品的格子供玩家选择
+type BonusGameConfig struct {
+	BoardSize int          `json:"board_size"` // 格子数量
+	MaxPicks  int          `json:"max_picks"`  // 最多选择次数（0为直到翻完或遇到结束格）
+	Prizes    []BonusPrize `json:"prizes"`     // 奖品表（每个格子按权重独立抽取）
+}
+
+// DefaultBonusGameConfig 默认选择奖励游戏配置（期望约12倍下注额）
+func DefaultBonusGameConfig() *BonusGameConfig {
+	return &BonusGameConfig{
+		BoardSize: defaultBonusBoardSize,
+		MaxPicks:  defaultBonusMaxPicks,
+		Prizes: []BonusPrize{
+			{Type: BonusTileCredit, Multiplier: 1, Weight: 30},
+			{Type: BonusTileCredit, Multiplier: 2, Weight: 25},
+			{Type: BonusTileCredit, Multiplier: 5, Weight: 15},
+			{Type: BonusTileCredit, Multiplier: 10, Weight: 6},
+			{Type: BonusTileCredit, Multiplier: 25, Weight: 2},
+			{Type: BonusTileCollect, Weight: 5},
+			{Type: BonusTileEnd, Weight: 18},
+		},
+	}
+}
+
+// orDefault 未配置时使用默认配置
+func (c *BonusGameConfig) orDefault() *BonusGameConfig {
+	if c == nil || len(c.Prizes) == 0 {
+		return DefaultBonusGameConfig()
+	}
+	return c
+}
+
+// picksLimit 实际最多选择次数
+func (c *BonusGameConfig) picksLimit() int {
+	if c.MaxPicks <= 0 || c.MaxPicks > c.BoardSize {
+		return c.BoardSize
+	}
+	return c.MaxPicks
+}
+
+// drawPrize 按权重抽取一个奖品
+func (c *BonusGameConfig) drawPrize(rng RandomGenerator) BonusPrize {
+	total := 0
+	for _, prize := range c.Prizes {
+		total += prize.Weight
+	}
+	value := rng.NextInt(0, total)
+	for _, prize := range c.Prizes {
+		if value < prize.Weight {
+			return prize
+		}
+		value -= prize.Weight
+	}
+	return c.Prizes[len(c.Prizes)-1]
+}
+
+// validate 校验选择奖励游戏配置
+func (c *BonusGameConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.BoardSize <= 0 || c.MaxPicks < 0 || len(c.Prizes) == 0 {
+		return ErrInvalidBonusGame
+	}
+	total := 0
+	for _, prize := range c.Prizes {
+		if prize.Weight < 0 || prize.Multiplier < 0 {
+			return ErrInvalidBonusGame
+		}
+		switch prize.Type {
+		case BonusTileCredit, BonusTileCollect, BonusTileEnd:
+		default:
+			return ErrInvalidBonusGame
+		}
+		total += prize.Weight
+	}
+	if total <= 0 {
+		return ErrInvalidBonusGame
+	}
+	return nil
+}
+
+// ExpectedMultiplier 奖励游戏的期望赢取（下注额倍数）
+// 格子独立同分布，选择顺序不影响期望：设奖金格概率p、全收格概率q、结束格概率r，
+// 单格期望奖金 m（非奖金格计0），奖金格条件期望 μ=m/p，最多选择K次，则
+// E = Σ_{j=1..K} p^(j-1)·[r·(j-1)μ + q·((j-1)μ + (N-j)m)] + p^K·K·μ
+func (c *BonusGameConfig) ExpectedMultiplier() float64 {
+	c = c.orDefault()
+	total := 0.0
+	for _, prize := range c.Prizes {
+		total += float64(prize.Weight)
+	}
+	if total <= 0 {
+		return 0
+	}
+
+	var pCredit, pCollect, pEnd, m float64
+	for _, prize := range c.Prizes {
+		p := float64(prize.Weight) / total
+		switch prize.Type {
+		case BonusTileCredit:
+			pCredit += p
+			m += p * prize.Multiplier
+		case BonusTileCollect:
+			pCollect += p
+		case BonusTileEnd:
+			pEnd += p
+		}
+	}
+	if pCredit == 0 {
+		return 0
+	}
+	mu := m / pCredit
+
+	n, k := c.BoardSize, c.picksLimit()
+	expected := 0.0
+	for j := 1; j <= k; j++ {
+		reach := math.Pow(pCredit, float64(j-1))
+		collected := float64(j-1) * mu
+		expected += reach * (pEnd*collected + pCollect*(collected+float64(n-j)*m))
+	}
+	expected += math.Pow(pCredit, float64(k)) * float64(k) * mu
+	return expected
+}
+
+// BonusTile 奖励游戏格子
+type BonusTile struct {
+	Index      int           `json:"index"`                // 格子序号
+	Picked     bool          `json:"picked"`               // 是否被玩家选中
+	Revealed   bool          `json:"revealed"`             // 是否已揭开
+	Type       BonusTileType `json:"type,omitempty"`       // 格子类型（未揭开时对客户端隐藏）
+	Multiplier float64       `json:"multiplier,omitempty"` // 奖金倍率
+	Win        int64         `json:"win"`                  // 该格计入的赢取
+}
+
+// BonusGamePhase 奖励游戏阶段
+type BonusGamePhase string
+
+const (
+	BonusGamePhaseActive    BonusGamePhase = "active"    // 等待选择
+	BonusGamePhaseCompleted BonusGamePhase = "completed" // 已结束
+)
+
+// BonusGameState 选择奖励游戏状态
+// 触发时一次性生成全部格子，之后只根据玩家的选择推进，可序列化保存并在断线后恢复
+type BonusGameState struct {
+	ID         string         `json:"id"`          // 奖励游戏ID（触发旋转的结果ID）
+	Phase      BonusGamePhase `json:"phase"`       // 阶段
+	BetAmount  int64          `json:"bet_amount"`  // 触发时的下注额
+	Board      []BonusTile    `json:"board"`       // 格子
+	Picks      int            `json:"picks"`       // 已选择次数
+	MaxPicks   int            `json:"max_picks"`   // 最多选择次数（0为不限）
+	TotalWin   int64          `json:"total_win"`   // 累计赢取
+	SpinWin    int64          `json:"spin_win"`    // 触发旋转所在局的赢取（与奖励游戏合计受最高赢取限制）
+	AutoPlayed bool           `json:"auto_played"` // 是否由引擎自动完成
+}
+
+// NewBonusGame 使用给定随机源生成奖励游戏格子
+func NewBonusGame(config *BonusGameConfig, rng RandomGenerator, betAmount int64) *BonusGameState {
+	config = config.orDefault()
+	state := &BonusGameState{
+		Phase:     BonusGamePhaseActive,
+		BetAmount: betAmount,
+		Board:     make([]BonusTile, config.BoardSize),
+		MaxPicks:  config.picksLimit(),
+	}
+	for i := range state.Board {
+		prize := config.drawPrize(rng)
+		state.Board[i] = BonusTile{Index: i, Type: prize.Type, Multiplier: prize.Multiplier}
+	}
+	return state
+}
+
+// IsActive 是否等待玩家选择
+func (s *BonusGameState) IsActive() bool {
+	return s != nil && s.Phase == BonusGamePhaseActive
+}
+
+// Pick 选择一个格子，返回揭开后的格子
+func (s *BonusGameState) Pick(index int) (BonusTile, error) {
+	if !s.IsActive() {
+		return BonusTile{}, ErrNoActiveBonus
+	}
+	if index < 0 || index >= len(s.Board) || s.Board[index].Revealed {
+		return BonusTile{}, ErrInvalidBonusPick
+	}
+
+	tile := &s.Board[index]
+	tile.Picked = true
+	tile.Revealed = true
+	s.Picks++
+
+	switch tile.Type {
+	case BonusTileCredit:
+		tile.Win = s.creditWin(tile)
+		s.TotalWin += tile.Win
+		if (s.MaxPicks > 0 && s.Picks >= s.MaxPicks) || s.unrevealed() == 0 {
+			s.complete()
+		}
+	case BonusTileCollect:
+		for i := range s.Board {
+			other := &s.Board[i]
+			if !other.Revealed && other.Type == BonusTileCredit {
+				other.Win = s.creditWin(other)
+				s.TotalWin += other.Win
+			}
+		}
+		s.complete()
+	default:
+		s.complete()
+	}
+	return *tile, nil
+}
+
+// ApplyMaxWin 将累计赢取限制在最高赢取内（与触发旋转所在局的赢取合计），达到上限时结束奖励游戏
+func (s *BonusGameState) ApplyMaxWin(limit *MaxWinConfig) bool {
+	var reached bool
+	s.TotalWin, reached = limit.ApplyRound(s.SpinWin, s.TotalWin, s.BetAmount)
+	if reached && s.IsActive() {
+		s.complete()
+	}
+	return reached
+}
+
+// AutoPlay 按格子顺序自动选择直到结束（非交互模式及模拟使用）
+func (s *BonusGameState) AutoPlay() int64 {
+	s.AutoPlayed = true
+	for i := 0; s.IsActive() && i < len(s.Board); i++ {
+		if !s.Board[i].Revealed {
+			s.Pick(i)
+		}
+	}
+	return s.TotalWin
+}
+
+// View 返回可发送给客户端的副本（进行中时隐藏未揭开格子的内容）
+func (s *BonusGameState) View() *BonusGameState {
+	if s == nil {
+		return nil
+	}
+	view := *s
+	view.Board = make([]BonusTile, len(s.Board))
+	copy(view.Board, s.Board)
+	if s.IsActive() {
+		for i := range view.Board {
+			if !view.Board[i].Revealed {
+				view.Board[i] = BonusTile{Index: i}
+			}
+		}
+	}
+	return &view
+}
+
+// creditWin 奖金格的赢取
+func (s *BonusGameState) creditWin(tile *BonusTile) int64 {
+	return int64(float64(s.BetAmount) * tile.Multiplier)
+}
+
+// unrevealed 未揭开的格子数量
+func (s *BonusGameState) unrevealed() int {
+	count := 0
+	for _, tile := range s.Board {
+		if !tile.Revealed {
+			count++
+		}
+	}
+	return count
+}
+
+// complete 结束奖励游戏并揭开全部格子
+func (s *BonusGameState) complete() {
+	s.Phase = BonusGamePhaseCompleted
+	for i := range s.Board {
+		s.Board[i].Revealed = true
+	}
+}
+
+// BonusTriggered 网格中的触发符号是否达到奖励游戏触发数量
+func (f *AbstractFeatureConfig) BonusTriggered(grid [][]int) bool {
+	if f == nil {
+		return false
+	}
+	minCount := f.MinCount
+	if minCount <= 0 {
+		minCount = defaultFreeGameTriggerCount
+	}
+	return CountGridSymbols(grid, f.TriggerSymbols) >= minCount
+}
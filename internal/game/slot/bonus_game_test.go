@@ -0,0 +1,345 @@
+package slot
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// bonusTestBoard 构造指定格子内容的进行中奖励游戏
+func bonusTestBoard(maxPicks int, tiles ...BonusTile) *BonusGameState {
+	for i := range tiles {
+		tiles[i].Index = i
+	}
+	return &BonusGameState{Phase: BonusGamePhaseActive, BetAmount: 100, Board: tiles, MaxPicks: maxPicks}
+}
+
+// bonusTestConfig 提高Bonus符号权重的纯数学配置（便于触发奖励游戏）
+func bonusTestConfig() *SlotConfig {
+	config := GetDefaultConfig()
+	config.Mode = EngineModePureMath
+	for i := range config.ReelStrips {
+		strip := &config.ReelStrips[i]
+		strip.Weights = append([]int(nil), strip.Weights...)
+		for j, symbol := range strip.Symbols {
+			if symbol == SymbolBonus {
+				strip.Weights[j] *= 10
+			}
+		}
+	}
+	return config
+}
+
+func credit(multiplier float64) BonusTile {
+	return BonusTile{Type: BonusTileCredit, Multiplier: multiplier}
+}
+
+func TestBonusGameState_Pick(t *testing.T) {
+	collect := BonusTile{Type: BonusTileCollect}
+	end := BonusTile{Type: BonusTileEnd}
+
+	t.Run("奖金格累计，达到选择次数结束", func(t *testing.T) {
+		state := bonusTestBoard(2, credit(2), credit(5), credit(10))
+		if _, err := state.Pick(1); err != nil {
+			t.Fatalf("Pick failed: %v", err)
+		}
+		if _, err := state.Pick(1); err != ErrInvalidBonusPick {
+			t.Errorf("repeat Pick = %v, want ErrInvalidBonusPick", err)
+		}
+		if _, err := state.Pick(3); err != ErrInvalidBonusPick {
+			t.Errorf("out of range Pick = %v, want ErrInvalidBonusPick", err)
+		}
+		tile, err := state.Pick(0)
+		if err != nil || tile.Win != 200 {
+			t.Fatalf("Pick = %+v, %v, want win 200", tile, err)
+		}
+		if state.IsActive() || state.TotalWin != 700 {
+			t.Errorf("state = %s total %d, want completed 700", state.Phase, state.TotalWin)
+		}
+		if !state.Board[2].Revealed || state.Board[2].Picked || state.Board[2].Win != 0 {
+			t.Errorf("unpicked tile = %+v, want revealed without win", state.Board[2])
+		}
+		if _, err := state.Pick(2); err != ErrNoActiveBonus {
+			t.Errorf("Pick after completion = %v, want ErrNoActiveBonus", err)
+		}
+	})
+
+	t.Run("全收格收取剩余奖金", func(t *testing.T) {
+		state := bonusTestBoard(0, credit(2), collect, credit(5), end, credit(1))
+		state.Pick(0)
+		state.Pick(1)
+		if state.IsActive() || state.TotalWin != 800 {
+			t.Errorf("state = %s total %d, want completed 800", state.Phase, state.TotalWin)
+		}
+		if state.Board[2].Win != 500 || state.Board[4].Win != 100 || state.Board[3].Win != 0 {
+			t.Errorf("collected board = %+v", state.Board)
+		}
+	})
+
+	t.Run("结束格保留已得奖金", func(t *testing.T) {
+		state := bonusTestBoard(0, credit(2), end, credit(5))
+		state.Pick(2)
+		state.Pick(1)
+		if state.IsActive() || state.TotalWin != 500 || state.Picks != 2 {
+			t.Errorf("state = %s total %d picks %d, want completed 500 after 2 picks", state.Phase, state.TotalWin, state.Picks)
+		}
+	})
+
+	t.Run("进行中隐藏未揭开格子", func(t *testing.T) {
+		state := bonusTestBoard(0, credit(2), end, credit(5))
+		state.Pick(0)
+		view := state.View()
+		if view.Board[0].Type != BonusTileCredit || view.Board[1].Type != "" || view.Board[2].Multiplier != 0 {
+			t.Errorf("view board = %+v", view.Board)
+		}
+		if state.Board[2].Multiplier != 5 {
+			t.Error("View must not modify the state")
+		}
+	})
+}
+
+func TestBonusGameConfig_ExpectedMultiplier(t *testing.T) {
+	configs := map[string]*BonusGameConfig{
+		"默认": DefaultBonusGameConfig(),
+		"不限次数": {
+			BoardSize: 6,
+			Prizes: []BonusPrize{
+				{Type: BonusTileCredit, Multiplier: 3, Weight: 5},
+				{Type: BonusTileCredit, Multiplier: 8, Weight: 1},
+				{Type: BonusTileCollect, Weight: 1},
+				{Type: BonusTileEnd, Weight: 2},
+			},
+		},
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			expected := config.ExpectedMultiplier()
+			rng := NewDRBGRandomGenerator(21)
+
+			const rounds = 100000
+			var sum, sumSq float64
+			for i := 0; i < rounds; i++ {
+				x := float64(NewBonusGame(config, rng, 100).AutoPlay()) / 100
+				sum += x
+				sumSq += x * x
+			}
+			mean := sum / rounds
+			stdErr := math.Sqrt((sumSq/rounds - mean*mean) / rounds)
+			if math.Abs(mean-expected) > 5*stdErr {
+				t.Errorf("simulated bonus %.4f, expected %.4f (stderr %.4f)", mean, expected, stdErr)
+			}
+		})
+	}
+
+	var defaults *BonusGameConfig
+	if got, want := defaults.ExpectedMultiplier(), DefaultBonusGameConfig().ExpectedMultiplier(); got != want || got < 10 || got > 14 {
+		t.Errorf("nil ExpectedMultiplier = %v, want default %v (about 12)", got, want)
+	}
+}
+
+func TestSlotEngine_InteractiveBonus(t *testing.T) {
+	config := bonusTestConfig()
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(3))
+	engine.EnableSeededMode()
+	engine.EnableInteractiveBonus()
+
+	userID, sessionID := uint(9), "bonus"
+	var trigger *SpinResult
+	for i := 0; i < 5000 && trigger == nil; i++ {
+		result, err := engine.Spin(userID, sessionID, 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if result.BonusGame != nil {
+			trigger = result
+		}
+	}
+	if trigger == nil {
+		t.Fatal("bonus game not triggered")
+	}
+	if !trigger.BonusGame.IsActive() || trigger.BonusGame.ID != trigger.ID || trigger.BonusGame.Board[0].Type != "" {
+		t.Fatalf("trigger bonus = %+v, want masked active board", trigger.BonusGame)
+	}
+	if _, err := engine.Spin(userID, sessionID, 100); err != ErrBonusInProgress {
+		t.Errorf("Spin during bonus = %v, want ErrBonusInProgress", err)
+	}
+
+	// 保存后在新引擎中恢复并完成选择
+	saved, err := json.Marshal(engine.GetSession(sessionID).BonusGame)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	restored, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	var state BonusGameState
+	if err := json.Unmarshal(saved, &state); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	restored.RestoreBonusGame(userID, sessionID, &state)
+	if restored.GetBonusGame(sessionID) == nil {
+		t.Fatal("restored engine has no active bonus")
+	}
+
+	totalWin := restored.GetStatistics().TotalWin
+	var finished *BonusGameState
+	for i := len(state.Board) - 1; i >= 0 && finished == nil; i-- {
+		view, err := restored.PickBonus(sessionID, i)
+		if err != nil {
+			t.Fatalf("PickBonus(%d) failed: %v", i, err)
+		}
+		if !view.IsActive() {
+			finished = view
+		}
+	}
+	if finished == nil {
+		t.Fatal("bonus game did not finish")
+	}
+	if got := restored.GetStatistics().TotalWin - totalWin; got != finished.TotalWin {
+		t.Errorf("statistics win = %d, want bonus win %d", got, finished.TotalWin)
+	}
+	if _, err := restored.PickBonus(sessionID, 0); err != ErrNoActiveBonus {
+		t.Errorf("PickBonus after completion = %v, want ErrNoActiveBonus", err)
+	}
+	if _, err := restored.Spin(userID, sessionID, 100); err != nil {
+		t.Errorf("Spin after bonus failed: %v", err)
+	}
+
+	// 重放得到相同的格子内容
+	replayed, err := engine.Replay(trigger)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	for i, tile := range replayed.BonusGame.Board {
+		if tile.Type != finished.Board[i].Type || tile.Multiplier != finished.Board[i].Multiplier {
+			t.Fatalf("replayed tile %d = %+v, want %+v", i, tile, finished.Board[i])
+		}
+	}
+}
+
+func TestSlotEngine_AutoBonus(t *testing.T) {
+	config := bonusTestConfig()
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(4))
+	engine.EnableSeededMode()
+
+	for i := 0; i < 5000; i++ {
+		result, err := engine.Spin(1, "auto", 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if result.BonusGame == nil || result.IsFreeSpin {
+			continue
+		}
+		if result.BonusGame.IsActive() || !result.BonusGame.AutoPlayed {
+			t.Fatalf("auto bonus = %+v, want completed", result.BonusGame)
+		}
+		if result.WinAmount < result.BonusGame.TotalWin {
+			t.Errorf("WinAmount %d should include bonus win %d", result.WinAmount, result.BonusGame.TotalWin)
+		}
+		replayed, err := engine.Replay(result)
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		if replayed.WinAmount != result.WinAmount || replayed.BonusGame.TotalWin != result.BonusGame.TotalWin {
+			t.Errorf("replayed win = %d (bonus %d), want %d (bonus %d)",
+				replayed.WinAmount, replayed.BonusGame.TotalWin, result.WinAmount, result.BonusGame.TotalWin)
+		}
+		return
+	}
+	t.Fatal("bonus game not triggered")
+}
+
+func TestCalculateTheoreticalRTP_Bonus(t *testing.T) {
+	base, err := CalculateTheoreticalRTP(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if base.BonusTriggerRate <= 0 {
+		t.Fatal("default config should trigger the bonus game")
+	}
+	if want := base.BonusTriggerRate * DefaultBonusGameConfig().ExpectedMultiplier(); math.Abs(base.BonusRTP-want) > 1e-12 {
+		t.Errorf("BonusRTP = %v, want %v", base.BonusRTP, want)
+	}
+
+	doubled := DefaultBonusGameConfig()
+	for i := range doubled.Prizes {
+		doubled.Prizes[i].Multiplier *= 2
+	}
+	config := GetDefaultConfig()
+	for i := range config.Features {
+		if config.Features[i].Type == FeatureTypeBonus {
+			config.Features[i].BonusGame = doubled
+		}
+	}
+	theory, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if math.Abs(theory.BonusRTP-2*base.BonusRTP) > 1e-12 || math.Abs(theory.BonusExpectedWin-2*base.BonusExpectedWin) > 1e-12 {
+		t.Errorf("doubled BonusRTP = %v, want %v", theory.BonusRTP, 2*base.BonusRTP)
+	}
+}
+
+func TestRunSimulation_BonusContribution(t *testing.T) {
+	config := bonusTestConfig()
+	opts := &SimulationOptions{Engine: "slot", Spins: 20000, Workers: 2, Seed: 13, BetAmount: 100}
+	report, err := RunSimulation(context.Background(), opts, NewSlotSimulationFactory(config, opts.BetAmount))
+	if err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+
+	for _, stat := range report.FeatureTriggers {
+		if stat.Feature != string(FeatureTypeBonus) {
+			continue
+		}
+		if stat.Win <= 0 || stat.RTPContribution <= 0 || stat.RTPContribution > report.RTP {
+			t.Errorf("bonus stat = %+v, report RTP %v", stat, report.RTP)
+		}
+		if want := float64(stat.Win) / float64(report.TotalBet); math.Abs(stat.RTPContribution-want) > 1e-12 {
+			t.Errorf("RTPContribution = %v, want %v", stat.RTPContribution, want)
+		}
+		return
+	}
+	t.Error("report has no bonus feature stat")
+}
+
+func TestValidateConfig_BonusGame(t *testing.T) {
+	tests := []struct {
+		name     string
+		bonus    *BonusGameConfig
+		expected error
+	}{
+		{"未配置", nil, nil},
+		{"默认", DefaultBonusGameConfig(), nil},
+		{"无格子", &BonusGameConfig{Prizes: DefaultBonusGameConfig().Prizes}, ErrInvalidBonusGame},
+		{"无奖品", &BonusGameConfig{BoardSize: 9}, ErrInvalidBonusGame},
+		{"未知格子类型", &BonusGameConfig{BoardSize: 9, Prizes: []BonusPrize{{Type: "jackpot", Weight: 1}}}, ErrInvalidBonusGame},
+		{"权重为零", &BonusGameConfig{BoardSize: 9, Prizes: []BonusPrize{{Type: BonusTileEnd}}}, ErrInvalidBonusGame},
+		{"负倍率", &BonusGameConfig{BoardSize: 9, Prizes: []BonusPrize{{Type: BonusTileCredit, Multiplier: -1, Weight: 1}}}, ErrInvalidBonusGame},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			for i := range config.Features {
+				if config.Features[i].Type == FeatureTypeBonus {
+					config.Features[i].BonusGame = tt.bonus
+				}
+			}
+			if err := ValidateConfig(config); err != tt.expected {
+				t.Errorf("ValidateConfig() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package slot
+
+import (
+	"math"
+	"time"
+)
+
+// BuyFeatureConfig 购买免费游戏配置
+// 按下注额的倍数付费后立即进入免费游戏，免费旋转按购买时的下注额派彩
+type BuyFeatureConfig struct {
+	PriceMultiplier float64 `json:"price_multiplier"` // 价格为下注额的倍数
+	FreeSpins       int     `json:"free_spins"`       // 获得的免费次数（缺省为最少触发数量对应的次数）
+}
+
+// Price 购买价格
+func (c *BuyFeatureConfig) Price(betAmount int64) int64 {
+	return int64(math.Round(float64(betAmount) * c.PriceMultiplier))
+}
+
+// Spins 购买获得的免费次数
+func (c *BuyFeatureConfig) Spins(config *SlotConfig) int {
+	if c.FreeSpins > 0 {
+		return c.FreeSpins
+	}
+	return config.freeGameConfig().SpinsFor(NewAdvancedPatternMatcher(config).freeGameTriggerCount())
+}
+
+// validate 校验购买配置：价格和次数有效且购买的理论RTP在允许范围内
+func (c *BuyFeatureConfig) validate(config *SlotConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.PriceMultiplier <= 0 || c.FreeSpins < 0 || c.Spins(config) <= 0 {
+		return ErrInvalidBuyFeature
+	}
+	rtp, err := CalculateBuyFeatureRTP(config)
+	if err != nil {
+		return err
+	}
+	if band := featureRTPBand(config); rtp < band.Min || rtp > band.Max {
+		return ErrRTPOutOfRange
+	}
+	return nil
+}
+
+// CalculateBuyFeatureRTP 购买免费游戏的理论RTP
+// 期望进行的免费旋转次数（含再触发）× 免费卷轴组单次RTP × 免费游戏倍率 ÷ 价格倍数
+func CalculateBuyFeatureRTP(config *SlotConfig) (float64, error) {
+	buy := config.BuyFeature
+	if buy == nil {
+		return 0, ErrBuyUnavailable
+	}
+	freeGame, freeAwards, err := calculateBaseGameRTP(freeGameReelConfig(config))
+	if err != nil {
+		return 0, err
+	}
+	freeConfig := config.freeGameConfig()
+	bought := []freeGameAward{{prob: 1, spins: buy.Spins(config)}}
+	expected, err := expectedFreeGames(freeConfig, bought, freeAwards)
+	if err != nil {
+		return 0, err
+	}
+	return expected * freeGame.BaseGameRTP * freeConfig.GetMultiplier() / buy.PriceMultiplier, nil
+}
+
+// FeaturePurchase 购买免费游戏的结果
+type FeaturePurchase struct {
+	ID        string         `json:"id"`         // 购买ID
+	SessionID string         `json:"session_id"` // 会话ID
+	UserID    uint           `json:"user_id"`    // 用户ID
+	BetAmount int64          `json:"bet_amount"` // 免费旋转的派彩下注额
+	Price     int64          `json:"price"`      // 实际扣费
+	FreeGame  *FreeGameState `json:"free_game"`  // 进入的免费游戏
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// CanBuyFeature 检查当前能否按下注额购买免费游戏，不改变会话状态
+// 扣费需要先落库时用它预检，提交成功后再调用 BuyFeature
+func (e *SlotEngine) CanBuyFeature(sessionID string, betAmount int64) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.checkBuyFeature(e.sessionData[sessionID], betAmount)
+}
+
+// checkBuyFeature 校验购买条件，session 为空表示还没有转动过
+func (e *SlotEngine) checkBuyFeature(session *SessionData, betAmount int64) error {
+	if !e.isRunning {
+		return ErrEngineNotReady
+	}
+	if e.config.BuyFeature == nil {
+		return ErrBuyUnavailable
+	}
+	if betAmount < e.config.MinBet || betAmount > e.config.MaxBet {
+		return ErrInvalidBet
+	}
+	if session == nil {
+		return nil
+	}
+	switch {
+	case session.FreeGameState.IsActive():
+		return ErrFreeGameInProgress
+	case session.BonusGame.IsActive():
+		return ErrBonusInProgress
+	case session.Gamble.IsActive():
+		return ErrGambleInProgress
+	}
+	return nil
+}
+
+// BuyFeature 按下注额购买免费游戏，之后的旋转为免费旋转直到次数用完
+// 免费游戏、奖励游戏或博倍进行中时不能购买
+func (e *SlotEngine) BuyFeature(userID uint, sessionID string, betAmount int64) (*FeaturePurchase, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.checkBuyFeature(e.sessionData[sessionID], betAmount); err != nil {
+		return nil, err
+	}
+	buy := e.config.BuyFeature
+	session := e.getOrCreateSession(userID, sessionID)
+
+	freeGame := &session.FreeGameState
+	spins := freeGame.Trigger(buy.Spins(e.config), betAmount, e.config.freeGameConfig())
+	price := buy.Price(betAmount)
+
+	// 购买费用计入下注统计
+	e.statistics.TotalBet += price
+	session.TotalBet += price
+	e.statistics.FreeSpinsTotal += spins
+	e.statistics.CurrentRTP = e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
+	e.statistics.LastUpdate = time.Now()
+	if rtpCtrl, ok := e.rtpController.(*DynamicRTPController); ok {
+		rtpCtrl.UpdateHistory(price, 0)
+	}
+	session.LastActiveAt = time.Now()
+
+	snapshot := *freeGame
+	return &FeaturePurchase{
+		ID:        e.generateResultID(),
+		SessionID: sessionID,
+		UserID:    userID,
+		BetAmount: betAmount,
+		Price:     price,
+		FreeGame:  &snapshot,
+		Timestamp: time.Now(),
+	}, nil
+}
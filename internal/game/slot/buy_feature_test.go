@@ -0,0 +1,82 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBuyFeatureConfig_Validate(t *testing.T) {
+	config := GetPharaohConfig()
+	rtp, err := CalculateBuyFeatureRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateBuyFeatureRTP failed: %v", err)
+	}
+	if rtp < 0.94 || rtp > 0.97 {
+		t.Errorf("buy RTP = %.4f, want about 95%%", rtp)
+	}
+
+	// 价格减半时RTP翻倍
+	config.BuyFeature = &BuyFeatureConfig{PriceMultiplier: 5, FreeSpins: 10}
+	half, err := CalculateBuyFeatureRTP(config)
+	if err != nil || math.Abs(half-2*rtp) > 1e-9 {
+		t.Errorf("half price RTP = %v, %v", half, err)
+	}
+	if err := ValidateConfig(config); err != ErrRTPOutOfRange {
+		t.Errorf("ValidateConfig with half price = %v", err)
+	}
+
+	config.BuyFeature = &BuyFeatureConfig{PriceMultiplier: 0}
+	if err := ValidateConfig(config); err != ErrInvalidBuyFeature {
+		t.Errorf("ValidateConfig with zero price = %v", err)
+	}
+
+	// 缺省次数为最少触发数量对应的次数
+	config.BuyFeature = &BuyFeatureConfig{PriceMultiplier: 10}
+	minTrigger := NewAdvancedPatternMatcher(config).freeGameTriggerCount()
+	if got, want := config.BuyFeature.Spins(config), config.freeGameConfig().SpinsFor(minTrigger); got != want {
+		t.Errorf("default spins = %d, want %d", got, want)
+	}
+}
+
+func TestSlotEngine_BuyFeature(t *testing.T) {
+	engine, err := NewSlotEngine(GetPharaohConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(3))
+
+	const session = "buy"
+	purchase, err := engine.BuyFeature(1, session, 160)
+	if err != nil {
+		t.Fatalf("BuyFeature failed: %v", err)
+	}
+	if purchase.Price != 1600 || purchase.FreeGame.FreeSpinsLeft != 10 || purchase.FreeGame.TriggerBet != 160 {
+		t.Fatalf("purchase = price %d free game %+v", purchase.Price, purchase.FreeGame)
+	}
+	if got := engine.GetStatistics().TotalBet; got != 1600 {
+		t.Errorf("statistics TotalBet = %d, want 1600", got)
+	}
+	if _, err := engine.BuyFeature(1, session, 160); err != ErrFreeGameInProgress {
+		t.Errorf("second purchase = %v", err)
+	}
+
+	result, err := engine.Spin(1, session, 160)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if !result.IsFreeSpin || result.BetAmount != 0 {
+		t.Errorf("spin after purchase = free %v bet %d", result.IsFreeSpin, result.BetAmount)
+	}
+
+	if _, err := engine.BuyFeature(1, "other", 1); err != ErrInvalidBet {
+		t.Errorf("purchase below min bet = %v", err)
+	}
+
+	classic, err := NewSlotEngine(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	if _, err := classic.BuyFeature(1, session, 100); err != ErrBuyUnavailable {
+		t.Errorf("purchase without config = %v", err)
+	}
+}
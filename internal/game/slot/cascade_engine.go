@@ -14,6 +14,9 @@ type CascadeEngine struct {
 	
 	// 消除统计
 	cascadeStats *CascadeStatistics
+	
+	// 免费游戏内保留的递增倍数（基础旋转时重置）
+	carriedMultiplier float64
 }
 
 // CascadeConfig 消除式配置
@@ -28,6 +31,15 @@ type CascadeConfig struct {
 	
 	// 连线方向
 	AdjacentOnly bool `json:"adjacent_only"` // 仅相邻连线（不包括对角线）
+	
+	// 消除修饰器（递增倍数、粘性Wild、消除升级、神秘符号）
+	Modifiers *CascadeModifiers `json:"modifiers,omitempty"`
+	
+	// 最高赢取（达到上限时结束连锁）
+	MaxWin *MaxWinConfig `json:"max_win,omitempty"`
+	
+	// 彩金触发规则（为空时不派发彩金）
+	Jackpot *JackpotConfig `json:"jackpot,omitempty"`
 }
 
 // CascadeResult 消除结果
@@ -39,6 +51,8 @@ type CascadeResult struct {
 	TotalRemoved    int                    `json:"total_removed"`     // 总消除数
 	CascadeDetails  []CascadeStep          `json:"cascade_details"`   // 每步详情
 	FinalMultiplier float64                `json:"final_multiplier"`  // 最终倍数
+	Events          []CascadeEvent         `json:"events,omitempty"`  // 最后一次连锁之后的修饰器事件（如揭示后无匹配）
+	MaxWinReached   bool                   `json:"max_win_reached"`   // 达到最高赢取（连锁提前结束）
 }
 
 // CascadeStep 单次消除步骤
@@ -50,6 +64,7 @@ type CascadeStep struct {
 	GridBefore       [][]int      `json:"grid_before"`        // 消除前网格
 	GridAfterRemove  [][]int      `json:"grid_after_remove"`  // 消除后网格（有空位-1）
 	GridAfter        [][]int      `json:"grid_after"`         // 重力填充后网格
+	Events           []CascadeEvent `json:"events,omitempty"`  // 本步修饰器事件（按发生顺序）
 }
 
 // MatchGroup 匹配组
@@ -91,43 +106,81 @@ func NewCascadeEngine(algorithmConfig *AlgorithmConfig, cascadeConfig *CascadeCo
 func (e *CascadeEngine) SpinCascade(ctx context.Context, request *SpinRequest) (*CascadeResult, error) {
 	// 1. 生成初始网格
 	initialGrid := e.generateInitialGrid()
+	modifiers := e.newModifierState(request, SYMBOL_WILD)
+	
+	// 期待效果按停轮时的初始网格计算（消除和揭示神秘符号前）
+	anticipation := GridAnticipation(initialGrid, e.abstractEngine.GetAlgorithmConfig().FeatureConfigs)
 	
 	// 2. 执行连锁消除
 	cascadeSteps := []CascadeStep{}
+	var pendingEvents []CascadeEvent
 	currentGrid := initialGrid
 	totalWin := int64(0)
+	maxWinReached := false
 	stepNumber := 1
 	
 	for stepNumber <= e.cascadeConfig.MaxCascades {
+		// 匹配前揭示神秘符号
+		var events []CascadeEvent
+		if reveal := e.revealMystery(currentGrid); reveal != nil {
+			events = append(events, *reveal)
+		}
+		
 		// 查找匹配组
 		matches := e.findMatches(currentGrid)
 		if len(matches) == 0 {
+			pendingEvents = events
 			break // 没有更多匹配，结束连锁
 		}
 		
 		// 计算本步赢取
-		stepWin := e.calculateStepWin(matches, stepNumber)
+		multiplier, multiplierEvent := modifiers.stepMultiplier(stepNumber, e.getCascadeMultiplier(stepNumber))
+		if multiplierEvent != nil {
+			events = append(events, *multiplierEvent)
+		}
+		stepWin := e.calculateStepWin(matches, multiplier)
+		stepWin, maxWinReached = e.cascadeConfig.MaxWin.ApplyRound(totalWin, stepWin, request.GameRequest.BetAmount)
 		
-		// 消除匹配符号
+		// 消除匹配符号（粘性Wild保留，可升级的符号组留下升级符号）
 		newGrid := e.removeMatches(currentGrid, matches)
+		modifiers.keepStickyWilds(newGrid)
+		modifiers.advanceStickyWilds()
+		events = append(events, modifiers.upgradeSymbols(newGrid, matches)...)
+		events = append(events, modifiers.stickyEvents()...)
 		
 		// 重力下落
-		newGrid = e.applyGravity(newGrid)
+		if len(modifiers.sticky) > 0 {
+			newGrid = e.applyGravityFixed(newGrid, modifiers.sticky)
+		} else {
+			newGrid = e.applyGravity(newGrid)
+		}
 		
 		// 记录步骤
 		step := CascadeStep{
 			StepNumber:    stepNumber,
 			RemovedGroups: matches,
 			StepWin:       stepWin,
-			Multiplier:    e.getCascadeMultiplier(stepNumber),
+			Multiplier:    multiplier,
 			GridAfter:     newGrid,
+			Events:        events,
 		}
 		cascadeSteps = append(cascadeSteps, step)
 		
 		totalWin += stepWin
 		currentGrid = newGrid
 		stepNumber++
+		
+		// 达到最高赢取时结束连锁
+		if maxWinReached {
+			break
+		}
 	}
+	// 达到最大连锁次数时揭示剩余的神秘符号
+	if reveal := e.revealMystery(currentGrid); reveal != nil {
+		pendingEvents = append(pendingEvents, *reveal)
+	}
+	e.finishModifierState(modifiers, request)
+	finalMultiplier := modifiers.finalMultiplier(e.getCascadeMultiplier(len(cascadeSteps)))
 	
 	// 3. 构建结果
 	result := &CascadeResult{
@@ -137,12 +190,15 @@ func (e *CascadeEngine) SpinCascade(ctx context.Context, request *SpinRequest) (
 			TotalWin:    totalWin,
 			IsWin:       totalWin > 0,
 			ReelResults: currentGrid,
-			Multiplier:  e.getCascadeMultiplier(len(cascadeSteps)),
+			Multiplier:  finalMultiplier,
+			Anticipation: anticipation,
 		},
 		CascadeCount:    len(cascadeSteps),
 		TotalRemoved:    e.countTotalRemoved(cascadeSteps),
 		CascadeDetails:  cascadeSteps,
-		FinalMultiplier: e.getCascadeMultiplier(len(cascadeSteps)),
+		FinalMultiplier: finalMultiplier,
+		Events:          pendingEvents,
+		MaxWinReached:   maxWinReached,
 	}
 	
 	// 4. 更新统计
@@ -158,7 +214,7 @@ func (e *CascadeEngine) generateInitialGrid() [][]int {
 		grid[i] = make([]int, e.cascadeConfig.GridWidth)
 		for j := range grid[i] {
 			// 使用抽象引擎的随机生成器
-			grid[i][j] = e.abstractEngine.(*AbstractSlotEngine).randomGen.NextInt(0, e.abstractEngine.GetAlgorithmConfig().SymbolCount)
+			grid[i][j] = e.nextSymbol()
 		}
 	}
 	return grid
@@ -175,7 +231,8 @@ func (e *CascadeEngine) findMatches(grid [][]int) []MatchGroup {
 	// 遍历每个位置
 	for row := 0; row < len(grid); row++ {
 		for col := 0; col < len(grid[row]); col++ {
-			if !visited[row][col] {
+			// 粘性Wild（消除升级产生）不单独成组
+			if !visited[row][col] && grid[row][col] != SYMBOL_WILD {
 				// 使用DFS查找连通的相同符号
 				positions := e.dfsMatch(grid, visited, row, col, grid[row][col])
 				if len(positions) >= e.cascadeConfig.MinMatch {
@@ -294,7 +351,7 @@ func (e *CascadeEngine) applyGravity(grid [][]int) [][]int {
 		
 		// 在顶部生成新符号填补空位
 		for len(symbols) < len(grid) {
-			newSymbol := e.abstractEngine.(*AbstractSlotEngine).randomGen.NextInt(0, e.abstractEngine.GetAlgorithmConfig().SymbolCount)
+			newSymbol := e.nextSymbol()
 			symbols = append(symbols, newSymbol)
 		}
 		
@@ -308,14 +365,13 @@ func (e *CascadeEngine) applyGravity(grid [][]int) [][]int {
 }
 
 // calculateStepWin 计算单步赢取
-func (e *CascadeEngine) calculateStepWin(matches []MatchGroup, stepNumber int) int64 {
+func (e *CascadeEngine) calculateStepWin(matches []MatchGroup, multiplier float64) int64 {
 	totalWin := int64(0)
 	for _, match := range matches {
 		totalWin += match.Payout
 	}
 	
 	// 应用连锁倍数
-	multiplier := e.getCascadeMultiplier(stepNumber)
 	return int64(float64(totalWin) * multiplier)
 }
 
@@ -359,6 +415,11 @@ func (e *CascadeEngine) updateCascadeStats(result *CascadeResult) {
 	}
 }
 
+// GetCascadeConfig 获取消除配置
+func (e *CascadeEngine) GetCascadeConfig() *CascadeConfig {
+	return e.cascadeConfig
+}
+
 // GetDefaultCascadeConfig 获取默认消除式配置
 func GetDefaultCascadeConfig() *CascadeConfig {
 	return &CascadeConfig{
@@ -123,16 +123,25 @@ func (e *GoldenWildCascadeEngine) SpinWithGoldenWild(ctx context.Context, reques
 	}
 	
 	// 2. 执行连锁消除（支持Wild）
+	modifiers := e.newModifierState(request, e.goldenWildConfig.WildSymbolID)
 	cascadeSteps := []CascadeStep{}
+	var pendingEvents []CascadeEvent
 	wildTransitions := []WildTransition{}
 	currentGrid := initialGrid
 	totalWin := int64(0)
+	maxWinReached := false
 	stepNumber := 1
 	
 	// 重置Wild跟踪器
 	e.wildTracker.NewWilds = make(map[string]GamePosition)
 	
 	for stepNumber <= e.cascadeConfig.MaxCascades {
+		// 匹配前揭示神秘符号
+		var events []CascadeEvent
+		if reveal := e.revealMystery(currentGrid); reveal != nil {
+			events = append(events, *reveal)
+		}
+		
 		// 查找1024线匹配（支持Wild替换）
 		lineMatches := e.line1024Matcher.Find1024LineMatchesWithWild(
 			currentGrid, 
@@ -141,6 +150,7 @@ func (e *GoldenWildCascadeEngine) SpinWithGoldenWild(ctx context.Context, reques
 		)
 		matches := e.line1024Matcher.ConvertToMatchGroups(lineMatches)
 		if len(matches) == 0 {
+			pendingEvents = events
 			break // 没有更多匹配，结束连锁
 		}
 		
@@ -156,10 +166,20 @@ func (e *GoldenWildCascadeEngine) SpinWithGoldenWild(ctx context.Context, reques
 		wildTransitions = append(wildTransitions, wildUsage...)
 		
 		// 计算本步赢取
-		stepWin := e.calculateStepWin(matches, stepNumber)
+		multiplier, multiplierEvent := modifiers.stepMultiplier(stepNumber, e.getCascadeMultiplier(stepNumber))
+		if multiplierEvent != nil {
+			events = append(events, *multiplierEvent)
+		}
+		stepWin := e.calculateStepWin(matches, multiplier)
+		stepWin, maxWinReached = e.cascadeConfig.MaxWin.ApplyRound(totalWin, stepWin, request.GameRequest.BetAmount)
 		
-		// 消除匹配符号，处理Golden→Wild转换
+		// 消除匹配符号，处理Golden→Wild转换（粘性Wild保留，新Wild登记为粘性Wild）
 		gridAfterRemove, newWilds := e.removeMatchesWithGoldenWild(currentGrid, matches, &goldenSymbols)
+		modifiers.keepStickyWilds(gridAfterRemove)
+		modifiers.advanceStickyWilds()
+		modifiers.addStickyWilds(newWilds)
+		events = append(events, modifiers.upgradeSymbols(gridAfterRemove, matches)...)
+		events = append(events, modifiers.stickyEvents()...)
 		
 		// 记录新生成的Wild
 		for _, wildPos := range newWilds {
@@ -173,25 +193,42 @@ func (e *GoldenWildCascadeEngine) SpinWithGoldenWild(ctx context.Context, reques
 			})
 		}
 		
-		// 重力下落和符号补充（保持Wild位置）
-		gridAfterFill := e.applyGravityWithWild(gridAfterRemove)
+		// 重力下落和符号补充（粘性Wild固定在原位）
+		var gridAfterFill [][]int
+		if len(modifiers.sticky) > 0 {
+			gridAfterFill = e.applyGravityFixed(gridAfterRemove, modifiers.sticky)
+		} else {
+			gridAfterFill = e.applyGravityWithWild(gridAfterRemove)
+		}
 		
 		// 记录步骤（包含所有网格状态）
 		step := CascadeStep{
 			StepNumber:      stepNumber,
 			RemovedGroups:   matches,
 			StepWin:         stepWin,
-			Multiplier:      e.getCascadeMultiplier(stepNumber),
+			Multiplier:      multiplier,
 			GridBefore:      gridBefore,      // 消除前
 			GridAfterRemove: gridAfterRemove, // 消除后（有空位-1）
 			GridAfter:       gridAfterFill,   // 重力填充后
+			Events:          events,
 		}
 		cascadeSteps = append(cascadeSteps, step)
 		
 		totalWin += stepWin
 		currentGrid = gridAfterFill
 		stepNumber++
+		
+		// 达到最高赢取时结束连锁
+		if maxWinReached {
+			break
+		}
+	}
+	// 达到最大连锁次数时揭示剩余的神秘符号
+	if reveal := e.revealMystery(currentGrid); reveal != nil {
+		pendingEvents = append(pendingEvents, *reveal)
 	}
+	e.finishModifierState(modifiers, request)
+	finalMultiplier := modifiers.finalMultiplier(e.getCascadeMultiplier(len(cascadeSteps)))
 	
 	// 3. 检测Animal游戏触发
 	var animalTrigger *AnimalTriggerData
@@ -209,12 +246,15 @@ func (e *GoldenWildCascadeEngine) SpinWithGoldenWild(ctx context.Context, reques
 				TotalWin:    totalWin,
 				IsWin:       totalWin > 0,
 				ReelResults: currentGrid,
-				Multiplier:  e.getCascadeMultiplier(len(cascadeSteps)),
+				Multiplier:  finalMultiplier,
+				Anticipation: GridAnticipation(originalInitialGrid, e.abstractEngine.GetAlgorithmConfig().FeatureConfigs),
 			},
 			CascadeCount:    len(cascadeSteps),
 			TotalRemoved:    e.countTotalRemoved(cascadeSteps),
 			CascadeDetails:  cascadeSteps,
-			FinalMultiplier: e.getCascadeMultiplier(len(cascadeSteps)),
+			FinalMultiplier: finalMultiplier,
+			Events:          pendingEvents,
+			MaxWinReached:   maxWinReached,
 		},
 		InitialGrid:     originalInitialGrid, // 保存真正的初始网格
 		GoldenSymbols:   goldenSymbols,
@@ -235,12 +275,14 @@ func (e *GoldenWildCascadeEngine) generateGridWithGolden() ([][]int, []GoldenSym
 	// Animal触发符号出现概率
 	animalWildProb := 0.02    // 2%概率出现Animal Wild
 	animalBonusProb := 0.01   // 1%概率出现Animal Bonus
+	scatterSymbol, scatterProb := e.featureSymbol(AbstractFeatureTypeFreeSpin)
+	bonusSymbol, bonusProb := e.featureSymbol(AbstractFeatureTypeBonus)
 	
 	for i := range grid {
 		grid[i] = make([]int, e.cascadeConfig.GridWidth)
 		for j := range grid[i] {
 			// 先检查是否生成特殊符号
-			rand := e.abstractEngine.(*AbstractSlotEngine).randomGen.Next()
+			rand := e.random().Next()
 			
 			if rand < animalBonusProb {
 				// 生成Animal Bonus符号
@@ -248,9 +290,15 @@ func (e *GoldenWildCascadeEngine) generateGridWithGolden() ([][]int, []GoldenSym
 			} else if rand < animalBonusProb + animalWildProb {
 				// 生成Animal Wild符号
 				grid[i][j] = SYMBOL_ANIMAL_WILD
+			} else if rand < animalBonusProb + animalWildProb + scatterProb {
+				// 生成免费游戏触发符号
+				grid[i][j] = scatterSymbol
+			} else if rand < animalBonusProb + animalWildProb + scatterProb + bonusProb {
+				// 生成奖励游戏触发符号
+				grid[i][j] = bonusSymbol
 			} else {
-				// 生成普通符号
-				symbolID := e.abstractEngine.(*AbstractSlotEngine).randomGen.NextInt(0, e.abstractEngine.GetAlgorithmConfig().SymbolCount)
+				// 生成普通符号（或神秘符号）
+				symbolID := e.nextSymbol()
 				grid[i][j] = symbolID
 				
 				// 检查是否变成金色
@@ -270,6 +318,15 @@ func (e *GoldenWildCascadeEngine) generateGridWithGolden() ([][]int, []GoldenSym
 	return grid, goldenSymbols
 }
 
+// featureSymbol 特性触发符号及其单格出现概率（未配置该特性时概率为0）
+func (e *GoldenWildCascadeEngine) featureSymbol(featureType AbstractFeatureType) (int, float64) {
+	feature := e.abstractEngine.GetAlgorithmConfig().FeatureConfigs[featureType]
+	if feature == nil || len(feature.TriggerSymbols) == 0 || feature.Probability <= 0 {
+		return 0, 0
+	}
+	return feature.TriggerSymbols[0], feature.Probability
+}
+
 // findMatchesWithWild 查找包含Wild的匹配
 func (e *GoldenWildCascadeEngine) findMatchesWithWild(grid [][]int) []MatchGroup {
 	matches := []MatchGroup{}
@@ -453,7 +510,7 @@ func (e *GoldenWildCascadeEngine) applyGravityWithWild(grid [][]int) [][]int {
 		
 		// 在顶部生成新符号填补空位
 		for len(symbols) < len(grid) {
-			newSymbol := e.abstractEngine.(*AbstractSlotEngine).randomGen.NextInt(0, e.abstractEngine.GetAlgorithmConfig().SymbolCount)
+			newSymbol := e.nextSymbol()
 			symbols = append(symbols, newSymbol)
 		}
 		
@@ -499,7 +556,7 @@ func (e *GoldenWildCascadeEngine) canBeGolden(symbolID int) bool {
 
 // shouldBeGolden 检查是否应该变成金色
 func (e *GoldenWildCascadeEngine) shouldBeGolden() bool {
-	randomValue := e.abstractEngine.(*AbstractSlotEngine).randomGen.Next()
+	randomValue := e.random().Next()
 	return randomValue < e.goldenWildConfig.GoldenProbability
 }
 
@@ -0,0 +1,364 @@
+package slot
+
+import "sort"
+
+// CascadeModifiers 消除修饰器配置（各项为空时不启用）
+type CascadeModifiers struct {
+	ProgressiveMultiplier *ProgressiveMultiplierConfig `json:"progressive_multiplier,omitempty"` // 递增倍数
+	StickyWild            *StickyWildConfig            `json:"sticky_wild,omitempty"`            // 粘性Wild
+	SymbolUpgrade         *SymbolUpgradeConfig         `json:"symbol_upgrade,omitempty"`         // 消除升级
+	Mystery               *MysterySymbolConfig         `json:"mystery,omitempty"`                // 神秘符号
+}
+
+// ProgressiveMultiplierConfig 递增倍数：每次连锁倍数增加，替代固定的连锁倍数表
+type ProgressiveMultiplierConfig struct {
+	Start             float64 `json:"start"`                // 第一次连锁的倍数（默认1）
+	Step              float64 `json:"step"`                 // 每次连锁增加的倍数
+	Max               float64 `json:"max"`                  // 最大倍数（0为不限）
+	PersistInFreeGame bool    `json:"persist_in_free_game"` // 免费游戏内跨旋转保留已达到的倍数
+}
+
+// StickyWildConfig 粘性Wild：新生成的Wild固定在原位，参与消除也不移除，保留指定次数的连锁
+type StickyWildConfig struct {
+	Cascades int `json:"cascades"` // 保留的连锁次数
+}
+
+// SymbolUpgradeConfig 消除升级：符号组被消除时，组内第一个位置留下升级后的符号
+// 消除网格用-1表示空位，Wild同为-1，因此不能升级为Wild
+type SymbolUpgradeConfig struct {
+	Upgrades map[int]int `json:"upgrades"` // 原符号ID → 升级后的符号ID（不能为Wild）
+}
+
+// MysterySymbolConfig 神秘符号：按概率出现在盘面和补充符号中，每次匹配前全部揭示为同一个随机符号
+type MysterySymbolConfig struct {
+	Probability   float64 `json:"probability"`    // 单格出现概率
+	RevealSymbols []int   `json:"reveal_symbols"` // 可揭示的符号（为空时为全部普通符号）
+}
+
+// validate 验证消除修饰器配置
+func (m *CascadeModifiers) validate() error {
+	if m == nil {
+		return nil
+	}
+	if progressive := m.ProgressiveMultiplier; progressive != nil {
+		if progressive.Start < 0 || progressive.Step < 0 || progressive.Max < 0 {
+			return ErrInvalidCascade
+		}
+	}
+	if m.StickyWild != nil && m.StickyWild.Cascades < 0 {
+		return ErrInvalidCascade
+	}
+	if upgrade := m.SymbolUpgrade; upgrade != nil {
+		for from, to := range upgrade.Upgrades {
+			// 升级后的符号写入消除后的空位，为负数（Wild、空位）时会被重力当作空位清除
+			if from < 0 || to < 0 {
+				return ErrInvalidCascade
+			}
+		}
+	}
+	if mystery := m.Mystery; mystery != nil {
+		if mystery.Probability < 0 || mystery.Probability > 1 {
+			return ErrInvalidCascade
+		}
+		for _, symbol := range mystery.RevealSymbols {
+			if symbol < 0 || symbol == SYMBOL_MYSTERY {
+				return ErrInvalidCascade
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateCascadeConfig 验证消除式配置
+func ValidateCascadeConfig(config *CascadeConfig) error {
+	if config.GridWidth <= 0 || config.GridHeight <= 0 || config.MinMatch <= 0 {
+		return ErrInvalidCascade
+	}
+	if err := config.Modifiers.validate(); err != nil {
+		return err
+	}
+	if err := config.MaxWin.validate(); err != nil {
+		return err
+	}
+	return config.Jackpot.validate()
+}
+
+// CascadeEventType 消除事件类型
+type CascadeEventType string
+
+const (
+	CascadeEventMultiplier    CascadeEventType = "multiplier"     // 本次连锁的倍数
+	CascadeEventStickyWild    CascadeEventType = "sticky_wild"    // 粘性Wild及剩余保留次数
+	CascadeEventSymbolUpgrade CascadeEventType = "symbol_upgrade" // 消除后符号升级
+	CascadeEventMysteryReveal CascadeEventType = "mystery_reveal" // 神秘符号揭示
+)
+
+// CascadeEvent 消除修饰器事件（客户端据此播放动画）
+type CascadeEvent struct {
+	Type       CascadeEventType `json:"type"`
+	Positions  []GamePosition   `json:"positions,omitempty"`  // 涉及的位置
+	FromSymbol int              `json:"from_symbol"`          // 原符号（升级、揭示）
+	ToSymbol   int              `json:"to_symbol"`            // 新符号（升级、揭示）
+	Multiplier float64          `json:"multiplier,omitempty"` // 倍数
+	Remaining  int              `json:"remaining,omitempty"`  // 粘性Wild剩余保留次数
+}
+
+// cascadeModifierState 单次旋转中的修饰器状态
+type cascadeModifierState struct {
+	modifiers  *CascadeModifiers
+	wildSymbol int
+	start      float64              // 递增倍数的起始值
+	multiplier float64              // 最近一次连锁的倍数
+	sticky     map[GamePosition]int // 粘性Wild位置 → 剩余保留次数
+}
+
+// newModifierState 创建本次旋转的修饰器状态
+// 免费旋转且配置了跨旋转保留时，递增倍数从上一次免费旋转达到的倍数开始
+func (e *CascadeEngine) newModifierState(request *SpinRequest, wildSymbol int) *cascadeModifierState {
+	state := &cascadeModifierState{
+		modifiers:  e.cascadeConfig.Modifiers,
+		wildSymbol: wildSymbol,
+		sticky:     make(map[GamePosition]int),
+	}
+	if state.modifiers == nil {
+		state.modifiers = &CascadeModifiers{}
+	}
+
+	if progressive := state.modifiers.ProgressiveMultiplier; progressive != nil {
+		state.start = progressive.Start
+		if state.start <= 0 {
+			state.start = 1
+		}
+		if progressive.PersistInFreeGame && request != nil && request.FreeSpin && e.carriedMultiplier > state.start {
+			state.start = e.carriedMultiplier
+		}
+		state.multiplier = state.start
+	}
+	return state
+}
+
+// finishModifierState 旋转结束：保存免费游戏内保留的倍数，基础旋转重置
+func (e *CascadeEngine) finishModifierState(state *cascadeModifierState, request *SpinRequest) {
+	progressive := state.modifiers.ProgressiveMultiplier
+	if progressive == nil || !progressive.PersistInFreeGame {
+		return
+	}
+	if request != nil && request.FreeSpin {
+		e.carriedMultiplier = state.multiplier
+	} else {
+		e.carriedMultiplier = 0
+	}
+}
+
+// stepMultiplier 第step次连锁的倍数，未启用递增倍数时使用固定倍数表
+func (s *cascadeModifierState) stepMultiplier(step int, fixed float64) (float64, *CascadeEvent) {
+	progressive := s.modifiers.ProgressiveMultiplier
+	if progressive == nil {
+		return fixed, nil
+	}
+
+	multiplier := s.start + progressive.Step*float64(step-1)
+	if progressive.Max > 0 && multiplier > progressive.Max {
+		multiplier = progressive.Max
+	}
+	s.multiplier = multiplier
+	return multiplier, &CascadeEvent{Type: CascadeEventMultiplier, Multiplier: multiplier}
+}
+
+// finalMultiplier 本次旋转最终的倍数
+func (s *cascadeModifierState) finalMultiplier(fixed float64) float64 {
+	if s.modifiers.ProgressiveMultiplier == nil {
+		return fixed
+	}
+	return s.multiplier
+}
+
+// nextSymbol 生成一个补充符号（配置了神秘符号时按概率生成神秘符号）
+func (e *CascadeEngine) nextSymbol() int {
+	if mystery := e.mysteryConfig(); mystery != nil && e.random().Next() < mystery.Probability {
+		return SYMBOL_MYSTERY
+	}
+	return e.random().NextInt(0, e.abstractEngine.GetAlgorithmConfig().SymbolCount)
+}
+
+// mysteryConfig 神秘符号配置（未启用时为空）
+func (e *CascadeEngine) mysteryConfig() *MysterySymbolConfig {
+	if e.cascadeConfig.Modifiers == nil {
+		return nil
+	}
+	mystery := e.cascadeConfig.Modifiers.Mystery
+	if mystery == nil || mystery.Probability <= 0 {
+		return nil
+	}
+	return mystery
+}
+
+// revealMystery 将盘面上的神秘符号全部揭示为同一个随机符号
+func (e *CascadeEngine) revealMystery(grid [][]int) *CascadeEvent {
+	var positions []GamePosition
+	for row := range grid {
+		for col, symbol := range grid[row] {
+			if symbol == SYMBOL_MYSTERY {
+				positions = append(positions, GamePosition{Reel: col, Row: row})
+			}
+		}
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+
+	var symbol int
+	if mystery := e.mysteryConfig(); mystery != nil && len(mystery.RevealSymbols) > 0 {
+		symbol = mystery.RevealSymbols[e.random().NextInt(0, len(mystery.RevealSymbols))]
+	} else {
+		symbol = e.random().NextInt(0, e.abstractEngine.GetAlgorithmConfig().SymbolCount)
+	}
+	for _, pos := range positions {
+		grid[pos.Row][pos.Reel] = symbol
+	}
+	return &CascadeEvent{
+		Type:       CascadeEventMysteryReveal,
+		Positions:  positions,
+		FromSymbol: SYMBOL_MYSTERY,
+		ToSymbol:   symbol,
+	}
+}
+
+// upgradeSymbols 消除后在每个可升级的符号组的第一个位置留下升级后的符号
+func (s *cascadeModifierState) upgradeSymbols(grid [][]int, matches []MatchGroup) []CascadeEvent {
+	upgrade := s.modifiers.SymbolUpgrade
+	if upgrade == nil || len(upgrade.Upgrades) == 0 {
+		return nil
+	}
+
+	var events []CascadeEvent
+	upgraded := make(map[GamePosition]bool)
+	for _, match := range matches {
+		to, ok := upgrade.Upgrades[match.SymbolID]
+		if !ok || len(match.Positions) == 0 {
+			continue
+		}
+		pos := match.Positions[0]
+		if _, sticky := s.sticky[pos]; sticky || upgraded[pos] || grid[pos.Row][pos.Reel] != -1 {
+			continue // 空位才能升级（Wild与空位同为-1，粘性Wild需排除）
+		}
+		upgraded[pos] = true
+		grid[pos.Row][pos.Reel] = to
+		events = append(events, CascadeEvent{
+			Type:       CascadeEventSymbolUpgrade,
+			Positions:  []GamePosition{pos},
+			FromSymbol: match.SymbolID,
+			ToSymbol:   to,
+		})
+	}
+	return events
+}
+
+// stickyEnabled 是否启用粘性Wild
+func (s *cascadeModifierState) stickyEnabled() bool {
+	return s.modifiers.StickyWild != nil && s.modifiers.StickyWild.Cascades > 0
+}
+
+// keepStickyWilds 参与消除的粘性Wild保留在原位
+func (s *cascadeModifierState) keepStickyWilds(grid [][]int) {
+	for pos := range s.sticky {
+		if grid[pos.Row][pos.Reel] == -1 {
+			grid[pos.Row][pos.Reel] = s.wildSymbol
+		}
+	}
+}
+
+// advanceStickyWilds 连锁结束时减少已有粘性Wild的保留次数，释放到期的Wild
+func (s *cascadeModifierState) advanceStickyWilds() {
+	for pos, remaining := range s.sticky {
+		if remaining <= 1 {
+			delete(s.sticky, pos)
+		} else {
+			s.sticky[pos] = remaining - 1
+		}
+	}
+}
+
+// addStickyWilds 登记本次连锁新生成的粘性Wild
+func (s *cascadeModifierState) addStickyWilds(positions []GamePosition) {
+	if !s.stickyEnabled() {
+		return
+	}
+	for _, pos := range positions {
+		s.sticky[pos] = s.modifiers.StickyWild.Cascades
+	}
+}
+
+// stickyEvents 当前的粘性Wild（按剩余保留次数分组）
+func (s *cascadeModifierState) stickyEvents() []CascadeEvent {
+	if len(s.sticky) == 0 {
+		return nil
+	}
+	byRemaining := make(map[int][]GamePosition)
+	maxRemaining := 0
+	for pos, remaining := range s.sticky {
+		byRemaining[remaining] = append(byRemaining[remaining], pos)
+		if remaining > maxRemaining {
+			maxRemaining = remaining
+		}
+	}
+
+	var events []CascadeEvent
+	for remaining := 1; remaining <= maxRemaining; remaining++ {
+		positions := byRemaining[remaining]
+		if len(positions) == 0 {
+			continue
+		}
+		sort.Slice(positions, func(i, j int) bool {
+			if positions[i].Row != positions[j].Row {
+				return positions[i].Row < positions[j].Row
+			}
+			return positions[i].Reel < positions[j].Reel
+		})
+		events = append(events, CascadeEvent{
+			Type:       CascadeEventStickyWild,
+			Positions:  positions,
+			FromSymbol: s.wildSymbol,
+			ToSymbol:   s.wildSymbol,
+			Remaining:  remaining,
+		})
+	}
+	return events
+}
+
+// applyGravityFixed 应用重力，粘性Wild固定不动，其余符号在固定位置之间下落
+func (e *CascadeEngine) applyGravityFixed(grid [][]int, fixed map[GamePosition]int) [][]int {
+	newGrid := make([][]int, len(grid))
+	for i := range newGrid {
+		newGrid[i] = make([]int, len(grid[i]))
+	}
+
+	for col := 0; col < len(grid[0]); col++ {
+		// 自下而上收集可移动的非空符号
+		symbols := []int{}
+		for row := len(grid) - 1; row >= 0; row-- {
+			if _, ok := fixed[GamePosition{Reel: col, Row: row}]; ok {
+				continue
+			}
+			if grid[row][col] != -1 {
+				symbols = append(symbols, grid[row][col])
+			}
+		}
+
+		// 自下而上填充，固定位置保持原符号，顶部空位补充新符号
+		next := 0
+		for row := len(grid) - 1; row >= 0; row-- {
+			if _, ok := fixed[GamePosition{Reel: col, Row: row}]; ok {
+				newGrid[row][col] = grid[row][col]
+				continue
+			}
+			if next < len(symbols) {
+				newGrid[row][col] = symbols[next]
+				next++
+			} else {
+				newGrid[row][col] = e.nextSymbol()
+			}
+		}
+	}
+	return newGrid
+}
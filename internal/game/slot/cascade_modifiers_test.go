@@ -0,0 +1,210 @@
+package slot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// modifierTestEngine 创建启用消除修饰器的金色Wild消除引擎（固定种子）
+func modifierTestEngine(modifiers *CascadeModifiers, seed int64) *GoldenWildCascadeEngine {
+	cascadeConfig := GetDefaultCascadeConfig()
+	cascadeConfig.Modifiers = modifiers
+	engine := NewGoldenWildCascadeEngine(GetMahjongAlgorithmConfig(), cascadeConfig)
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(seed))
+	return engine
+}
+
+func modifierTestRequest(freeSpin bool) *SpinRequest {
+	return &SpinRequest{
+		GameRequest: &GameRequest{SessionID: "modifiers", BetAmount: 100},
+		FreeSpin:    freeSpin,
+	}
+}
+
+func TestCascadeModifiers_ProgressiveMultiplier(t *testing.T) {
+	engine := modifierTestEngine(&CascadeModifiers{
+		ProgressiveMultiplier: &ProgressiveMultiplierConfig{Start: 1, Step: 1, Max: 3, PersistInFreeGame: true},
+	}, 1)
+
+	state := engine.newModifierState(modifierTestRequest(false), SYMBOL_WILD)
+	for step, want := range []float64{1, 2, 3, 3} {
+		multiplier, event := state.stepMultiplier(step+1, 99)
+		if multiplier != want || event == nil || event.Multiplier != want {
+			t.Fatalf("step %d multiplier = %v (event %+v), want %v", step+1, multiplier, event, want)
+		}
+	}
+
+	// 免费旋转保留已达到的倍数，回到基础旋转后重置
+	engine.finishModifierState(state, modifierTestRequest(true))
+	if free := engine.newModifierState(modifierTestRequest(true), SYMBOL_WILD); free.start != 3 {
+		t.Errorf("free spin start = %v, want 3", free.start)
+	}
+	if base := engine.newModifierState(modifierTestRequest(false), SYMBOL_WILD); base.start != 1 {
+		t.Errorf("base spin start = %v, want 1", base.start)
+	}
+	engine.finishModifierState(state, modifierTestRequest(false))
+	if free := engine.newModifierState(modifierTestRequest(true), SYMBOL_WILD); free.start != 1 {
+		t.Errorf("start after base spin = %v, want 1", free.start)
+	}
+
+	// 未启用时使用固定倍数表
+	plain := modifierTestEngine(nil, 1).newModifierState(nil, SYMBOL_WILD)
+	if multiplier, event := plain.stepMultiplier(2, 2); multiplier != 2 || event != nil {
+		t.Errorf("fixed multiplier = %v (event %+v), want 2", multiplier, event)
+	}
+}
+
+func TestCascadeModifiers_StickyWildAndGravity(t *testing.T) {
+	engine := modifierTestEngine(&CascadeModifiers{StickyWild: &StickyWildConfig{Cascades: 2}}, 1)
+	state := engine.newModifierState(nil, SYMBOL_WILD)
+	wild := GamePosition{Reel: 1, Row: 2}
+	state.addStickyWilds([]GamePosition{wild})
+
+	grid := [][]int{
+		{0, 1, 2},
+		{3, -1, 4},
+		{5, -1, 6},
+	}
+	state.keepStickyWilds(grid)
+	next := engine.applyGravityFixed(grid, state.sticky)
+	if next[2][1] != SYMBOL_WILD {
+		t.Fatalf("sticky wild moved: %v", next)
+	}
+	if next[1][1] != 1 {
+		t.Errorf("symbol above the emptied cell should fall onto the sticky wild, got %v", next)
+	}
+
+	events := state.stickyEvents()
+	if len(events) != 1 || events[0].Remaining != 2 || events[0].Positions[0] != wild {
+		t.Errorf("sticky events = %+v", events)
+	}
+	state.advanceStickyWilds()
+	if state.sticky[wild] != 1 {
+		t.Errorf("remaining = %d, want 1", state.sticky[wild])
+	}
+	state.advanceStickyWilds()
+	if len(state.sticky) != 0 {
+		t.Errorf("sticky wild should be released, got %v", state.sticky)
+	}
+}
+
+func TestCascadeModifiers_UpgradeAndMystery(t *testing.T) {
+	engine := modifierTestEngine(&CascadeModifiers{
+		SymbolUpgrade: &SymbolUpgradeConfig{Upgrades: map[int]int{0: 7}},
+		Mystery:       &MysterySymbolConfig{Probability: 0.5, RevealSymbols: []int{5}},
+	}, 1)
+	state := engine.newModifierState(nil, SYMBOL_WILD)
+
+	grid := [][]int{
+		{-1, -1, -1},
+		{2, 3, 4},
+	}
+	matches := []MatchGroup{{SymbolID: 0, Positions: []GamePosition{{Reel: 1, Row: 0}, {Reel: 0, Row: 0}, {Reel: 2, Row: 0}}}}
+	events := state.upgradeSymbols(grid, matches)
+	if grid[0][1] != 7 || grid[0][0] != -1 {
+		t.Fatalf("grid after upgrade = %v", grid)
+	}
+	if len(events) != 1 || events[0].Type != CascadeEventSymbolUpgrade || events[0].FromSymbol != 0 || events[0].ToSymbol != 7 {
+		t.Errorf("upgrade events = %+v", events)
+	}
+
+	grid = [][]int{
+		{SYMBOL_MYSTERY, 1},
+		{2, SYMBOL_MYSTERY},
+	}
+	reveal := engine.revealMystery(grid)
+	if reveal == nil || reveal.ToSymbol != 5 || len(reveal.Positions) != 2 {
+		t.Fatalf("reveal = %+v", reveal)
+	}
+	if grid[0][0] != 5 || grid[1][1] != 5 {
+		t.Errorf("grid after reveal = %v", grid)
+	}
+	if engine.revealMystery(grid) != nil {
+		t.Error("revealing a grid without mystery symbols should produce no event")
+	}
+}
+
+func TestGoldenWildCascade_ModifierEvents(t *testing.T) {
+	engine := modifierTestEngine(&CascadeModifiers{
+		ProgressiveMultiplier: &ProgressiveMultiplierConfig{Start: 1, Step: 1},
+		StickyWild:            &StickyWildConfig{Cascades: 2},
+		SymbolUpgrade:         &SymbolUpgradeConfig{Upgrades: map[int]int{7: 6}},
+		Mystery:               &MysterySymbolConfig{Probability: 0.05},
+	}, 7)
+
+	seen := make(map[CascadeEventType]int)
+	for i := 0; i < 300; i++ {
+		result, err := engine.SpinWithGoldenWild(context.Background(), modifierTestRequest(false))
+		if err != nil {
+			t.Fatalf("SpinWithGoldenWild failed: %v", err)
+		}
+
+		var totalWin int64
+		for _, step := range result.CascadeDetails {
+			totalWin += step.StepWin
+			if step.Multiplier != float64(step.StepNumber) {
+				t.Fatalf("step %d multiplier = %v", step.StepNumber, step.Multiplier)
+			}
+			for _, row := range step.GridBefore {
+				for _, symbol := range row {
+					if symbol == SYMBOL_MYSTERY {
+						t.Fatalf("mystery symbol left unrevealed before matching: %v", step.GridBefore)
+					}
+				}
+			}
+			for _, event := range step.Events {
+				seen[event.Type]++
+				if event.Type == CascadeEventStickyWild {
+					for _, pos := range event.Positions {
+						if step.GridAfter[pos.Row][pos.Reel] != SYMBOL_WILD {
+							t.Fatalf("sticky wild at %+v missing after gravity: %v", pos, step.GridAfter)
+						}
+					}
+				}
+			}
+		}
+		for _, row := range result.ReelResults {
+			for _, symbol := range row {
+				if symbol == SYMBOL_MYSTERY {
+					t.Fatalf("final grid contains mystery symbol: %v", result.ReelResults)
+				}
+			}
+		}
+		if totalWin != result.TotalWin {
+			t.Fatalf("TotalWin = %d, sum of steps %d", result.TotalWin, totalWin)
+		}
+		if n := len(result.CascadeDetails); n > 0 && result.FinalMultiplier != float64(n) {
+			t.Fatalf("FinalMultiplier = %v after %d cascades", result.FinalMultiplier, n)
+		}
+	}
+
+	for _, eventType := range []CascadeEventType{CascadeEventMultiplier, CascadeEventStickyWild, CascadeEventSymbolUpgrade, CascadeEventMysteryReveal} {
+		if seen[eventType] == 0 {
+			t.Errorf("no %s events in 300 spins (seen %v)", eventType, seen)
+		}
+	}
+}
+
+func TestValidateCascadeConfig(t *testing.T) {
+	config := GetDefaultCascadeConfig()
+	config.Modifiers = &CascadeModifiers{SymbolUpgrade: &SymbolUpgradeConfig{Upgrades: map[int]int{7: 6}}}
+	if err := ValidateCascadeConfig(config); err != nil {
+		t.Fatalf("ValidateCascadeConfig failed: %v", err)
+	}
+
+	// 升级为Wild会与消除后的空位混淆，配置被拒绝
+	config.Modifiers.SymbolUpgrade.Upgrades[7] = SYMBOL_WILD
+	if err := ValidateCascadeConfig(config); !errors.Is(err, ErrInvalidCascade) {
+		t.Fatalf("upgrade to wild err = %v, want ErrInvalidCascade", err)
+	}
+	factory := NewGoldenWildSimulationFactory(GetMahjongAlgorithmConfig(), config, 100)
+	if _, err := factory(NewDRBGRandomGenerator(1)); !errors.Is(err, ErrInvalidCascade) {
+		t.Errorf("simulation factory err = %v, want ErrInvalidCascade", err)
+	}
+
+	config.Modifiers = &CascadeModifiers{Mystery: &MysterySymbolConfig{Probability: 0.1, RevealSymbols: []int{SYMBOL_MYSTERY}}}
+	if err := ValidateCascadeConfig(config); !errors.Is(err, ErrInvalidCascade) {
+		t.Errorf("mystery revealing itself err = %v, want ErrInvalidCascade", err)
+	}
+}
@@ -3,6 +3,7 @@ package slot
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 )
@@ -39,6 +40,7 @@ type SpinRequest struct {
 	*GameRequest
 	ThemeID      string `json:"theme_id"`       // 指定主题
 	EnableTheme  bool   `json:"enable_theme"`   // 是否启用主题渲染
+	FreeSpin     bool   `json:"free_spin"`      // 是否为免费旋转（消除式递增倍数可在免费游戏内保留）
 }
 
 // SpinResponse 旋转响应 - 支持多种格式
@@ -298,6 +300,28 @@ func (e *CompositeSlotEngine) LoadThemeFromJSON(jsonData []byte) error {
 	return e.themeManager.LoadThemeFromJSON(jsonData)
 }
 
+// SetThemeManager 使用共享的主题管理器（如主题包加载器注册主题的管理器），须在开始旋转前调用
+func (e *CompositeSlotEngine) SetThemeManager(manager *ThemeManager) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.themeManager = manager
+}
+
+// 随机数接口
+func (e *CompositeSlotEngine) SetRandomGenerator(rng RandomGenerator) {
+	if engine, ok := e.abstractEngine.(*AbstractSlotEngine); ok {
+		engine.SetRandomGenerator(rng)
+	}
+}
+
+// random 获取抽象引擎使用的随机数生成器
+func (e *CompositeSlotEngine) random() RandomGenerator {
+	if engine, ok := e.abstractEngine.(*AbstractSlotEngine); ok {
+		return engine.GetRandomGenerator()
+	}
+	return NewCryptoRandomGenerator()
+}
+
 // RTP控制接口
 func (e *CompositeSlotEngine) SetTargetRTP(rtp float64) error {
 	return e.abstractEngine.SetTargetRTP(rtp)
@@ -406,6 +430,8 @@ type CompositeSimulationResult struct {
 	ActualRTP       float64           `json:"actual_rtp"`
 	RTTDeviation    float64           `json:"rtp_deviation"`
 	Confidence      float64           `json:"confidence"`
+	RTPLower        float64           `json:"rtp_lower"`        // 95%置信区间下限
+	RTPUpper        float64           `json:"rtp_upper"`        // 95%置信区间上限
 }
 
 // calculateSimulationStats 计算仿真统计
@@ -418,7 +444,17 @@ func (e *CompositeSlotEngine) calculateSimulationStats(results []*AbstractGameRe
 	}
 	
 	rtpDeviation := actualRTP - config.TargetRTP
-	confidence := calculateConfidenceLevel(len(results), actualRTP, config.TargetRTP)
+	
+	// 基于样本方差的置信区间
+	acc := newSimulationAccumulator(config.BetAmount)
+	for _, result := range results {
+		acc.add(&SimulationSample{BetAmount: result.BetAmount, WinAmount: result.TotalWin})
+	}
+	report := buildSimulationReport(acc, &SimulationOptions{
+		BetAmount:       config.BetAmount,
+		TargetRTP:       config.TargetRTP,
+		ConfidenceLevel: 0.95,
+	})
 	
 	return &CompositeSimulationResult{
 		Config:       config,
@@ -426,7 +462,9 @@ func (e *CompositeSlotEngine) calculateSimulationStats(results []*AbstractGameRe
 		Aggregated:   aggregated,
 		ActualRTP:    actualRTP,
 		RTTDeviation: rtpDeviation,
-		Confidence:   confidence,
+		Confidence:   calculateConfidenceLevel(report.StandardError, actualRTP, config.TargetRTP),
+		RTPLower:     report.RTPLower,
+		RTPUpper:     report.RTPUpper,
 	}
 }
 
@@ -435,11 +473,16 @@ func getCurrentTimeMs() int64 {
 	return time.Now().UnixMilli()
 }
 
-func calculateConfidenceLevel(sampleSize int, actualRTP, targetRTP float64) float64 {
-	// 简化的置信度计算
-	deviation := abs(actualRTP - targetRTP)
-	sampleFactor := min(float64(sampleSize)/10000.0, 1.0)  // 样本越大置信度越高
-	return max(0, 1.0 - deviation*10) * sampleFactor
+// calculateConfidenceLevel 计算实际RTP与目标RTP一致的置信度（双侧检验p值）
+func calculateConfidenceLevel(standardError, actualRTP, targetRTP float64) float64 {
+	if standardError <= 0 {
+		if actualRTP == targetRTP {
+			return 1
+		}
+		return 0
+	}
+	z := abs(actualRTP-targetRTP) / standardError
+	return math.Erfc(z / math.Sqrt2)
 }
 
 func abs(x float64) float64 {
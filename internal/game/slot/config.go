@@ -3,16 +3,16 @@ package slot
 // GetDefaultConfig 获取默认配置（经典水果机）
 func GetDefaultConfig() *SlotConfig {
 	return &SlotConfig{
-		MachineID:  "classic_fruit",
-		Name:       "经典水果机",
-		Rows:       3,
-		Reels:      5,
-		PayLines:   20,
-		MinBet:     10,
-		MaxBet:     10000,
-		DefaultBet: 100,
-		TargetRTP:  0.96, // 96% RTP
-		Volatility: VolatilityMedium,
+		MachineID:      "classic_fruit",
+		Name:           "经典水果机",
+		Rows:           3,
+		Reels:          5,
+		ActivePaylines: 20,
+		MinBet:         10,
+		MaxBet:         10000,
+		DefaultBet:     100,
+		TargetRTP:      0.96, // 96% RTP
+		Volatility:     VolatilityMedium,
 		
 		// 卷轴条配置
 		ReelStrips: []ReelStrip{
@@ -219,6 +219,17 @@ func GetLuckySevenConfig() *SlotConfig {
 			config.PayTables[i].Multiplier *= 2 // 双倍赔率
 		}
 	}
+	config.MaxWin = &MaxWinConfig{Multiplier: 2500} // 单局最高赢取2500倍下注
+	config.Gamble = &GambleConfig{MaxRounds: 5, Limit: &MaxWinConfig{Multiplier: 500}} // 中奖后可博倍，博倍金额不超过500倍下注
+	
+	// 彩金：默认规则之外，一条线上5个7直接中JP3
+	config.Jackpot = DefaultJackpotConfig()
+	config.Jackpot.Rules = append(config.Jackpot.Rules, JackpotRule{
+		Level:    JackpotLevelJP3,
+		Trigger:  JackpotTriggerSymbols,
+		SymbolID: classicSymbolIDs[SymbolSeven],
+		Count:    5,
+	})
 	
 	return config
 }
@@ -230,7 +241,7 @@ func GetMegaFruitConfig() *SlotConfig {
 	config.Name = "超级水果"
 	config.TargetRTP = 0.97
 	config.Volatility = VolatilityLow
-	config.PayLines = 30 // 更多支付线
+	config.ActivePaylines = 30 // 更多支付线
 	
 	// 降低赔率但提高中奖频率
 	for i := range config.PayTables {
@@ -243,16 +254,16 @@ func GetMegaFruitConfig() *SlotConfig {
 // GetDiamondDeluxeConfig 获取钻石豪华版配置
 func GetDiamondDeluxeConfig() *SlotConfig {
 	return &SlotConfig{
-		MachineID:  "diamond_deluxe",
-		Name:       "钻石豪华版",
-		Rows:       4,
-		Reels:      6,
-		PayLines:   50,
-		MinBet:     20,
-		MaxBet:     20000,
-		DefaultBet: 200,
-		TargetRTP:  0.96,
-		Volatility: VolatilityHigh,
+		MachineID:      "diamond_deluxe",
+		Name:           "钻石豪华版",
+		Rows:           4,
+		Reels:          6,
+		ActivePaylines: 50,
+		MinBet:         20,
+		MaxBet:         20000,
+		DefaultBet:     200,
+		TargetRTP:      0.96,
+		Volatility:     VolatilityHigh,
 		
 		// 使用钻石主题的符号...
 		// 这里简化处理，实际应该有完整的钻石主题配置
@@ -265,12 +276,53 @@ func GetDiamondDeluxeConfig() *SlotConfig {
 	}
 }
 
+// GetPharaohConfig 获取法老王配置（5x4全路径，1024路）
+func GetPharaohConfig() *SlotConfig {
+	config := GetDefaultConfig()
+	config.MachineID = "pharaoh"
+	config.Name = "法老王"
+	config.Rows = 4
+	config.WinMode = WinModeWays
+	config.WaysBetUnits = 16 // 总下注按16个单位折算每路赔付
+	config.MinBet = 16
+	config.MaxBet = 16000
+	config.DefaultBet = 160
+	config.MaxWin = &MaxWinConfig{Multiplier: 5000} // 单局最高赢取5000倍下注
+	config.Gamble = &GambleConfig{MaxRounds: 5, Limit: &MaxWinConfig{Multiplier: 500}} // 中奖后可博倍，博倍金额不超过500倍下注
+	
+	// 加注：多付25%下注，分散符号权重翻倍，免费游戏触发率约提高6倍
+	config.ReelSets = []ReelSet{anteReelSet("ante", config.ReelStrips, 2)}
+	config.Ante = &AnteConfig{Multiplier: 1.25, ReelSetID: "ante"}
+	// 购买免费游戏：10倍下注直接获得10次免费旋转
+	config.BuyFeature = &BuyFeatureConfig{PriceMultiplier: 10, FreeSpins: 10}
+	config.Jackpot = DefaultJackpotConfig()
+	
+	return config
+}
+
+// anteReelSet 由基础卷轴条生成加注卷轴组（分散符号权重乘以 scatterFactor）
+func anteReelSet(id string, base []ReelStrip, scatterFactor int) ReelSet {
+	strips := make([]ReelStrip, len(base))
+	for i, strip := range base {
+		weights := make([]int, len(strip.Weights))
+		copy(weights, strip.Weights)
+		for j, symbol := range strip.Symbols {
+			if symbol == SymbolScatter && j < len(weights) {
+				weights[j] *= scatterFactor
+			}
+		}
+		strips[i] = ReelStrip{ReelID: strip.ReelID, Symbols: strip.Symbols, Weights: weights}
+	}
+	return ReelSet{ID: id, Name: "加注", ReelStrips: strips}
+}
+
 // ConfigPresets 预设配置集合
 var ConfigPresets = map[string]*SlotConfig{
 	"classic_fruit":  GetDefaultConfig(),
 	"lucky_seven":    GetLuckySevenConfig(),
 	"mega_fruit":     GetMegaFruitConfig(),
 	"diamond_deluxe": GetDiamondDeluxeConfig(),
+	"pharaoh":        GetPharaohConfig(),
 }
 
 // GetConfigByID 根据ID获取配置
@@ -283,13 +335,17 @@ func GetConfigByID(machineID string) *SlotConfig {
 
 // ValidateConfig 验证配置
 func ValidateConfig(config *SlotConfig) error {
-	if config.Rows < 3 || config.Rows > 5 {
+	maxRows := 5
+	if config.WinMode.IsWays() {
+		maxRows = maxWaysRows
+	}
+	if config.Rows < 3 || config.Rows > maxRows {
 		return ErrInvalidConfig
 	}
 	if config.Reels < 3 || config.Reels > 7 {
 		return ErrInvalidConfig
 	}
-	if config.PayLines < 1 || config.PayLines > 100 {
+	if config.ActivePaylines < 1 || config.ActivePaylines > 100 {
 		return ErrInvalidConfig
 	}
 	if config.TargetRTP < 0.8 || config.TargetRTP > 0.99 {
@@ -301,5 +357,70 @@ func ValidateConfig(config *SlotConfig) error {
 	if len(config.PayTables) == 0 {
 		return ErrInvalidPayTable
 	}
+	if !config.Mode.IsValid() || !config.PayDirection.IsValid() {
+		return ErrInvalidConfig
+	}
+	if err := validateWays(config); err != nil {
+		return err
+	}
+	if err := validatePaylines(config); err != nil {
+		return err
+	}
+	if err := validateReelSets(config); err != nil {
+		return err
+	}
+	if err := config.freeGameConfig().validate(config); err != nil {
+		return err
+	}
+	if err := config.bonusGameConfig().validate(); err != nil {
+		return err
+	}
+	if err := config.MaxWin.validate(); err != nil {
+		return err
+	}
+	if err := config.Gamble.validate(); err != nil {
+		return err
+	}
+	if err := config.Ante.validate(config); err != nil {
+		return err
+	}
+	if err := config.BuyFeature.validate(config); err != nil {
+		return err
+	}
+	if err := config.Jackpot.validate(); err != nil {
+		return err
+	}
+	
+	// 配置了理论RTP范围时，精确计算并校验
+	if config.RTPBand != nil {
+		theory, err := CalculateTheoreticalRTP(config)
+		if err != nil {
+			return err
+		}
+		if theory.TotalRTP < config.RTPBand.Min || theory.TotalRTP > config.RTPBand.Max {
+			return ErrRTPOutOfRange
+		}
+	}
+	return nil
+}
+
+// validatePaylines 验证支付线定义：条数与启用的支付线数一致，每条线覆盖所有卷轴，行索引在范围内
+func validatePaylines(config *SlotConfig) error {
+	if len(config.Paylines) == 0 {
+		return nil
+	}
+	if len(config.Paylines) != config.ActivePaylines {
+		return ErrInvalidPaylines
+	}
+	for _, line := range config.Paylines {
+		if len(line) != config.Reels {
+			return ErrInvalidPaylines
+		}
+		for _, row := range line {
+			if row < 0 || row >= config.Rows {
+				return ErrInvalidPaylines
+			}
+		}
+	}
 	return nil
 }
\ No newline at end of file
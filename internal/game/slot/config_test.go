@@ -28,8 +28,8 @@ func TestGetDefaultConfig(t *testing.T) {
 		t.Errorf("Reels = %v, want 5", config.Reels)
 	}
 
-	if config.PayLines != 20 {
-		t.Errorf("PayLines = %v, want 20", config.PayLines)
+	if config.ActivePaylines != 20 {
+		t.Errorf("ActivePaylines = %v, want 20", config.ActivePaylines)
 	}
 
 	if config.TargetRTP != 0.96 {
@@ -137,8 +137,8 @@ func TestGetMegaFruitConfig(t *testing.T) {
 		t.Errorf("Volatility = %v, want VolatilityLow", config.Volatility)
 	}
 
-	if config.PayLines != 30 {
-		t.Errorf("PayLines = %v, want 30", config.PayLines)
+	if config.ActivePaylines != 30 {
+		t.Errorf("ActivePaylines = %v, want 30", config.ActivePaylines)
 	}
 
 	// 验证赔率是否降低
@@ -171,8 +171,8 @@ func TestGetDiamondDeluxeConfig(t *testing.T) {
 		t.Errorf("Reels = %v, want 6", config.Reels)
 	}
 
-	if config.PayLines != 50 {
-		t.Errorf("PayLines = %v, want 50", config.PayLines)
+	if config.ActivePaylines != 50 {
+		t.Errorf("ActivePaylines = %v, want 50", config.ActivePaylines)
 	}
 
 	if config.MinBet != 20 {
@@ -188,12 +188,35 @@ func TestGetDiamondDeluxeConfig(t *testing.T) {
 	}
 }
 
+func TestGetPharaohConfig(t *testing.T) {
+	config := GetPharaohConfig()
+
+	if config.MachineID != "pharaoh" || !config.WinMode.IsWays() {
+		t.Errorf("MachineID = %v, WinMode = %v, want pharaoh ways", config.MachineID, config.WinMode)
+	}
+	if config.Rows != 4 || config.Reels != 5 {
+		t.Errorf("grid = %vx%v, want 5x4", config.Reels, config.Rows)
+	}
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("ValidateConfig failed: %v", err)
+	}
+
+	theory, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if theory.TotalRTP < 0.9 || theory.TotalRTP > 1.0 {
+		t.Errorf("theoretical RTP = %.4f, want within [0.9, 1.0]", theory.TotalRTP)
+	}
+}
+
 func TestConfigPresets(t *testing.T) {
 	expectedPresets := []string{
 		"classic_fruit",
 		"lucky_seven",
 		"mega_fruit",
 		"diamond_deluxe",
+		"pharaoh",
 	}
 
 	for _, preset := range expectedPresets {
@@ -266,12 +289,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "行数过少",
 			config: &SlotConfig{
-				Rows:       2,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           2,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidConfig,
@@ -279,12 +302,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "行数过多",
 			config: &SlotConfig{
-				Rows:       6,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           6,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidConfig,
@@ -292,12 +315,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "卷轴数过少",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      2,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips[:2],
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          2,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips[:2],
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidConfig,
@@ -305,12 +328,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "卷轴数过多",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      8,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          8,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidConfig,
@@ -318,12 +341,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "支付线过少",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   0,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 0,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidConfig,
@@ -331,12 +354,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "支付线过多",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   101,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 101,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidConfig,
@@ -344,12 +367,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "RTP过低",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  0.79,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      0.79,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidRTP,
@@ -357,12 +380,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "RTP过高",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  1.0,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      1.0,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidRTP,
@@ -370,12 +393,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "卷轴条数量不匹配",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips[:3], // 只有3个，需要5个
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips[:3], // 只有3个，需要5个
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 			errType: ErrInvalidReelStrips,
@@ -383,12 +406,12 @@ func TestValidateConfig(t *testing.T) {
 		{
 			name: "空赔率表",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  []PayTable{},
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      []PayTable{},
 			},
 			wantErr: true,
 			errType: ErrInvalidPayTable,
@@ -478,4 +501,57 @@ func TestFeatureConfiguration(t *testing.T) {
 			t.Errorf("Missing expected feature: %v", expected)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestValidateConfig_Mode(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Mode = "forced"
+	if err := ValidateConfig(config); err != ErrInvalidConfig {
+		t.Errorf("ValidateConfig() error = %v, want %v", err, ErrInvalidConfig)
+	}
+}
+
+func TestValidateConfig_Paylines(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(c *SlotConfig)
+		expected error
+	}{
+		{"未声明使用内置支付线", func(c *SlotConfig) {}, nil},
+		{"有效声明", func(c *SlotConfig) {
+			c.ActivePaylines = 2
+			c.Paylines = [][]int{{1, 1, 1, 1, 1}, {0, 1, 2, 1, 0}}
+		}, nil},
+		{"声明数量不足", func(c *SlotConfig) {
+			c.ActivePaylines = 3
+			c.Paylines = [][]int{{1, 1, 1, 1, 1}, {0, 1, 2, 1, 0}}
+		}, ErrInvalidPaylines},
+		{"声明数量多于启用数", func(c *SlotConfig) {
+			c.ActivePaylines = 1
+			c.Paylines = [][]int{{1, 1, 1, 1, 1}, {0, 1, 2, 1, 0}}
+		}, ErrInvalidPaylines},
+		{"长度与卷轴数不一致", func(c *SlotConfig) {
+			c.ActivePaylines = 1
+			c.Paylines = [][]int{{1, 1, 1, 1}}
+		}, ErrInvalidPaylines},
+		{"行号越界", func(c *SlotConfig) {
+			c.ActivePaylines = 1
+			c.Paylines = [][]int{{0, 1, 3, 1, 0}}
+		}, ErrInvalidPaylines},
+		{"无效派彩方向", func(c *SlotConfig) {
+			c.PayDirection = "up"
+		}, ErrInvalidConfig},
+		{"双向派彩", func(c *SlotConfig) {
+			c.PayDirection = PayDirectionBoth
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(config)
+			if err := ValidateConfig(config); err != tt.expected {
+				t.Errorf("ValidateConfig() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
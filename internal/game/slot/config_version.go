@@ -0,0 +1,53 @@
+package slot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalConfig 序列化配置（配置版本以该序列化结果计算哈希并保存）
+func MarshalConfig(config *SlotConfig) ([]byte, error) {
+	if config == nil {
+		return nil, ErrInvalidConfig
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %w", err)
+	}
+	return data, nil
+}
+
+// ConfigHash 计算配置哈希（SHA-256），用于在旋转结果中标识所用的数学模型
+func ConfigHash(config *SlotConfig) (string, error) {
+	data, err := MarshalConfig(config)
+	if err != nil {
+		return "", err
+	}
+	return hashConfigData(data), nil
+}
+
+// hashConfigData 计算已序列化配置的哈希
+func hashConfigData(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// CloneConfig 深拷贝配置（内置预设返回共享指针，修改前需先拷贝）
+func CloneConfig(config *SlotConfig) (*SlotConfig, error) {
+	data, err := MarshalConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig 从JSON解析配置
+func ParseConfig(data []byte) (*SlotConfig, error) {
+	config := &SlotConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+	return config, nil
+}
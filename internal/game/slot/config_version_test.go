@@ -0,0 +1,132 @@
+package slot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfigHash(t *testing.T) {
+	config := GetDefaultConfig()
+	hash, err := ConfigHash(config)
+	if err != nil {
+		t.Fatalf("ConfigHash failed: %v", err)
+	}
+	if len(hash) != 64 {
+		t.Fatalf("hash = %q, want sha256 hex", hash)
+	}
+
+	// 拷贝后哈希不变，修改数学参数后哈希改变
+	clone, err := CloneConfig(config)
+	if err != nil {
+		t.Fatalf("CloneConfig failed: %v", err)
+	}
+	if got, _ := ConfigHash(clone); got != hash {
+		t.Errorf("clone hash = %s, want %s", got, hash)
+	}
+	clone.ReelStrips[0].Weights[0]++
+	if got, _ := ConfigHash(clone); got == hash {
+		t.Error("changing a reel weight should change the hash")
+	}
+	if config.ReelStrips[0].Weights[0] == clone.ReelStrips[0].Weights[0] {
+		t.Error("CloneConfig should not share reel strips with the original")
+	}
+}
+
+func TestSlotEngine_ReloadConfig(t *testing.T) {
+	config := GetDefaultConfig()
+	config.SeededRNG = true
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	oldHash := engine.ConfigHash()
+
+	before, err := engine.Spin(1, "reload", 100)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if before.ConfigHash != oldHash {
+		t.Errorf("result hash = %s, want %s", before.ConfigHash, oldHash)
+	}
+
+	// 无效配置被拒绝，继续使用原配置
+	invalid, _ := CloneConfig(config)
+	invalid.ReelStrips = invalid.ReelStrips[:2]
+	if err := engine.ReloadConfig(invalid); err == nil {
+		t.Fatal("ReloadConfig should reject an invalid config")
+	}
+	if engine.ConfigHash() != oldHash {
+		t.Fatal("rejected config must not replace the current one")
+	}
+
+	updated, _ := CloneConfig(config)
+	updated.MaxBet = 500
+	if err := engine.ReloadConfig(updated); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	newHash := engine.ConfigHash()
+	if newHash == oldHash || engine.GetConfig().MaxBet != 500 {
+		t.Fatalf("config not swapped: hash %s, max bet %d", newHash, engine.GetConfig().MaxBet)
+	}
+	if _, err := engine.Spin(1, "reload", 1000); err != ErrInvalidBet {
+		t.Errorf("Spin over new max bet err = %v, want ErrInvalidBet", err)
+	}
+
+	after, err := engine.Spin(1, "reload", 100)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if after.ConfigHash != newHash {
+		t.Errorf("result hash = %s, want %s", after.ConfigHash, newHash)
+	}
+	if session := engine.GetSession("reload"); session == nil || session.SpinCount != 2 {
+		t.Errorf("session should survive reload, got %+v", session)
+	}
+
+	// 旧配置的结果需在对应版本上重放
+	if _, err := engine.Replay(before); !errors.Is(err, ErrConfigMismatch) {
+		t.Errorf("Replay with old config err = %v, want ErrConfigMismatch", err)
+	}
+	if _, err := engine.Replay(after); err != nil {
+		t.Errorf("Replay failed: %v", err)
+	}
+}
+
+func TestSlotEngine_ReloadConfigConcurrentSpins(t *testing.T) {
+	config := GetDefaultConfig()
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	hashes := map[string]bool{engine.ConfigHash(): true}
+
+	updated, _ := CloneConfig(config)
+	updated.TargetRTP = 0.95
+	newHash, _ := ConfigHash(updated)
+	hashes[newHash] = true
+
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 200; i++ {
+			result, err := engine.Spin(2, "concurrent", 100)
+			if err != nil {
+				done <- err
+				return
+			}
+			if !hashes[result.ConfigHash] {
+				done <- errors.New("result references unknown config hash " + result.ConfigHash)
+				return
+			}
+		}
+		done <- nil
+	}()
+	if err := engine.ReloadConfig(updated); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if engine.ConfigHash() != newHash {
+		t.Error("engine should end on the reloaded config")
+	}
+}
@@ -16,18 +16,50 @@ var (
 	ErrInvalidRTP         = errors.New("无效的RTP设置")
 	ErrInvalidReelStrips  = errors.New("无效的卷轴条配置")
 	ErrInvalidPayTable    = errors.New("无效的赔率表")
+	ErrInvalidPaylines    = errors.New("无效的支付线定义")
 	ErrEngineNotReady     = errors.New("引擎未就绪")
+	ErrReplayUnavailable  = errors.New("结果不含种子，无法重放")
+	ErrRTPOutOfRange      = errors.New("理论RTP超出允许范围")
+	ErrReelSetRTPMismatch = errors.New("卷轴组理论RTP与认证值不符")
+	ErrUnknownReelSet     = errors.New("未知的卷轴组")
+	ErrInvalidFreeGame    = errors.New("无效的免费游戏配置")
+	ErrInvalidBonusGame   = errors.New("无效的奖励游戏配置")
+	ErrBonusInProgress    = errors.New("奖励游戏进行中，请先完成选择")
+	ErrNoActiveBonus      = errors.New("没有进行中的奖励游戏")
+	ErrInvalidBonusPick   = errors.New("无效的奖励游戏选择")
+	ErrConfigMismatch     = errors.New("结果使用的配置版本与当前配置不符")
+	ErrInvalidThemePack   = errors.New("无效的主题包")
+	ErrThemeSymbolMissing = errors.New("主题缺少算法配置中的符号")
+	ErrInvalidMaxWin      = errors.New("无效的最高赢取配置")
+	ErrInvalidGamble      = errors.New("无效的博倍配置")
+	ErrNoActiveGamble     = errors.New("没有进行中的博倍")
+	ErrInvalidGambleGuess = errors.New("无效的博倍竞猜")
+	ErrGambleLimitReached = errors.New("已达到博倍次数或金额上限")
+	ErrGambleInProgress   = errors.New("博倍进行中，请先收分")
+	ErrInvalidAnte        = errors.New("无效的加注配置")
+	ErrAnteUnavailable    = errors.New("该机台不支持加注")
+	ErrInvalidBuyFeature  = errors.New("无效的购买免费游戏配置")
+	ErrBuyUnavailable     = errors.New("该机台不支持购买免费游戏")
+	ErrFreeGameInProgress = errors.New("免费游戏进行中")
+	ErrInvalidJackpot     = errors.New("无效的彩金配置")
+	ErrJackpotSeedKey     = errors.New("未配置必中点种子的加密密钥")
+	ErrInvalidCascade     = errors.New("无效的消除式配置")
 )
 
 // SlotEngine 老虎机游戏引擎
 type SlotEngine struct {
 	mu             sync.RWMutex
 	config         *SlotConfig
+	configHash     string               // 当前配置的哈希（随旋转结果记录）
 	rtpController  RTPController
 	patternMatcher PatternMatcher
-	randomGen      RandomGenerator
+	randomGen      RandomGenerator      // 注入的随机源（种子模式下用于生成每次旋转的种子）
+	spinRNG        *DRBGRandomGenerator // 种子模式下每次旋转重新播种的生成器
+	reelSets       []ReelSet            // 认证卷轴组（已填充理论RTP）
 	statistics     *Statistics
+	theoryRTP      map[bool]float64     // 按配置计算的理论RTP缓存（键为是否加注，配置重载时清空）
 	sessionData    map[string]*SessionData
+	manualBonus    bool                 // 奖励游戏由玩家选择（否则在旋转内自动完成）
 	isRunning      bool
 }
 
@@ -38,7 +70,10 @@ type SessionData struct {
 	TotalBet       int64
 	TotalWin       int64
 	SpinCount      int
-	FreeSpinsLeft  int
+	ReelSetID      string
+	FreeGameState  // 免费游戏状态（剩余次数、累计次数与赢取等）
+	BonusGame      *BonusGameState // 等待玩家选择的奖励游戏（交互模式）
+	Gamble         *GambleState    // 等待竞猜或收分的博倍
 	LastSpinResult *SpinResult
 	CreatedAt      time.Time
 	LastActiveAt   time.Time
@@ -62,23 +97,94 @@ func NewSlotEngine(config *SlotConfig) (*SlotEngine, error) {
 		return nil, err
 	}
 	
+	configHash, err := ConfigHash(config)
+	if err != nil {
+		return nil, err
+	}
+	
 	engine := &SlotEngine{
 		config:         config,
-		rtpController:  NewDynamicRTPController(config.TargetRTP),
-		patternMatcher: NewAdvancedPatternMatcher(config),
+		configHash:     configHash,
+		patternMatcher: NewPatternMatcher(config),
 		randomGen:      NewCryptoRandomGenerator(),
+		reelSets:       certifiedReelSets(config),
 		statistics: &Statistics{
 			LastUpdate: time.Now(),
 		},
 		sessionData: make(map[string]*SessionData),
 		isRunning:   true,
 	}
+	engine.rtpController = newRTPController(config, engine.reelSets)
+	engine.syncControllerRandom()
+	
+	// 种子模式
+	if config.SeededRNG {
+		engine.spinRNG = NewDRBGRandomGenerator(0)
+	}
 	
 	return engine, nil
 }
 
+// newRTPController 按配置创建RTP控制器
+// 纯数学模式下配置了多个卷轴组时，通过选择卷轴组控制RTP
+func newRTPController(config *SlotConfig, reelSets []ReelSet) RTPController {
+	if config.Mode.IsPureMath() && len(reelSets) > 0 {
+		return NewReelSetController(config.TargetRTP)
+	}
+	return NewDynamicRTPController(config.TargetRTP)
+}
+
+// ReloadConfig 热加载配置：校验新配置后原子替换
+// 旋转全程持有引擎锁，进行中的旋转在旧配置上完成，之后的旋转使用新配置；
+// 会话数据（免费游戏、奖励游戏等）与累计统计保留，RTP控制器按新配置重建
+func (e *SlotEngine) ReloadConfig(config *SlotConfig) error {
+	if err := ValidateConfig(config); err != nil {
+		return err
+	}
+	configHash, err := ConfigHash(config)
+	if err != nil {
+		return err
+	}
+	
+	// 在锁外完成耗时的准备工作（卷轴组理论RTP计算等）
+	patternMatcher := NewPatternMatcher(config)
+	reelSets := certifiedReelSets(config)
+	rtpController := newRTPController(config, reelSets)
+	
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	e.config = config
+	e.configHash = configHash
+	e.patternMatcher = patternMatcher
+	e.reelSets = reelSets
+	e.rtpController = rtpController
+	e.theoryRTP = nil
+	e.syncControllerRandom()
+	
+	if config.SeededRNG && e.spinRNG == nil {
+		e.spinRNG = NewDRBGRandomGenerator(0)
+	} else if !config.SeededRNG {
+		e.spinRNG = nil
+	}
+	
+	return nil
+}
+
+// ConfigHash 获取当前配置的哈希
+func (e *SlotEngine) ConfigHash() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.configHash
+}
+
 // Spin 执行旋转
 func (e *SlotEngine) Spin(userID uint, sessionID string, betAmount int64) (*SpinResult, error) {
+	return e.SpinWithAnte(userID, sessionID, betAmount, false)
+}
+
+// SpinWithAnte 执行旋转，ante 为真时按加注模式扣费并使用加注卷轴组（免费旋转不加注）
+func (e *SlotEngine) SpinWithAnte(userID uint, sessionID string, betAmount int64, ante bool) (*SpinResult, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	
@@ -86,6 +192,9 @@ func (e *SlotEngine) Spin(userID uint, sessionID string, betAmount int64) (*Spin
 	if !e.isRunning {
 		return nil, ErrEngineNotReady
 	}
+	if ante && e.config.Ante == nil {
+		return nil, ErrAnteUnavailable
+	}
 	
 	// 验证下注金额
 	if betAmount < e.config.MinBet || betAmount > e.config.MaxBet {
@@ -95,30 +204,194 @@ func (e *SlotEngine) Spin(userID uint, sessionID string, betAmount int64) (*Spin
 	// 获取或创建会话数据
 	session := e.getOrCreateSession(userID, sessionID)
 	
+	// 奖励游戏未完成前不能继续旋转
+	if session.BonusGame.IsActive() {
+		return nil, ErrBonusInProgress
+	}
+	
+	// 博倍收分前不能继续旋转
+	if session.Gamble.IsActive() {
+		return nil, ErrGambleInProgress
+	}
+	
 	// 生成结果ID
 	resultID := e.generateResultID()
 	
-	// 判断是否免费旋转
-	isFreeSpins := session.FreeSpinsLeft > 0
-	if isFreeSpins {
-		session.FreeSpinsLeft--
-		betAmount = 0 // 免费旋转不扣费
+	// 判断是否免费旋转（免费旋转不扣费，按触发时的下注额计算赔付）
+	freeGame := &session.FreeGameState
+	freeConfig := e.config.freeGameConfig()
+	isFreeSpin := freeGame.IsActive()
+	payBet := betAmount
+	if isFreeSpin {
+		freeGame.Resume(betAmount)
+		freeGame.Next()
+		payBet = freeGame.TriggerBet
+		betAmount = 0
+		ante = false
+	}
+	
+	// 加注时按加注倍数扣费，派彩仍按原下注额
+	stake := betAmount
+	if ante {
+		stake = e.config.Ante.Cost(betAmount)
 	}
 	
 	// 更新统计
 	e.statistics.TotalSpins++
-	e.statistics.TotalBet += betAmount
-	session.TotalBet += betAmount
+	e.statistics.TotalBet += stake
+	session.TotalBet += stake
 	session.SpinCount++
 	
 	// 获取当前RTP
 	currentRTP := e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
 	
-	// 判断是否应该触发中奖
-	shouldWin := e.rtpController.ShouldTriggerWin(currentRTP, e.config.TargetRTP, betAmount)
+	// 自适应模式下由RTP控制器决定是否倾向中奖及赔付补偿倍率
+	// 纯数学模式下结果仅由卷轴条和随机数决定，不参考历史结果
+	shouldWin := false
+	compensation := 0.0
+	if !e.config.Mode.IsPureMath() {
+		shouldWin = e.rtpController.ShouldTriggerWin(currentRTP, e.config.TargetRTP, payBet)
+		if !shouldWin {
+			compensation = e.rtpController.GetCompensationMultiplier(currentRTP, e.config.TargetRTP)
+		}
+	}
+	
+	// 选择本次旋转的随机源（种子模式下为每次旋转重新播种）
+	outcomeRNG := e.randomGen
+	var seed int64
+	if e.spinRNG != nil {
+		seed = nextSpinSeed(e.randomGen)
+		e.spinRNG.Seed(seed)
+		outcomeRNG = e.spinRNG
+	}
+	
+	// 选择卷轴组（免费游戏配置了独立卷轴组时使用该卷轴组，加注时使用加注卷轴组）
+	reelSetID, reelStrips := e.freeGameReelSet(freeGame, isFreeSpin)
+	if ante {
+		reelSetID = e.config.Ante.ReelSetID
+		reelStrips, _ = e.reelStripsFor(reelSetID)
+	}
+	if reelStrips == nil {
+		reelSetID, reelStrips = e.selectReelSet(session)
+	}
+	
+	// 计算旋转结果
+	outcome := e.evaluateSpin(outcomeRNG, reelStrips, payBet, shouldWin, compensation, !e.manualBonus)
+	winAmount := outcome.winAmount
 	
+	// 推进免费游戏：免费旋转中可再触发，基础旋转触发新一轮
+	// 免费游戏累计赢取达到最高赢取时提前结束本轮
+	freeSpinsAwarded := 0
+	maxWinReached := outcome.maxWinReached
+	if isFreeSpin {
+		winAmount = int64(float64(winAmount) * freeGame.Multiplier)
+		var roundCapped bool
+		winAmount, roundCapped = e.config.MaxWin.ApplyRound(freeGame.FreeGameWin, winAmount, payBet)
+		if roundCapped || maxWinReached {
+			maxWinReached = true
+			freeGame.Stop()
+		} else {
+			freeSpinsAwarded = freeGame.Retrigger(outcome.freeSpins, freeConfig)
+		}
+		freeGame.Record(winAmount)
+	} else if outcome.freeSpins > 0 {
+		freeSpinsAwarded = freeGame.Trigger(outcome.freeSpins, betAmount, freeConfig)
+	}
+	
+	// 检查是否中大奖
+	if winAmount > payBet*100 { // 提高jackpot阈值到100倍
+		e.statistics.JackpotHits++
+	}
+	if winAmount > payBet*20 {
+		e.statistics.BigWins++
+	}
+	
+	// 更新统计
+	e.statistics.TotalWin += winAmount
+	session.TotalWin += winAmount
+	e.statistics.FreeSpinsTotal += freeSpinsAwarded
+	e.statistics.CurrentRTP = e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
+	e.statistics.LastUpdate = time.Now()
+	
+	// 更新RTP控制器历史
+	if rtpCtrl, ok := e.rtpController.(*DynamicRTPController); ok {
+		rtpCtrl.UpdateHistory(stake, winAmount)
+	}
+	if selector, ok := e.rtpController.(ReelSetSelector); ok {
+		selector.RecordSpin(reelSetID, stake, winAmount)
+	}
+	
+	// 创建旋转结果
+	result := &SpinResult{
+		ID:           resultID,
+		SessionID:    sessionID,
+		UserID:       userID,
+		BetAmount:    stake,
+		WinAmount:    winAmount,
+		Multiplier:   float64(winAmount) / float64(payBet+1), // 避免除零
+		Reels:        outcome.reels,
+		WinLines:     outcome.winLines,
+		Features:     outcome.features,
+		FreeSpins:    freeSpinsAwarded,
+		IsJackpot:    outcome.isJackpot,
+		RTP:          e.statistics.CurrentRTP,
+		ReelSetID:    reelSetID,
+		ConfigHash:   e.configHash,
+		Ways:         outcome.ways,
+		IsFreeSpin:   isFreeSpin,
+		Ante:         ante,
+		MaxWinReached: maxWinReached,
+		Anticipation:  outcome.anticipation,
+		Seed:         seed,
+		FavorWin:     shouldWin,
+		Compensation: compensation,
+		Timestamp:    time.Now(),
+	}
+	
+	if freeGame.Phase != FreeGamePhaseIdle {
+		snapshot := *freeGame
+		result.FreeGame = &snapshot
+	}
+	
+	// 奖励游戏：自动模式下已计入赢取，交互模式下保存到会话等待玩家选择
+	if bonus := outcome.bonusGame; bonus != nil {
+		bonus.ID = resultID
+		result.BonusGame = bonus
+		if bonus.IsActive() {
+			session.BonusGame = bonus
+			result.BonusGame = bonus.View()
+		}
+	}
+	
+	// 中奖后提供博倍（收分前赢取不结算）
+	e.offerGamble(session, result, payBet)
+	
+	// 保存结果到会话
+	session.LastSpinResult = result
+	session.LastActiveAt = time.Now()
+	
+	return result, nil
+}
+
+// spinOutcome 单次旋转的结果数据（仅由随机源和输入决定）
+type spinOutcome struct {
+	ways      int
+	reels     [][]Symbol
+	winLines  []WinLine
+	features  []Feature
+	winAmount int64
+	freeSpins int
+	isJackpot bool
+	bonusGame *BonusGameState
+	maxWinReached bool
+	anticipation  *Anticipation
+}
+
+// evaluateSpin 使用给定随机源计算旋转结果，不修改引擎和会话状态
+// autoBonus 为真时触发的奖励游戏立即自动完成并计入赢取，否则只生成格子等待玩家选择
+func (e *SlotEngine) evaluateSpin(rng RandomGenerator, reelStrips []ReelStrip, betAmount int64, favorWin bool, compensation float64, autoBonus bool) *spinOutcome {
 	// 生成卷轴结果
-	reels := e.generateReels(shouldWin)
+	reels := e.generateReels(rng, reelStrips, favorWin)
 	
 	// 查找中奖线
 	winLines := e.patternMatcher.FindWinningLines(reels, e.config)
@@ -126,100 +399,224 @@ func (e *SlotEngine) Spin(userID uint, sessionID string, betAmount int64) (*Spin
 	// 计算赔付
 	winAmount := e.patternMatcher.CalculatePayout(winLines, betAmount)
 	
-	// 应用RTP补偿
-	if shouldWin && winAmount == 0 {
-		// 强制产生小奖（降低倍率以控制RTP）
-		winAmount = betAmount / 2 // 0.5倍赔付
-		winLines = e.forceSmallWin(reels)
-	} else if !shouldWin && winAmount > 0 {
-		// 减少赔付
-		compensationMultiplier := e.rtpController.GetCompensationMultiplier(currentRTP, e.config.TargetRTP)
-		winAmount = int64(float64(winAmount) * compensationMultiplier * 0.8) // 额外降低20%
+	// 应用RTP补偿（仅自适应模式，纯数学模式下赔付完全由盘面决定）
+	if !e.config.Mode.IsPureMath() {
+		if favorWin && winAmount == 0 {
+			// 强制产生小奖（降低倍率以控制RTP）
+			winAmount = betAmount / 2 // 0.5倍赔付
+			winLines = e.forceSmallWin(reels)
+		} else if !favorWin && winAmount > 0 {
+			// 减少赔付
+			winAmount = int64(float64(winAmount) * compensation * 0.8) // 额外降低20%
+		}
 	}
 	
 	// 检测特殊功能
 	features := e.patternMatcher.DetectFeatures(reels, e.config)
 	
+	// 检测期待效果（慢停卷轴和差一点中奖）
+	anticipation := e.patternMatcher.DetectAnticipation(reels, winLines)
+	
 	// 处理特殊功能
 	freeSpinsAwarded := 0
 	isJackpot := false
+	var bonusGame *BonusGameState
 	for _, feature := range features {
 		switch feature.Type {
 		case FeatureTypeFreeSpins:
 			if spins, ok := feature.Value.(int); ok {
 				freeSpinsAwarded += spins
-				session.FreeSpinsLeft += spins
 			}
 		case FeatureTypeMultiplier:
 			if multiplier, ok := feature.Value.(float64); ok {
 				winAmount = int64(float64(winAmount) * multiplier)
 			}
 		case FeatureTypeBonus:
-			// 奖励游戏可以获得额外奖金
-			bonusWin := e.calculateBonusWin(betAmount)
-			winAmount += bonusWin
-			if bonusWin > betAmount*50 { // 降低jackpot阈值
-				isJackpot = true
+			// 选择奖励游戏：格子由本次旋转的随机源生成，可通过种子重放
+			bonusGame = NewBonusGame(e.config.bonusGameConfig(), rng, betAmount)
+			if autoBonus {
+				bonusWin := bonusGame.AutoPlay()
+				winAmount += bonusWin
+				if bonusWin > betAmount*50 { // 降低jackpot阈值
+					isJackpot = true
+				}
 			}
 		}
 	}
 	
-	// 检查是否中大奖
-	if winAmount > betAmount*100 { // 提高jackpot阈值到100倍
+	// 最高赢取限制：达到上限时本局结束，不再进入免费游戏或待选择的奖励游戏
+	winAmount, maxWinReached := e.config.MaxWin.Apply(winAmount, betAmount)
+	if maxWinReached {
+		freeSpinsAwarded = 0
+		if !autoBonus {
+			bonusGame = nil
+		}
+	}
+	
+	if winAmount > betAmount*100 {
 		isJackpot = true
-		e.statistics.JackpotHits++
 	}
-	if winAmount > betAmount*20 {
-		e.statistics.BigWins++
+	
+	// 全路径模式下记录盘面路数
+	ways := 0
+	if e.config.WinMode.IsWays() {
+		ways = 1
+		for _, column := range reels {
+			ways *= len(column)
+		}
 	}
 	
-	// 更新统计
-	e.statistics.TotalWin += winAmount
-	session.TotalWin += winAmount
-	e.statistics.FreeSpinsTotal += freeSpinsAwarded
-	e.statistics.CurrentRTP = e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
-	e.statistics.LastUpdate = time.Now()
+	return &spinOutcome{
+		ways:      ways,
+		reels:     reels,
+		winLines:  winLines,
+		features:  features,
+		winAmount: winAmount,
+		freeSpins: freeSpinsAwarded,
+		isJackpot: isJackpot,
+		bonusGame: bonusGame,
+		maxWinReached: maxWinReached,
+		anticipation:  anticipation,
+	}
+}
+
+// Replay 根据记录的种子重放一次旋转，用于争议核查
+// 返回的结果仅包含由种子决定的卷轴、中奖线和赔付，不影响统计和会话
+func (e *SlotEngine) Replay(original *SpinResult) (*SpinResult, error) {
+	if original == nil || original.Seed == 0 {
+		return nil, ErrReplayUnavailable
+	}
 	
-	// 更新RTP控制器历史
-	if rtpCtrl, ok := e.rtpController.(*DynamicRTPController); ok {
-		rtpCtrl.UpdateHistory(betAmount, winAmount)
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	
+	// 配置热加载后需使用结果记录的配置版本重放
+	if original.ConfigHash != "" && original.ConfigHash != e.configHash {
+		return nil, ErrConfigMismatch
 	}
 	
-	// 创建旋转结果
-	result := &SpinResult{
-		ID:         resultID,
-		SessionID:  sessionID,
-		UserID:     userID,
-		BetAmount:  betAmount,
-		WinAmount:  winAmount,
-		Multiplier: float64(winAmount) / float64(betAmount+1), // 避免除零
-		Reels:      reels,
-		WinLines:   winLines,
-		Features:   features,
-		FreeSpins:  freeSpinsAwarded,
-		IsJackpot:  isJackpot,
-		RTP:        e.statistics.CurrentRTP,
-		Timestamp:  time.Now(),
+	reelStrips, ok := e.reelStripsFor(original.ReelSetID)
+	if !ok {
+		return nil, ErrUnknownReelSet
 	}
 	
-	// 保存结果到会话
-	session.LastSpinResult = result
-	session.LastActiveAt = time.Now()
+	// 免费旋转按触发时的下注额和倍率计算，加注旋转按原下注额计算
+	payBet := original.BetAmount
+	multiplier := 1.0
+	if original.Ante && e.config.Ante != nil {
+		payBet = e.config.Ante.baseBet(original.BetAmount)
+	}
+	if original.IsFreeSpin && original.FreeGame != nil {
+		payBet = original.FreeGame.TriggerBet
+		multiplier = original.FreeGame.Multiplier
+	}
 	
-	return result, nil
+	// 交互模式的奖励游戏只重放格子内容，选择过程以记录为准
+	autoBonus := original.BonusGame == nil || original.BonusGame.AutoPlayed
+	
+	rng := NewDRBGRandomGenerator(original.Seed)
+	outcome := e.evaluateSpin(rng, reelStrips, payBet, original.FavorWin, original.Compensation, autoBonus)
+	if original.IsFreeSpin {
+		outcome.winAmount = int64(float64(outcome.winAmount) * multiplier)
+		
+		// 免费游戏按本次旋转之前的累计赢取应用最高赢取
+		var roundCapped bool
+		roundWin := original.FreeGame.FreeGameWin - original.WinAmount
+		outcome.winAmount, roundCapped = e.config.MaxWin.ApplyRound(roundWin, outcome.winAmount, payBet)
+		outcome.maxWinReached = outcome.maxWinReached || roundCapped
+	}
+	
+	return &SpinResult{
+		ID:           original.ID,
+		SessionID:    original.SessionID,
+		UserID:       original.UserID,
+		BetAmount:    original.BetAmount,
+		WinAmount:    outcome.winAmount,
+		Multiplier:   float64(outcome.winAmount) / float64(payBet+1),
+		Reels:        outcome.reels,
+		WinLines:     outcome.winLines,
+		Features:     outcome.features,
+		FreeSpins:    outcome.freeSpins,
+		IsJackpot:    outcome.isJackpot,
+		ReelSetID:    original.ReelSetID,
+		ConfigHash:   e.configHash,
+		Ways:         outcome.ways,
+		IsFreeSpin:   original.IsFreeSpin,
+		Ante:         original.Ante,
+		MaxWinReached: outcome.maxWinReached,
+		Anticipation:  outcome.anticipation,
+		FreeGame:     original.FreeGame,
+		BonusGame:    outcome.bonusGame,
+		Seed:         original.Seed,
+		FavorWin:     original.FavorWin,
+		Compensation: original.Compensation,
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// selectReelSet 选择本次旋转使用的卷轴组
+// RTP控制器实现了 ReelSetSelector 时由其选择（加注专用卷轴组不参与），否则使用默认卷轴组
+func (e *SlotEngine) selectReelSet(session *SessionData) (string, []ReelStrip) {
+	reelSetID := DefaultReelSetID
+	if selector, ok := e.rtpController.(ReelSetSelector); ok && len(e.reelSets) > 0 {
+		reelSetID = selector.SelectReelSet(e.config.Ante.baseReelSets(e.reelSets), session.ReelSetID)
+	}
+	
+	reelStrips, ok := e.reelStripsFor(reelSetID)
+	if !ok {
+		reelSetID, reelStrips = DefaultReelSetID, e.config.ReelStrips
+	}
+	session.ReelSetID = reelSetID
+	return reelSetID, reelStrips
+}
+
+// freeGameReelSet 免费旋转使用的独立卷轴组（未配置或非免费旋转时返回空）
+func (e *SlotEngine) freeGameReelSet(freeGame *FreeGameState, isFreeSpin bool) (string, []ReelStrip) {
+	if !isFreeSpin || freeGame.ReelSetID == "" {
+		return "", nil
+	}
+	reelStrips, ok := e.reelStripsFor(freeGame.ReelSetID)
+	if !ok {
+		return "", nil
+	}
+	return freeGame.ReelSetID, reelStrips
+}
+
+// reelStripsFor 根据卷轴组ID获取卷轴条（空ID视为默认卷轴组）
+func (e *SlotEngine) reelStripsFor(reelSetID string) ([]ReelStrip, bool) {
+	if reelSetID == "" || reelSetID == DefaultReelSetID {
+		return e.config.ReelStrips, true
+	}
+	for _, set := range e.reelSets {
+		if set.ID == reelSetID {
+			return set.ReelStrips, true
+		}
+	}
+	return nil, false
+}
+
+// GetReelSets 获取认证卷轴组（含理论RTP）
+func (e *SlotEngine) GetReelSets() []ReelSet {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	
+	sets := make([]ReelSet, len(e.reelSets))
+	copy(sets, e.reelSets)
+	return sets
 }
 
 // generateReels 生成卷轴结果
-func (e *SlotEngine) generateReels(favorWin bool) [][]Symbol {
+func (e *SlotEngine) generateReels(rng RandomGenerator, reelStrips []ReelStrip, favorWin bool) [][]Symbol {
 	reels := make([][]Symbol, e.config.Reels)
 	
 	for i := 0; i < e.config.Reels; i++ {
-		reels[i] = make([]Symbol, e.config.Rows)
-		reelStrip := e.config.ReelStrips[i]
+		rows := e.reelHeight(rng, i)
+		reels[i] = make([]Symbol, rows)
+		reelStrip := reelStrips[i]
 		
-		for j := 0; j < e.config.Rows; j++ {
+		for j := 0; j < rows; j++ {
 			// 根据权重选择符号
-			symbol := e.selectSymbolByWeight(reelStrip, favorWin)
+			symbol := e.selectSymbolByWeight(rng, reelStrip, favorWin)
 			reels[i][j] = symbol
 		}
 	}
@@ -232,15 +629,27 @@ func (e *SlotEngine) generateReels(favorWin bool) [][]Symbol {
 	return reels
 }
 
+// reelHeight 获取卷轴本次旋转的可见行数（Megaways风格时在范围内随机）
+func (e *SlotEngine) reelHeight(rng RandomGenerator, reel int) int {
+	if reel >= len(e.config.ReelHeights) {
+		return e.config.Rows
+	}
+	height := e.config.ReelHeights[reel]
+	if height.Max > height.Min {
+		return rng.NextInt(height.Min, height.Max+1)
+	}
+	return height.Min
+}
+
 // selectSymbolByWeight 根据权重选择符号
-func (e *SlotEngine) selectSymbolByWeight(reelStrip ReelStrip, favorWin bool) Symbol {
+func (e *SlotEngine) selectSymbolByWeight(rng RandomGenerator, reelStrip ReelStrip, favorWin bool) Symbol {
 	totalWeight := 0
 	for _, weight := range reelStrip.Weights {
 		totalWeight += weight
 	}
 	
 	// 生成随机数
-	randomValue := e.randomGen.NextInt(0, totalWeight)
+	randomValue := rng.NextInt(0, totalWeight)
 	
 	// 如果倾向于中奖，调整权重
 	if favorWin {
@@ -269,9 +678,13 @@ func (e *SlotEngine) adjustForWin(reels [][]Symbol) {
 		// 选择一个中等价值的符号
 		winSymbol := SymbolOrange
 		
-		// 在前3个卷轴的中间行放置相同符号
+		// 在前3个卷轴的中间行放置相同符号（行数可变时不超过该卷轴的行数）
 		for i := 0; i < 3 && i < e.config.Reels; i++ {
-			reels[i][middleRow] = winSymbol
+			row := middleRow
+			if row >= len(reels[i]) {
+				row = len(reels[i]) - 1
+			}
+			reels[i][row] = winSymbol
 		}
 	}
 }
@@ -303,13 +716,6 @@ func (e *SlotEngine) forceSmallWin(reels [][]Symbol) []WinLine {
 	}
 }
 
-// calculateBonusWin 计算奖励游戏赢取
-func (e *SlotEngine) calculateBonusWin(betAmount int64) int64 {
-	// 随机倍率 5-20倍（降低以维持合理RTP）
-	multiplier := e.randomGen.NextInt(5, 20)
-	return betAmount * int64(multiplier)
-}
-
 // getOrCreateSession 获取或创建会话
 func (e *SlotEngine) getOrCreateSession(userID uint, sessionID string) *SessionData {
 	if session, exists := e.sessionData[sessionID]; exists {
@@ -345,6 +751,137 @@ func (e *SlotEngine) SetRTPController(controller RTPController) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.rtpController = controller
+	e.syncControllerRandom()
+}
+
+// SetRandomGenerator 注入随机数生成器，RTP控制器的判定同样使用该生成器
+func (e *SlotEngine) SetRandomGenerator(rng RandomGenerator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.randomGen = rng
+	e.syncControllerRandom()
+}
+
+// EnableSeededMode 开启种子模式：每次旋转从随机源抽取种子并记录在结果中，可通过Replay重放
+func (e *SlotEngine) EnableSeededMode() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.spinRNG == nil {
+		e.spinRNG = NewDRBGRandomGenerator(0)
+	}
+}
+
+// IsSeededMode 是否处于种子模式
+func (e *SlotEngine) IsSeededMode() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.spinRNG != nil
+}
+
+// EnableInteractiveBonus 开启交互式奖励游戏：触发后保存在会话中，由玩家通过PickBonus逐个选择格子
+func (e *SlotEngine) EnableInteractiveBonus() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.manualBonus = true
+}
+
+// PickBonus 在进行中的奖励游戏中选择格子，奖励游戏结束时将赢取计入统计
+func (e *SlotEngine) PickBonus(sessionID string, index int) (*BonusGameState, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	
+	session, exists := e.sessionData[sessionID]
+	if !exists || !session.BonusGame.IsActive() {
+		return nil, ErrNoActiveBonus
+	}
+	
+	bonus := session.BonusGame
+	if _, err := bonus.Pick(index); err != nil {
+		return nil, err
+	}
+	if !bonus.IsActive() {
+		e.settleBonus(session, bonus)
+	}
+	session.LastActiveAt = time.Now()
+	
+	return bonus.View(), nil
+}
+
+// GetBonusGame 获取会话中进行中的奖励游戏（未揭开的格子内容已隐藏）
+func (e *SlotEngine) GetBonusGame(sessionID string) *BonusGameState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	
+	session, exists := e.sessionData[sessionID]
+	if !exists || !session.BonusGame.IsActive() {
+		return nil
+	}
+	return session.BonusGame.View()
+}
+
+// RestoreBonusGame 恢复持久化的奖励游戏（如断线重连或服务重启后）
+func (e *SlotEngine) RestoreBonusGame(userID uint, sessionID string, bonus *BonusGameState) {
+	if !bonus.IsActive() {
+		return
+	}
+	
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	session := e.getOrCreateSession(userID, sessionID)
+	session.BonusGame = bonus
+}
+
+// RestoreFreeGame 恢复持久化的免费游戏（如断线重连或服务重启后），会话中已有免费游戏时不覆盖
+func (e *SlotEngine) RestoreFreeGame(userID uint, sessionID string, freeGame FreeGameState) {
+	if !freeGame.IsActive() {
+		return
+	}
+	
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	session := e.getOrCreateSession(userID, sessionID)
+	if session.FreeGameState.IsActive() {
+		return
+	}
+	session.FreeGameState = freeGame
+}
+
+// settleBonus 结算已完成的交互式奖励游戏
+// 奖励游戏与触发它的旋转为同一局，两者合计不超过最高赢取
+func (e *SlotEngine) settleBonus(session *SessionData, bonus *BonusGameState) {
+	var spinWin int64
+	if last := session.LastSpinResult; last != nil && last.ID == bonus.ID {
+		spinWin = last.WinAmount
+	}
+	bonus.TotalWin, _ = e.config.MaxWin.ApplyRound(spinWin, bonus.TotalWin, bonus.BetAmount)
+	
+	e.statistics.TotalWin += bonus.TotalWin
+	session.TotalWin += bonus.TotalWin
+	e.statistics.CurrentRTP = e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
+	e.statistics.LastUpdate = time.Now()
+	
+	if rtpCtrl, ok := e.rtpController.(*DynamicRTPController); ok {
+		rtpCtrl.UpdateHistory(0, bonus.TotalWin)
+	}
+	if selector, ok := e.rtpController.(ReelSetSelector); ok {
+		selector.RecordSpin(session.ReelSetID, 0, bonus.TotalWin)
+	}
+	session.BonusGame = nil
+}
+
+// syncControllerRandom 将引擎随机源同步给RTP控制器
+func (e *SlotEngine) syncControllerRandom() {
+	if rtpCtrl, ok := e.rtpController.(*DynamicRTPController); ok {
+		rtpCtrl.SetRandomGenerator(e.randomGen)
+	}
+}
+
+// getMode 获取引擎模式（未配置时为自适应模式）
+func (e *SlotEngine) getMode() EngineMode {
+	if e.config.Mode == "" {
+		return EngineModeAdaptive
+	}
+	return e.config.Mode
 }
 
 // GetStatistics 获取统计数据
@@ -372,6 +909,13 @@ func (e *SlotEngine) GetSession(sessionID string) *SessionData {
 	return e.sessionData[sessionID]
 }
 
+// RemoveSession 移除会话数据（玩家离开机台时调用）
+func (e *SlotEngine) RemoveSession(sessionID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.sessionData, sessionID)
+}
+
 // CleanupSessions 清理过期会话
 func (e *SlotEngine) CleanupSessions(maxAge time.Duration) {
 	e.mu.Lock()
@@ -440,6 +984,7 @@ func (e *SlotEngine) GetRTPInfo() map[string]interface{} {
 	defer e.mu.RUnlock()
 	
 	info := map[string]interface{}{
+		"mode":        e.getMode(),
 		"target_rtp":  e.config.TargetRTP,
 		"current_rtp": e.statistics.CurrentRTP,
 		"total_spins": e.statistics.TotalSpins,
@@ -454,6 +999,12 @@ func (e *SlotEngine) GetRTPInfo() map[string]interface{} {
 		info["long_term_rtp"] = stats.LongTermRTP
 	}
 	
+	// 卷轴组控制器：奖池余额和各卷轴组统计
+	if reelSetCtrl, ok := e.rtpController.(*ReelSetController); ok {
+		info["pool_balance"] = reelSetCtrl.GetPoolBalance()
+		info["reel_set_stats"] = reelSetCtrl.GetReelSetStats()
+	}
+	
 	return info
 }
 
@@ -1,6 +1,7 @@
 package slot
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -19,24 +20,24 @@ func TestNewSlotEngine(t *testing.T) {
 		{
 			name: "无效行数",
 			config: &SlotConfig{
-				Rows:       2,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  0.96,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           2,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      0.96,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 		},
 		{
 			name: "无效RTP",
 			config: &SlotConfig{
-				Rows:       3,
-				Reels:      5,
-				PayLines:   20,
-				TargetRTP:  1.5,
-				ReelStrips: GetDefaultConfig().ReelStrips,
-				PayTables:  GetDefaultConfig().PayTables,
+				Rows:           3,
+				Reels:          5,
+				ActivePaylines: 20,
+				TargetRTP:      1.5,
+				ReelStrips:     GetDefaultConfig().ReelStrips,
+				PayTables:      GetDefaultConfig().PayTables,
 			},
 			wantErr: true,
 		},
@@ -392,4 +393,163 @@ func TestSlotEngine_GetRTPInfo(t *testing.T) {
 	if _, ok := info["long_term_rtp"]; !ok {
 		t.Error("GetRTPInfo missing long_term_rtp")
 	}
-}
\ No newline at end of file
+}
+func TestSlotEngine_SeededReplay(t *testing.T) {
+	config := GetDefaultConfig()
+	config.SeededRNG = true
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if !engine.IsSeededMode() {
+		t.Fatal("engine should be in seeded mode")
+	}
+
+	for i := 0; i < 50; i++ {
+		result, err := engine.Spin(1, "replay-test", 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if result.Seed == 0 {
+			t.Fatal("seeded spin did not record a seed")
+		}
+
+		replayed, err := engine.Replay(result)
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		if replayed.WinAmount != result.WinAmount {
+			t.Errorf("spin %d: replayed WinAmount = %d, want %d", i, replayed.WinAmount, result.WinAmount)
+		}
+		for r := range result.Reels {
+			for c := range result.Reels[r] {
+				if replayed.Reels[r][c] != result.Reels[r][c] {
+					t.Fatalf("spin %d: replayed reel[%d][%d] = %s, want %s",
+						i, r, c, replayed.Reels[r][c], result.Reels[r][c])
+				}
+			}
+		}
+	}
+}
+
+func TestSlotEngine_ReplayWithoutSeed(t *testing.T) {
+	engine, err := NewSlotEngine(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	result, err := engine.Spin(1, "no-seed", 100)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if result.Seed != 0 {
+		t.Errorf("non-seeded spin recorded seed %d", result.Seed)
+	}
+	if _, err := engine.Replay(result); err != ErrReplayUnavailable {
+		t.Errorf("Replay() error = %v, want %v", err, ErrReplayUnavailable)
+	}
+}
+
+func TestSlotEngine_InjectedRandomGenerator(t *testing.T) {
+	spinWith := func(seed int64) []*SpinResult {
+		engine, err := NewSlotEngine(GetDefaultConfig())
+		if err != nil {
+			t.Fatalf("Failed to create engine: %v", err)
+		}
+		engine.SetRandomGenerator(NewDRBGRandomGenerator(seed))
+
+		results := make([]*SpinResult, 0, 20)
+		for i := 0; i < 20; i++ {
+			result, err := engine.Spin(1, "inject", 100)
+			if err != nil {
+				t.Fatalf("Spin failed: %v", err)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+
+	a, b := spinWith(2024), spinWith(2024)
+	for i := range a {
+		if a[i].WinAmount != b[i].WinAmount || a[i].FavorWin != b[i].FavorWin {
+			t.Fatalf("spin %d differs with the same injected generator", i)
+		}
+	}
+}
+
+// fixedRTPController 固定决策的RTP控制器（测试用）
+type fixedRTPController struct {
+	win bool
+}
+
+func (c *fixedRTPController) AdjustOdds(currentRTP, targetRTP float64) float64 { return 1 }
+
+func (c *fixedRTPController) ShouldTriggerWin(currentRTP, targetRTP float64, betAmount int64) bool {
+	return c.win
+}
+
+func (c *fixedRTPController) CalculateRTP(totalWin, totalBet int64) float64 {
+	if totalBet == 0 {
+		return 0
+	}
+	return float64(totalWin) / float64(totalBet)
+}
+
+func (c *fixedRTPController) GetCompensationMultiplier(currentRTP, targetRTP float64) float64 {
+	return 0.1
+}
+
+func TestSlotEngine_PureMathModeIgnoresController(t *testing.T) {
+	spinWith := func(controller RTPController) []*SpinResult {
+		config := GetDefaultConfig()
+		config.Mode = EngineModePureMath
+		engine, err := NewSlotEngine(config)
+		if err != nil {
+			t.Fatalf("Failed to create engine: %v", err)
+		}
+		engine.SetRTPController(controller)
+		engine.SetRandomGenerator(NewDRBGRandomGenerator(77))
+
+		results := make([]*SpinResult, 0, 200)
+		for i := 0; i < 200; i++ {
+			result, err := engine.Spin(1, "pure", 100)
+			if err != nil {
+				t.Fatalf("Spin failed: %v", err)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+
+	favored := spinWith(&fixedRTPController{win: true})
+	suppressed := spinWith(&fixedRTPController{win: false})
+	for i := range favored {
+		if favored[i].FavorWin || favored[i].Compensation != 0 {
+			t.Fatalf("spin %d: pure math mode recorded controller decision", i)
+		}
+		if favored[i].WinAmount != suppressed[i].WinAmount {
+			t.Fatalf("spin %d: outcome depends on controller (%d vs %d)",
+				i, favored[i].WinAmount, suppressed[i].WinAmount)
+		}
+	}
+}
+
+func TestSlotEngine_PureMathModeMatchesTheory(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Mode = EngineModePureMath
+	theory, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(4096))
+
+	result := engine.SimulateBatch(100000, 1000)
+	if math.Abs(result.RTP-theory.BaseGameRTP) > 0.03 {
+		t.Errorf("pure math RTP %v too far from theoretical base game RTP %v", result.RTP, theory.BaseGameRTP)
+	}
+}
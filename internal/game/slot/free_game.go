@@ -0,0 +1,227 @@
+package slot
+
+import "sort"
+
+// defaultFreeGameTriggerCount 未配置触发数量时的默认值
+const defaultFreeGameTriggerCount = 3
+
+// FreeGameConfig 免费游戏配置
+// 挂在 FeatureConfig/AbstractFeatureConfig 的免费旋转功能上，触发符号和数量由所属功能配置决定
+type FreeGameConfig struct {
+	SpinsByCount map[int]int `json:"spins_by_count"` // 触发符号数量 → 免费次数（取不超过实际数量的最大档，为空时每个符号2次）
+	Multiplier   float64     `json:"multiplier"`     // 免费游戏赔付倍率（缺省为1）
+	ReelSetID    string      `json:"reel_set_id"`    // 免费游戏使用的卷轴组（为空时与基础游戏相同）
+	NoRetrigger  bool        `json:"no_retrigger"`   // 免费游戏中不可再触发
+	MaxSpins     int         `json:"max_spins"`      // 单轮免费游戏累计次数上限（0不限）
+}
+
+// SpinsFor 指定数量的触发符号获得的免费次数
+func (c *FreeGameConfig) SpinsFor(count int) int {
+	if c == nil || len(c.SpinsByCount) == 0 {
+		return count * freeSpinsPerScatter
+	}
+
+	best, spins := -1, 0
+	for need, award := range c.SpinsByCount {
+		if need <= count && need > best {
+			best, spins = need, award
+		}
+	}
+	return spins
+}
+
+// GetMultiplier 免费游戏赔付倍率
+func (c *FreeGameConfig) GetMultiplier() float64 {
+	if c == nil || c.Multiplier <= 0 {
+		return 1.0
+	}
+	return c.Multiplier
+}
+
+// CanRetrigger 免费游戏中是否可再触发
+func (c *FreeGameConfig) CanRetrigger() bool {
+	return c == nil || !c.NoRetrigger
+}
+
+// capSpins 按累计上限截断本次获得的次数
+func (c *FreeGameConfig) capSpins(total, spins int) int {
+	if c == nil || c.MaxSpins <= 0 {
+		return spins
+	}
+	if total+spins > c.MaxSpins {
+		spins = c.MaxSpins - total
+	}
+	if spins < 0 {
+		return 0
+	}
+	return spins
+}
+
+// counts 配置中出现的触发数量（升序）
+func (c *FreeGameConfig) counts() []int {
+	if c == nil {
+		return nil
+	}
+	counts := make([]int, 0, len(c.SpinsByCount))
+	for count := range c.SpinsByCount {
+		counts = append(counts, count)
+	}
+	sort.Ints(counts)
+	return counts
+}
+
+// validate 校验免费游戏配置
+func (c *FreeGameConfig) validate(config *SlotConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.Multiplier < 0 || c.MaxSpins < 0 {
+		return ErrInvalidFreeGame
+	}
+	for _, count := range c.counts() {
+		if count < 1 || c.SpinsByCount[count] < 0 {
+			return ErrInvalidFreeGame
+		}
+	}
+	if c.ReelSetID != "" && c.ReelSetID != DefaultReelSetID {
+		for _, set := range config.ReelSets {
+			if set.ID == c.ReelSetID {
+				return nil
+			}
+		}
+		return ErrUnknownReelSet
+	}
+	return nil
+}
+
+// FreeGamePhase 免费游戏阶段
+type FreeGamePhase string
+
+const (
+	FreeGamePhaseIdle      FreeGamePhase = ""          // 未触发
+	FreeGamePhaseActive    FreeGamePhase = "active"    // 进行中
+	FreeGamePhaseCompleted FreeGamePhase = "completed" // 本轮已结束（保留汇总直到下次触发）
+)
+
+// FreeGameState 免费游戏状态
+// 保存在会话中：基础旋转触发后进入进行中，每次免费旋转消耗一次，次数用完进入已结束
+type FreeGameState struct {
+	Phase           FreeGamePhase `json:"phase"`             // 阶段
+	FreeSpinsLeft   int           `json:"free_spins_left"`   // 剩余免费次数
+	FreeSpinsTotal  int           `json:"free_spins_total"`  // 本轮累计获得次数（含再触发）
+	FreeSpinsPlayed int           `json:"free_spins_played"` // 已进行次数
+	Retriggers      int           `json:"retriggers"`        // 再触发次数
+	TriggerBet      int64         `json:"trigger_bet"`       // 触发时的下注额（免费旋转按此计算赔付）
+	Multiplier      float64       `json:"multiplier"`        // 赔付倍率
+	ReelSetID       string        `json:"reel_set_id"`       // 使用的卷轴组
+	FreeGameWin     int64         `json:"free_game_win"`     // 本轮累计赢取
+}
+
+// IsActive 是否有待进行的免费旋转
+func (s *FreeGameState) IsActive() bool {
+	return s.FreeSpinsLeft > 0
+}
+
+// Trigger 基础旋转触发新一轮免费游戏，返回实际获得的次数
+func (s *FreeGameState) Trigger(spins int, betAmount int64, config *FreeGameConfig) int {
+	spins = config.capSpins(0, spins)
+	if spins <= 0 {
+		return 0
+	}
+
+	*s = FreeGameState{
+		Phase:          FreeGamePhaseActive,
+		FreeSpinsLeft:  spins,
+		FreeSpinsTotal: spins,
+		TriggerBet:     betAmount,
+		Multiplier:     config.GetMultiplier(),
+	}
+	if config != nil {
+		s.ReelSetID = config.ReelSetID
+	}
+	return spins
+}
+
+// Resume 恢复会话中只记录了剩余次数的免费游戏（如旧数据），补齐下注额和倍率
+func (s *FreeGameState) Resume(betAmount int64) {
+	if !s.IsActive() {
+		return
+	}
+	s.Phase = FreeGamePhaseActive
+	if s.TriggerBet == 0 {
+		s.TriggerBet = betAmount
+	}
+	if s.Multiplier <= 0 {
+		s.Multiplier = 1.0
+	}
+	if s.FreeSpinsTotal < s.FreeSpinsPlayed+s.FreeSpinsLeft {
+		s.FreeSpinsTotal = s.FreeSpinsPlayed + s.FreeSpinsLeft
+	}
+}
+
+// Next 开始一次免费旋转，返回本次是第几次
+func (s *FreeGameState) Next() int {
+	s.FreeSpinsLeft--
+	s.FreeSpinsPlayed++
+	return s.FreeSpinsPlayed
+}
+
+// Retrigger 免费旋转中再触发，返回实际增加的次数（不可再触发或达到上限时为0）
+func (s *FreeGameState) Retrigger(spins int, config *FreeGameConfig) int {
+	if spins <= 0 || !config.CanRetrigger() {
+		return 0
+	}
+	spins = config.capSpins(s.FreeSpinsTotal, spins)
+	if spins > 0 {
+		s.FreeSpinsLeft += spins
+		s.FreeSpinsTotal += spins
+		s.Retriggers++
+	}
+	return spins
+}
+
+// Stop 提前结束本轮（如达到最高赢取），剩余次数作废
+func (s *FreeGameState) Stop() {
+	s.FreeSpinsLeft = 0
+}
+
+// Record 记录一次免费旋转的赢取，次数用完时结束本轮
+func (s *FreeGameState) Record(winAmount int64) {
+	s.FreeGameWin += winAmount
+	if s.FreeSpinsLeft <= 0 {
+		s.FreeSpinsLeft = 0
+		s.Phase = FreeGamePhaseCompleted
+	}
+}
+
+// CountGridSymbols 统计网格中指定符号的数量（用于数值ID引擎的免费游戏触发）
+func CountGridSymbols(grid [][]int, symbols []int) int {
+	count := 0
+	for _, row := range grid {
+		for _, id := range row {
+			for _, symbol := range symbols {
+				if id == symbol {
+					count++
+					break
+				}
+			}
+		}
+	}
+	return count
+}
+
+// FreeSpinsFor 按网格中的触发符号数量计算获得的免费次数（未达到触发数量时为0）
+func (f *AbstractFeatureConfig) FreeSpinsFor(grid [][]int) (count, spins int) {
+	if f == nil {
+		return 0, 0
+	}
+	count = CountGridSymbols(grid, f.TriggerSymbols)
+	minCount := f.MinCount
+	if minCount <= 0 {
+		minCount = defaultFreeGameTriggerCount
+	}
+	if count < minCount {
+		return count, 0
+	}
+	return count, f.FreeGame.SpinsFor(count)
+}
@@ -0,0 +1,314 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+// freeGameTestConfig 在默认配置的免费旋转功能上挂载免费游戏配置
+func freeGameTestConfig(free *FreeGameConfig) *SlotConfig {
+	config := GetDefaultConfig()
+	config.Features = append([]FeatureConfig(nil), config.Features...)
+	for i := range config.Features {
+		if config.Features[i].Type == FeatureTypeFreeSpins {
+			config.Features[i].FreeGame = free
+		}
+	}
+	return config
+}
+
+func TestFreeGameConfig_SpinsFor(t *testing.T) {
+	var defaults *FreeGameConfig
+	if got := defaults.SpinsFor(4); got != 4*freeSpinsPerScatter {
+		t.Errorf("nil SpinsFor(4) = %d, want %d", got, 4*freeSpinsPerScatter)
+	}
+	if got := defaults.GetMultiplier(); got != 1 {
+		t.Errorf("nil GetMultiplier() = %v, want 1", got)
+	}
+
+	config := &FreeGameConfig{SpinsByCount: map[int]int{3: 8, 4: 12, 5: 20}}
+	tests := []struct {
+		count int
+		want  int
+	}{
+		{2, 0},
+		{3, 8},
+		{4, 12},
+		{5, 20},
+		{7, 20},
+	}
+	for _, tt := range tests {
+		if got := config.SpinsFor(tt.count); got != tt.want {
+			t.Errorf("SpinsFor(%d) = %d, want %d", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestFreeGameState_Lifecycle(t *testing.T) {
+	config := &FreeGameConfig{Multiplier: 2, MaxSpins: 12, ReelSetID: "free"}
+
+	var state FreeGameState
+	if got := state.Trigger(10, 200, config); got != 10 {
+		t.Fatalf("Trigger = %d, want 10", got)
+	}
+	if state.Phase != FreeGamePhaseActive || state.TriggerBet != 200 || state.Multiplier != 2 || state.ReelSetID != "free" {
+		t.Fatalf("state after trigger = %+v", state)
+	}
+
+	// 再触发受累计上限约束
+	state.Next()
+	if got := state.Retrigger(8, config); got != 2 {
+		t.Errorf("Retrigger = %d, want 2 (capped at 12)", got)
+	}
+	if got := state.Retrigger(8, config); got != 0 {
+		t.Errorf("Retrigger at cap = %d, want 0", got)
+	}
+	state.Record(100)
+
+	for state.IsActive() {
+		state.Next()
+		state.Record(50)
+	}
+	if state.Phase != FreeGamePhaseCompleted {
+		t.Errorf("Phase = %q, want completed", state.Phase)
+	}
+	if state.FreeSpinsPlayed != 12 || state.FreeSpinsTotal != 12 || state.Retriggers != 1 {
+		t.Errorf("played/total/retriggers = %d/%d/%d, want 12/12/1",
+			state.FreeSpinsPlayed, state.FreeSpinsTotal, state.Retriggers)
+	}
+	if state.FreeGameWin != 100+11*50 {
+		t.Errorf("FreeGameWin = %d, want %d", state.FreeGameWin, 100+11*50)
+	}
+
+	// 不可再触发
+	state.Trigger(5, 100, &FreeGameConfig{NoRetrigger: true})
+	if got := state.Retrigger(5, &FreeGameConfig{NoRetrigger: true}); got != 0 {
+		t.Errorf("Retrigger with NoRetrigger = %d, want 0", got)
+	}
+}
+
+func TestAbstractFeatureConfig_FreeSpinsFor(t *testing.T) {
+	feature := &AbstractFeatureConfig{
+		TriggerSymbols: []int{SYMBOL_SCATTER},
+		MinCount:       3,
+		FreeGame:       &FreeGameConfig{SpinsByCount: map[int]int{3: 8, 4: 12}},
+	}
+	grid := [][]int{
+		{SYMBOL_SCATTER, 1, 2, 3, 4},
+		{0, SYMBOL_SCATTER, 2, 3, 4},
+		{0, 1, 2, 3, SYMBOL_SCATTER},
+		{0, 1, 2, 3, 4},
+	}
+	if count, spins := feature.FreeSpinsFor(grid); count != 3 || spins != 8 {
+		t.Errorf("FreeSpinsFor = %d, %d, want 3, 8", count, spins)
+	}
+
+	grid[3][0] = SYMBOL_SCATTER
+	if count, spins := feature.FreeSpinsFor(grid); count != 4 || spins != 12 {
+		t.Errorf("FreeSpinsFor = %d, %d, want 4, 12", count, spins)
+	}
+
+	grid[0][0], grid[1][1] = 0, 0
+	if _, spins := feature.FreeSpinsFor(grid); spins != 0 {
+		t.Errorf("FreeSpinsFor below MinCount = %d, want 0", spins)
+	}
+
+	var none *AbstractFeatureConfig
+	if _, spins := none.FreeSpinsFor(grid); spins != 0 {
+		t.Errorf("nil FreeSpinsFor = %d, want 0", spins)
+	}
+}
+
+func TestSlotEngine_FreeGame(t *testing.T) {
+	config := freeGameTestConfig(&FreeGameConfig{
+		SpinsByCount: map[int]int{3: 10},
+		Multiplier:   3,
+		ReelSetID:    "free",
+	})
+	config.ReelSets = []ReelSet{testReelSet("free", 4)}
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(99))
+	engine.EnableSeededMode()
+
+	userID, sessionID := uint(7), "free-game"
+	session := engine.getOrCreateSession(userID, sessionID)
+	session.Trigger(50, 200, config.freeGameConfig())
+
+	winner := (*SpinResult)(nil)
+	for session.IsActive() {
+		result, err := engine.Spin(userID, sessionID, 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if !result.IsFreeSpin || result.BetAmount != 0 || result.ReelSetID != "free" {
+			t.Fatalf("free spin = is_free %v, bet %d, reel set %q", result.IsFreeSpin, result.BetAmount, result.ReelSetID)
+		}
+		if result.FreeGame == nil || result.FreeGame.TriggerBet != 200 {
+			t.Fatalf("FreeGame = %+v, want trigger bet 200", result.FreeGame)
+		}
+		if winner == nil && result.WinAmount > 0 {
+			winner = result
+		}
+	}
+	if session.Phase != FreeGamePhaseCompleted || session.FreeSpinsPlayed != session.FreeSpinsTotal {
+		t.Errorf("session free game = %+v, want completed", session.FreeGameState)
+	}
+	if winner == nil {
+		t.Fatal("no winning free spin in 50 spins")
+	}
+
+	// 回放按触发下注额和倍率重算
+	replayed, err := engine.Replay(winner)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if replayed.WinAmount != winner.WinAmount {
+		t.Errorf("replayed win = %d, want %d", replayed.WinAmount, winner.WinAmount)
+	}
+	unmultiplied := *winner
+	state := *winner.FreeGame
+	state.Multiplier = 1
+	unmultiplied.FreeGame = &state
+	base, err := engine.Replay(&unmultiplied)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if base.WinAmount*3 != winner.WinAmount {
+		t.Errorf("free spin win = %d, want 3 × %d", winner.WinAmount, base.WinAmount)
+	}
+
+	// 下一次旋转恢复为基础旋转
+	result, err := engine.Spin(userID, sessionID, 100)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if result.IsFreeSpin || result.BetAmount != 100 {
+		t.Errorf("base spin after free game = is_free %v, bet %d", result.IsFreeSpin, result.BetAmount)
+	}
+}
+
+func TestCalculateTheoreticalRTP_FreeGame(t *testing.T) {
+	base, err := CalculateTheoreticalRTP(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	a := base.ExpectedFreeSpins
+	if want := base.BaseGameRTP * a / (1 - a); math.Abs(base.FreeSpinRTP-want) > 1e-12 {
+		t.Errorf("default FreeSpinRTP = %v, want %v", base.FreeSpinRTP, want)
+	}
+
+	doubled, err := CalculateTheoreticalRTP(freeGameTestConfig(&FreeGameConfig{Multiplier: 2}))
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if math.Abs(doubled.FreeSpinRTP-2*base.FreeSpinRTP) > 1e-12 {
+		t.Errorf("doubled FreeSpinRTP = %v, want %v", doubled.FreeSpinRTP, 2*base.FreeSpinRTP)
+	}
+
+	single, err := CalculateTheoreticalRTP(freeGameTestConfig(&FreeGameConfig{NoRetrigger: true}))
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if math.Abs(single.ExpectedFreeGames-single.ExpectedFreeSpins) > 1e-12 {
+		t.Errorf("no retrigger: ExpectedFreeGames = %v, want %v", single.ExpectedFreeGames, single.ExpectedFreeSpins)
+	}
+
+	capped, err := CalculateTheoreticalRTP(freeGameTestConfig(&FreeGameConfig{MaxSpins: 8}))
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if capped.ExpectedFreeGames >= base.ExpectedFreeGames || capped.ExpectedFreeGames <= 0 {
+		t.Errorf("capped ExpectedFreeGames = %v, want in (0, %v)", capped.ExpectedFreeGames, base.ExpectedFreeGames)
+	}
+
+	// 独立卷轴组按该卷轴组的单次RTP计算
+	config := freeGameTestConfig(&FreeGameConfig{ReelSetID: "free", NoRetrigger: true})
+	config.ReelSets = []ReelSet{testReelSet("free", 4)}
+	freeSet, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	setRTP, _, err := calculateBaseGameRTP(freeGameReelConfig(config))
+	if err != nil {
+		t.Fatalf("calculateBaseGameRTP failed: %v", err)
+	}
+	if want := freeSet.ExpectedFreeGames * setRTP.BaseGameRTP; math.Abs(freeSet.FreeSpinRTP-want) > 1e-12 {
+		t.Errorf("free reel set FreeSpinRTP = %v, want %v", freeSet.FreeSpinRTP, want)
+	}
+}
+
+func TestExpectedFreeGames_MatchesStateMachine(t *testing.T) {
+	config := &FreeGameConfig{MaxSpins: 30}
+	baseAwards := []freeGameAward{{prob: 0.2, spins: 10}, {prob: 0.05, spins: 20}}
+	freeAwards := []freeGameAward{{prob: 0.25, spins: 10}, {prob: 0.05, spins: 20}}
+
+	expected, err := expectedFreeGames(config, baseAwards, freeAwards)
+	if err != nil {
+		t.Fatalf("expectedFreeGames failed: %v", err)
+	}
+
+	draw := func(rng *DRBGRandomGenerator, awards []freeGameAward) int {
+		p := rng.Next()
+		for _, award := range awards {
+			if p < award.prob {
+				return award.spins
+			}
+			p -= award.prob
+		}
+		return 0
+	}
+
+	rng := NewDRBGRandomGenerator(11)
+	const rounds = 200000
+	played := 0
+	for i := 0; i < rounds; i++ {
+		var state FreeGameState
+		if state.Trigger(draw(rng, baseAwards), 100, config) == 0 {
+			continue
+		}
+		for state.IsActive() {
+			state.Next()
+			state.Retrigger(draw(rng, freeAwards), config)
+			state.Record(0)
+		}
+		played += state.FreeSpinsPlayed
+	}
+
+	simulated := float64(played) / rounds
+	if math.Abs(simulated-expected) > 0.05 {
+		t.Errorf("simulated free spins %v, expected %v", simulated, expected)
+	}
+
+	// 不设上限时再触发期望不小于1的配置无法结束
+	if _, err := expectedFreeGames(nil, baseAwards, []freeGameAward{{prob: 0.2, spins: 5}}); err != ErrRTPOutOfRange {
+		t.Errorf("expectedFreeGames = %v, want ErrRTPOutOfRange", err)
+	}
+}
+
+func TestValidateConfig_FreeGame(t *testing.T) {
+	tests := []struct {
+		name     string
+		free     *FreeGameConfig
+		expected error
+	}{
+		{"未配置", nil, nil},
+		{"分档配置", &FreeGameConfig{SpinsByCount: map[int]int{3: 8, 4: 12}, Multiplier: 2, MaxSpins: 50}, nil},
+		{"负倍率", &FreeGameConfig{Multiplier: -1}, ErrInvalidFreeGame},
+		{"负上限", &FreeGameConfig{MaxSpins: -1}, ErrInvalidFreeGame},
+		{"无效数量", &FreeGameConfig{SpinsByCount: map[int]int{0: 8}}, ErrInvalidFreeGame},
+		{"负次数", &FreeGameConfig{SpinsByCount: map[int]int{3: -1}}, ErrInvalidFreeGame},
+		{"未知卷轴组", &FreeGameConfig{ReelSetID: "missing"}, ErrUnknownReelSet},
+		{"默认卷轴组", &FreeGameConfig{ReelSetID: DefaultReelSetID}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateConfig(freeGameTestConfig(tt.free)); err != tt.expected {
+				t.Errorf("ValidateConfig() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package slot
+
+import "time"
+
+// 默认博倍参数
+const (
+	defaultGambleMaxRounds = 5
+	gambleDeckSize         = 52
+	gamblePivotRank        = 7 // 猜大小时的通杀点数（7既不算大也不算小）
+)
+
+// GambleMode 博倍玩法
+type GambleMode string
+
+const (
+	GambleModeColor   GambleMode = "color"    // 猜红黑：翻一张牌，猜中颜色赢取翻倍
+	GambleModeHighLow GambleMode = "high_low" // 猜大小：翻一张牌，8-K为大、A-6为小，7通杀
+)
+
+// GambleGuess 博倍竞猜选项
+type GambleGuess string
+
+const (
+	GambleGuessRed   GambleGuess = "red"   // 红（红桃、方块）
+	GambleGuessBlack GambleGuess = "black" // 黑（黑桃、梅花）
+	GambleGuessHigh  GambleGuess = "high"  // 大
+	GambleGuessLow   GambleGuess = "low"   // 小
+)
+
+// Valid 竞猜选项是否属于该玩法
+func (m GambleMode) Valid(guess GambleGuess) bool {
+	switch m {
+	case GambleModeColor:
+		return guess == GambleGuessRed || guess == GambleGuessBlack
+	case GambleModeHighLow:
+		return guess == GambleGuessHigh || guess == GambleGuessLow
+	}
+	return false
+}
+
+// RTP 博倍单次竞猜的理论返还率（猜中赢取翻倍）
+// 红黑各26张为100%；大小各24张、7通杀为 2×24/52 = 12/13
+func (m GambleMode) RTP() float64 {
+	switch m {
+	case GambleModeColor:
+		return 1
+	case GambleModeHighLow:
+		highCards := (13 - gamblePivotRank) * 4
+		return 2 * float64(highCards) / gambleDeckSize
+	}
+	return 0
+}
+
+// GambleConfig 博倍配置
+// 中奖后玩家可以用本次赢取竞猜，猜中翻倍、猜错全输，收分后才结算到钱包
+type GambleConfig struct {
+	Modes     []GambleMode  `json:"modes"`           // 可用玩法（为空时全部可用）
+	MaxRounds int           `json:"max_rounds"`      // 每次中奖最多博倍次数（缺省5）
+	Limit     *MaxWinConfig `json:"limit,omitempty"` // 博倍金额上限（翻倍后超过上限时不能继续博倍）
+}
+
+// roundsLimit 实际最多博倍次数
+func (c *GambleConfig) roundsLimit() int {
+	if c.MaxRounds <= 0 {
+		return defaultGambleMaxRounds
+	}
+	return c.MaxRounds
+}
+
+// modes 可用玩法
+func (c *GambleConfig) modes() []GambleMode {
+	if len(c.Modes) == 0 {
+		return []GambleMode{GambleModeColor, GambleModeHighLow}
+	}
+	return c.Modes
+}
+
+// validate 校验博倍配置
+func (c *GambleConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxRounds < 0 {
+		return ErrInvalidGamble
+	}
+	for _, mode := range c.Modes {
+		if mode.RTP() == 0 {
+			return ErrInvalidGamble
+		}
+	}
+	if c.Limit != nil {
+		return c.Limit.validate()
+	}
+	return nil
+}
+
+// Offer 本次赢取是否可以博倍（未配置博倍或未中奖时不可以）
+func (c *GambleConfig) Offer(betAmount, winAmount int64) bool {
+	if c == nil || winAmount <= 0 {
+		return false
+	}
+	limit := c.Limit.Limit(betAmount)
+	return limit == 0 || winAmount*2 <= limit
+}
+
+// GambleCard 博倍翻开的牌
+type GambleCard struct {
+	Rank int `json:"rank"` // 点数 1-13（A-K）
+	Suit int `json:"suit"` // 花色 0黑桃 1红桃 2梅花 3方块
+}
+
+// IsRed 是否为红色花色
+func (c GambleCard) IsRed() bool {
+	return c.Suit == 1 || c.Suit == 3
+}
+
+// matches 翻开的牌是否猜中
+func (c GambleCard) matches(guess GambleGuess) bool {
+	switch guess {
+	case GambleGuessRed:
+		return c.IsRed()
+	case GambleGuessBlack:
+		return !c.IsRed()
+	case GambleGuessHigh:
+		return c.Rank > gamblePivotRank
+	case GambleGuessLow:
+		return c.Rank < gamblePivotRank
+	}
+	return false
+}
+
+// drawGambleCard 从一副牌中随机翻一张（每次竞猜重新洗牌）
+func drawGambleCard(rng RandomGenerator) GambleCard {
+	index := rng.NextInt(0, gambleDeckSize)
+	return GambleCard{Rank: index%13 + 1, Suit: index / 13}
+}
+
+// GambleRound 一次博倍竞猜
+type GambleRound struct {
+	Mode  GambleMode  `json:"mode"`  // 玩法
+	Guess GambleGuess `json:"guess"` // 竞猜选项
+	Card  GambleCard  `json:"card"`  // 翻开的牌
+	Won   bool        `json:"won"`   // 是否猜中
+	Stake int64       `json:"stake"` // 竞猜前的金额
+	Win   int64       `json:"win"`   // 竞猜后的金额（猜错为0）
+}
+
+// GamblePhase 博倍阶段
+type GamblePhase string
+
+const (
+	GamblePhaseActive    GamblePhase = "active"    // 等待竞猜或收分
+	GamblePhaseCollected GamblePhase = "collected" // 已收分
+	GamblePhaseLost      GamblePhase = "lost"      // 猜错，赢取全部输掉
+)
+
+// GambleState 博倍状态
+type GambleState struct {
+	ID          string        `json:"id"`           // 博倍ID（中奖旋转的结果ID）
+	Phase       GamblePhase   `json:"phase"`        // 阶段
+	BetAmount   int64         `json:"bet_amount"`   // 中奖旋转的下注额
+	OriginalWin int64         `json:"original_win"` // 博倍前的赢取
+	Stake       int64         `json:"stake"`        // 当前可收取的金额
+	Modes       []GambleMode  `json:"modes"`        // 可用玩法
+	MaxRounds   int           `json:"max_rounds"`   // 最多博倍次数
+	Limit       int64         `json:"limit"`        // 金额上限（0为不限）
+	Rounds      []GambleRound `json:"rounds"`       // 竞猜记录
+}
+
+// NewGamble 用一次中奖创建博倍
+func NewGamble(config *GambleConfig, id string, betAmount, winAmount int64) *GambleState {
+	return &GambleState{
+		ID:          id,
+		Phase:       GamblePhaseActive,
+		BetAmount:   betAmount,
+		OriginalWin: winAmount,
+		Stake:       winAmount,
+		Modes:       config.modes(),
+		MaxRounds:   config.roundsLimit(),
+		Limit:       config.Limit.Limit(betAmount),
+	}
+}
+
+// IsActive 是否等待竞猜或收分
+func (s *GambleState) IsActive() bool {
+	return s != nil && s.Phase == GamblePhaseActive
+}
+
+// CanDouble 是否还能继续竞猜（次数和金额上限）
+func (s *GambleState) CanDouble() bool {
+	if !s.IsActive() || len(s.Rounds) >= s.MaxRounds {
+		return false
+	}
+	return s.Limit == 0 || s.Stake*2 <= s.Limit
+}
+
+// allows 玩法是否可用
+func (s *GambleState) allows(mode GambleMode) bool {
+	for _, m := range s.Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Play 竞猜一次：猜中金额翻倍，猜错全部输掉并结束
+func (s *GambleState) Play(rng RandomGenerator, mode GambleMode, guess GambleGuess) (GambleRound, error) {
+	if !s.IsActive() {
+		return GambleRound{}, ErrNoActiveGamble
+	}
+	if !s.allows(mode) || !mode.Valid(guess) {
+		return GambleRound{}, ErrInvalidGambleGuess
+	}
+	if !s.CanDouble() {
+		return GambleRound{}, ErrGambleLimitReached
+	}
+
+	card := drawGambleCard(rng)
+	round := GambleRound{Mode: mode, Guess: guess, Card: card, Won: card.matches(guess), Stake: s.Stake}
+	if round.Won {
+		round.Win = s.Stake * 2
+	}
+	s.Stake = round.Win
+	s.Rounds = append(s.Rounds, round)
+	if !round.Won {
+		s.Phase = GamblePhaseLost
+	}
+	return round, nil
+}
+
+// Collect 收分结束博倍，返回结算金额
+func (s *GambleState) Collect() (int64, error) {
+	if !s.IsActive() {
+		return 0, ErrNoActiveGamble
+	}
+	s.Phase = GamblePhaseCollected
+	return s.Stake, nil
+}
+
+// offerGamble 基础旋转中奖后为会话创建博倍
+// 免费游戏进行中、奖励游戏等待选择或达到最高赢取时不提供
+func (e *SlotEngine) offerGamble(session *SessionData, result *SpinResult, payBet int64) {
+	if result.IsFreeSpin || result.MaxWinReached || session.FreeGameState.IsActive() || session.BonusGame.IsActive() {
+		return
+	}
+	if !e.config.Gamble.Offer(payBet, result.WinAmount) {
+		return
+	}
+	session.Gamble = NewGamble(e.config.Gamble, result.ID, payBet, result.WinAmount)
+	result.Gamble = session.Gamble.snapshot()
+}
+
+// PlayGamble 在会话进行中的博倍里竞猜一次，输赢计入统计
+func (e *SlotEngine) PlayGamble(sessionID string, mode GambleMode, guess GambleGuess) (*GambleState, GambleRound, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	session, exists := e.sessionData[sessionID]
+	if !exists || !session.Gamble.IsActive() {
+		return nil, GambleRound{}, ErrNoActiveGamble
+	}
+
+	gamble := session.Gamble
+	round, err := gamble.Play(e.randomGen, mode, guess)
+	if err != nil {
+		return nil, GambleRound{}, err
+	}
+
+	// 旋转时已按原赢取计入统计，这里只计入博倍的输赢差额
+	delta := round.Win - round.Stake
+	e.statistics.TotalWin += delta
+	session.TotalWin += delta
+	e.statistics.CurrentRTP = e.rtpController.CalculateRTP(e.statistics.TotalWin, e.statistics.TotalBet)
+	if !gamble.IsActive() {
+		session.Gamble = nil
+	}
+	session.LastActiveAt = time.Now()
+
+	return gamble.snapshot(), round, nil
+}
+
+// CollectGamble 收分结束会话中的博倍，返回结束后的博倍状态（Stake为结算金额）
+func (e *SlotEngine) CollectGamble(sessionID string) (*GambleState, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	session, exists := e.sessionData[sessionID]
+	if !exists || !session.Gamble.IsActive() {
+		return nil, ErrNoActiveGamble
+	}
+
+	gamble := session.Gamble
+	if _, err := gamble.Collect(); err != nil {
+		return nil, err
+	}
+	session.Gamble = nil
+	session.LastActiveAt = time.Now()
+
+	return gamble.snapshot(), nil
+}
+
+// GetGamble 获取会话中进行中的博倍
+func (e *SlotEngine) GetGamble(sessionID string) *GambleState {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if session, exists := e.sessionData[sessionID]; exists && session.Gamble.IsActive() {
+		return session.Gamble.snapshot()
+	}
+	return nil
+}
+
+// snapshot 返回博倍状态的副本
+func (s *GambleState) snapshot() *GambleState {
+	if s == nil {
+		return nil
+	}
+	view := *s
+	view.Rounds = append([]GambleRound(nil), s.Rounds...)
+	return &view
+}
@@ -0,0 +1,198 @@
+package slot
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// deckGenerator 按顺序翻出指定牌序号（0-51）的随机数生成器
+type deckGenerator struct {
+	cards []int
+}
+
+func (g *deckGenerator) Next() float64 { return 0 }
+func (g *deckGenerator) Seed(int64)    {}
+func (g *deckGenerator) NextInt(min, max int) int {
+	card := g.cards[0]
+	g.cards = g.cards[1:]
+	return card
+}
+
+func TestGambleMode_RTP(t *testing.T) {
+	if got := GambleModeColor.RTP(); got != 1 {
+		t.Errorf("color RTP = %v, want 1", got)
+	}
+	if got := GambleModeHighLow.RTP(); math.Abs(got-12.0/13.0) > 1e-9 {
+		t.Errorf("high_low RTP = %v, want 12/13", got)
+	}
+	if GambleModeColor.Valid(GambleGuessHigh) || !GambleModeHighLow.Valid(GambleGuessLow) {
+		t.Error("guess validation mismatch")
+	}
+}
+
+func TestGambleConfig_Offer(t *testing.T) {
+	var disabled *GambleConfig
+	if disabled.Offer(100, 500) {
+		t.Error("nil config should not offer a gamble")
+	}
+
+	config := &GambleConfig{Limit: &MaxWinConfig{Multiplier: 10}}
+	tests := []struct {
+		win  int64
+		want bool
+	}{
+		{0, false},
+		{200, true},
+		{500, true},
+		{501, false},
+	}
+	for _, tt := range tests {
+		if got := config.Offer(100, tt.win); got != tt.want {
+			t.Errorf("Offer(100, %d) = %v, want %v", tt.win, got, tt.want)
+		}
+	}
+
+	if err := (&GambleConfig{MaxRounds: -1}).validate(); err != ErrInvalidGamble {
+		t.Errorf("negative rounds = %v", err)
+	}
+	if err := (&GambleConfig{Modes: []GambleMode{"dice"}}).validate(); err != ErrInvalidGamble {
+		t.Errorf("unknown mode = %v", err)
+	}
+}
+
+func TestGambleState_Play(t *testing.T) {
+	// 红桃K（13+12=25）、黑桃7（6）、方块2（40）
+	rng := &deckGenerator{cards: []int{25, 6, 40}}
+	gamble := NewGamble(&GambleConfig{MaxRounds: 3}, "g1", 100, 200)
+
+	if _, err := gamble.Play(rng, GambleModeColor, GambleGuessHigh); err != ErrInvalidGambleGuess {
+		t.Fatalf("mismatched guess = %v", err)
+	}
+
+	round, err := gamble.Play(rng, GambleModeColor, GambleGuessRed)
+	if err != nil || !round.Won || round.Stake != 200 || round.Win != 400 || gamble.Stake != 400 {
+		t.Fatalf("red on K♥ = %+v, %v (stake %d)", round, err, gamble.Stake)
+	}
+
+	// 7通杀
+	round, err = gamble.Play(rng, GambleModeHighLow, GambleGuessHigh)
+	if err != nil || round.Won || round.Card.Rank != 7 {
+		t.Fatalf("high on 7 = %+v, %v", round, err)
+	}
+	if gamble.IsActive() || gamble.Phase != GamblePhaseLost || gamble.Stake != 0 {
+		t.Errorf("lost gamble = %+v", gamble)
+	}
+	if _, err := gamble.Play(rng, GambleModeColor, GambleGuessRed); err != ErrNoActiveGamble {
+		t.Errorf("play after losing = %v", err)
+	}
+	if _, err := gamble.Collect(); err != ErrNoActiveGamble {
+		t.Errorf("collect after losing = %v", err)
+	}
+}
+
+func TestGambleState_Limits(t *testing.T) {
+	// 方块A（39）：红色、小
+	rng := &deckGenerator{cards: []int{39, 39, 39}}
+
+	byRounds := NewGamble(&GambleConfig{MaxRounds: 1}, "rounds", 100, 100)
+	if _, err := byRounds.Play(rng, GambleModeColor, GambleGuessRed); err != nil {
+		t.Fatalf("first round failed: %v", err)
+	}
+	if byRounds.CanDouble() {
+		t.Error("CanDouble after reaching max rounds")
+	}
+	if _, err := byRounds.Play(rng, GambleModeColor, GambleGuessRed); err != ErrGambleLimitReached {
+		t.Errorf("round over limit = %v", err)
+	}
+
+	byAmount := NewGamble(&GambleConfig{Limit: &MaxWinConfig{Amount: 500}}, "amount", 100, 200)
+	if _, err := byAmount.Play(rng, GambleModeHighLow, GambleGuessLow); err != nil {
+		t.Fatalf("first round failed: %v", err)
+	}
+	if byAmount.CanDouble() {
+		t.Error("CanDouble when doubling would exceed the limit")
+	}
+	win, err := byAmount.Collect()
+	if err != nil || win != 400 || byAmount.Phase != GamblePhaseCollected {
+		t.Errorf("Collect = %d, %v (phase %s)", win, err, byAmount.Phase)
+	}
+}
+
+func TestSlotEngine_Gamble(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Gamble = &GambleConfig{MaxRounds: 2}
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(5))
+
+	const session = "gamble"
+	var result *SpinResult
+	for i := 0; i < 500 && (result == nil || result.Gamble == nil); i++ {
+		if result, err = engine.Spin(1, session, 100); err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+	}
+	if result.Gamble == nil {
+		t.Fatal("no gamble offered in 500 spins")
+	}
+	if result.Gamble.Stake != result.WinAmount {
+		t.Fatalf("gamble stake = %d, win %d", result.Gamble.Stake, result.WinAmount)
+	}
+	if _, err := engine.Spin(1, session, 100); err != ErrGambleInProgress {
+		t.Fatalf("Spin during gamble = %v", err)
+	}
+
+	totalWin := engine.GetStatistics().TotalWin
+	gamble, round, err := engine.PlayGamble(session, GambleModeColor, GambleGuessRed)
+	if err != nil {
+		t.Fatalf("PlayGamble failed: %v", err)
+	}
+	if got := engine.GetStatistics().TotalWin - totalWin; got != round.Win-round.Stake {
+		t.Errorf("statistics TotalWin moved by %d, want %d", got, round.Win-round.Stake)
+	}
+
+	if round.Won {
+		collected, err := engine.CollectGamble(session)
+		if err != nil || collected.Stake != gamble.Stake || collected.Phase != GamblePhaseCollected {
+			t.Fatalf("CollectGamble = %+v, %v", collected, err)
+		}
+	} else if engine.GetGamble(session) != nil {
+		t.Fatal("lost gamble still active")
+	}
+	if _, err := engine.CollectGamble(session); err != ErrNoActiveGamble {
+		t.Errorf("second collect = %v", err)
+	}
+	if _, err := engine.Spin(1, session, 100); err != nil {
+		t.Errorf("Spin after gamble = %v", err)
+	}
+}
+
+func TestRunSimulation_GambleRTP(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Gamble = &GambleConfig{MaxRounds: 5}
+	opts := &SimulationOptions{
+		Engine:    "slot",
+		Spins:     20000,
+		Workers:   2,
+		Seed:      21,
+		BetAmount: 100,
+		Gamble:    &GambleStrategy{Mode: GambleModeHighLow, Rounds: 2},
+	}
+
+	report, err := RunSimulation(context.Background(), opts, NewSlotGambleSimulationFactory(config, 100, opts.Gamble))
+	if err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+	if report.GambleRounds == 0 || report.GambleStake == 0 {
+		t.Fatalf("no gamble rounds played: %+v", report)
+	}
+	if report.GambleTheoryRTP != GambleModeHighLow.RTP() {
+		t.Errorf("GambleTheoryRTP = %v", report.GambleTheoryRTP)
+	}
+	if math.Abs(report.GambleRTP-report.GambleTheoryRTP) > 0.1 {
+		t.Errorf("GambleRTP = %v, theory %v", report.GambleRTP, report.GambleTheoryRTP)
+	}
+}
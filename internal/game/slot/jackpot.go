@@ -0,0 +1,177 @@
+package slot
+
+// 彩金等级（与JP池类型一致）
+const (
+	JackpotLevelJP1   = "JP1"
+	JackpotLevelJP2   = "JP2"
+	JackpotLevelJP3   = "JP3"
+	JackpotLevelJPAll = "JPALL"
+)
+
+// jackpotLevels 彩金等级（按协议编号顺序）
+var jackpotLevels = []string{JackpotLevelJP1, JackpotLevelJP2, JackpotLevelJP3, JackpotLevelJPAll}
+
+// JackpotLevelNumber 彩金等级在协议中的编号（1-4，未知等级为0）
+func JackpotLevelNumber(level string) uint32 {
+	for i, l := range jackpotLevels {
+		if l == level {
+			return uint32(i + 1)
+		}
+	}
+	return 0
+}
+
+// JackpotTrigger 彩金触发方式
+type JackpotTrigger string
+
+const (
+	JackpotTriggerSymbols   JackpotTrigger = "symbols"     // 指定符号出现足够数量
+	JackpotTriggerMystery   JackpotTrigger = "mystery"     // 每次下注按概率随机触发
+	JackpotTriggerMustHitBy JackpotTrigger = "must_hit_by" // 奖池达到必中金额（或奖池的隐藏必中点）时触发
+)
+
+// JackpotRule 彩金触发规则（同一等级可配置多条，任一条满足即触发）
+type JackpotRule struct {
+	Level       string         `json:"level"`                 // 彩金等级
+	Trigger     JackpotTrigger `json:"trigger"`               // 触发方式
+	SymbolID    int            `json:"symbol_id,omitempty"`   // 触发符号ID（符号触发）
+	Count       int            `json:"count,omitempty"`       // 触发符号最少数量（符号触发）
+	Probability float64        `json:"probability,omitempty"` // 每次下注的触发概率（随机触发）
+	Amount      int64          `json:"amount,omitempty"`      // 必中金额（必中触发，0为奖池的隐藏必中点，没有时为奖池上限）
+	MinBet      int64          `json:"min_bet,omitempty"`     // 参与该规则的最低下注额
+}
+
+// JackpotConfig 彩金配置（按机台配置）
+// 每次实际下注后按规则判断各等级是否触发，触发的等级派发整个奖池并重置为种子金额
+type JackpotConfig struct {
+	Rules []JackpotRule `json:"rules"`
+}
+
+// validate 校验彩金配置：等级已知、触发方式有效且参数完整
+func (c *JackpotConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, rule := range c.Rules {
+		if JackpotLevelNumber(rule.Level) == 0 || rule.MinBet < 0 {
+			return ErrInvalidJackpot
+		}
+		switch rule.Trigger {
+		case JackpotTriggerSymbols:
+			if rule.Count <= 0 {
+				return ErrInvalidJackpot
+			}
+		case JackpotTriggerMystery:
+			if rule.Probability <= 0 || rule.Probability > 1 {
+				return ErrInvalidJackpot
+			}
+		case JackpotTriggerMustHitBy:
+			if rule.Amount < 0 {
+				return ErrInvalidJackpot
+			}
+		default:
+			return ErrInvalidJackpot
+		}
+	}
+	return nil
+}
+
+// JackpotSpin 判断彩金所需的本局信息
+type JackpotSpin struct {
+	BetAmount    int64       // 实际下注额（免费旋转为0，不参与彩金）
+	SymbolCounts map[int]int // 各符号ID的数量
+}
+
+// LineJackpotSpin 支付线/全路径旋转的彩金信息：符号数量取该符号中奖线的最长连续个数
+func LineJackpotSpin(betAmount int64, result *SpinResult) JackpotSpin {
+	counts := make(map[int]int)
+	for _, line := range result.WinLines {
+		if id, ok := SymbolID(line.Symbol); ok && line.Count > counts[id] {
+			counts[id] = line.Count
+		}
+	}
+	return JackpotSpin{BetAmount: betAmount, SymbolCounts: counts}
+}
+
+// GridJackpotSpin 消除类旋转的彩金信息：符号数量取初始网格（grid[行][卷轴]）上的出现次数
+func GridJackpotSpin(betAmount int64, grid [][]int) JackpotSpin {
+	counts := make(map[int]int)
+	for _, row := range grid {
+		for _, id := range row {
+			counts[id]++
+		}
+	}
+	return JackpotSpin{BetAmount: betAmount, SymbolCounts: counts}
+}
+
+// JackpotPool 判断时的奖池状态
+type JackpotPool struct {
+	Level     string
+	Amount    int64 // 当前金额
+	MaxAmount int64 // 奖池上限
+	HitPoint  int64 // 隐藏必中点（必中奖池，0为没有）
+}
+
+// Triggered 返回本局触发的彩金等级（按协议编号顺序，每个等级最多一次）
+// 没有实际下注时不触发；随机触发每条规则各抽一次随机数
+func (c *JackpotConfig) Triggered(rng RandomGenerator, spin JackpotSpin, pools []JackpotPool) []string {
+	if c == nil || spin.BetAmount <= 0 {
+		return nil
+	}
+	poolOf := make(map[string]JackpotPool, len(pools))
+	for _, pool := range pools {
+		poolOf[pool.Level] = pool
+	}
+
+	hit := make(map[string]bool)
+	for _, rule := range c.Rules {
+		if spin.BetAmount < rule.MinBet {
+			continue
+		}
+		switch rule.Trigger {
+		case JackpotTriggerSymbols:
+			if spin.SymbolCounts[rule.SymbolID] >= rule.Count {
+				hit[rule.Level] = true
+			}
+		case JackpotTriggerMystery:
+			if rng.Next() < rule.Probability {
+				hit[rule.Level] = true
+			}
+		case JackpotTriggerMustHitBy:
+			pool, ok := poolOf[rule.Level]
+			if !ok {
+				continue
+			}
+			mustHit := rule.Amount
+			if mustHit <= 0 {
+				mustHit = pool.HitPoint
+			}
+			if mustHit <= 0 {
+				mustHit = pool.MaxAmount
+			}
+			if mustHit > 0 && pool.Amount >= mustHit {
+				hit[rule.Level] = true
+			}
+		}
+	}
+
+	var levels []string
+	for _, level := range jackpotLevels {
+		if hit[level] {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// DefaultJackpotConfig 默认彩金配置：JP1-JP3随机触发（等级越高概率越低），JPALL在奖池越过隐藏必中点（没有时为上限）时必中
+func DefaultJackpotConfig() *JackpotConfig {
+	return &JackpotConfig{
+		Rules: []JackpotRule{
+			{Level: JackpotLevelJP1, Trigger: JackpotTriggerMystery, Probability: 0.0005},
+			{Level: JackpotLevelJP2, Trigger: JackpotTriggerMystery, Probability: 0.0001},
+			{Level: JackpotLevelJP3, Trigger: JackpotTriggerMystery, Probability: 0.00002},
+			{Level: JackpotLevelJPAll, Trigger: JackpotTriggerMustHitBy},
+		},
+	}
+}
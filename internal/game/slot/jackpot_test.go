@@ -0,0 +1,201 @@
+package slot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJackpotConfig_Triggered(t *testing.T) {
+	config := &JackpotConfig{Rules: []JackpotRule{
+		{Level: JackpotLevelJP3, Trigger: JackpotTriggerSymbols, SymbolID: 7, Count: 5},
+		{Level: JackpotLevelJP1, Trigger: JackpotTriggerMystery, Probability: 0.5, MinBet: 100},
+		{Level: JackpotLevelJPAll, Trigger: JackpotTriggerMustHitBy, Amount: 30000},
+		{Level: JackpotLevelJP2, Trigger: JackpotTriggerMustHitBy},
+	}}
+	pools := []JackpotPool{
+		{Level: JackpotLevelJP2, Amount: 4999, MaxAmount: 5000},
+		{Level: JackpotLevelJPAll, Amount: 30000, MaxAmount: 20000000},
+	}
+	spin := JackpotSpin{BetAmount: 100, SymbolCounts: map[int]int{7: 5}}
+
+	rng := NewDRBGRandomGenerator(1)
+	got := config.Triggered(fixedRNG(0.1), spin, pools)
+	if want := []string{JackpotLevelJP1, JackpotLevelJP3, JackpotLevelJPAll}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Triggered = %v, want %v", got, want)
+	}
+
+	// 低于最低下注不参与随机触发；奖池未到上限不必中
+	spin.BetAmount = 50
+	spin.SymbolCounts[7] = 4
+	pools[1].Amount = 29999
+	if got := config.Triggered(fixedRNG(0.1), spin, pools); len(got) != 0 {
+		t.Errorf("Triggered = %v, want none", got)
+	}
+
+	// 奖池达到上限时必中
+	pools[0].Amount = 5000
+	if got := config.Triggered(rng, spin, pools); !reflect.DeepEqual(got, []string{JackpotLevelJP2}) {
+		t.Errorf("Triggered = %v, want JP2", got)
+	}
+
+	// 免费旋转（没有实际下注）不触发
+	spin.BetAmount = 0
+	if got := config.Triggered(fixedRNG(0), spin, pools); got != nil {
+		t.Errorf("free spin Triggered = %v", got)
+	}
+	if got := (*JackpotConfig)(nil).Triggered(rng, JackpotSpin{BetAmount: 100}, pools); got != nil {
+		t.Errorf("nil config Triggered = %v", got)
+	}
+}
+
+// fixedRNG 固定返回同一个随机数
+type fixedRNG float64
+
+func (r fixedRNG) Next() float64            { return float64(r) }
+func (r fixedRNG) NextInt(min, max int) int { return min }
+func (r fixedRNG) Seed(seed int64)          {}
+
+func TestJackpotSpins(t *testing.T) {
+	result := &SpinResult{WinLines: []WinLine{
+		{Symbol: SymbolSeven, Count: 3},
+		{Symbol: SymbolSeven, Count: 5},
+		{Symbol: SymbolCherry, Count: 4},
+	}}
+	line := LineJackpotSpin(100, result)
+	if want := map[int]int{7: 5, 0: 4}; line.BetAmount != 100 || !reflect.DeepEqual(line.SymbolCounts, want) {
+		t.Errorf("LineJackpotSpin = %+v", line)
+	}
+
+	grid := GridJackpotSpin(10, [][]int{{1, 1, SYMBOL_SCATTER}, {1, SYMBOL_WILD, 2}})
+	if want := map[int]int{1: 3, 2: 1, SYMBOL_SCATTER: 1, SYMBOL_WILD: 1}; !reflect.DeepEqual(grid.SymbolCounts, want) {
+		t.Errorf("GridJackpotSpin = %+v", grid)
+	}
+}
+
+func TestJackpotConfig_Validate(t *testing.T) {
+	invalid := []JackpotRule{
+		{Level: "JP9", Trigger: JackpotTriggerMustHitBy},
+		{Level: JackpotLevelJP1, Trigger: "lottery"},
+		{Level: JackpotLevelJP1, Trigger: JackpotTriggerSymbols},
+		{Level: JackpotLevelJP1, Trigger: JackpotTriggerMystery, Probability: 1.5},
+		{Level: JackpotLevelJP1, Trigger: JackpotTriggerMustHitBy, Amount: -1},
+	}
+	for _, rule := range invalid {
+		config := GetLuckySevenConfig()
+		config.Jackpot = &JackpotConfig{Rules: []JackpotRule{rule}}
+		if err := ValidateConfig(config); err != ErrInvalidJackpot {
+			t.Errorf("rule %+v: err = %v, want ErrInvalidJackpot", rule, err)
+		}
+	}
+
+	for _, config := range []*SlotConfig{GetLuckySevenConfig(), GetPharaohConfig()} {
+		if err := ValidateConfig(config); err != nil {
+			t.Errorf("%s: %v", config.MachineID, err)
+		}
+	}
+	if JackpotLevelNumber(JackpotLevelJPAll) != 4 || JackpotLevelNumber("JP9") != 0 {
+		t.Error("unexpected jackpot level numbers")
+	}
+}
+
+func TestJackpotCommitment(t *testing.T) {
+	seed := "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"
+	commitment, err := newJackpotCommitment(seed, 20000, 20000000)
+	if err != nil {
+		t.Fatalf("newJackpotCommitment failed: %v", err)
+	}
+	if commitment.HitPoint <= 20000 || commitment.HitPoint > 20000000 {
+		t.Errorf("hit point %d out of range", commitment.HitPoint)
+	}
+	// 同一种子派生同一必中点
+	if again, _ := JackpotHitPoint(seed, 20000, 20000000); again != commitment.HitPoint {
+		t.Errorf("hit point not reproducible: %d != %d", again, commitment.HitPoint)
+	}
+	if !commitment.Verify(commitment.Hash, 20000, 20000000) {
+		t.Error("commitment should verify")
+	}
+
+	// 篡改必中点、种子或范围都无法通过校验
+	tampered := *commitment
+	tampered.HitPoint++
+	if tampered.Verify(commitment.Hash, 20000, 20000000) {
+		t.Error("tampered hit point verified")
+	}
+	tampered = *commitment
+	tampered.Seed = "ff" + seed[2:]
+	if tampered.Verify(commitment.Hash, 20000, 20000000) {
+		t.Error("tampered seed verified")
+	}
+	if commitment.Verify(commitment.Hash, 20000, 10000000) {
+		t.Error("different range verified")
+	}
+
+	// 随机种子每次不同，必中点始终在范围内
+	for i := 0; i < 100; i++ {
+		c, err := NewJackpotCommitment(1000, 1010)
+		if err != nil {
+			t.Fatalf("NewJackpotCommitment failed: %v", err)
+		}
+		if c.HitPoint <= 1000 || c.HitPoint > 1010 || !c.Verify(c.Hash, 1000, 1010) {
+			t.Fatalf("commitment = %+v", c)
+		}
+	}
+	if _, err := NewJackpotCommitment(1000, 1000); err != ErrInvalidJackpot {
+		t.Errorf("empty range err = %v", err)
+	}
+}
+
+func TestJackpotConfig_TriggeredHitPoint(t *testing.T) {
+	config := DefaultJackpotConfig()
+	spin := JackpotSpin{BetAmount: 100}
+	pool := JackpotPool{Level: JackpotLevelJPAll, Amount: 20004, MaxAmount: 20000000, HitPoint: 20005}
+
+	// 奖池越过隐藏必中点的那次下注中奖
+	if got := config.Triggered(fixedRNG(1), spin, []JackpotPool{pool}); len(got) != 0 {
+		t.Errorf("Triggered below hit point = %v", got)
+	}
+	pool.Amount = 20005
+	if got := config.Triggered(fixedRNG(1), spin, []JackpotPool{pool}); !reflect.DeepEqual(got, []string{JackpotLevelJPAll}) {
+		t.Errorf("Triggered at hit point = %v", got)
+	}
+}
+
+func TestSealJackpotSeed(t *testing.T) {
+	seed := "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff"
+
+	// 未配置密钥时不能加密种子，也不接受空密钥
+	jackpotSeedKey = nil
+	if _, err := SealJackpotSeed(seed); err != ErrJackpotSeedKey {
+		t.Errorf("SealJackpotSeed without key = %v, want ErrJackpotSeedKey", err)
+	}
+	if err := SetJackpotSeedKey(""); err != ErrJackpotSeedKey {
+		t.Errorf("SetJackpotSeedKey(\"\") = %v, want ErrJackpotSeedKey", err)
+	}
+	if err := SetJackpotSeedKey("test-jackpot-secret"); err != nil {
+		t.Fatalf("SetJackpotSeedKey failed: %v", err)
+	}
+
+	sealed, err := SealJackpotSeed(seed)
+	if err != nil {
+		t.Fatalf("SealJackpotSeed failed: %v", err)
+	}
+	if sealed == seed || strings.Contains(sealed, seed) {
+		t.Errorf("sealed seed leaks the plaintext: %s", sealed)
+	}
+	if opened, err := OpenJackpotSeed(sealed); err != nil || opened != seed {
+		t.Errorf("OpenJackpotSeed = %q, %v", opened, err)
+	}
+
+	// 旧版本保存的明文种子原样返回，篡改的密文无法解密
+	if opened, err := OpenJackpotSeed(seed); err != nil || opened != seed {
+		t.Errorf("legacy seed = %q, %v", opened, err)
+	}
+	tampered := sealed[:len(sealed)-2] + "00"
+	if tampered == sealed {
+		tampered = sealed[:len(sealed)-2] + "11"
+	}
+	if _, err := OpenJackpotSeed(tampered); err == nil {
+		t.Error("tampered seed opened")
+	}
+}
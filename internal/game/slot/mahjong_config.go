@@ -0,0 +1,63 @@
+package slot
+
+// GetMahjongAlgorithmConfig 麻将拉霸机算法配置（5x4消除，金色百搭，免费游戏与选择奖励游戏）
+func GetMahjongAlgorithmConfig() *AlgorithmConfig {
+	return &AlgorithmConfig{
+		ReelCount:   5,
+		RowCount:    4,
+		SymbolCount: 8,
+		TargetRTP:   0.96,
+		MinRTP:      0.94,
+		MaxRTP:      0.98,
+
+		// 符号权重配置
+		SymbolWeights: [][]int{
+			{18, 16, 14, 12, 12, 10, 8, 6},
+			{16, 18, 14, 12, 12, 10, 8, 6},
+			{14, 16, 18, 12, 12, 10, 8, 6},
+			{12, 14, 16, 18, 12, 10, 8, 6},
+			{12, 12, 14, 16, 18, 12, 8, 6},
+		},
+
+		// 赔付表
+		PayTable: map[int][]int64{
+			0: {0, 0, 20, 60, 200},
+			1: {0, 0, 25, 75, 250},
+			2: {0, 0, 30, 90, 300},
+			3: {0, 0, 15, 45, 150},
+			4: {0, 0, 12, 36, 120},
+			5: {0, 0, 10, 30, 100},
+			6: {0, 0, 8, 24, 80},
+			7: {0, 0, 6, 18, 60},
+		},
+
+		// 特殊符号配置
+		ScatterSymbols: []int{SYMBOL_SCATTER},
+		BonusSymbols:   []int{SYMBOL_BONUS},
+
+		Algorithm:    AlgorithmTypeClassic,
+		Volatility:   0.55,
+		HitFrequency: 0.4,
+
+		// 免费游戏：3/4/5个以上Scatter分别获得8/12/20次免费旋转，赔付翻倍，可再触发
+		FeatureConfigs: map[AbstractFeatureType]*AbstractFeatureConfig{
+			AbstractFeatureTypeFreeSpin: {
+				TriggerSymbols: []int{SYMBOL_SCATTER},
+				MinCount:       3,
+				Probability:    0.02, // 单格出现Scatter的概率
+				FreeGame: &FreeGameConfig{
+					SpinsByCount: map[int]int{3: 8, 4: 12, 5: 20},
+					Multiplier:   2,
+					MaxSpins:     50,
+				},
+			},
+			// 选择奖励游戏：3个以上Bonus触发，玩家逐个翻开格子
+			AbstractFeatureTypeBonus: {
+				TriggerSymbols: []int{SYMBOL_BONUS},
+				MinCount:       3,
+				Probability:    0.015, // 单格出现Bonus的概率
+				BonusGame:      DefaultBonusGameConfig(),
+			},
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package slot
+
+// MaxWinConfig 最高赢取限制（按机台配置）
+// 单局赢取不超过下注额的倍数上限与绝对金额上限中较小者，达到上限时本局提前结束
+type MaxWinConfig struct {
+	Multiplier float64 `json:"multiplier"` // 下注额倍数上限（0为不限）
+	Amount     int64   `json:"amount"`     // 绝对金额上限（0为不限）
+}
+
+// Limit 按下注额计算单局赢取上限（0为不限）
+func (c *MaxWinConfig) Limit(betAmount int64) int64 {
+	if c == nil {
+		return 0
+	}
+	var limit int64
+	if c.Multiplier > 0 {
+		limit = int64(float64(betAmount) * c.Multiplier)
+	}
+	if c.Amount > 0 && (limit == 0 || c.Amount < limit) {
+		limit = c.Amount
+	}
+	return limit
+}
+
+// Apply 将单次赢取限制在上限内，返回限制后的赢取和是否达到上限
+func (c *MaxWinConfig) Apply(winAmount, betAmount int64) (int64, bool) {
+	return c.ApplyRound(0, winAmount, betAmount)
+}
+
+// ApplyRound 本局已赢取 roundWin 时限制新增赢取，使本局累计不超过上限
+// 用于消除的连锁和免费游戏的多次旋转
+func (c *MaxWinConfig) ApplyRound(roundWin, winAmount, betAmount int64) (int64, bool) {
+	limit := c.Limit(betAmount)
+	if limit <= 0 || roundWin+winAmount < limit {
+		return winAmount, false
+	}
+	if remaining := limit - roundWin; remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+// validate 验证最高赢取配置
+func (c *MaxWinConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Multiplier < 0 || c.Amount < 0 || (c.Multiplier == 0 && c.Amount == 0) {
+		return ErrInvalidMaxWin
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package slot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMaxWinConfig_Limit(t *testing.T) {
+	var unlimited *MaxWinConfig
+	if got, reached := unlimited.Apply(1000000, 100); got != 1000000 || reached {
+		t.Errorf("nil Apply = %d, %v", got, reached)
+	}
+
+	tests := []struct {
+		name   string
+		config *MaxWinConfig
+		bet    int64
+		want   int64
+	}{
+		{"multiplier", &MaxWinConfig{Multiplier: 500}, 100, 50000},
+		{"amount", &MaxWinConfig{Amount: 20000}, 100, 20000},
+		{"smaller of both", &MaxWinConfig{Multiplier: 500, Amount: 20000}, 100, 20000},
+		{"multiplier below amount", &MaxWinConfig{Multiplier: 500, Amount: 20000}, 10, 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.Limit(tt.bet); got != tt.want {
+				t.Errorf("Limit(%d) = %d, want %d", tt.bet, got, tt.want)
+			}
+		})
+	}
+
+	config := &MaxWinConfig{Amount: 1000}
+	if got, reached := config.ApplyRound(0, 999, 100); got != 999 || reached {
+		t.Errorf("below limit = %d, %v", got, reached)
+	}
+	if got, reached := config.ApplyRound(600, 600, 100); got != 400 || !reached {
+		t.Errorf("crossing limit = %d, %v", got, reached)
+	}
+	if got, reached := config.ApplyRound(1000, 50, 100); got != 0 || !reached {
+		t.Errorf("after limit = %d, %v", got, reached)
+	}
+}
+
+func TestValidateConfig_MaxWin(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxWin *MaxWinConfig
+		want   error
+	}{
+		{"unlimited", nil, nil},
+		{"multiplier", &MaxWinConfig{Multiplier: 5000}, nil},
+		{"empty", &MaxWinConfig{}, ErrInvalidMaxWin},
+		{"negative amount", &MaxWinConfig{Multiplier: 100, Amount: -1}, ErrInvalidMaxWin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.MaxWin = tt.maxWin
+			if err := ValidateConfig(config); err != tt.want {
+				t.Errorf("ValidateConfig() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotEngine_MaxWin(t *testing.T) {
+	config := GetDefaultConfig()
+	config.MaxWin = &MaxWinConfig{Multiplier: 2}
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	engine.SetRandomGenerator(NewDRBGRandomGenerator(3))
+
+	const bet = 100
+	capped := 0
+	for i := 0; i < 2000; i++ {
+		result, err := engine.Spin(1, "max_win", bet)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if result.WinAmount > 2*bet {
+			t.Fatalf("WinAmount = %d exceeds cap %d", result.WinAmount, 2*bet)
+		}
+		if result.IsFreeSpin && result.FreeGame.FreeGameWin > 2*result.FreeGame.TriggerBet {
+			t.Fatalf("FreeGameWin = %d exceeds cap", result.FreeGame.FreeGameWin)
+		}
+		if !result.MaxWinReached {
+			continue
+		}
+		capped++
+		// 达到上限的基础旋转不再进入免费游戏，免费游戏达到上限时本轮结束
+		if !result.IsFreeSpin && result.FreeSpins > 0 {
+			t.Fatalf("capped base spin awarded %d free spins", result.FreeSpins)
+		}
+		if result.IsFreeSpin && result.FreeGame.IsActive() {
+			t.Fatalf("free game still active after reaching the cap: %+v", result.FreeGame)
+		}
+	}
+	if capped == 0 {
+		t.Error("no spin reached the max win in 2000 spins")
+	}
+}
+
+func TestCascadeEngines_MaxWin(t *testing.T) {
+	cascadeConfig := GetDefaultCascadeConfig()
+	cascadeConfig.MaxWin = &MaxWinConfig{Amount: 50}
+	request := &SpinRequest{GameRequest: &GameRequest{SessionID: "max_win", BetAmount: 100}}
+
+	cascade := NewCascadeEngine(testMahjongAlgorithmConfig(), cascadeConfig)
+	cascade.SetRandomGenerator(NewDRBGRandomGenerator(5))
+	golden := NewGoldenWildCascadeEngine(testMahjongAlgorithmConfig(), cascadeConfig)
+	golden.SetRandomGenerator(NewDRBGRandomGenerator(5))
+
+	spins := map[string]func() (*CascadeResult, error){
+		"cascade": func() (*CascadeResult, error) {
+			return cascade.SpinCascade(context.Background(), request)
+		},
+		"golden_wild": func() (*CascadeResult, error) {
+			result, err := golden.SpinWithGoldenWild(context.Background(), request)
+			if err != nil {
+				return nil, err
+			}
+			return result.CascadeResult, nil
+		},
+	}
+	for name, spin := range spins {
+		t.Run(name, func(t *testing.T) {
+			capped := 0
+			for i := 0; i < 300; i++ {
+				result, err := spin()
+				if err != nil {
+					t.Fatalf("spin failed: %v", err)
+				}
+				var stepTotal int64
+				for _, step := range result.CascadeDetails {
+					stepTotal += step.StepWin
+				}
+				if result.TotalWin > 50 || stepTotal != result.TotalWin {
+					t.Fatalf("TotalWin = %d, sum of steps %d, cap 50", result.TotalWin, stepTotal)
+				}
+				if result.MaxWinReached {
+					capped++
+					if result.TotalWin != 50 {
+						t.Fatalf("capped TotalWin = %d, want 50", result.TotalWin)
+					}
+				}
+			}
+			if capped == 0 {
+				t.Error("no spin reached the max win in 300 spins")
+			}
+		})
+	}
+}
+
+func TestRunSimulation_MaxWinRate(t *testing.T) {
+	config := GetDefaultConfig()
+	config.MaxWin = &MaxWinConfig{Multiplier: 2}
+	opts := &SimulationOptions{Engine: "slot", Spins: 2000, Workers: 2, Seed: 11, BetAmount: 100}
+
+	report, err := RunSimulation(context.Background(), opts, NewSlotSimulationFactory(config, 100))
+	if err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+	if report.MaxWinHits == 0 {
+		t.Fatal("MaxWinHits = 0")
+	}
+	if report.MaxWinRate != float64(report.MaxWinHits)/float64(report.Spins) {
+		t.Errorf("MaxWinRate = %v, want %v", report.MaxWinRate, float64(report.MaxWinHits)/float64(report.Spins))
+	}
+	if report.MaxWinMultiplier > 2 {
+		t.Errorf("MaxWinMultiplier = %v exceeds cap", report.MaxWinMultiplier)
+	}
+}
@@ -0,0 +1,146 @@
+package slot
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// mustHitSeedSize 必中点种子的字节数
+const mustHitSeedSize = 32
+
+// jackpotSeedKey 必中点种子的加密密钥（由服务端密钥派生），未设置时不能加解密种子
+var jackpotSeedKey []byte
+
+// SetJackpotSeedKey 由服务端密钥派生必中点种子的加密密钥，需在读写奖池之前设置
+// 密钥为空时返回 ErrJackpotSeedKey，不使用任何内置密钥
+func SetJackpotSeedKey(secret string) error {
+	if secret == "" {
+		return ErrJackpotSeedKey
+	}
+	key := sha256.Sum256([]byte(secret))
+	jackpotSeedKey = key[:]
+	return nil
+}
+
+// SealJackpotSeed 用服务端密钥加密必中点种子（AES-256-GCM），奖池只保存密文
+func SealJackpotSeed(seed string) (string, error) {
+	plain, err := hex.DecodeString(seed)
+	if err != nil || len(plain) == 0 {
+		return "", ErrInvalidJackpot
+	}
+	gcm, err := jackpotSeedCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// OpenJackpotSeed 解密奖池保存的必中点种子，旧版本保存的明文种子原样返回
+func OpenJackpotSeed(sealed string) (string, error) {
+	data, err := hex.DecodeString(sealed)
+	if err != nil {
+		return "", ErrInvalidJackpot
+	}
+	if len(data) == mustHitSeedSize {
+		return sealed, nil
+	}
+	gcm, err := jackpotSeedCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", ErrInvalidJackpot
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrInvalidJackpot
+	}
+	return hex.EncodeToString(plain), nil
+}
+
+// jackpotSeedCipher 必中点种子的加密算法
+func jackpotSeedCipher() (cipher.AEAD, error) {
+	if len(jackpotSeedKey) == 0 {
+		return nil, ErrJackpotSeedKey
+	}
+	block, err := aes.NewCipher(jackpotSeedKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// JackpotCommitment 必中奖池的隐藏必中点
+// 奖池注入种子金额时由随机种子派生必中点，只公开承诺哈希，种子加密保存；派奖后公开必中点和种子供审计
+type JackpotCommitment struct {
+	HitPoint int64  `json:"hit_point"` // 必中点（奖池金额达到该值的那次下注中奖）
+	Seed     string `json:"seed"`      // 随机种子（十六进制）
+	Hash     string `json:"hash"`      // 承诺哈希 SHA256("必中点:种子")
+}
+
+// NewJackpotCommitment 用加密随机种子在 (min, max] 内抽取必中点
+func NewJackpotCommitment(min, max int64) (*JackpotCommitment, error) {
+	seed := make([]byte, mustHitSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return newJackpotCommitment(hex.EncodeToString(seed), min, max)
+}
+
+// newJackpotCommitment 由给定种子生成必中点和承诺
+func newJackpotCommitment(seed string, min, max int64) (*JackpotCommitment, error) {
+	hitPoint, err := JackpotHitPoint(seed, min, max)
+	if err != nil {
+		return nil, err
+	}
+	return &JackpotCommitment{
+		HitPoint: hitPoint,
+		Seed:     seed,
+		Hash:     JackpotHitHash(hitPoint, seed),
+	}, nil
+}
+
+// JackpotHitPoint 由种子派生 (min, max] 内的必中点
+// 以 SHA256(种子 || 计数器) 的前8字节为随机数，拒绝采样避免取模偏差，任何人都可以按同样步骤复算
+func JackpotHitPoint(seed string, min, max int64) (int64, error) {
+	key, err := hex.DecodeString(seed)
+	if err != nil || len(key) == 0 || max <= min {
+		return 0, ErrInvalidJackpot
+	}
+
+	n := uint64(max - min)
+	limit := ^uint64(0) - (^uint64(0) % n)
+	input := make([]byte, len(key)+8)
+	copy(input, key)
+	for counter := uint64(0); ; counter++ {
+		binary.BigEndian.PutUint64(input[len(key):], counter)
+		block := sha256.Sum256(input)
+		if v := binary.BigEndian.Uint64(block[:8]); v < limit {
+			return min + 1 + int64(v%n), nil
+		}
+	}
+}
+
+// JackpotHitHash 必中点的承诺哈希
+func JackpotHitHash(hitPoint int64, seed string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", hitPoint, seed)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify 校验公开的必中点和种子：与派奖前公布的承诺哈希一致，且必中点由种子在 (min, max] 内派生
+func (c *JackpotCommitment) Verify(hash string, min, max int64) bool {
+	hitPoint, err := JackpotHitPoint(c.Seed, min, max)
+	if err != nil {
+		return false
+	}
+	return hitPoint == c.HitPoint && JackpotHitHash(c.HitPoint, c.Seed) == hash
+}
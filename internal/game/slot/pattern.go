@@ -4,6 +4,9 @@ import (
 	"math"
 )
 
+// freeSpinsPerScatter 每个Scatter给予的免费旋转次数
+const freeSpinsPerScatter = 2
+
 // AdvancedPatternMatcher 高级图案匹配器
 type AdvancedPatternMatcher struct {
 	config          *SlotConfig
@@ -24,7 +27,29 @@ func (m *AdvancedPatternMatcher) initPaylinePatterns() {
 	rows := m.config.Rows
 	reels := m.config.Reels
 	
-	m.paylinePatterns = [][]Position{
+	// 配置中声明了支付线时全部使用（条数与启用数不一致的配置由 ValidateConfig 拒绝）
+	if len(m.config.Paylines) > 0 {
+		m.paylinePatterns = make([][]Position, 0, len(m.config.Paylines))
+		for _, line := range m.config.Paylines {
+			pattern := make([]Position, len(line))
+			for reel, row := range line {
+				pattern[reel] = Position{Reel: reel, Row: row}
+			}
+			m.paylinePatterns = append(m.paylinePatterns, pattern)
+		}
+		return
+	}
+	
+	// 内置线型限制到启用的支付线数
+	m.paylinePatterns = m.defaultPaylinePatterns(rows, reels)
+	if len(m.paylinePatterns) > m.config.ActivePaylines {
+		m.paylinePatterns = m.paylinePatterns[:m.config.ActivePaylines]
+	}
+}
+
+// defaultPaylinePatterns 内置支付线线型
+func (m *AdvancedPatternMatcher) defaultPaylinePatterns(rows, reels int) [][]Position {
+	return [][]Position{
 		// 水平线
 		m.createHorizontalLine(0, reels),            // 上线
 		m.createHorizontalLine(rows/2, reels),       // 中线
@@ -48,11 +73,6 @@ func (m *AdvancedPatternMatcher) initPaylinePatterns() {
 		// M型
 		m.createMLine(reels, rows),                  // M型
 	}
-	
-	// 限制到配置的支付线数
-	if len(m.paylinePatterns) > m.config.PayLines {
-		m.paylinePatterns = m.paylinePatterns[:m.config.PayLines]
-	}
 }
 
 // FindWinningLines 查找中奖线
@@ -64,28 +84,81 @@ func (m *AdvancedPatternMatcher) FindWinningLines(reels [][]Symbol, config *Slot
 		// 获取支付线上的符号
 		symbols := m.getSymbolsOnLine(reels, pattern)
 		
-		// 检查连续符号
-		if winInfo := m.checkConsecutiveSymbols(symbols, lineID, pattern); winInfo != nil {
+		// 检查连续符号（按配置的派彩方向）
+		winLines = append(winLines, m.evaluateLine(symbols, lineID, pattern)...)
+	}
+	
+	// 检查分散符号（Scatter不需要在支付线上，整个盘面只计算一次）
+	if scatterWin := m.checkScatterSymbols(reels); scatterWin != nil {
+		winLines = append(winLines, *scatterWin)
+	}
+	
+	return winLines
+}
+
+// evaluateLine 按配置的派彩方向计算单条支付线的中奖
+// 双向派彩时从左到右满线的中奖只计一次
+func (m *AdvancedPatternMatcher) evaluateLine(symbols []Symbol, lineID int, pattern []Position) []WinLine {
+	var winLines []WinLine
+	direction := m.payDirection()
+	
+	if direction != PayDirectionRightToLeft {
+		if winInfo := m.evaluateLineOneWay(symbols, lineID, pattern); winInfo != nil {
+			winInfo.Direction = PayDirectionLeftToRight
 			winLines = append(winLines, *winInfo)
-		}
-		
-		// 检查分散符号（Scatter不需要在支付线上）
-		if scatterWin := m.checkScatterSymbols(reels); scatterWin != nil {
-			winLines = append(winLines, *scatterWin)
+			if winInfo.Count == len(symbols) {
+				return winLines
+			}
 		}
 	}
 	
-	// 去重（避免重复计算Scatter）
-	winLines = m.deduplicateWinLines(winLines)
+	if direction != PayDirectionLeftToRight {
+		reversedSymbols, reversedPattern := reverseLine(symbols, pattern)
+		if winInfo := m.evaluateLineOneWay(reversedSymbols, lineID, reversedPattern); winInfo != nil {
+			winInfo.Direction = PayDirectionRightToLeft
+			winLines = append(winLines, *winInfo)
+		}
+	}
 	
 	return winLines
 }
 
+// evaluateLineOneWay 从支付线起点计算单方向中奖
+// 每条支付线独立派彩；Scatter只按分散方式派彩，不在支付线上重复计算
+func (m *AdvancedPatternMatcher) evaluateLineOneWay(symbols []Symbol, lineID int, pattern []Position) *WinLine {
+	winInfo := m.checkConsecutiveSymbols(symbols, lineID, pattern)
+	if winInfo == nil || m.isScatter(winInfo.Symbol) {
+		return nil
+	}
+	return winInfo
+}
+
+// payDirection 获取派彩方向（未配置时从左到右）
+func (m *AdvancedPatternMatcher) payDirection() PayDirection {
+	if m.config.PayDirection == "" {
+		return PayDirectionLeftToRight
+	}
+	return m.config.PayDirection
+}
+
+// reverseLine 反转支付线上的符号和位置（用于从右到左计算）
+func reverseLine(symbols []Symbol, pattern []Position) ([]Symbol, []Position) {
+	n := len(symbols)
+	reversedSymbols := make([]Symbol, n)
+	reversedPattern := make([]Position, n)
+	for i := 0; i < n; i++ {
+		reversedSymbols[i] = symbols[n-1-i]
+		reversedPattern[i] = pattern[n-1-i]
+	}
+	return reversedSymbols, reversedPattern
+}
+
 // CalculatePayout 计算赔付
 func (m *AdvancedPatternMatcher) CalculatePayout(winLines []WinLine, betAmount int64) int64 {
 	var totalPayout int64
 	
-	for _, winLine := range winLines {
+	for i := range winLines {
+		winLine := &winLines[i]
 		// 从赔率表查找倍率
 		multiplier := m.getMultiplier(winLine.Symbol, winLine.Count)
 		
@@ -230,22 +303,25 @@ func (m *AdvancedPatternMatcher) checkScatterSymbols(reels [][]Symbol) *WinLine
 }
 
 // detectFreeSpins 检测免费旋转
+// 触发符号和数量取自免费旋转功能配置（缺省为3个Scatter），获得次数由免费游戏配置决定
 func (m *AdvancedPatternMatcher) detectFreeSpins(reels [][]Symbol) *Feature {
-	scatterCount := 0
+	triggerCount := 0
 	var positions []Position
 	
 	for r := 0; r < len(reels); r++ {
 		for c := 0; c < len(reels[r]); c++ {
-			if m.isScatter(reels[r][c]) {
-				scatterCount++
+			if m.isFreeGameTrigger(reels[r][c]) {
+				triggerCount++
 				positions = append(positions, Position{Reel: r, Row: c})
 			}
 		}
 	}
 	
-	// 3个Scatter触发免费旋转
-	if scatterCount >= 3 {
-		freeSpins := scatterCount * 2 // 每个Scatter给2次免费旋转（降低）
+	if triggerCount >= m.freeGameTriggerCount() {
+		freeSpins := m.config.freeGameConfig().SpinsFor(triggerCount)
+		if freeSpins <= 0 {
+			return nil
+		}
 		return &Feature{
 			Type:        FeatureTypeFreeSpins,
 			TriggerPos:  positions,
@@ -257,6 +333,27 @@ func (m *AdvancedPatternMatcher) detectFreeSpins(reels [][]Symbol) *Feature {
 	return nil
 }
 
+// isFreeGameTrigger 是否为免费游戏触发符号（未配置时为Scatter）
+func (m *AdvancedPatternMatcher) isFreeGameTrigger(symbol Symbol) bool {
+	if feature := m.config.freeGameFeature(); feature != nil && len(feature.TriggerSymbols) > 0 {
+		for _, trigger := range feature.TriggerSymbols {
+			if symbol == trigger {
+				return true
+			}
+		}
+		return false
+	}
+	return m.isScatter(symbol)
+}
+
+// freeGameTriggerCount 触发免费游戏所需的符号数量
+func (m *AdvancedPatternMatcher) freeGameTriggerCount() int {
+	if feature := m.config.freeGameFeature(); feature != nil && feature.TriggerCount > 0 {
+		return feature.TriggerCount
+	}
+	return defaultFreeGameTriggerCount
+}
+
 // detectBonusGame 检测奖励游戏
 func (m *AdvancedPatternMatcher) detectBonusGame(reels [][]Symbol) *Feature {
 	bonusCount := 0
@@ -23,9 +23,9 @@ func TestNewAdvancedPatternMatcher(t *testing.T) {
 	}
 
 	// 支付线数量不应超过配置
-	if len(matcher.paylinePatterns) > config.PayLines {
+	if len(matcher.paylinePatterns) > config.ActivePaylines {
 		t.Errorf("Payline patterns %v exceeds configured %v",
-			len(matcher.paylinePatterns), config.PayLines)
+			len(matcher.paylinePatterns), config.ActivePaylines)
 	}
 }
 
@@ -331,10 +331,10 @@ func TestAdvancedPatternMatcher_checkConsecutiveSymbols(t *testing.T) {
 
 func TestAdvancedPatternMatcher_LinePatterns(t *testing.T) {
 	config := &SlotConfig{
-		Rows:      3,
-		Reels:     5,
-		PayLines:  20,
-		TargetRTP: 0.96,
+		Rows:           3,
+		Reels:          5,
+		ActivePaylines: 20,
+		TargetRTP:      0.96,
 	}
 	matcher := NewAdvancedPatternMatcher(config)
 
@@ -446,4 +446,87 @@ func TestAdvancedPatternMatcher_deduplicateWinLines(t *testing.T) {
 		}
 		combinations[key] = true
 	}
-}
\ No newline at end of file
+}
+func TestAdvancedPatternMatcher_DeclaredPaylines(t *testing.T) {
+	config := GetDefaultConfig()
+	config.ActivePaylines = 2
+	config.Paylines = [][]int{
+		{2, 2, 2, 2, 2},
+		{0, 1, 2, 1, 0},
+	}
+	matcher := NewAdvancedPatternMatcher(config)
+
+	if len(matcher.paylinePatterns) != 2 {
+		t.Fatalf("Payline patterns = %d, want 2", len(matcher.paylinePatterns))
+	}
+	for i, row := range config.Paylines[1] {
+		if matcher.paylinePatterns[1][i] != (Position{Reel: i, Row: row}) {
+			t.Errorf("Pattern position %d = %v, want row %d", i, matcher.paylinePatterns[1][i], row)
+		}
+	}
+
+	// 底行5个樱桃只在第一条声明的支付线上中奖
+	reels := [][]Symbol{
+		{SymbolLemon, SymbolPlum, SymbolCherry},
+		{SymbolOrange, SymbolGrape, SymbolCherry},
+		{SymbolPlum, SymbolBar, SymbolCherry},
+		{SymbolGrape, SymbolSeven, SymbolCherry},
+		{SymbolBar, SymbolLemon, SymbolCherry},
+	}
+	wins := matcher.FindWinningLines(reels, config)
+	if len(wins) != 1 || wins[0].LineID != 0 || wins[0].Count != 5 {
+		t.Fatalf("FindWinningLines = %+v, want one 5-cherry win on line 0", wins)
+	}
+	if wins[0].Direction != PayDirectionLeftToRight {
+		t.Errorf("Direction = %v, want %v", wins[0].Direction, PayDirectionLeftToRight)
+	}
+}
+
+func TestAdvancedPatternMatcher_PayDirection(t *testing.T) {
+	// 第一条支付线（上行）：右侧三个樱桃，左侧两个柠檬
+	reels := [][]Symbol{
+		{SymbolLemon, SymbolPlum, SymbolGrape},
+		{SymbolLemon, SymbolGrape, SymbolPlum},
+		{SymbolCherry, SymbolBar, SymbolOrange},
+		{SymbolCherry, SymbolOrange, SymbolBar},
+		{SymbolCherry, SymbolPlum, SymbolGrape},
+	}
+	fullLine := [][]Symbol{
+		{SymbolCherry, SymbolPlum, SymbolGrape},
+		{SymbolCherry, SymbolGrape, SymbolPlum},
+		{SymbolCherry, SymbolBar, SymbolOrange},
+		{SymbolCherry, SymbolOrange, SymbolBar},
+		{SymbolCherry, SymbolPlum, SymbolGrape},
+	}
+
+	tests := []struct {
+		name      string
+		direction PayDirection
+		reels     [][]Symbol
+		wantWins  int
+		wantDir   PayDirection
+	}{
+		{"从左到右不中奖", PayDirectionLeftToRight, reels, 0, ""},
+		{"默认方向为从左到右", "", reels, 0, ""},
+		{"从右到左中奖", PayDirectionRightToLeft, reels, 1, PayDirectionRightToLeft},
+		{"双向中奖", PayDirectionBoth, reels, 1, PayDirectionRightToLeft},
+		{"双向满线只计一次", PayDirectionBoth, fullLine, 1, PayDirectionLeftToRight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.ActivePaylines = 1
+			config.PayDirection = tt.direction
+			matcher := NewAdvancedPatternMatcher(config)
+
+			wins := matcher.FindWinningLines(tt.reels, config)
+			if len(wins) != tt.wantWins {
+				t.Fatalf("FindWinningLines = %+v, want %d wins", wins, tt.wantWins)
+			}
+			if tt.wantWins > 0 && wins[0].Direction != tt.wantDir {
+				t.Errorf("Direction = %v, want %v", wins[0].Direction, tt.wantDir)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package slot
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+)
+
+// DRBGRandomGenerator 确定性随机数生成器（SHA-256计数器模式）
+// 相同种子产生完全相同的随机序列，用于单次旋转的重放与审计
+type DRBGRandomGenerator struct {
+	mu      sync.Mutex
+	key     [sha256.Size]byte // 由种子派生的密钥
+	counter uint64            // 块计数器
+	block   [sha256.Size]byte // 当前输出块
+	offset  int               // 当前块已消耗的字节数
+}
+
+// NewDRBGRandomGenerator 创建确定性随机数生成器
+func NewDRBGRandomGenerator(seed int64) *DRBGRandomGenerator {
+	g := &DRBGRandomGenerator{}
+	g.Seed(seed)
+	return g
+}
+
+// Seed 设置种子并重置内部状态
+func (g *DRBGRandomGenerator) Seed(seed int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(seed))
+	g.key = sha256.Sum256(buf[:])
+	g.counter = 0
+	g.offset = sha256.Size // 强制下次读取时生成新块
+}
+
+// Next 生成下一个随机数 [0, 1)
+func (g *DRBGRandomGenerator) Next() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// 取53位以获得均匀分布的float64
+	return float64(g.nextUint64()>>11) / float64(1<<53)
+}
+
+// NextInt 生成指定范围内的随机整数 [min, max)
+func (g *DRBGRandomGenerator) NextInt(min, max int) int {
+	if min >= max {
+		return min
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// 拒绝采样，避免取模偏差
+	n := uint64(max - min)
+	limit := ^uint64(0) - (^uint64(0) % n)
+	for {
+		v := g.nextUint64()
+		if v < limit {
+			return min + int(v%n)
+		}
+	}
+}
+
+// nextUint64 读取下一个64位随机值（调用方需持有锁）
+func (g *DRBGRandomGenerator) nextUint64() uint64 {
+	if g.offset+8 > sha256.Size {
+		g.refill()
+	}
+	v := binary.BigEndian.Uint64(g.block[g.offset : g.offset+8])
+	g.offset += 8
+	return v
+}
+
+// refill 生成下一个输出块: SHA256(key || counter)
+func (g *DRBGRandomGenerator) refill() {
+	var input [sha256.Size + 8]byte
+	copy(input[:], g.key[:])
+	binary.BigEndian.PutUint64(input[sha256.Size:], g.counter)
+	g.block = sha256.Sum256(input[:])
+	g.counter++
+	g.offset = 0
+}
+
+// nextSpinSeed 从种子源生成单次旋转的非零种子
+func nextSpinSeed(source RandomGenerator) int64 {
+	for {
+		hi := int64(source.NextInt(0, 1<<31))
+		lo := int64(source.NextInt(0, 1<<31))
+		if seed := hi<<31 | lo; seed != 0 {
+			return seed
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package slot
+
+import (
+	"testing"
+)
+
+func TestDRBGRandomGenerator_Deterministic(t *testing.T) {
+	a := NewDRBGRandomGenerator(42)
+	b := NewDRBGRandomGenerator(42)
+
+	for i := 0; i < 1000; i++ {
+		if va, vb := a.NextInt(0, 1000), b.NextInt(0, 1000); va != vb {
+			t.Fatalf("draw %d: NextInt differs for same seed: %d != %d", i, va, vb)
+		}
+		if va, vb := a.Next(), b.Next(); va != vb {
+			t.Fatalf("draw %d: Next differs for same seed: %v != %v", i, va, vb)
+		}
+	}
+
+	// 重新播种后序列应从头开始
+	first := NewDRBGRandomGenerator(7).NextInt(0, 1<<30)
+	a.Seed(7)
+	if got := a.NextInt(0, 1<<30); got != first {
+		t.Errorf("Seed(7) did not reset sequence: got %d, want %d", got, first)
+	}
+}
+
+func TestDRBGRandomGenerator_Range(t *testing.T) {
+	rng := NewDRBGRandomGenerator(1)
+
+	for i := 0; i < 1000; i++ {
+		if val := rng.Next(); val < 0 || val >= 1 {
+			t.Fatalf("Next() returned %v, expected [0, 1)", val)
+		}
+		if val := rng.NextInt(10, 20); val < 10 || val >= 20 {
+			t.Fatalf("NextInt(10, 20) returned %v", val)
+		}
+	}
+
+	if val := rng.NextInt(5, 5); val != 5 {
+		t.Errorf("NextInt(5, 5) returned %v, expected 5", val)
+	}
+}
+
+func TestDRBGRandomGenerator_DifferentSeeds(t *testing.T) {
+	a := NewDRBGRandomGenerator(1)
+	b := NewDRBGRandomGenerator(2)
+
+	same := 0
+	for i := 0; i < 100; i++ {
+		if a.NextInt(0, 1<<30) == b.NextInt(0, 1<<30) {
+			same++
+		}
+	}
+	if same > 5 {
+		t.Errorf("different seeds produced %d identical draws out of 100", same)
+	}
+}
+
+func TestNextSpinSeed(t *testing.T) {
+	source := NewDRBGRandomGenerator(99)
+	seen := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		seed := nextSpinSeed(source)
+		if seed == 0 {
+			t.Fatal("nextSpinSeed returned 0")
+		}
+		if seen[seed] {
+			t.Errorf("nextSpinSeed returned duplicate seed %d", seed)
+		}
+		seen[seed] = true
+	}
+}
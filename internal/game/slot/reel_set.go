@@ -0,0 +1,246 @@
+package slot
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultReelSetID 默认卷轴组ID（SlotConfig.ReelStrips）
+const DefaultReelSetID = "default"
+
+// reelSetRTPTolerance 认证RTP与计算RTP允许的误差
+const reelSetRTPTolerance = 1e-4
+
+// 默认奖池水位（货币单位）
+const (
+	defaultPoolLowWater  int64 = -100000
+	defaultPoolHighWater int64 = 100000
+)
+
+// ReelSetSelector 卷轴组选择器
+// RTP控制器实现该接口后，引擎在每次旋转前通过它选择卷轴组，结果本身不再被干预
+type ReelSetSelector interface {
+	// SelectReelSet 选择卷轴组，sessionReelSetID为会话当前使用的卷轴组（可能为空）
+	SelectReelSet(sets []ReelSet, sessionReelSetID string) string
+
+	// RecordSpin 记录旋转结果
+	RecordSpin(reelSetID string, betAmount, winAmount int64)
+}
+
+// ReelSetStats 卷轴组使用统计
+type ReelSetStats struct {
+	Spins    int64   `json:"spins"`     // 旋转次数
+	TotalBet int64   `json:"total_bet"` // 总下注
+	TotalWin int64   `json:"total_win"` // 总赢取
+	RTP      float64 `json:"rtp"`       // 实际RTP
+}
+
+// ReelSetController 卷轴组RTP控制器
+// 不干预单次旋转结果，只根据运营目标RTP和奖池余额在认证卷轴组之间切换
+type ReelSetController struct {
+	mu          sync.RWMutex
+	targetRTP   float64                  // 运营目标RTP
+	perSession  bool                     // 按会话选择（会话内保持同一卷轴组）
+	poolBalance int64                    // 奖池余额（每次旋转计提 下注×目标RTP − 赔付）
+	poolLow     int64                    // 低水位，低于时切换到较低RTP卷轴组
+	poolHigh    int64                    // 高水位，高于时切换到较高RTP卷轴组
+	totalBet    int64                    // 总下注
+	totalWin    int64                    // 总赢取
+	setStats    map[string]*ReelSetStats // 各卷轴组统计
+}
+
+// NewReelSetController 创建卷轴组RTP控制器
+func NewReelSetController(targetRTP float64) *ReelSetController {
+	return &ReelSetController{
+		targetRTP: targetRTP,
+		poolLow:   defaultPoolLowWater,
+		poolHigh:  defaultPoolHighWater,
+		setStats:  make(map[string]*ReelSetStats),
+	}
+}
+
+// SetTargetRTP 设置运营目标RTP
+func (c *ReelSetController) SetTargetRTP(targetRTP float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.targetRTP = targetRTP
+}
+
+// SetPerSession 设置是否按会话选择卷轴组
+func (c *ReelSetController) SetPerSession(perSession bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.perSession = perSession
+}
+
+// SetPoolBounds 设置奖池高低水位
+func (c *ReelSetController) SetPoolBounds(low, high int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.poolLow = low
+	c.poolHigh = high
+}
+
+// SetPoolBalance 同步奖池余额（如由运营后台统一管理）
+func (c *ReelSetController) SetPoolBalance(balance int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.poolBalance = balance
+}
+
+// GetPoolBalance 获取奖池余额
+func (c *ReelSetController) GetPoolBalance() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.poolBalance
+}
+
+// SelectReelSet 选择卷轴组
+// 以不超过目标RTP的最高卷轴组为基准；奖池低于低水位时降一档，高于高水位时升一档
+func (c *ReelSetController) SelectReelSet(sets []ReelSet, sessionReelSetID string) string {
+	if len(sets) == 0 {
+		return DefaultReelSetID
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.perSession && sessionReelSetID != "" {
+		for _, set := range sets {
+			if set.ID == sessionReelSetID {
+				return sessionReelSetID
+			}
+		}
+	}
+
+	sorted := make([]ReelSet, len(sets))
+	copy(sorted, sets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TheoreticalRTP < sorted[j].TheoreticalRTP
+	})
+
+	index := 0
+	for i, set := range sorted {
+		if set.TheoreticalRTP <= c.targetRTP {
+			index = i
+		}
+	}
+
+	if c.poolBalance < c.poolLow && index > 0 {
+		index--
+	} else if c.poolBalance > c.poolHigh && index < len(sorted)-1 {
+		index++
+	}
+
+	return sorted[index].ID
+}
+
+// RecordSpin 记录旋转结果并更新奖池
+func (c *ReelSetController) RecordSpin(reelSetID string, betAmount, winAmount int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.totalBet += betAmount
+	c.totalWin += winAmount
+	c.poolBalance += int64(math.Round(float64(betAmount)*c.targetRTP)) - winAmount
+
+	stats, exists := c.setStats[reelSetID]
+	if !exists {
+		stats = &ReelSetStats{}
+		c.setStats[reelSetID] = stats
+	}
+	stats.Spins++
+	stats.TotalBet += betAmount
+	stats.TotalWin += winAmount
+	if stats.TotalBet > 0 {
+		stats.RTP = float64(stats.TotalWin) / float64(stats.TotalBet)
+	}
+}
+
+// GetReelSetStats 获取各卷轴组使用统计
+func (c *ReelSetController) GetReelSetStats() map[string]ReelSetStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]ReelSetStats, len(c.setStats))
+	for id, stats := range c.setStats {
+		result[id] = *stats
+	}
+	return result
+}
+
+// AdjustOdds 卷轴组模式不调整赔率
+func (c *ReelSetController) AdjustOdds(currentRTP, targetRTP float64) float64 {
+	return 1.0
+}
+
+// ShouldTriggerWin 卷轴组模式不干预中奖
+func (c *ReelSetController) ShouldTriggerWin(currentRTP, targetRTP float64, betAmount int64) bool {
+	return false
+}
+
+// CalculateRTP 计算当前RTP
+func (c *ReelSetController) CalculateRTP(totalWin, totalBet int64) float64 {
+	if totalBet == 0 {
+		return 0
+	}
+	return float64(totalWin) / float64(totalBet)
+}
+
+// GetCompensationMultiplier 卷轴组模式不补偿赔付
+func (c *ReelSetController) GetCompensationMultiplier(currentRTP, targetRTP float64) float64 {
+	return 1.0
+}
+
+// CalculateReelSetRTP 计算指定卷轴组的理论RTP
+func CalculateReelSetRTP(config *SlotConfig, set ReelSet) (*TheoreticalRTP, error) {
+	setConfig := *config
+	setConfig.ReelStrips = set.ReelStrips
+	result, err := CalculateTheoreticalRTP(&setConfig)
+	if err != nil {
+		return nil, err
+	}
+	result.ReelSetID = set.ID
+	return result, nil
+}
+
+// validateReelSets 校验卷轴组：ID唯一、卷轴数一致、认证RTP与计算结果一致且在允许范围内
+func validateReelSets(config *SlotConfig) error {
+	seen := map[string]bool{DefaultReelSetID: true}
+	for _, set := range config.ReelSets {
+		if set.ID == "" || seen[set.ID] {
+			return ErrInvalidReelStrips
+		}
+		seen[set.ID] = true
+		if len(set.ReelStrips) != config.Reels {
+			return ErrInvalidReelStrips
+		}
+
+		theory, err := CalculateReelSetRTP(config, set)
+		if err != nil {
+			return err
+		}
+		if set.TheoreticalRTP > 0 && math.Abs(set.TheoreticalRTP-theory.TotalRTP) > reelSetRTPTolerance {
+			return ErrReelSetRTPMismatch
+		}
+		if config.RTPBand != nil && !config.Ante.reserves(set.ID) && (theory.TotalRTP < config.RTPBand.Min || theory.TotalRTP > config.RTPBand.Max) {
+			return ErrRTPOutOfRange
+		}
+	}
+	return nil
+}
+
+// certifiedReelSets 返回填充了理论RTP的卷轴组副本（配置已通过校验）
+func certifiedReelSets(config *SlotConfig) []ReelSet {
+	sets := make([]ReelSet, 0, len(config.ReelSets))
+	for _, set := range config.ReelSets {
+		if set.TheoreticalRTP == 0 {
+			if theory, err := CalculateReelSetRTP(config, set); err == nil {
+				set.TheoreticalRTP = theory.TotalRTP
+			}
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
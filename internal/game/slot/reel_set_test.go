@@ -0,0 +1,199 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+// testReelSet 以默认卷轴条为基础，按倍数调整樱桃权重生成卷轴组
+func testReelSet(id string, cherryFactor int) ReelSet {
+	strips := GetDefaultConfig().ReelStrips
+	for i := range strips {
+		weights := make([]int, len(strips[i].Weights))
+		copy(weights, strips[i].Weights)
+		for j, symbol := range strips[i].Symbols {
+			if symbol == SymbolCherry {
+				weights[j] *= cherryFactor
+			}
+		}
+		strips[i].Weights = weights
+	}
+	return ReelSet{ID: id, Name: id, ReelStrips: strips}
+}
+
+func reelSetTestConfig() *SlotConfig {
+	config := GetDefaultConfig()
+	config.Mode = EngineModePureMath
+	config.ReelSets = []ReelSet{
+		testReelSet("rtp_low", 8),
+		testReelSet("rtp_mid", 4),
+		testReelSet("rtp_high", 1),
+	}
+	return config
+}
+
+func TestReelSetController_SelectReelSet(t *testing.T) {
+	sets := []ReelSet{
+		{ID: "high", TheoreticalRTP: 0.96},
+		{ID: "low", TheoreticalRTP: 0.92},
+		{ID: "mid", TheoreticalRTP: 0.94},
+	}
+
+	tests := []struct {
+		name      string
+		targetRTP float64
+		pool      int64
+		want      string
+	}{
+		{"目标RTP对应卷轴组", 0.95, 0, "mid"},
+		{"目标RTP低于所有卷轴组", 0.90, 0, "low"},
+		{"奖池低于低水位降档", 0.95, -200000, "low"},
+		{"奖池高于高水位升档", 0.95, 200000, "high"},
+		{"最高档不再升档", 0.97, 200000, "high"},
+		{"最低档不再降档", 0.92, -200000, "low"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewReelSetController(tt.targetRTP)
+			controller.SetPoolBalance(tt.pool)
+			if got := controller.SelectReelSet(sets, ""); got != tt.want {
+				t.Errorf("SelectReelSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReelSetController_PerSession(t *testing.T) {
+	sets := []ReelSet{
+		{ID: "low", TheoreticalRTP: 0.92},
+		{ID: "high", TheoreticalRTP: 0.96},
+	}
+	controller := NewReelSetController(0.96)
+	controller.SetPerSession(true)
+
+	if got := controller.SelectReelSet(sets, "low"); got != "low" {
+		t.Errorf("per-session selection = %v, want session set low", got)
+	}
+	if got := controller.SelectReelSet(sets, "removed"); got != "high" {
+		t.Errorf("unknown session set should fall back to selection, got %v", got)
+	}
+
+	controller.SetPerSession(false)
+	if got := controller.SelectReelSet(sets, "low"); got != "high" {
+		t.Errorf("per-spin selection = %v, want high", got)
+	}
+}
+
+func TestReelSetController_RecordSpin(t *testing.T) {
+	controller := NewReelSetController(0.95)
+	controller.RecordSpin("a", 100, 0)
+	controller.RecordSpin("a", 100, 300)
+
+	// 100*0.95*2 - 300 = -110
+	if got := controller.GetPoolBalance(); got != -110 {
+		t.Errorf("pool balance = %v, want -110", got)
+	}
+	stats := controller.GetReelSetStats()["a"]
+	if stats.Spins != 2 || stats.TotalBet != 200 || stats.TotalWin != 300 || stats.RTP != 1.5 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestSlotEngine_ReelSets(t *testing.T) {
+	config := reelSetTestConfig()
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if _, ok := engine.rtpController.(*ReelSetController); !ok {
+		t.Fatalf("pure math engine with reel sets should use ReelSetController, got %T", engine.rtpController)
+	}
+
+	// 每个卷轴组都填充了理论RTP
+	valid := map[string]bool{}
+	for _, set := range engine.GetReelSets() {
+		if set.TheoreticalRTP <= 0 {
+			t.Errorf("reel set %s has no theoretical RTP", set.ID)
+		}
+		valid[set.ID] = true
+	}
+
+	engine.EnableSeededMode()
+	for i := 0; i < 50; i++ {
+		result, err := engine.Spin(1, "reel-set", 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		if !valid[result.ReelSetID] {
+			t.Fatalf("spin %d recorded unknown reel set %q", i, result.ReelSetID)
+		}
+
+		replayed, err := engine.Replay(result)
+		if err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		if replayed.WinAmount != result.WinAmount || replayed.ReelSetID != result.ReelSetID {
+			t.Fatalf("spin %d replay mismatch", i)
+		}
+	}
+}
+
+func TestSlotEngine_ReplayUnknownReelSet(t *testing.T) {
+	engine, err := NewSlotEngine(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if _, err := engine.Replay(&SpinResult{Seed: 1, BetAmount: 100, ReelSetID: "missing"}); err != ErrUnknownReelSet {
+		t.Errorf("Replay() error = %v, want %v", err, ErrUnknownReelSet)
+	}
+}
+
+func TestValidateConfig_ReelSets(t *testing.T) {
+	config := reelSetTestConfig()
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("valid reel sets rejected: %v", err)
+	}
+
+	// 认证RTP与计算值一致
+	theory, err := CalculateReelSetRTP(config, config.ReelSets[0])
+	if err != nil {
+		t.Fatalf("CalculateReelSetRTP failed: %v", err)
+	}
+	config.ReelSets[0].TheoreticalRTP = theory.TotalRTP
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("matching certified RTP rejected: %v", err)
+	}
+
+	config.ReelSets[0].TheoreticalRTP = theory.TotalRTP + 0.01
+	if err := ValidateConfig(config); err != ErrReelSetRTPMismatch {
+		t.Errorf("err = %v, want ErrReelSetRTPMismatch", err)
+	}
+
+	config = reelSetTestConfig()
+	config.ReelSets[1].ID = config.ReelSets[0].ID
+	if err := ValidateConfig(config); err != ErrInvalidReelStrips {
+		t.Errorf("duplicate ID: err = %v, want ErrInvalidReelStrips", err)
+	}
+
+	config = reelSetTestConfig()
+	config.ReelSets[2].ReelStrips = config.ReelSets[2].ReelStrips[:3]
+	if err := ValidateConfig(config); err != ErrInvalidReelStrips {
+		t.Errorf("wrong reel count: err = %v, want ErrInvalidReelStrips", err)
+	}
+}
+
+func TestCalculateReelSetRTP_Differs(t *testing.T) {
+	config := reelSetTestConfig()
+	low, err := CalculateReelSetRTP(config, config.ReelSets[0])
+	if err != nil {
+		t.Fatalf("CalculateReelSetRTP failed: %v", err)
+	}
+	high, err := CalculateReelSetRTP(config, config.ReelSets[2])
+	if err != nil {
+		t.Fatalf("CalculateReelSetRTP failed: %v", err)
+	}
+	if low.ReelSetID != "rtp_low" || math.Abs(low.TotalRTP-high.TotalRTP) < 1e-6 {
+		t.Errorf("reel sets should have different RTPs: %v vs %v", low.TotalRTP, high.TotalRTP)
+	}
+}
@@ -18,6 +18,7 @@ type DynamicRTPController struct {
 	volatilityFactor    float64             // 波动因子
 	minRTP              float64             // 最小RTP
 	maxRTP              float64             // 最大RTP
+	randomGen           RandomGenerator     // 随机数生成器
 }
 
 // RTPHistory RTP历史记录
@@ -50,9 +51,17 @@ func NewDynamicRTPController(targetRTP float64) *DynamicRTPController {
 		volatilityFactor:   0.1,                  // 波动因子
 		minRTP:             targetRTP * 0.85,     // 最小85%目标RTP
 		maxRTP:             targetRTP * 1.15,     // 最大115%目标RTP
+		randomGen:          NewCryptoRandomGenerator(),
 	}
 }
 
+// SetRandomGenerator 设置随机数生成器
+func (c *DynamicRTPController) SetRandomGenerator(rng RandomGenerator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.randomGen = rng
+}
+
 // newRTPHistory 创建RTP历史记录
 func newRTPHistory(window time.Duration, maxSamples int) *RTPHistory {
 	return &RTPHistory{
@@ -116,14 +125,14 @@ func (c *DynamicRTPController) ShouldTriggerWin(currentRTP, targetRTP float64, b
 	baseWinProbability *= betFactor
 	
 	// 添加随机波动
-	volatility := c.volatilityFactor * (0.5 - randomFloat()) // randomFloat() 返回0-1的随机数
+	volatility := c.volatilityFactor * (0.5 - c.randomGen.Next()) // Next() 返回0-1的随机数
 	baseWinProbability += volatility
 	
 	// 限制概率范围
 	baseWinProbability = math.Max(0.1, math.Min(0.9, baseWinProbability))
 	
 	// 生成随机数判断
-	return randomFloat() < baseWinProbability
+	return c.randomGen.Next() < baseWinProbability
 }
 
 // CalculateRTP 计算当前RTP
@@ -237,21 +246,20 @@ func (h *RTPHistory) addSample(sample RTPSample) {
 	h.totalBet += sample.bet
 	h.totalWin += sample.win
 	
-	// 清理过期样本
+	// 清理过期样本（样本按时间顺序追加，只需从头部裁剪）
 	cutoff := time.Now().Add(-h.windowDuration)
-	validSamples := make([]RTPSample, 0)
-	
+	expired := 0
 	for _, s := range h.samples {
 		if s.timestamp.After(cutoff) {
-			validSamples = append(validSamples, s)
-		} else {
-			// 从总计中减去过期样本
-			h.totalBet -= s.bet
-			h.totalWin -= s.win
+			break
 		}
+		// 从总计中减去过期样本
+		h.totalBet -= s.bet
+		h.totalWin -= s.win
+		expired++
 	}
 	
-	h.samples = validSamples
+	h.samples = h.samples[expired:]
 	
 	// 添加新样本
 	h.samples = append(h.samples, sample)
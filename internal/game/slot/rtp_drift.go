@@ -0,0 +1,113 @@
+package slot
+
+import "math"
+
+// 漂移检测默认参数
+const (
+	DefaultDriftConfidence = 0.99 // 默认置信水平
+	MinDriftSpins          = 1000 // 付费旋转少于该数量时样本不足，不告警
+)
+
+// RTPDriftSample 同一档下注的实际旋转汇总（彩金单独统计，不计入）
+// 单次回报率 x = 赢取 / 档位下注，免费旋转计入赢取但不计下注
+type RTPDriftSample struct {
+	BetLevel    int64   `json:"bet_level"`     // 下注档位（名义下注）
+	Spins       int64   `json:"spins"`         // 旋转次数（含免费旋转）
+	PaidSpins   int64   `json:"paid_spins"`    // 付费旋转次数
+	TotalBet    int64   `json:"total_bet"`     // 实际下注合计
+	TotalWin    int64   `json:"total_win"`     // 赢取合计
+	ExpectedWin float64 `json:"expected_win"`  // 理论赢取合计 Σ(下注 × 理论RTP)
+	SumReturn   float64 `json:"sum_return"`    // Σx
+	SumReturnSq float64 `json:"sum_return_sq"` // Σx²
+}
+
+// RTPDrift 实际RTP与理论RTP的偏离检测结果
+type RTPDrift struct {
+	Spins           int64   `json:"spins"`
+	PaidSpins       int64   `json:"paid_spins"`
+	TotalBet        int64   `json:"total_bet"`
+	TotalWin        int64   `json:"total_win"`
+	ActualRTP       float64 `json:"actual_rtp"`
+	TheoreticalRTP  float64 `json:"theoretical_rtp"`
+	Deviation       float64 `json:"deviation"`        // 实际 - 理论
+	StandardError   float64 `json:"standard_error"`   // 实际RTP的标准误差
+	Bound           float64 `json:"bound"`            // 置信水平下允许的偏离
+	ConfidenceLevel float64 `json:"confidence_level"` // 置信水平
+	PValue          float64 `json:"p_value"`          // 理论RTP成立时出现该偏离的双侧概率
+	Sufficient      bool    `json:"sufficient"`       // 样本量是否足够
+	Alarm           bool    `json:"alarm"`            // 偏离超出统计界限
+}
+
+// CheckRTPDrift 合并各档下注的样本，检测实际RTP是否显著偏离理论RTP
+// 实际RTP按下注金额加权，方差为各档方差之和：Var = Σ(σ²·n·档位²) / (Σ下注)²
+func CheckRTPDrift(samples []RTPDriftSample, confidenceLevel float64) *RTPDrift {
+	if confidenceLevel <= 0 || confidenceLevel >= 1 {
+		confidenceLevel = DefaultDriftConfidence
+	}
+	drift := &RTPDrift{ConfidenceLevel: confidenceLevel}
+
+	var expectedWin, variance float64
+	for _, s := range samples {
+		drift.Spins += s.Spins
+		drift.PaidSpins += s.PaidSpins
+		drift.TotalBet += s.TotalBet
+		drift.TotalWin += s.TotalWin
+		expectedWin += s.ExpectedWin
+
+		// 单次回报率的样本方差（以档位下注为单位）
+		if s.Spins > 1 {
+			n := float64(s.Spins)
+			mean := s.SumReturn / n
+			v := (s.SumReturnSq - n*mean*mean) / (n - 1)
+			if v > 0 {
+				level := float64(s.BetLevel)
+				variance += v * n * level * level
+			}
+		}
+	}
+	if drift.TotalBet <= 0 {
+		return drift
+	}
+
+	totalBet := float64(drift.TotalBet)
+	drift.ActualRTP = float64(drift.TotalWin) / totalBet
+	drift.TheoreticalRTP = expectedWin / totalBet
+	drift.Deviation = drift.ActualRTP - drift.TheoreticalRTP
+	drift.StandardError = math.Sqrt(variance) / totalBet
+	drift.Bound = zScore(confidenceLevel) * drift.StandardError
+	drift.PValue = calculateConfidenceLevel(drift.StandardError, drift.ActualRTP, drift.TheoreticalRTP)
+	drift.Sufficient = drift.PaidSpins >= MinDriftSpins
+	drift.Alarm = drift.Sufficient && math.Abs(drift.Deviation) > drift.Bound
+	return drift
+}
+
+// TheoreticalRTP 一次旋转的理论RTP（不含彩金）
+// 使用认证卷轴组时取该卷轴组的理论RTP，否则取机台配置的理论RTP（首次计算后缓存）；加注旋转按加注倍数折算
+func (e *SlotEngine) TheoreticalRTP(reelSetID string, ante bool) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, set := range e.reelSets {
+		if set.ID == reelSetID && set.TheoreticalRTP > 0 {
+			if ante && e.config.Ante != nil && e.config.Ante.Multiplier > 0 {
+				return set.TheoreticalRTP / e.config.Ante.Multiplier
+			}
+			return set.TheoreticalRTP
+		}
+	}
+
+	if rtp, ok := e.theoryRTP[ante]; ok {
+		return rtp
+	}
+	var rtp float64
+	if ante {
+		rtp, _ = CalculateAnteRTP(e.config)
+	} else if theory, err := CalculateTheoreticalRTP(e.config); err == nil {
+		rtp = theory.TotalRTP
+	}
+	if e.theoryRTP == nil {
+		e.theoryRTP = make(map[bool]float64)
+	}
+	e.theoryRTP[ante] = rtp
+	return rtp
+}
@@ -0,0 +1,107 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+// driftSample 模拟固定下注的旋转：每次以概率 p 赢取 multiplier 倍下注
+func driftSample(seed int64, spins int, bet int64, p, multiplier, theory float64) RTPDriftSample {
+	rng := NewDRBGRandomGenerator(seed)
+	sample := RTPDriftSample{BetLevel: bet}
+	for i := 0; i < spins; i++ {
+		var win int64
+		if rng.Next() < p {
+			win = int64(float64(bet) * multiplier)
+		}
+		x := float64(win) / float64(bet)
+		sample.Spins++
+		sample.PaidSpins++
+		sample.TotalBet += bet
+		sample.TotalWin += win
+		sample.ExpectedWin += float64(bet) * theory
+		sample.SumReturn += x
+		sample.SumReturnSq += x * x
+	}
+	return sample
+}
+
+func TestCheckRTPDrift(t *testing.T) {
+	sample := driftSample(1, 20000, 100, 0.48, 2, 0.96)
+
+	// 与模拟器一致：标准误差 = σ / √n
+	drift := CheckRTPDrift([]RTPDriftSample{sample}, 0.99)
+	n := float64(sample.Spins)
+	mean := sample.SumReturn / n
+	sigma := math.Sqrt((sample.SumReturnSq - n*mean*mean) / (n - 1))
+	if math.Abs(drift.StandardError-sigma/math.Sqrt(n)) > 1e-12 {
+		t.Errorf("standard error = %f, want %f", drift.StandardError, sigma/math.Sqrt(n))
+	}
+	if math.Abs(drift.Bound-zScore(0.99)*drift.StandardError) > 1e-12 {
+		t.Errorf("bound = %f", drift.Bound)
+	}
+	if math.Abs(drift.TheoreticalRTP-0.96) > 1e-9 {
+		t.Errorf("theoretical RTP = %f, want 0.96", drift.TheoreticalRTP)
+	}
+	if !drift.Sufficient || drift.Alarm {
+		t.Errorf("RTP within bound should not alarm: %+v", drift)
+	}
+
+	// 理论RTP配置错误（0.80）时偏离远超界限
+	wrong := sample
+	wrong.ExpectedWin = float64(wrong.TotalBet) * 0.80
+	drift = CheckRTPDrift([]RTPDriftSample{wrong}, 0)
+	if drift.ConfidenceLevel != DefaultDriftConfidence || !drift.Alarm || drift.PValue >= 0.01 {
+		t.Errorf("drifted RTP should alarm: %+v", drift)
+	}
+
+	// 样本不足时不告警
+	small := driftSample(2, 200, 100, 0.48, 2, 0.80)
+	if drift := CheckRTPDrift([]RTPDriftSample{small}, 0.99); drift.Sufficient || drift.Alarm {
+		t.Errorf("small sample should not alarm: %+v", drift)
+	}
+
+	// 多档下注按下注金额合并
+	high := driftSample(3, 20000, 1000, 0.48, 2, 0.96)
+	combined := CheckRTPDrift([]RTPDriftSample{sample, high}, 0.99)
+	if combined.TotalBet != sample.TotalBet+high.TotalBet || combined.Spins != 40000 {
+		t.Errorf("combined drift = %+v", combined)
+	}
+	wantRTP := float64(sample.TotalWin+high.TotalWin) / float64(sample.TotalBet+high.TotalBet)
+	if math.Abs(combined.ActualRTP-wantRTP) > 1e-12 || combined.Alarm {
+		t.Errorf("combined RTP = %f, want %f (alarm %v)", combined.ActualRTP, wantRTP, combined.Alarm)
+	}
+
+	// 没有下注时只有计数
+	if empty := CheckRTPDrift(nil, 0.99); empty.ActualRTP != 0 || empty.Alarm {
+		t.Errorf("empty drift = %+v", empty)
+	}
+}
+
+func TestSlotEngine_TheoreticalRTP(t *testing.T) {
+	config := GetDefaultConfig()
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	theory, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+	if got := engine.TheoreticalRTP("", false); math.Abs(got-theory.TotalRTP) > 1e-12 {
+		t.Errorf("TheoreticalRTP = %f, want %f", got, theory.TotalRTP)
+	}
+
+	// 加注旋转取加注卷轴组的理论RTP并按加注倍数折算
+	pharaoh, err := NewSlotEngine(GetPharaohConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	anteRTP, err := CalculateAnteRTP(pharaoh.GetConfig())
+	if err != nil {
+		t.Fatalf("CalculateAnteRTP failed: %v", err)
+	}
+	if got := pharaoh.TheoreticalRTP("ante", true); math.Abs(got-anteRTP) > 1e-9 {
+		t.Errorf("ante TheoreticalRTP = %f, want %f", got, anteRTP)
+	}
+}
@@ -0,0 +1,682 @@
+package slot
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SimulationSample 单次旋转的仿真样本
+type SimulationSample struct {
+	BetAmount     int64            // 实际扣费（免费旋转为0）
+	WinAmount     int64            // 赢取金额
+	Features      []string         // 本次触发的特殊功能
+	FeatureWins   map[string]int64 // 赢取中归属各特殊功能的部分（计算功能的RTP贡献）
+	MaxWinReached bool             // 本次赢取达到最高赢取
+	GambleRounds  int              // 本次中奖后的博倍竞猜次数
+	GambleStake   int64            // 博倍竞猜投入的金额合计
+	GambleWin     int64            // 博倍竞猜返还的金额合计
+}
+
+// SimulationSpinner 仿真使用的单次旋转函数（每个工作协程一个，非并发安全）
+type SimulationSpinner func(ctx context.Context) (*SimulationSample, error)
+
+// SimulationSpinnerFactory 为每个工作协程创建旋转函数
+type SimulationSpinnerFactory func(rng RandomGenerator) (SimulationSpinner, error)
+
+// SimulationOptions 仿真参数
+type SimulationOptions struct {
+	Engine          string          `json:"engine"`           // 引擎类型
+	ConfigName      string          `json:"config_name"`      // 配置名称
+	Spins           int64           `json:"spins"`            // 总旋转次数
+	Workers         int             `json:"workers"`          // 工作协程数（默认CPU核数）
+	Seed            int64           `json:"seed"`             // 主种子（0表示使用加密随机数）
+	BetAmount       int64           `json:"bet_amount"`       // 名义下注额
+	TargetRTP       float64         `json:"target_rtp"`       // 目标RTP
+	ConfidenceLevel float64         `json:"confidence_level"` // 置信水平（默认0.95）
+	Gamble          *GambleStrategy `json:"gamble,omitempty"` // 博倍策略（为空时中奖后直接收分）
+}
+
+// GambleStrategy 仿真使用的博倍策略：可博倍的中奖按固定选项竞猜，直到猜错、达到上限或竞猜次数后收分
+type GambleStrategy struct {
+	Mode   GambleMode  `json:"mode"`   // 玩法
+	Guess  GambleGuess `json:"guess"`  // 竞猜选项（为空时使用玩法的第一个选项）
+	Rounds int         `json:"rounds"` // 每次中奖最多竞猜次数
+}
+
+// guess 实际使用的竞猜选项
+func (s *GambleStrategy) guess() GambleGuess {
+	if s.Guess != "" {
+		return s.Guess
+	}
+	if s.Mode == GambleModeHighLow {
+		return GambleGuessHigh
+	}
+	return GambleGuessRed
+}
+
+// HistogramBucket 中奖倍数分布区间
+type HistogramBucket struct {
+	Label           string  `json:"label"`            // 区间标签
+	Min             float64 `json:"min"`              // 下限（含）
+	Max             float64 `json:"max"`              // 上限（不含，0表示无上限）
+	Count           int64   `json:"count"`            // 次数
+	Frequency       float64 `json:"frequency"`        // 频率
+	RTPContribution float64 `json:"rtp_contribution"` // 对RTP的贡献
+}
+
+// FeatureTriggerStat 特殊功能触发统计
+type FeatureTriggerStat struct {
+	Feature         string  `json:"feature"`          // 功能名称
+	Count           int64   `json:"count"`            // 触发次数
+	Rate            float64 `json:"rate"`             // 每次旋转的触发率
+	OneIn           float64 `json:"one_in"`           // 平均多少次旋转触发一次
+	Win             int64   `json:"win"`              // 归属该功能的赢取
+	RTPContribution float64 `json:"rtp_contribution"` // 对RTP的贡献
+}
+
+// SimulationReport 仿真统计报告
+type SimulationReport struct {
+	Engine              string               `json:"engine"`
+	ConfigName          string               `json:"config_name"`
+	Seed                int64                `json:"seed"`
+	Workers             int                  `json:"workers"`
+	BetAmount           int64                `json:"bet_amount"`
+	Spins               int64                `json:"spins"`
+	PaidSpins           int64                `json:"paid_spins"`
+	TotalBet            int64                `json:"total_bet"`
+	TotalWin            int64                `json:"total_win"`
+	RTP                 float64              `json:"rtp"`
+	TargetRTP           float64              `json:"target_rtp"`
+	ConfidenceLevel     float64              `json:"confidence_level"`
+	RTPLower            float64              `json:"rtp_lower"`
+	RTPUpper            float64              `json:"rtp_upper"`
+	StandardError       float64              `json:"standard_error"`
+	StandardDeviation   float64              `json:"standard_deviation"`
+	VolatilityIndex     float64              `json:"volatility_index"`
+	HitCount            int64                `json:"hit_count"`
+	HitFrequency        float64              `json:"hit_frequency"`
+	MaxWinMultiplier    float64              `json:"max_win_multiplier"`
+	MaxWinHits          int64                `json:"max_win_hits"`
+	MaxWinRate          float64              `json:"max_win_rate"`
+	GambleRounds        int64                `json:"gamble_rounds"`
+	GambleStake         int64                `json:"gamble_stake"`
+	GambleWin           int64                `json:"gamble_win"`
+	GambleRTP           float64              `json:"gamble_rtp"`
+	GambleTheoryRTP     float64              `json:"gamble_theory_rtp"`
+	LongestLosingStreak int64                `json:"longest_losing_streak"`
+	Histogram           []HistogramBucket    `json:"histogram"`
+	FeatureTriggers     []FeatureTriggerStat `json:"feature_triggers"`
+	DurationMs          int64                `json:"duration_ms"`
+	GeneratedAt         time.Time            `json:"generated_at"`
+}
+
+// histogramBounds 中奖倍数区间边界
+var histogramBounds = []float64{0, 1, 2, 5, 10, 20, 50, 100, 500, 1000}
+
+// simulationAccumulator 单个工作协程的统计累加器
+type simulationAccumulator struct {
+	nominalBet    int64
+	spins         int64
+	paidSpins     int64
+	totalBet      int64
+	totalWin      int64
+	sumReturn     float64 // Σ(win/nominalBet)
+	sumReturnSq   float64 // Σ(win/nominalBet)^2
+	hits          int64
+	maxWinHits    int64
+	gambleRounds  int64
+	gambleStake   int64
+	gambleWin     int64
+	maxMultiplier float64
+	currentStreak int64
+	longestStreak int64
+	zeroWins      int64
+	buckets       []int64
+	bucketReturns []float64
+	features      map[string]int64
+	featureWins   map[string]int64
+}
+
+func newSimulationAccumulator(nominalBet int64) *simulationAccumulator {
+	return &simulationAccumulator{
+		nominalBet:    nominalBet,
+		buckets:       make([]int64, len(histogramBounds)),
+		bucketReturns: make([]float64, len(histogramBounds)),
+		features:      make(map[string]int64),
+		featureWins:   make(map[string]int64),
+	}
+}
+
+// add 累加一个样本
+func (a *simulationAccumulator) add(sample *SimulationSample) {
+	a.spins++
+	if sample.BetAmount > 0 {
+		a.paidSpins++
+		a.totalBet += sample.BetAmount
+	}
+	a.totalWin += sample.WinAmount
+	if sample.MaxWinReached {
+		a.maxWinHits++
+	}
+	a.gambleRounds += int64(sample.GambleRounds)
+	a.gambleStake += sample.GambleStake
+	a.gambleWin += sample.GambleWin
+
+	x := float64(sample.WinAmount) / float64(a.nominalBet)
+	a.sumReturn += x
+	a.sumReturnSq += x * x
+	if x > a.maxMultiplier {
+		a.maxMultiplier = x
+	}
+
+	if sample.WinAmount > 0 {
+		a.hits++
+		a.currentStreak = 0
+		a.buckets[bucketIndex(x)]++
+		a.bucketReturns[bucketIndex(x)] += x
+	} else {
+		a.zeroWins++
+		a.currentStreak++
+		if a.currentStreak > a.longestStreak {
+			a.longestStreak = a.currentStreak
+		}
+	}
+
+	for _, feature := range sample.Features {
+		a.features[feature]++
+	}
+	for feature, win := range sample.FeatureWins {
+		a.featureWins[feature] += win
+	}
+}
+
+// merge 合并另一个累加器
+func (a *simulationAccumulator) merge(other *simulationAccumulator) {
+	a.spins += other.spins
+	a.paidSpins += other.paidSpins
+	a.totalBet += other.totalBet
+	a.totalWin += other.totalWin
+	a.sumReturn += other.sumReturn
+	a.sumReturnSq += other.sumReturnSq
+	a.hits += other.hits
+	a.maxWinHits += other.maxWinHits
+	a.gambleRounds += other.gambleRounds
+	a.gambleStake += other.gambleStake
+	a.gambleWin += other.gambleWin
+	a.zeroWins += other.zeroWins
+	if other.maxMultiplier > a.maxMultiplier {
+		a.maxMultiplier = other.maxMultiplier
+	}
+	if other.longestStreak > a.longestStreak {
+		a.longestStreak = other.longestStreak
+	}
+	for i := range a.buckets {
+		a.buckets[i] += other.buckets[i]
+		a.bucketReturns[i] += other.bucketReturns[i]
+	}
+	for feature, count := range other.features {
+		a.features[feature] += count
+	}
+	for feature, win := range other.featureWins {
+		a.featureWins[feature] += win
+	}
+}
+
+// bucketIndex 返回中奖倍数所属区间（仅用于中奖样本）
+func bucketIndex(multiplier float64) int {
+	for i := len(histogramBounds) - 1; i >= 0; i-- {
+		if multiplier >= histogramBounds[i] {
+			return i
+		}
+	}
+	return 0
+}
+
+// RunSimulation 在多个工作协程上并行执行仿真并生成报告
+func RunSimulation(ctx context.Context, opts *SimulationOptions, factory SimulationSpinnerFactory) (*SimulationReport, error) {
+	if opts.Spins <= 0 {
+		return nil, fmt.Errorf("spins must be positive")
+	}
+	if opts.BetAmount <= 0 {
+		return nil, fmt.Errorf("bet amount must be positive")
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if int64(opts.Workers) > opts.Spins {
+		opts.Workers = int(opts.Spins)
+	}
+	if opts.ConfidenceLevel <= 0 || opts.ConfidenceLevel >= 1 {
+		opts.ConfidenceLevel = 0.95
+	}
+
+	// 每个工作协程的随机源：指定主种子时可完整复现
+	var seedSource RandomGenerator = NewCryptoRandomGenerator()
+	if opts.Seed != 0 {
+		seedSource = NewDRBGRandomGenerator(opts.Seed)
+	}
+
+	spinners := make([]SimulationSpinner, opts.Workers)
+	for i := range spinners {
+		spinner, err := factory(NewDRBGRandomGenerator(nextSpinSeed(seedSource)))
+		if err != nil {
+			return nil, fmt.Errorf("create spinner %d: %w", i, err)
+		}
+		spinners[i] = spinner
+	}
+
+	startTime := time.Now()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	accumulators := make([]*simulationAccumulator, opts.Workers)
+	errs := make([]error, opts.Workers)
+	var wg sync.WaitGroup
+
+	perWorker := opts.Spins / int64(opts.Workers)
+	remainder := opts.Spins % int64(opts.Workers)
+
+	for i := 0; i < opts.Workers; i++ {
+		count := perWorker
+		if int64(i) < remainder {
+			count++
+		}
+		accumulators[i] = newSimulationAccumulator(opts.BetAmount)
+
+		wg.Add(1)
+		go func(worker int, count int64) {
+			defer wg.Done()
+			acc := accumulators[worker]
+			for n := int64(0); n < count; n++ {
+				if n&0x3ff == 0 && ctx.Err() != nil {
+					errs[worker] = ctx.Err()
+					return
+				}
+				sample, err := spinners[worker](ctx)
+				if err != nil {
+					errs[worker] = fmt.Errorf("worker %d spin %d: %w", worker, n, err)
+					cancel()
+					return
+				}
+				acc.add(sample)
+			}
+		}(i, count)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total := newSimulationAccumulator(opts.BetAmount)
+	for _, acc := range accumulators {
+		total.merge(acc)
+	}
+
+	report := buildSimulationReport(total, opts)
+	report.DurationMs = time.Since(startTime).Milliseconds()
+	return report, nil
+}
+
+// buildSimulationReport 根据累加器生成报告
+func buildSimulationReport(acc *simulationAccumulator, opts *SimulationOptions) *SimulationReport {
+	report := &SimulationReport{
+		Engine:              opts.Engine,
+		ConfigName:          opts.ConfigName,
+		Seed:                opts.Seed,
+		Workers:             opts.Workers,
+		BetAmount:           opts.BetAmount,
+		Spins:               acc.spins,
+		PaidSpins:           acc.paidSpins,
+		TotalBet:            acc.totalBet,
+		TotalWin:            acc.totalWin,
+		TargetRTP:           opts.TargetRTP,
+		ConfidenceLevel:     opts.ConfidenceLevel,
+		HitCount:            acc.hits,
+		MaxWinMultiplier:    acc.maxMultiplier,
+		MaxWinHits:          acc.maxWinHits,
+		GambleRounds:        acc.gambleRounds,
+		GambleStake:         acc.gambleStake,
+		GambleWin:           acc.gambleWin,
+		LongestLosingStreak: acc.longestStreak,
+		GeneratedAt:         time.Now(),
+	}
+
+	if acc.totalBet > 0 {
+		report.RTP = float64(acc.totalWin) / float64(acc.totalBet)
+	}
+	if acc.spins > 0 {
+		report.HitFrequency = float64(acc.hits) / float64(acc.spins)
+		report.MaxWinRate = float64(acc.maxWinHits) / float64(acc.spins)
+	}
+
+	// 博倍返还率：竞猜返还 / 竞猜投入
+	if acc.gambleStake > 0 {
+		report.GambleRTP = float64(acc.gambleWin) / float64(acc.gambleStake)
+	}
+	if opts.Gamble != nil {
+		report.GambleTheoryRTP = opts.Gamble.Mode.RTP()
+	}
+
+	// 单次旋转回报率的标准差（以名义下注为单位）
+	n := float64(acc.spins)
+	if acc.spins > 1 {
+		mean := acc.sumReturn / n
+		variance := (acc.sumReturnSq - n*mean*mean) / (n - 1)
+		if variance < 0 {
+			variance = 0
+		}
+		report.StandardDeviation = math.Sqrt(variance)
+	}
+
+	// 免费旋转不计下注，RTP = Σx / 付费旋转数
+	z := zScore(opts.ConfidenceLevel)
+	if acc.paidSpins > 0 && acc.spins > 0 {
+		scale := n / float64(acc.paidSpins)
+		report.StandardError = scale * report.StandardDeviation / math.Sqrt(n)
+		report.RTPLower = report.RTP - z*report.StandardError
+		report.RTPUpper = report.RTP + z*report.StandardError
+	}
+
+	// 波动指数：90%置信水平下的单次旋转标准差倍数
+	report.VolatilityIndex = zScore(0.90) * report.StandardDeviation
+
+	report.Histogram = buildHistogram(acc)
+	report.FeatureTriggers = buildFeatureStats(acc)
+	return report
+}
+
+// buildHistogram 生成中奖倍数分布
+func buildHistogram(acc *simulationAccumulator) []HistogramBucket {
+	buckets := make([]HistogramBucket, 0, len(histogramBounds)+1)
+
+	// 未中奖
+	noWin := HistogramBucket{Label: "0x", Count: acc.zeroWins}
+	if acc.spins > 0 {
+		noWin.Frequency = float64(acc.zeroWins) / float64(acc.spins)
+	}
+	buckets = append(buckets, noWin)
+
+	for i, lower := range histogramBounds {
+		bucket := HistogramBucket{Min: lower, Count: acc.buckets[i]}
+		if i+1 < len(histogramBounds) {
+			bucket.Max = histogramBounds[i+1]
+			bucket.Label = fmt.Sprintf("%gx-%gx", lower, bucket.Max)
+		} else {
+			bucket.Label = fmt.Sprintf("%gx+", lower)
+		}
+		if i == 0 {
+			bucket.Label = fmt.Sprintf(">0x-%gx", bucket.Max)
+		}
+		if acc.spins > 0 {
+			bucket.Frequency = float64(bucket.Count) / float64(acc.spins)
+		}
+		if acc.paidSpins > 0 {
+			bucket.RTPContribution = acc.bucketReturns[i] / float64(acc.paidSpins)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// buildFeatureStats 生成特殊功能触发统计
+func buildFeatureStats(acc *simulationAccumulator) []FeatureTriggerStat {
+	stats := make([]FeatureTriggerStat, 0, len(acc.features))
+	for feature, count := range acc.features {
+		stat := FeatureTriggerStat{Feature: feature, Count: count}
+		if acc.spins > 0 {
+			stat.Rate = float64(count) / float64(acc.spins)
+		}
+		if count > 0 {
+			stat.OneIn = float64(acc.spins) / float64(count)
+		}
+		stat.Win = acc.featureWins[feature]
+		if acc.totalBet > 0 {
+			stat.RTPContribution = float64(stat.Win) / float64(acc.totalBet)
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Feature < stats[j].Feature })
+	return stats
+}
+
+// zScore 双侧置信水平对应的标准正态分位数
+func zScore(confidenceLevel float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidenceLevel)
+}
+
+// WriteJSON 以JSON格式输出报告
+func (r *SimulationReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV 以CSV格式输出报告（汇总、分布、特殊功能三个区块）
+func (r *SimulationReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = false
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 6, 64) }
+	i := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	rows := [][]string{
+		{"section", "metric", "value"},
+		{"summary", "engine", r.Engine},
+		{"summary", "config_name", r.ConfigName},
+		{"summary", "seed", i(r.Seed)},
+		{"summary", "workers", strconv.Itoa(r.Workers)},
+		{"summary", "bet_amount", i(r.BetAmount)},
+		{"summary", "spins", i(r.Spins)},
+		{"summary", "paid_spins", i(r.PaidSpins)},
+		{"summary", "total_bet", i(r.TotalBet)},
+		{"summary", "total_win", i(r.TotalWin)},
+		{"summary", "rtp", f(r.RTP)},
+		{"summary", "target_rtp", f(r.TargetRTP)},
+		{"summary", "confidence_level", f(r.ConfidenceLevel)},
+		{"summary", "rtp_lower", f(r.RTPLower)},
+		{"summary", "rtp_upper", f(r.RTPUpper)},
+		{"summary", "standard_error", f(r.StandardError)},
+		{"summary", "standard_deviation", f(r.StandardDeviation)},
+		{"summary", "volatility_index", f(r.VolatilityIndex)},
+		{"summary", "hit_count", i(r.HitCount)},
+		{"summary", "hit_frequency", f(r.HitFrequency)},
+		{"summary", "max_win_multiplier", f(r.MaxWinMultiplier)},
+		{"summary", "max_win_hits", i(r.MaxWinHits)},
+		{"summary", "max_win_rate", f(r.MaxWinRate)},
+		{"summary", "gamble_rounds", i(r.GambleRounds)},
+		{"summary", "gamble_stake", i(r.GambleStake)},
+		{"summary", "gamble_win", i(r.GambleWin)},
+		{"summary", "gamble_rtp", f(r.GambleRTP)},
+		{"summary", "gamble_theory_rtp", f(r.GambleTheoryRTP)},
+		{"summary", "longest_losing_streak", i(r.LongestLosingStreak)},
+		{"summary", "duration_ms", i(r.DurationMs)},
+		{},
+		{"histogram", "bucket", "count", "frequency", "rtp_contribution"},
+	}
+	for _, b := range r.Histogram {
+		rows = append(rows, []string{"histogram", b.Label, i(b.Count), f(b.Frequency), f(b.RTPContribution)})
+	}
+	rows = append(rows, []string{}, []string{"feature", "name", "count", "rate", "one_in", "win", "rtp_contribution"})
+	for _, s := range r.FeatureTriggers {
+		rows = append(rows, []string{"feature", s.Feature, i(s.Count), f(s.Rate), f(s.OneIn), i(s.Win), f(s.RTPContribution)})
+	}
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// NewSlotSimulationFactory 创建SlotEngine的仿真旋转函数工厂
+func NewSlotSimulationFactory(config *SlotConfig, betAmount int64) SimulationSpinnerFactory {
+	return NewSlotGambleSimulationFactory(config, betAmount, nil)
+}
+
+// NewSlotGambleSimulationFactory 创建按博倍策略竞猜的SlotEngine仿真旋转函数工厂（策略为空时中奖后直接收分）
+func NewSlotGambleSimulationFactory(config *SlotConfig, betAmount int64, strategy *GambleStrategy) SimulationSpinnerFactory {
+	return func(rng RandomGenerator) (SimulationSpinner, error) {
+		engine, err := NewSlotEngine(config)
+		if err != nil {
+			return nil, err
+		}
+		engine.SetRandomGenerator(rng)
+
+		return func(ctx context.Context) (*SimulationSample, error) {
+			result, err := engine.Spin(0, "simulation", betAmount)
+			if err != nil {
+				return nil, err
+			}
+			sample := &SimulationSample{
+				BetAmount:     result.BetAmount,
+				WinAmount:     result.WinAmount,
+				MaxWinReached: result.MaxWinReached,
+			}
+			for _, feature := range result.Features {
+				sample.Features = append(sample.Features, string(feature.Type))
+			}
+			sample.FeatureWins = slotFeatureWins(result)
+			if result.Gamble != nil {
+				if err := simulateGamble(engine, strategy, sample); err != nil {
+					return nil, err
+				}
+			}
+			return sample, nil
+		}, nil
+	}
+}
+
+// simulateGamble 按策略完成一次博倍并收分，赢取替换为博倍后的金额
+func simulateGamble(engine *SlotEngine, strategy *GambleStrategy, sample *SimulationSample) error {
+	if strategy != nil {
+		for i := 0; i < strategy.Rounds; i++ {
+			if gamble := engine.GetGamble("simulation"); gamble == nil || !gamble.CanDouble() {
+				break
+			}
+			gamble, round, err := engine.PlayGamble("simulation", strategy.Mode, strategy.guess())
+			if err != nil {
+				return err
+			}
+			sample.GambleRounds++
+			sample.GambleStake += round.Stake
+			sample.GambleWin += round.Win
+			if !round.Won {
+				sample.WinAmount -= gamble.OriginalWin
+				return nil
+			}
+		}
+	}
+
+	gamble, err := engine.CollectGamble("simulation")
+	if err != nil {
+		return err
+	}
+	sample.WinAmount += gamble.Stake - gamble.OriginalWin
+	return nil
+}
+
+// slotFeatureWins 拆分SlotEngine旋转结果中归属奖励游戏和免费旋转的赢取
+// 免费旋转中触发的奖励游戏按免费游戏倍率计入奖励游戏，其余赢取计入免费旋转
+func slotFeatureWins(result *SpinResult) map[string]int64 {
+	var bonusWin int64
+	if result.BonusGame != nil && result.BonusGame.AutoPlayed {
+		bonusWin = result.BonusGame.TotalWin
+		if result.IsFreeSpin && result.FreeGame != nil {
+			bonusWin = int64(float64(bonusWin) * result.FreeGame.Multiplier)
+		}
+	}
+	if bonusWin == 0 && !result.IsFreeSpin {
+		return nil
+	}
+
+	wins := make(map[string]int64)
+	if bonusWin > 0 {
+		wins[string(FeatureTypeBonus)] = bonusWin
+	}
+	if result.IsFreeSpin {
+		wins[string(FeatureTypeFreeSpins)] = result.WinAmount - bonusWin
+	}
+	return wins
+}
+
+// NewCascadeSimulationFactory 创建CascadeEngine的仿真旋转函数工厂
+func NewCascadeSimulationFactory(algorithmConfig *AlgorithmConfig, cascadeConfig *CascadeConfig, betAmount int64) SimulationSpinnerFactory {
+	return func(rng RandomGenerator) (SimulationSpinner, error) {
+		if err := ValidateCascadeConfig(cascadeConfig); err != nil {
+			return nil, err
+		}
+		algo := *algorithmConfig
+		cascade := *cascadeConfig
+		engine := NewCascadeEngine(&algo, &cascade)
+		engine.SetRandomGenerator(rng)
+
+		request := &SpinRequest{GameRequest: &GameRequest{SessionID: "simulation", BetAmount: betAmount}}
+		return func(ctx context.Context) (*SimulationSample, error) {
+			result, err := engine.SpinCascade(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			return &SimulationSample{
+				BetAmount:     betAmount,
+				WinAmount:     result.TotalWin,
+				Features:      cascadeFeatures(result),
+				MaxWinReached: result.MaxWinReached,
+			}, nil
+		}, nil
+	}
+}
+
+// NewGoldenWildSimulationFactory 创建GoldenWildCascadeEngine的仿真旋转函数工厂
+func NewGoldenWildSimulationFactory(algorithmConfig *AlgorithmConfig, cascadeConfig *CascadeConfig, betAmount int64) SimulationSpinnerFactory {
+	return func(rng RandomGenerator) (SimulationSpinner, error) {
+		if err := ValidateCascadeConfig(cascadeConfig); err != nil {
+			return nil, err
+		}
+		algo := *algorithmConfig
+		cascade := *cascadeConfig
+		engine := NewGoldenWildCascadeEngine(&algo, &cascade)
+		engine.SetRandomGenerator(rng)
+
+		request := &SpinRequest{GameRequest: &GameRequest{SessionID: "simulation", BetAmount: betAmount}}
+		return func(ctx context.Context) (*SimulationSample, error) {
+			result, err := engine.SpinWithGoldenWild(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			sample := &SimulationSample{
+				BetAmount:     betAmount,
+				WinAmount:     result.TotalWin,
+				Features:      cascadeFeatures(result.CascadeResult),
+				MaxWinReached: result.MaxWinReached,
+			}
+			if len(result.GoldenSymbols) > 0 {
+				sample.Features = append(sample.Features, "GOLDEN_SYMBOL")
+			}
+			if result.AnimalTrigger != nil {
+				sample.Features = append(sample.Features, string(result.AnimalTrigger.Type))
+			}
+			return sample, nil
+		}, nil
+	}
+}
+
+// cascadeFeatures 提取消除结果中的特殊功能
+func cascadeFeatures(result *CascadeResult) []string {
+	var features []string
+	if result.CascadeCount > 1 {
+		features = append(features, string(FeatureTypeCascade))
+	}
+	return features
+}
@@ -0,0 +1,148 @@
+package slot
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"math"
+	"testing"
+)
+
+func testMahjongAlgorithmConfig() *AlgorithmConfig {
+	return &AlgorithmConfig{
+		ReelCount:   5,
+		RowCount:    4,
+		SymbolCount: 8,
+		TargetRTP:   0.96,
+		SymbolWeights: [][]int{
+			{18, 16, 14, 12, 12, 10, 8, 6},
+			{16, 18, 14, 12, 12, 10, 8, 6},
+			{14, 16, 18, 12, 12, 10, 8, 6},
+			{12, 14, 16, 18, 12, 10, 8, 6},
+			{12, 12, 14, 16, 18, 12, 8, 6},
+		},
+		PayTable: map[int][]int64{
+			0: {0, 0, 20, 60, 200},
+			1: {0, 0, 25, 75, 250},
+			2: {0, 0, 30, 90, 300},
+			3: {0, 0, 15, 45, 150},
+			4: {0, 0, 12, 36, 120},
+			5: {0, 0, 10, 30, 100},
+			6: {0, 0, 8, 24, 80},
+			7: {0, 0, 6, 18, 60},
+		},
+	}
+}
+
+func TestRunSimulation_SlotEngine(t *testing.T) {
+	config := GetDefaultConfig()
+	opts := &SimulationOptions{
+		Engine:    "slot",
+		Spins:     5000,
+		Workers:   3,
+		Seed:      11,
+		BetAmount: 100,
+		TargetRTP: config.TargetRTP,
+	}
+
+	report, err := RunSimulation(context.Background(), opts, NewSlotSimulationFactory(config, opts.BetAmount))
+	if err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+
+	if report.Spins != opts.Spins {
+		t.Errorf("Spins = %d, want %d", report.Spins, opts.Spins)
+	}
+	if report.ConfidenceLevel != 0.95 {
+		t.Errorf("ConfidenceLevel = %v, want default 0.95", report.ConfidenceLevel)
+	}
+	if report.RTPLower > report.RTP || report.RTPUpper < report.RTP {
+		t.Errorf("RTP %v outside its interval [%v, %v]", report.RTP, report.RTPLower, report.RTPUpper)
+	}
+
+	var histogramTotal int64
+	for _, bucket := range report.Histogram {
+		histogramTotal += bucket.Count
+	}
+	if histogramTotal != report.Spins {
+		t.Errorf("histogram counts sum to %d, want %d", histogramTotal, report.Spins)
+	}
+	if report.Histogram[0].Count+report.HitCount != report.Spins {
+		t.Errorf("no-win bucket %d + hits %d != spins %d", report.Histogram[0].Count, report.HitCount, report.Spins)
+	}
+}
+
+func TestRunSimulation_Reproducible(t *testing.T) {
+	run := func() *SimulationReport {
+		opts := &SimulationOptions{Spins: 2000, Workers: 2, Seed: 99, BetAmount: 100}
+		factory := NewGoldenWildSimulationFactory(testMahjongAlgorithmConfig(), GetDefaultCascadeConfig(), opts.BetAmount)
+		report, err := RunSimulation(context.Background(), opts, factory)
+		if err != nil {
+			t.Fatalf("RunSimulation failed: %v", err)
+		}
+		return report
+	}
+
+	a, b := run(), run()
+	if a.TotalWin != b.TotalWin || a.HitCount != b.HitCount || a.LongestLosingStreak != b.LongestLosingStreak {
+		t.Errorf("same seed produced different reports: win %d/%d hits %d/%d",
+			a.TotalWin, b.TotalWin, a.HitCount, b.HitCount)
+	}
+}
+
+func TestRunSimulation_InvalidOptions(t *testing.T) {
+	factory := NewSlotSimulationFactory(GetDefaultConfig(), 100)
+	if _, err := RunSimulation(context.Background(), &SimulationOptions{Spins: 0, BetAmount: 100}, factory); err == nil {
+		t.Error("expected error for zero spins")
+	}
+	if _, err := RunSimulation(context.Background(), &SimulationOptions{Spins: 10}, factory); err == nil {
+		t.Error("expected error for zero bet")
+	}
+}
+
+func TestSimulationReport_WriteCSV(t *testing.T) {
+	opts := &SimulationOptions{Engine: "slot", Spins: 500, Workers: 1, Seed: 5, BetAmount: 100}
+	report, err := RunSimulation(context.Background(), opts, NewSlotSimulationFactory(GetDefaultConfig(), 100))
+	if err != nil {
+		t.Fatalf("RunSimulation failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("CSV output is not parseable: %v", err)
+	}
+	if len(records) < 20 {
+		t.Errorf("CSV has %d records, expected summary, histogram and feature sections", len(records))
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+}
+
+func TestZScoreAndConfidence(t *testing.T) {
+	if z := zScore(0.95); math.Abs(z-1.959964) > 1e-4 {
+		t.Errorf("zScore(0.95) = %v, want 1.96", z)
+	}
+	if z := zScore(0.90); math.Abs(z-1.644854) > 1e-4 {
+		t.Errorf("zScore(0.90) = %v, want 1.645", z)
+	}
+
+	// 偏差为0时置信度为1，偏差越大置信度越低
+	if c := calculateConfidenceLevel(0.01, 0.96, 0.96); c != 1 {
+		t.Errorf("confidence for zero deviation = %v, want 1", c)
+	}
+	near := calculateConfidenceLevel(0.01, 0.965, 0.96)
+	far := calculateConfidenceLevel(0.01, 0.99, 0.96)
+	if !(near > far) {
+		t.Errorf("confidence should decrease with deviation: near=%v far=%v", near, far)
+	}
+}
@@ -17,6 +17,8 @@ const (
 	SYMBOL_SCATTER      = 8  // Scatter符号（免费游戏）
 	SYMBOL_ANIMAL_WILD  = 9  // 动物Wild符号（触发Animal游戏）
 	SYMBOL_ANIMAL_BONUS = 10 // 动物Bonus符号（触发超级Animal游戏）
+	SYMBOL_BONUS        = 11 // Bonus符号（触发选择奖励游戏）
+	SYMBOL_MYSTERY      = 12 // 神秘符号（消除匹配前揭示为随机符号）
 
 	// 金色符号范围 (16-23)
 	SYMBOL_GOLDEN_BASE = 16
@@ -152,6 +154,15 @@ func GetSymbolInfo(symbolID int) *SymbolInfo {
 			CanBeGolden: false,
 			IsSpecial:   true,
 		},
+		SYMBOL_BONUS: {
+			ID:          SYMBOL_BONUS,
+			Name:        "Bonus",
+			Type:        "bonus",
+			Display:     "🎁",
+			Description: "Bonus符号 - 3个触发选择奖励游戏",
+			CanBeGolden: false,
+			IsSpecial:   true,
+		},
 	}
 
 	// 添加金色符号
@@ -191,6 +202,7 @@ func IsSpecialSymbol(symbolID int) bool {
 		symbolID == SYMBOL_SCATTER ||
 		symbolID == SYMBOL_ANIMAL_WILD ||
 		symbolID == SYMBOL_ANIMAL_BONUS ||
+		symbolID == SYMBOL_BONUS ||
 		(symbolID >= SYMBOL_GOLDEN_BASE && symbolID <= SYMBOL_GOLDEN_8)
 }
 
@@ -224,3 +236,24 @@ func GetBaseSymbol(goldenSymbolID int) int {
 	}
 	return goldenSymbolID
 }
+
+// classicSymbolIDs 经典符号对应的符号ID（与主题包、客户端协议使用同一套ID）
+var classicSymbolIDs = map[Symbol]int{
+	SymbolCherry:     0,
+	SymbolLemon:      1,
+	SymbolOrange:     2,
+	SymbolPlum:       3,
+	SymbolGrape:      4,
+	SymbolWatermelon: 5,
+	SymbolBar:        6,
+	SymbolSeven:      7,
+	SymbolWild:       SYMBOL_WILD,
+	SymbolScatter:    SYMBOL_SCATTER,
+	SymbolBonus:      SYMBOL_BONUS,
+}
+
+// SymbolID 获取经典符号对应的符号ID
+func SymbolID(symbol Symbol) (int, bool) {
+	id, ok := classicSymbolIDs[symbol]
+	return id, ok
+}
@@ -251,4 +251,22 @@ func TestGetBaseSymbol(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestSymbolID(t *testing.T) {
+	tests := map[Symbol]int{
+		SymbolCherry:  0,
+		SymbolSeven:   7,
+		SymbolWild:    SYMBOL_WILD,
+		SymbolScatter: SYMBOL_SCATTER,
+		SymbolBonus:   SYMBOL_BONUS,
+	}
+	for symbol, want := range tests {
+		if id, ok := SymbolID(symbol); !ok || id != want {
+			t.Errorf("SymbolID(%v) = %v, %v, want %v", symbol, id, ok, want)
+		}
+	}
+	if _, ok := SymbolID("UNKNOWN"); ok {
+		t.Error("SymbolID should reject unknown symbols")
+	}
+}
@@ -0,0 +1,588 @@
+package slot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// 主题包目录中的文件
+const (
+	themePackManifestFile   = "theme.json"      // 主题信息与资源清单
+	themePackSymbolsFile    = "symbols.json"    // 符号到资源的映射
+	themePackAnimationsFile = "animations.json" // 动画（可选）
+	themePackSoundsFile     = "sounds.json"     // 音效（可选）
+)
+
+// themePackReloadDelay 文件变化后等待的时间（合并同一次拷贝产生的多个事件）
+const themePackReloadDelay = 300 * time.Millisecond
+
+// 主题包中按名称引用的动画、音效、特效类型
+var (
+	themeAnimationTypes = map[string]AnimationType{
+		"win_line":        AnimationTypeWinLine,
+		"symbol_win":      AnimationTypeSymbolWin,
+		"feature_trigger": AnimationTypeFeatureTrigger,
+		"big_win":         AnimationTypeBigWin,
+		"jackpot":         AnimationTypeJackpot,
+		"anticipation":    AnimationTypeAnticipation,
+	}
+	themeSoundTypes = map[string]SoundType{
+		"spin":    SoundTypeSpin,
+		"win":     SoundTypeWin,
+		"big_win": SoundTypeBigWin,
+		"jackpot": SoundTypeJackpot,
+		"feature": SoundTypeFeature,
+		"ambient": SoundTypeAmbient,
+	}
+	themeEffectTypes = map[string]EffectType{
+		"particle":  EffectTypeParticle,
+		"flash":     EffectTypeFlash,
+		"shake":     EffectTypeShake,
+		"glow":      EffectTypeGlow,
+		"explosion": EffectTypeExplosion,
+	}
+)
+
+// ThemePackManifest 主题包 theme.json
+// Assets 为资源清单（资源键 -> URL），符号、动画、音效、背景都通过资源键引用资源
+type ThemePackManifest struct {
+	ID          string                  `json:"id"`          // 主题ID（为空时使用目录名）
+	Name        string                  `json:"name"`        // 名称
+	Description string                  `json:"description"` // 描述
+	Version     string                  `json:"version"`     // 版本
+	SlotType    int32                   `json:"slot_type"`   // 对应的拉霸机类型（pb.ESlotType）
+	Assets      map[string]string       `json:"assets"`      // 资源清单
+	Background  ThemePackBackground     `json:"background"`  // 背景
+	Effects     map[string]VisualEffect `json:"effects"`     // 特效（按名称）
+	UI          UIConfig                `json:"ui"`          // UI配置
+	Properties  map[string]interface{}  `json:"properties"`  // 扩展属性
+}
+
+// ThemePackBackground 主题包背景配置
+type ThemePackBackground struct {
+	Image       string  `json:"image"`        // 背景图资源键
+	Color       string  `json:"color"`        // 背景色
+	Animation   string  `json:"animation"`    // 背景动画
+	Music       string  `json:"music"`        // 背景音乐资源键
+	MusicVolume float64 `json:"music_volume"` // 背景音乐音量
+}
+
+// ThemePackSymbol 主题包 symbols.json 中的符号
+type ThemePackSymbol struct {
+	ID         int                    `json:"id"`         // 抽象符号ID
+	Name       string                 `json:"name"`       // 符号名称
+	Asset      string                 `json:"asset"`      // 图片资源键
+	Animation  string                 `json:"animation"`  // 动画名称
+	Rarity     SymbolRarity           `json:"rarity"`     // 稀有度
+	Properties map[string]interface{} `json:"properties"` // 符号属性
+}
+
+// ThemePackAnimation 主题包 animations.json 中的动画（按动画类型名称分组）
+type ThemePackAnimation struct {
+	Duration   int                    `json:"duration"`   // 持续时间(ms)
+	Frames     []ThemePackFrame       `json:"frames"`     // 动画帧
+	Properties map[string]interface{} `json:"properties"` // 动画属性
+}
+
+// ThemePackFrame 主题包动画帧
+type ThemePackFrame struct {
+	Asset     string    `json:"asset"`     // 帧图片资源键
+	Duration  int       `json:"duration"`  // 持续时间(ms)
+	Transform Transform `json:"transform"` // 变换
+}
+
+// ThemePackSound 主题包 sounds.json 中的音效（按音效类型名称）
+type ThemePackSound struct {
+	Asset  string  `json:"asset"`  // 音频资源键
+	Volume float64 `json:"volume"` // 音量
+	Loop   bool    `json:"loop"`   // 是否循环
+	Delay  int     `json:"delay"`  // 延迟(ms)
+}
+
+// LoadThemePack 从目录加载主题包并校验资源清单
+// 符号、动画、音效和背景引用的资源键必须都在 theme.json 的资源清单中
+func LoadThemePack(dir string) (*Theme, int32, error) {
+	manifest := &ThemePackManifest{}
+	if err := readThemePackFile(dir, themePackManifestFile, manifest, true); err != nil {
+		return nil, 0, err
+	}
+	if manifest.ID == "" {
+		manifest.ID = filepath.Base(dir)
+	}
+
+	var symbols []ThemePackSymbol
+	if err := readThemePackFile(dir, themePackSymbolsFile, &symbols, true); err != nil {
+		return nil, 0, err
+	}
+	animations := map[string][]ThemePackAnimation{}
+	if err := readThemePackFile(dir, themePackAnimationsFile, &animations, false); err != nil {
+		return nil, 0, err
+	}
+	sounds := map[string]ThemePackSound{}
+	if err := readThemePackFile(dir, themePackSoundsFile, &sounds, false); err != nil {
+		return nil, 0, err
+	}
+
+	assets := themePackAssets{manifest: manifest.Assets}
+	theme := &Theme{
+		ID:          manifest.ID,
+		Name:        manifest.Name,
+		Description: manifest.Description,
+		Version:     manifest.Version,
+		SymbolMap:   make(map[int]ThemeSymbol, len(symbols)),
+		Background: BackgroundConfig{
+			ImageURL:    assets.url(manifest.Background.Image, "background.image"),
+			Color:       manifest.Background.Color,
+			Animation:   manifest.Background.Animation,
+			MusicURL:    assets.url(manifest.Background.Music, "background.music"),
+			MusicVolume: manifest.Background.MusicVolume,
+		},
+		Sounds:     make(map[SoundType]SoundEffect, len(sounds)),
+		Animations: make(map[AnimationType][]Animation, len(animations)),
+		Effects:    make(map[EffectType]VisualEffect, len(manifest.Effects)),
+		UI:         manifest.UI,
+		Properties: manifest.Properties,
+	}
+
+	for _, symbol := range symbols {
+		if _, exists := theme.SymbolMap[symbol.ID]; exists {
+			return nil, 0, fmt.Errorf("%w: %s 中符号 %d 重复", ErrInvalidThemePack, themePackSymbolsFile, symbol.ID)
+		}
+		theme.SymbolMap[symbol.ID] = ThemeSymbol{
+			ID:         symbol.ID,
+			Name:       symbol.Name,
+			ImageURL:   assets.require(symbol.Asset, fmt.Sprintf("symbol %d", symbol.ID)),
+			Animation:  symbol.Animation,
+			Rarity:     symbol.Rarity,
+			Properties: symbol.Properties,
+		}
+	}
+
+	for name, items := range animations {
+		animationType, ok := themeAnimationTypes[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: 未知的动画类型 %q", ErrInvalidThemePack, name)
+		}
+		for _, item := range items {
+			animation := Animation{
+				Type:       animationType,
+				Duration:   item.Duration,
+				Sequence:   make([]AnimationFrame, len(item.Frames)),
+				Properties: item.Properties,
+			}
+			for i, frame := range item.Frames {
+				animation.Sequence[i] = AnimationFrame{
+					ImageURL:  assets.require(frame.Asset, "animation "+name),
+					Duration:  frame.Duration,
+					Transform: frame.Transform,
+				}
+			}
+			theme.Animations[animationType] = append(theme.Animations[animationType], animation)
+		}
+	}
+
+	for name, sound := range sounds {
+		soundType, ok := themeSoundTypes[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: 未知的音效类型 %q", ErrInvalidThemePack, name)
+		}
+		theme.Sounds[soundType] = SoundEffect{
+			Type:    soundType,
+			FileURL: assets.require(sound.Asset, "sound "+name),
+			Volume:  sound.Volume,
+			Loop:    sound.Loop,
+			Delay:   sound.Delay,
+		}
+	}
+
+	for name, effect := range manifest.Effects {
+		effectType, ok := themeEffectTypes[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: 未知的特效类型 %q", ErrInvalidThemePack, name)
+		}
+		effect.Type = effectType
+		theme.Effects[effectType] = effect
+	}
+
+	if len(assets.missing) > 0 {
+		sort.Strings(assets.missing)
+		return nil, 0, fmt.Errorf("%w: 资源清单缺少 %v", ErrInvalidThemePack, assets.missing)
+	}
+	return theme, manifest.SlotType, nil
+}
+
+// readThemePackFile 读取并解析主题包中的JSON文件
+func readThemePackFile(dir, name string, v interface{}, required bool) error {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("%w: 读取 %s 失败: %v", ErrInvalidThemePack, name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: 解析 %s 失败: %v", ErrInvalidThemePack, name, err)
+	}
+	return nil
+}
+
+// themePackAssets 按资源清单解析资源键，记录缺失的引用
+type themePackAssets struct {
+	manifest map[string]string
+	missing  []string
+}
+
+// url 解析可选的资源键（为空时返回空）
+func (a *themePackAssets) url(key, usage string) string {
+	if key == "" {
+		return ""
+	}
+	return a.require(key, usage)
+}
+
+// require 解析必需的资源键
+func (a *themePackAssets) require(key, usage string) string {
+	url, ok := a.manifest[key]
+	if !ok || url == "" {
+		a.missing = append(a.missing, fmt.Sprintf("%s(%s)", key, usage))
+	}
+	return url
+}
+
+// SymbolIDs 配置中出现的全部抽象符号ID（普通符号、赔付表、特殊符号与特性触发符号），按升序排列
+func (c *AlgorithmConfig) SymbolIDs() []int {
+	seen := make(map[int]bool)
+	for id := 0; id < c.SymbolCount; id++ {
+		seen[id] = true
+	}
+	for id := range c.PayTable {
+		seen[id] = true
+	}
+	for _, group := range [][]int{c.WildSymbols, c.ScatterSymbols, c.BonusSymbols} {
+		for _, id := range group {
+			seen[id] = true
+		}
+	}
+	for _, feature := range c.FeatureConfigs {
+		if feature == nil {
+			continue
+		}
+		for _, id := range feature.TriggerSymbols {
+			seen[id] = true
+		}
+	}
+
+	return sortedSymbolIDs(seen)
+}
+
+// SymbolIDs 经典配置中出现的全部符号（卷轴条、赔付表、特殊符号与特性触发符号）对应的符号ID，按升序排列
+func (c *SlotConfig) SymbolIDs() []int {
+	seen := make(map[int]bool)
+	add := func(symbols ...Symbol) {
+		for _, symbol := range symbols {
+			if id, ok := SymbolID(symbol); ok {
+				seen[id] = true
+			}
+		}
+	}
+	strips := append([]ReelStrip(nil), c.ReelStrips...)
+	for _, set := range c.ReelSets {
+		strips = append(strips, set.ReelStrips...)
+	}
+	for _, strip := range strips {
+		add(strip.Symbols...)
+	}
+	for _, pay := range c.PayTables {
+		add(pay.Symbol)
+	}
+	add(c.WildSymbols...)
+	add(c.ScatterSymbols...)
+	add(c.BonusSymbols...)
+	for _, feature := range c.Features {
+		add(feature.TriggerSymbols...)
+	}
+	return sortedSymbolIDs(seen)
+}
+
+// sortedSymbolIDs 符号ID集合转为升序列表
+func sortedSymbolIDs(seen map[int]bool) []int {
+	ids := make([]int, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// ValidateThemeSymbols 校验主题为算法配置中的每个符号ID都提供了主题符号
+func ValidateThemeSymbols(theme *Theme, config *AlgorithmConfig) error {
+	return validateThemeSymbolIDs(theme, config.SymbolIDs())
+}
+
+// validateThemeSymbolIDs 校验主题为每个符号ID都提供了主题符号
+func validateThemeSymbolIDs(theme *Theme, symbolIDs []int) error {
+	var missing []int
+	for _, id := range symbolIDs {
+		if _, ok := theme.SymbolMap[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: 主题 %s 缺少符号 %v", ErrThemeSymbolMissing, theme.ID, missing)
+	}
+	return nil
+}
+
+// ThemePackInfo 主题包加载状态
+type ThemePackInfo struct {
+	ID          string    `json:"id"`              // 主题ID
+	Name        string    `json:"name"`            // 名称
+	Description string    `json:"description"`     // 描述
+	Version     string    `json:"version"`         // 版本
+	SlotType    int32     `json:"slot_type"`       // 拉霸机类型
+	Dir         string    `json:"dir"`             // 主题包目录
+	Symbols     int       `json:"symbols"`         // 符号数量
+	Active      bool      `json:"active"`          // 是否已注册（加载失败时保留上一个有效版本）
+	Error       string    `json:"error,omitempty"` // 最近一次加载错误
+	LoadedAt    time.Time `json:"loaded_at"`       // 最近一次成功加载时间
+}
+
+// ThemePackLoader 主题包加载器
+// 扫描根目录下的每个子目录作为一个主题包，校验后注册到主题管理器；
+// Watch 监听目录变化后重新加载，校验失败的主题包保留上一个有效版本
+type ThemePackLoader struct {
+	mu      sync.RWMutex
+	root    string
+	manager *ThemeManager
+	symbols map[int32][]int           // 各拉霸机类型的主题必须覆盖的符号ID（0为默认）
+	packs   map[string]*ThemePackInfo // 目录 -> 加载状态
+}
+
+// NewThemePackLoader 创建主题包加载器
+func NewThemePackLoader(root string, manager *ThemeManager) *ThemePackLoader {
+	return &ThemePackLoader{
+		root:    root,
+		manager: manager,
+		symbols: make(map[int32][]int),
+		packs:   make(map[string]*ThemePackInfo),
+	}
+}
+
+// RequireSymbols 设置拉霸机类型的算法配置，该类型的主题必须覆盖配置中的全部符号ID
+// slotType 为0时作为未单独设置的类型的默认配置
+func (l *ThemePackLoader) RequireSymbols(slotType int32, config *AlgorithmConfig) {
+	l.requireSymbolIDs(slotType, config.SymbolIDs())
+}
+
+// RequireSlotSymbols 设置使用经典配置（支付线/全路径）的拉霸机类型，该类型的主题必须覆盖配置中全部符号对应的符号ID
+func (l *ThemePackLoader) RequireSlotSymbols(slotType int32, config *SlotConfig) {
+	l.requireSymbolIDs(slotType, config.SymbolIDs())
+}
+
+// requireSymbolIDs 设置拉霸机类型的主题必须覆盖的符号ID
+func (l *ThemePackLoader) requireSymbolIDs(slotType int32, symbolIDs []int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.symbols[slotType] = symbolIDs
+}
+
+// LoadAll 加载根目录下的全部主题包，返回各主题包的错误
+func (l *ThemePackLoader) LoadAll() error {
+	entries, err := os.ReadDir(l.root)
+	if err != nil {
+		return fmt.Errorf("读取主题目录失败: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+	seen := make(map[string]bool)
+	ids := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(l.root, entry.Name())
+		seen[dir] = true
+		if err := l.loadPack(dir, ids); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 目录已删除的主题包注销
+	for dir, info := range l.packs {
+		if !seen[dir] {
+			if info.Active {
+				l.manager.RemoveTheme(info.ID)
+			}
+			delete(l.packs, dir)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadPack 加载单个主题包（调用方持有锁）
+func (l *ThemePackLoader) loadPack(dir string, ids map[string]string) error {
+	info, ok := l.packs[dir]
+	if !ok {
+		info = &ThemePackInfo{Dir: dir}
+		l.packs[dir] = info
+	}
+
+	theme, slotType, err := LoadThemePack(dir)
+	if err == nil {
+		if other, exists := ids[theme.ID]; exists {
+			err = fmt.Errorf("%w: 主题ID %s 与 %s 重复", ErrInvalidThemePack, theme.ID, other)
+		}
+	}
+	if err == nil {
+		if symbolIDs, ok := l.requiredSymbols(slotType); ok {
+			err = validateThemeSymbolIDs(theme, symbolIDs)
+		}
+	}
+	if err == nil {
+		err = l.manager.RegisterTheme(theme)
+	}
+	if err != nil {
+		info.Error = err.Error()
+		if info.ID == "" {
+			info.ID = filepath.Base(dir)
+		}
+		if info.Active {
+			ids[info.ID] = dir
+		}
+		return fmt.Errorf("主题包 %s: %w", dir, err)
+	}
+
+	// 主题ID变化时注销旧ID
+	if info.Active && info.ID != theme.ID {
+		l.manager.RemoveTheme(info.ID)
+	}
+	ids[theme.ID] = dir
+	*info = ThemePackInfo{
+		ID:          theme.ID,
+		Name:        theme.Name,
+		Description: theme.Description,
+		Version:     theme.Version,
+		SlotType:    slotType,
+		Dir:         dir,
+		Symbols:     len(theme.SymbolMap),
+		Active:      true,
+		LoadedAt:    time.Now(),
+	}
+	return nil
+}
+
+// requiredSymbols 主题包必须覆盖的符号ID，未设置时不校验（调用方持有锁）
+func (l *ThemePackLoader) requiredSymbols(slotType int32) ([]int, bool) {
+	if symbolIDs, ok := l.symbols[slotType]; ok {
+		return symbolIDs, true
+	}
+	symbolIDs, ok := l.symbols[0]
+	return symbolIDs, ok
+}
+
+// Packs 获取全部主题包的加载状态（按主题ID排序）
+func (l *ThemePackLoader) Packs() []ThemePackInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	packs := make([]ThemePackInfo, 0, len(l.packs))
+	for _, info := range l.packs {
+		packs = append(packs, *info)
+	}
+	sort.Slice(packs, func(i, j int) bool {
+		if packs[i].ID != packs[j].ID {
+			return packs[i].ID < packs[j].ID
+		}
+		return packs[i].Dir < packs[j].Dir
+	})
+	return packs
+}
+
+// Manager 主题包注册到的主题管理器（供渲染主题的引擎共用）
+func (l *ThemePackLoader) Manager() *ThemeManager {
+	return l.manager
+}
+
+// Theme 获取已注册的主题
+func (l *ThemePackLoader) Theme(themeID string) (*Theme, error) {
+	return l.manager.GetTheme(themeID)
+}
+
+// Watch 监听主题目录变化并重新加载，直到 ctx 结束
+// onReload 在每次重新加载后调用（可为空），参数为本次加载的错误
+func (l *ThemePackLoader) Watch(ctx context.Context, onReload func(error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("创建主题目录监听失败: %w", err)
+	}
+	if err := l.watchDirs(watcher); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				reload = time.After(themePackReloadDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				if onReload != nil {
+					onReload(err)
+				}
+			case <-reload:
+				reload = nil
+				err := l.LoadAll()
+				// 新增的主题包目录加入监听
+				if watchErr := l.watchDirs(watcher); watchErr != nil {
+					err = errors.Join(err, watchErr)
+				}
+				if onReload != nil {
+					onReload(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// watchDirs 监听根目录及每个主题包目录
+func (l *ThemePackLoader) watchDirs(watcher *fsnotify.Watcher) error {
+	if err := watcher.Add(l.root); err != nil {
+		return fmt.Errorf("监听主题目录失败: %w", err)
+	}
+	entries, err := os.ReadDir(l.root)
+	if err != nil {
+		return fmt.Errorf("读取主题目录失败: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := watcher.Add(filepath.Join(l.root, entry.Name())); err != nil {
+				return fmt.Errorf("监听主题包目录失败: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package slot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// themePackRoot 仓库自带的主题包目录
+const themePackRoot = "../../../config/themes"
+
+// copyThemePack 将仓库中的主题包拷贝到临时目录
+func copyThemePack(t *testing.T, name, dst string) {
+	t.Helper()
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(themePackRoot, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(themePackRoot, name, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// editThemePackFile 修改主题包中的文件内容
+func editThemePackFile(t *testing.T, path, old, replacement string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), old) {
+		t.Fatalf("%s does not contain %q", path, old)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), old, replacement, 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltinThemePacks(t *testing.T) {
+	config := GetMahjongAlgorithmConfig()
+	for name, slotType := range map[string]int32{"mahjong": 1, "pharaoh": 2, "777": 3} {
+		theme, gotType, err := LoadThemePack(filepath.Join(themePackRoot, name))
+		if err != nil {
+			t.Fatalf("LoadThemePack(%s) failed: %v", name, err)
+		}
+		if theme.ID != name || gotType != slotType {
+			t.Errorf("%s: id %q slot type %d", name, theme.ID, gotType)
+		}
+		if err := ValidateThemeSymbols(theme, config); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if symbol := theme.SymbolMap[SYMBOL_SCATTER]; symbol.ImageURL == "" {
+			t.Errorf("%s: scatter symbol has no image", name)
+		}
+		if len(theme.Animations[AnimationTypeWinLine]) == 0 || theme.Sounds[SoundTypeSpin].FileURL == "" {
+			t.Errorf("%s: animations or sounds not loaded", name)
+		}
+		if len(theme.Animations[AnimationTypeAnticipation]) == 0 {
+			t.Errorf("%s: anticipation animation not loaded", name)
+		}
+	}
+}
+
+func TestAlgorithmConfigSymbolIDs(t *testing.T) {
+	ids := GetMahjongAlgorithmConfig().SymbolIDs()
+	want := []int{0, 1, 2, 3, 4, 5, 6, 7, SYMBOL_SCATTER, SYMBOL_BONUS}
+	if len(ids) != len(want) {
+		t.Fatalf("SymbolIDs() = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("SymbolIDs() = %v, want %v", ids, want)
+		}
+	}
+
+	theme := &Theme{ID: "partial", SymbolMap: map[int]ThemeSymbol{0: {ID: 0}}}
+	if err := ValidateThemeSymbols(theme, GetMahjongAlgorithmConfig()); !errors.Is(err, ErrThemeSymbolMissing) {
+		t.Errorf("ValidateThemeSymbols err = %v, want ErrThemeSymbolMissing", err)
+	}
+}
+
+func TestLoadThemePackMissingAsset(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mahjong")
+	copyThemePack(t, "mahjong", dir)
+	editThemePackFile(t, filepath.Join(dir, "sounds.json"), `"sound.spin"`, `"sound.missing"`)
+
+	_, _, err := LoadThemePack(dir)
+	if !errors.Is(err, ErrInvalidThemePack) || !strings.Contains(err.Error(), "sound.missing") {
+		t.Fatalf("LoadThemePack err = %v, want missing asset error", err)
+	}
+}
+
+func TestThemePackLoader(t *testing.T) {
+	root := t.TempDir()
+	copyThemePack(t, "mahjong", filepath.Join(root, "mahjong"))
+	copyThemePack(t, "pharaoh", filepath.Join(root, "pharaoh"))
+
+	manager := NewThemeManager()
+	loader := NewThemePackLoader(root, manager)
+	loader.RequireSymbols(0, GetMahjongAlgorithmConfig())
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	packs := loader.Packs()
+	if len(packs) != 2 || packs[0].ID != "mahjong" || packs[1].ID != "pharaoh" || !packs[0].Active {
+		t.Fatalf("packs = %+v", packs)
+	}
+	if _, err := manager.GetTheme("pharaoh"); err != nil {
+		t.Fatalf("pharaoh theme not registered: %v", err)
+	}
+
+	// 缺少符号的修改被拒绝，继续使用上一个有效版本
+	pharaoh := filepath.Join(root, "pharaoh")
+	editThemePackFile(t, filepath.Join(pharaoh, "symbols.json"), `"id": 11,`, `"id": 12,`)
+	editThemePackFile(t, filepath.Join(pharaoh, "theme.json"), `"name": "法老王"`, `"name": "法老王2"`)
+	if err := loader.LoadAll(); !errors.Is(err, ErrThemeSymbolMissing) {
+		t.Fatalf("LoadAll err = %v, want ErrThemeSymbolMissing", err)
+	}
+	theme, err := manager.GetTheme("pharaoh")
+	if err != nil || theme.Name != "法老王" {
+		t.Fatalf("previous pharaoh theme should stay registered, got %+v, %v", theme, err)
+	}
+	if info := loader.Packs()[1]; !info.Active || info.Error == "" {
+		t.Errorf("pharaoh pack info = %+v, want active with error", info)
+	}
+
+	// 删除目录后注销主题，内置主题不受影响
+	if err := os.RemoveAll(pharaoh); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if _, err := manager.GetTheme("pharaoh"); err == nil {
+		t.Error("removed pack should be unregistered")
+	}
+	if _, err := manager.GetTheme("classic"); err != nil {
+		t.Error("builtin theme should stay registered")
+	}
+	if len(loader.Packs()) != 1 {
+		t.Errorf("packs = %+v", loader.Packs())
+	}
+}
+
+func TestThemePackLoaderWatch(t *testing.T) {
+	root := t.TempDir()
+	copyThemePack(t, "777", filepath.Join(root, "777"))
+
+	manager := NewThemeManager()
+	loader := NewThemePackLoader(root, manager)
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan error, 10)
+	if err := loader.Watch(ctx, func(err error) { reloaded <- err }); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	// 新增主题包目录
+	copyThemePack(t, "mahjong", filepath.Join(root, "mahjong"))
+	deadline := time.After(5 * time.Second)
+	for {
+		if _, err := manager.GetTheme("mahjong"); err == nil {
+			break
+		}
+		select {
+		case <-reloaded:
+		case <-deadline:
+			t.Fatal("new theme pack was not loaded")
+		}
+	}
+
+	// 修改已有主题包
+	editThemePackFile(t, filepath.Join(root, "777", "theme.json"), `"version": "1.0.0"`, `"version": "1.1.0"`)
+	for {
+		if theme, _ := manager.GetTheme("777"); theme.Version == "1.1.0" {
+			break
+		}
+		select {
+		case <-reloaded:
+		case <-deadline:
+			t.Fatal("modified theme pack was not reloaded")
+		}
+	}
+}
+
+func TestThemePackLoaderSlotSymbols(t *testing.T) {
+	root := t.TempDir()
+	pharaoh := filepath.Join(root, "pharaoh")
+	copyThemePack(t, "pharaoh", pharaoh)
+	// 去掉百搭符号：麻将的百搭由消除引擎生成，不在麻将算法配置的符号中
+	editThemePackFile(t, filepath.Join(pharaoh, "symbols.json"), `"id": -1,`, `"id": 99,`)
+
+	manager := NewThemeManager()
+	loader := NewThemePackLoader(root, manager)
+	loader.RequireSymbols(0, GetMahjongAlgorithmConfig())
+	if err := loader.LoadAll(); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+
+	// 法老王主题按法老王配置的符号校验
+	loader.RequireSlotSymbols(2, GetPharaohConfig())
+	err := loader.LoadAll()
+	if !errors.Is(err, ErrThemeSymbolMissing) || !strings.Contains(err.Error(), "[-1]") {
+		t.Fatalf("LoadAll err = %v, want wild symbol missing", err)
+	}
+	if info := loader.Packs()[0]; info.SlotType != 2 || info.Error == "" {
+		t.Errorf("pharaoh pack info = %+v, want error", info)
+	}
+}
@@ -3,6 +3,7 @@ package slot
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // ThemeRenderer 主题渲染器 - 将抽象结果转换为具体图案
@@ -14,6 +15,7 @@ type ThemeRenderer interface {
 	GetTheme(themeID string) (*Theme, error)
 	ListThemes() []string
 	RegisterTheme(theme *Theme) error
+	RemoveTheme(themeID string)
 }
 
 // ThemedGameResult 主题化游戏结果
@@ -28,6 +30,9 @@ type ThemedGameResult struct {
 	WinAnimations []Animation     `json:"win_animations"` // 获胜动画
 	SoundEffects  []SoundEffect   `json:"sound_effects"`  // 音效
 	VisualEffects []VisualEffect  `json:"visual_effects"` // 视觉特效
+
+	// 期待动画（停轮过程中播放：慢停卷轴和差一点中奖的符号高亮）
+	AnticipationAnimations []Animation `json:"anticipation_animations,omitempty"`
 }
 
 // ThemeSymbol 主题符号（重命名避免冲突）
@@ -85,6 +90,8 @@ const (
 	AnimationTypeFeatureTrigger
 	AnimationTypeBigWin
 	AnimationTypeJackpot
+	AnimationTypeAnticipation // 卷轴慢停
+	AnimationTypeNearMiss     // 差一点中奖的符号高亮
 )
 
 type SoundType int
@@ -182,6 +189,7 @@ type FontConfig struct {
 
 // DefaultThemeRenderer 默认主题渲染器实现
 type DefaultThemeRenderer struct {
+	mu     sync.RWMutex
 	themes map[string]*Theme
 }
 
@@ -215,14 +223,18 @@ func (r *DefaultThemeRenderer) RenderResult(abstract *AbstractGameResult, themeI
 	// 4. 生成视觉特效
 	visualEffects := r.generateVisualEffects(abstract, theme)
 
+	// 5. 生成期待动画
+	anticipationAnimations := r.generateAnticipationAnimations(abstract.Anticipation, theme)
+
 	return &ThemedGameResult{
-		AbstractGameResult: abstract,
-		ThemeID:            theme.ID,
-		ThemeName:          theme.Name,
-		ReelSymbols:        reelSymbols,
-		WinAnimations:      winAnimations,
-		SoundEffects:       soundEffects,
-		VisualEffects:      visualEffects,
+		AbstractGameResult:     abstract,
+		ThemeID:                theme.ID,
+		ThemeName:              theme.Name,
+		ReelSymbols:            reelSymbols,
+		WinAnimations:          winAnimations,
+		AnticipationAnimations: anticipationAnimations,
+		SoundEffects:           soundEffects,
+		VisualEffects:          visualEffects,
 	}, nil
 }
 
@@ -290,6 +302,42 @@ func (r *DefaultThemeRenderer) generateWinAnimations(abstract *AbstractGameResul
 	return animations
 }
 
+// generateAnticipationAnimations 生成期待动画
+// 每个慢停卷轴一个慢停动画（属性 reel 为卷轴，order 为第几个慢停），每个差一点中奖一个高亮动画
+func (r *DefaultThemeRenderer) generateAnticipationAnimations(anticipation *Anticipation, theme *Theme) []Animation {
+	if anticipation.IsEmpty() {
+		return nil
+	}
+	var animations []Animation
+
+	for order, reel := range anticipation.SlowReels {
+		for _, baseAnim := range theme.Animations[AnimationTypeAnticipation] {
+			anim := baseAnim
+			anim.Properties = map[string]interface{}{
+				"reel":  reel,
+				"order": order,
+			}
+			animations = append(animations, anim)
+		}
+	}
+
+	for _, nearMiss := range anticipation.NearMisses {
+		for _, baseAnim := range theme.Animations[AnimationTypeNearMiss] {
+			anim := baseAnim
+			anim.Target = nearMiss.Positions
+			anim.Properties = map[string]interface{}{
+				"near_miss": nearMiss.Type,
+				"symbol_id": nearMiss.SymbolID,
+				"count":     nearMiss.Count,
+				"required":  nearMiss.Required,
+			}
+			animations = append(animations, anim)
+		}
+	}
+
+	return animations
+}
+
 // generateSoundEffects 生成音效
 func (r *DefaultThemeRenderer) generateSoundEffects(abstract *AbstractGameResult, theme *Theme) []SoundEffect {
 	var sounds []SoundEffect
@@ -402,6 +450,8 @@ func (r *DefaultThemeRenderer) getDefaultSymbol(symbolID int) ThemeSymbol {
 
 // 主题管理方法
 func (r *DefaultThemeRenderer) GetTheme(themeID string) (*Theme, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	theme, exists := r.themes[themeID]
 	if !exists {
 		return nil, fmt.Errorf("theme not found: %s", themeID)
@@ -410,6 +460,8 @@ func (r *DefaultThemeRenderer) GetTheme(themeID string) (*Theme, error) {
 }
 
 func (r *DefaultThemeRenderer) ListThemes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	themes := make([]string, 0, len(r.themes))
 	for themeID := range r.themes {
 		themes = append(themes, themeID)
@@ -421,10 +473,19 @@ func (r *DefaultThemeRenderer) RegisterTheme(theme *Theme) error {
 	if theme.ID == "" {
 		return fmt.Errorf("theme ID cannot be empty")
 	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.themes[theme.ID] = theme
 	return nil
 }
 
+// RemoveTheme 注销主题（主题包目录删除时调用）
+func (r *DefaultThemeRenderer) RemoveTheme(themeID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.themes, themeID)
+}
+
 // registerBuiltinThemes 注册内置主题
 func (r *DefaultThemeRenderer) registerBuiltinThemes() {
 	// 经典老虎机主题
@@ -480,6 +541,26 @@ func (r *DefaultThemeRenderer) registerBuiltinThemes() {
 					},
 				},
 			},
+			AnimationTypeAnticipation: {
+				{
+					Type:     AnimationTypeAnticipation,
+					Duration: 2000,
+					Sequence: []AnimationFrame{
+						{ImageURL: "/images/effects/reel_glow_1.png", Duration: 400, Transform: Transform{Scale: 1.0, Alpha: 0.6}},
+						{ImageURL: "/images/effects/reel_glow_2.png", Duration: 400, Transform: Transform{Scale: 1.0, Alpha: 1.0}},
+					},
+				},
+			},
+			AnimationTypeNearMiss: {
+				{
+					Type:     AnimationTypeNearMiss,
+					Duration: 1200,
+					Sequence: []AnimationFrame{
+						{ImageURL: "/images/effects/pulse_1.png", Duration: 300, Transform: Transform{Scale: 1.0, Alpha: 1.0}},
+						{ImageURL: "/images/effects/pulse_2.png", Duration: 300, Transform: Transform{Scale: 1.15, Alpha: 0.7}},
+					},
+				},
+			},
 		},
 		Effects: map[EffectType]VisualEffect{
 			EffectTypeGlow:      {Type: EffectTypeGlow, Duration: 1000, Intensity: 0.8},
@@ -537,6 +618,21 @@ func (tm *ThemeManager) GetAvailableThemes() []string {
 	return tm.renderer.ListThemes()
 }
 
+// GetTheme 获取主题
+func (tm *ThemeManager) GetTheme(themeID string) (*Theme, error) {
+	return tm.renderer.GetTheme(themeID)
+}
+
+// RegisterTheme 注册主题
+func (tm *ThemeManager) RegisterTheme(theme *Theme) error {
+	return tm.renderer.RegisterTheme(theme)
+}
+
+// RemoveTheme 注销主题
+func (tm *ThemeManager) RemoveTheme(themeID string) {
+	tm.renderer.RemoveTheme(themeID)
+}
+
 // LoadThemeFromJSON 从JSON加载主题配置
 func (tm *ThemeManager) LoadThemeFromJSON(jsonData []byte) error {
 	var theme Theme
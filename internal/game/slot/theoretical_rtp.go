@@ -0,0 +1,729 @@
+package slot
+
+import (
+	"math"
+	"sort"
+)
+
+// enumerationLimit 单条支付线全量枚举的组合数上限，超过后改用加权卷积
+const enumerationLimit = 1 << 20
+
+// 理论RTP计算方法
+const (
+	RTPMethodEnumeration = "enumeration" // 全量枚举
+	RTPMethodConvolution = "convolution" // 加权卷积
+	RTPMethodWays        = "ways"        // 全路径按卷轴解析计算
+)
+
+// PaylineRTP 单条支付线的理论RTP
+type PaylineRTP struct {
+	LineID  int     `json:"line_id"`  // 支付线ID
+	Length  int     `json:"length"`   // 支付线长度
+	RTP     float64 `json:"rtp"`      // 理论RTP（相对总下注）
+	HitRate float64 `json:"hit_rate"` // 期望中奖次数（单向派彩时即中奖概率）
+	Method  string  `json:"method"`   // 计算方法
+}
+
+// SymbolRTP 单个符号的支付线理论RTP
+type SymbolRTP struct {
+	Symbol  Symbol  `json:"symbol"`   // 符号
+	RTP     float64 `json:"rtp"`      // 理论RTP（所有支付线合计）
+	HitRate float64 `json:"hit_rate"` // 每次旋转的期望中奖线数
+}
+
+// TheoreticalRTP 理论RTP计算结果
+// 基础游戏 = 支付线 + Scatter + 奖励游戏，免费旋转按触发时的下注额、免费游戏倍率和卷轴组计算（含再触发与次数上限）
+type TheoreticalRTP struct {
+	MachineID    string `json:"machine_id"`            // 机器ID
+	ReelSetID    string `json:"reel_set_id,omitempty"` // 卷轴组ID
+	Method       string `json:"method"`                // 计算方法（存在卷积时为convolution）
+	Combinations int64  `json:"combinations"`          // 枚举的组合总数
+
+	TotalRTP    float64 `json:"total_rtp"`     // 总理论RTP
+	BaseGameRTP float64 `json:"base_game_rtp"` // 单次旋转RTP（不含免费旋转）
+	LineRTP     float64 `json:"line_rtp"`      // 支付线贡献
+	ScatterRTP  float64 `json:"scatter_rtp"`   // Scatter派彩贡献
+	BonusRTP    float64 `json:"bonus_rtp"`     // 奖励游戏贡献
+	FreeSpinRTP float64 `json:"free_spin_rtp"` // 免费旋转贡献
+
+	FreeSpinTriggerRate float64 `json:"free_spin_trigger_rate"` // 免费旋转触发概率
+	ExpectedFreeSpins   float64 `json:"expected_free_spins"`    // 每次旋转期望获得的免费旋转次数
+	ExpectedFreeGames   float64 `json:"expected_free_games"`    // 每次旋转期望进行的免费旋转次数（含再触发）
+	BonusTriggerRate    float64 `json:"bonus_trigger_rate"`     // 奖励游戏触发概率
+	BonusExpectedWin    float64 `json:"bonus_expected_win"`     // 每次奖励游戏的期望赢取（下注额倍数）
+
+	Paylines []PaylineRTP `json:"paylines"` // 各支付线RTP
+	Symbols  []SymbolRTP  `json:"symbols"`  // 各符号RTP
+}
+
+// weightedSymbol 卷轴上的符号及其出现概率
+type weightedSymbol struct {
+	symbol Symbol
+	prob   float64
+}
+
+// lineResult 单条支付线的计算结果
+type lineResult struct {
+	rtp          float64
+	hitRate      float64
+	symbolRTP    map[Symbol]float64
+	symbolHits   map[Symbol]float64
+	method       string
+	combinations int64
+}
+
+// freeGameAward 触发免费游戏的概率及获得的次数
+type freeGameAward struct {
+	prob  float64
+	spins int
+}
+
+// CalculateTheoreticalRTP 计算配置的精确理论RTP
+// 每个格子按卷轴条权重独立抽取，因此支付线上的符号相互独立：
+// 组合数较小时全量枚举，较大时按卷轴逐个做加权卷积，两种方法结果一致
+func CalculateTheoreticalRTP(config *SlotConfig) (*TheoreticalRTP, error) {
+	result, baseAwards, err := calculateBaseGameRTP(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// 免费游戏：每次免费旋转的RTP = 免费卷轴组上的单次RTP × 倍率
+	freeConfig := config.freeGameConfig()
+	freeGame, freeAwards := result, baseAwards
+	if reelConfig := freeGameReelConfig(config); reelConfig != config {
+		if freeGame, freeAwards, err = calculateBaseGameRTP(reelConfig); err != nil {
+			return nil, err
+		}
+	}
+	result.ExpectedFreeGames, err = expectedFreeGames(freeConfig, baseAwards, freeAwards)
+	if err != nil {
+		return nil, err
+	}
+	result.FreeSpinRTP = result.ExpectedFreeGames * freeGame.BaseGameRTP * freeConfig.GetMultiplier()
+	result.TotalRTP = result.BaseGameRTP + result.FreeSpinRTP
+
+	return result, nil
+}
+
+// calculateBaseGameRTP 计算单次旋转的RTP及免费游戏触发分布
+func calculateBaseGameRTP(config *SlotConfig) (*TheoreticalRTP, []freeGameAward, error) {
+	if config.Reels <= 0 || config.Rows <= 0 {
+		return nil, nil, ErrInvalidConfig
+	}
+	dists, err := reelDistributions(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matcher := NewAdvancedPatternMatcher(config)
+	result := &TheoreticalRTP{
+		MachineID: config.MachineID,
+		Method:    RTPMethodEnumeration,
+	}
+
+	symbolRTP := make(map[Symbol]float64)
+	symbolHits := make(map[Symbol]float64)
+	paylines := matcher.paylinePatterns
+	if config.WinMode.IsWays() {
+		// 全路径：各卷轴独立，按卷轴解析计算
+		result.Method = RTPMethodWays
+		ways := computeWays(config, matcher, dists)
+		result.LineRTP = ways.rtp
+		for symbol, rtp := range ways.symbolRTP {
+			symbolRTP[symbol] += rtp
+			symbolHits[symbol] += ways.symbolHits[symbol]
+		}
+		paylines = nil
+	}
+
+	// 支付线（相同卷轴序列的支付线结果相同，缓存复用）
+	cache := make(map[string]*lineResult)
+	for lineID, pattern := range paylines {
+		key := lineKey(pattern)
+		line, ok := cache[key]
+		if !ok {
+			line = computeLine(matcher, dists, pattern)
+			cache[key] = line
+			result.Combinations += line.combinations
+		}
+		if line.method == RTPMethodConvolution {
+			result.Method = RTPMethodConvolution
+		}
+
+		result.Paylines = append(result.Paylines, PaylineRTP{
+			LineID:  lineID,
+			Length:  len(pattern),
+			RTP:     line.rtp,
+			HitRate: line.hitRate,
+			Method:  line.method,
+		})
+		result.LineRTP += line.rtp
+		for symbol, rtp := range line.symbolRTP {
+			symbolRTP[symbol] += rtp
+			symbolHits[symbol] += line.symbolHits[symbol]
+		}
+	}
+
+	for symbol, rtp := range symbolRTP {
+		result.Symbols = append(result.Symbols, SymbolRTP{
+			Symbol:  symbol,
+			RTP:     rtp,
+			HitRate: symbolHits[symbol],
+		})
+	}
+	sort.Slice(result.Symbols, func(i, j int) bool {
+		if result.Symbols[i].RTP != result.Symbols[j].RTP {
+			return result.Symbols[i].RTP > result.Symbols[j].RTP
+		}
+		return result.Symbols[i].Symbol < result.Symbols[j].Symbol
+	})
+
+	// Scatter派彩
+	scatterCounts := symbolCountDistribution(config, dists, matcher.isScatter)
+	for count := 3; count < len(scatterCounts); count++ {
+		result.ScatterRTP += scatterCounts[count] * matcher.getMultiplier(SymbolScatter, count)
+	}
+
+	// 免费游戏触发（触发符号未配置时为Scatter）
+	var awards []freeGameAward
+	freeConfig := config.freeGameConfig()
+	triggerCounts := symbolCountDistribution(config, dists, matcher.isFreeGameTrigger)
+	for count := matcher.freeGameTriggerCount(); count < len(triggerCounts); count++ {
+		spins := freeConfig.SpinsFor(count)
+		if spins <= 0 || triggerCounts[count] == 0 {
+			continue
+		}
+		p := triggerCounts[count]
+		awards = append(awards, freeGameAward{prob: p, spins: spins})
+		result.FreeSpinTriggerRate += p
+		result.ExpectedFreeSpins += p * float64(spins)
+	}
+
+	// 选择奖励游戏（每次触发的期望赢取由格子奖品表精确计算）
+	bonusCounts := symbolCountDistribution(config, dists, matcher.isBonus)
+	for count := 3; count < len(bonusCounts); count++ {
+		result.BonusTriggerRate += bonusCounts[count]
+	}
+	result.BonusExpectedWin = config.bonusGameConfig().ExpectedMultiplier()
+	result.BonusRTP = result.BonusTriggerRate * result.BonusExpectedWin
+
+	result.BaseGameRTP = result.LineRTP + result.ScatterRTP + result.BonusRTP
+	result.TotalRTP = result.BaseGameRTP
+
+	return result, awards, nil
+}
+
+// freeGameReelConfig 免费游戏使用的卷轴配置（未配置独立卷轴组时为原配置）
+func freeGameReelConfig(config *SlotConfig) *SlotConfig {
+	freeConfig := config.freeGameConfig()
+	if freeConfig == nil || freeConfig.ReelSetID == "" {
+		return config
+	}
+	for _, set := range config.ReelSets {
+		if set.ID == freeConfig.ReelSetID {
+			reelConfig := *config
+			reelConfig.ReelStrips = set.ReelStrips
+			return &reelConfig
+		}
+	}
+	return config
+}
+
+// expectedFreeGames 每次基础旋转期望进行的免费旋转次数
+// 不可再触发时为触发次数的期望；可再触发且无上限时，每次免费旋转期望再获得a次，总次数 = s/(1-a)；
+// 有累计上限时按（已获得次数, 剩余次数）做动态规划
+func expectedFreeGames(config *FreeGameConfig, baseAwards, freeAwards []freeGameAward) (float64, error) {
+	maxSpins := 0
+	if config != nil {
+		maxSpins = config.MaxSpins
+	}
+
+	if !config.CanRetrigger() || maxSpins <= 0 {
+		retrigger := 0.0
+		if config.CanRetrigger() {
+			for _, award := range freeAwards {
+				retrigger += award.prob * float64(award.spins)
+			}
+			if retrigger >= 1 {
+				return 0, ErrRTPOutOfRange // 再触发期望不小于1，免费游戏不会结束
+			}
+		}
+		expected := 0.0
+		for _, award := range baseAwards {
+			expected += award.prob * float64(config.capSpins(0, award.spins))
+		}
+		return expected / (1 - retrigger), nil
+	}
+
+	// f[t][r]：已累计获得t次、剩余r次时期望还会进行的次数
+	f := make([][]float64, maxSpins+1)
+	for t := maxSpins; t >= 0; t-- {
+		f[t] = make([]float64, t+1)
+		for r := 1; r <= t; r++ {
+			expected, missed := 1.0, 1.0
+			for _, award := range freeAwards {
+				added := config.capSpins(t, award.spins)
+				expected += award.prob * f[t+added][r-1+added]
+				missed -= award.prob
+			}
+			f[t][r] = expected + missed*f[t][r-1]
+		}
+	}
+
+	expected := 0.0
+	for _, award := range baseAwards {
+		spins := config.capSpins(0, award.spins)
+		expected += award.prob * f[spins][spins]
+	}
+	return expected, nil
+}
+
+// reelDistributions 计算各卷轴的符号概率（同一卷轴上相同符号的权重合并）
+func reelDistributions(config *SlotConfig) ([][]weightedSymbol, error) {
+	if len(config.ReelStrips) != config.Reels {
+		return nil, ErrInvalidReelStrips
+	}
+
+	dists := make([][]weightedSymbol, config.Reels)
+	for i, strip := range config.ReelStrips {
+		if len(strip.Symbols) == 0 || len(strip.Symbols) != len(strip.Weights) {
+			return nil, ErrInvalidReelStrips
+		}
+
+		total := 0
+		weights := make(map[Symbol]int)
+		var order []Symbol
+		for j, weight := range strip.Weights {
+			if weight < 0 {
+				return nil, ErrInvalidReelStrips
+			}
+			symbol := strip.Symbols[j]
+			if _, seen := weights[symbol]; !seen {
+				order = append(order, symbol)
+			}
+			weights[symbol] += weight
+			total += weight
+		}
+		if total == 0 {
+			return nil, ErrInvalidReelStrips
+		}
+
+		for _, symbol := range order {
+			if weights[symbol] > 0 {
+				dists[i] = append(dists[i], weightedSymbol{
+					symbol: symbol,
+					prob:   float64(weights[symbol]) / float64(total),
+				})
+			}
+		}
+	}
+	return dists, nil
+}
+
+// lineKey 支付线的卷轴序列键
+func lineKey(pattern []Position) string {
+	key := make([]byte, len(pattern))
+	for i, pos := range pattern {
+		key[i] = byte(pos.Reel)
+	}
+	return string(key)
+}
+
+// computeLine 计算单条支付线，按组合数选择枚举或卷积
+func computeLine(m *AdvancedPatternMatcher, dists [][]weightedSymbol, pattern []Position) *lineResult {
+	combinations := int64(1)
+	for _, pos := range pattern {
+		combinations *= int64(len(dists[pos.Reel]))
+		if combinations > enumerationLimit {
+			return convolveLineDirected(m, dists, pattern)
+		}
+	}
+	return enumerateLine(m, dists, pattern)
+}
+
+// convolveLineDirected 按派彩方向卷积计算支付线
+// 双向派彩 = 从左到右 + 从右到左 − 左起满线时的从右到左派彩（满线只计一次）
+func convolveLineDirected(m *AdvancedPatternMatcher, dists [][]weightedSymbol, pattern []Position) *lineResult {
+	_, reversed := reverseLine(make([]Symbol, len(pattern)), pattern)
+
+	switch m.payDirection() {
+	case PayDirectionRightToLeft:
+		return convolveLine(m, dists, reversed)
+	case PayDirectionBoth:
+		result := convolveLine(m, dists, pattern)
+		result.merge(convolveLine(m, dists, reversed))
+		subtractFullLineOverlap(m, dists, pattern, result)
+		return result
+	}
+	return convolveLine(m, dists, pattern)
+}
+
+// subtractFullLineOverlap 扣除左起满线中奖时从右到左的派彩
+func subtractFullLineOverlap(m *AdvancedPatternMatcher, dists [][]weightedSymbol, pattern []Position, result *lineResult) {
+	n := len(pattern)
+	if n < 3 {
+		return
+	}
+
+	wildProb := make([]float64, n)
+	symbolProb := make([]map[Symbol]float64, n)
+	candidates := make(map[Symbol]bool)
+	for i, pos := range pattern {
+		symbolProb[i] = make(map[Symbol]float64)
+		for _, ws := range dists[pos.Reel] {
+			symbolProb[i][ws.symbol] += ws.prob
+			if m.isWild(ws.symbol) {
+				wildProb[i] += ws.prob
+			} else {
+				candidates[ws.symbol] = true
+			}
+		}
+	}
+
+	// 全部为Wild：从右到左以末位Wild为中奖符号，后续Wild各乘1.5
+	allWild := 1.0
+	for i := 0; i < n-1; i++ {
+		allWild *= wildProb[i]
+	}
+	for symbol, p := range symbolProb[n-1] {
+		if m.isWild(symbol) {
+			pay := m.getMultiplier(symbol, n) * math.Pow(1.5, float64(n-1))
+			result.subtract(symbol, p*allWild, p*allWild*pay)
+		}
+	}
+
+	for symbol := range candidates {
+		if m.isScatter(symbol) {
+			continue // Scatter不在支付线上派彩，不构成满线
+		}
+
+		if m.isBonus(symbol) {
+			// 不可替代：全部为该符号，或仅首位为Wild（此时从右到左在Wild处中断）
+			rest := 1.0
+			for i := 1; i < n; i++ {
+				rest *= symbolProb[i][symbol]
+			}
+			all := symbolProb[0][symbol] * rest
+			result.subtract(symbol, all, all*m.getMultiplier(symbol, n))
+			if n-1 >= 3 {
+				leadWild := wildProb[0] * rest
+				result.subtract(symbol, leadWild, leadWild*m.getMultiplier(symbol, n-1))
+			}
+			continue
+		}
+
+		// 每个位置为该符号或Wild且至少一个为该符号；从右到左时首位（末卷轴）的Wild不加倍
+		prob, weighted := 1.0, 1.0
+		wildOnly, wildOnlyWeighted := 1.0, 1.0
+		for i := 0; i < n; i++ {
+			factor := 1.5
+			if i == n-1 {
+				factor = 1
+			}
+			prob *= symbolProb[i][symbol] + wildProb[i]
+			weighted *= symbolProb[i][symbol] + factor*wildProb[i]
+			wildOnly *= wildProb[i]
+			wildOnlyWeighted *= factor * wildProb[i]
+		}
+		result.subtract(symbol, prob-wildOnly, (weighted-wildOnlyWeighted)*m.getMultiplier(symbol, n))
+	}
+}
+
+// enumerateLine 全量枚举支付线上的所有符号组合
+func enumerateLine(m *AdvancedPatternMatcher, dists [][]weightedSymbol, pattern []Position) *lineResult {
+	result := newLineResult(RTPMethodEnumeration)
+	n := len(pattern)
+	indices := make([]int, n)
+	symbols := make([]Symbol, n)
+
+	for {
+		prob := 1.0
+		for i, pos := range pattern {
+			ws := dists[pos.Reel][indices[i]]
+			symbols[i] = ws.symbol
+			prob *= ws.prob
+		}
+		result.combinations++
+
+		for _, win := range m.evaluateLine(symbols, 0, pattern) {
+			pay := m.getMultiplier(win.Symbol, win.Count) * math.Max(win.Multiplier, 1)
+			result.add(win.Symbol, prob, pay)
+		}
+
+		// 进位到下一个组合
+		i := n - 1
+		for ; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(dists[pattern[i].Reel]) {
+				break
+			}
+			indices[i] = 0
+		}
+		if i < 0 {
+			break
+		}
+	}
+	return result
+}
+
+// convolveLine 按卷轴逐个卷积计算支付线期望，与 checkConsecutiveSymbols 的判定规则一致：
+// 以第一个非Wild符号为中奖符号，首位之后的Wild参与连线时每个乘以1.5，Scatter/Bonus不可被替代
+func convolveLine(m *AdvancedPatternMatcher, dists [][]weightedSymbol, pattern []Position) *lineResult {
+	result := newLineResult(RTPMethodConvolution)
+	n := len(pattern)
+	if n == 0 {
+		return result
+	}
+
+	wildProb := make([]float64, n)
+	symbolProb := make([]map[Symbol]float64, n)
+	candidates := make(map[Symbol]bool)
+	for i, pos := range pattern {
+		symbolProb[i] = make(map[Symbol]float64)
+		for _, ws := range dists[pos.Reel] {
+			symbolProb[i][ws.symbol] += ws.prob
+			if m.isWild(ws.symbol) {
+				wildProb[i] += ws.prob
+			} else {
+				candidates[ws.symbol] = true
+			}
+		}
+	}
+
+	// 全部为Wild：中奖符号为首位Wild
+	allWild := 1.0
+	for i := 1; i < n; i++ {
+		allWild *= wildProb[i]
+	}
+	if n >= 3 && allWild > 0 {
+		for symbol, p := range symbolProb[0] {
+			if m.isWild(symbol) {
+				pay := m.getMultiplier(symbol, n) * math.Pow(1.5, float64(n-1))
+				result.add(symbol, p*allWild, pay)
+			}
+		}
+	}
+
+	// 第一个非Wild符号位于位置j
+	for symbol := range candidates {
+		if m.isScatter(symbol) {
+			continue // Scatter不在支付线上派彩
+		}
+		substitutable := !m.isBonus(symbol)
+
+		prefix := 1.0 // 前j个位置均为Wild的概率
+		for j := 0; j < n; j++ {
+			if j > 0 {
+				prefix *= wildProb[j-1]
+			}
+			start := prefix * symbolProb[j][symbol]
+			if start == 0 {
+				continue
+			}
+
+			if !substitutable {
+				// Wild不能替代：首位Wild后紧跟Wild即中断
+				if j >= 2 {
+					continue
+				}
+				walkRun(m, result, symbol, symbolProb, nil, j, start, 1)
+				continue
+			}
+
+			// 首位Wild不加倍，其后的前缀Wild每个乘以1.5
+			walkRun(m, result, symbol, symbolProb, wildProb, j, start, math.Pow(1.5, math.Max(float64(j-1), 0)))
+		}
+	}
+	return result
+}
+
+// walkRun 从位置j之后累计连续段，wildProb为nil表示Wild不参与替代
+// weight为到达当前长度的概率，multiplier为对应的期望Wild倍率
+func walkRun(m *AdvancedPatternMatcher, result *lineResult, symbol Symbol, symbolProb []map[Symbol]float64,
+	wildProb []float64, j int, prob, multiplier float64) {
+	n := len(symbolProb)
+	// weighted 为概率与Wild倍率乘积的累计，用于求期望派彩
+	weighted := prob * multiplier
+	for k := j + 1; ; k++ {
+		count := k
+		// 连续段在位置k处中断（或到达末尾）
+		stop, stopWeighted := 1.0, 1.0
+		if k < n {
+			cont, contWeighted := symbolProb[k][symbol], symbolProb[k][symbol]
+			if wildProb != nil {
+				cont += wildProb[k]
+				contWeighted += wildProb[k] * 1.5
+			}
+			stop = 1 - cont
+			stopWeighted = stop
+			if count >= 3 && stop > 0 {
+				result.addWeighted(symbol, prob*stop, weighted*stopWeighted*m.getMultiplier(symbol, count))
+			}
+			prob *= cont
+			weighted *= contWeighted
+			if prob == 0 {
+				return
+			}
+			continue
+		}
+		if count >= 3 {
+			result.addWeighted(symbol, prob, weighted*m.getMultiplier(symbol, count))
+		}
+		return
+	}
+}
+
+// symbolCountDistribution 计算整个盘面上指定类型符号数量的分布（逐格卷积，行数可变时按行数分布加权）
+func symbolCountDistribution(config *SlotConfig, dists [][]weightedSymbol, match func(Symbol) bool) []float64 {
+	counts := []float64{1}
+	for reel, dist := range dists {
+		p := 0.0
+		for _, ws := range dist {
+			if match(ws.symbol) {
+				p += ws.prob
+			}
+		}
+
+		// 单个卷轴上的数量分布
+		heights := reelHeightDistribution(config, reel)
+		reelCounts := make([]float64, len(heights))
+		binomial := []float64{1}
+		for rows := 0; rows < len(heights); rows++ {
+			if rows > 0 {
+				next := make([]float64, len(binomial)+1)
+				for k, q := range binomial {
+					next[k] += q * (1 - p)
+					next[k+1] += q * p
+				}
+				binomial = next
+			}
+			for k, q := range binomial {
+				reelCounts[k] += heights[rows] * q
+			}
+		}
+
+		next := make([]float64, len(counts)+len(reelCounts)-1)
+		for i, a := range counts {
+			for j, b := range reelCounts {
+				next[i+j] += a * b
+			}
+		}
+		counts = next
+	}
+	return counts
+}
+
+// computeWays 全路径理论RTP
+// 各卷轴独立：设C为卷轴上目标符号或Wild的数量、W为Wild数量，
+// 长度为L的中奖期望路数 = (ΠE[C] − ΠE[W·1{无目标符号}]) × P(第L+1卷轴C=0)，后一项扣除纯Wild连线
+func computeWays(config *SlotConfig, m *AdvancedPatternMatcher, dists [][]weightedSymbol) *lineResult {
+	result := newLineResult(RTPMethodWays)
+	units := float64(m.betUnits())
+
+	candidates := make(map[Symbol]bool)
+	for _, dist := range dists {
+		for _, ws := range dist {
+			if m.paysWays(ws.symbol) {
+				candidates[ws.symbol] = true
+			}
+		}
+	}
+
+	n := len(dists)
+	for symbol := range candidates {
+		expected := make([]float64, n)    // E[C]
+		wildOnly := make([]float64, n)    // E[W·1{无目标符号}]
+		present := make([]float64, n)     // P(C>0)
+		wildPresent := make([]float64, n) // P(W>0且无目标符号)
+		absent := make([]float64, n)      // P(C=0)
+		for reel, dist := range dists {
+			p, w := 0.0, 0.0
+			for _, ws := range dist {
+				if ws.symbol == symbol {
+					p += ws.prob
+				} else if m.isWild(ws.symbol) {
+					w += ws.prob
+				}
+			}
+			q := p + w
+			for rows, h := range reelHeightDistribution(config, reel) {
+				if h == 0 {
+					continue
+				}
+				r := float64(rows)
+				expected[reel] += h * r * q
+				if rows > 0 {
+					wildOnly[reel] += h * r * w * math.Pow(1-p, r-1)
+				}
+				absent[reel] += h * math.Pow(1-q, r)
+				wildPresent[reel] += h * (math.Pow(1-p, r) - math.Pow(1-q, r))
+			}
+			present[reel] = 1 - absent[reel]
+		}
+
+		ways, wildWays := 1.0, 1.0
+		hits, wildHits := 1.0, 1.0
+		for length := 1; length <= n; length++ {
+			ways *= expected[length-1]
+			wildWays *= wildOnly[length-1]
+			hits *= present[length-1]
+			wildHits *= wildPresent[length-1]
+			if length < 3 {
+				continue
+			}
+
+			stop := 1.0
+			if length < n {
+				stop = absent[length]
+			}
+			pay := m.getMultiplier(symbol, length) / units
+			result.addWeighted(symbol, (hits-wildHits)*stop, (ways-wildWays)*stop*pay)
+		}
+	}
+	return result
+}
+
+func newLineResult(method string) *lineResult {
+	return &lineResult{
+		symbolRTP:  make(map[Symbol]float64),
+		symbolHits: make(map[Symbol]float64),
+		method:     method,
+	}
+}
+
+// add 累计一种中奖结果（pay为相对单线下注的赔付倍数）
+func (r *lineResult) add(symbol Symbol, prob, pay float64) {
+	r.addWeighted(symbol, prob, prob*pay)
+}
+
+// merge 合并另一方向的计算结果
+func (r *lineResult) merge(other *lineResult) {
+	r.rtp += other.rtp
+	r.hitRate += other.hitRate
+	for symbol, rtp := range other.symbolRTP {
+		r.symbolRTP[symbol] += rtp
+		r.symbolHits[symbol] += other.symbolHits[symbol]
+	}
+}
+
+// subtract 扣除重复计算的中奖
+func (r *lineResult) subtract(symbol Symbol, prob, expectedPay float64) {
+	if expectedPay <= 0 {
+		return
+	}
+	r.rtp -= expectedPay
+	r.hitRate -= prob
+	r.symbolRTP[symbol] -= expectedPay
+	r.symbolHits[symbol] -= prob
+}
+
+// addWeighted 累计中奖概率和期望赔付
+func (r *lineResult) addWeighted(symbol Symbol, prob, expectedPay float64) {
+	if expectedPay <= 0 {
+		return
+	}
+	r.rtp += expectedPay
+	r.hitRate += prob
+	r.symbolRTP[symbol] += expectedPay
+	r.symbolHits[symbol] += prob
+}
@@ -0,0 +1,184 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateTheoreticalRTP_SimpleConfig(t *testing.T) {
+	config := &SlotConfig{
+		MachineID:      "simple",
+		Rows:           3,
+		Reels:          3,
+		ActivePaylines: 1,
+		TargetRTP:      0.95,
+		PayTables: []PayTable{
+			{Symbol: SymbolCherry, Count: 3, Multiplier: 10},
+			{Symbol: SymbolLemon, Count: 3, Multiplier: 2},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		config.ReelStrips = append(config.ReelStrips, ReelStrip{
+			ReelID:  i,
+			Symbols: []Symbol{SymbolCherry, SymbolLemon},
+			Weights: []int{1, 1},
+		})
+	}
+
+	result, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+
+	// 1/8 * 10 + 1/8 * 2 = 1.5
+	if math.Abs(result.LineRTP-1.5) > 1e-12 {
+		t.Errorf("LineRTP = %v, want 1.5", result.LineRTP)
+	}
+	if math.Abs(result.Paylines[0].HitRate-0.25) > 1e-12 {
+		t.Errorf("HitRate = %v, want 0.25", result.Paylines[0].HitRate)
+	}
+	if result.Method != RTPMethodEnumeration || result.Combinations != 8 {
+		t.Errorf("Method = %v, Combinations = %v, want enumeration/8", result.Method, result.Combinations)
+	}
+	if result.ScatterRTP != 0 || result.FreeSpinRTP != 0 {
+		t.Errorf("config without scatter should have no scatter/free spin RTP")
+	}
+}
+
+func TestCalculateTheoreticalRTP_ConvolutionMatchesEnumeration(t *testing.T) {
+	for _, config := range []*SlotConfig{GetDefaultConfig(), GetLuckySevenConfig(), GetMegaFruitConfig()} {
+		dists, err := reelDistributions(config)
+		if err != nil {
+			t.Fatalf("reelDistributions failed: %v", err)
+		}
+		matcher := NewAdvancedPatternMatcher(config)
+
+		for lineID, pattern := range matcher.paylinePatterns {
+			enumerated := enumerateLine(matcher, dists, pattern)
+			convolved := convolveLine(matcher, dists, pattern)
+			if math.Abs(enumerated.rtp-convolved.rtp) > 1e-12 {
+				t.Errorf("%s line %d: enumeration RTP %v != convolution RTP %v",
+					config.MachineID, lineID, enumerated.rtp, convolved.rtp)
+			}
+			if math.Abs(enumerated.hitRate-convolved.hitRate) > 1e-12 {
+				t.Errorf("%s line %d: enumeration hit rate %v != convolution hit rate %v",
+					config.MachineID, lineID, enumerated.hitRate, convolved.hitRate)
+			}
+			for symbol, rtp := range enumerated.symbolRTP {
+				if math.Abs(rtp-convolved.symbolRTP[symbol]) > 1e-12 {
+					t.Errorf("%s line %d symbol %s: %v != %v",
+						config.MachineID, lineID, symbol, rtp, convolved.symbolRTP[symbol])
+				}
+			}
+		}
+	}
+}
+
+func TestCalculateTheoreticalRTP_MatchesMonteCarlo(t *testing.T) {
+	config := GetDefaultConfig()
+	result, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	matcher := NewAdvancedPatternMatcher(config)
+	rng := NewDRBGRandomGenerator(2024)
+
+	// 不经过RTP控制器，直接按卷轴权重生成盘面
+	const spins = 200000
+	totalPay := 0.0
+	for i := 0; i < spins; i++ {
+		reels := engine.generateReels(rng, config.ReelStrips, false)
+		for _, line := range matcher.FindWinningLines(reels, config) {
+			totalPay += matcher.getMultiplier(line.Symbol, line.Count) * math.Max(line.Multiplier, 1)
+		}
+	}
+
+	simulated := totalPay / spins
+	expected := result.LineRTP + result.ScatterRTP
+	if math.Abs(simulated-expected) > 0.03 {
+		t.Errorf("simulated line+scatter RTP %v too far from theoretical %v", simulated, expected)
+	}
+}
+
+func TestCalculateTheoreticalRTP_Breakdown(t *testing.T) {
+	result, err := CalculateTheoreticalRTP(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+
+	lineSum, symbolSum := 0.0, 0.0
+	for _, line := range result.Paylines {
+		lineSum += line.RTP
+	}
+	for _, symbol := range result.Symbols {
+		symbolSum += symbol.RTP
+	}
+	if math.Abs(lineSum-result.LineRTP) > 1e-9 || math.Abs(symbolSum-result.LineRTP) > 1e-9 {
+		t.Errorf("payline sum %v and symbol sum %v should equal LineRTP %v", lineSum, symbolSum, result.LineRTP)
+	}
+
+	if result.FreeSpinTriggerRate <= 0 || result.ScatterRTP <= 0 || result.FreeSpinRTP <= 0 {
+		t.Errorf("default config should have scatter and free spin contribution: %+v", result)
+	}
+	want := result.LineRTP + result.ScatterRTP + result.BonusRTP + result.FreeSpinRTP
+	if math.Abs(result.TotalRTP-want) > 1e-12 {
+		t.Errorf("TotalRTP = %v, want %v", result.TotalRTP, want)
+	}
+}
+
+func TestCalculateTheoreticalRTP_InvalidStrips(t *testing.T) {
+	config := GetDefaultConfig()
+	config.ReelStrips[0].Weights = config.ReelStrips[0].Weights[:3]
+	if _, err := CalculateTheoreticalRTP(config); err != ErrInvalidReelStrips {
+		t.Errorf("err = %v, want ErrInvalidReelStrips", err)
+	}
+}
+
+func TestValidateConfig_RTPBand(t *testing.T) {
+	config := GetDefaultConfig()
+	result, err := CalculateTheoreticalRTP(config)
+	if err != nil {
+		t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+	}
+
+	config.RTPBand = &RTPBand{Min: result.TotalRTP - 0.01, Max: result.TotalRTP + 0.01}
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("config inside band rejected: %v", err)
+	}
+
+	config.RTPBand = &RTPBand{Min: result.TotalRTP - 0.10, Max: result.TotalRTP - 0.05}
+	if err := ValidateConfig(config); err != ErrRTPOutOfRange {
+		t.Errorf("err = %v, want ErrRTPOutOfRange", err)
+	}
+}
+
+func TestCalculateTheoreticalRTP_PayDirections(t *testing.T) {
+	for _, direction := range []PayDirection{PayDirectionRightToLeft, PayDirectionBoth} {
+		for _, config := range []*SlotConfig{GetDefaultConfig(), GetLuckySevenConfig()} {
+			config.PayDirection = direction
+			dists, err := reelDistributions(config)
+			if err != nil {
+				t.Fatalf("reelDistributions failed: %v", err)
+			}
+			matcher := NewAdvancedPatternMatcher(config)
+
+			for lineID, pattern := range matcher.paylinePatterns {
+				enumerated := enumerateLine(matcher, dists, pattern)
+				convolved := convolveLineDirected(matcher, dists, pattern)
+				if math.Abs(enumerated.rtp-convolved.rtp) > 1e-12 {
+					t.Errorf("%s %s line %d: enumeration RTP %v != convolution RTP %v",
+						config.MachineID, direction, lineID, enumerated.rtp, convolved.rtp)
+				}
+				if math.Abs(enumerated.hitRate-convolved.hitRate) > 1e-12 {
+					t.Errorf("%s %s line %d: enumeration hit rate %v != convolution hit rate %v",
+						config.MachineID, direction, lineID, enumerated.hitRate, convolved.hitRate)
+				}
+			}
+		}
+	}
+}
@@ -30,6 +30,7 @@ const (
 	LineTypeDiagonal                   // 对角线
 	LineTypeV                          // V型
 	LineTypeZigzag                     // 之字形
+	LineTypeWays                       // 全路径（按卷轴相邻计算）
 )
 
 // SpinResult 旋转结果
@@ -48,6 +49,37 @@ type SpinResult struct {
 	IsJackpot   bool       `json:"is_jackpot"`   // 是否中大奖
 	RTP         float64    `json:"rtp"`          // 实际RTP
 	Timestamp   time.Time  `json:"timestamp"`    // 时间戳
+
+	// 审计数据
+	ReelSetID  string `json:"reel_set_id"`           // 使用的卷轴组ID
+	ConfigHash string `json:"config_hash,omitempty"` // 使用的配置版本哈希
+
+	// 全路径模式下本次盘面的路数（各卷轴行数之积）
+	Ways int `json:"ways,omitempty"`
+
+	// 本局赢取达到机台最高赢取（赢取已截断，本局提前结束）
+	MaxWinReached bool `json:"max_win_reached,omitempty"`
+
+	// 本次中奖可博倍时的博倍状态（收分后赢取才结算）
+	Gamble *GambleState `json:"gamble,omitempty"`
+
+	// 加注旋转（BetAmount为加注后的扣费，派彩按原下注额计算）
+	Ante bool `json:"ante,omitempty"`
+
+	// 期待效果：需要慢停的卷轴和差一点中奖的符号（没有时为空）
+	Anticipation *Anticipation `json:"anticipation,omitempty"`
+
+	// 免费游戏
+	IsFreeSpin bool           `json:"is_free_spin"`        // 本次是否为免费旋转
+	FreeGame   *FreeGameState `json:"free_game,omitempty"` // 本次旋转后的免费游戏状态（未触发过时为空）
+
+	// 选择奖励游戏（本次旋转触发时记录，交互模式下进行中的格子内容对客户端隐藏）
+	BonusGame *BonusGameState `json:"bonus_game,omitempty"`
+
+	// 重放数据（种子模式下记录）
+	Seed         int64   `json:"seed,omitempty"`         // 单次旋转种子
+	FavorWin     bool    `json:"favor_win"`              // RTP控制器判定结果
+	Compensation float64 `json:"compensation,omitempty"` // 赔付补偿倍率
 }
 
 // GetTotalPayout 获取总赔付（兼容性方法）
@@ -72,32 +104,59 @@ func (s *SpinResult) GetWinDescription() string {
 // ToJSON 转换为JSON map
 func (s *SpinResult) ToJSON() map[string]interface{} {
 	return map[string]interface{}{
-		"id":           s.ID,
-		"session_id":   s.SessionID,
-		"user_id":      s.UserID,
-		"bet_amount":   s.BetAmount,
-		"win_amount":   s.WinAmount,
-		"total_payout": s.GetTotalPayout(),
-		"multiplier":   s.Multiplier,
-		"reels":        s.Reels,
-		"win_lines":    s.WinLines,
-		"features":     s.Features,
-		"free_spins":   s.FreeSpins,
-		"is_jackpot":   s.IsJackpot,
-		"rtp":          s.RTP,
-		"timestamp":    s.Timestamp,
+		"id":              s.ID,
+		"session_id":      s.SessionID,
+		"user_id":         s.UserID,
+		"bet_amount":      s.BetAmount,
+		"win_amount":      s.WinAmount,
+		"total_payout":    s.GetTotalPayout(),
+		"multiplier":      s.Multiplier,
+		"reels":           s.Reels,
+		"win_lines":       s.WinLines,
+		"features":        s.Features,
+		"free_spins":      s.FreeSpins,
+		"is_jackpot":      s.IsJackpot,
+		"rtp":             s.RTP,
+		"seed":            s.Seed,
+		"reel_set_id":     s.ReelSetID,
+		"config_hash":     s.ConfigHash,
+		"ways":            s.Ways,
+		"max_win_reached": s.MaxWinReached,
+		"is_free_spin":    s.IsFreeSpin,
+		"free_game":       s.FreeGame,
+		"bonus_game":      s.BonusGame,
+		"gamble":          s.Gamble,
+		"ante":            s.Ante,
+		"anticipation":    s.Anticipation,
+		"timestamp":       s.Timestamp,
 	}
 }
 
 // WinLine 中奖线
 type WinLine struct {
-	LineID     int        `json:"line_id"`    // 线ID
-	LineType   LineType   `json:"line_type"`  // 线类型
-	Symbol     Symbol     `json:"symbol"`     // 中奖符号
-	Count      int        `json:"count"`      // 连续个数
-	Positions  []Position `json:"positions"`  // 位置
-	WinAmount  int64      `json:"win_amount"` // 中奖金额
-	Multiplier float64    `json:"multiplier"` // 倍率
+	LineID     int          `json:"line_id"`             // 线ID
+	LineType   LineType     `json:"line_type"`           // 线类型
+	Symbol     Symbol       `json:"symbol"`              // 中奖符号
+	Count      int          `json:"count"`               // 连续个数
+	Positions  []Position   `json:"positions"`           // 位置
+	WinAmount  int64        `json:"win_amount"`          // 中奖金额
+	Multiplier float64      `json:"multiplier"`          // 倍率
+	Direction  PayDirection `json:"direction,omitempty"` // 派彩方向
+	Ways       int          `json:"ways,omitempty"`      // 全路径模式下的中奖路数
+}
+
+// PayDirection 支付线派彩方向
+type PayDirection string
+
+const (
+	PayDirectionLeftToRight PayDirection = "ltr"  // 从左到右（默认）
+	PayDirectionRightToLeft PayDirection = "rtl"  // 从右到左
+	PayDirectionBoth        PayDirection = "both" // 双向（满线只计一次）
+)
+
+// IsValid 是否为有效方向（空值视为从左到右）
+func (d PayDirection) IsValid() bool {
+	return d == "" || d == PayDirectionLeftToRight || d == PayDirectionRightToLeft || d == PayDirectionBoth
 }
 
 // Position 符号位置
@@ -140,24 +199,95 @@ type ReelStrip struct {
 	Weights []int    `json:"weights"` // 权重
 }
 
+// ReelSet 经过认证的卷轴组
+type ReelSet struct {
+	ID             string      `json:"id"`              // 卷轴组ID
+	Name           string      `json:"name"`            // 名称
+	TheoreticalRTP float64     `json:"theoretical_rtp"` // 认证的理论RTP（为0时按卷轴条计算）
+	ReelStrips     []ReelStrip `json:"reel_strips"`     // 卷轴条配置
+}
+
 // SlotConfig 老虎机配置
 type SlotConfig struct {
-	MachineID      string          `json:"machine_id"`      // 机器ID
-	Name           string          `json:"name"`            // 名称
-	Rows           int             `json:"rows"`            // 行数
-	Reels          int             `json:"reels"`           // 卷轴数
-	PayLines       int             `json:"pay_lines"`       // 支付线数
-	MinBet         int64           `json:"min_bet"`         // 最小下注
-	MaxBet         int64           `json:"max_bet"`         // 最大下注
-	DefaultBet     int64           `json:"default_bet"`     // 默认下注
-	TargetRTP      float64         `json:"target_rtp"`      // 目标RTP
-	Volatility     Volatility      `json:"volatility"`      // 波动性
-	ReelStrips     []ReelStrip     `json:"reel_strips"`     // 卷轴条配置
-	PayTables      []PayTable      `json:"pay_tables"`      // 赔率表
-	WildSymbols    []Symbol        `json:"wild_symbols"`    // 百搭符号
-	ScatterSymbols []Symbol        `json:"scatter_symbols"` // 分散符号
-	BonusSymbols   []Symbol        `json:"bonus_symbols"`   // 奖励符号
-	Features       []FeatureConfig `json:"features"`        // 特殊功能配置
+	MachineID      string            `json:"machine_id"`            // 机器ID
+	Name           string            `json:"name"`                  // 名称
+	Rows           int               `json:"rows"`                  // 行数
+	Reels          int               `json:"reels"`                 // 卷轴数
+	ActivePaylines int               `json:"pay_lines"`             // 启用的支付线数（声明了支付线时须与声明的条数一致）
+	Paylines       [][]int           `json:"paylines"`              // 支付线定义（每条线为各卷轴的行索引，为空时使用内置线型）
+	PayDirection   PayDirection      `json:"pay_direction"`         // 派彩方向（缺省从左到右）
+	MinBet         int64             `json:"min_bet"`               // 最小下注
+	MaxBet         int64             `json:"max_bet"`               // 最大下注
+	DefaultBet     int64             `json:"default_bet"`           // 默认下注
+	TargetRTP      float64           `json:"target_rtp"`            // 目标RTP
+	Volatility     Volatility        `json:"volatility"`            // 波动性
+	ReelStrips     []ReelStrip       `json:"reel_strips"`           // 卷轴条配置（默认卷轴组）
+	ReelSets       []ReelSet         `json:"reel_sets"`             // 可选的认证卷轴组
+	PayTables      []PayTable        `json:"pay_tables"`            // 赔率表
+	WildSymbols    []Symbol          `json:"wild_symbols"`          // 百搭符号
+	ScatterSymbols []Symbol          `json:"scatter_symbols"`       // 分散符号
+	BonusSymbols   []Symbol          `json:"bonus_symbols"`         // 奖励符号
+	Features       []FeatureConfig   `json:"features"`              // 特殊功能配置
+	SeededRNG      bool              `json:"seeded_rng"`            // 种子模式（每次旋转记录种子，可重放）
+	RTPBand        *RTPBand          `json:"rtp_band"`              // 理论RTP允许范围（为空时不校验）
+	Mode           EngineMode        `json:"mode"`                  // 引擎模式（缺省为自适应模式）
+	WinMode        WinMode           `json:"win_mode"`              // 中奖判定方式（缺省为支付线）
+	ReelHeights    []ReelHeight      `json:"reel_heights"`          // 各卷轴可见行数（仅全路径模式，为空时均为Rows）
+	WaysBetUnits   int               `json:"ways_bet_units"`        // 全路径模式下总下注折算的单位数，每路赔付 = 下注/单位数 × 赔率（缺省为1）
+	MaxWin         *MaxWinConfig     `json:"max_win,omitempty"`     // 单局最高赢取（为空时不限）
+	Gamble         *GambleConfig     `json:"gamble,omitempty"`      // 中奖后博倍（为空时不提供）
+	Ante           *AnteConfig       `json:"ante,omitempty"`        // 加注模式（为空时不提供）
+	BuyFeature     *BuyFeatureConfig `json:"buy_feature,omitempty"` // 购买免费游戏（为空时不提供）
+	Jackpot        *JackpotConfig    `json:"jackpot,omitempty"`     // 彩金触发规则（为空时不派发彩金）
+}
+
+// WinMode 中奖判定方式
+type WinMode string
+
+const (
+	WinModePaylines WinMode = "paylines" // 支付线（默认）
+	WinModeWays     WinMode = "ways"     // 全路径（243/1024/117649等，相邻卷轴出现即连线）
+)
+
+// IsWays 是否为全路径模式
+func (m WinMode) IsWays() bool {
+	return m == WinModeWays
+}
+
+// IsValid 是否为有效方式（空值视为支付线）
+func (m WinMode) IsValid() bool {
+	return m == "" || m == WinModePaylines || m == WinModeWays
+}
+
+// ReelHeight 卷轴可见行数范围
+// Min与Max相同时为固定行数，不同时每次旋转在范围内均匀抽取（Megaways风格）
+type ReelHeight struct {
+	Min int `json:"min"` // 最少行数
+	Max int `json:"max"` // 最多行数
+}
+
+// EngineMode 引擎结果模式
+type EngineMode string
+
+const (
+	EngineModeAdaptive EngineMode = "adaptive"  // 自适应模式：RTP控制器根据历史干预结果（仅限娱乐场景）
+	EngineModePureMath EngineMode = "pure_math" // 纯数学模式：结果仅由卷轴条和随机数决定，可认证
+)
+
+// IsPureMath 是否为纯数学模式
+func (m EngineMode) IsPureMath() bool {
+	return m == EngineModePureMath
+}
+
+// IsValid 是否为有效模式（空值视为自适应模式）
+func (m EngineMode) IsValid() bool {
+	return m == "" || m == EngineModeAdaptive || m == EngineModePureMath
+}
+
+// RTPBand 理论RTP允许范围
+type RTPBand struct {
+	Min float64 `json:"min"` // 最小理论RTP
+	Max float64 `json:"max"` // 最大理论RTP
 }
 
 // FeatureConfig 特殊功能配置
@@ -167,6 +297,37 @@ type FeatureConfig struct {
 	TriggerCount   int         `json:"trigger_count"`   // 触发数量
 	Probability    float64     `json:"probability"`     // 触发概率
 	Value          interface{} `json:"value"`           // 功能值
+
+	FreeGame  *FreeGameConfig  `json:"free_game,omitempty"`  // 免费游戏配置（仅免费旋转功能）
+	BonusGame *BonusGameConfig `json:"bonus_game,omitempty"` // 选择奖励游戏配置（仅奖励游戏功能）
+}
+
+// freeGameFeature 获取免费旋转功能配置（未配置时为空）
+func (c *SlotConfig) freeGameFeature() *FeatureConfig {
+	for i := range c.Features {
+		if c.Features[i].Type == FeatureTypeFreeSpins {
+			return &c.Features[i]
+		}
+	}
+	return nil
+}
+
+// freeGameConfig 获取免费游戏配置（未配置时为空，按默认规则处理）
+func (c *SlotConfig) freeGameConfig() *FreeGameConfig {
+	if feature := c.freeGameFeature(); feature != nil {
+		return feature.FreeGame
+	}
+	return nil
+}
+
+// bonusGameConfig 获取选择奖励游戏配置（未配置时为空，按默认配置处理）
+func (c *SlotConfig) bonusGameConfig() *BonusGameConfig {
+	for i := range c.Features {
+		if c.Features[i].Type == FeatureTypeBonus {
+			return c.Features[i].BonusGame
+		}
+	}
+	return nil
 }
 
 // Volatility 波动性
@@ -203,6 +364,9 @@ type PatternMatcher interface {
 
 	// DetectFeatures 检测特殊功能
 	DetectFeatures(reels [][]Symbol, config *SlotConfig) []Feature
+
+	// DetectAnticipation 检测慢停卷轴和差一点中奖
+	DetectAnticipation(reels [][]Symbol, winLines []WinLine) *Anticipation
 }
 
 // RandomGenerator 随机数生成器接口
@@ -233,6 +397,27 @@ type Engine interface {
 
 	// Reset 重置引擎
 	Reset()
+
+	// PlayGamble 在进行中的博倍里竞猜一次
+	PlayGamble(sessionID string, mode GambleMode, guess GambleGuess) (*GambleState, GambleRound, error)
+
+	// CollectGamble 收分结束博倍
+	CollectGamble(sessionID string) (*GambleState, error)
+
+	// SpinWithAnte 执行旋转（ante 为真时按加注模式）
+	SpinWithAnte(userID uint, sessionID string, betAmount int64, ante bool) (*SpinResult, error)
+
+	// BuyFeature 购买免费游戏
+	BuyFeature(userID uint, sessionID string, betAmount int64) (*FeaturePurchase, error)
+	
+	// CanBuyFeature 检查能否购买免费游戏（不改变状态）
+	CanBuyFeature(sessionID string, betAmount int64) error
+	
+	// TheoreticalRTP 旋转所用卷轴组的理论RTP（RTP漂移检测用）
+	TheoreticalRTP(reelSetID string, ante bool) float64
+
+	// GetSession 获取会话数据（免费游戏进度等）
+	GetSession(sessionID string) *SessionData
 }
 
 // Statistics 统计数据
@@ -0,0 +1,209 @@
+package slot
+
+// maxWaysRows 全路径模式下单个卷轴的最大行数（6卷轴×7行 = 117649路）
+const maxWaysRows = 7
+
+// WaysPatternMatcher 全路径匹配器（243/1024/117649路）
+// 符号出现在从第一个卷轴开始的相邻卷轴上即构成连线，不依赖固定支付线，支持各卷轴行数不同；
+// 赔付 = 单位下注 × 赔率 × 路数，路数为各卷轴上该符号与Wild数量之积。
+// Wild可替代除Scatter/Bonus外的符号，连线中至少要有一个真实符号；Scatter按分散方式派彩，Bonus只触发奖励游戏
+type WaysPatternMatcher struct {
+	*AdvancedPatternMatcher
+}
+
+// NewWaysPatternMatcher 创建全路径匹配器
+func NewWaysPatternMatcher(config *SlotConfig) *WaysPatternMatcher {
+	return &WaysPatternMatcher{
+		AdvancedPatternMatcher: NewAdvancedPatternMatcher(config),
+	}
+}
+
+// NewPatternMatcher 根据配置的中奖判定方式创建匹配器
+func NewPatternMatcher(config *SlotConfig) PatternMatcher {
+	if config.WinMode.IsWays() {
+		return NewWaysPatternMatcher(config)
+	}
+	return NewAdvancedPatternMatcher(config)
+}
+
+// FindWinningLines 查找全路径中奖（每个符号最多一条）
+func (m *WaysPatternMatcher) FindWinningLines(reels [][]Symbol, config *SlotConfig) []WinLine {
+	var winLines []WinLine
+
+	for _, symbol := range m.candidateSymbols(reels) {
+		if win := m.evaluateSymbol(reels, symbol); win != nil {
+			win.LineID = len(winLines)
+			winLines = append(winLines, *win)
+		}
+	}
+
+	// 检查分散符号（整个盘面只计算一次）
+	if scatterWin := m.checkScatterSymbols(reels); scatterWin != nil {
+		winLines = append(winLines, *scatterWin)
+	}
+
+	return winLines
+}
+
+// CalculatePayout 计算赔付
+// 全路径中奖按单位下注 × 赔率 × 路数派彩，Scatter按总下注派彩
+func (m *WaysPatternMatcher) CalculatePayout(winLines []WinLine, betAmount int64) int64 {
+	var totalPayout int64
+	unitBet := float64(betAmount) / float64(m.betUnits())
+
+	for i := range winLines {
+		winLine := &winLines[i]
+		if winLine.Ways == 0 {
+			winLine.WinAmount = m.AdvancedPatternMatcher.CalculatePayout(winLines[i:i+1], betAmount)
+		} else {
+			multiplier := m.getMultiplier(winLine.Symbol, winLine.Count)
+			winLine.WinAmount = int64(unitBet * multiplier * float64(winLine.Ways))
+		}
+		totalPayout += winLine.WinAmount
+	}
+
+	return totalPayout
+}
+
+// DetectFeatures 检测特殊功能
+func (m *WaysPatternMatcher) DetectFeatures(reels [][]Symbol, config *SlotConfig) []Feature {
+	var features []Feature
+
+	if freeSpins := m.detectFreeSpins(reels); freeSpins != nil {
+		features = append(features, *freeSpins)
+	}
+	if bonus := m.detectBonusGame(reels); bonus != nil {
+		features = append(features, *bonus)
+	}
+	if expandWild := m.detectExpandingWild(reels); expandWild != nil {
+		features = append(features, *expandWild)
+	}
+
+	// 级联消除按全路径中奖判断
+	if winLines := m.FindWinningLines(reels, config); len(winLines) > 0 {
+		features = append(features, Feature{
+			Type:        FeatureTypeCascade,
+			TriggerPos:  winLines[0].Positions,
+			Value:       len(winLines),
+			Description: "触发级联消除",
+		})
+	}
+
+	return features
+}
+
+// evaluateSymbol 计算指定符号从第一个卷轴起的全路径中奖
+func (m *WaysPatternMatcher) evaluateSymbol(reels [][]Symbol, symbol Symbol) *WinLine {
+	ways := 1
+	count := 0
+	hasSymbol := false
+	var positions []Position
+
+	for reel, column := range reels {
+		matched := 0
+		for row, s := range column {
+			if s == symbol || m.isWild(s) {
+				matched++
+				positions = append(positions, Position{Reel: reel, Row: row})
+				if s == symbol {
+					hasSymbol = true
+				}
+			}
+		}
+		if matched == 0 {
+			break // 相邻卷轴中断
+		}
+		ways *= matched
+		count++
+	}
+
+	if count < 3 || !hasSymbol {
+		return nil
+	}
+
+	return &WinLine{
+		LineType:   LineTypeWays,
+		Symbol:     symbol,
+		Count:      count,
+		Positions:  positions,
+		Multiplier: 1.0,
+		Direction:  PayDirectionLeftToRight,
+		Ways:       ways,
+	}
+}
+
+// candidateSymbols 盘面上可按全路径派彩的符号（按出现顺序去重）
+func (m *WaysPatternMatcher) candidateSymbols(reels [][]Symbol) []Symbol {
+	var symbols []Symbol
+	seen := make(map[Symbol]bool)
+	for _, column := range reels {
+		for _, s := range column {
+			if seen[s] || !m.paysWays(s) {
+				continue
+			}
+			seen[s] = true
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols
+}
+
+// paysWays 符号是否按全路径派彩
+func (m *AdvancedPatternMatcher) paysWays(symbol Symbol) bool {
+	return symbol != "" && !m.isWild(symbol) && !m.isScatter(symbol) && !m.isBonus(symbol)
+}
+
+// betUnits 总下注折算的单位数
+func (m *AdvancedPatternMatcher) betUnits() int {
+	if m.config.WaysBetUnits > 0 {
+		return m.config.WaysBetUnits
+	}
+	return 1
+}
+
+// validateWays 验证全路径相关配置
+func validateWays(config *SlotConfig) error {
+	if !config.WinMode.IsValid() || config.WaysBetUnits < 0 {
+		return ErrInvalidConfig
+	}
+	if !config.WinMode.IsWays() {
+		if len(config.ReelHeights) > 0 {
+			return ErrInvalidConfig // 支付线模式要求固定行数
+		}
+		return nil
+	}
+
+	// 全路径只按从左到右计算
+	if config.PayDirection != "" && config.PayDirection != PayDirectionLeftToRight {
+		return ErrInvalidConfig
+	}
+	if len(config.ReelHeights) == 0 {
+		return nil
+	}
+	if len(config.ReelHeights) != config.Reels {
+		return ErrInvalidConfig
+	}
+	for _, height := range config.ReelHeights {
+		if height.Min < 1 || height.Min > height.Max || height.Max > config.Rows {
+			return ErrInvalidConfig
+		}
+	}
+	return nil
+}
+
+// reelHeightDistribution 卷轴可见行数的概率分布（下标为行数）
+func reelHeightDistribution(config *SlotConfig, reel int) []float64 {
+	if reel >= len(config.ReelHeights) {
+		dist := make([]float64, config.Rows+1)
+		dist[config.Rows] = 1
+		return dist
+	}
+
+	height := config.ReelHeights[reel]
+	dist := make([]float64, height.Max+1)
+	p := 1 / float64(height.Max-height.Min+1)
+	for rows := height.Min; rows <= height.Max; rows++ {
+		dist[rows] = p
+	}
+	return dist
+}
@@ -0,0 +1,237 @@
+package slot
+
+import (
+	"math"
+	"testing"
+)
+
+// waysTestConfig 基于默认配置构造全路径配置
+func waysTestConfig(rows, reels int) *SlotConfig {
+	config := GetDefaultConfig()
+	config.WinMode = WinModeWays
+	config.WaysBetUnits = 50
+	config.Rows = rows
+	for len(config.ReelStrips) < reels {
+		strip := config.ReelStrips[len(config.ReelStrips)%5]
+		strip.ReelID = len(config.ReelStrips)
+		config.ReelStrips = append(config.ReelStrips, strip)
+	}
+	config.Reels = reels
+	return config
+}
+
+func TestWaysPatternMatcher_FindWinningLines(t *testing.T) {
+	config := waysTestConfig(3, 5)
+	matcher := NewWaysPatternMatcher(config)
+
+	reels := [][]Symbol{
+		{SymbolCherry, SymbolLemon, SymbolCherry},
+		{SymbolWild, SymbolCherry, SymbolPlum},
+		{SymbolGrape, SymbolCherry, SymbolOrange},
+		{SymbolBar, SymbolSeven, SymbolPlum},
+		{SymbolCherry, SymbolCherry, SymbolCherry},
+	}
+
+	wins := matcher.FindWinningLines(reels, config)
+	if len(wins) != 1 {
+		t.Fatalf("FindWinningLines = %+v, want 1 win", wins)
+	}
+	win := wins[0]
+	// 2 × 2 × 1 = 4路，第4卷轴中断
+	if win.Symbol != SymbolCherry || win.Count != 3 || win.Ways != 4 {
+		t.Errorf("win = %v×%d ways=%d, want CHERRY×3 ways=4", win.Symbol, win.Count, win.Ways)
+	}
+	if len(win.Positions) != 5 {
+		t.Errorf("Positions = %d, want 5", len(win.Positions))
+	}
+
+	// 赔付 = 下注/单位数 × 赔率 × 路数
+	payout := matcher.CalculatePayout(wins, 1000)
+	want := int64(1000 / 50 * matcher.getMultiplier(SymbolCherry, 3) * 4)
+	if payout != want || wins[0].WinAmount != want {
+		t.Errorf("CalculatePayout = %d (line %d), want %d", payout, wins[0].WinAmount, want)
+	}
+}
+
+func TestWaysPatternMatcher_WildAndSpecialSymbols(t *testing.T) {
+	config := waysTestConfig(3, 5)
+	matcher := NewWaysPatternMatcher(config)
+
+	tests := []struct {
+		name  string
+		reels [][]Symbol
+		want  int
+	}{
+		{
+			name:  "纯Wild不构成连线",
+			reels: [][]Symbol{{SymbolWild}, {SymbolWild}, {SymbolWild}, {SymbolBonus}, {SymbolScatter}},
+			want:  0,
+		},
+		{
+			name:  "Wild不替代Bonus",
+			reels: [][]Symbol{{SymbolBonus}, {SymbolWild}, {SymbolBonus}, {SymbolOrange}, {SymbolBar}},
+			want:  0,
+		},
+		{
+			name:  "Wild开头由后续符号决定",
+			reels: [][]Symbol{{SymbolWild}, {SymbolSeven}, {SymbolSeven}, {SymbolOrange}, {SymbolBar}},
+			want:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wins := matcher.FindWinningLines(tt.reels, config)
+			if len(wins) != tt.want {
+				t.Errorf("FindWinningLines = %+v, want %d wins", wins, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotEngine_WinModeSelectsMatcher(t *testing.T) {
+	engine, err := NewSlotEngine(GetDefaultConfig())
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	if _, ok := engine.patternMatcher.(*AdvancedPatternMatcher); !ok {
+		t.Errorf("default engine should use paylines, got %T", engine.patternMatcher)
+	}
+
+	engine, err = NewSlotEngine(waysTestConfig(4, 5))
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+	if _, ok := engine.patternMatcher.(*WaysPatternMatcher); !ok {
+		t.Errorf("ways engine should use WaysPatternMatcher, got %T", engine.patternMatcher)
+	}
+
+	result, err := engine.Spin(1, "ways", 100)
+	if err != nil {
+		t.Fatalf("Spin failed: %v", err)
+	}
+	if result.Ways != 1024 {
+		t.Errorf("Ways = %d, want 1024", result.Ways)
+	}
+}
+
+func TestSlotEngine_Megaways(t *testing.T) {
+	config := waysTestConfig(7, 6)
+	config.Mode = EngineModePureMath
+	for i := 0; i < config.Reels; i++ {
+		config.ReelHeights = append(config.ReelHeights, ReelHeight{Min: 2, Max: 7})
+	}
+	engine, err := NewSlotEngine(config)
+	if err != nil {
+		t.Fatalf("NewSlotEngine failed: %v", err)
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		result, err := engine.Spin(1, "megaways", 100)
+		if err != nil {
+			t.Fatalf("Spin failed: %v", err)
+		}
+		ways := 1
+		for _, column := range result.Reels {
+			if len(column) < 2 || len(column) > 7 {
+				t.Fatalf("reel height %d out of [2, 7]", len(column))
+			}
+			seen[len(column)] = true
+			ways *= len(column)
+		}
+		if result.Ways != ways || ways > 117649 {
+			t.Fatalf("Ways = %d, want %d (<= 117649)", result.Ways, ways)
+		}
+	}
+	if len(seen) < 6 {
+		t.Errorf("reel heights should vary, saw %v", seen)
+	}
+}
+
+func TestCalculateTheoreticalRTP_WaysMatchesMonteCarlo(t *testing.T) {
+	fixed := waysTestConfig(4, 5)
+	megaways := waysTestConfig(7, 6)
+	megaways.WaysBetUnits = 2000
+	for i := 0; i < megaways.Reels; i++ {
+		megaways.ReelHeights = append(megaways.ReelHeights, ReelHeight{Min: 2, Max: 7})
+	}
+
+	for _, config := range []*SlotConfig{fixed, megaways} {
+		theory, err := CalculateTheoreticalRTP(config)
+		if err != nil {
+			t.Fatalf("CalculateTheoreticalRTP failed: %v", err)
+		}
+		if theory.Method != RTPMethodWays || len(theory.Paylines) != 0 {
+			t.Errorf("Method = %v, paylines = %d, want ways/0", theory.Method, len(theory.Paylines))
+		}
+
+		engine, err := NewSlotEngine(config)
+		if err != nil {
+			t.Fatalf("NewSlotEngine failed: %v", err)
+		}
+		matcher := NewWaysPatternMatcher(config)
+		rng := NewDRBGRandomGenerator(7)
+
+		const spins = 200000
+		var sum, sumSq float64
+		for i := 0; i < spins; i++ {
+			reels := engine.generateReels(rng, config.ReelStrips, false)
+			pay := 0.0
+			for _, win := range matcher.FindWinningLines(reels, config) {
+				if win.Ways > 0 {
+					pay += matcher.getMultiplier(win.Symbol, win.Count) * float64(win.Ways) / float64(config.WaysBetUnits)
+				}
+			}
+			sum += pay
+			sumSq += pay * pay
+		}
+
+		mean := sum / spins
+		stdErr := math.Sqrt((sumSq/spins - mean*mean) / spins)
+		if math.Abs(mean-theory.LineRTP) > 5*stdErr {
+			t.Errorf("%d rows: simulated ways RTP %.4f, theoretical %.4f (stderr %.4f)",
+				config.Rows, mean, theory.LineRTP, stdErr)
+		}
+	}
+}
+
+func TestValidateConfig_Ways(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(c *SlotConfig)
+		expected error
+	}{
+		{"243路", func(c *SlotConfig) {}, nil},
+		{"支付线模式不支持7行", func(c *SlotConfig) {
+			c.WinMode = WinModePaylines
+			c.Rows = 7
+		}, ErrInvalidConfig},
+		{"全路径支持7行", func(c *SlotConfig) { c.Rows = 7 }, nil},
+		{"无效判定方式", func(c *SlotConfig) { c.WinMode = "cluster" }, ErrInvalidConfig},
+		{"全路径不支持从右到左", func(c *SlotConfig) { c.PayDirection = PayDirectionRightToLeft }, ErrInvalidConfig},
+		{"支付线模式不支持可变行数", func(c *SlotConfig) {
+			c.WinMode = ""
+			c.ReelHeights = []ReelHeight{{3, 3}, {3, 3}, {3, 3}, {3, 3}, {3, 3}}
+		}, ErrInvalidConfig},
+		{"行数范围超出Rows", func(c *SlotConfig) {
+			c.ReelHeights = []ReelHeight{{2, 4}, {3, 3}, {3, 3}, {3, 3}, {3, 3}}
+		}, ErrInvalidConfig},
+		{"行数范围数量不符", func(c *SlotConfig) {
+			c.ReelHeights = []ReelHeight{{2, 3}}
+		}, ErrInvalidConfig},
+		{"有效可变行数", func(c *SlotConfig) {
+			c.ReelHeights = []ReelHeight{{2, 3}, {3, 3}, {1, 3}, {3, 3}, {2, 2}}
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := waysTestConfig(3, 5)
+			tt.modify(config)
+			if err := ValidateConfig(config); err != tt.expected {
+				t.Errorf("ValidateConfig() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/repository"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SlotMachineRegistry 老虎机配置注册表
+// 从 slot_machines 表加载配置并校验，配置变化时原子替换对应引擎的配置，
+// 每个生效过的配置按哈希保存到 slot_config_versions，旋转结果通过哈希引用所用的数学模型
+type SlotMachineRegistry struct {
+	mu          sync.RWMutex
+	engines     map[string]*slot.SlotEngine
+	machineRepo repository.SlotMachineRepository
+	versionRepo repository.SlotConfigVersionRepository
+	logger      *zap.Logger
+}
+
+// NewSlotMachineRegistry 创建老虎机配置注册表
+func NewSlotMachineRegistry(db *gorm.DB, logger *zap.Logger) *SlotMachineRegistry {
+	return &SlotMachineRegistry{
+		engines:     make(map[string]*slot.SlotEngine),
+		machineRepo: repository.NewSlotMachineRepository(db),
+		versionRepo: repository.NewSlotConfigVersionRepository(db),
+		logger:      logger,
+	}
+}
+
+// Register 登记使用内置配置创建的引擎（数据库中有同一机器ID的配置时以数据库为准）
+func (r *SlotMachineRegistry) Register(engine *slot.SlotEngine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.engines[engine.GetConfig().MachineID] = engine
+}
+
+// RegisterBuiltin 登记内置机台（经典水果机、幸运777、法老王）的引擎
+func (r *SlotMachineRegistry) RegisterBuiltin() {
+	for _, config := range []*slot.SlotConfig{slot.DefaultConfig(), slot.GetLuckySevenConfig(), slot.GetPharaohConfig()} {
+		engine, err := slot.NewSlotEngine(config)
+		if err != nil {
+			r.logger.Error("创建内置老虎机引擎失败",
+				zap.String("machine_id", config.MachineID),
+				zap.Error(err))
+			continue
+		}
+		r.Register(engine)
+	}
+}
+
+// Engine 获取机器对应的引擎
+func (r *SlotMachineRegistry) Engine(machineID string) (*slot.SlotEngine, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	engine, ok := r.engines[machineID]
+	return engine, ok
+}
+
+// Reload 重新加载全部活跃机器的配置
+// 单台机器的配置无效时保留其当前配置并记录日志，不影响其他机器
+func (r *SlotMachineRegistry) Reload(ctx context.Context) error {
+	machines, err := r.machineRepo.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("加载老虎机配置失败: %w", err)
+	}
+
+	for _, machine := range machines {
+		changed, err := r.ReloadMachine(ctx, machine)
+		if err != nil {
+			r.logger.Warn("老虎机配置无效，继续使用当前配置",
+				zap.String("machine_id", machine.MachineID),
+				zap.Error(err))
+			continue
+		}
+		if changed {
+			r.logger.Info("老虎机配置已更新",
+				zap.String("machine_id", machine.MachineID),
+				zap.String("config_hash", r.configHash(machine.MachineID)))
+		}
+	}
+	return nil
+}
+
+// ReloadMachine 加载单台机器的配置，返回引擎配置是否发生变化
+func (r *SlotMachineRegistry) ReloadMachine(ctx context.Context, machine *models.SlotMachine) (bool, error) {
+	config, err := SlotConfigFromMachine(machine)
+	if err != nil {
+		return false, err
+	}
+	if err := slot.ValidateConfig(config); err != nil {
+		return false, err
+	}
+
+	data, err := slot.MarshalConfig(config)
+	if err != nil {
+		return false, err
+	}
+	hash, err := slot.ConfigHash(config)
+	if err != nil {
+		return false, err
+	}
+	if hash == r.configHash(config.MachineID) {
+		return false, nil
+	}
+
+	// 先保存版本再切换，保证结果引用的哈希都能查到配置
+	version := &models.SlotConfigVersion{
+		MachineID: config.MachineID,
+		Hash:      hash,
+		Config:    string(data),
+	}
+	if err := r.versionRepo.Save(ctx, version); err != nil {
+		return false, fmt.Errorf("保存配置版本失败: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if engine, ok := r.engines[config.MachineID]; ok {
+		if err := engine.ReloadConfig(config); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	engine, err := slot.NewSlotEngine(config)
+	if err != nil {
+		return false, err
+	}
+	r.engines[config.MachineID] = engine
+	return true, nil
+}
+
+// StartReloadTask 启动配置热加载任务（立即加载一次，之后按间隔轮询）
+func (r *SlotMachineRegistry) StartReloadTask(ctx context.Context, interval time.Duration) {
+	go func() {
+		if err := r.Reload(ctx); err != nil {
+			r.logger.Warn("加载老虎机配置失败", zap.Error(err))
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				r.logger.Info("停止老虎机配置热加载任务")
+				return
+			case <-ticker.C:
+				if err := r.Reload(ctx); err != nil {
+					r.logger.Warn("加载老虎机配置失败", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// configHash 机器当前生效配置的哈希
+func (r *SlotMachineRegistry) configHash(machineID string) string {
+	engine, ok := r.Engine(machineID)
+	if !ok {
+		return ""
+	}
+	return engine.ConfigHash()
+}
+
+// SlotConfigFromMachine 由数据库记录生成老虎机配置
+// 以同ID的内置预设为基础（没有预设时从空配置开始），先应用 reels/rows/paylines 列，
+// 再依次叠加 symbols、pay_table、bonus_config 三个JSON列（键与 SlotConfig 的JSON字段一致，
+// 如 reel_strips、pay_tables、features），JSON列中的同名字段优先
+func SlotConfigFromMachine(machine *models.SlotMachine) (*slot.SlotConfig, error) {
+	config := &slot.SlotConfig{}
+	if preset, ok := slot.ConfigPresets[machine.MachineID]; ok {
+		clone, err := slot.CloneConfig(preset)
+		if err != nil {
+			return nil, err
+		}
+		config = clone
+	}
+
+	config.MachineID = machine.MachineID
+	if machine.Name != "" {
+		config.Name = machine.Name
+	}
+	if machine.Reels > 0 {
+		config.Reels = machine.Reels
+	}
+	if machine.Rows > 0 {
+		config.Rows = machine.Rows
+	}
+	if machine.Paylines > 0 {
+		config.ActivePaylines = machine.Paylines
+	}
+
+	for _, column := range []models.JSONMap{machine.Symbols, machine.PayTable, machine.BonusConfig} {
+		if len(column) == 0 {
+			continue
+		}
+		data, err := json.Marshal(column)
+		if err != nil {
+			return nil, fmt.Errorf("解析老虎机配置失败: %w", err)
+		}
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("解析老虎机配置失败: %w", err)
+		}
+	}
+
+	// 机器ID以记录为准
+	config.MachineID = machine.MachineID
+	return config, nil
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestSlotConfigFromMachine(t *testing.T) {
+	machine := &models.SlotMachine{
+		MachineID: "classic_fruit",
+		Name:      "数据库水果机",
+		Reels:     5,
+		Rows:      3,
+		Paylines:  10,
+		PayTable:  models.JSONMap{"target_rtp": 0.94, "max_bet": 2000},
+		BonusConfig: models.JSONMap{
+			"features": []interface{}{
+				map[string]interface{}{"type": "FREE_SPINS", "trigger_count": 3, "value": 8, "probability": 0.02},
+			},
+		},
+	}
+
+	config, err := SlotConfigFromMachine(machine)
+	require.NoError(t, err)
+	assert.Equal(t, "数据库水果机", config.Name)
+	assert.Equal(t, 10, config.ActivePaylines)
+	assert.Equal(t, 0.94, config.TargetRTP)
+	assert.Equal(t, int64(2000), config.MaxBet)
+	require.Len(t, config.Features, 1)
+	assert.Equal(t, slot.FeatureTypeFreeSpins, config.Features[0].Type)
+	// 未在记录中覆盖的字段沿用内置预设
+	assert.Len(t, config.ReelStrips, 5)
+	assert.NoError(t, slot.ValidateConfig(config))
+
+	// 预设本身不受影响
+	assert.Equal(t, 0.96, slot.GetConfigByID("classic_fruit").TargetRTP)
+}
+
+func TestSlotMachineRegistry_Reload(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Game{}, &models.SlotMachine{}, &models.SlotConfigVersion{}))
+	ctx := context.Background()
+
+	engine, err := slot.NewSlotEngine(slot.DefaultConfig())
+	require.NoError(t, err)
+	registry := NewSlotMachineRegistry(db, zap.NewNop())
+	registry.Register(engine)
+	builtinHash := engine.ConfigHash()
+
+	game := &models.Game{Name: "水果机", Type: "slot", Status: "active"}
+	require.NoError(t, db.Create(game).Error)
+	machine := &models.SlotMachine{
+		GameID:    game.ID,
+		MachineID: "classic_fruit",
+		Reels:     5,
+		Rows:      3,
+		Paylines:  20,
+		PayTable:  models.JSONMap{"target_rtp": 0.95},
+		Status:    "active",
+	}
+	require.NoError(t, db.Create(machine).Error)
+
+	// 数据库配置替换内置配置，并保存版本
+	require.NoError(t, registry.Reload(ctx))
+	firstHash := engine.ConfigHash()
+	assert.NotEqual(t, builtinHash, firstHash)
+	assert.Equal(t, 0.95, engine.GetConfig().TargetRTP)
+
+	var version models.SlotConfigVersion
+	require.NoError(t, db.Where("hash = ?", firstHash).First(&version).Error)
+	stored, err := slot.ParseConfig([]byte(version.Config))
+	require.NoError(t, err)
+	storedHash, err := slot.ConfigHash(stored)
+	require.NoError(t, err)
+	assert.Equal(t, firstHash, storedHash, "stored config should reproduce its hash")
+
+	// 未变化时不重复保存
+	require.NoError(t, registry.Reload(ctx))
+	var count int64
+	db.Model(&models.SlotConfigVersion{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// 无效配置被拒绝，引擎保留当前配置
+	machine.PayTable = models.JSONMap{"target_rtp": 1.5}
+	require.NoError(t, db.Save(machine).Error)
+	require.NoError(t, registry.Reload(ctx))
+	assert.Equal(t, firstHash, engine.ConfigHash())
+
+	// 行更新后热加载，同一引擎切换到新配置
+	machine.PayTable = models.JSONMap{"target_rtp": 0.93}
+	require.NoError(t, db.Save(machine).Error)
+	require.NoError(t, registry.Reload(ctx))
+	assert.Equal(t, 0.93, engine.GetConfig().TargetRTP)
+	result, err := engine.Spin(1, "registry", 100)
+	require.NoError(t, err)
+	assert.Equal(t, engine.ConfigHash(), result.ConfigHash)
+
+	db.Model(&models.SlotConfigVersion{}).Where("machine_id = ?", "classic_fruit").Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	// 没有内置引擎的机器按记录创建新引擎
+	other := &models.SlotMachine{
+		GameID:    game.ID + 1,
+		MachineID: "lucky_seven",
+		Reels:     5,
+		Rows:      3,
+		Paylines:  20,
+		Status:    "active",
+	}
+	require.NoError(t, db.Create(other).Error)
+	require.NoError(t, registry.Reload(ctx))
+	lucky, ok := registry.Engine("lucky_seven")
+	require.True(t, ok)
+	assert.Equal(t, "lucky_seven", lucky.GetConfig().MachineID)
+}
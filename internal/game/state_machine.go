@@ -19,6 +19,7 @@ const (
 	StateSpinning   GameState = "spinning"    // 转动中
 	StateCalculating GameState = "calculating" // 计算中奖
 	StateWinning    GameState = "winning"     // 中奖展示
+	StateGambling   GameState = "gambling"    // 博倍中（收分前赢取不结算）
 	StateSettlement GameState = "settlement"  // 结算状态
 	StateError      GameState = "error"       // 错误状态
 )
@@ -189,6 +190,57 @@ func (sm *StateMachine) initTransitions() {
 		},
 	})
 	
+	// 中奖展示 -> 博倍
+	sm.addTransition(StateTransition{
+		From:  StateWinning,
+		Event: "gamble",
+		To:    StateGambling,
+		Action: func(ctx context.Context, sm *StateMachine) error {
+			sm.logger.Info("开始博倍", 
+				zap.String("session_id", sm.sessionID),
+				zap.Int64("win_amount", sm.winAmount))
+			return nil
+		},
+	})
+	
+	// 博倍 -> 博倍（猜中后继续竞猜）
+	sm.addTransition(StateTransition{
+		From:  StateGambling,
+		Event: "gamble",
+		To:    StateGambling,
+		Action: func(ctx context.Context, sm *StateMachine) error {
+			sm.logger.Info("继续博倍", 
+				zap.String("session_id", sm.sessionID),
+				zap.Int64("win_amount", sm.winAmount))
+			return nil
+		},
+	})
+	
+	// 博倍 -> 结算（猜错，赢取清零）
+	sm.addTransition(StateTransition{
+		From:  StateGambling,
+		Event: "gamble_lose",
+		To:    StateSettlement,
+		Action: func(ctx context.Context, sm *StateMachine) error {
+			sm.logger.Info("博倍失败", zap.String("session_id", sm.sessionID))
+			sm.winAmount = 0
+			return nil
+		},
+	})
+	
+	// 博倍 -> 结算（收分）
+	sm.addTransition(StateTransition{
+		From:  StateGambling,
+		Event: "collect",
+		To:    StateSettlement,
+		Action: func(ctx context.Context, sm *StateMachine) error {
+			sm.logger.Info("博倍收分", 
+				zap.String("session_id", sm.sessionID),
+				zap.Int64("win_amount", sm.winAmount))
+			return nil
+		},
+	})
+	
 	// 中奖展示 -> 结算
 	sm.addTransition(StateTransition{
 		From:  StateWinning,
@@ -224,7 +276,7 @@ func (sm *StateMachine) initTransitions() {
 	})
 	
 	// 任何状态 -> 错误状态
-	for _, state := range []GameState{StateIdle, StateReady, StateSpinning, StateCalculating, StateWinning, StateSettlement} {
+	for _, state := range []GameState{StateIdle, StateReady, StateSpinning, StateCalculating, StateWinning, StateGambling, StateSettlement} {
 		sm.addTransition(StateTransition{
 			From:  state,
 			Event: "error",
@@ -46,6 +46,7 @@ type UserGameStats struct {
 type GameStartRequest struct {
 	UserID    uint  `json:"user_id" binding:"required"`
 	BetAmount int64 `json:"bet_amount" binding:"required,min=1"`
+	Ante      bool  `json:"ante"` // 加注（按机台加注比例扣费）
 }
 
 // GameSpinRequest 转动请求
@@ -58,6 +59,39 @@ type GameSettleRequest struct {
 	SessionID string `json:"session_id" binding:"required"`
 }
 
+// GameGambleRequest 博倍请求
+type GameGambleRequest struct {
+	SessionID string           `json:"session_id" binding:"required"`
+	Mode      slot.GambleMode  `json:"mode" binding:"required"`  // 玩法：color猜红黑，high_low猜大小
+	Guess     slot.GambleGuess `json:"guess" binding:"required"` // 竞猜：red/black 或 high/low
+}
+
+// GambleResponse 博倍响应
+type GambleResponse struct {
+	SessionID string            `json:"session_id"`
+	Round     *slot.GambleRound `json:"round"`      // 本次竞猜
+	Gamble    *slot.GambleState `json:"gamble"`     // 博倍状态（Stake为当前可收取金额）
+	CanDouble bool              `json:"can_double"` // 是否还能继续竞猜
+	State     string            `json:"state"`
+	TotalWin  int64             `json:"total_win"`
+}
+
+// GameBuyFeatureRequest 购买免费游戏请求
+type GameBuyFeatureRequest struct {
+	UserID    uint   `json:"user_id" binding:"required"`
+	SessionID string `json:"session_id" binding:"required"`
+	BetAmount int64  `json:"bet_amount" binding:"required,min=1"` // 免费旋转按该金额派彩
+}
+
+// BuyFeatureResponse 购买免费游戏响应
+type BuyFeatureResponse struct {
+	SessionID string                `json:"session_id"`
+	Purchase  *slot.FeaturePurchase `json:"purchase"` // 购买结果（含扣费和免费游戏状态）
+	Balance   int64                 `json:"balance"`
+	State     string                `json:"state"`
+	TotalBet  int64                 `json:"total_bet"`
+}
+
 // GameHistoryRequest 游戏历史请求
 type GameHistoryRequest struct {
 	UserID uint `json:"user_id" binding:"required"`
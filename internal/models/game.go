@@ -102,6 +102,38 @@ type SlotMachine struct {
 	Game        Game      `gorm:"foreignKey:GameID" json:"game,omitempty"`
 }
 
+// SlotConfigVersion 老虎机配置版本表（每个生效过的数学配置按哈希保存一份）
+type SlotConfigVersion struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	MachineID   string    `gorm:"index;size:50;not null" json:"machine_id"`
+	Hash        string    `gorm:"uniqueIndex;size:64;not null" json:"hash"`
+	Config      string    `gorm:"type:text;not null" json:"config"` // 完整配置JSON（哈希的计算内容）
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SlotStatBucket 老虎机旋转统计（按机台、日期、下注档位汇总，彩金单独统计不计入RTP）
+type SlotStatBucket struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	MachineID       string    `gorm:"size:50;not null;uniqueIndex:idx_slot_stat_bucket" json:"machine_id"`
+	Day             string    `gorm:"size:10;not null;uniqueIndex:idx_slot_stat_bucket;index" json:"day"` // 日期（2006-01-02）
+	BetLevel        int64     `gorm:"not null;uniqueIndex:idx_slot_stat_bucket" json:"bet_level"`         // 下注档位（名义下注）
+	Spins           int64     `gorm:"default:0" json:"spins"`                                             // 旋转次数（含免费旋转）
+	PaidSpins       int64     `gorm:"default:0" json:"paid_spins"`                                        // 付费旋转次数
+	TotalBet        int64     `gorm:"default:0" json:"total_bet"`
+	TotalWin        int64     `gorm:"default:0" json:"total_win"`
+	ExpectedWin     float64   `gorm:"default:0" json:"expected_win"`     // 理论赢取合计 Σ(下注 × 理论RTP)
+	SumReturn       float64   `gorm:"default:0" json:"sum_return"`       // Σ(赢取/档位下注)
+	SumReturnSq     float64   `gorm:"default:0" json:"sum_return_sq"`    // Σ(赢取/档位下注)²
+	HitCount        int64     `gorm:"default:0" json:"hit_count"`        // 中奖次数
+	FreeSpins       int64     `gorm:"default:0" json:"free_spins"`       // 免费旋转次数
+	FeatureTriggers int64     `gorm:"default:0" json:"feature_triggers"` // 特殊功能触发次数（免费游戏、奖励游戏）
+	JackpotHits     int64     `gorm:"default:0" json:"jackpot_hits"`
+	JackpotWin      int64     `gorm:"default:0" json:"jackpot_win"`
+	MaxWin          int64     `gorm:"default:0" json:"max_win"` // 单次最高赢取
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
 // SlotSpin 老虎机旋转记录表
 type SlotSpin struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
@@ -113,6 +145,9 @@ type SlotSpin struct {
 	BonusWon    bool      `gorm:"default:false" json:"bonus_won"`
 	FreeSpins   int       `gorm:"default:0" json:"free_spins"`
 	Multiplier  float64   `gorm:"default:1" json:"multiplier"`
+	ReelSetID   string    `gorm:"size:50;index" json:"reel_set_id"` // 使用的认证卷轴组
+	Seed        int64     `gorm:"default:0" json:"seed"`             // 单次旋转种子（种子模式下可重放）
+	ConfigHash  string    `gorm:"size:64;index" json:"config_hash"`  // 使用的配置版本哈希（对应 slot_config_versions）
 	CreatedAt   time.Time `json:"created_at"`
 	
 	// 关联
@@ -201,6 +236,11 @@ type Jackpot struct {
 	TotalOut    int64     `gorm:"default:0" json:"total_out"`   // 累计流出金额
 	Status      string    `gorm:"size:20;default:'active'" json:"status"` // active, locked, disabled
 	
+	// 必中模式：注入种子金额时在最小和最大金额之间抽取隐藏必中点，只公开承诺哈希
+	MustHitBy   bool      `gorm:"default:false" json:"must_hit_by"`     // 是否为必中奖池
+	HitHash     string    `gorm:"size:64" json:"hit_hash,omitempty"`    // 当前必中点的承诺哈希
+	HitSeed     string    `gorm:"size:128" json:"-"`                    // 当前必中点的种子（服务端密钥加密，派奖后随中奖历史公开明文）
+	
 	// 关联
 	Game        Game      `gorm:"foreignKey:GameID" json:"game,omitempty"`
 }
@@ -215,6 +255,11 @@ type JackpotHistory struct {
 	Amount      int64     `gorm:"not null" json:"amount"`
 	PoolBefore  int64     `json:"pool_before"`  // 中奖前奖池金额
 	PoolAfter   int64     `json:"pool_after"`   // 中奖后奖池金额
+	MinAmount   int64     `json:"min_amount"`   // 抽取必中点时的最小金额
+	MaxAmount   int64     `json:"max_amount"`   // 抽取必中点时的最大金额
+	HitPoint    int64     `json:"hit_point,omitempty"`               // 公开的必中点（必中奖池）
+	HitSeed     string    `gorm:"size:64" json:"hit_seed,omitempty"` // 公开的必中点种子
+	HitHash     string    `gorm:"size:64" json:"hit_hash,omitempty"` // 派奖前公布的承诺哈希
 	WonAt       time.Time `json:"won_at"`
 	
 	// 关联
@@ -223,3 +268,32 @@ type JackpotHistory struct {
 	Result      GameResult  `gorm:"foreignKey:ResultID" json:"result,omitempty"`
 }
 
+
+// ActivityRecord 动物活动场记录（每场活动结算时保存）
+type ActivityRecord struct {
+	BaseModel
+	ActivityID  uint32    `gorm:"not null;index" json:"activity_id"`
+	AgentID     uint32    `gorm:"index" json:"agent_id"`
+	SendGold    int64     `json:"send_gold"`                      // 每位玩家的活动金豆
+	PlayerCount int       `json:"player_count"`                   // 参与人数
+	TotalReward int64     `gorm:"default:0" json:"total_reward"`  // 已发放的排名奖励合计
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `gorm:"index" json:"ended_at"`
+	
+	// 关联
+	Rankings    []ActivityRanking `gorm:"foreignKey:RecordID" json:"rankings,omitempty"`
+}
+
+// ActivityRanking 动物活动场玩家名次和奖励
+type ActivityRanking struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	RecordID    uint      `gorm:"not null;index" json:"record_id"`
+	Rank        uint32    `json:"rank"`                           // 名次（没有积分为0）
+	PlayerID    uint32    `gorm:"index" json:"player_id"`
+	UserID      uint      `gorm:"index" json:"user_id"`
+	Name        string    `gorm:"size:100" json:"name"`
+	Score       int64     `json:"score"`                          // 活动积分
+	Reward      int64     `gorm:"default:0" json:"reward"`        // 排名奖励金豆
+	Paid        bool      `gorm:"default:false" json:"paid"`      // 奖励是否已发放到钱包
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -32,6 +32,18 @@ type Wallet struct {
 // WalletTransaction 是 Transaction 的别名，用于兼容性
 type WalletTransaction = Transaction
 
+// 投注交易子类型（Type为bet时的SubType）
+const (
+	BetSubTypeSpin       = "spin"        // 普通下注
+	BetSubTypeAnte       = "ante"        // 加注下注
+	BetSubTypeBuyFeature = "buy_feature" // 购买免费游戏
+)
+
+// 奖励交易子类型（Type为bonus时的SubType）
+const (
+	BonusSubTypeActivityRank = "activity_rank" // 动物活动场排名奖励
+)
+
 // Transaction 交易记录表
 type Transaction struct {
 	BaseModel
@@ -884,7 +884,7 @@ func (x *M_1802Toc) GetTotalWin() uint32 {
 // @name go_bet
 type M_1803Tos struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            *uint32                `protobuf:"varint,1,req,name=id" json:"id,omitempty"`                            // 动物ID
+	Id            *uint32                `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`                            // 客户端显示命中的动物ID（仅供参考，命中由服务端按弹道判定）
 	BulletId      *string                `protobuf:"bytes,2,opt,name=bullet_id,json=bulletId" json:"bullet_id,omitempty"` // 子弹 id
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -942,6 +942,7 @@ type M_1803Toc struct {
 	Skill         []*PAnimalSkill        `protobuf:"bytes,4,rep,name=skill" json:"skill,omitempty"`                        // 技能列表（用于更新技能）
 	FreeGold      *uint64                `protobuf:"varint,5,opt,name=free_gold,json=freeGold" json:"free_gold,omitempty"` // 体验币
 	TotalWin      *uint64                `protobuf:"varint,6,req,name=total_win,json=totalWin" json:"total_win,omitempty"` // 累计赢的分
+	HitId         *uint32                `protobuf:"varint,7,opt,name=hit_id,json=hitId" json:"hit_id,omitempty"`          // 服务端判定命中的动物ID（0 表示未命中）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1018,6 +1019,13 @@ func (x *M_1803Toc) GetTotalWin() uint64 {
 	return 0
 }
 
+func (x *M_1803Toc) GetHitId() uint32 {
+	if x != nil && x.HitId != nil {
+		return *x.HitId
+	}
+	return 0
+}
+
 // 查看玩家游戏记录
 // @name get_animal_record
 type M_1804Tos struct {
@@ -2141,6 +2149,9 @@ func (x *M_1814Toc) GetRoleId() uint32 {
 type M_1815Tos struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BetVal        *uint32                `protobuf:"varint,1,req,name=bet_val,json=betVal" json:"bet_val,omitempty"` // 下注金额
+	Angle         *float32               `protobuf:"fixed32,2,opt,name=angle" json:"angle,omitempty"`                // 发射角度（度，屏幕坐标系：0 水平向右，90 竖直向下）
+	Seat          *uint32                `protobuf:"varint,3,opt,name=seat" json:"seat,omitempty"`                   // 发射座位号（须与服务端分配的座位一致）
+	Time          *uint64                `protobuf:"varint,4,opt,name=time" json:"time,omitempty"`                   // 客户端发射时间（毫秒时间戳，用于延迟补偿）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2182,6 +2193,27 @@ func (x *M_1815Tos) GetBetVal() uint32 {
 	return 0
 }
 
+func (x *M_1815Tos) GetAngle() float32 {
+	if x != nil && x.Angle != nil {
+		return *x.Angle
+	}
+	return 0
+}
+
+func (x *M_1815Tos) GetSeat() uint32 {
+	if x != nil && x.Seat != nil {
+		return *x.Seat
+	}
+	return 0
+}
+
+func (x *M_1815Tos) GetTime() uint64 {
+	if x != nil && x.Time != nil {
+		return *x.Time
+	}
+	return 0
+}
+
 type M_1815Toc struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BulletId      *string                `protobuf:"bytes,1,req,name=bullet_id,json=bulletId" json:"bullet_id,omitempty"` // 子弹 id
@@ -2234,6 +2266,144 @@ func (x *M_1815Toc) GetBalance() uint64 {
 	return 0
 }
 
+// 观战房间（不占座位，不能下注，离开使用 leave_room）
+// @name watch_room
+type M_1816Tos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *EZooType              `protobuf:"varint,1,opt,name=type,enum=animal.EZooType" json:"type,omitempty"` // 动物园场类型
+	RoomId        *uint32                `protobuf:"varint,2,opt,name=room_id,json=roomId" json:"room_id,omitempty"`    // 房间ID（不传时观战该场类型人数最多的房间）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1816Tos) Reset() {
+	*x = M_1816Tos{}
+	mi := &file_proto_animal_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1816Tos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1816Tos) ProtoMessage() {}
+
+func (x *M_1816Tos) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_animal_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1816Tos.ProtoReflect.Descriptor instead.
+func (*M_1816Tos) Descriptor() ([]byte, []int) {
+	return file_proto_animal_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *M_1816Tos) GetType() EZooType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return EZooType_civilian
+}
+
+func (x *M_1816Tos) GetRoomId() uint32 {
+	if x != nil && x.RoomId != nil {
+		return *x.RoomId
+	}
+	return 0
+}
+
+type M_1816Toc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        *uint32                `protobuf:"varint,1,req,name=room_id,json=roomId" json:"room_id,omitempty"` // 房间ID
+	BetVal        []uint32               `protobuf:"varint,2,rep,name=bet_val,json=betVal" json:"bet_val,omitempty"` // 下注档位
+	Odds          []*PAnimalOdds         `protobuf:"bytes,3,rep,name=odds" json:"odds,omitempty"`                    // 动物赔率
+	Animals       []*PRoute              `protobuf:"bytes,4,rep,name=animals" json:"animals,omitempty"`              // 路线
+	Players       []*PAnimalPlayer       `protobuf:"bytes,5,rep,name=players" json:"players,omitempty"`              // 玩家信息
+	Watchers      *uint32                `protobuf:"varint,6,req,name=watchers" json:"watchers,omitempty"`           // 观战人数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1816Toc) Reset() {
+	*x = M_1816Toc{}
+	mi := &file_proto_animal_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1816Toc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1816Toc) ProtoMessage() {}
+
+func (x *M_1816Toc) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_animal_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1816Toc.ProtoReflect.Descriptor instead.
+func (*M_1816Toc) Descriptor() ([]byte, []int) {
+	return file_proto_animal_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *M_1816Toc) GetRoomId() uint32 {
+	if x != nil && x.RoomId != nil {
+		return *x.RoomId
+	}
+	return 0
+}
+
+func (x *M_1816Toc) GetBetVal() []uint32 {
+	if x != nil {
+		return x.BetVal
+	}
+	return nil
+}
+
+func (x *M_1816Toc) GetOdds() []*PAnimalOdds {
+	if x != nil {
+		return x.Odds
+	}
+	return nil
+}
+
+func (x *M_1816Toc) GetAnimals() []*PRoute {
+	if x != nil {
+		return x.Animals
+	}
+	return nil
+}
+
+func (x *M_1816Toc) GetPlayers() []*PAnimalPlayer {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *M_1816Toc) GetWatchers() uint32 {
+	if x != nil && x.Watchers != nil {
+		return *x.Watchers
+	}
+	return 0
+}
+
 // 推送玩家打动物
 // @name push_hit_animal
 type M_1899Toc struct {
@@ -2246,7 +2416,7 @@ type M_1899Toc struct {
 
 func (x *M_1899Toc) Reset() {
 	*x = M_1899Toc{}
-	mi := &file_proto_animal_proto_msgTypes[34]
+	mi := &file_proto_animal_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2258,7 +2428,7 @@ func (x *M_1899Toc) String() string {
 func (*M_1899Toc) ProtoMessage() {}
 
 func (x *M_1899Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[34]
+	mi := &file_proto_animal_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2271,7 +2441,7 @@ func (x *M_1899Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1899Toc.ProtoReflect.Descriptor instead.
 func (*M_1899Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{34}
+	return file_proto_animal_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *M_1899Toc) GetRoleId() uint32 {
@@ -2299,7 +2469,7 @@ type M_1888Toc struct {
 
 func (x *M_1888Toc) Reset() {
 	*x = M_1888Toc{}
-	mi := &file_proto_animal_proto_msgTypes[35]
+	mi := &file_proto_animal_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2311,7 +2481,7 @@ func (x *M_1888Toc) String() string {
 func (*M_1888Toc) ProtoMessage() {}
 
 func (x *M_1888Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[35]
+	mi := &file_proto_animal_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2324,7 +2494,7 @@ func (x *M_1888Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1888Toc.ProtoReflect.Descriptor instead.
 func (*M_1888Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{35}
+	return file_proto_animal_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *M_1888Toc) GetId() uint32 {
@@ -2345,7 +2515,7 @@ type M_1887Toc struct {
 
 func (x *M_1887Toc) Reset() {
 	*x = M_1887Toc{}
-	mi := &file_proto_animal_proto_msgTypes[36]
+	mi := &file_proto_animal_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2357,7 +2527,7 @@ func (x *M_1887Toc) String() string {
 func (*M_1887Toc) ProtoMessage() {}
 
 func (x *M_1887Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[36]
+	mi := &file_proto_animal_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2370,7 +2540,7 @@ func (x *M_1887Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1887Toc.ProtoReflect.Descriptor instead.
 func (*M_1887Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{36}
+	return file_proto_animal_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *M_1887Toc) GetAnimal() []*PRoute {
@@ -2391,7 +2561,7 @@ type M_1886Toc struct {
 
 func (x *M_1886Toc) Reset() {
 	*x = M_1886Toc{}
-	mi := &file_proto_animal_proto_msgTypes[37]
+	mi := &file_proto_animal_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2403,7 +2573,7 @@ func (x *M_1886Toc) String() string {
 func (*M_1886Toc) ProtoMessage() {}
 
 func (x *M_1886Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[37]
+	mi := &file_proto_animal_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2416,7 +2586,7 @@ func (x *M_1886Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1886Toc.ProtoReflect.Descriptor instead.
 func (*M_1886Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{37}
+	return file_proto_animal_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *M_1886Toc) GetPlayer() *PAnimalPlayer {
@@ -2437,7 +2607,7 @@ type M_1885Toc struct {
 
 func (x *M_1885Toc) Reset() {
 	*x = M_1885Toc{}
-	mi := &file_proto_animal_proto_msgTypes[38]
+	mi := &file_proto_animal_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2449,7 +2619,7 @@ func (x *M_1885Toc) String() string {
 func (*M_1885Toc) ProtoMessage() {}
 
 func (x *M_1885Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[38]
+	mi := &file_proto_animal_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2462,7 +2632,7 @@ func (x *M_1885Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1885Toc.ProtoReflect.Descriptor instead.
 func (*M_1885Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{38}
+	return file_proto_animal_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *M_1885Toc) GetRoleId() uint32 {
@@ -2485,7 +2655,7 @@ type M_1884Toc struct {
 
 func (x *M_1884Toc) Reset() {
 	*x = M_1884Toc{}
-	mi := &file_proto_animal_proto_msgTypes[39]
+	mi := &file_proto_animal_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2497,7 +2667,7 @@ func (x *M_1884Toc) String() string {
 func (*M_1884Toc) ProtoMessage() {}
 
 func (x *M_1884Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[39]
+	mi := &file_proto_animal_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2510,7 +2680,7 @@ func (x *M_1884Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1884Toc.ProtoReflect.Descriptor instead.
 func (*M_1884Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{39}
+	return file_proto_animal_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *M_1884Toc) GetRoleId() uint32 {
@@ -2545,7 +2715,7 @@ type PAnimalOne struct {
 
 func (x *PAnimalOne) Reset() {
 	*x = PAnimalOne{}
-	mi := &file_proto_animal_proto_msgTypes[40]
+	mi := &file_proto_animal_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2557,7 +2727,7 @@ func (x *PAnimalOne) String() string {
 func (*PAnimalOne) ProtoMessage() {}
 
 func (x *PAnimalOne) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[40]
+	mi := &file_proto_animal_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2570,7 +2740,7 @@ func (x *PAnimalOne) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PAnimalOne.ProtoReflect.Descriptor instead.
 func (*PAnimalOne) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{40}
+	return file_proto_animal_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *PAnimalOne) GetId() uint32 {
@@ -2606,7 +2776,7 @@ type M_1883Toc struct {
 
 func (x *M_1883Toc) Reset() {
 	*x = M_1883Toc{}
-	mi := &file_proto_animal_proto_msgTypes[41]
+	mi := &file_proto_animal_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2618,7 +2788,7 @@ func (x *M_1883Toc) String() string {
 func (*M_1883Toc) ProtoMessage() {}
 
 func (x *M_1883Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[41]
+	mi := &file_proto_animal_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2631,7 +2801,7 @@ func (x *M_1883Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1883Toc.ProtoReflect.Descriptor instead.
 func (*M_1883Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{41}
+	return file_proto_animal_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *M_1883Toc) GetAnimal() EAnimal {
@@ -2662,7 +2832,7 @@ type M_1882Toc struct {
 
 func (x *M_1882Toc) Reset() {
 	*x = M_1882Toc{}
-	mi := &file_proto_animal_proto_msgTypes[42]
+	mi := &file_proto_animal_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2674,7 +2844,7 @@ func (x *M_1882Toc) String() string {
 func (*M_1882Toc) ProtoMessage() {}
 
 func (x *M_1882Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[42]
+	mi := &file_proto_animal_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2687,7 +2857,7 @@ func (x *M_1882Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1882Toc.ProtoReflect.Descriptor instead.
 func (*M_1882Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{42}
+	return file_proto_animal_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *M_1882Toc) GetRoleId() uint32 {
@@ -2730,7 +2900,7 @@ type M_1871Tos struct {
 
 func (x *M_1871Tos) Reset() {
 	*x = M_1871Tos{}
-	mi := &file_proto_animal_proto_msgTypes[43]
+	mi := &file_proto_animal_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2742,7 +2912,7 @@ func (x *M_1871Tos) String() string {
 func (*M_1871Tos) ProtoMessage() {}
 
 func (x *M_1871Tos) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[43]
+	mi := &file_proto_animal_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2755,7 +2925,7 @@ func (x *M_1871Tos) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1871Tos.ProtoReflect.Descriptor instead.
 func (*M_1871Tos) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{43}
+	return file_proto_animal_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *M_1871Tos) GetAgentId() uint32 {
@@ -2782,7 +2952,7 @@ type M_1871Toc struct {
 
 func (x *M_1871Toc) Reset() {
 	*x = M_1871Toc{}
-	mi := &file_proto_animal_proto_msgTypes[44]
+	mi := &file_proto_animal_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2794,7 +2964,7 @@ func (x *M_1871Toc) String() string {
 func (*M_1871Toc) ProtoMessage() {}
 
 func (x *M_1871Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[44]
+	mi := &file_proto_animal_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2807,7 +2977,7 @@ func (x *M_1871Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1871Toc.ProtoReflect.Descriptor instead.
 func (*M_1871Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{44}
+	return file_proto_animal_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *M_1871Toc) GetBetVal() []uint32 {
@@ -2884,7 +3054,7 @@ type PActivityReward struct {
 
 func (x *PActivityReward) Reset() {
 	*x = PActivityReward{}
-	mi := &file_proto_animal_proto_msgTypes[45]
+	mi := &file_proto_animal_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2896,7 +3066,7 @@ func (x *PActivityReward) String() string {
 func (*PActivityReward) ProtoMessage() {}
 
 func (x *PActivityReward) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[45]
+	mi := &file_proto_animal_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2909,7 +3079,7 @@ func (x *PActivityReward) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PActivityReward.ProtoReflect.Descriptor instead.
 func (*PActivityReward) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{45}
+	return file_proto_animal_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *PActivityReward) GetMin() uint32 {
@@ -2947,7 +3117,7 @@ type PRank struct {
 
 func (x *PRank) Reset() {
 	*x = PRank{}
-	mi := &file_proto_animal_proto_msgTypes[46]
+	mi := &file_proto_animal_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2959,7 +3129,7 @@ func (x *PRank) String() string {
 func (*PRank) ProtoMessage() {}
 
 func (x *PRank) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[46]
+	mi := &file_proto_animal_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2972,7 +3142,7 @@ func (x *PRank) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PRank.ProtoReflect.Descriptor instead.
 func (*PRank) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{46}
+	return file_proto_animal_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *PRank) GetId() uint32 {
@@ -3029,7 +3199,7 @@ type M_1872Tos struct {
 
 func (x *M_1872Tos) Reset() {
 	*x = M_1872Tos{}
-	mi := &file_proto_animal_proto_msgTypes[47]
+	mi := &file_proto_animal_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3041,7 +3211,7 @@ func (x *M_1872Tos) String() string {
 func (*M_1872Tos) ProtoMessage() {}
 
 func (x *M_1872Tos) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[47]
+	mi := &file_proto_animal_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3054,7 +3224,7 @@ func (x *M_1872Tos) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1872Tos.ProtoReflect.Descriptor instead.
 func (*M_1872Tos) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{47}
+	return file_proto_animal_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *M_1872Tos) GetId() uint32 {
@@ -3081,7 +3251,7 @@ type M_1872Toc struct {
 
 func (x *M_1872Toc) Reset() {
 	*x = M_1872Toc{}
-	mi := &file_proto_animal_proto_msgTypes[48]
+	mi := &file_proto_animal_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3093,7 +3263,7 @@ func (x *M_1872Toc) String() string {
 func (*M_1872Toc) ProtoMessage() {}
 
 func (x *M_1872Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[48]
+	mi := &file_proto_animal_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3106,7 +3276,7 @@ func (x *M_1872Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1872Toc.ProtoReflect.Descriptor instead.
 func (*M_1872Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{48}
+	return file_proto_animal_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *M_1872Toc) GetBalance() uint64 {
@@ -3135,7 +3305,7 @@ type M_1873Tos struct {
 
 func (x *M_1873Tos) Reset() {
 	*x = M_1873Tos{}
-	mi := &file_proto_animal_proto_msgTypes[49]
+	mi := &file_proto_animal_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3147,7 +3317,7 @@ func (x *M_1873Tos) String() string {
 func (*M_1873Tos) ProtoMessage() {}
 
 func (x *M_1873Tos) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[49]
+	mi := &file_proto_animal_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3160,7 +3330,7 @@ func (x *M_1873Tos) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1873Tos.ProtoReflect.Descriptor instead.
 func (*M_1873Tos) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{49}
+	return file_proto_animal_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *M_1873Tos) GetId() uint32 {
@@ -3186,7 +3356,7 @@ type M_1873Toc struct {
 
 func (x *M_1873Toc) Reset() {
 	*x = M_1873Toc{}
-	mi := &file_proto_animal_proto_msgTypes[50]
+	mi := &file_proto_animal_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3198,7 +3368,7 @@ func (x *M_1873Toc) String() string {
 func (*M_1873Toc) ProtoMessage() {}
 
 func (x *M_1873Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[50]
+	mi := &file_proto_animal_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3211,7 +3381,7 @@ func (x *M_1873Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1873Toc.ProtoReflect.Descriptor instead.
 func (*M_1873Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{50}
+	return file_proto_animal_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *M_1873Toc) GetRank() []*PRank {
@@ -3232,7 +3402,7 @@ type M_1874Toc struct {
 
 func (x *M_1874Toc) Reset() {
 	*x = M_1874Toc{}
-	mi := &file_proto_animal_proto_msgTypes[51]
+	mi := &file_proto_animal_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3244,7 +3414,7 @@ func (x *M_1874Toc) String() string {
 func (*M_1874Toc) ProtoMessage() {}
 
 func (x *M_1874Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[51]
+	mi := &file_proto_animal_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3257,7 +3427,7 @@ func (x *M_1874Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1874Toc.ProtoReflect.Descriptor instead.
 func (*M_1874Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{51}
+	return file_proto_animal_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *M_1874Toc) GetId() []uint32 {
@@ -3279,7 +3449,7 @@ type M_1875Toc struct {
 
 func (x *M_1875Toc) Reset() {
 	*x = M_1875Toc{}
-	mi := &file_proto_animal_proto_msgTypes[52]
+	mi := &file_proto_animal_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3291,7 +3461,7 @@ func (x *M_1875Toc) String() string {
 func (*M_1875Toc) ProtoMessage() {}
 
 func (x *M_1875Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[52]
+	mi := &file_proto_animal_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3304,7 +3474,7 @@ func (x *M_1875Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1875Toc.ProtoReflect.Descriptor instead.
 func (*M_1875Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{52}
+	return file_proto_animal_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *M_1875Toc) GetGold() uint64 {
@@ -3332,7 +3502,7 @@ type M_1876Toc struct {
 
 func (x *M_1876Toc) Reset() {
 	*x = M_1876Toc{}
-	mi := &file_proto_animal_proto_msgTypes[53]
+	mi := &file_proto_animal_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3344,7 +3514,7 @@ func (x *M_1876Toc) String() string {
 func (*M_1876Toc) ProtoMessage() {}
 
 func (x *M_1876Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[53]
+	mi := &file_proto_animal_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3357,7 +3527,7 @@ func (x *M_1876Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1876Toc.ProtoReflect.Descriptor instead.
 func (*M_1876Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{53}
+	return file_proto_animal_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *M_1876Toc) GetAnimal() []*PRoute {
@@ -3380,7 +3550,7 @@ type M_1877Toc struct {
 
 func (x *M_1877Toc) Reset() {
 	*x = M_1877Toc{}
-	mi := &file_proto_animal_proto_msgTypes[54]
+	mi := &file_proto_animal_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3392,7 +3562,7 @@ func (x *M_1877Toc) String() string {
 func (*M_1877Toc) ProtoMessage() {}
 
 func (x *M_1877Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[54]
+	mi := &file_proto_animal_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3405,7 +3575,7 @@ func (x *M_1877Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1877Toc.ProtoReflect.Descriptor instead.
 func (*M_1877Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{54}
+	return file_proto_animal_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *M_1877Toc) GetRoleId() uint32 {
@@ -3441,7 +3611,7 @@ type M_1878Toc struct {
 
 func (x *M_1878Toc) Reset() {
 	*x = M_1878Toc{}
-	mi := &file_proto_animal_proto_msgTypes[55]
+	mi := &file_proto_animal_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3453,7 +3623,7 @@ func (x *M_1878Toc) String() string {
 func (*M_1878Toc) ProtoMessage() {}
 
 func (x *M_1878Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[55]
+	mi := &file_proto_animal_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3466,7 +3636,7 @@ func (x *M_1878Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1878Toc.ProtoReflect.Descriptor instead.
 func (*M_1878Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{55}
+	return file_proto_animal_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *M_1878Toc) GetTime() uint32 {
@@ -3494,7 +3664,7 @@ type M_1879Tos struct {
 
 func (x *M_1879Tos) Reset() {
 	*x = M_1879Tos{}
-	mi := &file_proto_animal_proto_msgTypes[56]
+	mi := &file_proto_animal_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3506,7 +3676,7 @@ func (x *M_1879Tos) String() string {
 func (*M_1879Tos) ProtoMessage() {}
 
 func (x *M_1879Tos) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[56]
+	mi := &file_proto_animal_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3519,7 +3689,7 @@ func (x *M_1879Tos) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1879Tos.ProtoReflect.Descriptor instead.
 func (*M_1879Tos) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{56}
+	return file_proto_animal_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *M_1879Tos) GetAgentId() uint32 {
@@ -3543,7 +3713,7 @@ type M_1879Toc struct {
 
 func (x *M_1879Toc) Reset() {
 	*x = M_1879Toc{}
-	mi := &file_proto_animal_proto_msgTypes[57]
+	mi := &file_proto_animal_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3555,7 +3725,7 @@ func (x *M_1879Toc) String() string {
 func (*M_1879Toc) ProtoMessage() {}
 
 func (x *M_1879Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[57]
+	mi := &file_proto_animal_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3568,7 +3738,7 @@ func (x *M_1879Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1879Toc.ProtoReflect.Descriptor instead.
 func (*M_1879Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{57}
+	return file_proto_animal_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *M_1879Toc) GetAnimals() []*PRoute {
@@ -3624,7 +3794,7 @@ type M_1880Toc struct {
 
 func (x *M_1880Toc) Reset() {
 	*x = M_1880Toc{}
-	mi := &file_proto_animal_proto_msgTypes[58]
+	mi := &file_proto_animal_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3636,7 +3806,7 @@ func (x *M_1880Toc) String() string {
 func (*M_1880Toc) ProtoMessage() {}
 
 func (x *M_1880Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[58]
+	mi := &file_proto_animal_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3649,7 +3819,7 @@ func (x *M_1880Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1880Toc.ProtoReflect.Descriptor instead.
 func (*M_1880Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{58}
+	return file_proto_animal_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *M_1880Toc) GetRank() []*PRank {
@@ -3670,7 +3840,7 @@ type M_1881Toc struct {
 
 func (x *M_1881Toc) Reset() {
 	*x = M_1881Toc{}
-	mi := &file_proto_animal_proto_msgTypes[59]
+	mi := &file_proto_animal_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3682,7 +3852,7 @@ func (x *M_1881Toc) String() string {
 func (*M_1881Toc) ProtoMessage() {}
 
 func (x *M_1881Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[59]
+	mi := &file_proto_animal_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3695,7 +3865,7 @@ func (x *M_1881Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1881Toc.ProtoReflect.Descriptor instead.
 func (*M_1881Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{59}
+	return file_proto_animal_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *M_1881Toc) GetId() uint32 {
@@ -3719,7 +3889,7 @@ type M_1889Toc struct {
 
 func (x *M_1889Toc) Reset() {
 	*x = M_1889Toc{}
-	mi := &file_proto_animal_proto_msgTypes[60]
+	mi := &file_proto_animal_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3731,7 +3901,7 @@ func (x *M_1889Toc) String() string {
 func (*M_1889Toc) ProtoMessage() {}
 
 func (x *M_1889Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_animal_proto_msgTypes[60]
+	mi := &file_proto_animal_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3744,7 +3914,7 @@ func (x *M_1889Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1889Toc.ProtoReflect.Descriptor instead.
 func (*M_1889Toc) Descriptor() ([]byte, []int) {
-	return file_proto_animal_proto_rawDescGZIP(), []int{60}
+	return file_proto_animal_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *M_1889Toc) GetName() string {
@@ -3821,8 +3991,8 @@ const file_proto_animal_proto_rawDesc = "" +
 	"\ttotal_win\x18\x01 \x02(\rR\btotalWin\"9\n" +
 	"\n" +
 	"m_1803_tos\x12\x0e\n" +
-	"\x02id\x18\x01 \x02(\rR\x02id\x12\x1b\n" +
-	"\tbullet_id\x18\x02 \x01(\tR\bbulletId\"\xb9\x01\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1b\n" +
+	"\tbullet_id\x18\x02 \x01(\tR\bbulletId\"\xd0\x01\n" +
 	"\n" +
 	"m_1803_toc\x12\x18\n" +
 	"\abalance\x18\x01 \x02(\x04R\abalance\x12\x10\n" +
@@ -3830,7 +4000,8 @@ const file_proto_animal_proto_rawDesc = "" +
 	"\ared_bag\x18\x03 \x02(\rR\x06redBag\x12,\n" +
 	"\x05skill\x18\x04 \x03(\v2\x16.animal.p_animal_skillR\x05skill\x12\x1b\n" +
 	"\tfree_gold\x18\x05 \x01(\x04R\bfreeGold\x12\x1b\n" +
-	"\ttotal_win\x18\x06 \x02(\x04R\btotalWin\"4\n" +
+	"\ttotal_win\x18\x06 \x02(\x04R\btotalWin\x12\x15\n" +
+	"\x06hit_id\x18\a \x01(\rR\x05hitId\"4\n" +
 	"\n" +
 	"m_1804_tos\x12\x0e\n" +
 	"\x02id\x18\x01 \x02(\rR\x02id\x12\x16\n" +
@@ -3908,14 +4079,29 @@ const file_proto_animal_proto_rawDesc = "" +
 	"\abet_val\x18\x03 \x02(\rR\x06betVal\"%\n" +
 	"\n" +
 	"m_1814_toc\x12\x17\n" +
-	"\arole_id\x18\x01 \x02(\rR\x06roleId\"%\n" +
+	"\arole_id\x18\x01 \x02(\rR\x06roleId\"c\n" +
 	"\n" +
 	"m_1815_tos\x12\x17\n" +
-	"\abet_val\x18\x01 \x02(\rR\x06betVal\"C\n" +
+	"\abet_val\x18\x01 \x02(\rR\x06betVal\x12\x14\n" +
+	"\x05angle\x18\x02 \x01(\x02R\x05angle\x12\x12\n" +
+	"\x04seat\x18\x03 \x01(\rR\x04seat\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\x04R\x04time\"C\n" +
 	"\n" +
 	"m_1815_toc\x12\x1b\n" +
 	"\tbullet_id\x18\x01 \x02(\tR\bbulletId\x12\x18\n" +
-	"\abalance\x18\x02 \x02(\x04R\abalance\"5\n" +
+	"\abalance\x18\x02 \x02(\x04R\abalance\"M\n" +
+	"\n" +
+	"m_1816_tos\x12&\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x12.animal.e_zoo_typeR\x04type\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\rR\x06roomId\"\xe3\x01\n" +
+	"\n" +
+	"m_1816_toc\x12\x17\n" +
+	"\aroom_id\x18\x01 \x02(\rR\x06roomId\x12\x17\n" +
+	"\abet_val\x18\x02 \x03(\rR\x06betVal\x12)\n" +
+	"\x04odds\x18\x03 \x03(\v2\x15.animal.p_animal_oddsR\x04odds\x12)\n" +
+	"\aanimals\x18\x04 \x03(\v2\x0f.animal.p_routeR\aanimals\x121\n" +
+	"\aplayers\x18\x05 \x03(\v2\x17.animal.p_animal_playerR\aplayers\x12\x1a\n" +
+	"\bwatchers\x18\x06 \x02(\rR\bwatchers\"5\n" +
 	"\n" +
 	"m_1899_toc\x12\x17\n" +
 	"\arole_id\x18\x01 \x02(\rR\x06roleId\x12\x0e\n" +
@@ -4095,7 +4281,7 @@ func file_proto_animal_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_animal_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_proto_animal_proto_msgTypes = make([]protoimpl.MessageInfo, 61)
+var file_proto_animal_proto_msgTypes = make([]protoimpl.MessageInfo, 63)
 var file_proto_animal_proto_goTypes = []any{
 	(EAnimalSkillType)(0),   // 0: animal.e_animal_skill_type
 	(EAnimalState)(0),       // 1: animal.e_animal_state
@@ -4136,33 +4322,35 @@ var file_proto_animal_proto_goTypes = []any{
 	(*M_1814Toc)(nil),       // 36: animal.m_1814_toc
 	(*M_1815Tos)(nil),       // 37: animal.m_1815_tos
 	(*M_1815Toc)(nil),       // 38: animal.m_1815_toc
-	(*M_1899Toc)(nil),       // 39: animal.m_1899_toc
-	(*M_1888Toc)(nil),       // 40: animal.m_1888_toc
-	(*M_1887Toc)(nil),       // 41: animal.m_1887_toc
-	(*M_1886Toc)(nil),       // 42: animal.m_1886_toc
-	(*M_1885Toc)(nil),       // 43: animal.m_1885_toc
-	(*M_1884Toc)(nil),       // 44: animal.m_1884_toc
-	(*PAnimalOne)(nil),      // 45: animal.p_animal_one
-	(*M_1883Toc)(nil),       // 46: animal.m_1883_toc
-	(*M_1882Toc)(nil),       // 47: animal.m_1882_toc
-	(*M_1871Tos)(nil),       // 48: animal.m_1871_tos
-	(*M_1871Toc)(nil),       // 49: animal.m_1871_toc
-	(*PActivityReward)(nil), // 50: animal.p_activity_reward
-	(*PRank)(nil),           // 51: animal.p_rank
-	(*M_1872Tos)(nil),       // 52: animal.m_1872_tos
-	(*M_1872Toc)(nil),       // 53: animal.m_1872_toc
-	(*M_1873Tos)(nil),       // 54: animal.m_1873_tos
-	(*M_1873Toc)(nil),       // 55: animal.m_1873_toc
-	(*M_1874Toc)(nil),       // 56: animal.m_1874_toc
-	(*M_1875Toc)(nil),       // 57: animal.m_1875_toc
-	(*M_1876Toc)(nil),       // 58: animal.m_1876_toc
-	(*M_1877Toc)(nil),       // 59: animal.m_1877_toc
-	(*M_1878Toc)(nil),       // 60: animal.m_1878_toc
-	(*M_1879Tos)(nil),       // 61: animal.m_1879_tos
-	(*M_1879Toc)(nil),       // 62: animal.m_1879_toc
-	(*M_1880Toc)(nil),       // 63: animal.m_1880_toc
-	(*M_1881Toc)(nil),       // 64: animal.m_1881_toc
-	(*M_1889Toc)(nil),       // 65: animal.m_1889_toc
+	(*M_1816Tos)(nil),       // 39: animal.m_1816_tos
+	(*M_1816Toc)(nil),       // 40: animal.m_1816_toc
+	(*M_1899Toc)(nil),       // 41: animal.m_1899_toc
+	(*M_1888Toc)(nil),       // 42: animal.m_1888_toc
+	(*M_1887Toc)(nil),       // 43: animal.m_1887_toc
+	(*M_1886Toc)(nil),       // 44: animal.m_1886_toc
+	(*M_1885Toc)(nil),       // 45: animal.m_1885_toc
+	(*M_1884Toc)(nil),       // 46: animal.m_1884_toc
+	(*PAnimalOne)(nil),      // 47: animal.p_animal_one
+	(*M_1883Toc)(nil),       // 48: animal.m_1883_toc
+	(*M_1882Toc)(nil),       // 49: animal.m_1882_toc
+	(*M_1871Tos)(nil),       // 50: animal.m_1871_tos
+	(*M_1871Toc)(nil),       // 51: animal.m_1871_toc
+	(*PActivityReward)(nil), // 52: animal.p_activity_reward
+	(*PRank)(nil),           // 53: animal.p_rank
+	(*M_1872Tos)(nil),       // 54: animal.m_1872_tos
+	(*M_1872Toc)(nil),       // 55: animal.m_1872_toc
+	(*M_1873Tos)(nil),       // 56: animal.m_1873_tos
+	(*M_1873Toc)(nil),       // 57: animal.m_1873_toc
+	(*M_1874Toc)(nil),       // 58: animal.m_1874_toc
+	(*M_1875Toc)(nil),       // 59: animal.m_1875_toc
+	(*M_1876Toc)(nil),       // 60: animal.m_1876_toc
+	(*M_1877Toc)(nil),       // 61: animal.m_1877_toc
+	(*M_1878Toc)(nil),       // 62: animal.m_1878_toc
+	(*M_1879Tos)(nil),       // 63: animal.m_1879_tos
+	(*M_1879Toc)(nil),       // 64: animal.m_1879_toc
+	(*M_1880Toc)(nil),       // 65: animal.m_1880_toc
+	(*M_1881Toc)(nil),       // 66: animal.m_1881_toc
+	(*M_1889Toc)(nil),       // 67: animal.m_1889_toc
 }
 var file_proto_animal_proto_depIdxs = []int32{
 	4,  // 0: animal.m_1801_tos.type:type_name -> animal.e_zoo_type
@@ -4185,31 +4373,35 @@ var file_proto_animal_proto_depIdxs = []int32{
 	4,  // 17: animal.p_zoo_type_info.type:type_name -> animal.e_zoo_type
 	0,  // 18: animal.m_1808_tos.type:type_name -> animal.e_animal_skill_type
 	34, // 19: animal.m_1812_toc.list:type_name -> animal.p_cj_log
-	9,  // 20: animal.m_1887_toc.animal:type_name -> animal.p_route
-	10, // 21: animal.m_1886_toc.player:type_name -> animal.p_animal_player
-	3,  // 22: animal.m_1884_toc.type:type_name -> animal.e_animal_type
-	45, // 23: animal.m_1884_toc.ids:type_name -> animal.p_animal_one
-	2,  // 24: animal.m_1883_toc.animal:type_name -> animal.e_animal
-	0,  // 25: animal.m_1882_toc.type:type_name -> animal.e_animal_skill_type
-	8,  // 26: animal.m_1871_toc.odds:type_name -> animal.p_animal_odds
-	9,  // 27: animal.m_1871_toc.animals:type_name -> animal.p_route
-	51, // 28: animal.m_1871_toc.rank:type_name -> animal.p_rank
-	50, // 29: animal.m_1871_toc.reward:type_name -> animal.p_activity_reward
-	51, // 30: animal.m_1873_toc.rank:type_name -> animal.p_rank
-	51, // 31: animal.m_1875_toc.rank:type_name -> animal.p_rank
-	9,  // 32: animal.m_1876_toc.animal:type_name -> animal.p_route
-	3,  // 33: animal.m_1877_toc.type:type_name -> animal.e_animal_type
-	45, // 34: animal.m_1877_toc.ids:type_name -> animal.p_animal_one
-	9,  // 35: animal.m_1879_toc.animals:type_name -> animal.p_route
-	51, // 36: animal.m_1879_toc.rank:type_name -> animal.p_rank
-	50, // 37: animal.m_1879_toc.reward:type_name -> animal.p_activity_reward
-	51, // 38: animal.m_1880_toc.rank:type_name -> animal.p_rank
-	2,  // 39: animal.m_1889_toc.animal_name:type_name -> animal.e_animal
-	40, // [40:40] is the sub-list for method output_type
-	40, // [40:40] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
+	4,  // 20: animal.m_1816_tos.type:type_name -> animal.e_zoo_type
+	8,  // 21: animal.m_1816_toc.odds:type_name -> animal.p_animal_odds
+	9,  // 22: animal.m_1816_toc.animals:type_name -> animal.p_route
+	10, // 23: animal.m_1816_toc.players:type_name -> animal.p_animal_player
+	9,  // 24: animal.m_1887_toc.animal:type_name -> animal.p_route
+	10, // 25: animal.m_1886_toc.player:type_name -> animal.p_animal_player
+	3,  // 26: animal.m_1884_toc.type:type_name -> animal.e_animal_type
+	47, // 27: animal.m_1884_toc.ids:type_name -> animal.p_animal_one
+	2,  // 28: animal.m_1883_toc.animal:type_name -> animal.e_animal
+	0,  // 29: animal.m_1882_toc.type:type_name -> animal.e_animal_skill_type
+	8,  // 30: animal.m_1871_toc.odds:type_name -> animal.p_animal_odds
+	9,  // 31: animal.m_1871_toc.animals:type_name -> animal.p_route
+	53, // 32: animal.m_1871_toc.rank:type_name -> animal.p_rank
+	52, // 33: animal.m_1871_toc.reward:type_name -> animal.p_activity_reward
+	53, // 34: animal.m_1873_toc.rank:type_name -> animal.p_rank
+	53, // 35: animal.m_1875_toc.rank:type_name -> animal.p_rank
+	9,  // 36: animal.m_1876_toc.animal:type_name -> animal.p_route
+	3,  // 37: animal.m_1877_toc.type:type_name -> animal.e_animal_type
+	47, // 38: animal.m_1877_toc.ids:type_name -> animal.p_animal_one
+	9,  // 39: animal.m_1879_toc.animals:type_name -> animal.p_route
+	53, // 40: animal.m_1879_toc.rank:type_name -> animal.p_rank
+	52, // 41: animal.m_1879_toc.reward:type_name -> animal.p_activity_reward
+	53, // 42: animal.m_1880_toc.rank:type_name -> animal.p_rank
+	2,  // 43: animal.m_1889_toc.animal_name:type_name -> animal.e_animal
+	44, // [44:44] is the sub-list for method output_type
+	44, // [44:44] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
 }
 
 func init() { file_proto_animal_proto_init() }
@@ -4223,7 +4415,7 @@ func file_proto_animal_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_animal_proto_rawDesc), len(file_proto_animal_proto_rawDesc)),
 			NumEnums:      5,
-			NumMessages:   61,
+			NumMessages:   63,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
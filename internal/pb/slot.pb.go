@@ -80,6 +80,127 @@ func (ESlotType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_slot_proto_rawDescGZIP(), []int{0}
 }
 
+type ESlotBonusTile int32
+
+const (
+	ESlotBonusTile_e_slot_bonus_tile_credit  ESlotBonusTile = 1 // 奖金
+	ESlotBonusTile_e_slot_bonus_tile_collect ESlotBonusTile = 2 // 全收
+	ESlotBonusTile_e_slot_bonus_tile_end     ESlotBonusTile = 3 // 结束
+)
+
+// Enum value maps for ESlotBonusTile.
+var (
+	ESlotBonusTile_name = map[int32]string{
+		1: "e_slot_bonus_tile_credit",
+		2: "e_slot_bonus_tile_collect",
+		3: "e_slot_bonus_tile_end",
+	}
+	ESlotBonusTile_value = map[string]int32{
+		"e_slot_bonus_tile_credit":  1,
+		"e_slot_bonus_tile_collect": 2,
+		"e_slot_bonus_tile_end":     3,
+	}
+)
+
+func (x ESlotBonusTile) Enum() *ESlotBonusTile {
+	p := new(ESlotBonusTile)
+	*p = x
+	return p
+}
+
+func (x ESlotBonusTile) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ESlotBonusTile) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_slot_proto_enumTypes[1].Descriptor()
+}
+
+func (ESlotBonusTile) Type() protoreflect.EnumType {
+	return &file_proto_slot_proto_enumTypes[1]
+}
+
+func (x ESlotBonusTile) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ESlotBonusTile) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ESlotBonusTile(num)
+	return nil
+}
+
+// Deprecated: Use ESlotBonusTile.Descriptor instead.
+func (ESlotBonusTile) EnumDescriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{1}
+}
+
+type ESlotGambleGuess int32
+
+const (
+	ESlotGambleGuess_e_slot_gamble_guess_red   ESlotGambleGuess = 1 // 红
+	ESlotGambleGuess_e_slot_gamble_guess_black ESlotGambleGuess = 2 // 黑
+	ESlotGambleGuess_e_slot_gamble_guess_high  ESlotGambleGuess = 3 // 大（8-K）
+	ESlotGambleGuess_e_slot_gamble_guess_low   ESlotGambleGuess = 4 // 小（A-6）
+)
+
+// Enum value maps for ESlotGambleGuess.
+var (
+	ESlotGambleGuess_name = map[int32]string{
+		1: "e_slot_gamble_guess_red",
+		2: "e_slot_gamble_guess_black",
+		3: "e_slot_gamble_guess_high",
+		4: "e_slot_gamble_guess_low",
+	}
+	ESlotGambleGuess_value = map[string]int32{
+		"e_slot_gamble_guess_red":   1,
+		"e_slot_gamble_guess_black": 2,
+		"e_slot_gamble_guess_high":  3,
+		"e_slot_gamble_guess_low":   4,
+	}
+)
+
+func (x ESlotGambleGuess) Enum() *ESlotGambleGuess {
+	p := new(ESlotGambleGuess)
+	*p = x
+	return p
+}
+
+func (x ESlotGambleGuess) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ESlotGambleGuess) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_slot_proto_enumTypes[2].Descriptor()
+}
+
+func (ESlotGambleGuess) Type() protoreflect.EnumType {
+	return &file_proto_slot_proto_enumTypes[2]
+}
+
+func (x ESlotGambleGuess) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *ESlotGambleGuess) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = ESlotGambleGuess(num)
+	return nil
+}
+
+// Deprecated: Use ESlotGambleGuess.Descriptor instead.
+func (ESlotGambleGuess) EnumDescriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{2}
+}
+
 type ESlotBetType int32
 
 const (
@@ -93,6 +214,7 @@ const (
 	ESlotBetType_e_slot_bet_type_7      ESlotBetType = 7
 	ESlotBetType_e_slot_bet_type_wild   ESlotBetType = 8  // wild
 	ESlotBetType_e_slot_bet_type_free   ESlotBetType = 9  // 免费符
+	ESlotBetType_e_slot_bet_type_bonus  ESlotBetType = 10 // 奖励符
 	ESlotBetType_e_slot_bet_type_gold_0 ESlotBetType = 16 // 黄金牌0
 	ESlotBetType_e_slot_bet_type_gold_1 ESlotBetType = 17 // 黄金牌1
 	ESlotBetType_e_slot_bet_type_gold_2 ESlotBetType = 18 // 黄金牌2
@@ -116,6 +238,7 @@ var (
 		7:  "e_slot_bet_type_7",
 		8:  "e_slot_bet_type_wild",
 		9:  "e_slot_bet_type_free",
+		10: "e_slot_bet_type_bonus",
 		16: "e_slot_bet_type_gold_0",
 		17: "e_slot_bet_type_gold_1",
 		18: "e_slot_bet_type_gold_2",
@@ -136,6 +259,7 @@ var (
 		"e_slot_bet_type_7":      7,
 		"e_slot_bet_type_wild":   8,
 		"e_slot_bet_type_free":   9,
+		"e_slot_bet_type_bonus":  10,
 		"e_slot_bet_type_gold_0": 16,
 		"e_slot_bet_type_gold_1": 17,
 		"e_slot_bet_type_gold_2": 18,
@@ -158,11 +282,11 @@ func (x ESlotBetType) String() string {
 }
 
 func (ESlotBetType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_slot_proto_enumTypes[1].Descriptor()
+	return file_proto_slot_proto_enumTypes[3].Descriptor()
 }
 
 func (ESlotBetType) Type() protoreflect.EnumType {
-	return &file_proto_slot_proto_enumTypes[1]
+	return &file_proto_slot_proto_enumTypes[3]
 }
 
 func (x ESlotBetType) Number() protoreflect.EnumNumber {
@@ -181,7 +305,7 @@ func (x *ESlotBetType) UnmarshalJSON(b []byte) error {
 
 // Deprecated: Use ESlotBetType.Descriptor instead.
 func (ESlotBetType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{1}
+	return file_proto_slot_proto_rawDescGZIP(), []int{3}
 }
 
 // 进入房间
@@ -232,9 +356,11 @@ func (x *M_1901Tos) GetType() ESlotType {
 
 type M_1901Toc struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	BetVal        []uint32               `protobuf:"varint,1,rep,name=bet_val,json=betVal" json:"bet_val,omitempty"` // 下注档位
-	Odds          []*PSlotOdds           `protobuf:"bytes,2,rep,name=odds" json:"odds,omitempty"`                    // 拉霸机赔率
-	Cfg           *PConfig               `protobuf:"bytes,3,req,name=cfg" json:"cfg,omitempty"`                      // 配置
+	BetVal        []uint32               `protobuf:"varint,1,rep,name=bet_val,json=betVal" json:"bet_val,omitempty"`            // 下注档位
+	Odds          []*PSlotOdds           `protobuf:"bytes,2,rep,name=odds" json:"odds,omitempty"`                               // 拉霸机赔率
+	Cfg           *PConfig               `protobuf:"bytes,3,req,name=cfg" json:"cfg,omitempty"`                                 // 配置
+	Ante          *PSlotAnte             `protobuf:"bytes,4,opt,name=ante" json:"ante,omitempty"`                               // 加注模式（机台不支持时为空）
+	BuyFeature    *PSlotBuyFeature       `protobuf:"bytes,5,opt,name=buy_feature,json=buyFeature" json:"buy_feature,omitempty"` // 购买免费游戏（机台不支持时为空）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -290,6 +416,132 @@ func (x *M_1901Toc) GetCfg() *PConfig {
 	return nil
 }
 
+func (x *M_1901Toc) GetAnte() *PSlotAnte {
+	if x != nil {
+		return x.Ante
+	}
+	return nil
+}
+
+func (x *M_1901Toc) GetBuyFeature() *PSlotBuyFeature {
+	if x != nil {
+		return x.BuyFeature
+	}
+	return nil
+}
+
+type PSlotAnte struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CostRate      *uint32                `protobuf:"varint,1,req,name=cost_rate,json=costRate" json:"cost_rate,omitempty"` // 加注扣费比例（百分比，125为扣下注金额的1.25倍）
+	Rtp           *uint32                `protobuf:"varint,2,req,name=rtp" json:"rtp,omitempty"`                           // 加注理论返还率（万分比）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotAnte) Reset() {
+	*x = PSlotAnte{}
+	mi := &file_proto_slot_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotAnte) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotAnte) ProtoMessage() {}
+
+func (x *PSlotAnte) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotAnte.ProtoReflect.Descriptor instead.
+func (*PSlotAnte) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PSlotAnte) GetCostRate() uint32 {
+	if x != nil && x.CostRate != nil {
+		return *x.CostRate
+	}
+	return 0
+}
+
+func (x *PSlotAnte) GetRtp() uint32 {
+	if x != nil && x.Rtp != nil {
+		return *x.Rtp
+	}
+	return 0
+}
+
+type PSlotBuyFeature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceRate     *uint32                `protobuf:"varint,1,req,name=price_rate,json=priceRate" json:"price_rate,omitempty"` // 购买价格比例（百分比，1000为下注金额的10倍）
+	FreeSpins     *uint32                `protobuf:"varint,2,req,name=free_spins,json=freeSpins" json:"free_spins,omitempty"` // 获得的免费次数
+	Rtp           *uint32                `protobuf:"varint,3,req,name=rtp" json:"rtp,omitempty"`                              // 购买理论返还率（万分比）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotBuyFeature) Reset() {
+	*x = PSlotBuyFeature{}
+	mi := &file_proto_slot_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotBuyFeature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotBuyFeature) ProtoMessage() {}
+
+func (x *PSlotBuyFeature) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotBuyFeature.ProtoReflect.Descriptor instead.
+func (*PSlotBuyFeature) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PSlotBuyFeature) GetPriceRate() uint32 {
+	if x != nil && x.PriceRate != nil {
+		return *x.PriceRate
+	}
+	return 0
+}
+
+func (x *PSlotBuyFeature) GetFreeSpins() uint32 {
+	if x != nil && x.FreeSpins != nil {
+		return *x.FreeSpins
+	}
+	return 0
+}
+
+func (x *PSlotBuyFeature) GetRtp() uint32 {
+	if x != nil && x.Rtp != nil {
+		return *x.Rtp
+	}
+	return 0
+}
+
 type PConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DevId         *string                `protobuf:"bytes,1,req,name=dev_id,json=devId" json:"dev_id,omitempty"`
@@ -300,7 +552,7 @@ type PConfig struct {
 
 func (x *PConfig) Reset() {
 	*x = PConfig{}
-	mi := &file_proto_slot_proto_msgTypes[2]
+	mi := &file_proto_slot_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -312,7 +564,7 @@ func (x *PConfig) String() string {
 func (*PConfig) ProtoMessage() {}
 
 func (x *PConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[2]
+	mi := &file_proto_slot_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,7 +577,7 @@ func (x *PConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PConfig.ProtoReflect.Descriptor instead.
 func (*PConfig) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{2}
+	return file_proto_slot_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *PConfig) GetDevId() string {
@@ -347,13 +599,14 @@ func (x *PConfig) GetDevNo() uint32 {
 type M_1902Tos struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BetVal        *uint32                `protobuf:"varint,1,req,name=bet_val,json=betVal" json:"bet_val,omitempty"` // 下注金额
+	Ante          *bool                  `protobuf:"varint,2,opt,name=ante" json:"ante,omitempty"`                   // 是否加注（按加注比例扣费，提高免费游戏触发率）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *M_1902Tos) Reset() {
 	*x = M_1902Tos{}
-	mi := &file_proto_slot_proto_msgTypes[3]
+	mi := &file_proto_slot_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -365,7 +618,7 @@ func (x *M_1902Tos) String() string {
 func (*M_1902Tos) ProtoMessage() {}
 
 func (x *M_1902Tos) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[3]
+	mi := &file_proto_slot_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,7 +631,7 @@ func (x *M_1902Tos) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1902Tos.ProtoReflect.Descriptor instead.
 func (*M_1902Tos) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{3}
+	return file_proto_slot_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *M_1902Tos) GetBetVal() uint32 {
@@ -388,6 +641,13 @@ func (x *M_1902Tos) GetBetVal() uint32 {
 	return 0
 }
 
+func (x *M_1902Tos) GetAnte() bool {
+	if x != nil && x.Ante != nil {
+		return *x.Ante
+	}
+	return false
+}
+
 type M_1902Toc struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BetVal        *uint32                `protobuf:"varint,1,req,name=bet_val,json=betVal" json:"bet_val,omitempty"`                // 下注金额
@@ -397,13 +657,16 @@ type M_1902Toc struct {
 	CurrentFree   *uint32                `protobuf:"varint,5,req,name=current_free,json=currentFree" json:"current_free,omitempty"` // 当前第几次免费
 	TotalFree     *uint32                `protobuf:"varint,6,req,name=total_free,json=totalFree" json:"total_free,omitempty"`       // 总共免费次数
 	Result        *PSlotResult           `protobuf:"bytes,7,req,name=result" json:"result,omitempty"`                               // 结果
+	Bonus         *PSlotBonus            `protobuf:"bytes,8,opt,name=bonus" json:"bonus,omitempty"`                                 // 本手触发的奖励游戏
+	Gamble        *PSlotGamble           `protobuf:"bytes,9,opt,name=gamble" json:"gamble,omitempty"`                               // 本手可以博倍（收分前赢得金币不结算）
+	Anticipation  *PSlotAnticipation     `protobuf:"bytes,10,opt,name=anticipation" json:"anticipation,omitempty"`                  // 期待效果（没有时为空）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *M_1902Toc) Reset() {
 	*x = M_1902Toc{}
-	mi := &file_proto_slot_proto_msgTypes[4]
+	mi := &file_proto_slot_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -415,7 +678,7 @@ func (x *M_1902Toc) String() string {
 func (*M_1902Toc) ProtoMessage() {}
 
 func (x *M_1902Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[4]
+	mi := &file_proto_slot_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -428,7 +691,7 @@ func (x *M_1902Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1902Toc.ProtoReflect.Descriptor instead.
 func (*M_1902Toc) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{4}
+	return file_proto_slot_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *M_1902Toc) GetBetVal() uint32 {
@@ -480,33 +743,50 @@ func (x *M_1902Toc) GetResult() *PSlotResult {
 	return nil
 }
 
-type PSlotResult struct {
+func (x *M_1902Toc) GetBonus() *PSlotBonus {
+	if x != nil {
+		return x.Bonus
+	}
+	return nil
+}
+
+func (x *M_1902Toc) GetGamble() *PSlotGamble {
+	if x != nil {
+		return x.Gamble
+	}
+	return nil
+}
+
+func (x *M_1902Toc) GetAnticipation() *PSlotAnticipation {
+	if x != nil {
+		return x.Anticipation
+	}
+	return nil
+}
+
+type PSlotAnticipation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Line1         []ESlotBetType         `protobuf:"varint,1,rep,name=line1,enum=slot.ESlotBetType" json:"line1,omitempty"` // 第一行
-	Line2         []ESlotBetType         `protobuf:"varint,2,rep,name=line2,enum=slot.ESlotBetType" json:"line2,omitempty"` // 第二行
-	Line3         []ESlotBetType         `protobuf:"varint,3,rep,name=line3,enum=slot.ESlotBetType" json:"line3,omitempty"` // 第三行
-	Line4         []ESlotBetType         `protobuf:"varint,4,rep,name=line4,enum=slot.ESlotBetType" json:"line4,omitempty"` // 第四行
-	Line5         []ESlotBetType         `protobuf:"varint,5,rep,name=line5,enum=slot.ESlotBetType" json:"line5,omitempty"` // 第五行
-	Rewards       []*PSlotReward         `protobuf:"bytes,6,rep,name=rewards" json:"rewards,omitempty"`                     // 奖励
+	SlowReels     []uint32               `protobuf:"varint,1,rep,name=slow_reels,json=slowReels" json:"slow_reels,omitempty"`   // 需要慢停的卷轴（从0开始，从左到右）
+	NearMisses    []*PSlotNearMiss       `protobuf:"bytes,2,rep,name=near_misses,json=nearMisses" json:"near_misses,omitempty"` // 差一点中奖
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PSlotResult) Reset() {
-	*x = PSlotResult{}
-	mi := &file_proto_slot_proto_msgTypes[5]
+func (x *PSlotAnticipation) Reset() {
+	*x = PSlotAnticipation{}
+	mi := &file_proto_slot_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PSlotResult) String() string {
+func (x *PSlotAnticipation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PSlotResult) ProtoMessage() {}
+func (*PSlotAnticipation) ProtoMessage() {}
 
-func (x *PSlotResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[5]
+func (x *PSlotAnticipation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,55 +797,187 @@ func (x *PSlotResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PSlotResult.ProtoReflect.Descriptor instead.
-func (*PSlotResult) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use PSlotAnticipation.ProtoReflect.Descriptor instead.
+func (*PSlotAnticipation) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *PSlotResult) GetLine1() []ESlotBetType {
+func (x *PSlotAnticipation) GetSlowReels() []uint32 {
 	if x != nil {
-		return x.Line1
+		return x.SlowReels
 	}
 	return nil
 }
 
-func (x *PSlotResult) GetLine2() []ESlotBetType {
+func (x *PSlotAnticipation) GetNearMisses() []*PSlotNearMiss {
 	if x != nil {
-		return x.Line2
+		return x.NearMisses
 	}
 	return nil
 }
 
-func (x *PSlotResult) GetLine3() []ESlotBetType {
-	if x != nil {
-		return x.Line3
-	}
-	return nil
+type PSlotNearMiss struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *ESlotBetType          `protobuf:"varint,1,req,name=type,enum=slot.ESlotBetType" json:"type,omitempty"` // 符号
+	Feature       *bool                  `protobuf:"varint,2,req,name=feature" json:"feature,omitempty"`                  // 是否差一个触发符号进入免费/奖励游戏（否则为差一个卷轴连线）
+	Count         *uint32                `protobuf:"varint,3,req,name=count" json:"count,omitempty"`                      // 已出现的数量（连线时为连续卷轴数）
+	Need          *uint32                `protobuf:"varint,4,req,name=need" json:"need,omitempty"`                        // 触发或中奖所需的数量
+	Pos           []uint32               `protobuf:"varint,5,rep,name=pos" json:"pos,omitempty"`                          // 高亮位置（row*5+reel）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PSlotResult) GetLine4() []ESlotBetType {
-	if x != nil {
-		return x.Line4
-	}
-	return nil
+func (x *PSlotNearMiss) Reset() {
+	*x = PSlotNearMiss{}
+	mi := &file_proto_slot_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *PSlotResult) GetLine5() []ESlotBetType {
-	if x != nil {
-		return x.Line5
-	}
-	return nil
+func (x *PSlotNearMiss) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *PSlotResult) GetRewards() []*PSlotReward {
+func (*PSlotNearMiss) ProtoMessage() {}
+
+func (x *PSlotNearMiss) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[8]
 	if x != nil {
-		return x.Rewards
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type PSlotReward struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+// Deprecated: Use PSlotNearMiss.ProtoReflect.Descriptor instead.
+func (*PSlotNearMiss) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PSlotNearMiss) GetType() ESlotBetType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ESlotBetType_e_slot_bet_type_0
+}
+
+func (x *PSlotNearMiss) GetFeature() bool {
+	if x != nil && x.Feature != nil {
+		return *x.Feature
+	}
+	return false
+}
+
+func (x *PSlotNearMiss) GetCount() uint32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *PSlotNearMiss) GetNeed() uint32 {
+	if x != nil && x.Need != nil {
+		return *x.Need
+	}
+	return 0
+}
+
+func (x *PSlotNearMiss) GetPos() []uint32 {
+	if x != nil {
+		return x.Pos
+	}
+	return nil
+}
+
+type PSlotResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line1         []ESlotBetType         `protobuf:"varint,1,rep,name=line1,enum=slot.ESlotBetType" json:"line1,omitempty"` // 第一行
+	Line2         []ESlotBetType         `protobuf:"varint,2,rep,name=line2,enum=slot.ESlotBetType" json:"line2,omitempty"` // 第二行
+	Line3         []ESlotBetType         `protobuf:"varint,3,rep,name=line3,enum=slot.ESlotBetType" json:"line3,omitempty"` // 第三行
+	Line4         []ESlotBetType         `protobuf:"varint,4,rep,name=line4,enum=slot.ESlotBetType" json:"line4,omitempty"` // 第四行
+	Line5         []ESlotBetType         `protobuf:"varint,5,rep,name=line5,enum=slot.ESlotBetType" json:"line5,omitempty"` // 第五行
+	Rewards       []*PSlotReward         `protobuf:"bytes,6,rep,name=rewards" json:"rewards,omitempty"`                     // 奖励
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotResult) Reset() {
+	*x = PSlotResult{}
+	mi := &file_proto_slot_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotResult) ProtoMessage() {}
+
+func (x *PSlotResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotResult.ProtoReflect.Descriptor instead.
+func (*PSlotResult) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PSlotResult) GetLine1() []ESlotBetType {
+	if x != nil {
+		return x.Line1
+	}
+	return nil
+}
+
+func (x *PSlotResult) GetLine2() []ESlotBetType {
+	if x != nil {
+		return x.Line2
+	}
+	return nil
+}
+
+func (x *PSlotResult) GetLine3() []ESlotBetType {
+	if x != nil {
+		return x.Line3
+	}
+	return nil
+}
+
+func (x *PSlotResult) GetLine4() []ESlotBetType {
+	if x != nil {
+		return x.Line4
+	}
+	return nil
+}
+
+func (x *PSlotResult) GetLine5() []ESlotBetType {
+	if x != nil {
+		return x.Line5
+	}
+	return nil
+}
+
+func (x *PSlotResult) GetRewards() []*PSlotReward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+type PSlotReward struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          *ESlotBetType          `protobuf:"varint,1,req,name=type,enum=slot.ESlotBetType" json:"type,omitempty"` // 奖励类型
 	Val           *uint32                `protobuf:"varint,2,req,name=val" json:"val,omitempty"`                          // 奖励值
 	Pos           []uint32               `protobuf:"varint,3,rep,name=pos" json:"pos,omitempty"`                          // 奖励位置
@@ -575,7 +987,7 @@ type PSlotReward struct {
 
 func (x *PSlotReward) Reset() {
 	*x = PSlotReward{}
-	mi := &file_proto_slot_proto_msgTypes[6]
+	mi := &file_proto_slot_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -587,7 +999,7 @@ func (x *PSlotReward) String() string {
 func (*PSlotReward) ProtoMessage() {}
 
 func (x *PSlotReward) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[6]
+	mi := &file_proto_slot_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -600,7 +1012,7 @@ func (x *PSlotReward) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PSlotReward.ProtoReflect.Descriptor instead.
 func (*PSlotReward) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{6}
+	return file_proto_slot_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *PSlotReward) GetType() ESlotBetType {
@@ -641,7 +1053,7 @@ type M_1903Toc struct {
 
 func (x *M_1903Toc) Reset() {
 	*x = M_1903Toc{}
-	mi := &file_proto_slot_proto_msgTypes[7]
+	mi := &file_proto_slot_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -653,7 +1065,7 @@ func (x *M_1903Toc) String() string {
 func (*M_1903Toc) ProtoMessage() {}
 
 func (x *M_1903Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[7]
+	mi := &file_proto_slot_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -666,7 +1078,7 @@ func (x *M_1903Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1903Toc.ProtoReflect.Descriptor instead.
 func (*M_1903Toc) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{7}
+	return file_proto_slot_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *M_1903Toc) GetCoins() uint32 {
@@ -732,7 +1144,7 @@ type M_1904Toc struct {
 
 func (x *M_1904Toc) Reset() {
 	*x = M_1904Toc{}
-	mi := &file_proto_slot_proto_msgTypes[8]
+	mi := &file_proto_slot_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -744,7 +1156,7 @@ func (x *M_1904Toc) String() string {
 func (*M_1904Toc) ProtoMessage() {}
 
 func (x *M_1904Toc) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[8]
+	mi := &file_proto_slot_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -757,7 +1169,7 @@ func (x *M_1904Toc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use M_1904Toc.ProtoReflect.Descriptor instead.
 func (*M_1904Toc) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{8}
+	return file_proto_slot_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *M_1904Toc) GetDevId() string {
@@ -788,29 +1200,30 @@ func (x *M_1904Toc) GetJp() uint32 {
 	return 0
 }
 
-type PSlotOdds struct {
+// 奖励游戏选择
+// @name bonus_pick
+type M_1906Tos struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Odds          *uint32                `protobuf:"varint,1,req,name=odds" json:"odds,omitempty"` // 赔率
-	Val           *uint32                `protobuf:"varint,2,req,name=val" json:"val,omitempty"`   // 下注值
+	Index         *uint32                `protobuf:"varint,1,req,name=index" json:"index,omitempty"` // 选择的格子序号
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PSlotOdds) Reset() {
-	*x = PSlotOdds{}
-	mi := &file_proto_slot_proto_msgTypes[9]
+func (x *M_1906Tos) Reset() {
+	*x = M_1906Tos{}
+	mi := &file_proto_slot_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PSlotOdds) String() string {
+func (x *M_1906Tos) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PSlotOdds) ProtoMessage() {}
+func (*M_1906Tos) ProtoMessage() {}
 
-func (x *PSlotOdds) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_slot_proto_msgTypes[9]
+func (x *M_1906Tos) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -821,88 +1234,998 @@ func (x *PSlotOdds) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PSlotOdds.ProtoReflect.Descriptor instead.
-func (*PSlotOdds) Descriptor() ([]byte, []int) {
-	return file_proto_slot_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use M_1906Tos.ProtoReflect.Descriptor instead.
+func (*M_1906Tos) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PSlotOdds) GetOdds() uint32 {
-	if x != nil && x.Odds != nil {
-		return *x.Odds
+func (x *M_1906Tos) GetIndex() uint32 {
+	if x != nil && x.Index != nil {
+		return *x.Index
 	}
 	return 0
 }
 
-func (x *PSlotOdds) GetVal() uint32 {
-	if x != nil && x.Val != nil {
-		return *x.Val
+type M_1906Toc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bonus         *PSlotBonus            `protobuf:"bytes,1,req,name=bonus" json:"bonus,omitempty"` // 奖励游戏
+	Win           *uint32                `protobuf:"varint,2,req,name=win" json:"win,omitempty"`    // 本次选择赢得金币
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1906Toc) Reset() {
+	*x = M_1906Toc{}
+	mi := &file_proto_slot_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1906Toc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1906Toc) ProtoMessage() {}
+
+func (x *M_1906Toc) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1906Toc.ProtoReflect.Descriptor instead.
+func (*M_1906Toc) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *M_1906Toc) GetBonus() *PSlotBonus {
+	if x != nil {
+		return x.Bonus
+	}
+	return nil
+}
+
+func (x *M_1906Toc) GetWin() uint32 {
+	if x != nil && x.Win != nil {
+		return *x.Win
 	}
 	return 0
 }
 
-var File_proto_slot_proto protoreflect.FileDescriptor
+// 查询进行中的奖励游戏（断线重连后恢复）
+// @name bonus_info
+type M_1907Tos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_slot_proto_rawDesc = "" +
-	"\n" +
-	"\x10proto/slot.proto\x12\x04slot\"3\n" +
-	"\n" +
-	"m_1901_tos\x12%\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x11.slot.e_slot_typeR\x04type\"n\n" +
-	"\n" +
-	"m_1901_toc\x12\x17\n" +
-	"\abet_val\x18\x01 \x03(\rR\x06betVal\x12%\n" +
-	"\x04odds\x18\x02 \x03(\v2\x11.slot.p_slot_oddsR\x04odds\x12 \n" +
-	"\x03cfg\x18\x03 \x02(\v2\x0e.slot.p_configR\x03cfg\"8\n" +
-	"\bp_config\x12\x15\n" +
-	"\x06dev_id\x18\x01 \x02(\tR\x05devId\x12\x15\n" +
-	"\x06dev_no\x18\x02 \x02(\rR\x05devNo\"%\n" +
-	"\n" +
-	"m_1902_tos\x12\x17\n" +
-	"\abet_val\x18\x01 \x02(\rR\x06betVal\"\xdc\x01\n" +
-	"\n" +
-	"m_1902_toc\x12\x17\n" +
-	"\abet_val\x18\x01 \x02(\rR\x06betVal\x12\x10\n" +
-	"\x03win\x18\x02 \x02(\rR\x03win\x12\x1b\n" +
-	"\ttotal_win\x18\x03 \x02(\rR\btotalWin\x12\x17\n" +
-	"\ais_free\x18\x04 \x02(\bR\x06isFree\x12!\n" +
-	"\fcurrent_free\x18\x05 \x02(\rR\vcurrentFree\x12\x1d\n" +
-	"\n" +
-	"total_free\x18\x06 \x02(\rR\ttotalFree\x12+\n" +
-	"\x06result\x18\a \x02(\v2\x13.slot.p_slot_resultR\x06result\"\x9f\x02\n" +
-	"\rp_slot_result\x12+\n" +
-	"\x05line1\x18\x01 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line1\x12+\n" +
-	"\x05line2\x18\x02 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line2\x12+\n" +
-	"\x05line3\x18\x03 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line3\x12+\n" +
-	"\x05line4\x18\x04 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line4\x12+\n" +
-	"\x05line5\x18\x05 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line5\x12-\n" +
-	"\arewards\x18\x06 \x03(\v2\x13.slot.p_slot_rewardR\arewards\"^\n" +
-	"\rp_slot_reward\x12)\n" +
-	"\x04type\x18\x01 \x02(\x0e2\x15.slot.e_slot_bet_typeR\x04type\x12\x10\n" +
-	"\x03val\x18\x02 \x02(\rR\x03val\x12\x10\n" +
-	"\x03pos\x18\x03 \x03(\rR\x03pos\"\xa6\x01\n" +
-	"\n" +
-	"m_1903_toc\x12\x14\n" +
-	"\x05coins\x18\x01 \x02(\rR\x05coins\x12\x16\n" +
-	"\x06remain\x18\x02 \x02(\rR\x06remain\x12\x1d\n" +
-	"\n" +
-	"down_coins\x18\x03 \x02(\rR\tdownCoins\x12\x10\n" +
-	"\x03jp1\x18\x04 \x02(\rR\x03jp1\x12\x10\n" +
-	"\x03jp2\x18\x05 \x02(\rR\x03jp2\x12\x10\n" +
-	"\x03jp3\x18\x06 \x02(\rR\x03jp3\x12\x15\n" +
-	"\x06jp_all\x18\a \x02(\rR\x05jpAll\"^\n" +
-	"\n" +
-	"m_1904_toc\x12\x15\n" +
-	"\x06dev_id\x18\x01 \x02(\tR\x05devId\x12\x15\n" +
-	"\x06dev_no\x18\x02 \x02(\rR\x05devNo\x12\x12\n" +
-	"\x04type\x18\x03 \x02(\rR\x04type\x12\x0e\n" +
-	"\x02jp\x18\x04 \x02(\rR\x02jp\"3\n" +
+func (x *M_1907Tos) Reset() {
+	*x = M_1907Tos{}
+	mi := &file_proto_slot_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1907Tos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1907Tos) ProtoMessage() {}
+
+func (x *M_1907Tos) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1907Tos.ProtoReflect.Descriptor instead.
+func (*M_1907Tos) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{15}
+}
+
+type M_1907Toc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bonus         *PSlotBonus            `protobuf:"bytes,1,opt,name=bonus" json:"bonus,omitempty"` // 奖励游戏（没有进行中的奖励游戏时为空）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1907Toc) Reset() {
+	*x = M_1907Toc{}
+	mi := &file_proto_slot_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1907Toc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1907Toc) ProtoMessage() {}
+
+func (x *M_1907Toc) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1907Toc.ProtoReflect.Descriptor instead.
+func (*M_1907Toc) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *M_1907Toc) GetBonus() *PSlotBonus {
+	if x != nil {
+		return x.Bonus
+	}
+	return nil
+}
+
+type PSlotBonus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *string                `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`                              // 奖励游戏id
+	BetVal        *uint32                `protobuf:"varint,2,req,name=bet_val,json=betVal" json:"bet_val,omitempty"`       // 触发时的下注金额
+	Tiles         []*PSlotBonusTile      `protobuf:"bytes,3,rep,name=tiles" json:"tiles,omitempty"`                        // 格子
+	Picks         *uint32                `protobuf:"varint,4,req,name=picks" json:"picks,omitempty"`                       // 已选择次数
+	MaxPicks      *uint32                `protobuf:"varint,5,req,name=max_picks,json=maxPicks" json:"max_picks,omitempty"` // 最多选择次数
+	TotalWin      *uint32                `protobuf:"varint,6,req,name=total_win,json=totalWin" json:"total_win,omitempty"` // 累计赢得金币
+	Finished      *bool                  `protobuf:"varint,7,req,name=finished" json:"finished,omitempty"`                 // 是否已结束
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotBonus) Reset() {
+	*x = PSlotBonus{}
+	mi := &file_proto_slot_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotBonus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotBonus) ProtoMessage() {}
+
+func (x *PSlotBonus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotBonus.ProtoReflect.Descriptor instead.
+func (*PSlotBonus) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PSlotBonus) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *PSlotBonus) GetBetVal() uint32 {
+	if x != nil && x.BetVal != nil {
+		return *x.BetVal
+	}
+	return 0
+}
+
+func (x *PSlotBonus) GetTiles() []*PSlotBonusTile {
+	if x != nil {
+		return x.Tiles
+	}
+	return nil
+}
+
+func (x *PSlotBonus) GetPicks() uint32 {
+	if x != nil && x.Picks != nil {
+		return *x.Picks
+	}
+	return 0
+}
+
+func (x *PSlotBonus) GetMaxPicks() uint32 {
+	if x != nil && x.MaxPicks != nil {
+		return *x.MaxPicks
+	}
+	return 0
+}
+
+func (x *PSlotBonus) GetTotalWin() uint32 {
+	if x != nil && x.TotalWin != nil {
+		return *x.TotalWin
+	}
+	return 0
+}
+
+func (x *PSlotBonus) GetFinished() bool {
+	if x != nil && x.Finished != nil {
+		return *x.Finished
+	}
+	return false
+}
+
+type PSlotBonusTile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         *uint32                `protobuf:"varint,1,req,name=index" json:"index,omitempty"`                        // 格子序号
+	Picked        *bool                  `protobuf:"varint,2,req,name=picked" json:"picked,omitempty"`                      // 是否被玩家选中
+	Type          *ESlotBonusTile        `protobuf:"varint,3,opt,name=type,enum=slot.ESlotBonusTile" json:"type,omitempty"` // 格子类型（未揭开时为空）
+	Val           *uint32                `protobuf:"varint,4,opt,name=val" json:"val,omitempty"`                            // 奖金格的奖金
+	Win           *uint32                `protobuf:"varint,5,opt,name=win" json:"win,omitempty"`                            // 计入的奖金
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotBonusTile) Reset() {
+	*x = PSlotBonusTile{}
+	mi := &file_proto_slot_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotBonusTile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotBonusTile) ProtoMessage() {}
+
+func (x *PSlotBonusTile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotBonusTile.ProtoReflect.Descriptor instead.
+func (*PSlotBonusTile) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PSlotBonusTile) GetIndex() uint32 {
+	if x != nil && x.Index != nil {
+		return *x.Index
+	}
+	return 0
+}
+
+func (x *PSlotBonusTile) GetPicked() bool {
+	if x != nil && x.Picked != nil {
+		return *x.Picked
+	}
+	return false
+}
+
+func (x *PSlotBonusTile) GetType() ESlotBonusTile {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ESlotBonusTile_e_slot_bonus_tile_credit
+}
+
+func (x *PSlotBonusTile) GetVal() uint32 {
+	if x != nil && x.Val != nil {
+		return *x.Val
+	}
+	return 0
+}
+
+func (x *PSlotBonusTile) GetWin() uint32 {
+	if x != nil && x.Win != nil {
+		return *x.Win
+	}
+	return 0
+}
+
+// 博倍竞猜（猜中翻倍，猜错清零）
+// @name gamble
+type M_1908Tos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Guess         *ESlotGambleGuess      `protobuf:"varint,1,req,name=guess,enum=slot.ESlotGambleGuess" json:"guess,omitempty"` // 竞猜选项（红黑为猜红黑，大小为猜大小）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1908Tos) Reset() {
+	*x = M_1908Tos{}
+	mi := &file_proto_slot_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1908Tos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1908Tos) ProtoMessage() {}
+
+func (x *M_1908Tos) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1908Tos.ProtoReflect.Descriptor instead.
+func (*M_1908Tos) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *M_1908Tos) GetGuess() ESlotGambleGuess {
+	if x != nil && x.Guess != nil {
+		return *x.Guess
+	}
+	return ESlotGambleGuess_e_slot_gamble_guess_red
+}
+
+type M_1908Toc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gamble        *PSlotGamble           `protobuf:"bytes,1,req,name=gamble" json:"gamble,omitempty"` // 博倍
+	Card          *PSlotGambleCard       `protobuf:"bytes,2,req,name=card" json:"card,omitempty"`     // 翻开的牌
+	Won           *bool                  `protobuf:"varint,3,req,name=won" json:"won,omitempty"`      // 是否猜中
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1908Toc) Reset() {
+	*x = M_1908Toc{}
+	mi := &file_proto_slot_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1908Toc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1908Toc) ProtoMessage() {}
+
+func (x *M_1908Toc) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1908Toc.ProtoReflect.Descriptor instead.
+func (*M_1908Toc) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *M_1908Toc) GetGamble() *PSlotGamble {
+	if x != nil {
+		return x.Gamble
+	}
+	return nil
+}
+
+func (x *M_1908Toc) GetCard() *PSlotGambleCard {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+func (x *M_1908Toc) GetWon() bool {
+	if x != nil && x.Won != nil {
+		return *x.Won
+	}
+	return false
+}
+
+// 博倍收分
+// @name gamble_collect
+type M_1909Tos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1909Tos) Reset() {
+	*x = M_1909Tos{}
+	mi := &file_proto_slot_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1909Tos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1909Tos) ProtoMessage() {}
+
+func (x *M_1909Tos) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1909Tos.ProtoReflect.Descriptor instead.
+func (*M_1909Tos) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{21}
+}
+
+type M_1909Toc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Win           *uint32                `protobuf:"varint,1,req,name=win" json:"win,omitempty"` // 收取的金币
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1909Toc) Reset() {
+	*x = M_1909Toc{}
+	mi := &file_proto_slot_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1909Toc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1909Toc) ProtoMessage() {}
+
+func (x *M_1909Toc) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1909Toc.ProtoReflect.Descriptor instead.
+func (*M_1909Toc) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *M_1909Toc) GetWin() uint32 {
+	if x != nil && x.Win != nil {
+		return *x.Win
+	}
+	return 0
+}
+
+// 购买免费游戏（扣费后直接进入免费游戏，之后的开始游戏为免费旋转）
+// @name buy_feature
+type M_1910Tos struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BetVal        *uint32                `protobuf:"varint,1,req,name=bet_val,json=betVal" json:"bet_val,omitempty"` // 下注金额（免费旋转按该金额派彩）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1910Tos) Reset() {
+	*x = M_1910Tos{}
+	mi := &file_proto_slot_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1910Tos) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1910Tos) ProtoMessage() {}
+
+func (x *M_1910Tos) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1910Tos.ProtoReflect.Descriptor instead.
+func (*M_1910Tos) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *M_1910Tos) GetBetVal() uint32 {
+	if x != nil && x.BetVal != nil {
+		return *x.BetVal
+	}
+	return 0
+}
+
+type M_1910Toc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BetVal        *uint32                `protobuf:"varint,1,req,name=bet_val,json=betVal" json:"bet_val,omitempty"`          // 下注金额
+	Price         *uint32                `protobuf:"varint,2,req,name=price" json:"price,omitempty"`                          // 实际扣费
+	TotalFree     *uint32                `protobuf:"varint,3,req,name=total_free,json=totalFree" json:"total_free,omitempty"` // 获得的免费次数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *M_1910Toc) Reset() {
+	*x = M_1910Toc{}
+	mi := &file_proto_slot_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *M_1910Toc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*M_1910Toc) ProtoMessage() {}
+
+func (x *M_1910Toc) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use M_1910Toc.ProtoReflect.Descriptor instead.
+func (*M_1910Toc) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *M_1910Toc) GetBetVal() uint32 {
+	if x != nil && x.BetVal != nil {
+		return *x.BetVal
+	}
+	return 0
+}
+
+func (x *M_1910Toc) GetPrice() uint32 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *M_1910Toc) GetTotalFree() uint32 {
+	if x != nil && x.TotalFree != nil {
+		return *x.TotalFree
+	}
+	return 0
+}
+
+type PSlotGamble struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *string                `protobuf:"bytes,1,req,name=id" json:"id,omitempty"`                                       // 博倍id
+	BetVal        *uint32                `protobuf:"varint,2,req,name=bet_val,json=betVal" json:"bet_val,omitempty"`                // 中奖时的下注金额
+	OriginalWin   *uint32                `protobuf:"varint,3,req,name=original_win,json=originalWin" json:"original_win,omitempty"` // 博倍前赢得金币
+	Stake         *uint32                `protobuf:"varint,4,req,name=stake" json:"stake,omitempty"`                                // 当前可收取金币
+	Rounds        *uint32                `protobuf:"varint,5,req,name=rounds" json:"rounds,omitempty"`                              // 已竞猜次数
+	MaxRounds     *uint32                `protobuf:"varint,6,req,name=max_rounds,json=maxRounds" json:"max_rounds,omitempty"`       // 最多竞猜次数
+	CanDouble     *bool                  `protobuf:"varint,7,req,name=can_double,json=canDouble" json:"can_double,omitempty"`       // 是否还能继续竞猜
+	Finished      *bool                  `protobuf:"varint,8,req,name=finished" json:"finished,omitempty"`                          // 是否已结束（猜错或已收分）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotGamble) Reset() {
+	*x = PSlotGamble{}
+	mi := &file_proto_slot_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotGamble) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotGamble) ProtoMessage() {}
+
+func (x *PSlotGamble) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotGamble.ProtoReflect.Descriptor instead.
+func (*PSlotGamble) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PSlotGamble) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *PSlotGamble) GetBetVal() uint32 {
+	if x != nil && x.BetVal != nil {
+		return *x.BetVal
+	}
+	return 0
+}
+
+func (x *PSlotGamble) GetOriginalWin() uint32 {
+	if x != nil && x.OriginalWin != nil {
+		return *x.OriginalWin
+	}
+	return 0
+}
+
+func (x *PSlotGamble) GetStake() uint32 {
+	if x != nil && x.Stake != nil {
+		return *x.Stake
+	}
+	return 0
+}
+
+func (x *PSlotGamble) GetRounds() uint32 {
+	if x != nil && x.Rounds != nil {
+		return *x.Rounds
+	}
+	return 0
+}
+
+func (x *PSlotGamble) GetMaxRounds() uint32 {
+	if x != nil && x.MaxRounds != nil {
+		return *x.MaxRounds
+	}
+	return 0
+}
+
+func (x *PSlotGamble) GetCanDouble() bool {
+	if x != nil && x.CanDouble != nil {
+		return *x.CanDouble
+	}
+	return false
+}
+
+func (x *PSlotGamble) GetFinished() bool {
+	if x != nil && x.Finished != nil {
+		return *x.Finished
+	}
+	return false
+}
+
+type PSlotGambleCard struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          *uint32                `protobuf:"varint,1,req,name=rank" json:"rank,omitempty"` // 点数 1-13
+	Suit          *uint32                `protobuf:"varint,2,req,name=suit" json:"suit,omitempty"` // 花色 0黑桃 1红桃 2梅花 3方块
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotGambleCard) Reset() {
+	*x = PSlotGambleCard{}
+	mi := &file_proto_slot_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotGambleCard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotGambleCard) ProtoMessage() {}
+
+func (x *PSlotGambleCard) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotGambleCard.ProtoReflect.Descriptor instead.
+func (*PSlotGambleCard) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PSlotGambleCard) GetRank() uint32 {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return 0
+}
+
+func (x *PSlotGambleCard) GetSuit() uint32 {
+	if x != nil && x.Suit != nil {
+		return *x.Suit
+	}
+	return 0
+}
+
+type PSlotOdds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Odds          *uint32                `protobuf:"varint,1,req,name=odds" json:"odds,omitempty"`                        // 赔率（麻将为下注倍数；法老王/777为该下注值下赔付表一项的单线/单路赢取）
+	Val           *uint32                `protobuf:"varint,2,req,name=val" json:"val,omitempty"`                          // 下注值
+	Type          *ESlotBetType          `protobuf:"varint,3,opt,name=type,enum=slot.ESlotBetType" json:"type,omitempty"` // 符号（法老王/777的赔付表项）
+	Count         *uint32                `protobuf:"varint,4,opt,name=count" json:"count,omitempty"`                      // 连续个数（法老王/777的赔付表项）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PSlotOdds) Reset() {
+	*x = PSlotOdds{}
+	mi := &file_proto_slot_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PSlotOdds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSlotOdds) ProtoMessage() {}
+
+func (x *PSlotOdds) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_slot_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSlotOdds.ProtoReflect.Descriptor instead.
+func (*PSlotOdds) Descriptor() ([]byte, []int) {
+	return file_proto_slot_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PSlotOdds) GetOdds() uint32 {
+	if x != nil && x.Odds != nil {
+		return *x.Odds
+	}
+	return 0
+}
+
+func (x *PSlotOdds) GetVal() uint32 {
+	if x != nil && x.Val != nil {
+		return *x.Val
+	}
+	return 0
+}
+
+func (x *PSlotOdds) GetType() ESlotBetType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ESlotBetType_e_slot_bet_type_0
+}
+
+func (x *PSlotOdds) GetCount() uint32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+var File_proto_slot_proto protoreflect.FileDescriptor
+
+const file_proto_slot_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/slot.proto\x12\x04slot\"3\n" +
+	"\n" +
+	"m_1901_tos\x12%\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x11.slot.e_slot_typeR\x04type\"\xd0\x01\n" +
+	"\n" +
+	"m_1901_toc\x12\x17\n" +
+	"\abet_val\x18\x01 \x03(\rR\x06betVal\x12%\n" +
+	"\x04odds\x18\x02 \x03(\v2\x11.slot.p_slot_oddsR\x04odds\x12 \n" +
+	"\x03cfg\x18\x03 \x02(\v2\x0e.slot.p_configR\x03cfg\x12%\n" +
+	"\x04ante\x18\x04 \x01(\v2\x11.slot.p_slot_anteR\x04ante\x129\n" +
+	"\vbuy_feature\x18\x05 \x01(\v2\x18.slot.p_slot_buy_featureR\n" +
+	"buyFeature\"<\n" +
+	"\vp_slot_ante\x12\x1b\n" +
+	"\tcost_rate\x18\x01 \x02(\rR\bcostRate\x12\x10\n" +
+	"\x03rtp\x18\x02 \x02(\rR\x03rtp\"d\n" +
+	"\x12p_slot_buy_feature\x12\x1d\n" +
+	"\n" +
+	"price_rate\x18\x01 \x02(\rR\tpriceRate\x12\x1d\n" +
+	"\n" +
+	"free_spins\x18\x02 \x02(\rR\tfreeSpins\x12\x10\n" +
+	"\x03rtp\x18\x03 \x02(\rR\x03rtp\"8\n" +
+	"\bp_config\x12\x15\n" +
+	"\x06dev_id\x18\x01 \x02(\tR\x05devId\x12\x15\n" +
+	"\x06dev_no\x18\x02 \x02(\rR\x05devNo\"9\n" +
+	"\n" +
+	"m_1902_tos\x12\x17\n" +
+	"\abet_val\x18\x01 \x02(\rR\x06betVal\x12\x12\n" +
+	"\x04ante\x18\x02 \x01(\bR\x04ante\"\xf2\x02\n" +
+	"\n" +
+	"m_1902_toc\x12\x17\n" +
+	"\abet_val\x18\x01 \x02(\rR\x06betVal\x12\x10\n" +
+	"\x03win\x18\x02 \x02(\rR\x03win\x12\x1b\n" +
+	"\ttotal_win\x18\x03 \x02(\rR\btotalWin\x12\x17\n" +
+	"\ais_free\x18\x04 \x02(\bR\x06isFree\x12!\n" +
+	"\fcurrent_free\x18\x05 \x02(\rR\vcurrentFree\x12\x1d\n" +
+	"\n" +
+	"total_free\x18\x06 \x02(\rR\ttotalFree\x12+\n" +
+	"\x06result\x18\a \x02(\v2\x13.slot.p_slot_resultR\x06result\x12(\n" +
+	"\x05bonus\x18\b \x01(\v2\x12.slot.p_slot_bonusR\x05bonus\x12+\n" +
+	"\x06gamble\x18\t \x01(\v2\x13.slot.p_slot_gambleR\x06gamble\x12=\n" +
+	"\fanticipation\x18\n" +
+	" \x01(\v2\x19.slot.p_slot_anticipationR\fanticipation\"m\n" +
+	"\x13p_slot_anticipation\x12\x1d\n" +
+	"\n" +
+	"slow_reels\x18\x01 \x03(\rR\tslowReels\x127\n" +
+	"\vnear_misses\x18\x02 \x03(\v2\x16.slot.p_slot_near_missR\n" +
+	"nearMisses\"\x93\x01\n" +
+	"\x10p_slot_near_miss\x12)\n" +
+	"\x04type\x18\x01 \x02(\x0e2\x15.slot.e_slot_bet_typeR\x04type\x12\x18\n" +
+	"\afeature\x18\x02 \x02(\bR\afeature\x12\x14\n" +
+	"\x05count\x18\x03 \x02(\rR\x05count\x12\x12\n" +
+	"\x04need\x18\x04 \x02(\rR\x04need\x12\x10\n" +
+	"\x03pos\x18\x05 \x03(\rR\x03pos\"\x9f\x02\n" +
+	"\rp_slot_result\x12+\n" +
+	"\x05line1\x18\x01 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line1\x12+\n" +
+	"\x05line2\x18\x02 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line2\x12+\n" +
+	"\x05line3\x18\x03 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line3\x12+\n" +
+	"\x05line4\x18\x04 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line4\x12+\n" +
+	"\x05line5\x18\x05 \x03(\x0e2\x15.slot.e_slot_bet_typeR\x05line5\x12-\n" +
+	"\arewards\x18\x06 \x03(\v2\x13.slot.p_slot_rewardR\arewards\"^\n" +
+	"\rp_slot_reward\x12)\n" +
+	"\x04type\x18\x01 \x02(\x0e2\x15.slot.e_slot_bet_typeR\x04type\x12\x10\n" +
+	"\x03val\x18\x02 \x02(\rR\x03val\x12\x10\n" +
+	"\x03pos\x18\x03 \x03(\rR\x03pos\"\xa6\x01\n" +
+	"\n" +
+	"m_1903_toc\x12\x14\n" +
+	"\x05coins\x18\x01 \x02(\rR\x05coins\x12\x16\n" +
+	"\x06remain\x18\x02 \x02(\rR\x06remain\x12\x1d\n" +
+	"\n" +
+	"down_coins\x18\x03 \x02(\rR\tdownCoins\x12\x10\n" +
+	"\x03jp1\x18\x04 \x02(\rR\x03jp1\x12\x10\n" +
+	"\x03jp2\x18\x05 \x02(\rR\x03jp2\x12\x10\n" +
+	"\x03jp3\x18\x06 \x02(\rR\x03jp3\x12\x15\n" +
+	"\x06jp_all\x18\a \x02(\rR\x05jpAll\"^\n" +
+	"\n" +
+	"m_1904_toc\x12\x15\n" +
+	"\x06dev_id\x18\x01 \x02(\tR\x05devId\x12\x15\n" +
+	"\x06dev_no\x18\x02 \x02(\rR\x05devNo\x12\x12\n" +
+	"\x04type\x18\x03 \x02(\rR\x04type\x12\x0e\n" +
+	"\x02jp\x18\x04 \x02(\rR\x02jp\"\"\n" +
+	"\n" +
+	"m_1906_tos\x12\x14\n" +
+	"\x05index\x18\x01 \x02(\rR\x05index\"H\n" +
+	"\n" +
+	"m_1906_toc\x12(\n" +
+	"\x05bonus\x18\x01 \x02(\v2\x12.slot.p_slot_bonusR\x05bonus\x12\x10\n" +
+	"\x03win\x18\x02 \x02(\rR\x03win\"\f\n" +
+	"\n" +
+	"m_1907_tos\"6\n" +
+	"\n" +
+	"m_1907_toc\x12(\n" +
+	"\x05bonus\x18\x01 \x01(\v2\x12.slot.p_slot_bonusR\x05bonus\"\xd2\x01\n" +
+	"\fp_slot_bonus\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\tR\x02id\x12\x17\n" +
+	"\abet_val\x18\x02 \x02(\rR\x06betVal\x12-\n" +
+	"\x05tiles\x18\x03 \x03(\v2\x17.slot.p_slot_bonus_tileR\x05tiles\x12\x14\n" +
+	"\x05picks\x18\x04 \x02(\rR\x05picks\x12\x1b\n" +
+	"\tmax_picks\x18\x05 \x02(\rR\bmaxPicks\x12\x1b\n" +
+	"\ttotal_win\x18\x06 \x02(\rR\btotalWin\x12\x1a\n" +
+	"\bfinished\x18\a \x02(\bR\bfinished\"\x92\x01\n" +
+	"\x11p_slot_bonus_tile\x12\x14\n" +
+	"\x05index\x18\x01 \x02(\rR\x05index\x12\x16\n" +
+	"\x06picked\x18\x02 \x02(\bR\x06picked\x12+\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x17.slot.e_slot_bonus_tileR\x04type\x12\x10\n" +
+	"\x03val\x18\x04 \x01(\rR\x03val\x12\x10\n" +
+	"\x03win\x18\x05 \x01(\rR\x03win\"=\n" +
+	"\n" +
+	"m_1908_tos\x12/\n" +
+	"\x05guess\x18\x01 \x02(\x0e2\x19.slot.e_slot_gamble_guessR\x05guess\"y\n" +
+	"\n" +
+	"m_1908_toc\x12+\n" +
+	"\x06gamble\x18\x01 \x02(\v2\x13.slot.p_slot_gambleR\x06gamble\x12,\n" +
+	"\x04card\x18\x02 \x02(\v2\x18.slot.p_slot_gamble_cardR\x04card\x12\x10\n" +
+	"\x03won\x18\x03 \x02(\bR\x03won\"\f\n" +
+	"\n" +
+	"m_1909_tos\"\x1e\n" +
+	"\n" +
+	"m_1909_toc\x12\x10\n" +
+	"\x03win\x18\x01 \x02(\rR\x03win\"%\n" +
+	"\n" +
+	"m_1910_tos\x12\x17\n" +
+	"\abet_val\x18\x01 \x02(\rR\x06betVal\"Z\n" +
+	"\n" +
+	"m_1910_toc\x12\x17\n" +
+	"\abet_val\x18\x01 \x02(\rR\x06betVal\x12\x14\n" +
+	"\x05price\x18\x02 \x02(\rR\x05price\x12\x1d\n" +
+	"\n" +
+	"total_free\x18\x03 \x02(\rR\ttotalFree\"\xe3\x01\n" +
+	"\rp_slot_gamble\x12\x0e\n" +
+	"\x02id\x18\x01 \x02(\tR\x02id\x12\x17\n" +
+	"\abet_val\x18\x02 \x02(\rR\x06betVal\x12!\n" +
+	"\foriginal_win\x18\x03 \x02(\rR\voriginalWin\x12\x14\n" +
+	"\x05stake\x18\x04 \x02(\rR\x05stake\x12\x16\n" +
+	"\x06rounds\x18\x05 \x02(\rR\x06rounds\x12\x1d\n" +
+	"\n" +
+	"max_rounds\x18\x06 \x02(\rR\tmaxRounds\x12\x1d\n" +
+	"\n" +
+	"can_double\x18\a \x02(\bR\tcanDouble\x12\x1a\n" +
+	"\bfinished\x18\b \x02(\bR\bfinished\"<\n" +
+	"\x12p_slot_gamble_card\x12\x12\n" +
+	"\x04rank\x18\x01 \x02(\rR\x04rank\x12\x12\n" +
+	"\x04suit\x18\x02 \x02(\rR\x04suit\"t\n" +
 	"\vp_slot_odds\x12\x12\n" +
 	"\x04odds\x18\x01 \x02(\rR\x04odds\x12\x10\n" +
-	"\x03val\x18\x02 \x02(\rR\x03val*T\n" +
+	"\x03val\x18\x02 \x02(\rR\x03val\x12)\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x15.slot.e_slot_bet_typeR\x04type\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\rR\x05count*T\n" +
 	"\ve_slot_type\x12\x17\n" +
 	"\x13e_slot_type_mahjong\x10\x01\x12\x17\n" +
 	"\x13e_slot_type_pharaoh\x10\x02\x12\x13\n" +
-	"\x0fe_slot_type_777\x10\x03*\xdd\x03\n" +
+	"\x0fe_slot_type_777\x10\x03*k\n" +
+	"\x11e_slot_bonus_tile\x12\x1c\n" +
+	"\x18e_slot_bonus_tile_credit\x10\x01\x12\x1d\n" +
+	"\x19e_slot_bonus_tile_collect\x10\x02\x12\x19\n" +
+	"\x15e_slot_bonus_tile_end\x10\x03*\x8c\x01\n" +
+	"\x13e_slot_gamble_guess\x12\x1b\n" +
+	"\x17e_slot_gamble_guess_red\x10\x01\x12\x1d\n" +
+	"\x19e_slot_gamble_guess_black\x10\x02\x12\x1c\n" +
+	"\x18e_slot_gamble_guess_high\x10\x03\x12\x1b\n" +
+	"\x17e_slot_gamble_guess_low\x10\x04*\xf8\x03\n" +
 	"\x0fe_slot_bet_type\x12\x15\n" +
 	"\x11e_slot_bet_type_0\x10\x00\x12\x15\n" +
 	"\x11e_slot_bet_type_1\x10\x01\x12\x15\n" +
@@ -913,7 +2236,9 @@ const file_proto_slot_proto_rawDesc = "" +
 	"\x11e_slot_bet_type_6\x10\x06\x12\x15\n" +
 	"\x11e_slot_bet_type_7\x10\a\x12\x18\n" +
 	"\x14e_slot_bet_type_wild\x10\b\x12\x18\n" +
-	"\x14e_slot_bet_type_free\x10\t\x12\x1a\n" +
+	"\x14e_slot_bet_type_free\x10\t\x12\x19\n" +
+	"\x15e_slot_bet_type_bonus\x10\n" +
+	"\x12\x1a\n" +
 	"\x16e_slot_bet_type_gold_0\x10\x10\x12\x1a\n" +
 	"\x16e_slot_bet_type_gold_1\x10\x11\x12\x1a\n" +
 	"\x16e_slot_bet_type_gold_2\x10\x12\x12\x1a\n" +
@@ -935,39 +2260,74 @@ func file_proto_slot_proto_rawDescGZIP() []byte {
 	return file_proto_slot_proto_rawDescData
 }
 
-var file_proto_slot_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_slot_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_slot_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_slot_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_proto_slot_proto_goTypes = []any{
-	(ESlotType)(0),      // 0: slot.e_slot_type
-	(ESlotBetType)(0),   // 1: slot.e_slot_bet_type
-	(*M_1901Tos)(nil),   // 2: slot.m_1901_tos
-	(*M_1901Toc)(nil),   // 3: slot.m_1901_toc
-	(*PConfig)(nil),     // 4: slot.p_config
-	(*M_1902Tos)(nil),   // 5: slot.m_1902_tos
-	(*M_1902Toc)(nil),   // 6: slot.m_1902_toc
-	(*PSlotResult)(nil), // 7: slot.p_slot_result
-	(*PSlotReward)(nil), // 8: slot.p_slot_reward
-	(*M_1903Toc)(nil),   // 9: slot.m_1903_toc
-	(*M_1904Toc)(nil),   // 10: slot.m_1904_toc
-	(*PSlotOdds)(nil),   // 11: slot.p_slot_odds
+	(ESlotType)(0),            // 0: slot.e_slot_type
+	(ESlotBonusTile)(0),       // 1: slot.e_slot_bonus_tile
+	(ESlotGambleGuess)(0),     // 2: slot.e_slot_gamble_guess
+	(ESlotBetType)(0),         // 3: slot.e_slot_bet_type
+	(*M_1901Tos)(nil),         // 4: slot.m_1901_tos
+	(*M_1901Toc)(nil),         // 5: slot.m_1901_toc
+	(*PSlotAnte)(nil),         // 6: slot.p_slot_ante
+	(*PSlotBuyFeature)(nil),   // 7: slot.p_slot_buy_feature
+	(*PConfig)(nil),           // 8: slot.p_config
+	(*M_1902Tos)(nil),         // 9: slot.m_1902_tos
+	(*M_1902Toc)(nil),         // 10: slot.m_1902_toc
+	(*PSlotAnticipation)(nil), // 11: slot.p_slot_anticipation
+	(*PSlotNearMiss)(nil),     // 12: slot.p_slot_near_miss
+	(*PSlotResult)(nil),       // 13: slot.p_slot_result
+	(*PSlotReward)(nil),       // 14: slot.p_slot_reward
+	(*M_1903Toc)(nil),         // 15: slot.m_1903_toc
+	(*M_1904Toc)(nil),         // 16: slot.m_1904_toc
+	(*M_1906Tos)(nil),         // 17: slot.m_1906_tos
+	(*M_1906Toc)(nil),         // 18: slot.m_1906_toc
+	(*M_1907Tos)(nil),         // 19: slot.m_1907_tos
+	(*M_1907Toc)(nil),         // 20: slot.m_1907_toc
+	(*PSlotBonus)(nil),        // 21: slot.p_slot_bonus
+	(*PSlotBonusTile)(nil),    // 22: slot.p_slot_bonus_tile
+	(*M_1908Tos)(nil),         // 23: slot.m_1908_tos
+	(*M_1908Toc)(nil),         // 24: slot.m_1908_toc
+	(*M_1909Tos)(nil),         // 25: slot.m_1909_tos
+	(*M_1909Toc)(nil),         // 26: slot.m_1909_toc
+	(*M_1910Tos)(nil),         // 27: slot.m_1910_tos
+	(*M_1910Toc)(nil),         // 28: slot.m_1910_toc
+	(*PSlotGamble)(nil),       // 29: slot.p_slot_gamble
+	(*PSlotGambleCard)(nil),   // 30: slot.p_slot_gamble_card
+	(*PSlotOdds)(nil),         // 31: slot.p_slot_odds
 }
 var file_proto_slot_proto_depIdxs = []int32{
 	0,  // 0: slot.m_1901_tos.type:type_name -> slot.e_slot_type
-	11, // 1: slot.m_1901_toc.odds:type_name -> slot.p_slot_odds
-	4,  // 2: slot.m_1901_toc.cfg:type_name -> slot.p_config
-	7,  // 3: slot.m_1902_toc.result:type_name -> slot.p_slot_result
-	1,  // 4: slot.p_slot_result.line1:type_name -> slot.e_slot_bet_type
-	1,  // 5: slot.p_slot_result.line2:type_name -> slot.e_slot_bet_type
-	1,  // 6: slot.p_slot_result.line3:type_name -> slot.e_slot_bet_type
-	1,  // 7: slot.p_slot_result.line4:type_name -> slot.e_slot_bet_type
-	1,  // 8: slot.p_slot_result.line5:type_name -> slot.e_slot_bet_type
-	8,  // 9: slot.p_slot_result.rewards:type_name -> slot.p_slot_reward
-	1,  // 10: slot.p_slot_reward.type:type_name -> slot.e_slot_bet_type
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	31, // 1: slot.m_1901_toc.odds:type_name -> slot.p_slot_odds
+	8,  // 2: slot.m_1901_toc.cfg:type_name -> slot.p_config
+	6,  // 3: slot.m_1901_toc.ante:type_name -> slot.p_slot_ante
+	7,  // 4: slot.m_1901_toc.buy_feature:type_name -> slot.p_slot_buy_feature
+	13, // 5: slot.m_1902_toc.result:type_name -> slot.p_slot_result
+	21, // 6: slot.m_1902_toc.bonus:type_name -> slot.p_slot_bonus
+	29, // 7: slot.m_1902_toc.gamble:type_name -> slot.p_slot_gamble
+	11, // 8: slot.m_1902_toc.anticipation:type_name -> slot.p_slot_anticipation
+	12, // 9: slot.p_slot_anticipation.near_misses:type_name -> slot.p_slot_near_miss
+	3,  // 10: slot.p_slot_near_miss.type:type_name -> slot.e_slot_bet_type
+	3,  // 11: slot.p_slot_result.line1:type_name -> slot.e_slot_bet_type
+	3,  // 12: slot.p_slot_result.line2:type_name -> slot.e_slot_bet_type
+	3,  // 13: slot.p_slot_result.line3:type_name -> slot.e_slot_bet_type
+	3,  // 14: slot.p_slot_result.line4:type_name -> slot.e_slot_bet_type
+	3,  // 15: slot.p_slot_result.line5:type_name -> slot.e_slot_bet_type
+	14, // 16: slot.p_slot_result.rewards:type_name -> slot.p_slot_reward
+	3,  // 17: slot.p_slot_reward.type:type_name -> slot.e_slot_bet_type
+	21, // 18: slot.m_1906_toc.bonus:type_name -> slot.p_slot_bonus
+	21, // 19: slot.m_1907_toc.bonus:type_name -> slot.p_slot_bonus
+	22, // 20: slot.p_slot_bonus.tiles:type_name -> slot.p_slot_bonus_tile
+	1,  // 21: slot.p_slot_bonus_tile.type:type_name -> slot.e_slot_bonus_tile
+	2,  // 22: slot.m_1908_tos.guess:type_name -> slot.e_slot_gamble_guess
+	29, // 23: slot.m_1908_toc.gamble:type_name -> slot.p_slot_gamble
+	30, // 24: slot.m_1908_toc.card:type_name -> slot.p_slot_gamble_card
+	3,  // 25: slot.p_slot_odds.type:type_name -> slot.e_slot_bet_type
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_proto_slot_proto_init() }
@@ -980,8 +2340,8 @@ func file_proto_slot_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_slot_proto_rawDesc), len(file_proto_slot_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   10,
+			NumEnums:      4,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/wfunc/slot-game/internal/models"
+	"gorm.io/gorm"
+)
+
+// ActivityRepository 动物活动场记录仓库
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository 创建动物活动场记录仓库
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// SaveSettlement 保存活动记录并把排名奖励发放到玩家钱包，返回参与玩家结算后的游戏币（userID -> coins）
+// 记录、奖励和交易流水在同一个事务中；没有钱包的玩家只记录名次，不发放奖励
+func (r *ActivityRepository) SaveSettlement(record *models.ActivityRecord) (map[uint]int64, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return fmt.Errorf("保存活动记录失败: %w", err)
+		}
+
+		var totalReward int64
+		for i := range record.Rankings {
+			ranking := &record.Rankings[i]
+			if ranking.Reward <= 0 || ranking.UserID == 0 {
+				continue
+			}
+
+			var wallet models.Wallet
+			if err := tx.Where("user_id = ?", ranking.UserID).First(&wallet).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return fmt.Errorf("查询钱包失败: %w", err)
+			}
+			if err := tx.Model(&models.Wallet{}).
+				Where("user_id = ?", ranking.UserID).
+				Update("coins", gorm.Expr("coins + ?", ranking.Reward)).Error; err != nil {
+				return fmt.Errorf("发放活动奖励失败: %w", err)
+			}
+
+			transaction := &models.Transaction{
+				UserID:        ranking.UserID,
+				OrderNo:       fmt.Sprintf("ACT-%d-%d", record.ID, ranking.ID),
+				Type:          "bonus",
+				SubType:       models.BonusSubTypeActivityRank,
+				Amount:        ranking.Reward,
+				BeforeBalance: wallet.Coins,
+				AfterBalance:  wallet.Coins + ranking.Reward,
+				RefType:       "animal_activity",
+				RefID:         strconv.FormatUint(uint64(record.ID), 10),
+				Description:   fmt.Sprintf("动物活动场第%d名奖励", ranking.Rank),
+				Status:        "success",
+			}
+			if err := tx.Create(transaction).Error; err != nil {
+				return fmt.Errorf("记录交易失败: %w", err)
+			}
+			if err := tx.Model(ranking).Update("paid", true).Error; err != nil {
+				return fmt.Errorf("更新发放状态失败: %w", err)
+			}
+			totalReward += ranking.Reward
+		}
+
+		record.TotalReward = totalReward
+		return tx.Model(record).Update("total_reward", totalReward).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, 0, len(record.Rankings))
+	for _, ranking := range record.Rankings {
+		if ranking.UserID != 0 {
+			userIDs = append(userIDs, ranking.UserID)
+		}
+	}
+	balances := make(map[uint]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return balances, nil
+	}
+
+	var wallets []models.Wallet
+	if err := r.db.Select("user_id", "coins").Where("user_id IN ?", userIDs).Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	for _, wallet := range wallets {
+		balances[wallet.UserID] = wallet.Coins
+	}
+	return balances, nil
+}
+
+// GetRecords 获取最近的活动记录（含名次）
+func (r *ActivityRepository) GetRecords(limit int) ([]models.ActivityRecord, error) {
+	var records []models.ActivityRecord
+	query := r.db.Preload("Rankings", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/models"
+)
+
+func TestActivityRepository_SaveSettlement(t *testing.T) {
+	db := TestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.ActivityRecord{}, &models.ActivityRanking{}))
+	db.Where("1 = 1").Delete(&models.ActivityRanking{})
+	db.Unscoped().Where("1 = 1").Delete(&models.ActivityRecord{})
+	repo := NewActivityRepository(db)
+
+	winner := &models.User{Username: "activity_winner", Email: "activity_winner@example.com", Phone: "13800000021", Status: "active"}
+	second := &models.User{Username: "activity_second", Email: "activity_second@example.com", Phone: "13800000022", Status: "active"}
+	require.NoError(t, db.Create(winner).Error)
+	require.NoError(t, db.Create(second).Error)
+	require.NoError(t, db.Create(&models.Wallet{UserID: winner.ID, Coins: 1000}).Error)
+
+	now := time.Now()
+	record := &models.ActivityRecord{
+		ActivityID:  7,
+		SendGold:    10000,
+		PlayerCount: 3,
+		StartedAt:   now.Add(-5 * time.Minute),
+		EndedAt:     now,
+		Rankings: []models.ActivityRanking{
+			{Rank: 1, PlayerID: uint32(winner.ID), UserID: winner.ID, Name: "winner", Score: 9000, Reward: 5000},
+			// 没有钱包的玩家只记录名次
+			{Rank: 2, PlayerID: uint32(second.ID), UserID: second.ID, Name: "second", Score: 3000, Reward: 1000},
+			{PlayerID: 9999, Name: "guest"},
+		},
+	}
+
+	balances, err := repo.SaveSettlement(record)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]int64{winner.ID: 6000}, balances)
+	assert.Equal(t, int64(5000), record.TotalReward)
+
+	var transaction models.Transaction
+	require.NoError(t, db.Where("user_id = ? AND sub_type = ?", winner.ID, models.BonusSubTypeActivityRank).First(&transaction).Error)
+	assert.Equal(t, int64(5000), transaction.Amount)
+	assert.Equal(t, int64(1000), transaction.BeforeBalance)
+	assert.Equal(t, int64(6000), transaction.AfterBalance)
+
+	records, err := repo.GetRecords(10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, uint32(7), records[0].ActivityID)
+	assert.Equal(t, int64(5000), records[0].TotalReward)
+	require.Len(t, records[0].Rankings, 3)
+	assert.True(t, records[0].Rankings[0].Paid)
+	assert.False(t, records[0].Rankings[1].Paid)
+	assert.False(t, records[0].Rankings[2].Paid)
+}
@@ -1,13 +1,18 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/models"
 	"gorm.io/gorm"
 )
 
+// ErrJackpotChanged 派奖时奖池已被其他事务改动
+var ErrJackpotChanged = errors.New("JP池已变动，请重试")
+
 // JackpotRepository JP奖池仓库
 type JackpotRepository struct {
 	db *gorm.DB
@@ -25,11 +30,12 @@ func (r *JackpotRepository) InitializeJackpots(gameID uint) error {
 		Percentage float64
 		MinAmount  int64
 		MaxAmount  int64
+		MustHitBy  bool
 	}{
-		{"JP1", 0.10, 1000, 1000000},    // JP1: 10%抽成
-		{"JP2", 0.05, 5000, 5000000},    // JP2: 5%抽成
-		{"JP3", 0.05, 10000, 10000000},  // JP3: 5%抽成
-		{"JPALL", 0.05, 20000, 20000000}, // JPALL: 5%抽成
+		{"JP1", 0.10, 1000, 1000000, false},    // JP1: 10%抽成
+		{"JP2", 0.05, 5000, 5000000, false},    // JP2: 5%抽成
+		{"JP3", 0.05, 10000, 10000000, false},  // JP3: 5%抽成
+		{"JPALL", 0.05, 20000, 20000000, true}, // JPALL: 5%抽成，必中奖池
 	}
 
 	for _, jp := range jpTypes {
@@ -45,18 +51,91 @@ func (r *JackpotRepository) InitializeJackpots(gameID uint) error {
 				MinAmount:  jp.MinAmount,
 				MaxAmount:  jp.MaxAmount,
 				Percentage: jp.Percentage,
+				MustHitBy:  jp.MustHitBy,
 				Status:     "active",
 			}
+			if jp.MustHitBy {
+				hash, seed, err := newHitCommitment(jp.MinAmount, jp.MaxAmount)
+				if err != nil {
+					return fmt.Errorf("生成JP池 %s 必中点失败: %w", jp.Type, err)
+				}
+				jackpot.HitHash = hash
+				jackpot.HitSeed = seed
+			}
 			
 			if err := r.db.Create(&jackpot).Error; err != nil {
 				return fmt.Errorf("创建JP池 %s 失败: %w", jp.Type, err)
 			}
+		} else if result.Error == nil && jp.MustHitBy && existing.HitHash == "" {
+			// 旧版本创建的奖池：启用必中模式并抽取必中点（当前金额已越过时下一次下注即中奖）
+			hash, seed, err := newHitCommitment(existing.MinAmount, existing.MaxAmount)
+			if err != nil {
+				return fmt.Errorf("生成JP池 %s 必中点失败: %w", jp.Type, err)
+			}
+			if err := r.db.Model(&existing).Updates(map[string]interface{}{
+				"must_hit_by": true,
+				"hit_hash":    hash,
+				"hit_seed":    seed,
+			}).Error; err != nil {
+				return fmt.Errorf("启用JP池 %s 必中模式失败: %w", jp.Type, err)
+			}
+		} else if result.Error == nil && existing.HitSeed != "" {
+			// 旧版本明文保存的种子改为加密保存（必中点和承诺哈希不变）
+			seed, err := slot.OpenJackpotSeed(existing.HitSeed)
+			if err != nil || seed != existing.HitSeed {
+				continue
+			}
+			sealed, err := slot.SealJackpotSeed(seed)
+			if err != nil {
+				return fmt.Errorf("加密JP池 %s 必中点种子失败: %w", jp.Type, err)
+			}
+			if err := r.db.Model(&existing).Update("hit_seed", sealed).Error; err != nil {
+				return fmt.Errorf("加密JP池 %s 必中点种子失败: %w", jp.Type, err)
+			}
 		}
 	}
 	
 	return nil
 }
 
+// newHitCommitment 抽取新的必中点，返回承诺哈希和加密后的种子
+func newHitCommitment(min, max int64) (string, string, error) {
+	commitment, err := slot.NewJackpotCommitment(min, max)
+	if err != nil {
+		return "", "", err
+	}
+	seed, err := slot.SealJackpotSeed(commitment.Seed)
+	if err != nil {
+		return "", "", err
+	}
+	return commitment.Hash, seed, nil
+}
+
+// jackpotSeed 解密必中奖池当前的种子（非必中奖池或未抽取时为空）
+func jackpotSeed(jackpot *models.Jackpot) string {
+	if !jackpot.MustHitBy || jackpot.HitSeed == "" {
+		return ""
+	}
+	seed, err := slot.OpenJackpotSeed(jackpot.HitSeed)
+	if err != nil {
+		return ""
+	}
+	return seed
+}
+
+// JackpotHitPoint 必中奖池当前的隐藏必中点（非必中奖池或未抽取时为0）
+func JackpotHitPoint(jackpot *models.Jackpot) int64 {
+	seed := jackpotSeed(jackpot)
+	if seed == "" {
+		return 0
+	}
+	hitPoint, err := slot.JackpotHitPoint(seed, jackpot.MinAmount, jackpot.MaxAmount)
+	if err != nil {
+		return 0
+	}
+	return hitPoint
+}
+
 // GetJackpot 获取指定JP池
 func (r *JackpotRepository) GetJackpot(gameID uint, jpType string) (*models.Jackpot, error) {
 	var jackpot models.Jackpot
@@ -74,6 +153,13 @@ func (r *JackpotRepository) GetAllJackpots(gameID uint) ([]models.Jackpot, error
 	return jackpots, err
 }
 
+// GetAllJackpotsTx 在事务中获取所有JP池（累计后判断彩金触发）
+func (r *JackpotRepository) GetAllJackpotsTx(tx *gorm.DB, gameID uint) ([]models.Jackpot, error) {
+	var jackpots []models.Jackpot
+	err := tx.Where("game_id = ? AND status = ?", gameID, "active").Find(&jackpots).Error
+	return jackpots, err
+}
+
 // AccumulateJackpot 累计JP奖池（事务处理）
 func (r *JackpotRepository) AccumulateJackpot(tx *gorm.DB, gameID uint, betAmount int64) error {
 	// 计算各JP池的累计金额
@@ -129,7 +215,10 @@ func (r *JackpotRepository) AccumulateJackpot(tx *gorm.DB, gameID uint, betAmoun
 	return nil
 }
 
-// WinJackpot 中JP处理
+// WinJackpot 中JP处理（事务处理）
+// 派发整个奖池并重置为种子金额（最小金额），同时记录中奖历史
+// 必中奖池在中奖历史中公开本轮的必中点和种子，并为下一轮抽取新的必中点
+// 奖池按读取时的金额条件更新，期间被其他事务改动时返回 ErrJackpotChanged，调用方回滚整个事务
 func (r *JackpotRepository) WinJackpot(tx *gorm.DB, gameID uint, userID uint, sessionID uint, resultID uint, jpType string) (int64, error) {
 	// 获取当前JP池
 	var jackpot models.Jackpot
@@ -144,17 +233,32 @@ func (r *JackpotRepository) WinJackpot(tx *gorm.DB, gameID uint, userID uint, se
 	
 	// 重置JP池到最小金额
 	now := time.Now()
-	if err := tx.Model(&jackpot).Updates(map[string]interface{}{
+	updates := map[string]interface{}{
 		"amount":      jackpot.MinAmount,
 		"last_won_at": now,
 		"last_winner": userID,
 		"win_count":   gorm.Expr("win_count + 1"),
 		"total_out":   gorm.Expr("total_out + ?", winAmount),
-	}).Error; err != nil {
-		return 0, fmt.Errorf("更新JP池失败: %w", err)
+	}
+	if jackpot.MustHitBy {
+		hash, seed, err := newHitCommitment(jackpot.MinAmount, jackpot.MaxAmount)
+		if err != nil {
+			return 0, fmt.Errorf("生成必中点失败: %w", err)
+		}
+		updates["hit_hash"] = hash
+		updates["hit_seed"] = seed
+	}
+	result := tx.Model(&models.Jackpot{}).
+		Where("id = ? AND amount = ?", jackpot.ID, poolBefore).
+		Updates(updates)
+	if result.Error != nil {
+		return 0, fmt.Errorf("更新JP池失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, ErrJackpotChanged
 	}
 	
-	// 记录中奖历史
+	// 记录中奖历史：公开解密后的种子，保存抽取必中点时的金额范围供校验
 	history := models.JackpotHistory{
 		JackpotID:  jackpot.ID,
 		UserID:     userID,
@@ -163,6 +267,11 @@ func (r *JackpotRepository) WinJackpot(tx *gorm.DB, gameID uint, userID uint, se
 		Amount:     winAmount,
 		PoolBefore: poolBefore,
 		PoolAfter:  jackpot.MinAmount,
+		MinAmount:  jackpot.MinAmount,
+		MaxAmount:  jackpot.MaxAmount,
+		HitPoint:   JackpotHitPoint(&jackpot),
+		HitSeed:    jackpotSeed(&jackpot),
+		HitHash:    jackpot.HitHash,
 		WonAt:      now,
 	}
 	
@@ -191,6 +300,15 @@ func (r *JackpotRepository) GetJackpotHistory(gameID uint, limit int) ([]models.
 	return history, err
 }
 
+// GetJackpotHistoryByID 获取单条JP中奖历史（含所属奖池，用于审计必中点）
+func (r *JackpotRepository) GetJackpotHistoryByID(id uint) (*models.JackpotHistory, error) {
+	var history models.JackpotHistory
+	if err := r.db.Preload("Jackpot").First(&history, id).Error; err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
 // GetUserJackpotHistory 获取用户JP中奖历史
 func (r *JackpotRepository) GetUserJackpotHistory(userID uint, limit int) ([]models.JackpotHistory, error) {
 	var history []models.JackpotHistory
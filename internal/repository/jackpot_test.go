@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"gorm.io/gorm"
+)
+
+func setupJackpotTest(t *testing.T) (*gorm.DB, *JackpotRepository) {
+	db := TestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Jackpot{}, &models.JackpotHistory{}))
+	db.Where("1 = 1").Delete(&models.JackpotHistory{})
+	db.Unscoped().Where("1 = 1").Delete(&models.Jackpot{})
+
+	require.NoError(t, slot.SetJackpotSeedKey("test-jackpot-secret"))
+	repo := NewJackpotRepository(db)
+	require.NoError(t, repo.InitializeJackpots(1))
+	return db, repo
+}
+
+func TestJackpotRepository_WinJackpot(t *testing.T) {
+	db, repo := setupJackpotTest(t)
+
+	var won int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := repo.AccumulateJackpot(tx, 1, 1000); err != nil {
+			return err
+		}
+		jackpots, err := repo.GetAllJackpotsTx(tx, 1)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, jackpots, 4)
+
+		won, err = repo.WinJackpot(tx, 1, 7, 0, 42, "JP1")
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1100), won)
+
+	// 奖池重置为种子金额
+	jp1, err := repo.GetJackpot(1, "JP1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), jp1.Amount)
+	assert.Equal(t, 1, jp1.WinCount)
+	assert.Equal(t, int64(1100), jp1.TotalOut)
+	assert.Equal(t, uint(7), jp1.LastWinner)
+	assert.NotNil(t, jp1.LastWonAt)
+
+	// 其他奖池不受影响
+	jp2, err := repo.GetJackpot(1, "JP2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5050), jp2.Amount)
+
+	history, err := repo.GetUserJackpotHistory(7, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, jp1.ID, history[0].JackpotID)
+	assert.Equal(t, uint(42), history[0].ResultID)
+	assert.Equal(t, int64(1100), history[0].Amount)
+	assert.Equal(t, int64(1100), history[0].PoolBefore)
+	assert.Equal(t, int64(1000), history[0].PoolAfter)
+}
+
+func TestJackpotRepository_WinJackpotRollback(t *testing.T) {
+	db, repo := setupJackpotTest(t)
+
+	// 派奖所在事务失败时奖池和历史一起回滚
+	errSpin := errors.New("spin failed")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := repo.AccumulateJackpot(tx, 1, 1000); err != nil {
+			return err
+		}
+		if _, err := repo.WinJackpot(tx, 1, 7, 0, 42, "JPALL"); err != nil {
+			return err
+		}
+		return errSpin
+	})
+	assert.ErrorIs(t, err, errSpin)
+
+	jpAll, err := repo.GetJackpot(1, "JPALL")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20000), jpAll.Amount)
+	assert.Equal(t, 0, jpAll.WinCount)
+
+	history, err := repo.GetUserJackpotHistory(7, 0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestJackpotRepository_MustHitBy(t *testing.T) {
+	db, repo := setupJackpotTest(t)
+
+	jpAll, err := repo.GetJackpot(1, "JPALL")
+	require.NoError(t, err)
+	assert.True(t, jpAll.MustHitBy)
+	assert.Len(t, jpAll.HitHash, 64)
+	hitPoint := JackpotHitPoint(jpAll)
+	assert.Greater(t, hitPoint, jpAll.MinAmount)
+	assert.LessOrEqual(t, hitPoint, jpAll.MaxAmount)
+
+	jp1, err := repo.GetJackpot(1, "JP1")
+	require.NoError(t, err)
+	assert.False(t, jp1.MustHitBy)
+	assert.Empty(t, jp1.HitHash)
+	assert.Zero(t, JackpotHitPoint(jp1))
+
+	// 派奖后公开本轮必中点和种子，并抽取新的必中点
+	committed := jpAll.HitHash
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		_, err := repo.WinJackpot(tx, 1, 7, 0, 42, "JPALL")
+		return err
+	}))
+
+	history, err := repo.GetUserJackpotHistory(7, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, hitPoint, history[0].HitPoint)
+	seed, err := slot.OpenJackpotSeed(jpAll.HitSeed)
+	require.NoError(t, err)
+	assert.NotEqual(t, seed, jpAll.HitSeed, "pool seed must be stored encrypted")
+	assert.Equal(t, seed, history[0].HitSeed)
+	assert.Equal(t, committed, history[0].HitHash)
+	assert.Equal(t, jpAll.MinAmount, history[0].MinAmount)
+	assert.Equal(t, jpAll.MaxAmount, history[0].MaxAmount)
+
+	revealed := &slot.JackpotCommitment{HitPoint: history[0].HitPoint, Seed: history[0].HitSeed}
+	assert.True(t, revealed.Verify(committed, jpAll.MinAmount, jpAll.MaxAmount))
+
+	reseeded, err := repo.GetJackpot(1, "JPALL")
+	require.NoError(t, err)
+	assert.NotEqual(t, committed, reseeded.HitHash)
+	assert.NotEqual(t, jpAll.HitSeed, reseeded.HitSeed)
+
+	found, err := repo.GetJackpotHistoryByID(history[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "JPALL", found.Jackpot.Type)
+}
+
+func TestJackpotRepository_InitializeLegacyMustHitBy(t *testing.T) {
+	db, repo := setupJackpotTest(t)
+
+	// 旧版本创建的JPALL没有必中点，初始化时补齐
+	require.NoError(t, db.Model(&models.Jackpot{}).Where("type = ?", "JPALL").
+		Updates(map[string]interface{}{"must_hit_by": false, "hit_hash": "", "hit_seed": ""}).Error)
+	require.NoError(t, repo.InitializeJackpots(1))
+
+	jpAll, err := repo.GetJackpot(1, "JPALL")
+	require.NoError(t, err)
+	assert.True(t, jpAll.MustHitBy)
+	assert.NotEmpty(t, jpAll.HitHash)
+	assert.NotZero(t, JackpotHitPoint(jpAll))
+
+	// 旧版本明文保存的种子初始化时改为加密保存，必中点不变
+	seed, err := slot.OpenJackpotSeed(jpAll.HitSeed)
+	require.NoError(t, err)
+	hitPoint := JackpotHitPoint(jpAll)
+	require.NoError(t, db.Model(&models.Jackpot{}).Where("type = ?", "JPALL").Update("hit_seed", seed).Error)
+	require.NoError(t, repo.InitializeJackpots(1))
+
+	resealed, err := repo.GetJackpot(1, "JPALL")
+	require.NoError(t, err)
+	assert.NotEqual(t, seed, resealed.HitSeed)
+	assert.Equal(t, jpAll.HitHash, resealed.HitHash)
+	assert.Equal(t, hitPoint, JackpotHitPoint(resealed))
+}
@@ -9,6 +9,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrSlotMachineNotFound 老虎机未在数据库登记
+var ErrSlotMachineNotFound = errors.New("老虎机不存在")
+
 // SlotMachineRepository 老虎机仓储接口
 type SlotMachineRepository interface {
 	BaseRepository
@@ -56,7 +59,7 @@ func (r *slotMachineRepo) FindByID(ctx context.Context, id uint) (*models.SlotMa
 	err := r.db.WithContext(ctx).First(&machine, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("老虎机不存在")
+			return nil, ErrSlotMachineNotFound
 		}
 		return nil, err
 	}
@@ -69,7 +72,7 @@ func (r *slotMachineRepo) FindByMachineID(ctx context.Context, machineID string)
 	err := r.db.WithContext(ctx).Where("machine_id = ?", machineID).First(&machine).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("老虎机不存在")
+			return nil, ErrSlotMachineNotFound
 		}
 		return nil, err
 	}
@@ -126,6 +129,7 @@ type SlotSpinRepository interface {
 	FindBySessionID(ctx context.Context, sessionID uint, pagination *Pagination) ([]*models.SlotSpin, error)
 	FindByUserID(ctx context.Context, userID uint, pagination *Pagination) ([]*models.SlotSpin, error)
 	FindByMachineID(ctx context.Context, machineID uint, pagination *Pagination) ([]*models.SlotSpin, error)
+	FindByReelSetID(ctx context.Context, machineID uint, reelSetID string, pagination *Pagination) ([]*models.SlotSpin, error)
 	GetStatistics(ctx context.Context, machineID uint, start, end time.Time) (*SpinStatistics, error)
 }
 
@@ -256,6 +260,27 @@ func (r *slotSpinRepo) FindByMachineID(ctx context.Context, machineID uint, pagi
 	return spins, err
 }
 
+// FindByReelSetID 根据卷轴组查找（审计用）
+func (r *slotSpinRepo) FindByReelSetID(ctx context.Context, machineID uint, reelSetID string, pagination *Pagination) ([]*models.SlotSpin, error) {
+	var spins []*models.SlotSpin
+	query := r.db.WithContext(ctx).Model(&models.SlotSpin{}).
+		Where("machine_id = ? AND reel_set_id = ?", machineID, reelSetID)
+	
+	// 获取总数
+	var total int64
+	query.Count(&total)
+	pagination.Total = total
+	
+	// 分页查询
+	err := query.
+		Limit(pagination.PageSize).
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Order("created_at DESC").
+		Find(&spins).Error
+	
+	return spins, err
+}
+
 // GetStatistics 获取统计数据
 func (r *slotSpinRepo) GetStatistics(ctx context.Context, machineID uint, start, end time.Time) (*SpinStatistics, error) {
 	stats := &SpinStatistics{}
@@ -325,6 +350,63 @@ func (r *slotSpinRepo) WithTx(tx *gorm.DB) BaseRepository {
 	}
 }
 
+// SlotConfigVersionRepository 老虎机配置版本仓储接口
+type SlotConfigVersionRepository interface {
+	BaseRepository
+	Save(ctx context.Context, version *models.SlotConfigVersion) error
+	FindByHash(ctx context.Context, hash string) (*models.SlotConfigVersion, error)
+	FindByMachineID(ctx context.Context, machineID string) ([]*models.SlotConfigVersion, error)
+}
+
+// slotConfigVersionRepo 老虎机配置版本仓储实现
+type slotConfigVersionRepo struct {
+	*BaseRepo
+}
+
+// NewSlotConfigVersionRepository 创建老虎机配置版本仓储
+func NewSlotConfigVersionRepository(db *gorm.DB) SlotConfigVersionRepository {
+	return &slotConfigVersionRepo{
+		BaseRepo: &BaseRepo{db: db},
+	}
+}
+
+// Save 保存配置版本（相同哈希只保存一次）
+func (r *slotConfigVersionRepo) Save(ctx context.Context, version *models.SlotConfigVersion) error {
+	return r.db.WithContext(ctx).
+		Where("hash = ?", version.Hash).
+		FirstOrCreate(version).Error
+}
+
+// FindByHash 根据哈希查找
+func (r *slotConfigVersionRepo) FindByHash(ctx context.Context, hash string) (*models.SlotConfigVersion, error) {
+	var version models.SlotConfigVersion
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("配置版本不存在")
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// FindByMachineID 根据机器ID查找全部版本（新版本在前）
+func (r *slotConfigVersionRepo) FindByMachineID(ctx context.Context, machineID string) ([]*models.SlotConfigVersion, error) {
+	var versions []*models.SlotConfigVersion
+	err := r.db.WithContext(ctx).
+		Where("machine_id = ?", machineID).
+		Order("id DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// WithTx 使用事务
+func (r *slotConfigVersionRepo) WithTx(tx *gorm.DB) BaseRepository {
+	return &slotConfigVersionRepo{
+		BaseRepo: &BaseRepo{db: tx},
+	}
+}
+
 // SlotWinLineRepository 老虎机中奖线仓储接口
 type SlotWinLineRepository interface {
 	BaseRepository
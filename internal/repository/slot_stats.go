@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slotStatDayLayout 统计日期格式
+const slotStatDayLayout = "2006-01-02"
+
+// SlotSpinStat 一次旋转计入统计的数据
+type SlotSpinStat struct {
+	MachineID      string
+	BetLevel       int64   // 下注档位（名义下注，免费旋转为触发时的下注）
+	BetAmount      int64   // 实际下注（免费旋转为0）
+	WinAmount      int64   // 赢取（不含彩金）
+	TheoreticalRTP float64 // 本次旋转的理论RTP（免费旋转的赢取已计入触发旋转的理论RTP，0为没有理论值）
+	FreeSpin       bool
+	Features       int // 触发的特殊功能数量
+	JackpotHits    int
+	JackpotWin     int64
+	PlayedAt       time.Time
+}
+
+// SlotStatFilter 统计查询条件（日期闭区间，空值为不限）
+type SlotStatFilter struct {
+	MachineID string `json:"machine_id"`
+	BetLevel  int64  `json:"bet_level"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+// SlotStatSummary 日期范围内某机台某档下注的统计汇总
+type SlotStatSummary struct {
+	MachineID       string  `json:"machine_id"`
+	BetLevel        int64   `json:"bet_level"`
+	Spins           int64   `json:"spins"`
+	PaidSpins       int64   `json:"paid_spins"`
+	TotalBet        int64   `json:"total_bet"`
+	TotalWin        int64   `json:"total_win"`
+	ExpectedWin     float64 `json:"expected_win"`
+	SumReturn       float64 `json:"-"`
+	SumReturnSq     float64 `json:"-"`
+	HitCount        int64   `json:"hit_count"`
+	FreeSpins       int64   `json:"free_spins"`
+	FeatureTriggers int64   `json:"feature_triggers"`
+	JackpotHits     int64   `json:"jackpot_hits"`
+	JackpotWin      int64   `json:"jackpot_win"`
+	MaxWin          int64   `json:"max_win"`
+	ActualRTP       float64 `json:"actual_rtp"`
+	HitFrequency    float64 `json:"hit_frequency"`
+}
+
+// sample 转换为RTP漂移检测样本
+func (s *SlotStatSummary) sample() slot.RTPDriftSample {
+	return slot.RTPDriftSample{
+		BetLevel:    s.BetLevel,
+		Spins:       s.Spins,
+		PaidSpins:   s.PaidSpins,
+		TotalBet:    s.TotalBet,
+		TotalWin:    s.TotalWin,
+		ExpectedWin: s.ExpectedWin,
+		SumReturn:   s.SumReturn,
+		SumReturnSq: s.SumReturnSq,
+	}
+}
+
+// RTPDriftReport RTP漂移报告
+type RTPDriftReport struct {
+	From            string             `json:"from"`
+	To              string             `json:"to"`
+	ConfidenceLevel float64            `json:"confidence_level"`
+	Machines        []*MachineRTPDrift `json:"machines"`
+	Alerts          []*RTPDriftAlert   `json:"alerts"`
+	GeneratedAt     time.Time          `json:"generated_at"`
+}
+
+// MachineRTPDrift 机台整体及各档下注的RTP漂移
+type MachineRTPDrift struct {
+	MachineID string              `json:"machine_id"`
+	Drift     *slot.RTPDrift      `json:"drift"`
+	BetLevels []*BetLevelRTPDrift `json:"bet_levels"`
+}
+
+// BetLevelRTPDrift 单档下注的RTP漂移
+type BetLevelRTPDrift struct {
+	BetLevel int64          `json:"bet_level"`
+	Drift    *slot.RTPDrift `json:"drift"`
+}
+
+// RTPDriftAlert RTP漂移告警
+type RTPDriftAlert struct {
+	MachineID      string  `json:"machine_id"`
+	BetLevel       int64   `json:"bet_level"` // 0为机台整体
+	Spins          int64   `json:"spins"`
+	ActualRTP      float64 `json:"actual_rtp"`
+	TheoreticalRTP float64 `json:"theoretical_rtp"`
+	Deviation      float64 `json:"deviation"`
+	Bound          float64 `json:"bound"`
+	PValue         float64 `json:"p_value"`
+	AlertLevel     string  `json:"alert_level"` // warning, critical
+	Message        string  `json:"message"`
+}
+
+// SlotStatsRepository 老虎机旋转统计仓库
+type SlotStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewSlotStatsRepository 创建老虎机旋转统计仓库
+func NewSlotStatsRepository(db *gorm.DB) *SlotStatsRepository {
+	return &SlotStatsRepository{db: db}
+}
+
+// Record 把一次旋转累加到所属机台、日期、下注档位的统计（在旋转结果的事务中调用）
+func (r *SlotStatsRepository) Record(tx *gorm.DB, stat *SlotSpinStat) error {
+	if stat.MachineID == "" || stat.BetLevel <= 0 {
+		return nil
+	}
+	playedAt := stat.PlayedAt
+	if playedAt.IsZero() {
+		playedAt = time.Now()
+	}
+	day := playedAt.Format(slotStatDayLayout)
+
+	var paid, hit, free int64
+	if stat.BetAmount > 0 {
+		paid = 1
+	}
+	if stat.WinAmount > 0 {
+		hit = 1
+	}
+	if stat.FreeSpin {
+		free = 1
+	}
+	x := float64(stat.WinAmount) / float64(stat.BetLevel)
+	expected := float64(stat.BetAmount) * stat.TheoreticalRTP
+
+	// 同一机台、日期、档位只有一行，并发旋转按唯一索引原子累加
+	bucket := &models.SlotStatBucket{
+		MachineID:       stat.MachineID,
+		Day:             day,
+		BetLevel:        stat.BetLevel,
+		Spins:           1,
+		PaidSpins:       paid,
+		TotalBet:        stat.BetAmount,
+		TotalWin:        stat.WinAmount,
+		ExpectedWin:     expected,
+		SumReturn:       x,
+		SumReturnSq:     x * x,
+		HitCount:        hit,
+		FreeSpins:       free,
+		FeatureTriggers: int64(stat.Features),
+		JackpotHits:     int64(stat.JackpotHits),
+		JackpotWin:      stat.JackpotWin,
+		MaxWin:          stat.WinAmount,
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "machine_id"}, {Name: "day"}, {Name: "bet_level"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"spins":            gorm.Expr("spins + 1"),
+			"paid_spins":       gorm.Expr("paid_spins + ?", paid),
+			"total_bet":        gorm.Expr("total_bet + ?", stat.BetAmount),
+			"total_win":        gorm.Expr("total_win + ?", stat.WinAmount),
+			"expected_win":     gorm.Expr("expected_win + ?", expected),
+			"sum_return":       gorm.Expr("sum_return + ?", x),
+			"sum_return_sq":    gorm.Expr("sum_return_sq + ?", x*x),
+			"hit_count":        gorm.Expr("hit_count + ?", hit),
+			"free_spins":       gorm.Expr("free_spins + ?", free),
+			"feature_triggers": gorm.Expr("feature_triggers + ?", stat.Features),
+			"jackpot_hits":     gorm.Expr("jackpot_hits + ?", stat.JackpotHits),
+			"jackpot_win":      gorm.Expr("jackpot_win + ?", stat.JackpotWin),
+			"max_win":          gorm.Expr("CASE WHEN max_win < ? THEN ? ELSE max_win END", stat.WinAmount, stat.WinAmount),
+			"updated_at":       time.Now(),
+		}),
+	}).Create(bucket).Error
+	if err != nil {
+		return fmt.Errorf("更新旋转统计失败: %w", err)
+	}
+	return nil
+}
+
+// GetBuckets 获取按日统计（按日期、机台、下注档位排序）
+func (r *SlotStatsRepository) GetBuckets(filter SlotStatFilter) ([]models.SlotStatBucket, error) {
+	var buckets []models.SlotStatBucket
+	err := r.query(filter).Order("day, machine_id, bet_level").Find(&buckets).Error
+	return buckets, err
+}
+
+// Summarize 按机台和下注档位汇总日期范围内的统计
+func (r *SlotStatsRepository) Summarize(filter SlotStatFilter) ([]*SlotStatSummary, error) {
+	var summaries []*SlotStatSummary
+	err := r.query(filter).
+		Select(`machine_id, bet_level,
+			SUM(spins) AS spins, SUM(paid_spins) AS paid_spins,
+			SUM(total_bet) AS total_bet, SUM(total_win) AS total_win, SUM(expected_win) AS expected_win,
+			SUM(sum_return) AS sum_return, SUM(sum_return_sq) AS sum_return_sq,
+			SUM(hit_count) AS hit_count, SUM(free_spins) AS free_spins, SUM(feature_triggers) AS feature_triggers,
+			SUM(jackpot_hits) AS jackpot_hits, SUM(jackpot_win) AS jackpot_win, MAX(max_win) AS max_win`).
+		Group("machine_id, bet_level").
+		Order("machine_id, bet_level").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range summaries {
+		if s.TotalBet > 0 {
+			s.ActualRTP = float64(s.TotalWin) / float64(s.TotalBet)
+		}
+		if s.Spins > 0 {
+			s.HitFrequency = float64(s.HitCount) / float64(s.Spins)
+		}
+	}
+	return summaries, nil
+}
+
+// GetRTPDriftReport 检测日期范围内各机台（整体及各档下注）实际RTP相对理论RTP的漂移
+// 偏离超出置信界限时告警，p值低于显著性水平的十分之一时为严重告警
+// 没有理论RTP的统计（如消除玩法）不参与检测
+func (r *SlotStatsRepository) GetRTPDriftReport(filter SlotStatFilter, confidenceLevel float64) (*RTPDriftReport, error) {
+	summaries, err := r.Summarize(filter)
+	if err != nil {
+		return nil, err
+	}
+	if confidenceLevel <= 0 || confidenceLevel >= 1 {
+		confidenceLevel = slot.DefaultDriftConfidence
+	}
+
+	report := &RTPDriftReport{
+		From:            filter.From,
+		To:              filter.To,
+		ConfidenceLevel: confidenceLevel,
+		Machines:        make([]*MachineRTPDrift, 0),
+		Alerts:          make([]*RTPDriftAlert, 0),
+		GeneratedAt:     time.Now(),
+	}
+
+	// 汇总已按机台排序
+	var machine *MachineRTPDrift
+	var samples []slot.RTPDriftSample
+	flush := func() {
+		if machine == nil {
+			return
+		}
+		machine.Drift = slot.CheckRTPDrift(samples, confidenceLevel)
+		report.addAlert(machine.MachineID, 0, machine.Drift)
+		report.Machines = append(report.Machines, machine)
+	}
+	for _, s := range summaries {
+		if s.ExpectedWin == 0 {
+			continue
+		}
+		if machine == nil || machine.MachineID != s.MachineID {
+			flush()
+			machine = &MachineRTPDrift{MachineID: s.MachineID}
+			samples = nil
+		}
+		sample := s.sample()
+		samples = append(samples, sample)
+		level := &BetLevelRTPDrift{
+			BetLevel: s.BetLevel,
+			Drift:    slot.CheckRTPDrift([]slot.RTPDriftSample{sample}, confidenceLevel),
+		}
+		report.addAlert(s.MachineID, s.BetLevel, level.Drift)
+		machine.BetLevels = append(machine.BetLevels, level)
+	}
+	flush()
+
+	return report, nil
+}
+
+// addAlert 漂移超出界限时添加告警
+func (report *RTPDriftReport) addAlert(machineID string, betLevel int64, drift *slot.RTPDrift) {
+	if !drift.Alarm {
+		return
+	}
+	level := "warning"
+	if drift.PValue < (1-drift.ConfidenceLevel)/10 {
+		level = "critical"
+	}
+	scope := "整体"
+	if betLevel > 0 {
+		scope = fmt.Sprintf("下注%d", betLevel)
+	}
+	report.Alerts = append(report.Alerts, &RTPDriftAlert{
+		MachineID:      machineID,
+		BetLevel:       betLevel,
+		Spins:          drift.Spins,
+		ActualRTP:      drift.ActualRTP,
+		TheoreticalRTP: drift.TheoreticalRTP,
+		Deviation:      drift.Deviation,
+		Bound:          drift.Bound,
+		PValue:         drift.PValue,
+		AlertLevel:     level,
+		Message: fmt.Sprintf("机台 %s %s实际RTP %.2f%% 偏离理论RTP %.2f%%，超出%.0f%%置信界限 ±%.2f%%",
+			machineID, scope, drift.ActualRTP*100, drift.TheoreticalRTP*100, drift.ConfidenceLevel*100, drift.Bound*100),
+	})
+}
+
+// query 按条件筛选统计
+func (r *SlotStatsRepository) query(filter SlotStatFilter) *gorm.DB {
+	query := r.db.Model(&models.SlotStatBucket{})
+	if filter.MachineID != "" {
+		query = query.Where("machine_id = ?", filter.MachineID)
+	}
+	if filter.BetLevel > 0 {
+		query = query.Where("bet_level = ?", filter.BetLevel)
+	}
+	if filter.From != "" {
+		query = query.Where("day >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("day <= ?", filter.To)
+	}
+	return query
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wfunc/slot-game/internal/models"
+	"gorm.io/gorm"
+)
+
+func setupSlotStatsTest(t *testing.T) (*gorm.DB, *SlotStatsRepository) {
+	db := TestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.SlotStatBucket{}))
+	db.Where("1 = 1").Delete(&models.SlotStatBucket{})
+	return db, NewSlotStatsRepository(db)
+}
+
+func TestSlotStatsRepository_Record(t *testing.T) {
+	db, repo := setupSlotStatsTest(t)
+
+	day1 := time.Date(2026, 10, 1, 10, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	spins := []*SlotSpinStat{
+		{MachineID: "pharaoh", BetLevel: 100, BetAmount: 100, WinAmount: 0, TheoreticalRTP: 0.96, PlayedAt: day1},
+		{MachineID: "pharaoh", BetLevel: 100, BetAmount: 125, WinAmount: 300, TheoreticalRTP: 0.96, Features: 1, PlayedAt: day1},
+		{MachineID: "pharaoh", BetLevel: 100, WinAmount: 50, FreeSpin: true, PlayedAt: day1},
+		{MachineID: "pharaoh", BetLevel: 100, BetAmount: 100, WinAmount: 20, TheoreticalRTP: 0.96, JackpotHits: 1, JackpotWin: 5000, PlayedAt: day2},
+		{MachineID: "pharaoh", BetLevel: 200, BetAmount: 200, TheoreticalRTP: 0.96, PlayedAt: day2},
+		{MachineID: "mahjong", BetLevel: 100, BetAmount: 100, WinAmount: 80, TheoreticalRTP: 0.96, PlayedAt: day2},
+	}
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		for _, spin := range spins {
+			if err := repo.Record(tx, spin); err != nil {
+				return err
+			}
+		}
+		// 没有机台或档位的旋转不计入
+		return repo.Record(tx, &SlotSpinStat{BetAmount: 100})
+	}))
+
+	buckets, err := repo.GetBuckets(SlotStatFilter{MachineID: "pharaoh", From: "2026-10-01", To: "2026-10-01"})
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	bucket := buckets[0]
+	assert.Equal(t, "2026-10-01", bucket.Day)
+	assert.Equal(t, int64(3), bucket.Spins)
+	assert.Equal(t, int64(2), bucket.PaidSpins)
+	assert.Equal(t, int64(225), bucket.TotalBet)
+	assert.Equal(t, int64(350), bucket.TotalWin)
+	assert.InDelta(t, 225*0.96, bucket.ExpectedWin, 1e-9)
+	assert.InDelta(t, 3.5, bucket.SumReturn, 1e-9)
+	assert.InDelta(t, 9.25, bucket.SumReturnSq, 1e-9)
+	assert.Equal(t, int64(2), bucket.HitCount)
+	assert.Equal(t, int64(1), bucket.FreeSpins)
+	assert.Equal(t, int64(1), bucket.FeatureTriggers)
+	assert.Equal(t, int64(300), bucket.MaxWin)
+
+	summaries, err := repo.Summarize(SlotStatFilter{MachineID: "pharaoh", From: "2026-10-01", To: "2026-10-02"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, int64(100), summaries[0].BetLevel)
+	assert.Equal(t, int64(4), summaries[0].Spins)
+	assert.Equal(t, int64(325), summaries[0].TotalBet)
+	assert.Equal(t, int64(370), summaries[0].TotalWin)
+	assert.Equal(t, int64(1), summaries[0].JackpotHits)
+	assert.Equal(t, int64(5000), summaries[0].JackpotWin)
+	assert.InDelta(t, 370.0/325.0, summaries[0].ActualRTP, 1e-9)
+	assert.Equal(t, int64(200), summaries[1].BetLevel)
+
+	all, err := repo.GetBuckets(SlotStatFilter{BetLevel: 100, From: "2026-10-02"})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestSlotStatsRepository_RTPDriftReport(t *testing.T) {
+	db, repo := setupSlotStatsTest(t)
+
+	// 每档10000次旋转、单次回报率方差约1：正常机台实际RTP在界限内，异常机台偏离理论10个百分点
+	bucket := func(machineID string, betLevel int64, rtp float64) *models.SlotStatBucket {
+		spins := int64(10000)
+		totalBet := spins * betLevel
+		totalWin := int64(float64(totalBet) * rtp)
+		return &models.SlotStatBucket{
+			MachineID:   machineID,
+			Day:         "2026-10-01",
+			BetLevel:    betLevel,
+			Spins:       spins,
+			PaidSpins:   spins,
+			TotalBet:    totalBet,
+			TotalWin:    totalWin,
+			ExpectedWin: float64(totalBet) * 0.96,
+			SumReturn:   float64(spins) * rtp,
+			SumReturnSq: float64(spins) * (1 + rtp*rtp),
+		}
+	}
+	require.NoError(t, db.Create(bucket("mahjong", 100, 0.965)).Error)
+	require.NoError(t, db.Create(bucket("pharaoh", 100, 0.97)).Error)
+	require.NoError(t, db.Create(bucket("pharaoh", 200, 1.06)).Error)
+	// 没有理论RTP的机台不参与检测
+	unknown := bucket("cascade", 100, 1.5)
+	unknown.ExpectedWin = 0
+	require.NoError(t, db.Create(unknown).Error)
+
+	report, err := repo.GetRTPDriftReport(SlotStatFilter{From: "2026-10-01", To: "2026-10-01"}, 0.99)
+	require.NoError(t, err)
+	require.Len(t, report.Machines, 2)
+
+	mahjong := report.Machines[0]
+	assert.Equal(t, "mahjong", mahjong.MachineID)
+	assert.False(t, mahjong.Drift.Alarm)
+	assert.InDelta(t, 0.01, mahjong.Drift.StandardError, 1e-4)
+
+	pharaoh := report.Machines[1]
+	require.Len(t, pharaoh.BetLevels, 2)
+	assert.False(t, pharaoh.BetLevels[0].Drift.Alarm)
+	assert.True(t, pharaoh.BetLevels[1].Drift.Alarm)
+	assert.True(t, pharaoh.Drift.Alarm)
+	assert.InDelta(t, (0.97*100+1.06*200)/300, pharaoh.Drift.ActualRTP, 1e-6)
+
+	require.Len(t, report.Alerts, 2)
+	assert.Equal(t, int64(200), report.Alerts[0].BetLevel)
+	assert.Equal(t, "critical", report.Alerts[0].AlertLevel)
+	assert.Contains(t, report.Alerts[0].Message, "pharaoh")
+	assert.Equal(t, int64(0), report.Alerts[1].BetLevel)
+}
@@ -645,4 +645,58 @@ func (suite *SlotRepositoryTestSuite) TestSlotWinLineRepository_FindBySpinID() {
 // TestSlotRepositorySuite 运行Slot游戏仓储测试套件
 func TestSlotRepositorySuite(t *testing.T) {
 	suite.Run(t, new(SlotRepositoryTestSuite))
-}
\ No newline at end of file
+}
+// TestSlotSpinRepository_FindByReelSetID 测试按卷轴组查找旋转记录
+func (suite *SlotRepositoryTestSuite) TestSlotSpinRepository_FindByReelSetID() {
+	ctx := context.Background()
+	
+	// 创建游戏
+	game := &models.Game{
+		Name:   "测试老虎机",
+		Type:   "slot",
+		Status: "active",
+	}
+	err := suite.db.Create(game).Error
+	assert.NoError(suite.T(), err)
+	
+	// 创建机器
+	machine := &models.SlotMachine{
+		GameID:      game.ID,
+		MachineID:   "SLOT_REELSET",
+		Name:        "测试机器",
+		Reels:       5,
+		Rows:        3,
+		Paylines:    20,
+		Status:      "active",
+		Symbols:     models.JSONMap{},
+		PayTable:    models.JSONMap{},
+		BonusConfig: models.JSONMap{},
+	}
+	err = suite.machineRepo.Create(ctx, machine)
+	assert.NoError(suite.T(), err)
+	
+	// 不同卷轴组的旋转记录
+	for i, reelSetID := range []string{"rtp_92", "rtp_96", "rtp_96"} {
+		spin := &models.SlotSpin{
+			ResultID:   uint(i + 1),
+			MachineID:  machine.ID,
+			SpinNumber: i + 1,
+			ReelStops:  models.JSONMap{},
+			WinLines:   models.JSONMap{},
+			ReelSetID:  reelSetID,
+			Seed:       int64(1000 + i),
+		}
+		err = suite.spinRepo.Create(ctx, spin)
+		assert.NoError(suite.T(), err)
+	}
+	
+	pagination := &Pagination{Page: 1, PageSize: 10}
+	spins, err := suite.spinRepo.FindByReelSetID(ctx, machine.ID, "rtp_96", pagination)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), spins, 2)
+	assert.Equal(suite.T(), int64(2), pagination.Total)
+	for _, spin := range spins {
+		assert.Equal(suite.T(), "rtp_96", spin.ReelSetID)
+		assert.NotZero(suite.T(), spin.Seed)
+	}
+}
@@ -41,8 +41,12 @@ func SetupTestDB() *gorm.DB {
 	// 清理所有表数据（保留表结构）
 	// 注意：清理顺序很重要，先清理有外键依赖的表
 	tables := []interface{}{
+		&models.ActivityRanking{},
+		&models.ActivityRecord{},
+		&models.SlotStatBucket{},
 		&models.SlotWinLine{},
 		&models.SlotSpin{},
+		&models.SlotConfigVersion{},
 		&models.SlotMachine{},
 		&models.CoinDrop{},
 		&models.PusherSession{},
@@ -98,7 +102,13 @@ func SetupTestDB() *gorm.DB {
 		// Slot游戏
 		&models.SlotMachine{},
 		&models.SlotSpin{},
+		&models.SlotConfigVersion{},
 		&models.SlotWinLine{},
+		&models.SlotStatBucket{},
+
+		// 动物活动场
+		&models.ActivityRecord{},
+		&models.ActivityRanking{},
 
 		// Pusher游戏
 		&models.PusherMachine{},
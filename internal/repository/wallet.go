@@ -22,6 +22,7 @@ type WalletRepository interface {
 	LockForUpdate(ctx context.Context, userID uint) (*models.Wallet, error)
 	UpdateStatistics(ctx context.Context, userID uint, field string, amount int64) error
 	UpdateGameStatsTx(tx *gorm.DB, userID uint, betAmount, winAmount, coinsIn, coinsOut int64) error
+	DeductBetTx(tx *gorm.DB, userID uint, betAmount int64) error
 	CreateTransaction(ctx context.Context, transaction *models.WalletTransaction) error
 }
 
@@ -159,6 +160,27 @@ func (r *walletRepo) UpdateGameStatsTx(tx *gorm.DB, userID uint, betAmount, winA
 	return nil
 }
 
+// DeductBetTx 在事务中扣除下注的游戏币并计入下注统计，游戏币不足时不扣除
+func (r *walletRepo) DeductBetTx(tx *gorm.DB, userID uint, betAmount int64) error {
+	result := tx.Model(&models.Wallet{}).
+		Where("user_id = ? AND coins >= ?", userID, betAmount).
+		Updates(map[string]interface{}{
+			"total_bet": gorm.Expr("total_bet + ?", betAmount),
+			"daily_bet": gorm.Expr("daily_bet + ?", betAmount),
+			"coins":     gorm.Expr("coins - ?", betAmount),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("余额不足")
+	}
+
+	return nil
+}
+
 // CreateTransaction 创建交易记录
 func (r *walletRepo) CreateTransaction(ctx context.Context, transaction *models.WalletTransaction) error {
 	return r.db.WithContext(ctx).Create(transaction).Error
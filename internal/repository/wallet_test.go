@@ -160,6 +160,35 @@ func (suite *WalletRepositoryTestSuite) TestWalletRepository_DeductBalance() {
 	assert.Equal(suite.T(), int64(700), found.Balance)
 }
 
+// TestWalletRepository_DeductBetTx 测试扣除下注的游戏币
+func (suite *WalletRepositoryTestSuite) TestWalletRepository_DeductBetTx() {
+	ctx := context.Background()
+	user := suite.createTestUser("deductbetuser")
+
+	wallet := &models.Wallet{
+		UserID: user.ID,
+		Coins:  500,
+	}
+	err := suite.walletRepo.Create(ctx, wallet)
+	assert.NoError(suite.T(), err)
+
+	// 扣除下注（成功）
+	err = suite.walletRepo.DeductBetTx(suite.db, user.ID, 300)
+	assert.NoError(suite.T(), err)
+
+	// 扣除下注（游戏币不足）
+	err = suite.walletRepo.DeductBetTx(suite.db, user.ID, 300)
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "余额不足")
+
+	// 验证只扣除了一次
+	found, err := suite.walletRepo.FindByUserID(ctx, user.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(200), found.Coins)
+	assert.Equal(suite.T(), int64(300), found.TotalBet)
+	assert.Equal(suite.T(), int64(300), found.DailyBet)
+}
+
 // TestWalletRepository_LockForUpdate 测试悲观锁
 func (suite *WalletRepositoryTestSuite) TestWalletRepository_LockForUpdate() {
 	ctx := context.Background()
@@ -0,0 +1,281 @@
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/wfunc/slot-game/internal/game/animal"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/pb"
+	"github.com/wfunc/slot-game/internal/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// activityRoomID 活动场房间ID（与普通动物房间的自增ID区分）
+const activityRoomID uint32 = 10000
+
+// guestPlayerIDBase 游客玩家ID起始值（高位区间，不与用户ID冲突）
+const guestPlayerIDBase uint32 = 1 << 31
+
+// errActivityLogin 未登录的客户端不能参加活动场（排名奖励按用户发放）
+var errActivityLogin = errors.New("登录后才能参加活动场")
+
+// ActivityArena 活动场，protobuf 和二进制两种连接共用同一个活动场
+type ActivityArena struct {
+	room *animal.ActivityRoom
+
+	mu          sync.RWMutex
+	subscribers []func(*animal.PushMessage)
+}
+
+// NewActivityArena 创建并启动活动场（config 为空时使用默认配置）
+func NewActivityArena(db *gorm.DB, config *animal.ActivityConfig, logger *zap.Logger) *ActivityArena {
+	a := &ActivityArena{}
+	repo := repository.NewActivityRepository(db)
+	settleCallback := func(settlement *animal.ActivitySettlement) map[uint32]uint64 {
+		return settleActivity(repo, settlement, logger)
+	}
+
+	a.room = animal.NewActivityRoom(activityRoomID, config, logger, a.push, settleCallback)
+	a.room.Start()
+	return a
+}
+
+// Room 活动场房间
+func (a *ActivityArena) Room() *animal.ActivityRoom {
+	return a.room
+}
+
+// Subscribe 订阅活动场推送
+func (a *ActivityArena) Subscribe(push func(*animal.PushMessage)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.subscribers = append(a.subscribers, push)
+}
+
+// Stop 停止活动场（进行中的活动立即结算）
+func (a *ActivityArena) Stop() {
+	a.room.Stop()
+}
+
+// push 把活动场推送转发给所有订阅者
+func (a *ActivityArena) push(msg *animal.PushMessage) {
+	msg.RoomID = activityRoomID
+
+	a.mu.RLock()
+	subscribers := a.subscribers
+	a.mu.RUnlock()
+
+	for _, push := range subscribers {
+		push(msg)
+	}
+}
+
+// handleEnterActivity 进入活动场（1871）
+func (h *AnimalHandler) handleEnterActivity(session *AnimalSession, payload []byte) {
+	req := &pb.M_1871Tos{}
+	if err := proto.Unmarshal(payload, req); err != nil {
+		h.logger.Error("[AnimalHandler] 解析进入活动场失败", zap.Error(err))
+		return
+	}
+
+	// 先离开普通动物房间或停止观战
+	if session.RoomID != activityRoomID || session.Spectator {
+		h.leaveCurrentRoom(session)
+	}
+
+	resp, err := h.activityRoom.Enter(req.GetAgentId(), &animal.ActivityPlayer{
+		PlayerID: session.PlayerID,
+		UserID:   session.UserID,
+		Name:     session.Name,
+		Icon:     session.Icon,
+		VIP:      session.VIP,
+	})
+	if err != nil {
+		h.logger.Warn("[AnimalHandler] 进入活动场失败",
+			zap.Uint32("player_id", session.PlayerID),
+			zap.Error(err))
+		return
+	}
+
+	session.ZooType = 0
+	session.RoomID = activityRoomID
+
+	h.sendMessage(session, 1871, resp)
+}
+
+// handleHitActivityAnimal 活动场打动物（1872）
+func (h *AnimalHandler) handleHitActivityAnimal(session *AnimalSession, payload []byte) {
+	req := &pb.M_1872Tos{}
+	if err := proto.Unmarshal(payload, req); err != nil {
+		h.logger.Error("[AnimalHandler] 解析活动场打动物失败", zap.Error(err))
+		return
+	}
+	if session.RoomID != activityRoomID || session.Spectator {
+		h.logger.Warn("[AnimalHandler] 玩家不在活动场", zap.Uint32("player_id", session.PlayerID))
+		return
+	}
+
+	resp, err := h.activityRoom.Hit(session.PlayerID, req.GetId(), req.GetBetVal())
+	if err != nil {
+		h.logger.Warn("[AnimalHandler] 活动场打动物失败",
+			zap.Uint32("player_id", session.PlayerID),
+			zap.Uint32("animal_id", req.GetId()),
+			zap.Error(err))
+		return
+	}
+
+	h.sendMessage(session, 1872, resp)
+}
+
+// handleGetActivityRank 获取活动场排行榜（1873）
+func (h *AnimalHandler) handleGetActivityRank(session *AnimalSession, payload []byte) {
+	req := &pb.M_1873Tos{}
+	if err := proto.Unmarshal(payload, req); err != nil {
+		h.logger.Error("[AnimalHandler] 解析活动场排行榜失败", zap.Error(err))
+		return
+	}
+
+	h.sendMessage(session, 1873, h.activityRoom.GetRank(req.GetId(), req.GetNum()))
+}
+
+// settleActivity 保存活动记录并发放排名奖励，返回玩家结算后的游戏币（playerID -> coins）
+func settleActivity(repo *repository.ActivityRepository, settlement *animal.ActivitySettlement, logger *zap.Logger) map[uint32]uint64 {
+	// 没有玩家参加的活动不保存记录
+	if len(settlement.Payouts) == 0 {
+		return nil
+	}
+
+	activity := settlement.Activity
+	record := &models.ActivityRecord{
+		ActivityID:  activity.ID,
+		AgentID:     activity.AgentID,
+		SendGold:    int64(activity.SendGold),
+		PlayerCount: len(settlement.Payouts),
+		StartedAt:   activity.StartTime,
+		EndedAt:     activity.EndTime,
+	}
+	for _, payout := range settlement.Payouts {
+		record.Rankings = append(record.Rankings, models.ActivityRanking{
+			Rank:     payout.Rank,
+			PlayerID: payout.PlayerID,
+			UserID:   payout.UserID,
+			Name:     payout.Name,
+			Score:    int64(payout.Score),
+			Reward:   int64(payout.Reward),
+		})
+	}
+
+	balances, err := repo.SaveSettlement(record)
+	if err != nil {
+		logger.Error("[Activity] 活动结算保存失败",
+			zap.Uint32("activity_id", activity.ID),
+			zap.Error(err))
+		return nil
+	}
+
+	gold := make(map[uint32]uint64, len(settlement.Payouts))
+	for _, payout := range settlement.Payouts {
+		if coins, ok := balances[payout.UserID]; ok && coins > 0 {
+			gold[payout.PlayerID] = uint64(coins)
+		}
+	}
+	return gold
+}
+
+// clientPlayerID 客户端的玩家ID（未登录的客户端沿用本连接已分配的游客ID，没有时分配新的游客ID）
+func (r *BinaryProtocolRouter) clientPlayerID(client *ProtocolClient) uint32 {
+	if client.UserID != 0 {
+		return uint32(client.UserID)
+	}
+	if managedClient := r.clientManager.GetClient(client.ID); managedClient != nil && managedClient.PlayerID >= guestPlayerIDBase {
+		return managedClient.PlayerID
+	}
+	return guestPlayerIDBase + r.nextGuestID.Add(1)
+}
+
+// handleEnterActivity 处理进入活动场（1871）
+func (r *BinaryProtocolRouter) handleEnterActivity(client *ProtocolClient, msg *ClientMessage) (*ServerMessage, error) {
+	req := &pb.M_1871Tos{}
+	if err := proto.Unmarshal(msg.Data, req); err != nil {
+		r.logger.Error("[路由] 解析1871请求失败", zap.Error(err))
+		return nil, err
+	}
+
+	if client.UserID == 0 {
+		return nil, errActivityLogin
+	}
+
+	managedClient := r.clientManager.GetClient(client.ID)
+	if managedClient == nil {
+		r.clientManager.AddClient(client)
+	} else if managedClient.Spectator {
+		r.unwatchRoom(client.ID, managedClient.RoomID)
+	}
+
+	playerID := r.clientPlayerID(client)
+	resp, err := r.activityRoom.Enter(req.GetAgentId(), &animal.ActivityPlayer{
+		PlayerID: playerID,
+		UserID:   client.UserID,
+		Name:     "Player",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 加入活动场后接收活动推送（会离开之前的房间）
+	r.clientManager.JoinRoom(client.ID, activityRoomID, playerID)
+
+	return r.protoResponse(msg, resp)
+}
+
+// handleHitActivityAnimal 处理活动场打动物（1872）
+func (r *BinaryProtocolRouter) handleHitActivityAnimal(client *ProtocolClient, msg *ClientMessage) (*ServerMessage, error) {
+	req := &pb.M_1872Tos{}
+	if err := proto.Unmarshal(msg.Data, req); err != nil {
+		r.logger.Error("[路由] 解析1872请求失败", zap.Error(err))
+		return nil, err
+	}
+
+	managedClient := r.clientManager.GetClient(client.ID)
+	if managedClient == nil || managedClient.RoomID != activityRoomID || managedClient.Spectator {
+		return nil, fmt.Errorf("客户端不在活动场中")
+	}
+
+	resp, err := r.activityRoom.Hit(managedClient.PlayerID, req.GetId(), req.GetBetVal())
+	if err != nil {
+		return nil, err
+	}
+	return r.protoResponse(msg, resp)
+}
+
+// handleGetActivityRank 处理活动场排行榜（1873）
+func (r *BinaryProtocolRouter) handleGetActivityRank(client *ProtocolClient, msg *ClientMessage) (*ServerMessage, error) {
+	req := &pb.M_1873Tos{}
+	if err := proto.Unmarshal(msg.Data, req); err != nil {
+		r.logger.Error("[路由] 解析1873请求失败", zap.Error(err))
+		return nil, err
+	}
+
+	return r.protoResponse(msg, r.activityRoom.GetRank(req.GetId(), req.GetNum()))
+}
+
+// protoResponse 序列化动物园protobuf响应
+func (r *BinaryProtocolRouter) protoResponse(msg *ClientMessage, resp proto.Message) (*ServerMessage, error) {
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		r.logger.Error("[路由] 序列化响应失败", zap.Uint16("cmd", msg.Cmd), zap.Error(err))
+		return nil, err
+	}
+
+	return &ServerMessage{
+		ErrorID:    0,
+		DataStatus: 0,
+		Flag:       msg.Flag,
+		Cmd:        msg.Cmd,
+		Data:       data,
+	}, nil
+}
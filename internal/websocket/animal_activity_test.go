@@ -0,0 +1,182 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/game/animal"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/pb"
+	"github.com/wfunc/slot-game/internal/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAnimalHandlerActivitySettlement(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	db := setupTestActivityDB(t)
+
+	user := &models.User{Username: "activity_user", Email: "activity@test.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 1000})
+
+	// 立即开始、必定击杀的活动场
+	arena := NewActivityArena(db, &animal.ActivityConfig{
+		ID:       1,
+		PreTime:  0,
+		WorkTime: 60,
+		IdleTime: 60,
+		SendGold: 10000,
+		MaxNum:   10,
+		BetVals:  []uint32{100},
+		Percent:  4000000000,
+		Rewards: []animal.RewardConfig{
+			{Rank: 1, Gold: 5000},
+			{Rank: 2, MaxRank: 10, Gold: 1000},
+		},
+	}, logger)
+	defer arena.Stop()
+
+	handler := NewAnimalHandlerWithArena(db, logger, arena)
+	defer handler.Cleanup()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if state, _ := handler.activityRoom.GetState(); state == animal.ActivityStateActive {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("activity did not become active")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp, err := handler.activityRoom.Enter(0, &animal.ActivityPlayer{PlayerID: uint32(user.ID), UserID: user.ID, Name: "winner"})
+	if err != nil {
+		t.Fatalf("Enter failed: %v", err)
+	}
+	if resp.GetGold() != 10000 || resp.GetState() != 2 || len(resp.GetAnimals()) == 0 {
+		t.Fatalf("unexpected enter response: gold=%d state=%d animals=%d", resp.GetGold(), resp.GetState(), len(resp.GetAnimals()))
+	}
+	if _, err := handler.activityRoom.Enter(0, &animal.ActivityPlayer{PlayerID: 9999, Name: "idle"}); err != nil {
+		t.Fatalf("Enter failed: %v", err)
+	}
+
+	if _, err := handler.activityRoom.Hit(uint32(user.ID), resp.GetAnimals()[0].GetId(), 300); err != animal.ErrInvalidBetValue {
+		t.Errorf("expected ErrInvalidBetValue, got %v", err)
+	}
+	hit, err := handler.activityRoom.Hit(uint32(user.ID), resp.GetAnimals()[0].GetId(), 100)
+	if err != nil {
+		t.Fatalf("Hit failed: %v", err)
+	}
+	if hit.GetBalance() != 9900 || hit.GetScore() == 0 {
+		t.Errorf("unexpected hit response: balance=%d score=%d", hit.GetBalance(), hit.GetScore())
+	}
+
+	rank := handler.activityRoom.GetRank(0, 10)
+	if len(rank.GetRank()) != 1 || rank.GetRank()[0].GetName() != "winner" {
+		t.Fatalf("unexpected current rank: %v", rank.GetRank())
+	}
+
+	if err := handler.activityRoom.EndActivity(); err != nil {
+		t.Fatalf("EndActivity failed: %v", err)
+	}
+	if state, _ := handler.activityRoom.GetState(); state != animal.ActivityStateIdle {
+		t.Errorf("expected idle after settlement, got %s", state)
+	}
+	if _, err := handler.activityRoom.Hit(uint32(user.ID), resp.GetAnimals()[0].GetId(), 100); err != animal.ErrActivityNotActive {
+		t.Errorf("expected ErrActivityNotActive, got %v", err)
+	}
+
+	// 第一名奖励发放到钱包
+	var wallet models.Wallet
+	db.Where("user_id = ?", user.ID).First(&wallet)
+	if wallet.Coins != 6000 {
+		t.Errorf("expected coins 6000 after reward, got %d", wallet.Coins)
+	}
+	var count int64
+	db.Model(&models.Transaction{}).Where("user_id = ? AND sub_type = ?", user.ID, models.BonusSubTypeActivityRank).Count(&count)
+	if count != 1 {
+		t.Errorf("expected 1 activity reward transaction, got %d", count)
+	}
+
+	records, err := repository.NewActivityRepository(db).GetRecords(10)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].PlayerCount != 2 || records[0].TotalReward != 5000 || len(records[0].Rankings) != 2 {
+		t.Fatalf("unexpected activity records: %+v", records)
+	}
+
+	history := handler.activityRoom.GetRank(1, 10)
+	if len(history.GetRank()) != 1 || history.GetRank()[0].GetId() != 1 {
+		t.Errorf("unexpected history rank: %v", history.GetRank())
+	}
+}
+
+func TestActivityArenaShared(t *testing.T) {
+	logger := zap.NewNop()
+	db := setupTestActivityDB(t)
+
+	arena := NewActivityArena(db, &animal.ActivityConfig{
+		ID:       1,
+		WorkTime: 60,
+		IdleTime: 60,
+		SendGold: 10000,
+		MaxNum:   10,
+		BetVals:  []uint32{100},
+		Rewards:  []animal.RewardConfig{{Rank: 1, Gold: 5000}},
+	}, logger)
+	defer arena.Stop()
+
+	handler := NewAnimalHandlerWithArena(db, logger, arena)
+	defer handler.Cleanup()
+	router := NewBinaryProtocolRouter(db, logger, arena)
+	defer router.GetAnimalRoom(1).Stop()
+
+	if handler.activityRoom != router.activityRoom {
+		t.Fatal("handler and router should share one activity room")
+	}
+
+	// 未登录的客户端不能进入活动场
+	guest := NewProtocolClient("guest", nil, nil, logger)
+	enter, _ := proto.Marshal(&pb.M_1871Tos{AgentId: proto.Uint32(0)})
+	if _, err := router.HandleMessage(guest, &ClientMessage{Cmd: 1871, Data: enter}); err != errActivityLogin {
+		t.Errorf("expected errActivityLogin, got %v", err)
+	}
+
+	// 游客在普通房间使用各自的玩家ID
+	other := NewProtocolClient("guest-2", nil, nil, logger)
+	for _, client := range []*ProtocolClient{guest, other} {
+		if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1801}); err != nil {
+			t.Fatalf("enter room failed: %v", err)
+		}
+	}
+	first, second := router.clientManager.GetClient(guest.ID).PlayerID, router.clientManager.GetClient(other.ID).PlayerID
+	if first < guestPlayerIDBase || second < guestPlayerIDBase || first == second {
+		t.Errorf("guests should get distinct guest ids, got %d and %d", first, second)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if state, _ := arena.Room().GetState(); state == animal.ActivityStateActive {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("activity did not become active")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 没有玩家参加的活动不保存记录
+	if err := arena.Room().EndActivity(); err != nil {
+		t.Fatalf("EndActivity failed: %v", err)
+	}
+	records, err := repository.NewActivityRepository(db).GetRecords(10)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("empty activity should not be saved, got %d records", len(records))
+	}
+}
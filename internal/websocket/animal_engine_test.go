@@ -0,0 +1,239 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/game/animal"
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBinaryProtocolRouterAnimalBet(t *testing.T) {
+	logger := zap.NewNop()
+	router := newTestAnimalRouter(t)
+
+	client := NewProtocolClient("player-one", nil, nil, logger)
+	client.Balance = 10000
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1801}); err != nil {
+		t.Fatalf("enter room failed: %v", err)
+	}
+
+	// 未发射子弹不能下注
+	animals := router.GetAnimalRoom(1).GetAnimals()
+	if len(animals) == 0 {
+		t.Fatalf("room should have animals after start")
+	}
+	target := animals[0].GetId()
+	bet, _ := proto.Marshal(&pb.M_1803Tos{Id: proto.Uint32(target)})
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1803, Data: bet}); err == nil {
+		t.Errorf("bet without bullet should fail")
+	}
+
+	fire, _ := proto.Marshal(&pb.M_1815Tos{BetVal: proto.Uint32(100)})
+	resp, err := router.HandleMessage(client, &ClientMessage{Cmd: 1815, Data: fire})
+	if err != nil {
+		t.Fatalf("fire bullet failed: %v", err)
+	}
+	fired := &pb.M_1815Toc{}
+	if err := proto.Unmarshal(resp.Data, fired); err != nil {
+		t.Fatalf("failed to decode 1815: %v", err)
+	}
+	if fired.GetBalance() != 9900 || fired.GetBulletId() == "" {
+		t.Fatalf("unexpected 1815 response: balance=%d bullet=%q", fired.GetBalance(), fired.GetBulletId())
+	}
+
+	// 由房间引擎结算，余额等于扣除下注后加上赢取
+	bet, _ = proto.Marshal(&pb.M_1803Tos{Id: proto.Uint32(target), BulletId: proto.String(fired.GetBulletId())})
+	resp, err = router.HandleMessage(client, &ClientMessage{Cmd: 1803, Data: bet})
+	if err != nil {
+		t.Fatalf("bet failed: %v", err)
+	}
+	result := &pb.M_1803Toc{}
+	if err := proto.Unmarshal(resp.Data, result); err != nil {
+		t.Fatalf("failed to decode 1803: %v", err)
+	}
+	if result.GetBalance() != 9900+uint64(result.GetWin()) || result.GetTotalWin() != uint64(result.GetWin()) {
+		t.Errorf("unexpected 1803 response: balance=%d win=%d total=%d", result.GetBalance(), result.GetWin(), result.GetTotalWin())
+	}
+
+	// 子弹只能结算一次
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1803, Data: bet}); err == nil {
+		t.Errorf("bullet should not settle twice")
+	}
+
+	// 座位与服务端分配的不一致时不能发射，也不扣费
+	wrongSeat, _ := proto.Marshal(&pb.M_1815Tos{BetVal: proto.Uint32(100), Seat: proto.Uint32(3)})
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1815, Data: wrongSeat}); err != animal.ErrSeatMismatch {
+		t.Errorf("expected ErrSeatMismatch, got %v", err)
+	}
+	if client.Balance != result.GetBalance() {
+		t.Errorf("rejected fire should not charge: balance=%d", client.Balance)
+	}
+
+	// 命中由服务端按弹道判定：朝场景外发射的子弹不会命中，客户端上报的动物ID无效
+	if hit := shootAnimal(t, router, client, 90, target); hit != 0 {
+		t.Errorf("bullet fired off screen should miss, hit %d", hit)
+	}
+
+	// 向上扇形扫射，总能命中场上的动物
+	hit := uint32(0)
+	for angle := float32(-170); angle <= -10 && hit == 0; angle += 5 {
+		hit = shootAnimal(t, router, client, angle, 0)
+	}
+	if hit == 0 {
+		t.Errorf("sweeping shots should hit an animal")
+	}
+}
+
+// shootAnimal 从座位按角度发射一发子弹并结算，返回服务端判定命中的动物ID
+func shootAnimal(t *testing.T, router *BinaryProtocolRouter, client *ProtocolClient, angle float32, claimed uint32) uint32 {
+	t.Helper()
+
+	fire, _ := proto.Marshal(&pb.M_1815Tos{BetVal: proto.Uint32(100), Angle: proto.Float32(angle), Seat: proto.Uint32(1)})
+	resp, err := router.HandleMessage(client, &ClientMessage{Cmd: 1815, Data: fire})
+	if err != nil {
+		t.Fatalf("fire bullet failed: %v", err)
+	}
+	fired := &pb.M_1815Toc{}
+	if err := proto.Unmarshal(resp.Data, fired); err != nil {
+		t.Fatalf("failed to decode 1815: %v", err)
+	}
+
+	bet, _ := proto.Marshal(&pb.M_1803Tos{Id: proto.Uint32(claimed), BulletId: proto.String(fired.GetBulletId())})
+	resp, err = router.HandleMessage(client, &ClientMessage{Cmd: 1803, Data: bet})
+	if err != nil {
+		t.Fatalf("bet failed: %v", err)
+	}
+	result := &pb.M_1803Toc{}
+	if err := proto.Unmarshal(resp.Data, result); err != nil {
+		t.Fatalf("failed to decode 1803: %v", err)
+	}
+	if result.GetHitId() == 0 && result.GetWin() != 0 {
+		t.Errorf("missed shot should not win: win=%d", result.GetWin())
+	}
+	if result.GetBalance() != fired.GetBalance()+uint64(result.GetWin()) {
+		t.Errorf("unexpected balance after settle: balance=%d fired=%d win=%d", result.GetBalance(), fired.GetBalance(), result.GetWin())
+	}
+	return result.GetHitId()
+}
+
+func TestBinaryProtocolRouterRefundsExpiredBullet(t *testing.T) {
+	logger := zap.NewNop()
+	router := newTestAnimalRouter(t)
+
+	client := NewProtocolClient("player-expired", nil, nil, logger)
+	client.Balance = 10000
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1801}); err != nil {
+		t.Fatalf("enter room failed: %v", err)
+	}
+
+	fire, _ := proto.Marshal(&pb.M_1815Tos{BetVal: proto.Uint32(100), Angle: proto.Float32(-90)})
+	resp, err := router.HandleMessage(client, &ClientMessage{Cmd: 1815, Data: fire})
+	if err != nil {
+		t.Fatalf("fire bullet failed: %v", err)
+	}
+	fired := &pb.M_1815Toc{}
+	if err := proto.Unmarshal(resp.Data, fired); err != nil {
+		t.Fatalf("failed to decode 1815: %v", err)
+	}
+
+	// 超时未结算的子弹退还下注
+	balance := func() uint64 {
+		client.mu.RLock()
+		defer client.mu.RUnlock()
+		return client.Balance
+	}
+	deadline := time.Now().Add(animal.BulletLifetime + 3*time.Second)
+	for balance() != 10000 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if got := balance(); got != 10000 {
+		t.Fatalf("expired bullet should be refunded: balance=%d", got)
+	}
+
+	// 已退还的子弹不能再结算，也不计入房间盈亏
+	bet, _ := proto.Marshal(&pb.M_1803Tos{BulletId: proto.String(fired.GetBulletId())})
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1803, Data: bet}); err == nil {
+		t.Errorf("expired bullet should not settle")
+	}
+	if stats := router.ProfitStats(); len(stats) == 0 || stats[0].Shots != 0 {
+		t.Errorf("expired bullet should not count as a shot: %+v", stats)
+	}
+}
+
+func TestBinaryProtocolRouterProfitStats(t *testing.T) {
+	logger := zap.NewNop()
+	router := newTestAnimalRouter(t)
+
+	client := NewProtocolClient("player-profit", nil, nil, logger)
+	client.Balance = 100000
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1801}); err != nil {
+		t.Fatalf("enter room failed: %v", err)
+	}
+
+	// 每发子弹下注100，结算计入房间的P值和W值
+	shots := 0
+	for angle := float32(-170); angle <= -10; angle += 10 {
+		shootAnimal(t, router, client, angle, 0)
+		shots++
+	}
+
+	stats := router.ProfitStats()
+	if len(stats) == 0 || stats[0].RoomID != 1 {
+		t.Fatalf("expected stats for room 1, got %+v", stats)
+	}
+	room := stats[0]
+	totalBet := uint64(shots) * 100
+	totalWin := client.Balance + totalBet - 100000
+	if room.Shots != uint64(shots) || room.TotalBet != totalBet || room.TotalWin != totalWin {
+		t.Errorf("unexpected room profit: shots=%d bet=%d win=%d, want shots=%d bet=%d win=%d",
+			room.Shots, room.TotalBet, room.TotalWin, shots, totalBet, totalWin)
+	}
+	if room.Players != 1 || room.TargetRTP != animal.DefaultProfitConfig.TargetRTP {
+		t.Errorf("unexpected room info: players=%d target=%v", room.Players, room.TargetRTP)
+	}
+	if room.TotalWin > 0 && room.Kills == 0 {
+		t.Errorf("winning shots should record kills")
+	}
+}
+
+func TestBinaryProtocolRouterSetProfitConfig(t *testing.T) {
+	router := newTestAnimalRouter(t)
+	t.Cleanup(func() { animal.SetCurrentProfitConfig(animal.DefaultProfitConfig) })
+
+	if _, err := animal.ParseExpectedShots(map[string]float64{"dragon": 10}); err == nil {
+		t.Errorf("unknown animal should be rejected")
+	}
+	invalid := animal.DefaultProfitConfig
+	invalid.PumpRTP = invalid.TargetRTP
+	if err := animal.SetCurrentProfitConfig(invalid); err == nil {
+		t.Errorf("pump rtp below target should be rejected")
+	}
+
+	shots, err := animal.ParseExpectedShots(map[string]float64{"lion": 45})
+	if err != nil {
+		t.Fatalf("ParseExpectedShots failed: %v", err)
+	}
+	config := animal.DefaultProfitConfig
+	config.TargetRTP = 0.9
+	config.GlobalTargetRTP = 0.92
+	config.ExpectedShots = shots
+	if err := animal.SetCurrentProfitConfig(config); err != nil {
+		t.Fatalf("SetCurrentProfitConfig failed: %v", err)
+	}
+	router.SetProfitConfig(animal.CurrentProfitConfig())
+
+	// 已有房间立即使用新配置，之后新建的房间也使用新配置
+	if stats := router.ProfitStats(); len(stats) == 0 || stats[0].TargetRTP != 0.9 {
+		t.Fatalf("existing room should use the new target rtp, got %+v", stats)
+	}
+	room := animal.NewAnimalRoom(2, pb.EZooType_civilian, zap.NewNop(), nil)
+	if stats := room.GetProfitStats(); stats.TargetRTP != 0.9 {
+		t.Errorf("new room should use the current config, got target %v", stats.TargetRTP)
+	}
+	if current := animal.CurrentProfitConfig(); current.GlobalTargetRTP != 0.92 || current.ExpectedShotsOf(pb.EAnimal_lion, 100) != 45 {
+		t.Errorf("unexpected current config: %+v", current)
+	}
+}
@@ -38,6 +38,7 @@ type AnimalSession struct {
 	VIP      uint32
 	ZooType  pb.EZooType
 	RoomID   uint32      // 玩家所在房间ID
+	Spectator bool       // 观战中（不占座位，不能下注）
 
 	Conn  *websocket.Conn
 	Codec *ProtobufCodec
@@ -51,7 +52,7 @@ type AnimalHandler struct {
 
 	db           *gorm.DB
 	walletRepo   repository.WalletRepository
-	manager      *animal.Manager
+	manager      *animal.Manager // 玩家技能库存、下注记录和大奖榜
 	logger       *zap.Logger
 	configHandler *ConfigHandler // 添加配置处理器
 	bulletManager *animal.BulletManager // 子弹管理器
@@ -60,10 +61,19 @@ type AnimalHandler struct {
 	animalRooms    map[uint32]*animal.AnimalRoom        // roomID -> AnimalRoom
 	roomsByType    map[pb.EZooType][]uint32             // zooType -> roomID list
 	nextRoomID     uint32                              // 下一个房间ID
+
+	// 活动场
+	activityRoom   *animal.ActivityRoom
+	activityArena  *ActivityArena // 处理器自己创建的活动场（共用的活动场不在这里停止）
 }
 
-// NewAnimalHandler 创建处理器
+// NewAnimalHandler 创建处理器（使用独立的活动场）
 func NewAnimalHandler(db *gorm.DB, logger *zap.Logger) *AnimalHandler {
+	return NewAnimalHandlerWithArena(db, logger, nil)
+}
+
+// NewAnimalHandlerWithArena 创建处理器，arena 为空时创建独立的活动场
+func NewAnimalHandlerWithArena(db *gorm.DB, logger *zap.Logger, arena *ActivityArena) *AnimalHandler {
 	h := &AnimalHandler{
 		sessions:       make(map[string]*AnimalSession),
 		playerSessions: make(map[uint32]map[string]*AnimalSession),
@@ -72,20 +82,32 @@ func NewAnimalHandler(db *gorm.DB, logger *zap.Logger) *AnimalHandler {
 		manager:        animal.NewManager(),
 		logger:         logger,
 		configHandler:  NewConfigHandler(db, logger), // 初始化配置处理器
-		bulletManager:  animal.NewBulletManager(),    // 初始化子弹管理器
 		animalRooms:    make(map[uint32]*animal.AnimalRoom),
 		roomsByType:    make(map[pb.EZooType][]uint32),
 		nextRoomID:     1,
 	}
+	h.bulletManager = animal.NewBulletManager(h.refundBullet) // 初始化子弹管理器，超时未结算的子弹退还下注
 
 	// 初始化动物房间系统
 	h.initializeAnimalRooms()
 
+	if arena == nil {
+		arena = NewActivityArena(db, nil, logger)
+		h.activityArena = arena
+	}
+	h.activityRoom = arena.Room()
+	arena.Subscribe(h.broadcastToRoom)
+
 	return h
 }
 
 // Cleanup 清理资源和停止所有房间
 func (h *AnimalHandler) Cleanup() {
+	// 先停止自己创建的活动场（进行中的活动立即结算，结算推送需要获取 h.mu）
+	if h.activityArena != nil {
+		h.activityArena.Stop()
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -200,16 +222,28 @@ func (h *AnimalHandler) createRoom(zooType pb.EZooType) *animal.AnimalRoom {
 	return room
 }
 
+// getRoom 获取动物房间
+func (h *AnimalHandler) getRoom(roomID uint32) *animal.AnimalRoom {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.animalRooms[roomID]
+}
+
 // broadcastToRoom 向房间广播消息
 func (h *AnimalHandler) broadcastToRoom(msg *animal.PushMessage) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	// 同一条消息只编码一次
+	var data []byte
 	for _, session := range h.sessions {
-		// 检查是否是同一个房间的玩家
+		// 检查是否是同一个房间的玩家或观众
 		if session.RoomID == msg.RoomID {
-			// 如果有指定目标玩家，只发送给指定玩家
+			// 如果有指定目标玩家，只发送给指定玩家（观众不接收）
 			if len(msg.Targets) > 0 {
+				if session.Spectator {
+					continue
+				}
 				found := false
 				for _, targetID := range msg.Targets {
 					if session.PlayerID == targetID {
@@ -223,12 +257,15 @@ func (h *AnimalHandler) broadcastToRoom(msg *animal.PushMessage) {
 			}
 
 			// 编码并发送消息
-			data, err := session.Codec.Encode(msg.MsgID, msg.Message)
-			if err != nil {
-				h.logger.Error("[AnimalHandler] 编码广播消息失败",
-					zap.Error(err),
-					zap.Uint16("msg_id", msg.MsgID))
-				continue
+			if data == nil {
+				encoded, err := session.Codec.Encode(msg.MsgID, msg.Message)
+				if err != nil {
+					h.logger.Error("[AnimalHandler] 编码广播消息失败",
+						zap.Error(err),
+						zap.Uint16("msg_id", msg.MsgID))
+					return
+				}
+				data = encoded
 			}
 
 			if err := session.Conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
@@ -356,6 +393,17 @@ func (h *AnimalHandler) listen(session *AnimalSession) {
 			h.handleGetJackpotHistory(session, payload)
 		case 1815:
 			h.handleFireBullet(session, payload)
+		case 1816:
+			h.handleWatchRoom(session, payload)
+		// 活动场
+		case 1871:
+			h.handleEnterActivity(session, payload)
+		case 1872:
+			h.handleHitActivityAnimal(session, payload)
+		case 1873:
+			h.handleGetActivityRank(session, payload)
+		case 1879:
+			h.handleLookActivity(session, payload)
 		// Config相关协议
 		case 2001, 2002, 2099:
 			h.configHandler.HandleMessage(session.Conn, msgID, payload, session.UserID)
@@ -366,6 +414,11 @@ func (h *AnimalHandler) listen(session *AnimalSession) {
 }
 
 func (h *AnimalHandler) cleanupSession(session *AnimalSession) {
+	// 观众断线时离开观战的房间（需要在获取 h.mu 之前，房间推送会获取 h.mu）
+	if session.Spectator {
+		h.leaveCurrentRoom(session)
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -424,6 +477,11 @@ func (h *AnimalHandler) handleEnterRoom(session *AnimalSession, payload []byte)
 		roomType = pb.EZooType_free // 默认体验场
 	}
 
+	// 观战中的玩家先停止观战
+	if session.Spectator {
+		h.leaveCurrentRoom(session)
+	}
+
 	// 使用动态房间管理系统
 	room, err := h.findOrCreateRoom(roomType)
 	if err != nil {
@@ -433,9 +491,9 @@ func (h *AnimalHandler) handleEnterRoom(session *AnimalSession, payload []byte)
 		return
 	}
 
-	// 调用新的动物房间进入逻辑
-	// TODO: 需要传入clientID，暂时使用空字符串
-	resp, _, err := room.EnterRoom(session.PlayerID, session.Name, session.Icon, "")
+	// 登记玩家（技能库存和下注记录）后进入房间
+	h.manager.EnsurePlayer(session.PlayerID, session.Name, session.Icon, session.VIP)
+	resp, _, err := room.EnterRoom(session.PlayerID, session.Name, session.Icon, session.ID)
 	if err != nil {
 		h.logger.Error("[AnimalHandler] 进入动物房间失败", zap.Error(err))
 		return
@@ -460,23 +518,8 @@ func (h *AnimalHandler) handleLeaveRoom(session *AnimalSession, payload []byte)
 		return
 	}
 
-	// 使用动态房间管理系统
-	if session.RoomID != 0 {
-		h.mu.RLock()
-		room, exists := h.animalRooms[session.RoomID]
-		h.mu.RUnlock()
-		if exists {
-			room.LeaveRoom(session.PlayerID)
-			h.logger.Info("[AnimalHandler] 玩家离开房间",
-				zap.Uint32("player_id", session.PlayerID),
-				zap.Uint32("room_id", session.RoomID),
-				zap.Uint32("current_players", room.GetPlayerCount()))
-		}
-	}
-
-	// 重置会话房间信息
-	session.ZooType = 0
-	session.RoomID = 0
+	// 使用动态房间管理系统（观众停止观战）
+	h.leaveCurrentRoom(session)
 
 	// 返回简单的确认响应
 	resp := &pb.M_1802Toc{}
@@ -494,10 +537,8 @@ func (h *AnimalHandler) handleBet(session *AnimalSession, payload []byte) {
 		// 对于1803请求，通常是空的或包含简单下注信息，继续处理
 	}
 
-	// 获取目标动物ID
-	targetID := req.GetId()
-	if targetID == 0 {
-		h.logger.Warn("[AnimalHandler] 无效的动物ID", zap.Uint32("target_id", targetID))
+	if session.Spectator {
+		h.logger.Warn("[AnimalHandler] 观战中不能下注", zap.Uint32("player_id", session.PlayerID))
 		return
 	}
 
@@ -527,20 +568,55 @@ func (h *AnimalHandler) handleBet(session *AnimalSession, payload []byte) {
 
 	h.logger.Info("[AnimalHandler] 使用子弹攻击",
 		zap.String("bullet_id", bullet.ID),
-		zap.Uint32("target_id", targetID),
+		zap.Uint32("client_target_id", req.GetId()),
 		zap.Uint32("bet_value", bullet.BetValue),
 		zap.Uint32("multiple", bullet.Multiple))
 
-	// 调用manager进行实际的下注处理
-	// 注意：这里需要修改manager.Bet方法来接受子弹信息
-	resp, pushes, err := h.manager.BetWithBullet(session.PlayerID, targetID, bullet.BetValue, bullet.Multiple)
+	room := h.getRoom(session.RoomID)
+	if room == nil {
+		h.logger.Warn("[AnimalHandler] 玩家不在动物房间", zap.Uint32("player_id", session.PlayerID))
+		return
+	}
+
+	// 由房间引擎按子弹弹道判定命中并结算，打动物和死亡推送由房间广播
+	outcome, err := room.ProcessShot(session.PlayerID, bullet.BetValue, bullet.Multiple, bullet.Trajectory)
 	if err != nil {
-		h.logger.Error("[AnimalHandler] 下注失败", zap.Error(err))
+		h.logger.Warn("[AnimalHandler] 下注失败",
+			zap.Uint32("player_id", session.PlayerID),
+			zap.String("bullet_id", bullet.ID),
+			zap.Error(err))
+		// 未结算的子弹不计为未命中，退还下注
+		h.refundBullet(bullet)
 		return
 	}
 
+	// 赢取的金豆入账
+	if outcome.GoldAmount > 0 {
+		if err := h.walletRepo.UpdateGameStatsTx(h.db, session.UserID, 0, int64(outcome.GoldAmount), 0, 0); err != nil {
+			h.logger.Error("[AnimalHandler] 赢取入账失败", zap.Error(err))
+			return
+		}
+	}
+
+	wallet, err := h.walletRepo.GetByUserID(context.Background(), session.UserID)
+	if err != nil {
+		h.logger.Error("[AnimalHandler] 获取钱包失败", zap.Error(err))
+		return
+	}
+
+	if outcome.TargetID != 0 {
+		h.manager.RecordBet(session.PlayerID, outcome.Animal, bullet.BetValue*bullet.Multiple, outcome.GoldAmount)
+	}
+
+	resp := &pb.M_1803Toc{
+		Balance:  proto.Uint64(uint64(wallet.Coins)),
+		Win:      proto.Uint32(outcome.GoldAmount),
+		RedBag:   proto.Uint32(outcome.RedBag),
+		TotalWin: proto.Uint64(outcome.TotalWin),
+		HitId:    proto.Uint32(outcome.TargetID),
+	}
+
 	h.sendMessage(session, 1803, resp)
-	h.dispatchPushes(session, pushes)
 }
 
 func (h *AnimalHandler) handleGetRecord(session *AnimalSession, payload []byte) {
@@ -577,14 +653,30 @@ func (h *AnimalHandler) handleUseSkill(session *AnimalSession, payload []byte) {
 		return
 	}
 
-	resp, pushes, err := h.manager.UseSkill(session.PlayerID, req)
+	if session.Spectator {
+		h.logger.Warn("[AnimalHandler] 观战中不能使用技能", zap.Uint32("player_id", session.PlayerID))
+		return
+	}
+
+	room := h.getRoom(session.RoomID)
+	if room == nil {
+		h.logger.Warn("[AnimalHandler] 玩家不在动物房间", zap.Uint32("player_id", session.PlayerID))
+		return
+	}
+
+	skill, err := h.manager.ConsumeSkill(session.PlayerID, req.GetType())
+	if err != nil {
+		h.logger.Error("[AnimalHandler] 使用技能失败", zap.Error(err))
+		return
+	}
+
+	resp, err := room.UseSkill(session.PlayerID, skill)
 	if err != nil {
 		h.logger.Error("[AnimalHandler] 使用技能失败", zap.Error(err))
 		return
 	}
 
 	h.sendMessage(session, 1806, resp)
-	h.dispatchPushes(session, pushes)
 }
 
 func (h *AnimalHandler) handleGetZooInfo(session *AnimalSession, payload []byte) {
@@ -653,42 +745,59 @@ func (h *AnimalHandler) handleFireBullet(session *AnimalSession, payload []byte)
 		req.BetVal = proto.Uint32(100)
 	}
 
+	if session.Spectator {
+		h.logger.Warn("[AnimalHandler] 观战中不能下注", zap.Uint32("player_id", session.PlayerID))
+		return
+	}
+
 	// 获取下注金额
 	betVal := req.GetBetVal()
 	if betVal == 0 {
 		betVal = 100 // 默认最小下注值
 	}
 
-	// 扣除金币
-	wallet, err := h.walletRepo.GetByUserID(context.Background(), session.UserID)
-	if err != nil {
-		h.logger.Error("[AnimalHandler] 获取钱包失败", zap.Error(err))
+	// 只能从自己的座位发射
+	room := h.getRoom(session.RoomID)
+	if room == nil {
+		h.logger.Warn("[AnimalHandler] 玩家不在动物房间", zap.Uint32("player_id", session.PlayerID))
 		return
 	}
+	if err := room.CheckSeat(session.PlayerID, req.GetSeat()); err != nil {
+		h.logger.Warn("[AnimalHandler] 发射座位无效",
+			zap.Uint32("player_id", session.PlayerID),
+			zap.Uint32("seat", req.GetSeat()),
+			zap.Error(err))
+		return
+	}
+	trajectory := animal.Trajectory{
+		Angle:   req.GetAngle(),
+		FiredAt: animal.FireTime(req.GetTime(), time.Now()),
+	}
 
-	if wallet.Coins < int64(betVal) {
-		h.logger.Warn("[AnimalHandler] 余额不足",
+	// 扣除金币（游戏币不足时不扣除，并发发射不会扣成负数）
+	if err := h.walletRepo.DeductBetTx(h.db, session.UserID, int64(betVal)); err != nil {
+		h.logger.Warn("[AnimalHandler] 扣除金币失败",
 			zap.Uint("user_id", session.UserID),
 			zap.Uint32("bet_val", betVal),
-			zap.Int64("balance", wallet.Coins))
+			zap.Error(err))
 		return
 	}
 
-	// 扣除金币
-	if err := h.walletRepo.DeductBalance(context.Background(), session.UserID, int64(betVal)); err != nil {
-		h.logger.Error("[AnimalHandler] 扣除金币失败", zap.Error(err))
-		return
+	// 返回扣除后的余额（已扣费，读取失败也要发射子弹）
+	var balance int64
+	if wallet, err := h.walletRepo.GetByUserID(context.Background(), session.UserID); err != nil {
+		h.logger.Error("[AnimalHandler] 获取钱包失败", zap.Error(err))
+	} else {
+		balance = wallet.Coins
 	}
-	// 更新本地钱包余额以便返回正确的余额
-	wallet.Coins -= int64(betVal)
 
 	// 使用子弹管理器创建子弹
-	bullet := h.bulletManager.CreateBullet(session.PlayerID, betVal, 1) // 默认倍数为1
+	bullet := h.bulletManager.CreateBullet(session.PlayerID, betVal, 1, trajectory) // 默认倍数为1
 
 	// 构造响应
 	resp := &pb.M_1815Toc{
 		BulletId: proto.String(bullet.ID),
-		Balance:  proto.Uint64(uint64(wallet.Coins)),
+		Balance:  proto.Uint64(uint64(balance)),
 	}
 
 	h.sendMessage(session, 1815, resp)
@@ -696,77 +805,54 @@ func (h *AnimalHandler) handleFireBullet(session *AnimalSession, payload []byte)
 	h.logger.Info("[AnimalHandler] 发射子弹",
 		zap.String("bullet_id", bullet.ID),
 		zap.Uint32("bet_val", betVal),
-		zap.Int64("balance", wallet.Coins),
+		zap.Int64("balance", balance),
 		zap.Int("bullet_count", h.bulletManager.GetBulletCount(session.PlayerID)))
 }
 
-func (h *AnimalHandler) sendMessage(session *AnimalSession, msgID uint16, msg proto.Message) {
-	data, err := session.Codec.Encode(msgID, msg)
-	if err != nil {
-		h.logger.Error("[AnimalHandler] 编码失败", zap.Error(err))
-		return
+// refundBullet 退还未结算子弹的下注（子弹超时或无法判定命中），玩家已离线时只记录日志
+func (h *AnimalHandler) refundBullet(bullet *animal.Bullet) {
+	var userID uint
+	h.mu.RLock()
+	for _, session := range h.playerSessions[bullet.PlayerID] {
+		userID = session.UserID
+		break
 	}
+	h.mu.RUnlock()
 
-	session.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
-	if err := session.Conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
-		h.logger.Error("[AnimalHandler] 发送失败", zap.Error(err))
+	amount := int64(bullet.BetValue) * int64(bullet.Multiple)
+	if userID == 0 {
+		h.logger.Warn("[AnimalHandler] 子弹未结算，玩家已离线，无法退还下注",
+			zap.String("bullet_id", bullet.ID),
+			zap.Uint32("player_id", bullet.PlayerID),
+			zap.Int64("amount", amount))
+		return
 	}
-}
 
-func (h *AnimalHandler) dispatchPushes(origin *AnimalSession, pushes []animal.PushMessage) {
-	if len(pushes) == 0 {
+	if err := h.walletRepo.UpdateGameStatsTx(h.db, userID, -amount, 0, 0, 0); err != nil {
+		h.logger.Error("[AnimalHandler] 退还子弹下注失败",
+			zap.String("bullet_id", bullet.ID),
+			zap.Uint("user_id", userID),
+			zap.Error(err))
 		return
 	}
 
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	for _, push := range pushes {
-		targets := h.resolveTargets(push, origin)
-		if len(targets) == 0 {
-			continue
-		}
-
-		data, err := origin.Codec.Encode(push.MsgID, push.Message)
-		if err != nil {
-			h.logger.Error("[AnimalHandler] 推送编码失败", zap.Error(err))
-			continue
-		}
-
-		for _, sess := range targets {
-			sess.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
-			if err := sess.Conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
-				h.logger.Warn("[AnimalHandler] 推送失败", zap.Error(err))
-			}
-		}
-	}
+	h.logger.Info("[AnimalHandler] 子弹未结算，已退还下注",
+		zap.String("bullet_id", bullet.ID),
+		zap.Uint("user_id", userID),
+		zap.Int64("amount", amount))
 }
 
-func (h *AnimalHandler) resolveTargets(push animal.PushMessage, origin *AnimalSession) []*AnimalSession {
-	recipients := make([]*AnimalSession, 0)
-
-	if len(push.Targets) == 0 {
-		for _, session := range h.sessions {
-			if push.ZooType != 0 && session.ZooType != push.ZooType {
-				continue
-			}
-			recipients = append(recipients, session)
-		}
-		return recipients
+func (h *AnimalHandler) sendMessage(session *AnimalSession, msgID uint16, msg proto.Message) {
+	data, err := session.Codec.Encode(msgID, msg)
+	if err != nil {
+		h.logger.Error("[AnimalHandler] 编码失败", zap.Error(err))
+		return
 	}
 
-	for _, playerID := range push.Targets {
-		if sessions, ok := h.playerSessions[playerID]; ok {
-			for _, session := range sessions {
-				if push.ZooType != 0 && session.ZooType != push.ZooType {
-					continue
-				}
-				recipients = append(recipients, session)
-			}
-		}
+	session.Conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if err := session.Conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
+		h.logger.Error("[AnimalHandler] 发送失败", zap.Error(err))
 	}
-
-	return recipients
 }
 
 func (h *AnimalHandler) getOrCreateTestUser() uint {
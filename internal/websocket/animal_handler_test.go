@@ -34,6 +34,29 @@ func setupTestAnimalDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// setupTestActivityDB 创建包含活动场表的测试数据库
+func setupTestActivityDB(t *testing.T) *gorm.DB {
+	db := setupTestAnimalDB(t)
+	if err := db.AutoMigrate(&models.ActivityRecord{}, &models.ActivityRanking{}); err != nil {
+		t.Fatalf("failed to migrate activity tables: %v", err)
+	}
+	return db
+}
+
+// newTestAnimalRouter 创建二进制协议路由器，测试结束时停止活动场和默认房间
+func newTestAnimalRouter(t *testing.T) *BinaryProtocolRouter {
+	logger := zap.NewNop()
+	db := setupTestActivityDB(t)
+
+	arena := NewActivityArena(db, nil, logger)
+	router := NewBinaryProtocolRouter(db, logger, arena)
+	t.Cleanup(func() {
+		arena.Stop()
+		router.GetAnimalRoom(1).Stop()
+	})
+	return router
+}
+
 func TestNewAnimalHandler(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	db := setupTestAnimalDB(t)
@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"sort"
+
+	"github.com/wfunc/slot-game/internal/game/animal"
+)
+
+// collectProfitStats 按房间ID排序汇总房间的实时盈亏
+func collectProfitStats(rooms []*animal.AnimalRoom) []animal.ProfitStats {
+	stats := make([]animal.ProfitStats, 0, len(rooms))
+	for _, room := range rooms {
+		stats = append(stats, room.GetProfitStats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].RoomID < stats[j].RoomID })
+	return stats
+}
+
+// profitRooms 复制房间列表（房间推送时会获取 h.mu，不能持锁访问房间）
+func (h *AnimalHandler) profitRooms() []*animal.AnimalRoom {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	rooms := make([]*animal.AnimalRoom, 0, len(h.animalRooms))
+	for _, room := range h.animalRooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// ProfitStats 获取所有动物房间的实时盈亏（P值、W值、RTP）
+func (h *AnimalHandler) ProfitStats() []animal.ProfitStats {
+	return collectProfitStats(h.profitRooms())
+}
+
+// SetProfitConfig 更新所有已有动物房间的盈亏控制配置
+func (h *AnimalHandler) SetProfitConfig(config animal.ProfitConfig) {
+	for _, room := range h.profitRooms() {
+		room.SetProfitConfig(config)
+	}
+}
+
+// profitRooms 复制房间列表
+func (r *BinaryProtocolRouter) profitRooms() []*animal.AnimalRoom {
+	r.animalRoomsMutex.RLock()
+	defer r.animalRoomsMutex.RUnlock()
+
+	rooms := make([]*animal.AnimalRoom, 0, len(r.animalRooms))
+	for _, room := range r.animalRooms {
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// ProfitStats 获取所有动物房间的实时盈亏（P值、W值、RTP）
+func (r *BinaryProtocolRouter) ProfitStats() []animal.ProfitStats {
+	return collectProfitStats(r.profitRooms())
+}
+
+// SetProfitConfig 更新所有已有动物房间的盈亏控制配置
+func (r *BinaryProtocolRouter) SetProfitConfig(config animal.ProfitConfig) {
+	for _, room := range r.profitRooms() {
+		room.SetProfitConfig(config)
+	}
+}
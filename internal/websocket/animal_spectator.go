@@ -0,0 +1,212 @@
+package websocket
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/wfunc/slot-game/internal/game/animal"
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var errSpectatorBet = errors.New("观战中不能下注")
+
+// pickWatchRoom 选择观战房间：指定房间ID时取该房间，否则取该场类型（不指定时为全部房间）人数最多的房间
+func pickWatchRoom(rooms map[uint32]*animal.AnimalRoom, zooType pb.EZooType, roomID uint32) *animal.AnimalRoom {
+	if roomID != 0 {
+		return rooms[roomID]
+	}
+
+	ids := make([]uint32, 0, len(rooms))
+	for id := range rooms {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var best *animal.AnimalRoom
+	var bestPlayers uint32
+	for _, id := range ids {
+		room := rooms[id]
+		if zooType != 0 && room.GetRoomType() != zooType {
+			continue
+		}
+		if players := room.GetPlayerCount(); best == nil || players > bestPlayers {
+			best, bestPlayers = room, players
+		}
+	}
+	return best
+}
+
+// leaveCurrentRoom 离开当前所在的房间：玩家离开普通动物房间，观众停止观战
+// 活动场玩家保留本场活动的金豆和积分，不从活动中移除
+func (h *AnimalHandler) leaveCurrentRoom(session *AnimalSession) {
+	if session.RoomID == 0 {
+		return
+	}
+
+	if session.RoomID == activityRoomID {
+		if session.Spectator {
+			h.activityRoom.Unwatch(session.ID)
+		}
+	} else {
+		if room := h.getRoom(session.RoomID); room != nil {
+			if session.Spectator {
+				room.Unwatch(session.ID)
+			} else {
+				room.LeaveRoom(session.PlayerID)
+			}
+			h.logger.Info("[AnimalHandler] 玩家离开房间",
+				zap.Uint32("player_id", session.PlayerID),
+				zap.Uint32("room_id", session.RoomID),
+				zap.Bool("spectator", session.Spectator),
+				zap.Uint32("current_players", room.GetPlayerCount()))
+		}
+	}
+
+	session.ZooType = 0
+	session.RoomID = 0
+	session.Spectator = false
+}
+
+// handleWatchRoom 观战动物房间（1816）
+func (h *AnimalHandler) handleWatchRoom(session *AnimalSession, payload []byte) {
+	req := &pb.M_1816Tos{}
+	if err := proto.Unmarshal(payload, req); err != nil {
+		h.logger.Error("[AnimalHandler] 解析观战房间失败", zap.Error(err))
+		return
+	}
+
+	// 复制房间列表后再查询人数（房间推送时会获取 h.mu）
+	h.mu.RLock()
+	rooms := make(map[uint32]*animal.AnimalRoom, len(h.animalRooms))
+	for id, room := range h.animalRooms {
+		rooms[id] = room
+	}
+	h.mu.RUnlock()
+
+	room := pickWatchRoom(rooms, req.GetType(), req.GetRoomId())
+	if room == nil {
+		h.logger.Warn("[AnimalHandler] 观战房间不存在",
+			zap.String("room_type", req.GetType().String()),
+			zap.Uint32("room_id", req.GetRoomId()))
+		return
+	}
+
+	h.leaveCurrentRoom(session)
+
+	resp, err := room.Watch(session.ID)
+	if err != nil {
+		h.logger.Warn("[AnimalHandler] 观战房间失败",
+			zap.Uint32("room_id", room.GetRoomID()),
+			zap.Error(err))
+		return
+	}
+
+	session.ZooType = room.GetRoomType()
+	session.RoomID = room.GetRoomID()
+	session.Spectator = true
+
+	h.sendMessage(session, 1816, resp)
+}
+
+// handleLookActivity 观战活动场（1879）
+func (h *AnimalHandler) handleLookActivity(session *AnimalSession, payload []byte) {
+	req := &pb.M_1879Tos{}
+	if err := proto.Unmarshal(payload, req); err != nil {
+		h.logger.Error("[AnimalHandler] 解析观战活动场失败", zap.Error(err))
+		return
+	}
+
+	h.leaveCurrentRoom(session)
+
+	resp, err := h.activityRoom.Look(req.GetAgentId(), session.ID)
+	if err != nil {
+		h.logger.Warn("[AnimalHandler] 观战活动场失败",
+			zap.Uint32("player_id", session.PlayerID),
+			zap.Error(err))
+		return
+	}
+
+	session.ZooType = 0
+	session.RoomID = activityRoomID
+	session.Spectator = true
+
+	h.sendMessage(session, 1879, resp)
+}
+
+// unwatchRoom 观众离开观战的房间
+func (r *BinaryProtocolRouter) unwatchRoom(clientID string, roomID uint32) {
+	if roomID == activityRoomID {
+		r.activityRoom.Unwatch(clientID)
+		return
+	}
+	if room := r.GetAnimalRoom(roomID); room != nil {
+		room.Unwatch(clientID)
+	}
+}
+
+// handleAnimalWatchRoom 处理观战动物房间（1816）
+func (r *BinaryProtocolRouter) handleAnimalWatchRoom(client *ProtocolClient, msg *ClientMessage) (*ServerMessage, error) {
+	req := &pb.M_1816Tos{}
+	if err := proto.Unmarshal(msg.Data, req); err != nil {
+		r.logger.Error("[路由] 解析1816请求失败", zap.Error(err))
+		return nil, err
+	}
+
+	r.animalRoomsMutex.RLock()
+	room := pickWatchRoom(r.animalRooms, req.GetType(), req.GetRoomId())
+	r.animalRoomsMutex.RUnlock()
+	if room == nil {
+		return nil, animal.ErrRoomNotFound
+	}
+
+	r.leaveForWatch(client)
+
+	resp, err := room.Watch(client.ID)
+	if err != nil {
+		r.clientManager.LeaveRoom(client.ID)
+		return nil, err
+	}
+	r.clientManager.WatchRoom(client.ID, room.GetRoomID())
+
+	return r.protoResponse(msg, resp)
+}
+
+// handleLookActivity 处理观战活动场（1879）
+func (r *BinaryProtocolRouter) handleLookActivity(client *ProtocolClient, msg *ClientMessage) (*ServerMessage, error) {
+	req := &pb.M_1879Tos{}
+	if err := proto.Unmarshal(msg.Data, req); err != nil {
+		r.logger.Error("[路由] 解析1879请求失败", zap.Error(err))
+		return nil, err
+	}
+
+	r.leaveForWatch(client)
+
+	resp, err := r.activityRoom.Look(req.GetAgentId(), client.ID)
+	if err != nil {
+		r.clientManager.LeaveRoom(client.ID)
+		return nil, err
+	}
+	r.clientManager.WatchRoom(client.ID, activityRoomID)
+
+	return r.protoResponse(msg, resp)
+}
+
+// leaveForWatch 观战前离开当前房间：普通动物房间的玩家让出座位，观众停止之前的观战
+func (r *BinaryProtocolRouter) leaveForWatch(client *ProtocolClient) {
+	managedClient := r.clientManager.GetClient(client.ID)
+	if managedClient == nil {
+		r.clientManager.AddClient(client)
+		return
+	}
+
+	switch {
+	case managedClient.Spectator:
+		r.unwatchRoom(client.ID, managedClient.RoomID)
+	case managedClient.RoomID != 0 && managedClient.RoomID != activityRoomID:
+		if room := r.GetAnimalRoom(managedClient.RoomID); room != nil {
+			room.RemovePlayerByClientID(client.ID)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/wfunc/slot-game/internal/game/animal"
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// queued 取出客户端发送队列中的消息数
+func queued(client *ProtocolClient) int {
+	count := 0
+	for {
+		select {
+		case <-client.send:
+			count++
+		default:
+			return count
+		}
+	}
+}
+
+func TestClientManagerSpectatorBroadcast(t *testing.T) {
+	logger := zap.NewNop()
+	manager := NewClientManager(logger)
+
+	player := NewProtocolClient("player", nil, nil, logger)
+	watcher := NewProtocolClient("watcher", nil, nil, logger)
+	slow := NewProtocolClient("slow", nil, nil, logger)
+	for _, client := range []*ProtocolClient{player, watcher, slow} {
+		manager.AddClient(client)
+	}
+	manager.JoinRoom("player", 1, 7)
+	manager.WatchRoom("watcher", 1)
+	manager.WatchRoom("slow", 1)
+
+	if count := manager.GetSpectatorCount(1); count != 2 {
+		t.Fatalf("expected 2 spectators, got %d", count)
+	}
+	if len(manager.GetRoomClients(1)) != 1 {
+		t.Errorf("spectators should not take player slots")
+	}
+
+	manager.BroadcastToRoom(1, 1887, []byte{1, 2, 3})
+	for _, client := range []*ProtocolClient{player, watcher, slow} {
+		if count := queued(client); count != 1 {
+			t.Errorf("client %s expected 1 broadcast, got %d", client.ID, count)
+		}
+	}
+
+	// 指定玩家的推送不发给观众
+	manager.SendToRoomPlayers(1, []uint32{7, 0}, 1875, []byte{1})
+	if queued(player) != 1 || queued(watcher) != 0 {
+		t.Errorf("targeted push should only reach players")
+	}
+
+	// 发送队列已满的观众丢弃推送，不阻塞其他客户端
+	for i := 0; i < cap(slow.send); i++ {
+		slow.send <- []byte{0}
+	}
+	manager.BroadcastToRoom(1, 1888, []byte{4})
+	if queued(watcher) != 1 || queued(player) != 1 {
+		t.Errorf("broadcast should still reach other clients")
+	}
+	if count := queued(slow); count != cap(slow.send) {
+		t.Errorf("full spectator queue should drop push, got %d messages", count)
+	}
+
+	// 观众坐下后不再计入观战人数
+	manager.JoinRoom("watcher", 1, 8)
+	managed := manager.GetClient("watcher")
+	if managed.Spectator || manager.GetSpectatorCount(1) != 1 || len(manager.GetRoomClients(1)) != 2 {
+		t.Errorf("spectator joining as player should leave spectators: spectator=%v count=%d", managed.Spectator, manager.GetSpectatorCount(1))
+	}
+
+	manager.RemoveClient("slow")
+	if count := manager.GetSpectatorCount(1); count != 0 {
+		t.Errorf("expected 0 spectators after removal, got %d", count)
+	}
+}
+
+func TestAnimalRoomSpectatorCap(t *testing.T) {
+	logger := zap.NewNop()
+	db := setupTestAnimalDB(t)
+
+	handler := NewAnimalHandler(db, logger)
+	defer handler.Cleanup()
+
+	room := handler.animalRooms[1]
+	room.SetMaxSpectators(1)
+
+	resp, err := room.Watch("first")
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	if resp.GetRoomId() != 1 || resp.GetWatchers() != 1 || len(resp.GetAnimals()) == 0 {
+		t.Errorf("unexpected watch response: room=%d watchers=%d animals=%d", resp.GetRoomId(), resp.GetWatchers(), len(resp.GetAnimals()))
+	}
+	if _, err := room.Watch("second"); err != animal.ErrSpectatorsFull {
+		t.Errorf("expected ErrSpectatorsFull, got %v", err)
+	}
+	if _, err := room.Watch("first"); err != nil {
+		t.Errorf("watching again should succeed: %v", err)
+	}
+	if room.GetPlayerCount() != 0 {
+		t.Errorf("spectators should not take seats")
+	}
+
+	session := &AnimalSession{ID: "first", RoomID: 1, Spectator: true}
+	handler.leaveCurrentRoom(session)
+	if room.GetSpectatorCount() != 0 || session.RoomID != 0 || session.Spectator {
+		t.Errorf("leaveCurrentRoom should stop watching")
+	}
+	if _, err := room.Watch("second"); err != nil {
+		t.Errorf("Watch after leave failed: %v", err)
+	}
+}
+
+func TestBinaryProtocolRouterSpectator(t *testing.T) {
+	logger := zap.NewNop()
+	router := newTestAnimalRouter(t)
+
+	client := NewProtocolClient("spectator", nil, nil, logger)
+	data, _ := proto.Marshal(&pb.M_1816Tos{})
+	resp, err := router.HandleMessage(client, &ClientMessage{Cmd: 1816, Flag: 3, Data: data})
+	if err != nil {
+		t.Fatalf("watch room failed: %v", err)
+	}
+	watch := &pb.M_1816Toc{}
+	if err := proto.Unmarshal(resp.Data, watch); err != nil {
+		t.Fatalf("failed to decode 1816: %v", err)
+	}
+	if watch.GetRoomId() != 1 || watch.GetWatchers() != 1 || resp.Flag != 3 {
+		t.Errorf("unexpected 1816 response: room=%d watchers=%d flag=%d", watch.GetRoomId(), watch.GetWatchers(), resp.Flag)
+	}
+	if router.clientManager.GetSpectatorCount(1) != 1 {
+		t.Errorf("client should be registered as spectator")
+	}
+
+	// 观众不能下注
+	bet, _ := proto.Marshal(&pb.M_1803Tos{Id: proto.Uint32(1)})
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1803, Data: bet}); err != errSpectatorBet {
+		t.Errorf("expected errSpectatorBet on 1803, got %v", err)
+	}
+	fire, _ := proto.Marshal(&pb.M_1815Tos{BetVal: proto.Uint32(100)})
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1815, Data: fire}); err != errSpectatorBet {
+		t.Errorf("expected errSpectatorBet on 1815, got %v", err)
+	}
+
+	// 切换到活动场观战
+	look, _ := proto.Marshal(&pb.M_1879Tos{AgentId: proto.Uint32(0)})
+	resp, err = router.HandleMessage(client, &ClientMessage{Cmd: 1879, Data: look})
+	if err != nil {
+		t.Fatalf("look activity failed: %v", err)
+	}
+	lookResp := &pb.M_1879Toc{}
+	if err := proto.Unmarshal(resp.Data, lookResp); err != nil {
+		t.Fatalf("failed to decode 1879: %v", err)
+	}
+	if lookResp.GetState() == 0 || len(lookResp.GetReward()) == 0 {
+		t.Errorf("unexpected 1879 response: state=%d rewards=%d", lookResp.GetState(), len(lookResp.GetReward()))
+	}
+	if router.GetAnimalRoom(1).GetSpectatorCount() != 0 || router.activityRoom.GetSpectatorCount() != 1 {
+		t.Errorf("spectator should move from room 1 to activity")
+	}
+	hit, _ := proto.Marshal(&pb.M_1872Tos{Id: proto.Uint32(1), BetVal: proto.Uint32(100)})
+	if _, err := router.HandleMessage(client, &ClientMessage{Cmd: 1872, Data: hit}); err == nil {
+		t.Errorf("spectator should not hit activity animals")
+	}
+
+	router.OnClientDisconnect(client)
+	if router.activityRoom.GetSpectatorCount() != 0 || router.clientManager.GetSpectatorCount(activityRoomID) != 0 {
+		t.Errorf("disconnect should remove spectator")
+	}
+}
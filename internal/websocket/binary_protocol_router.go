@@ -3,8 +3,9 @@ package websocket
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/wfunc/slot-game/internal/game/animal"
 	"github.com/wfunc/slot-game/internal/pb"
 	"go.uber.org/zap"
@@ -16,13 +17,15 @@ import (
 // 实现 MessageHandler 接口
 type BinaryProtocolRouter struct {
 	slotHandler      *SlotHandler
-	animalHandler    *AnimalHandler
 	configHandler    *ConfigHandler
 	codec            *ProtobufCodec
 	clientManager    *ClientManager
 	pushManager      *PushManager
 	animalRooms      map[uint32]*animal.AnimalRoom // roomID -> room
 	animalRoomsMutex sync.RWMutex
+	activityRoom     *animal.ActivityRoom  // 活动场（与 protobuf 连接共用）
+	bulletManager    *animal.BulletManager // 已发射待结算的子弹
+	nextGuestID      atomic.Uint32         // 游客玩家ID序号
 	logger           *zap.Logger
 	db               *gorm.DB
 }
@@ -30,25 +33,27 @@ type BinaryProtocolRouter struct {
 // 确保 BinaryProtocolRouter 实现了 MessageHandler 接口
 var _ MessageHandler = (*BinaryProtocolRouter)(nil)
 
-// NewBinaryProtocolRouter 创建二进制协议路由器
-func NewBinaryProtocolRouter(db *gorm.DB, logger *zap.Logger) *BinaryProtocolRouter {
+// NewBinaryProtocolRouter 创建二进制协议路由器，活动场由 arena 提供
+func NewBinaryProtocolRouter(db *gorm.DB, logger *zap.Logger, arena *ActivityArena) *BinaryProtocolRouter {
 	clientManager := NewClientManager(logger)
 	pushManager := NewPushManager(clientManager, logger)
 
 	r := &BinaryProtocolRouter{
 		slotHandler:   NewSlotHandler(db),
-		animalHandler: NewAnimalHandler(db, logger),
 		configHandler: NewConfigHandler(db, logger),
 		codec:         NewProtobufCodec(),
 		clientManager: clientManager,
 		pushManager:   pushManager,
 		animalRooms:   make(map[uint32]*animal.AnimalRoom),
+		activityRoom:  arena.Room(),
 		logger:        logger,
 		db:            db,
 	}
+	r.bulletManager = animal.NewBulletManager(r.refundBullet) // 超时未结算的子弹退还下注
 
 	// 初始化默认房间
 	r.initDefaultAnimalRoom()
+	arena.Subscribe(pushManager.CreatePushCallback(activityRoomID))
 
 	return r
 }
@@ -132,6 +137,16 @@ func (r *BinaryProtocolRouter) routeToAnimalHandler(client *ProtocolClient, msg
 		return r.handleAnimalBet(client, msg)
 	case 1815: // 发射子弹
 		return r.handleAnimalFireBullet(client, msg)
+	case 1816: // 观战房间
+		return r.handleAnimalWatchRoom(client, msg)
+	case 1871: // 进入活动场
+		return r.handleEnterActivity(client, msg)
+	case 1872: // 活动场打动物
+		return r.handleHitActivityAnimal(client, msg)
+	case 1873: // 活动场排行榜
+		return r.handleGetActivityRank(client, msg)
+	case 1879: // 观战活动场
+		return r.handleLookActivity(client, msg)
 	default:
 		// 其他命令暂时返回空响应
 		response := &ServerMessage{
@@ -240,11 +255,9 @@ func (r *BinaryProtocolRouter) createSlotResponse(client *ProtocolClient, msg *C
 		// 创建正确的protobuf响应
 		r.logger.Info("[路由] 处理1901命令 - 进入房间")
 
-		devID := "SLOT001"
-		devNo := uint32(1)
 		cfg := &pb.PConfig{
-			DevId: &devID,
-			DevNo: &devNo,
+			DevId: proto.String(r.slotHandler.devID),
+			DevNo: proto.Uint32(r.slotHandler.devNo),
 		}
 
 		respProto := &pb.M_1901Toc{
@@ -304,125 +317,6 @@ func (r *BinaryProtocolRouter) GetSlotHandler() *SlotHandler {
 	return r.slotHandler
 }
 
-// GetAnimalHandler 获取Animal处理器
-func (r *BinaryProtocolRouter) GetAnimalHandler() *AnimalHandler {
-	return r.animalHandler
-}
-
-// AnimalDieInfo 动物死亡信息
-type AnimalDieInfo struct {
-	ID     uint32
-	Win    uint32
-	RedBag uint32
-}
-
-// pushHitAnimal 推送玩家打中动物（1899）
-func (r *BinaryProtocolRouter) pushHitAnimal(roomID uint32, playerID uint32, animalID uint32) {
-	pushMsg := &pb.M_1899Toc{
-		RoleId: proto.Uint32(playerID),
-		Id:     proto.Uint32(animalID),
-	}
-
-	data, err := proto.Marshal(pushMsg)
-	if err != nil {
-		r.logger.Error("[路由] 编码1899推送失败", zap.Error(err))
-		return
-	}
-
-	r.logger.Info("[路由] 推送1899 - 玩家打中动物",
-		zap.Uint32("room_id", roomID),
-		zap.Uint32("player_id", playerID),
-		zap.Uint32("animal_id", animalID))
-
-	r.clientManager.BroadcastToRoom(roomID, 1899, data)
-}
-
-// pushAnimalDie 推送动物死亡（1884）
-func (r *BinaryProtocolRouter) pushAnimalDie(roomID uint32, playerID uint32, skillType pb.EAnimalType, animals []AnimalDieInfo) {
-	// 构建动物列表
-	var animalList []*pb.PAnimalOne
-	for _, animal := range animals {
-		animalList = append(animalList, &pb.PAnimalOne{
-			Id:     proto.Uint32(animal.ID),
-			Win:    proto.Uint32(animal.Win),
-			RedBag: proto.Uint32(animal.RedBag),
-		})
-	}
-
-	pushMsg := &pb.M_1884Toc{
-		RoleId: proto.Uint32(playerID),
-		Type:   &skillType,
-		Ids:    animalList,
-	}
-
-	data, err := proto.Marshal(pushMsg)
-	if err != nil {
-		r.logger.Error("[路由] 编码1884推送失败", zap.Error(err))
-		return
-	}
-
-	r.logger.Info("[路由] 推送1884 - 动物死亡",
-		zap.Uint32("room_id", roomID),
-		zap.Uint32("player_id", playerID),
-		zap.String("skill_type", skillType.String()),
-		zap.Int("animal_count", len(animals)))
-
-	r.clientManager.BroadcastToRoom(roomID, 1884, data)
-}
-
-// pushAnimalComing 推送动物即将进场（1883）
-func (r *BinaryProtocolRouter) pushAnimalComing(roomID uint32, animalType pb.EAnimal, timeSeconds uint32) {
-	pushMsg := &pb.M_1883Toc{
-		Animal: &animalType,
-		Time:   proto.Uint32(timeSeconds),
-	}
-
-	data, err := proto.Marshal(pushMsg)
-	if err != nil {
-		r.logger.Error("[路由] 编码1883推送失败", zap.Error(err))
-		return
-	}
-
-	r.logger.Info("[路由] 推送1883 - 动物即将进场",
-		zap.Uint32("room_id", roomID),
-		zap.String("animal_type", animalType.String()),
-		zap.Uint32("time", timeSeconds))
-
-	r.clientManager.BroadcastToRoom(roomID, 1883, data)
-}
-
-// getAnimalTypeFromID 根据动物ID获取动物类型（模拟）
-func (r *BinaryProtocolRouter) getAnimalTypeFromID(animalID uint32) pb.EAnimal {
-	// 模拟：根据ID确定动物类型
-	types := []pb.EAnimal{
-		pb.EAnimal_turtle,
-		pb.EAnimal_cock,
-		pb.EAnimal_dog,
-		pb.EAnimal_monkey,
-		pb.EAnimal_horse,
-		pb.EAnimal_ox,
-		pb.EAnimal_panda,
-		pb.EAnimal_hippo,
-		pb.EAnimal_lion,
-		pb.EAnimal_elephant,
-		pb.EAnimal_pikachu,
-		pb.EAnimal_bomber,
-	}
-	return types[animalID%uint32(len(types))]
-}
-
-// getSkillTypeForAnimal 获取动物死亡时的技能类型
-func (r *BinaryProtocolRouter) getSkillTypeForAnimal(animalType pb.EAnimal) pb.EAnimalType {
-	switch animalType {
-	case pb.EAnimal_pikachu:
-		return pb.EAnimalType_lightning // 皮卡丘 - 闪电效果
-	case pb.EAnimal_bomber:
-		return pb.EAnimalType_boom // 炸弹人 - 全屏爆炸
-	default:
-		return pb.EAnimalType_type_normal // 普通动物
-	}
-}
-
 // OnClientDisconnect 处理客户端断开连接
 func (r *BinaryProtocolRouter) OnClientDisconnect(client *ProtocolClient) {
 	r.logger.Info("[路由] 处理客户端断开连接",
@@ -430,7 +324,10 @@ func (r *BinaryProtocolRouter) OnClientDisconnect(client *ProtocolClient) {
 
 	// 从ClientManager中移除客户端
 	managedClient := r.clientManager.GetClient(client.ID)
-	if managedClient != nil && managedClient.RoomID > 0 {
+	if managedClient != nil && managedClient.Spectator {
+		// 观众离开观战的房间
+		r.unwatchRoom(client.ID, managedClient.RoomID)
+	} else if managedClient != nil && managedClient.RoomID > 0 {
 		// 从动物房间中移除玩家
 		room := r.GetAnimalRoom(managedClient.RoomID)
 		if room != nil {
@@ -461,11 +358,16 @@ func (r *BinaryProtocolRouter) handleAnimalEnterRoom(client *ProtocolClient, msg
 	client.TotalWin = 0
 	client.mu.Unlock()
 
+	// 观战中的客户端先停止观战
+	if managedClient := r.clientManager.GetClient(client.ID); managedClient != nil && managedClient.Spectator {
+		r.unwatchRoom(client.ID, managedClient.RoomID)
+		r.clientManager.LeaveRoom(client.ID)
+	}
+
 	// 将客户端加入到管理器
 	r.clientManager.AddClient(client)
 
-	// 使用真实的玩家ID (这里暂时使用客户端ID的hash值作为玩家ID)
-	playerID := uint32(len(client.ID)) // TODO: 从用户会话获取真实玩家ID
+	playerID := r.clientPlayerID(client)
 
 	// 尝试加入默认房间（房间ID 1）
 	roomID := uint32(1)
@@ -633,7 +535,10 @@ func (r *BinaryProtocolRouter) handleAnimalLeaveRoom(client *ProtocolClient, msg
 	totalWin := client.TotalWin
 	client.mu.RUnlock()
 
-	// 离开房间
+	// 离开房间（观众停止观战）
+	if managedClient := r.clientManager.GetClient(client.ID); managedClient != nil && managedClient.Spectator {
+		r.unwatchRoom(client.ID, managedClient.RoomID)
+	}
 	r.clientManager.LeaveRoom(client.ID)
 
 	// 构造响应 - 必须包含 total_win 字段
@@ -682,7 +587,7 @@ func (r *BinaryProtocolRouter) handleAnimalBet(client *ProtocolClient, msg *Clie
 	bulletID := req.GetBulletId()
 
 	r.logger.Info("[路由] 子弹击中动物",
-		zap.Uint32("animal_id", animalID),
+		zap.Uint32("client_animal_id", animalID),
 		zap.String("bullet_id", bulletID))
 
 	// 获取客户端所在房间
@@ -690,25 +595,38 @@ func (r *BinaryProtocolRouter) handleAnimalBet(client *ProtocolClient, msg *Clie
 	if managedClient == nil || managedClient.RoomID == 0 {
 		return nil, fmt.Errorf("客户端不在房间中")
 	}
+	if managedClient.Spectator {
+		return nil, errSpectatorBet
+	}
 	roomID := managedClient.RoomID
 	playerID := managedClient.PlayerID
 
-	// 模拟游戏结果（实际应该调用游戏逻辑）
-	// 判断是否击中
-	isHit := true
-	isDead := true
-	winAmount := uint32(0)
-	redBagAmount := uint32(0)
+	// 取出对应的子弹（未指定时使用最早发射的子弹）
+	var bullet *animal.Bullet
+	var err error
+	if bulletID != "" {
+		bullet, err = r.bulletManager.UseBullet(bulletID)
+	} else {
+		bullet, err = r.bulletManager.UseOldestPlayerBullet(playerID)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	// 判断动物是否死亡（模拟：如果ID能被4整除则死亡）
-	if isHit {
-		// 推送1899 - 玩家打中动物但未打死
-		r.pushHitAnimal(roomID, playerID, animalID)
+	room := r.GetAnimalRoom(roomID)
+	if room == nil {
+		return nil, animal.ErrRoomNotFound
+	}
 
-		if isDead {
-			winAmount = 100 * (1 + animalID%5) // 根据动物ID生成赢取金额
-		}
+	// 由房间引擎按子弹弹道判定命中并结算，打动物和死亡推送由房间广播
+	outcome, err := room.ProcessShot(playerID, bullet.BetValue, bullet.Multiple, bullet.Trajectory)
+	if err != nil {
+		// 未结算的子弹不计为未命中，退还下注
+		r.refundBullet(bullet)
+		return nil, err
 	}
+	winAmount := outcome.GoldAmount
+	redBagAmount := outcome.RedBag
 
 	// 使用客户端的真实余额并更新
 	client.mu.Lock()
@@ -723,10 +641,11 @@ func (r *BinaryProtocolRouter) handleAnimalBet(client *ProtocolClient, msg *Clie
 
 	// 构造响应
 	respProto := &pb.M_1803Toc{
-		Balance:  proto.Uint64(currentBalance), // required: 当前余额
-		Win:      proto.Uint32(winAmount),      // required: 赢得金额
-		RedBag:   proto.Uint32(redBagAmount),   // required: 红包金额
-		TotalWin: proto.Uint64(totalWin),       // required: 本次房间内的累计赢取
+		Balance:  proto.Uint64(currentBalance),   // required: 当前余额
+		Win:      proto.Uint32(winAmount),        // required: 赢得金额
+		RedBag:   proto.Uint32(redBagAmount),     // required: 红包金额
+		TotalWin: proto.Uint64(totalWin),         // required: 本次房间内的累计赢取
+		HitId:    proto.Uint32(outcome.TargetID), // 服务端判定命中的动物
 		// Skill 和 FreeGold 是可选的，暂时不填
 	}
 
@@ -749,23 +668,11 @@ func (r *BinaryProtocolRouter) handleAnimalBet(client *ProtocolClient, msg *Clie
 		zap.Uint16("cmd", response.Cmd),
 		zap.Uint32("flag", response.Flag),
 		zap.Int("data_len", len(response.Data)),
-		zap.Uint32("animal_id", animalID),
+		zap.Uint32("hit_id", outcome.TargetID),
 		zap.Uint32("win", winAmount),
 		zap.Uint32("red_bag", redBagAmount),
 		zap.Uint64("balance", currentBalance))
 
-	// 如果动物死亡，推送1884消息
-	if isDead && winAmount > 0 {
-		// 判断动物类型以确定技能效果
-		animalType := r.getAnimalTypeFromID(animalID)
-		skillType := r.getSkillTypeForAnimal(animalType)
-
-		// 推送动物死亡消息
-		r.pushAnimalDie(roomID, playerID, skillType, []AnimalDieInfo{
-			{ID: animalID, Win: winAmount, RedBag: redBagAmount},
-		})
-	}
-
 	return response, nil
 }
 
@@ -788,8 +695,26 @@ func (r *BinaryProtocolRouter) handleAnimalFireBullet(client *ProtocolClient, ms
 		betVal = 100
 	}
 
-	// 生成子弹ID
-	bulletID := "bullet_" + uuid.New().String()
+	managedClient := r.clientManager.GetClient(client.ID)
+	if managedClient != nil && managedClient.Spectator {
+		return nil, errSpectatorBet
+	}
+	if managedClient == nil || managedClient.RoomID == 0 {
+		return nil, fmt.Errorf("客户端不在房间中")
+	}
+
+	// 只能从自己的座位发射
+	room := r.GetAnimalRoom(managedClient.RoomID)
+	if room == nil {
+		return nil, animal.ErrRoomNotFound
+	}
+	if err := room.CheckSeat(managedClient.PlayerID, req.GetSeat()); err != nil {
+		return nil, err
+	}
+	trajectory := animal.Trajectory{
+		Angle:   req.GetAngle(),
+		FiredAt: animal.FireTime(req.GetTime(), time.Now()),
+	}
 
 	// 扣除下注金额并更新余额
 	client.mu.Lock()
@@ -802,6 +727,9 @@ func (r *BinaryProtocolRouter) handleAnimalFireBullet(client *ProtocolClient, ms
 	balance := client.Balance
 	client.mu.Unlock()
 
+	// 登记子弹，下注（1803）时按子弹结算
+	bulletID := r.bulletManager.CreateBullet(managedClient.PlayerID, betVal, 1, trajectory).ID
+
 	// 构造响应
 	respProto := &pb.M_1815Toc{
 		BulletId: proto.String(bulletID),
@@ -833,6 +761,25 @@ func (r *BinaryProtocolRouter) handleAnimalFireBullet(client *ProtocolClient, ms
 	return response, nil
 }
 
+// refundBullet 把未结算子弹（超时或无法判定命中）的下注退回客户端余额，玩家已离开时余额随连接释放
+func (r *BinaryProtocolRouter) refundBullet(bullet *animal.Bullet) {
+	managedClient := r.clientManager.GetPlayerClient(bullet.PlayerID)
+	if managedClient == nil {
+		return
+	}
+
+	amount := uint64(bullet.BetValue) * uint64(bullet.Multiple)
+	client := managedClient.Client
+	client.mu.Lock()
+	client.Balance += amount
+	client.mu.Unlock()
+
+	r.logger.Info("[路由] 子弹未结算，已退还下注",
+		zap.String("bullet_id", bullet.ID),
+		zap.Uint32("player_id", bullet.PlayerID),
+		zap.Uint64("amount", amount))
+}
+
 // handleGetUserInfo 处理获取用户信息 (2001命令)
 func (r *BinaryProtocolRouter) handleGetUserInfo(client *ProtocolClient, msg *ClientMessage) (*ServerMessage, error) {
 	r.logger.Info("[路由] 处理2001命令 - 获取用户信息",
@@ -8,25 +8,30 @@ import (
 
 // ClientManager 客户端管理器
 type ClientManager struct {
-	clients map[string]*ManagedClient // clientID -> client
-	rooms   map[uint32][]*ManagedClient // roomID -> clients
-	mu      sync.RWMutex
-	logger  *zap.Logger
+	clients    map[string]*ManagedClient // clientID -> client
+	rooms      map[uint32][]*ManagedClient // roomID -> clients
+	spectators map[uint32][]*ManagedClient // roomID -> 观众
+	protocol   *Protocol
+	mu         sync.RWMutex
+	logger     *zap.Logger
 }
 
 // ManagedClient 被管理的客户端
 type ManagedClient struct {
-	Client   *ProtocolClient
-	RoomID   uint32
-	PlayerID uint32
+	Client    *ProtocolClient
+	RoomID    uint32
+	PlayerID  uint32
+	Spectator bool // 观战中（不占座位，不能下注）
 }
 
 // NewClientManager 创建客户端管理器
 func NewClientManager(logger *zap.Logger) *ClientManager {
 	return &ClientManager{
-		clients: make(map[string]*ManagedClient),
-		rooms:   make(map[uint32][]*ManagedClient),
-		logger:  logger,
+		clients:    make(map[string]*ManagedClient),
+		rooms:      make(map[uint32][]*ManagedClient),
+		spectators: make(map[uint32][]*ManagedClient),
+		protocol:   NewProtocol(),
+		logger:     logger,
 	}
 }
 
@@ -78,8 +83,8 @@ func (m *ClientManager) JoinRoom(clientID string, roomID uint32, playerID uint32
 		return
 	}
 
-	// 如果已经在其他房间，先离开
-	if managedClient.RoomID > 0 && managedClient.RoomID != roomID {
+	// 如果已经在其他房间或正在观战，先离开
+	if managedClient.RoomID > 0 && (managedClient.RoomID != roomID || managedClient.Spectator) {
 		m.removeFromRoom(managedClient)
 	}
 
@@ -98,6 +103,31 @@ func (m *ClientManager) JoinRoom(clientID string, roomID uint32, playerID uint32
 		zap.Uint32("player_id", playerID))
 }
 
+// WatchRoom 以观众身份进入房间（不占座位，只接收房间广播）
+func (m *ClientManager) WatchRoom(clientID string, roomID uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	managedClient, exists := m.clients[clientID]
+	if !exists {
+		return
+	}
+
+	// 先离开之前的房间
+	if managedClient.RoomID > 0 {
+		m.removeFromRoom(managedClient)
+	}
+
+	managedClient.RoomID = roomID
+	managedClient.Spectator = true
+	m.spectators[roomID] = append(m.spectators[roomID], managedClient)
+
+	m.logger.Info("[ClientManager] 客户端开始观战",
+		zap.String("client_id", clientID),
+		zap.Uint32("room_id", roomID),
+		zap.Int("spectator_count", len(m.spectators[roomID])))
+}
+
 // LeaveRoom 离开房间
 func (m *ClientManager) LeaveRoom(clientID string) {
 	m.mu.Lock()
@@ -118,35 +148,43 @@ func (m *ClientManager) removeFromRoom(managedClient *ManagedClient) {
 		return
 	}
 
-	clients := m.rooms[roomID]
+	members := m.rooms
+	if managedClient.Spectator {
+		members = m.spectators
+	}
+
+	clients := members[roomID]
 	for i, c := range clients {
 		if c == managedClient {
 			// 从切片中移除
-			m.rooms[roomID] = append(clients[:i], clients[i+1:]...)
+			members[roomID] = append(clients[:i], clients[i+1:]...)
 			break
 		}
 	}
 
 	// 如果房间为空，删除房间
-	if len(m.rooms[roomID]) == 0 {
-		delete(m.rooms, roomID)
+	if len(members[roomID]) == 0 {
+		delete(members, roomID)
 	}
 
 	managedClient.RoomID = 0
 	managedClient.PlayerID = 0
+	managedClient.Spectator = false
 
 	m.logger.Info("[ClientManager] 客户端离开房间",
 		zap.String("client_id", managedClient.Client.ID),
 		zap.Uint32("room_id", roomID))
 }
 
-// BroadcastToRoom 向房间广播消息
+// BroadcastToRoom 向房间广播消息（玩家和观众）
+// 观众的消息只编码一次并以非阻塞方式投递，发送队列已满的观众丢弃本条推送，避免慢连接拖慢整个房间
 func (m *ClientManager) BroadcastToRoom(roomID uint32, msgID uint16, data []byte) {
 	m.mu.RLock()
-	clients := m.rooms[roomID]
+	clients := append([]*ManagedClient(nil), m.rooms[roomID]...)
+	spectators := append([]*ManagedClient(nil), m.spectators[roomID]...)
 	m.mu.RUnlock()
 
-	if len(clients) == 0 {
+	if len(clients) == 0 && len(spectators) == 0 {
 		return
 	}
 
@@ -154,6 +192,7 @@ func (m *ClientManager) BroadcastToRoom(roomID uint32, msgID uint16, data []byte
 		zap.Uint32("room_id", roomID),
 		zap.Uint16("msg_id", msgID),
 		zap.Int("client_count", len(clients)),
+		zap.Int("spectator_count", len(spectators)),
 		zap.Int("data_len", len(data)))
 
 	// 记录需要清理的客户端
@@ -189,12 +228,83 @@ func (m *ClientManager) BroadcastToRoom(roomID uint32, msgID uint16, data []byte
 		}
 	}
 
+	disconnectedClients = append(disconnectedClients, m.broadcastToSpectators(roomID, spectators, msgID, data)...)
+
 	// 清理已断开的客户端
 	for _, clientID := range disconnectedClients {
 		m.RemoveClient(clientID)
 	}
 }
 
+// broadcastToSpectators 向观众投递广播消息，返回已断开的客户端
+func (m *ClientManager) broadcastToSpectators(roomID uint32, spectators []*ManagedClient, msgID uint16, data []byte) []string {
+	if len(spectators) == 0 {
+		return nil
+	}
+
+	encoded, err := m.protocol.EncodeServerMessage(&ServerMessage{
+		ErrorID:    0,
+		DataStatus: 0,
+		Flag:       0,
+		Cmd:        msgID,
+		Data:       data,
+	})
+	if err != nil {
+		m.logger.Error("[ClientManager] 编码观战消息失败",
+			zap.Uint16("msg_id", msgID),
+			zap.Error(err))
+		return nil
+	}
+
+	var disconnected []string
+	dropped := 0
+	for _, managedClient := range spectators {
+		if !managedClient.Client.IsConnected() {
+			disconnected = append(disconnected, managedClient.Client.ID)
+			continue
+		}
+		if !managedClient.Client.TrySendRawMessage(encoded) {
+			dropped++
+		}
+	}
+
+	if dropped > 0 {
+		m.logger.Warn("[ClientManager] 观众发送队列已满，丢弃推送",
+			zap.Uint32("room_id", roomID),
+			zap.Uint16("msg_id", msgID),
+			zap.Int("dropped", dropped))
+	}
+	return disconnected
+}
+
+// SendToRoomPlayers 向房间内指定玩家发送消息
+func (m *ClientManager) SendToRoomPlayers(roomID uint32, playerIDs []uint32, msgID uint16, data []byte) {
+	targets := make(map[uint32]bool, len(playerIDs))
+	for _, playerID := range playerIDs {
+		targets[playerID] = true
+	}
+
+	for _, managedClient := range m.GetRoomClients(roomID) {
+		if !targets[managedClient.PlayerID] || !managedClient.Client.IsConnected() {
+			continue
+		}
+
+		msg := &ServerMessage{
+			ErrorID:    0,
+			DataStatus: 0,
+			Flag:       0,
+			Cmd:        msgID,
+			Data:       data,
+		}
+		if err := managedClient.Client.SendMessage(msg); err != nil {
+			m.logger.Error("[ClientManager] 发送玩家消息失败",
+				zap.String("client_id", managedClient.Client.ID),
+				zap.Uint32("player_id", managedClient.PlayerID),
+				zap.Error(err))
+		}
+	}
+}
+
 // GetClient 获取客户端
 func (m *ClientManager) GetClient(clientID string) *ManagedClient {
 	m.mu.RLock()
@@ -203,6 +313,27 @@ func (m *ClientManager) GetClient(clientID string) *ManagedClient {
 	return m.clients[clientID]
 }
 
+// GetPlayerClient 按玩家ID获取在房间中下注的客户端，玩家已离开时返回 nil
+func (m *ClientManager) GetPlayerClient(playerID uint32) *ManagedClient {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, managedClient := range m.clients {
+		if managedClient.PlayerID == playerID && !managedClient.Spectator {
+			return managedClient
+		}
+	}
+	return nil
+}
+
+// GetSpectatorCount 获取房间观战人数
+func (m *ClientManager) GetSpectatorCount(roomID uint32) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.spectators[roomID])
+}
+
 // GetRoomClients 获取房间内的所有客户端
 func (m *ClientManager) GetRoomClients(roomID uint32) []*ManagedClient {
 	m.mu.RLock()
@@ -285,6 +285,29 @@ func (c *ProtocolClient) SendRawMessage(data []byte) error {
 	}
 }
 
+// TrySendRawMessage 不阻塞地发送原始二进制数据，发送队列已满或客户端已关闭时返回false
+func (c *ProtocolClient) TrySendRawMessage(data []byte) (sent bool) {
+	// 客户端关闭后发送通道已关闭
+	defer func() {
+		if r := recover(); r != nil {
+			sent = false
+		}
+	}()
+
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
 // Close 关闭客户端连接
 func (c *ProtocolClient) Close() {
 	c.once.Do(func() {
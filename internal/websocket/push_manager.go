@@ -58,6 +58,12 @@ func (pm *PushManager) HandleAnimalPush(msg *animal.PushMessage) {
 		}
 	}
 
+	// 指定了目标玩家时只发送给房间内的这些玩家
+	if len(msg.Targets) > 0 {
+		pm.clientManager.SendToRoomPlayers(msg.RoomID, msg.Targets, msg.MsgID, data)
+		return
+	}
+
 	// 广播给房间内的所有客户端
 	pm.clientManager.BroadcastToRoom(msg.RoomID, msg.MsgID, data)
 }
@@ -0,0 +1,235 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// slotBonusState 奖励游戏在 game_states 表中的状态名
+const slotBonusState = "slot_bonus"
+
+// slotBonusStateKey 玩家奖励游戏状态的持久化键（每个玩家最多一个进行中的奖励游戏）
+func slotBonusStateKey(userID uint) string {
+	return fmt.Sprintf("slot_bonus_%d", userID)
+}
+
+// newSlotBonusGame 按奖励游戏特性配置生成奖励游戏（未触发时返回空）
+func newSlotBonusGame(feature *slot.AbstractFeatureConfig, grid [][]int, betAmount int64) *slot.BonusGameState {
+	if !feature.BonusTriggered(grid) {
+		return nil
+	}
+	bonus := slot.NewBonusGame(feature.BonusGame, slot.NewCryptoRandomGenerator(), betAmount)
+	bonus.ID = uuid.New().String()
+	return bonus
+}
+
+// loadBonusGame 加载玩家进行中的奖励游戏（断线重连后继续选择）
+func (h *SlotHandler) loadBonusGame(userID uint) *slot.BonusGameState {
+	var state models.GameState
+	err := h.db.Where("session_id = ? AND current_state = ?", slotBonusStateKey(userID), slotBonusState).First(&state).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			h.logger.Warn("[SlotHandler] 加载奖励游戏失败", zap.Uint("user_id", userID), zap.Error(err))
+		}
+		return nil
+	}
+
+	bonus := &slot.BonusGameState{}
+	if err := json.Unmarshal([]byte(state.StateData), bonus); err != nil {
+		h.logger.Warn("[SlotHandler] 解析奖励游戏失败", zap.Uint("user_id", userID), zap.Error(err))
+		return nil
+	}
+	if !bonus.IsActive() {
+		return nil
+	}
+	return bonus
+}
+
+// saveBonusGame 保存进行中的奖励游戏
+func saveBonusGame(tx *gorm.DB, userID uint, bonus *slot.BonusGameState) error {
+	data, err := json.Marshal(bonus)
+	if err != nil {
+		return fmt.Errorf("序列化奖励游戏失败: %w", err)
+	}
+
+	state := &models.GameState{
+		SessionID:    slotBonusStateKey(userID),
+		UserID:       userID,
+		CurrentState: slotBonusState,
+	}
+	err = tx.Where("session_id = ?", state.SessionID).
+		Assign(models.GameState{
+			CurrentState: slotBonusState,
+			StateData:    string(data),
+			UpdatedAt:    time.Now(),
+		}).
+		FirstOrCreate(state).Error
+	if err != nil {
+		return fmt.Errorf("保存奖励游戏失败: %w", err)
+	}
+	return nil
+}
+
+// deleteBonusGame 删除已结束的奖励游戏
+func deleteBonusGame(tx *gorm.DB, userID uint) error {
+	if err := tx.Where("session_id = ?", slotBonusStateKey(userID)).Delete(&models.GameState{}).Error; err != nil {
+		return fmt.Errorf("删除奖励游戏失败: %w", err)
+	}
+	return nil
+}
+
+// handleBonusPick 处理奖励游戏选择请求
+func (h *SlotHandler) handleBonusPick(session *SlotSessionSimple, data []byte) {
+	req := &pb.M_1906Tos{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		log.Printf("[SlotHandler] 解析奖励游戏选择失败: %v", err)
+		return
+	}
+
+	session.mu.Lock()
+	bonus := session.BonusGame
+	if !bonus.IsActive() {
+		session.mu.Unlock()
+		log.Printf("[SlotHandler] 玩家 %s 没有进行中的奖励游戏", session.ID)
+		return
+	}
+
+	before := bonus.TotalWin
+	if _, err := bonus.Pick(int(req.GetIndex())); err != nil {
+		session.mu.Unlock()
+		log.Printf("[SlotHandler] 奖励游戏选择无效: index=%d, %v", req.GetIndex(), err)
+		return
+	}
+	// 奖励游戏与触发它的一局合计不超过最高赢取，达到上限时结束
+	if session.Engine != nil {
+		bonus.ApplyMaxWin(session.Engine.GetCascadeConfig().MaxWin)
+	}
+	win := bonus.TotalWin - before
+	finished := !bonus.IsActive()
+
+	// 奖励游戏结束后一次性计入落币数
+	if finished {
+		session.TotalWin = bonus.TotalWin
+		session.TotalDownCoins += bonus.TotalWin
+		session.BonusGame = nil
+	}
+	view := bonus.View()
+	userIDNum := session.UserID
+	session.mu.Unlock()
+
+	// 进行中保存选择进度，结束时结算派彩并删除状态
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if !finished {
+			return saveBonusGame(tx, userIDNum, bonus)
+		}
+
+		if err := h.walletRepo.UpdateGameStatsTx(tx, userIDNum, 0, bonus.TotalWin, 0, bonus.TotalWin); err != nil {
+			return fmt.Errorf("更新用户资产失败: %w", err)
+		}
+
+		gameResult := &models.GameResult{
+			UserID:     userIDNum,
+			GameID:     h.gameID,
+			SessionID:  0,
+			RoundID:    bonus.ID,
+			BetAmount:  0,
+			WinAmount:  bonus.TotalWin,
+			Multiplier: float64(bonus.TotalWin) / float64(bonus.BetAmount),
+			Result: models.JSONMap{
+				"bonus_game": bonus,
+			},
+			IsBonus:  true,
+			PlayedAt: time.Now(),
+		}
+		if err := tx.Create(gameResult).Error; err != nil {
+			return fmt.Errorf("创建游戏结果失败: %w", err)
+		}
+
+		return deleteBonusGame(tx, userIDNum)
+	})
+	if err != nil {
+		log.Printf("[SlotHandler] 奖励游戏数据库操作失败: %v", err)
+	}
+
+	resp := &pb.M_1906Toc{
+		Bonus: convertBonusGame(view),
+		Win:   proto.Uint32(uint32(win)),
+	}
+	if err := h.sendMessage(session, 1906, resp); err != nil {
+		log.Printf("[SlotHandler] 发送奖励游戏选择结果失败: %v", err)
+	}
+
+	if finished {
+		h.pushGameData(session)
+	}
+}
+
+// handleBonusInfo 处理奖励游戏查询请求
+func (h *SlotHandler) handleBonusInfo(session *SlotSessionSimple, data []byte) {
+	session.mu.RLock()
+	view := session.BonusGame.View()
+	session.mu.RUnlock()
+
+	resp := &pb.M_1907Toc{}
+	if view.IsActive() {
+		resp.Bonus = convertBonusGame(view)
+	}
+	if err := h.sendMessage(session, 1907, resp); err != nil {
+		log.Printf("[SlotHandler] 发送奖励游戏信息失败: %v", err)
+	}
+}
+
+// convertBonusGame 转换奖励游戏（应传入已隐藏未揭开格子的视图）
+func convertBonusGame(bonus *slot.BonusGameState) *pb.PSlotBonus {
+	if bonus == nil {
+		return nil
+	}
+
+	tiles := make([]*pb.PSlotBonusTile, 0, len(bonus.Board))
+	for _, tile := range bonus.Board {
+		item := &pb.PSlotBonusTile{
+			Index:  proto.Uint32(uint32(tile.Index)),
+			Picked: proto.Bool(tile.Picked),
+		}
+		if tile.Revealed {
+			item.Type = convertBonusTileType(tile.Type).Enum()
+			item.Win = proto.Uint32(uint32(tile.Win))
+			if tile.Type == slot.BonusTileCredit {
+				item.Val = proto.Uint32(uint32(float64(bonus.BetAmount) * tile.Multiplier))
+			}
+		}
+		tiles = append(tiles, item)
+	}
+
+	return &pb.PSlotBonus{
+		Id:       proto.String(bonus.ID),
+		BetVal:   proto.Uint32(uint32(bonus.BetAmount)),
+		Tiles:    tiles,
+		Picks:    proto.Uint32(uint32(bonus.Picks)),
+		MaxPicks: proto.Uint32(uint32(bonus.MaxPicks)),
+		TotalWin: proto.Uint32(uint32(bonus.TotalWin)),
+		Finished: proto.Bool(!bonus.IsActive()),
+	}
+}
+
+// convertBonusTileType 转换奖励游戏格子类型
+func convertBonusTileType(tileType slot.BonusTileType) pb.ESlotBonusTile {
+	switch tileType {
+	case slot.BonusTileCollect:
+		return pb.ESlotBonusTile_e_slot_bonus_tile_collect
+	case slot.BonusTileEnd:
+		return pb.ESlotBonusTile_e_slot_bonus_tile_end
+	default:
+		return pb.ESlotBonusTile_e_slot_bonus_tile_credit
+	}
+}
@@ -0,0 +1,170 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	pb "github.com/wfunc/slot-game/internal/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSlotHandlerBonusGame(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "bonus_user", Nickname: "Bonus", Phone: "12345678909", Email: "bonus@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   100000,
+		GameState: "bonus",
+		LastSync:  time.Now(),
+	}
+
+	// 触发后保存，重新进入房间时恢复
+	config := &slot.BonusGameConfig{
+		BoardSize: 4,
+		Prizes:    []slot.BonusPrize{{Type: slot.BonusTileCredit, Multiplier: 3, Weight: 1}},
+	}
+	bonus := slot.NewBonusGame(config, slot.NewDRBGRandomGenerator(1), 100)
+	bonus.ID = "bonus-round"
+	if err := saveBonusGame(db, user.ID, bonus); err != nil {
+		t.Fatalf("saveBonusGame failed: %v", err)
+	}
+	session.BonusGame = handler.loadBonusGame(user.ID)
+	if !session.BonusGame.IsActive() || session.BonusGame.ID != "bonus-round" {
+		t.Fatalf("loaded bonus = %+v", session.BonusGame)
+	}
+
+	pick := func(index uint32) {
+		data, err := proto.Marshal(&pb.M_1906Tos{Index: proto.Uint32(index)})
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		handler.handleBonusPick(session, data)
+	}
+
+	// 进行中的选择进度被保存
+	pick(2)
+	if saved := handler.loadBonusGame(user.ID); saved == nil || saved.Picks != 1 || saved.TotalWin != 300 {
+		t.Fatalf("saved bonus = %+v, want 1 pick with win 300", saved)
+	}
+	pick(2) // 重复选择被忽略
+	for _, index := range []uint32{0, 1, 3} {
+		pick(index)
+	}
+
+	if session.BonusGame != nil || session.TotalDownCoins != 1200 {
+		t.Errorf("session bonus = %+v, down coins %d, want settled 1200", session.BonusGame, session.TotalDownCoins)
+	}
+	if handler.loadBonusGame(user.ID) != nil {
+		t.Error("finished bonus should be deleted")
+	}
+
+	var result models.GameResult
+	if err := db.Where("round_id = ?", "bonus-round").First(&result).Error; err != nil {
+		t.Fatalf("bonus game result not recorded: %v", err)
+	}
+	if !result.IsBonus || result.WinAmount != 1200 || result.BetAmount != 0 {
+		t.Errorf("game result = bonus %v, win %d, bet %d", result.IsBonus, result.WinAmount, result.BetAmount)
+	}
+}
+
+func TestSlotHandlerBonusGameMaxWin(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	// 最高赢取读取麻将游戏记录中的配置
+	if err := db.Model(&models.Game{}).Where("id = ?", handler.gameID).
+		Update("config", models.JSONMap{"max_win": map[string]interface{}{"multiplier": 10}}).Error; err != nil {
+		t.Fatalf("update game config failed: %v", err)
+	}
+	if maxWin := handler.mahjongCascadeConfig().MaxWin; maxWin == nil || maxWin.Multiplier != 10 {
+		t.Fatalf("MaxWin = %+v, want multiplier 10 from game config", maxWin)
+	}
+
+	user := &models.User{Username: "bonus_cap_user", Nickname: "BonusCap", Phone: "12345678914", Email: "bonus_cap@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   100000,
+		GameState: "idle",
+		LastSync:  time.Now(),
+	}
+	handler.handleEnterRoom(session, nil)
+
+	// 触发旋转已赢取800，上限为10倍下注（1000），奖励游戏最多再赢取200
+	config := &slot.BonusGameConfig{
+		BoardSize: 4,
+		Prizes:    []slot.BonusPrize{{Type: slot.BonusTileCredit, Multiplier: 3, Weight: 1}},
+	}
+	bonus := slot.NewBonusGame(config, slot.NewDRBGRandomGenerator(1), 100)
+	bonus.ID = "bonus-capped"
+	bonus.SpinWin = 800
+	session.BonusGame = bonus
+
+	data, _ := proto.Marshal(&pb.M_1906Tos{Index: proto.Uint32(0)})
+	handler.handleBonusPick(session, data)
+	if session.BonusGame != nil || session.TotalDownCoins != 200 {
+		t.Fatalf("session bonus = %+v, down coins %d, want capped at 200 and finished", session.BonusGame, session.TotalDownCoins)
+	}
+	var result models.GameResult
+	if err := db.Where("round_id = ?", "bonus-capped").First(&result).Error; err != nil {
+		t.Fatalf("bonus game result not recorded: %v", err)
+	}
+	if result.WinAmount != 200 {
+		t.Errorf("WinAmount = %d, want 200", result.WinAmount)
+	}
+}
+
+func TestConvertBonusGame(t *testing.T) {
+	bonus := &slot.BonusGameState{
+		ID:        "b1",
+		Phase:     slot.BonusGamePhaseActive,
+		BetAmount: 50,
+		MaxPicks:  3,
+		Board: []slot.BonusTile{
+			{Index: 0, Type: slot.BonusTileCredit, Multiplier: 4},
+			{Index: 1, Type: slot.BonusTileEnd},
+		},
+	}
+	bonus.Pick(0)
+
+	msg := convertBonusGame(bonus.View())
+	if msg.GetId() != "b1" || msg.GetFinished() || msg.GetTotalWin() != 200 || len(msg.GetTiles()) != 2 {
+		t.Fatalf("converted bonus = %v", msg)
+	}
+	if tile := msg.GetTiles()[0]; !tile.GetPicked() || tile.GetVal() != 200 || tile.GetType() != pb.ESlotBonusTile_e_slot_bonus_tile_credit {
+		t.Errorf("picked tile = %v", tile)
+	}
+	if tile := msg.GetTiles()[1]; tile.Type != nil || tile.Val != nil {
+		t.Errorf("unrevealed tile should be hidden, got %v", tile)
+	}
+
+	if got := convertSymbols([]int{slot.SYMBOL_BONUS}); got[0] != pb.ESlotBetType_e_slot_bet_type_bonus {
+		t.Errorf("convertSymbols(SYMBOL_BONUS) = %v", got)
+	}
+}
@@ -0,0 +1,419 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/game"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/pb"
+	"github.com/wfunc/slot-game/internal/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// slotBetMultipliers 下注档位相对基础下注的倍数（即麻将赔率表中的赔率）
+var slotBetMultipliers = []uint32{1, 2, 5, 10, 20, 50, 100}
+
+// slotBetLevels 按基础下注生成下注档位（maxBet 大于0时不超过最大下注）
+func slotBetLevels(base, maxBet uint32) []uint32 {
+	betVals := make([]uint32, 0, len(slotBetMultipliers))
+	for _, multiplier := range slotBetMultipliers {
+		val := base * multiplier
+		if maxBet > 0 && val > maxBet {
+			break
+		}
+		betVals = append(betVals, val)
+	}
+	return betVals
+}
+
+// slotOddsTable 按基础下注生成麻将的下注档位和赔率表
+func slotOddsTable(base uint32) ([]uint32, []*pb.PSlotOdds) {
+	betVals := slotBetLevels(base, 0)
+	odds := make([]*pb.PSlotOdds, 0, len(betVals))
+	for i, val := range betVals {
+		odds = append(odds, &pb.PSlotOdds{
+			Odds: proto.Uint32(slotBetMultipliers[i]),
+			Val:  proto.Uint32(val),
+		})
+	}
+	return betVals, odds
+}
+
+// classicOddsTable 按机台配置生成法老王/777的下注档位和赔率表
+// 赔率表逐项对应赔付表，赔率为最小下注时该项的单线赢取（全路径为单路赢取，按下注单位数折算）
+func classicOddsTable(config *slot.SlotConfig) ([]uint32, []*pb.PSlotOdds) {
+	betVals := slotBetLevels(uint32(config.MinBet), uint32(config.MaxBet))
+	unitBet := float64(config.MinBet)
+	if config.WinMode.IsWays() && config.WaysBetUnits > 0 {
+		unitBet /= float64(config.WaysBetUnits)
+	}
+
+	odds := make([]*pb.PSlotOdds, 0, len(config.PayTables))
+	for _, pay := range config.PayTables {
+		if _, ok := slot.SymbolID(pay.Symbol); !ok {
+			continue
+		}
+		betType := convertClassicSymbol(pay.Symbol)
+		odds = append(odds, &pb.PSlotOdds{
+			Odds:  proto.Uint32(uint32(unitBet * pay.Multiplier)),
+			Val:   proto.Uint32(uint32(config.MinBet)),
+			Type:  &betType,
+			Count: proto.Uint32(uint32(pay.Count)),
+		})
+	}
+	return betVals, odds
+}
+
+// classicSlotMachines 支付线/全路径拉霸机类型对应的机台ID
+// 法老王为1024路全路径，777为20线支付线；麻将使用消除引擎，不在注册表中
+var classicSlotMachines = map[pb.ESlotType]string{
+	pb.ESlotType_e_slot_type_pharaoh: "pharaoh",
+	pb.ESlotType_e_slot_type_777:     "lucky_seven",
+}
+
+// newBuiltinMachineRegistry 创建只登记内置机台引擎的注册表（未接入游戏服务的注册表时使用）
+func newBuiltinMachineRegistry(db *gorm.DB, logger *zap.Logger) *game.SlotMachineRegistry {
+	registry := game.NewSlotMachineRegistry(db, logger)
+	registry.RegisterBuiltin()
+	return registry
+}
+
+// SetMachineRegistry 使用游戏服务的老虎机配置注册表，与REST接口共用各机台的引擎和配置热加载
+func (h *SlotHandler) SetMachineRegistry(registry *game.SlotMachineRegistry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.machines = registry
+}
+
+// classicSlotEngine 从注册表获取拉霸机类型对应机台的引擎，麻将返回空
+func (h *SlotHandler) classicSlotEngine(slotType pb.ESlotType) (*slot.SlotEngine, error) {
+	machineID, ok := classicSlotMachines[slotType]
+	if !ok {
+		return nil, nil
+	}
+
+	h.mu.RLock()
+	machines := h.machines
+	h.mu.RUnlock()
+
+	engine, ok := machines.Engine(machineID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrSlotMachineNotFound, machineID)
+	}
+	return engine, nil
+}
+
+// handleClassicStartGame 处理法老王/777的开始游戏请求
+// 免费游戏和奖励游戏由引擎按会话管理，奖励游戏在旋转内自动完成并计入赢取
+// 中奖可以博倍时赢取暂不计入落币数，收分（或开始下一局自动收分）后结算
+// 加注时按加注比例扣费（免费旋转不加注），扣费流水记为加注子类型
+func (h *SlotHandler) handleClassicStartGame(session *SlotSessionSimple, engine *slot.SlotEngine, betAmount uint32, ante bool) {
+	h.collectPendingGamble(session)
+
+	// 引擎会话中有剩余免费旋转时不扣费
+	isFreeSpin := false
+	if data := engine.GetSession(session.ID); data != nil {
+		isFreeSpin = data.FreeGameState.IsActive()
+	}
+	ante = ante && !isFreeSpin
+	stake := int64(betAmount)
+	if ante {
+		stake = engine.GetConfig().Ante.Cost(stake)
+	}
+
+	session.mu.Lock()
+	if !isFreeSpin && session.Balance < stake {
+		session.mu.Unlock()
+		log.Printf("[SlotHandler] 玩家余额不足: %d < %d", session.Balance, stake)
+		return
+	}
+	if isFreeSpin {
+		session.GameState = "free_spin"
+	} else {
+		session.Balance -= stake
+		session.CurrentBet = betAmount
+		session.GameState = "playing"
+	}
+	userID := session.UserID
+	balance := session.Balance
+	session.mu.Unlock()
+
+	result, err := engine.SpinWithAnte(userID, session.ID, int64(betAmount), ante)
+	if err != nil {
+		log.Printf("[SlotHandler] 游戏执行失败: %v", err)
+		session.mu.Lock()
+		if !isFreeSpin {
+			session.Balance += stake
+		}
+		session.GameState = "idle"
+		session.mu.Unlock()
+		return
+	}
+
+	// 免费游戏进度（免费旋转按触发时的下注额派彩）
+	payBet := int64(betAmount)
+	isFree := result.IsFreeSpin
+	var currentFree, totalFree uint32
+	roundWin := result.WinAmount
+	if freeGame := result.FreeGame; freeGame != nil {
+		isFree = isFree || freeGame.IsActive()
+		currentFree = uint32(freeGame.FreeSpinsPlayed)
+		totalFree = uint32(freeGame.FreeSpinsTotal)
+		if result.IsFreeSpin {
+			payBet = freeGame.TriggerBet
+			roundWin = freeGame.FreeGameWin
+		}
+	}
+
+	// 博倍中的赢取收分后再计入
+	settledWin := result.WinAmount
+	if result.Gamble != nil {
+		settledWin = 0
+	}
+
+	session.mu.Lock()
+	session.TotalWin = result.WinAmount
+	session.TotalDownCoins += settledWin
+	session.GameState = "idle"
+	if isFree {
+		session.GameState = "free_spin"
+	} else if result.Gamble != nil {
+		session.GameState = "gamble"
+	}
+	session.mu.Unlock()
+
+	detail := models.JSONMap{
+		"result_id":    result.ID,
+		"machine_id":   engine.GetConfig().MachineID,
+		"config_hash":  result.ConfigHash,
+		"reels":        result.Reels,
+		"win_lines":    result.WinLines,
+		"ways":         result.Ways,
+		"is_free_spin": result.IsFreeSpin,
+		"free_game":    result.FreeGame,
+		"bonus_game":   result.BonusGame,
+		"ante":         result.Ante,
+	}
+	if result.Gamble != nil {
+		detail["gamble_id"] = result.Gamble.ID
+		detail["pending_win"] = result.WinAmount
+	}
+	recordBet := payBet
+	if result.Ante {
+		recordBet = result.BetAmount
+	}
+	jackpotSpin := slot.LineJackpotSpin(recordBet, result)
+	if result.IsFreeSpin {
+		jackpotSpin.BetAmount = 0
+	}
+	stat := &repository.SlotSpinStat{
+		MachineID: engine.GetConfig().MachineID,
+		BetLevel:  payBet,
+		WinAmount: result.WinAmount,
+		FreeSpin:  result.IsFreeSpin,
+	}
+	if !result.IsFreeSpin {
+		stat.TheoreticalRTP = engine.TheoreticalRTP(result.ReelSetID, result.Ante)
+	}
+	if result.FreeSpins > 0 {
+		stat.Features++
+	}
+	if result.BonusGame != nil {
+		stat.Features++
+	}
+	// 加注流水和免费游戏进度随本局在同一事务内保存，免费游戏断线重连后继续
+	persist := func(tx *gorm.DB) error {
+		if result.Ante {
+			if err := recordStakeTransaction(tx, userID, models.BetSubTypeAnte, result.BetAmount, balance, result.ID); err != nil {
+				return fmt.Errorf("记录加注流水失败: %w", err)
+			}
+		}
+		if result.FreeGame != nil {
+			return saveFreeGame(tx, userID, engine.GetConfig().MachineID, result.FreeGame)
+		}
+		return nil
+	}
+	jackpots, err := h.recordSlotRound(userID, recordBet, settledWin, result.IsFreeSpin, detail, nil, engine.GetConfig().Jackpot, jackpotSpin, stat, persist)
+	if err != nil {
+		log.Printf("[SlotHandler] 数据库操作失败: %v", err)
+	}
+
+	resp := &pb.M_1902Toc{
+		BetVal:       proto.Uint32(uint32(payBet)),
+		Win:          proto.Uint32(uint32(result.WinAmount)),
+		TotalWin:     proto.Uint32(uint32(roundWin)),
+		IsFree:       proto.Bool(isFree),
+		CurrentFree:  proto.Uint32(currentFree),
+		TotalFree:    proto.Uint32(totalFree),
+		Result:       convertClassicResult(result),
+		Bonus:        convertBonusGame(result.BonusGame),
+		Gamble:       convertGamble(result.Gamble),
+		Anticipation: convertAnticipation(result.Anticipation),
+	}
+	if err := h.sendMessage(session, 1902, resp); err != nil {
+		log.Printf("[SlotHandler] 发送游戏结果失败: %v", err)
+	}
+	h.notifyJackpots(session, jackpots)
+
+	h.pushGameData(session)
+}
+
+// slotFreeGameState 法老王/777免费游戏在 game_states 表中的状态名
+const slotFreeGameState = "slot_free_game"
+
+// slotFreeGameStateKey 玩家在机台上免费游戏的持久化键（引擎会话按连接区分，重连后按玩家和机台恢复）
+func slotFreeGameStateKey(userID uint, machineID string) string {
+	return fmt.Sprintf("slot_free_%s_%d", machineID, userID)
+}
+
+// loadFreeGame 加载玩家在机台上未完成的免费游戏
+func (h *SlotHandler) loadFreeGame(userID uint, machineID string) *slot.FreeGameState {
+	var state models.GameState
+	err := h.db.Where("session_id = ? AND current_state = ?", slotFreeGameStateKey(userID, machineID), slotFreeGameState).First(&state).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			h.logger.Warn("[SlotHandler] 加载免费游戏失败", zap.Uint("user_id", userID), zap.Error(err))
+		}
+		return nil
+	}
+
+	freeGame := &slot.FreeGameState{}
+	if err := json.Unmarshal([]byte(state.StateData), freeGame); err != nil {
+		h.logger.Warn("[SlotHandler] 解析免费游戏失败", zap.Uint("user_id", userID), zap.Error(err))
+		return nil
+	}
+	if !freeGame.IsActive() {
+		return nil
+	}
+	return freeGame
+}
+
+// saveFreeGame 保存免费游戏进度，免费游戏结束时删除
+func saveFreeGame(tx *gorm.DB, userID uint, machineID string, freeGame *slot.FreeGameState) error {
+	key := slotFreeGameStateKey(userID, machineID)
+	if !freeGame.IsActive() {
+		if err := tx.Where("session_id = ?", key).Delete(&models.GameState{}).Error; err != nil {
+			return fmt.Errorf("删除免费游戏失败: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(freeGame)
+	if err != nil {
+		return fmt.Errorf("序列化免费游戏失败: %w", err)
+	}
+	state := &models.GameState{
+		SessionID:    key,
+		UserID:       userID,
+		CurrentState: slotFreeGameState,
+	}
+	err = tx.Where("session_id = ?", key).
+		Assign(models.GameState{
+			CurrentState: slotFreeGameState,
+			StateData:    string(data),
+			UpdatedAt:    time.Now(),
+		}).
+		FirstOrCreate(state).Error
+	if err != nil {
+		return fmt.Errorf("保存免费游戏失败: %w", err)
+	}
+	return nil
+}
+
+// restoreFreeGame 进入机台时恢复玩家未完成的免费游戏，返回是否处于免费游戏中
+func (h *SlotHandler) restoreFreeGame(session *SlotSessionSimple, engine *slot.SlotEngine) bool {
+	if data := engine.GetSession(session.ID); data != nil && data.FreeGameState.IsActive() {
+		return true
+	}
+	freeGame := h.loadFreeGame(session.UserID, engine.GetConfig().MachineID)
+	if freeGame == nil {
+		return false
+	}
+	engine.RestoreFreeGame(session.UserID, session.ID, *freeGame)
+	return true
+}
+
+// convertClassicResult 转换支付线/全路径旋转结果
+// 卷轴按列存储，协议按行下发（line1为第一行），位置索引为 row*5+reel
+func convertClassicResult(result *slot.SpinResult) *pb.PSlotResult {
+	lines := make([][]pb.ESlotBetType, 5)
+	for _, column := range result.Reels {
+		for row, symbol := range column {
+			if row < len(lines) {
+				lines[row] = append(lines[row], convertClassicSymbol(symbol))
+			}
+		}
+	}
+
+	rewards := make([]*pb.PSlotReward, 0, len(result.WinLines))
+	for _, line := range result.WinLines {
+		positions := make([]uint32, 0, len(line.Positions))
+		for _, pos := range line.Positions {
+			positions = append(positions, uint32(pos.Row*5+pos.Reel))
+		}
+		betType := convertClassicSymbol(line.Symbol)
+		rewards = append(rewards, &pb.PSlotReward{
+			Type: &betType,
+			Val:  proto.Uint32(uint32(line.WinAmount)),
+			Pos:  positions,
+		})
+	}
+
+	return &pb.PSlotResult{
+		Line1:   lines[0],
+		Line2:   lines[1],
+		Line3:   lines[2],
+		Line4:   lines[3],
+		Line5:   lines[4],
+		Rewards: rewards,
+	}
+}
+
+// convertAnticipation 转换期待效果（没有时为空），位置索引与中奖线相同
+func convertAnticipation(anticipation *slot.Anticipation) *pb.PSlotAnticipation {
+	if anticipation.IsEmpty() {
+		return nil
+	}
+	slowReels := make([]uint32, 0, len(anticipation.SlowReels))
+	for _, reel := range anticipation.SlowReels {
+		slowReels = append(slowReels, uint32(reel))
+	}
+	nearMisses := make([]*pb.PSlotNearMiss, 0, len(anticipation.NearMisses))
+	for _, nearMiss := range anticipation.NearMisses {
+		positions := make([]uint32, 0, len(nearMiss.Positions))
+		for _, pos := range nearMiss.Positions {
+			positions = append(positions, uint32(pos.Row*5+pos.Reel))
+		}
+		// 抽象网格（麻将）只有符号ID，经典机台按经典符号转换
+		betType := convertSymbol(nearMiss.SymbolID)
+		if nearMiss.Symbol != "" {
+			betType = convertClassicSymbol(nearMiss.Symbol)
+		}
+		nearMisses = append(nearMisses, &pb.PSlotNearMiss{
+			Type:    &betType,
+			Feature: proto.Bool(nearMiss.Type == slot.NearMissTypeFeature),
+			Count:   proto.Uint32(uint32(nearMiss.Count)),
+			Need:    proto.Uint32(uint32(nearMiss.Required)),
+			Pos:     positions,
+		})
+	}
+	return &pb.PSlotAnticipation{
+		SlowReels:  slowReels,
+		NearMisses: nearMisses,
+	}
+}
+
+// convertClassicSymbol 转换经典符号为枚举类型
+func convertClassicSymbol(symbol slot.Symbol) pb.ESlotBetType {
+	symbolID, ok := slot.SymbolID(symbol)
+	if !ok {
+		return pb.ESlotBetType_e_slot_bet_type_0
+	}
+	return convertSymbol(symbolID)
+}
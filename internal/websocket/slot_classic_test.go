@@ -0,0 +1,318 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	pb "github.com/wfunc/slot-game/internal/pb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSlotHandlerSlotTypes(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "classic_user", Nickname: "Classic", Phone: "12345678910", Email: "classic@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	tests := []struct {
+		slotType  pb.ESlotType
+		machineID string
+		bet       uint32
+	}{
+		{pb.ESlotType_e_slot_type_pharaoh, "pharaoh", 160},
+		{pb.ESlotType_e_slot_type_777, "lucky_seven", 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.machineID, func(t *testing.T) {
+			conn := createTestWebSocketConn(t)
+			defer conn.Close()
+			session := &SlotSessionSimple{
+				ID:        uuid.New().String(),
+				UserID:    user.ID,
+				Conn:      conn,
+				Codec:     NewProtobufCodec(),
+				Balance:   100000,
+				GameState: "idle",
+				LastSync:  time.Now(),
+			}
+
+			slotType := tt.slotType
+			data, err := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+			if err != nil {
+				t.Fatalf("Failed to marshal request: %v", err)
+			}
+			handler.handleEnterRoom(session, data)
+			if session.Engine != nil || session.ClassicEngine == nil {
+				t.Fatalf("slot type %v should use the classic engine", tt.slotType)
+			}
+			if got := session.ClassicEngine.GetConfig().MachineID; got != tt.machineID {
+				t.Fatalf("MachineID = %v, want %v", got, tt.machineID)
+			}
+
+			data, err = proto.Marshal(&pb.M_1902Tos{BetVal: proto.Uint32(tt.bet)})
+			if err != nil {
+				t.Fatalf("Failed to marshal request: %v", err)
+			}
+			handler.handleStartGame(session, data)
+
+			// 首局必定扣费，中奖计入落币
+			if session.Balance != 100000-int64(tt.bet) {
+				t.Errorf("Balance = %d, want %d", session.Balance, 100000-int64(tt.bet))
+			}
+			var result models.GameResult
+			if err := db.Where("user_id = ?", user.ID).Order("id DESC").First(&result).Error; err != nil {
+				t.Fatalf("game result not recorded: %v", err)
+			}
+			if result.Result["machine_id"] != tt.machineID || result.BetAmount != int64(tt.bet) {
+				t.Errorf("game result = machine %v, bet %d", result.Result["machine_id"], result.BetAmount)
+			}
+			if session.TotalDownCoins != result.WinAmount {
+				t.Errorf("TotalDownCoins = %d, want %d", session.TotalDownCoins, result.WinAmount)
+			}
+		})
+	}
+
+	// 切回麻将场使用消除引擎
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{ID: uuid.New().String(), UserID: user.ID, Conn: conn, Codec: NewProtobufCodec(), ClassicEngine: &slot.SlotEngine{}}
+	handler.handleEnterRoom(session, nil)
+	if session.Engine == nil || session.ClassicEngine != nil || session.SlotType != pb.ESlotType_e_slot_type_mahjong {
+		t.Error("default slot type should use the mahjong cascade engine")
+	}
+}
+
+func TestSlotHandlerMachineRegistry(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.SlotMachine{}, &models.SlotConfigVersion{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	// 数据库中777机台的配置经注册表热加载后对WebSocket生效
+	machine := &models.SlotMachine{GameID: 1, MachineID: "lucky_seven", Reels: 5, Rows: 3, Paylines: 5, Status: "active"}
+	if err := db.Create(machine).Error; err != nil {
+		t.Fatalf("failed to create machine: %v", err)
+	}
+	registry := game.NewSlotMachineRegistry(db, zap.NewNop())
+	registry.RegisterBuiltin()
+	if err := registry.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	handler.SetMachineRegistry(registry)
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{ID: uuid.New().String(), Conn: conn, Codec: NewProtobufCodec()}
+	slotType := pb.ESlotType_e_slot_type_777
+	data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+	handler.handleEnterRoom(session, data)
+
+	engine, _ := registry.Engine("lucky_seven")
+	if session.ClassicEngine != engine || engine.GetConfig().ActivePaylines != 5 {
+		t.Errorf("777 should use the registry engine with the database config")
+	}
+}
+
+func TestSlotOddsTable(t *testing.T) {
+	betVals, odds := slotOddsTable(16)
+	if len(betVals) != len(odds) || betVals[0] != 16 || betVals[len(betVals)-1] != 1600 {
+		t.Fatalf("betVals = %v", betVals)
+	}
+	for i, item := range odds {
+		if item.GetVal() != betVals[i] || item.GetOdds()*16 != item.GetVal() {
+			t.Errorf("odds[%d] = %d/%d", i, item.GetOdds(), item.GetVal())
+		}
+	}
+}
+
+func TestClassicOddsTable(t *testing.T) {
+	config := slot.GetPharaohConfig()
+	betVals, odds := classicOddsTable(config)
+	if len(betVals) == 0 || betVals[0] != uint32(config.MinBet) {
+		t.Fatalf("betVals = %v", betVals)
+	}
+	if len(odds) != len(config.PayTables) {
+		t.Fatalf("len(odds) = %d, want %d pay table entries", len(odds), len(config.PayTables))
+	}
+	for i, pay := range config.PayTables {
+		item := odds[i]
+		want := uint32(float64(config.MinBet) / float64(config.WaysBetUnits) * pay.Multiplier)
+		if item.GetType() != convertClassicSymbol(pay.Symbol) || item.GetCount() != uint32(pay.Count) || item.GetOdds() != want || item.GetVal() != uint32(config.MinBet) {
+			t.Errorf("odds[%d] = %+v, want %s x%d pays %d", i, item, pay.Symbol, pay.Count, want)
+		}
+	}
+
+	// 支付线机台按最小下注计算单线赢取
+	seven := slot.GetLuckySevenConfig()
+	_, sevenOdds := classicOddsTable(seven)
+	last := seven.PayTables[len(seven.PayTables)-1]
+	if want := uint32(float64(seven.MinBet) * last.Multiplier); sevenOdds[len(sevenOdds)-1].GetOdds() != want {
+		t.Errorf("777 top odds = %d, want %d", sevenOdds[len(sevenOdds)-1].GetOdds(), want)
+	}
+}
+
+func TestSlotHandlerClassicFreeGameReconnect(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}, &models.Transaction{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "reconnect_user", Nickname: "Reconnect", Phone: "12345678913", Email: "reconnect@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	newSession := func() *SlotSessionSimple {
+		conn := createTestWebSocketConn(t)
+		t.Cleanup(func() { conn.Close() })
+		session := &SlotSessionSimple{
+			ID:        uuid.New().String(),
+			UserID:    user.ID,
+			Conn:      conn,
+			Codec:     NewProtobufCodec(),
+			Balance:   1000000,
+			GameState: "idle",
+			LastSync:  time.Now(),
+		}
+		slotType := pb.ESlotType_e_slot_type_pharaoh
+		data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+		handler.handleEnterRoom(session, data)
+		return session
+	}
+
+	// 购买免费游戏后旋转一次
+	first := newSession()
+	engine := first.ClassicEngine
+	buy, _ := proto.Marshal(&pb.M_1910Tos{BetVal: proto.Uint32(160)})
+	handler.handleBuyFeature(first, buy)
+	spin, _ := proto.Marshal(&pb.M_1902Tos{BetVal: proto.Uint32(160)})
+	handler.handleStartGame(first, spin)
+	played := engine.GetSession(first.ID).FreeGameState
+	if played.FreeSpinsPlayed != 1 || !played.IsActive() {
+		t.Fatalf("free game after one spin = %+v", played)
+	}
+
+	// 断线后以新连接进入同一机台，继续剩余的免费旋转
+	engine.RemoveSession(first.ID)
+	second := newSession()
+	restored := engine.GetSession(second.ID)
+	if restored == nil || restored.FreeGameState != played {
+		t.Fatalf("restored free game = %+v, want %+v", restored, played)
+	}
+	if second.GameState != "free_spin" {
+		t.Errorf("GameState = %s, want free_spin", second.GameState)
+	}
+	balance := second.Balance
+	handler.handleStartGame(second, spin)
+	if second.Balance != balance {
+		t.Errorf("restored free spin charged %d", balance-second.Balance)
+	}
+
+	// 免费游戏结束后删除保存的进度
+	for i := 0; i < 100 && engine.GetSession(second.ID).FreeGameState.IsActive(); i++ {
+		handler.handleStartGame(second, spin)
+	}
+	if freeGame := handler.loadFreeGame(user.ID, engine.GetConfig().MachineID); freeGame != nil {
+		t.Errorf("finished free game still saved: %+v", freeGame)
+	}
+}
+
+func TestConvertClassicResult(t *testing.T) {
+	result := &slot.SpinResult{
+		Reels: [][]slot.Symbol{
+			{slot.SymbolSeven, slot.SymbolCherry, slot.SymbolWild},
+			{slot.SymbolSeven, slot.SymbolLemon, slot.SymbolScatter},
+			{slot.SymbolSeven, slot.SymbolBar, slot.SymbolBonus},
+		},
+		WinLines: []slot.WinLine{{
+			Symbol:    slot.SymbolSeven,
+			Count:     3,
+			WinAmount: 500,
+			Positions: []slot.Position{{Reel: 0, Row: 0}, {Reel: 1, Row: 0}, {Reel: 2, Row: 0}},
+		}},
+	}
+
+	converted := convertClassicResult(result)
+	want := []pb.ESlotBetType{pb.ESlotBetType_e_slot_bet_type_7, pb.ESlotBetType_e_slot_bet_type_7, pb.ESlotBetType_e_slot_bet_type_7}
+	for i, symbol := range converted.Line1 {
+		if symbol != want[i] {
+			t.Fatalf("Line1 = %v, want %v", converted.Line1, want)
+		}
+	}
+	if converted.Line3[0] != pb.ESlotBetType_e_slot_bet_type_wild ||
+		converted.Line3[1] != pb.ESlotBetType_e_slot_bet_type_free ||
+		converted.Line3[2] != pb.ESlotBetType_e_slot_bet_type_bonus {
+		t.Errorf("Line3 = %v", converted.Line3)
+	}
+	if len(converted.Line4) != 0 || len(converted.Line5) != 0 {
+		t.Errorf("unused lines should be empty")
+	}
+
+	if len(converted.Rewards) != 1 {
+		t.Fatalf("Rewards = %v", converted.Rewards)
+	}
+	reward := converted.Rewards[0]
+	if reward.GetType() != pb.ESlotBetType_e_slot_bet_type_7 || reward.GetVal() != 500 {
+		t.Errorf("reward = %v", reward)
+	}
+	if pos := reward.GetPos(); len(pos) != 3 || pos[0] != 0 || pos[2] != 2 {
+		t.Errorf("reward positions = %v", pos)
+	}
+}
+
+func TestConvertAnticipation(t *testing.T) {
+	if convertAnticipation(nil) != nil {
+		t.Error("nil anticipation should not be sent")
+	}
+
+	converted := convertAnticipation(&slot.Anticipation{
+		SlowReels: []int{3, 4},
+		NearMisses: []slot.NearMiss{{
+			Type:      slot.NearMissTypeFeature,
+			Symbol:    slot.SymbolScatter,
+			Positions: []slot.Position{{Reel: 0, Row: 2}, {Reel: 2, Row: 0}},
+			Count:     2,
+			Required:  3,
+		}},
+	})
+	if reels := converted.GetSlowReels(); len(reels) != 2 || reels[0] != 3 || reels[1] != 4 {
+		t.Errorf("SlowReels = %v", reels)
+	}
+	if len(converted.GetNearMisses()) != 1 {
+		t.Fatalf("NearMisses = %v", converted.GetNearMisses())
+	}
+	nearMiss := converted.GetNearMisses()[0]
+	if nearMiss.GetType() != pb.ESlotBetType_e_slot_bet_type_free || !nearMiss.GetFeature() ||
+		nearMiss.GetCount() != 2 || nearMiss.GetNeed() != 3 {
+		t.Errorf("near miss = %v", nearMiss)
+	}
+	if pos := nearMiss.GetPos(); len(pos) != 2 || pos[0] != 10 || pos[1] != 2 {
+		t.Errorf("near miss positions = %v", pos)
+	}
+
+	// 抽象网格（麻将）的差一点触发只有符号ID
+	converted = convertAnticipation(&slot.Anticipation{
+		NearMisses: []slot.NearMiss{{
+			Type:      slot.NearMissTypeFeature,
+			SymbolID:  slot.SYMBOL_BONUS,
+			Positions: []slot.Position{{Reel: 1, Row: 0}, {Reel: 3, Row: 1}},
+			Count:     2,
+			Required:  3,
+		}},
+	})
+	if got := converted.GetNearMisses()[0].GetType(); got != pb.ESlotBetType_e_slot_bet_type_bonus {
+		t.Errorf("abstract near miss type = %v", got)
+	}
+}
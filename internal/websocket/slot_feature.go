@@ -0,0 +1,192 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/pb"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// handleBuyFeature 处理购买免费游戏（法老王）
+// 扣费、流水和游戏结果提交后引擎会话才进入免费游戏，之后的开始游戏为免费旋转
+func (h *SlotHandler) handleBuyFeature(session *SlotSessionSimple, data []byte) {
+	req := &pb.M_1910Tos{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		log.Printf("[SlotHandler] 解析购买免费游戏请求失败: %v", err)
+		return
+	}
+
+	session.mu.RLock()
+	engine := session.ClassicEngine
+	session.mu.RUnlock()
+	if engine == nil || engine.GetConfig().BuyFeature == nil {
+		log.Printf("[SlotHandler] 玩家 %s 当前机台不支持购买免费游戏", session.ID)
+		return
+	}
+	h.collectPendingGamble(session)
+
+	betAmount := int64(req.GetBetVal())
+	if err := engine.CanBuyFeature(session.ID, betAmount); err != nil {
+		log.Printf("[SlotHandler] 购买免费游戏失败: %v", err)
+		return
+	}
+	price := engine.GetConfig().BuyFeature.Price(betAmount)
+	machineID := engine.GetConfig().MachineID
+
+	session.mu.RLock()
+	balance := session.Balance - price
+	userID := session.UserID
+	session.mu.RUnlock()
+	if balance < 0 {
+		log.Printf("[SlotHandler] 玩家余额不足: %d < %d", balance+price, price)
+		return
+	}
+
+	// 先提交扣费，提交失败不开启免费游戏
+	roundID := uuid.New().String()
+	gameResult := &models.GameResult{
+		UserID:    userID,
+		GameID:    h.gameID,
+		SessionID: 0,
+		RoundID:   roundID,
+		BetAmount: price,
+		Result: models.JSONMap{
+			"machine_id": machineID,
+			"bet_amount": betAmount,
+		},
+		IsBonus:  true,
+		PlayedAt: time.Now(),
+	}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := h.walletRepo.UpdateGameStatsTx(tx, userID, price, 0, price, 0); err != nil {
+			return fmt.Errorf("更新用户资产失败: %w", err)
+		}
+		if err := recordStakeTransaction(tx, userID, models.BetSubTypeBuyFeature, price, balance, roundID); err != nil {
+			return err
+		}
+		if err := tx.Create(gameResult).Error; err != nil {
+			return fmt.Errorf("创建游戏结果失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[SlotHandler] 购买免费游戏扣费失败: %v", err)
+		return
+	}
+
+	purchase, err := engine.BuyFeature(userID, session.ID, betAmount)
+	if err != nil {
+		log.Printf("[SlotHandler] 购买免费游戏失败: %v", err)
+		if err := h.refundBuyFeature(userID, price, roundID); err != nil {
+			log.Printf("[SlotHandler] 撤销购买免费游戏扣费失败: %v", err)
+		}
+		return
+	}
+
+	session.mu.Lock()
+	session.Balance -= price
+	session.CurrentBet = uint32(betAmount)
+	session.GameState = "free_spin"
+	session.mu.Unlock()
+
+	// 补充购买结果，购买的免费游戏断线重连后继续
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		result := models.JSONMap{
+			"machine_id":  machineID,
+			"buy_feature": purchase,
+		}
+		if err := tx.Model(gameResult).Update("result", result).Error; err != nil {
+			return fmt.Errorf("更新游戏结果失败: %w", err)
+		}
+		return saveFreeGame(tx, userID, machineID, purchase.FreeGame)
+	})
+	if err != nil {
+		log.Printf("[SlotHandler] 保存购买的免费游戏失败: %v", err)
+	}
+
+	resp := &pb.M_1910Toc{
+		BetVal:    proto.Uint32(uint32(betAmount)),
+		Price:     proto.Uint32(uint32(price)),
+		TotalFree: proto.Uint32(uint32(purchase.FreeGame.FreeSpinsTotal)),
+	}
+	if err := h.sendMessage(session, 1910, resp); err != nil {
+		log.Printf("[SlotHandler] 发送购买免费游戏结果失败: %v", err)
+	}
+
+	h.pushGameData(session)
+}
+
+// refundBuyFeature 扣费提交后引擎拒绝购买时撤销扣费、流水和游戏结果
+func (h *SlotHandler) refundBuyFeature(userID uint, price int64, roundID string) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := h.walletRepo.UpdateGameStatsTx(tx, userID, -price, 0, -price, 0); err != nil {
+			return fmt.Errorf("退还扣费失败: %w", err)
+		}
+		if err := tx.Where("user_id = ? AND ref_id = ?", userID, roundID).Delete(&models.Transaction{}).Error; err != nil {
+			return fmt.Errorf("删除扣费流水失败: %w", err)
+		}
+		if err := tx.Where("round_id = ?", roundID).Delete(&models.GameResult{}).Error; err != nil {
+			return fmt.Errorf("删除游戏结果失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// recordStakeTransaction 记录加注和购买免费游戏的扣费流水，子类型与普通下注区分
+func recordStakeTransaction(tx *gorm.DB, userID uint, subType string, amount, balance int64, refID string) error {
+	transaction := &models.Transaction{
+		UserID:        userID,
+		OrderNo:       fmt.Sprintf("BET-%s-%s", subType, uuid.New().String()),
+		Type:          "bet",
+		SubType:       subType,
+		Amount:        amount,
+		BeforeBalance: balance + amount,
+		AfterBalance:  balance,
+		RefType:       "slot",
+		RefID:         refID,
+		Description:   slotStakeDescriptions[subType],
+		Status:        "success",
+	}
+	if err := tx.Create(transaction).Error; err != nil {
+		return fmt.Errorf("记录交易失败: %w", err)
+	}
+	return nil
+}
+
+// slotStakeDescriptions 扣费流水的说明
+var slotStakeDescriptions = map[string]string{
+	models.BetSubTypeAnte:       "拉霸机加注投注",
+	models.BetSubTypeBuyFeature: "拉霸机购买免费游戏",
+}
+
+// convertAnte 转换加注配置（机台不支持加注时为空）
+func convertAnte(config *slot.SlotConfig) *pb.PSlotAnte {
+	rtp, err := slot.CalculateAnteRTP(config)
+	if err != nil {
+		return nil
+	}
+	return &pb.PSlotAnte{
+		CostRate: proto.Uint32(uint32(math.Round(config.Ante.Multiplier * 100))),
+		Rtp:      proto.Uint32(uint32(math.Round(rtp * 10000))),
+	}
+}
+
+// convertBuyFeature 转换购买免费游戏配置（机台不支持购买时为空）
+func convertBuyFeature(config *slot.SlotConfig) *pb.PSlotBuyFeature {
+	rtp, err := slot.CalculateBuyFeatureRTP(config)
+	if err != nil {
+		return nil
+	}
+	return &pb.PSlotBuyFeature{
+		PriceRate: proto.Uint32(uint32(math.Round(config.BuyFeature.PriceMultiplier * 100))),
+		FreeSpins: proto.Uint32(uint32(config.BuyFeature.Spins(config))),
+		Rtp:       proto.Uint32(uint32(math.Round(rtp * 10000))),
+	}
+}
@@ -0,0 +1,146 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	pb "github.com/wfunc/slot-game/internal/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSlotHandlerAnteAndBuyFeature(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}, &models.Transaction{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "feature_user", Nickname: "Feature", Phone: "12345678912", Email: "feature@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   1000000,
+		GameState: "idle",
+		LastSync:  time.Now(),
+	}
+	slotType := pb.ESlotType_e_slot_type_pharaoh
+	data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+	handler.handleEnterRoom(session, data)
+	engine := session.ClassicEngine
+	engine.SetRandomGenerator(slot.NewDRBGRandomGenerator(7))
+
+	// 进入房间时下发加注和购买配置
+	config := engine.GetConfig()
+	if ante := convertAnte(config); ante.GetCostRate() != 125 || ante.GetRtp() < 9400 || ante.GetRtp() > 9700 {
+		t.Errorf("ante info = %+v", ante)
+	}
+	if buy := convertBuyFeature(config); buy.GetPriceRate() != 1000 || buy.GetFreeSpins() != 10 {
+		t.Errorf("buy feature info = %+v", buy)
+	}
+	if convertAnte(slot.GetLuckySevenConfig()) != nil {
+		t.Error("777 should not offer ante")
+	}
+
+	// 加注按1.25倍扣费并记录加注流水
+	balance := session.Balance
+	spin, _ := proto.Marshal(&pb.M_1902Tos{BetVal: proto.Uint32(160), Ante: proto.Bool(true)})
+	handler.handleStartGame(session, spin)
+	if spent := balance - session.Balance; spent != 200 {
+		t.Errorf("ante spin charged %d, want 200", spent)
+	}
+	var anteTx models.Transaction
+	if err := db.Where("sub_type = ?", models.BetSubTypeAnte).First(&anteTx).Error; err != nil {
+		t.Fatalf("ante transaction not recorded: %v", err)
+	}
+	if anteTx.Amount != 200 || anteTx.Type != "bet" {
+		t.Errorf("ante transaction = %+v", anteTx)
+	}
+
+	// 购买免费游戏：扣费后之后的开始游戏为免费旋转
+	handler.collectPendingGamble(session)
+	balance = session.Balance
+	buy, _ := proto.Marshal(&pb.M_1910Tos{BetVal: proto.Uint32(160)})
+	handler.handleBuyFeature(session, buy)
+	if balance-session.Balance != 1600 {
+		t.Fatalf("buy feature charged %d, want 1600", balance-session.Balance)
+	}
+	if data := engine.GetSession(session.ID); data == nil || data.FreeGameState.FreeSpinsLeft != 10 {
+		t.Fatalf("free game not started after purchase")
+	}
+	var buyTx models.Transaction
+	if err := db.Where("sub_type = ?", models.BetSubTypeBuyFeature).First(&buyTx).Error; err != nil {
+		t.Fatalf("buy transaction not recorded: %v", err)
+	}
+	if buyTx.Amount != 1600 {
+		t.Errorf("buy transaction amount = %d", buyTx.Amount)
+	}
+
+	balance = session.Balance
+	handler.handleStartGame(session, spin)
+	if session.Balance != balance {
+		t.Errorf("free spin charged %d", balance-session.Balance)
+	}
+	if data := engine.GetSession(session.ID); data.FreeGameState.FreeSpinsPlayed != 1 {
+		t.Errorf("free spins played = %d, want 1", data.FreeGameState.FreeSpinsPlayed)
+	}
+
+	// 免费游戏进行中不能再次购买
+	balance = session.Balance
+	handler.handleBuyFeature(session, buy)
+	if session.Balance != balance {
+		t.Error("purchase during free game should be refunded")
+	}
+}
+
+func TestSlotHandlerBuyFeatureChargeFails(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.Transaction{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "buy_fail_user", Nickname: "BuyFail", Phone: "12345678913", Email: "buy_fail@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   1000000,
+		GameState: "idle",
+		LastSync:  time.Now(),
+	}
+	slotType := pb.ESlotType_e_slot_type_pharaoh
+	data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+	handler.handleEnterRoom(session, data)
+	engine := session.ClassicEngine
+
+	// 游戏结果表不存在，扣费事务回滚：不扣钱也不开启免费游戏
+	buy, _ := proto.Marshal(&pb.M_1910Tos{BetVal: proto.Uint32(160)})
+	handler.handleBuyFeature(session, buy)
+	if session.Balance != 1000000 || session.GameState != "idle" {
+		t.Errorf("failed purchase changed session: balance=%d state=%s", session.Balance, session.GameState)
+	}
+	if data := engine.GetSession(session.ID); data != nil && data.FreeGameState.IsActive() {
+		t.Error("free game started although the charge failed")
+	}
+	var wallet models.Wallet
+	db.Where("user_id = ?", user.ID).First(&wallet)
+	if wallet.Coins != 100000 || wallet.TotalBet != 0 {
+		t.Errorf("failed purchase changed wallet: coins=%d total_bet=%d", wallet.Coins, wallet.TotalBet)
+	}
+}
@@ -0,0 +1,160 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	"github.com/wfunc/slot-game/internal/pb"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// slotGambleGuesses 协议竞猜选项对应的博倍玩法和竞猜
+var slotGambleGuesses = map[pb.ESlotGambleGuess]struct {
+	mode  slot.GambleMode
+	guess slot.GambleGuess
+}{
+	pb.ESlotGambleGuess_e_slot_gamble_guess_red:   {slot.GambleModeColor, slot.GambleGuessRed},
+	pb.ESlotGambleGuess_e_slot_gamble_guess_black: {slot.GambleModeColor, slot.GambleGuessBlack},
+	pb.ESlotGambleGuess_e_slot_gamble_guess_high:  {slot.GambleModeHighLow, slot.GambleGuessHigh},
+	pb.ESlotGambleGuess_e_slot_gamble_guess_low:   {slot.GambleModeHighLow, slot.GambleGuessLow},
+}
+
+// handleGamble 处理博倍竞猜（法老王/777中奖后）
+// 猜错时本局赢取清零并结束博倍，猜中后可继续竞猜或收分
+func (h *SlotHandler) handleGamble(session *SlotSessionSimple, data []byte) {
+	req := &pb.M_1908Tos{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		log.Printf("[SlotHandler] 解析博倍竞猜失败: %v", err)
+		return
+	}
+	choice, ok := slotGambleGuesses[req.GetGuess()]
+	if !ok {
+		log.Printf("[SlotHandler] 博倍竞猜选项无效: %v", req.GetGuess())
+		return
+	}
+
+	session.mu.RLock()
+	engine := session.ClassicEngine
+	session.mu.RUnlock()
+	if engine == nil {
+		log.Printf("[SlotHandler] 玩家 %s 当前机台不支持博倍", session.ID)
+		return
+	}
+
+	gamble, round, err := engine.PlayGamble(session.ID, choice.mode, choice.guess)
+	if err != nil {
+		log.Printf("[SlotHandler] 博倍竞猜失败: %v", err)
+		return
+	}
+
+	// 猜错直接结束本局（赢取为0）
+	if !gamble.IsActive() {
+		h.settleGamble(session, gamble)
+	}
+
+	resp := &pb.M_1908Toc{
+		Gamble: convertGamble(gamble),
+		Card: &pb.PSlotGambleCard{
+			Rank: proto.Uint32(uint32(round.Card.Rank)),
+			Suit: proto.Uint32(uint32(round.Card.Suit)),
+		},
+		Won: proto.Bool(round.Won),
+	}
+	if err := h.sendMessage(session, 1908, resp); err != nil {
+		log.Printf("[SlotHandler] 发送博倍竞猜结果失败: %v", err)
+	}
+}
+
+// handleGambleCollect 处理博倍收分
+func (h *SlotHandler) handleGambleCollect(session *SlotSessionSimple, data []byte) {
+	gamble := h.collectPendingGamble(session)
+	if gamble == nil {
+		log.Printf("[SlotHandler] 玩家 %s 没有进行中的博倍", session.ID)
+		return
+	}
+
+	resp := &pb.M_1909Toc{
+		Win: proto.Uint32(uint32(gamble.Stake)),
+	}
+	if err := h.sendMessage(session, 1909, resp); err != nil {
+		log.Printf("[SlotHandler] 发送博倍收分结果失败: %v", err)
+	}
+}
+
+// collectPendingGamble 收分结束会话中进行中的博倍，没有博倍时返回空
+// 开始下一局、切换机台或断开连接时自动收分，避免赢取丢失
+func (h *SlotHandler) collectPendingGamble(session *SlotSessionSimple) *slot.GambleState {
+	session.mu.RLock()
+	engine := session.ClassicEngine
+	session.mu.RUnlock()
+	if engine == nil || engine.GetGamble(session.ID) == nil {
+		return nil
+	}
+
+	gamble, err := engine.CollectGamble(session.ID)
+	if err != nil {
+		log.Printf("[SlotHandler] 博倍收分失败: %v", err)
+		return nil
+	}
+	h.settleGamble(session, gamble)
+	return gamble
+}
+
+// settleGamble 博倍结束后结算赢取（计入落币数并保存结果）
+func (h *SlotHandler) settleGamble(session *SlotSessionSimple, gamble *slot.GambleState) {
+	session.mu.Lock()
+	session.TotalWin = gamble.Stake
+	session.TotalDownCoins += gamble.Stake
+	userIDNum := session.UserID
+	session.mu.Unlock()
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := h.walletRepo.UpdateGameStatsTx(tx, userIDNum, 0, gamble.Stake, 0, gamble.Stake); err != nil {
+			return fmt.Errorf("更新用户资产失败: %w", err)
+		}
+
+		gameResult := &models.GameResult{
+			UserID:     userIDNum,
+			GameID:     h.gameID,
+			SessionID:  0,
+			RoundID:    gamble.ID + "-gamble",
+			BetAmount:  0,
+			WinAmount:  gamble.Stake,
+			Multiplier: float64(gamble.Stake) / float64(gamble.BetAmount),
+			Result: models.JSONMap{
+				"gamble": gamble,
+			},
+			PlayedAt: time.Now(),
+		}
+		if err := tx.Create(gameResult).Error; err != nil {
+			return fmt.Errorf("创建游戏结果失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[SlotHandler] 博倍数据库操作失败: %v", err)
+	}
+
+	h.pushGameData(session)
+}
+
+// convertGamble 转换博倍状态
+func convertGamble(gamble *slot.GambleState) *pb.PSlotGamble {
+	if gamble == nil {
+		return nil
+	}
+	return &pb.PSlotGamble{
+		Id:          proto.String(gamble.ID),
+		BetVal:      proto.Uint32(uint32(gamble.BetAmount)),
+		OriginalWin: proto.Uint32(uint32(gamble.OriginalWin)),
+		Stake:       proto.Uint32(uint32(gamble.Stake)),
+		Rounds:      proto.Uint32(uint32(len(gamble.Rounds))),
+		MaxRounds:   proto.Uint32(uint32(gamble.MaxRounds)),
+		CanDouble:   proto.Bool(gamble.CanDouble()),
+		Finished:    proto.Bool(!gamble.IsActive()),
+	}
+}
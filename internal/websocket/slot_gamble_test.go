@@ -0,0 +1,89 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	pb "github.com/wfunc/slot-game/internal/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSlotHandlerGamble(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "gamble_user", Nickname: "Gamble", Phone: "12345678911", Email: "gamble@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   1000000,
+		GameState: "idle",
+		LastSync:  time.Now(),
+	}
+	slotType := pb.ESlotType_e_slot_type_777
+	data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+	handler.handleEnterRoom(session, data)
+	engine := session.ClassicEngine
+	engine.SetRandomGenerator(slot.NewDRBGRandomGenerator(3))
+
+	// 转到可以博倍的中奖为止，博倍中的赢取不计入落币
+	spin, _ := proto.Marshal(&pb.M_1902Tos{BetVal: proto.Uint32(100)})
+	var pending *slot.GambleState
+	for i := 0; i < 500 && pending == nil; i++ {
+		handler.handleStartGame(session, spin)
+		pending = engine.GetGamble(session.ID)
+	}
+	if pending == nil {
+		t.Fatal("no gamble offered in 500 spins")
+	}
+	downCoins := session.TotalDownCoins
+
+	guess, _ := proto.Marshal(&pb.M_1908Tos{Guess: pb.ESlotGambleGuess_e_slot_gamble_guess_red.Enum()})
+	handler.handleGamble(session, guess)
+	gamble := engine.GetGamble(session.ID)
+	if gamble != nil {
+		if gamble.Stake != pending.Stake*2 || session.TotalDownCoins != downCoins {
+			t.Fatalf("won gamble = %+v, down coins %d", gamble, session.TotalDownCoins)
+		}
+		handler.handleGambleCollect(session, nil)
+		if engine.GetGamble(session.ID) != nil {
+			t.Fatal("gamble still active after collect")
+		}
+	}
+
+	var result models.GameResult
+	if err := db.Where("round_id = ?", pending.ID+"-gamble").First(&result).Error; err != nil {
+		t.Fatalf("gamble result not recorded: %v", err)
+	}
+	if session.TotalDownCoins != downCoins+result.WinAmount {
+		t.Errorf("TotalDownCoins = %d, want %d", session.TotalDownCoins, downCoins+result.WinAmount)
+	}
+	if gamble != nil && result.WinAmount != gamble.Stake {
+		t.Errorf("collected win = %d, want %d", result.WinAmount, gamble.Stake)
+	}
+	if gamble == nil && result.WinAmount != 0 {
+		t.Errorf("lost gamble win = %d, want 0", result.WinAmount)
+	}
+
+	// 开始下一局时自动收分
+	handler.handleStartGame(session, spin)
+	if g := engine.GetGamble(session.ID); g != nil {
+		handler.handleStartGame(session, spin)
+		if g2 := engine.GetGamble(session.ID); g2 != nil && g2.ID == g.ID {
+			t.Error("pending gamble should be collected before the next spin")
+		}
+	}
+}
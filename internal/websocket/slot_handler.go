@@ -2,6 +2,8 @@ package websocket
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/wfunc/slot-game/internal/game"
 	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/models"
 	"github.com/wfunc/slot-game/internal/pb"
@@ -23,18 +26,27 @@ type SlotSessionSimple struct {
 	ID          string
 	UserID      uint  // 添加用户ID字段
 	Conn        *websocket.Conn
-	Engine      *slot.GoldenWildCascadeEngine // 使用具体的引擎类型
+	SlotType    pb.ESlotType                  // 拉霸机场类型
+	Engine      *slot.GoldenWildCascadeEngine // 使用具体的引擎类型（麻将）
+	ClassicEngine *slot.SlotEngine            // 支付线/全路径引擎（法老王、777）
 	Codec       *ProtobufCodec
 	CurrentBet  uint32
 	Balance     int64
 	GameState   string // "idle", "playing", "free_spin"
-	FreeSpins   int
+	FreeGame    slot.FreeGameState // 免费游戏状态
+	BonusGame   *slot.BonusGameState // 进行中的奖励游戏
 	TotalWin    int64           // 单次中奖金额
 	TotalDownCoins int64        // 累计落币数（总落币）
 	LastSync    time.Time
 	mu          sync.RWMutex
 }
 
+// mahjongMachineID 麻将机台在旋转统计中的机台ID
+const mahjongMachineID = "mahjong"
+
+// mahjongMaxWinMultiplier 麻将机台未配置最高赢取时的默认上限（下注额倍数）
+const mahjongMaxWinMultiplier = 5000
+
 // SlotHandler 处理老虎机游戏的WebSocket连接
 type SlotHandler struct {
 	sessions       map[string]*SlotSessionSimple
@@ -42,7 +54,13 @@ type SlotHandler struct {
 	db             *gorm.DB
 	walletRepo     repository.WalletRepository
 	jackpotRepo    *repository.JackpotRepository
+	statsRepo      *repository.SlotStatsRepository  // 旋转统计（按机台、日期、下注档位）
 	gameID         uint  // 当前游戏ID（老虎机）
+	devID          string  // 设备ID（彩金推送）
+	devNo          uint32  // 机台号（彩金推送）
+	jackpotRNG     slot.RandomGenerator  // 彩金随机触发使用的随机数
+	machines       *game.SlotMachineRegistry  // 法老王、777的机台引擎（按机台ID，数据库配置热加载）
+	themes         *slot.ThemeManager  // 主题包注册的主题（为空时麻将引擎使用内置主题）
 	configHandler  *ConfigHandler  // 配置处理器
 	logger         *zap.Logger     // 日志记录器
 }
@@ -85,12 +103,58 @@ func NewSlotHandler(db *gorm.DB) *SlotHandler {
 		db:            db,
 		walletRepo:    walletRepo,
 		jackpotRepo:   jackpotRepo,
+		statsRepo:     repository.NewSlotStatsRepository(db),
 		gameID:        game.ID,
+		devID:         defaultSlotDevID,
+		devNo:         defaultSlotDevNo,
+		jackpotRNG:    slot.NewCryptoRandomGenerator(),
+		machines:      newBuiltinMachineRegistry(db, logger),
 		configHandler: configHandler,
 		logger:        logger,
 	}
 }
 
+// mahjongCascadeConfig 麻将机台的消除式配置
+// 以默认配置为基础，叠加游戏记录 config 列中的配置（键与 CascadeConfig 的JSON字段一致，如 max_win、jackpot），
+// 每次进入房间时读取，配置无效时使用默认配置
+func (h *SlotHandler) mahjongCascadeConfig() *slot.CascadeConfig {
+	newDefault := func() *slot.CascadeConfig {
+		config := slot.GetDefaultCascadeConfig()
+		config.MaxWin = &slot.MaxWinConfig{Multiplier: mahjongMaxWinMultiplier}
+		config.Jackpot = slot.DefaultJackpotConfig()
+		return config
+	}
+	config := newDefault()
+
+	var record models.Game
+	if err := h.db.Select("config").First(&record, h.gameID).Error; err != nil {
+		h.logger.Warn("[SlotHandler] 读取麻将机台配置失败，使用默认配置", zap.Error(err))
+		return config
+	}
+	if len(record.Config) == 0 {
+		return config
+	}
+	data, err := json.Marshal(record.Config)
+	if err == nil {
+		err = json.Unmarshal(data, config)
+	}
+	if err == nil {
+		err = slot.ValidateCascadeConfig(config)
+	}
+	if err != nil {
+		h.logger.Warn("[SlotHandler] 麻将机台配置无效，使用默认配置", zap.Error(err))
+		return newDefault()
+	}
+	return config
+}
+
+// SetThemeManager 使用主题包加载器的主题管理器，之后进入房间创建的麻将引擎按主题包渲染
+func (h *SlotHandler) SetThemeManager(themes *slot.ThemeManager) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.themes = themes
+}
+
 // HandleConnection 处理新的WebSocket连接
 func (h *SlotHandler) HandleConnection(conn *websocket.Conn) {
 	sessionID := uuid.New().String()
@@ -120,7 +184,16 @@ func (h *SlotHandler) HandleConnection(conn *websocket.Conn) {
 	// 处理消息
 	h.handleMessages(session)
 	
-	// 清理会话
+	// 未收分的博倍按当前金额收分
+	h.collectPendingGamble(session)
+	
+	// 清理会话（机台引擎为各连接共用，移除本连接的引擎会话数据）
+	session.mu.RLock()
+	classicEngine := session.ClassicEngine
+	session.mu.RUnlock()
+	if classicEngine != nil {
+		classicEngine.RemoveSession(sessionID)
+	}
 	h.mu.Lock()
 	delete(h.sessions, sessionID)
 	h.mu.Unlock()
@@ -188,6 +261,16 @@ func (h *SlotHandler) handleMessages(session *SlotSessionSimple) {
 			h.logger.Warn("[SlotHandler] 客户端发送了服务端推送消息", zap.Uint16("msg_id", msgID))
 		case 1905: // 未定义消息 (处理以避免错误)
 			h.logger.Warn("[SlotHandler] 收到未定义消息", zap.Uint16("msg_id", msgID))
+		case 1906: // 奖励游戏选择
+			h.handleBonusPick(session, protoData)
+		case 1907: // 查询奖励游戏
+			h.handleBonusInfo(session, protoData)
+		case 1908: // 博倍竞猜
+			h.handleGamble(session, protoData)
+		case 1909: // 博倍收分
+			h.handleGambleCollect(session, protoData)
+		case 1910: // 购买免费游戏
+			h.handleBuyFeature(session, protoData)
 		// Config相关协议 (2000-2099)
 		case 2001, 2002, 2099:
 			// 创建临时的ConfigHandler处理这些消息
@@ -211,66 +294,75 @@ func (h *SlotHandler) handleEnterRoom(session *SlotSessionSimple, data []byte) {
 		// 对于1901请求，通常是空的，所以直接继续处理
 	}
 	
-	log.Printf("[SlotHandler] 玩家 %s 进入房间，类型: %v", session.ID, req.GetType())
+	slotType := req.GetType()
+	log.Printf("[SlotHandler] 玩家 %s 进入房间，类型: %v", session.ID, slotType)
+	
+	// 法老王和777使用注册表中对应机台的支付线/全路径引擎
+	classicEngine, err := h.classicSlotEngine(slotType)
+	if err != nil {
+		log.Printf("[SlotHandler] 获取游戏引擎失败: %v", err)
+		return
+	}
+	
+	var resp *pb.M_1901Toc
+	// 切换机台前收分上一台未结束的博倍
+	h.collectPendingGamble(session)
 	
-	// 创建游戏引擎配置
-	cascadeConfig := slot.GetDefaultCascadeConfig()
-	cascadeConfig.GridWidth = 5
-	cascadeConfig.GridHeight = 4
-	cascadeConfig.MinMatch = 3
-	cascadeConfig.MaxCascades = 10
+	// 离开上一台机台的引擎会话
+	session.mu.RLock()
+	previous := session.ClassicEngine
+	session.mu.RUnlock()
+	if previous != nil && previous != classicEngine {
+		previous.RemoveSession(session.ID)
+	}
 	
-	algorithmConfig := &slot.AlgorithmConfig{
-		ReelCount:   5,
-		RowCount:    4,
-		SymbolCount: 8,
-		TargetRTP:   0.96,
-		MinRTP:      0.94,
-		MaxRTP:      0.98,
+	if classicEngine != nil {
+		session.mu.Lock()
+		session.SlotType = slotType
+		session.Engine = nil
+		session.ClassicEngine = classicEngine
+		session.BonusGame = nil
+		session.FreeGame = slot.FreeGameState{}
+		session.mu.Unlock()
 		
-		// 符号权重配置
-		SymbolWeights: [][]int{
-			{18, 16, 14, 12, 12, 10, 8, 6},
-			{16, 18, 14, 12, 12, 10, 8, 6},
-			{14, 16, 18, 12, 12, 10, 8, 6},
-			{12, 14, 16, 18, 12, 10, 8, 6},
-			{12, 12, 14, 16, 18, 12, 8, 6},
-		},
+		// 恢复断线前未完成的免费游戏
+		if h.restoreFreeGame(session, classicEngine) {
+			session.mu.Lock()
+			session.GameState = "free_spin"
+			session.mu.Unlock()
+		}
 		
-		// 赔付表
-		PayTable: map[int][]int64{
-			0: {0, 0, 20, 60, 200},
-			1: {0, 0, 25, 75, 250},
-			2: {0, 0, 30, 90, 300},
-			3: {0, 0, 15, 45, 150},
-			4: {0, 0, 12, 36, 120},
-			5: {0, 0, 10, 30, 100},
-			6: {0, 0, 8, 24, 80},
-			7: {0, 0, 6, 18, 60},
-		},
+		resp = &pb.M_1901Toc{}
+		resp.BetVal, resp.Odds = classicOddsTable(classicEngine.GetConfig())
+		resp.Ante = convertAnte(classicEngine.GetConfig())
+		resp.BuyFeature = convertBuyFeature(classicEngine.GetConfig())
+	} else {
+		// 创建游戏引擎配置
+		cascadeConfig := h.mahjongCascadeConfig()
 		
-		Algorithm:    slot.AlgorithmTypeClassic,
-		Volatility:   0.55,
-		HitFrequency: 0.4,
-	}
-	
-	// 创建游戏引擎
-	session.mu.Lock()
-	session.Engine = slot.NewGoldenWildCascadeEngine(algorithmConfig, cascadeConfig)
-	session.mu.Unlock()
-	
-	// 构造响应
-	resp := &pb.M_1901Toc{
-		BetVal: []uint32{10, 20, 50, 100, 200, 500, 1000}, // 下注档位
-		Odds: []*pb.PSlotOdds{
-			{Odds: proto.Uint32(1), Val: proto.Uint32(10)},
-			{Odds: proto.Uint32(2), Val: proto.Uint32(20)},
-			{Odds: proto.Uint32(5), Val: proto.Uint32(50)},
-			{Odds: proto.Uint32(10), Val: proto.Uint32(100)},
-			{Odds: proto.Uint32(20), Val: proto.Uint32(200)},
-			{Odds: proto.Uint32(50), Val: proto.Uint32(500)},
-			{Odds: proto.Uint32(100), Val: proto.Uint32(1000)},
-		},
+		algorithmConfig := slot.GetMahjongAlgorithmConfig()
+		
+		// 恢复未完成的奖励游戏
+		bonusGame := h.loadBonusGame(session.UserID)
+		
+		// 创建游戏引擎
+		engine := slot.NewGoldenWildCascadeEngine(algorithmConfig, cascadeConfig)
+		h.mu.RLock()
+		if h.themes != nil {
+			engine.SetThemeManager(h.themes)
+		}
+		h.mu.RUnlock()
+		
+		session.mu.Lock()
+		session.SlotType = pb.ESlotType_e_slot_type_mahjong
+		session.Engine = engine
+		session.ClassicEngine = nil
+		session.BonusGame = bonusGame
+		session.mu.Unlock()
+		
+		// 构造响应
+		resp = &pb.M_1901Toc{}
+		resp.BetVal, resp.Odds = slotOddsTable(10)
 	}
 	
 	// 发送响应
@@ -295,10 +387,28 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 	betAmount := req.GetBetVal()
 	log.Printf("[SlotHandler] 玩家 %s 开始游戏，下注: %d", session.ID, betAmount)
 	
+	session.mu.RLock()
+	classicEngine := session.ClassicEngine
+	session.mu.RUnlock()
+	if classicEngine != nil {
+		h.handleClassicStartGame(session, classicEngine, betAmount, req.GetAnte())
+		return
+	}
+	
 	session.mu.Lock()
 	
+	// 奖励游戏完成前不能开始新的一手
+	if session.BonusGame.IsActive() {
+		session.mu.Unlock()
+		log.Printf("[SlotHandler] 玩家 %s 有进行中的奖励游戏", session.ID)
+		return
+	}
+	
+	// 免费旋转不扣费，按触发时的下注额计算
+	isFreeSpin := session.FreeGame.IsActive()
+	
 	// 检查余额
-	if session.Balance < int64(betAmount) {
+	if !isFreeSpin && session.Balance < int64(betAmount) {
 		session.mu.Unlock()
 		log.Printf("[SlotHandler] 玩家余额不足: %d < %d", session.Balance, betAmount)
 		return
@@ -311,10 +421,17 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 		return
 	}
 	
-	// 扣除下注金额
-	session.Balance -= int64(betAmount)
-	session.CurrentBet = betAmount
-	session.GameState = "playing"
+	// 免费旋转次数在旋转成功后才扣减
+	if isFreeSpin {
+		session.FreeGame.Resume(int64(betAmount))
+		betAmount = uint32(session.FreeGame.TriggerBet)
+		session.GameState = "free_spin"
+	} else {
+		// 扣除下注金额
+		session.Balance -= int64(betAmount)
+		session.CurrentBet = betAmount
+		session.GameState = "playing"
+	}
 	
 	engine := session.Engine
 	session.mu.Unlock()
@@ -330,6 +447,7 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 		},
 		ThemeID:     "mahjong",
 		EnableTheme: false,
+		FreeSpin:    isFreeSpin,
 	}
 	
 	// 执行游戏
@@ -337,6 +455,14 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 	result, err := engine.SpinWithGoldenWild(ctx, spinReq)
 	if err != nil {
 		log.Printf("[SlotHandler] 游戏执行失败: %v", err)
+		session.mu.Lock()
+		if isFreeSpin {
+			session.GameState = "free_spin"
+		} else {
+			session.Balance += int64(betAmount)
+			session.GameState = "idle"
+		}
+		session.mu.Unlock()
 		return
 	}
 
@@ -355,77 +481,101 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 	// 检测是否触发Animal游戏
 	animalTrigger = triggerDetector.DetectAnimalTrigger(finalGrid)
 
+	// 按初始盘面的Scatter数量检查免费游戏触发
+	freeFeature := engine.GetAlgorithmConfig().FeatureConfigs[slot.AbstractFeatureTypeFreeSpin]
+	_, freeSpins := freeFeature.FreeSpinsFor(result.InitialGrid)
+	var freeConfig *slot.FreeGameConfig
+	if freeFeature != nil {
+		freeConfig = freeFeature.FreeGame
+	}
+	
+	// 按初始盘面的Bonus数量检查奖励游戏触发（派彩在玩家选择结束后结算）
+	bonusFeature := engine.GetAlgorithmConfig().FeatureConfigs[slot.AbstractFeatureTypeBonus]
+	bonusGame := newSlotBonusGame(bonusFeature, result.InitialGrid, int64(betAmount))
+
+	// 达到最高赢取时本局结束，不再触发免费游戏和奖励游戏
+	maxWinReached := result.MaxWinReached
+	if maxWinReached {
+		freeSpins = 0
+		bonusGame = nil
+	}
+	
 	// 更新余额和落币数
 	session.mu.Lock()
+	totalWin := result.TotalWin
+	if isFreeSpin {
+		// 免费旋转按倍率派彩，可再触发；本轮累计达到最高赢取时提前结束
+		session.FreeGame.Next()
+		totalWin = int64(float64(totalWin) * session.FreeGame.Multiplier)
+		var roundCapped bool
+		totalWin, roundCapped = engine.GetCascadeConfig().MaxWin.ApplyRound(session.FreeGame.FreeGameWin, totalWin, int64(betAmount))
+		if roundCapped {
+			maxWinReached = true
+			session.FreeGame.Stop()
+		} else {
+			session.FreeGame.Retrigger(freeSpins, freeConfig)
+		}
+		session.FreeGame.Record(totalWin)
+	} else if freeSpins > 0 {
+		session.FreeGame.Trigger(freeSpins, int64(betAmount), freeConfig)
+	}
+	
 	// 余额不变（不加中奖金额）
 	// session.Balance 保持不变，中奖金额累计到落币数
-	session.TotalWin = result.TotalWin  // 记录本次中奖
-	session.TotalDownCoins += result.TotalWin  // 累计到总落币数
+	session.TotalWin = totalWin  // 记录本次中奖
+	session.TotalDownCoins += totalWin  // 累计到总落币数
 
-	// 检查免费游戏
-	isFree := false
-	currentFree := uint32(0)
-	totalFree := uint32(0)
-	if result.CascadeCount >= 3 {
-		isFree = true
-		currentFree = 1
-		totalFree = uint32(result.CascadeCount)
-		session.FreeSpins = result.CascadeCount - 1
+	// 免费游戏进度
+	isFree := isFreeSpin || session.FreeGame.IsActive()
+	currentFree := uint32(session.FreeGame.FreeSpinsPlayed)
+	totalFree := uint32(session.FreeGame.FreeSpinsTotal)
+	freeGame := session.FreeGame
+	roundWin := totalWin  // 免费游戏中为本轮免费游戏累计赢取
+	if isFreeSpin {
+		roundWin = freeGame.FreeGameWin
 	}
 	
 	session.GameState = "idle"
+	if session.FreeGame.IsActive() {
+		session.GameState = "free_spin"
+	}
+	if bonusGame != nil {
+		bonusGame.SpinWin = roundWin
+		session.BonusGame = bonusGame
+		session.GameState = "bonus"
+	}
 	userIDNum := session.UserID
 	session.mu.Unlock()
 	
-	// 在事务中更新JP池和用户资产
-	err = h.db.Transaction(func(tx *gorm.DB) error {
-		// 累计JP池
-		if err := h.jackpotRepo.AccumulateJackpot(tx, h.gameID, int64(betAmount)); err != nil {
-			return fmt.Errorf("累计JP池失败: %w", err)
-		}
-		
-		// 更新用户钱包统计（包括投币数和落币数）
-		coinsIn := int64(betAmount)  // 投币数 = 下注金额
-		coinsOut := result.TotalWin  // 落币数 = 赢取金额
-		
-		if err := h.walletRepo.UpdateGameStatsTx(tx, userIDNum, int64(betAmount), result.TotalWin, coinsIn, coinsOut); err != nil {
-			return fmt.Errorf("更新用户资产失败: %w", err)
-		}
-		
-		// 获取最终网格（如果有消除步骤，使用最后一步的网格）
-		var finalGrid [][]int
-		if len(result.CascadeDetails) > 0 {
-			finalGrid = result.CascadeDetails[len(result.CascadeDetails)-1].GridAfter
-		} else {
-			finalGrid = result.InitialGrid
-		}
-		
-		// 创建游戏结果记录
-		gameResult := &models.GameResult{
-			UserID:    userIDNum,
-			GameID:    h.gameID,
-			SessionID: 0, // 暂时使用0，实际应该从游戏会话获取
-			RoundID:   uuid.New().String(),
-			BetAmount: int64(betAmount),
-			WinAmount: result.TotalWin,
-			Multiplier: float64(result.TotalWin) / float64(betAmount),
-			Result: models.JSONMap{
-				"cascade_count": result.CascadeCount,
-				"initial_grid": result.InitialGrid,
-				"final_grid": finalGrid,
-			},
-			IsJackpot: false,
-			IsBonus:   isFree,
-			PlayedAt:  time.Now(),
-		}
-		
-		if err := tx.Create(gameResult).Error; err != nil {
-			return fmt.Errorf("创建游戏结果失败: %w", err)
-		}
-		
-		return nil
-	})
+	// 本局结果明细
+	detail := models.JSONMap{
+		"cascade_count": result.CascadeCount,
+		"initial_grid": result.InitialGrid,
+		"final_grid": finalGrid,
+		"is_free_spin": isFreeSpin,
+		"free_game": freeGame,
+		"max_win_reached": maxWinReached,
+	}
 	
+	// 在事务中更新JP池、派发彩金和用户资产
+	jackpotSpin := slot.GridJackpotSpin(int64(betAmount), result.InitialGrid)
+	if isFreeSpin {
+		jackpotSpin.BetAmount = 0
+	}
+	// 消除玩法没有解析计算的理论RTP（目标RTP只是调控参数），不参与漂移检测
+	stat := &repository.SlotSpinStat{
+		MachineID: mahjongMachineID,
+		BetLevel:  int64(betAmount),
+		WinAmount: totalWin,
+		FreeSpin:  isFreeSpin,
+	}
+	if freeSpins > 0 {
+		stat.Features++
+	}
+	if bonusGame != nil {
+		stat.Features++
+	}
+	jackpots, err := h.recordSlotRound(userIDNum, int64(betAmount), totalWin, isFreeSpin, detail, bonusGame, engine.GetCascadeConfig().Jackpot, jackpotSpin, stat, nil)
 	if err != nil {
 		log.Printf("[SlotHandler] 数据库操作失败: %v", err)
 	}
@@ -442,13 +592,15 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 	
 	// 构造响应
 	resp := &pb.M_1902Toc{
-		BetVal:      proto.Uint32(betAmount),
-		Win:         proto.Uint32(uint32(result.TotalWin)),
-		TotalWin:    proto.Uint32(uint32(result.TotalWin)),
-		IsFree:      proto.Bool(isFree),
-		CurrentFree: proto.Uint32(currentFree),
-		TotalFree:   proto.Uint32(totalFree),
-		Result:      slotResult,
+		BetVal:       proto.Uint32(betAmount),
+		Win:          proto.Uint32(uint32(totalWin)),
+		TotalWin:     proto.Uint32(uint32(roundWin)),
+		IsFree:       proto.Bool(isFree),
+		CurrentFree:  proto.Uint32(currentFree),
+		TotalFree:    proto.Uint32(totalFree),
+		Result:       slotResult,
+		Bonus:        convertBonusGame(bonusGame.View()),
+		Anticipation: convertAnticipation(result.Anticipation),
 	}
 	
 	// 发送响应
@@ -487,10 +639,120 @@ func (h *SlotHandler) handleStartGame(session *SlotSessionSimple, data []byte) {
 		}
 	}
 	
+	// 彩金通知
+	h.notifyJackpots(session, jackpots)
+	
 	// 推送最新数据
 	h.pushGameData(session)
 }
 
+// recordSlotRound 在事务中累计JP池、派发彩金、更新用户资产并保存本局结果和旋转统计
+// betAmount 为派彩基准下注（免费旋转为触发时的下注），免费旋转不计投币也不参与彩金
+// persist 在同一事务中保存本局的其他数据（可为空）
+// 派奖时JP池被其他机台的事务改动则整个事务重做，最多 jackpotRetryLimit 次
+// 返回本局派发的彩金（事务失败时为空）
+func (h *SlotHandler) recordSlotRound(userID uint, betAmount, totalWin int64, isFreeSpin bool, detail models.JSONMap, bonusGame *slot.BonusGameState, jackpot *slot.JackpotConfig, jackpotSpin slot.JackpotSpin, stat *repository.SlotSpinStat, persist func(tx *gorm.DB) error) ([]jackpotAward, error) {
+	var awards []jackpotAward
+	round := func(tx *gorm.DB) error {
+		// 免费旋转没有实际下注，不累计JP池也不计投币
+		stakeAmount := betAmount
+		if isFreeSpin {
+			stakeAmount = 0
+		}
+		
+		// 累计JP池
+		if stakeAmount > 0 {
+			if err := h.jackpotRepo.AccumulateJackpot(tx, h.gameID, stakeAmount); err != nil {
+				return fmt.Errorf("累计JP池失败: %w", err)
+			}
+		}
+		
+		// 累计后判断彩金
+		var err error
+		awards, err = h.triggerJackpots(tx, jackpot, jackpotSpin)
+		if err != nil {
+			return err
+		}
+		jackpotWin := jackpotTotal(awards)
+		
+		// 更新用户钱包统计（包括投币数和落币数）
+		coinsIn := stakeAmount  // 投币数 = 下注金额
+		coinsOut := totalWin + jackpotWin  // 落币数 = 赢取金额 + 彩金
+		
+		if err := h.walletRepo.UpdateGameStatsTx(tx, userID, stakeAmount, coinsOut, coinsIn, coinsOut); err != nil {
+			return fmt.Errorf("更新用户资产失败: %w", err)
+		}
+		
+		// 创建游戏结果记录
+		gameResult := &models.GameResult{
+			UserID:    userID,
+			GameID:    h.gameID,
+			SessionID: 0, // 暂时使用0，实际应该从游戏会话获取
+			RoundID:   uuid.New().String(),
+			BetAmount: stakeAmount,
+			WinAmount: totalWin + jackpotWin,
+			Multiplier: float64(totalWin) / float64(betAmount),
+			Result:    detail,
+			IsJackpot: len(awards) > 0,
+			IsBonus:   isFreeSpin,
+			PlayedAt:  time.Now(),
+		}
+		if bonusGame != nil {
+			gameResult.Result["bonus_game_id"] = bonusGame.ID
+		}
+		if len(awards) > 0 {
+			gameResult.Result["jackpots"] = awards
+		} else {
+			delete(gameResult.Result, "jackpots") // 清除重试前的彩金
+		}
+		
+		if err := tx.Create(gameResult).Error; err != nil {
+			return fmt.Errorf("创建游戏结果失败: %w", err)
+		}
+		
+		// 派发彩金并记录中奖历史
+		if err := h.payJackpots(tx, userID, gameResult.ID, awards); err != nil {
+			return err
+		}
+		
+		// 保存触发的奖励游戏，断线后可继续选择
+		if bonusGame != nil {
+			if err := saveBonusGame(tx, userID, bonusGame); err != nil {
+				return err
+			}
+		}
+		
+		// 累加旋转统计（彩金单独统计，不计入RTP）
+		if stat != nil {
+			stat.BetAmount = stakeAmount
+			stat.JackpotHits = len(awards)
+			stat.JackpotWin = jackpotWin
+			stat.PlayedAt = gameResult.PlayedAt
+			if err := h.statsRepo.Record(tx, stat); err != nil {
+				return err
+			}
+		}
+		
+		if persist != nil {
+			return persist(tx)
+		}
+		return nil
+	}
+	
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = h.db.Transaction(round)
+		if !errors.Is(err, repository.ErrJackpotChanged) || attempt >= jackpotRetryLimit {
+			break
+		}
+		log.Printf("[SlotHandler] JP池已变动，重做本局事务（第%d次）", attempt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return awards, nil
+}
+
 // pushGameData 推送游戏数据
 func (h *SlotHandler) pushGameData(session *SlotSessionSimple) {
 	session.mu.RLock()
@@ -565,17 +827,25 @@ func (h *SlotHandler) sendMessage(session *SlotSessionSimple, msgID uint16, msg
 func convertSymbols(row []int) []pb.ESlotBetType {
 	result := make([]pb.ESlotBetType, len(row))
 	for i, symbolID := range row {
-		if symbolID == -1 {
-			result[i] = pb.ESlotBetType_e_slot_bet_type_wild
-		} else if symbolID >= 0 && symbolID <= 7 {
-			result[i] = pb.ESlotBetType(symbolID)
-		} else {
-			result[i] = pb.ESlotBetType_e_slot_bet_type_0
-		}
+		result[i] = convertSymbol(symbolID)
 	}
 	return result
 }
 
+// convertSymbol 转换单个符号ID为枚举类型
+func convertSymbol(symbolID int) pb.ESlotBetType {
+	if symbolID == -1 {
+		return pb.ESlotBetType_e_slot_bet_type_wild
+	} else if symbolID == slot.SYMBOL_SCATTER {
+		return pb.ESlotBetType_e_slot_bet_type_free
+	} else if symbolID == slot.SYMBOL_BONUS {
+		return pb.ESlotBetType_e_slot_bet_type_bonus
+	} else if symbolID >= 0 && symbolID <= 7 {
+		return pb.ESlotBetType(symbolID)
+	}
+	return pb.ESlotBetType_e_slot_bet_type_0
+}
+
 // convertRewards 转换游戏奖励
 func convertRewards(result *slot.GoldenWildResult) []*pb.PSlotReward {
 	var rewards []*pb.PSlotReward
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
 	"github.com/wfunc/slot-game/internal/models"
 	pb "github.com/wfunc/slot-game/internal/pb"
 	"google.golang.org/protobuf/proto"
@@ -28,11 +29,17 @@ func setupTestSlotDB(t *testing.T) *gorm.DB {
 		&models.Game{},
 		&models.Transaction{},
 		&models.Jackpot{},
+		&models.SlotStatBucket{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
 
+	// 必中奖池的种子需要加密密钥
+	if err := slot.SetJackpotSeedKey("test-jackpot-secret"); err != nil {
+		t.Fatalf("failed to set jackpot seed key: %v", err)
+	}
+
 	// 创建测试游戏
 	game := &models.Game{
 		Name:        "Test Slot",
@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/pb"
+	"github.com/wfunc/slot-game/internal/repository"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// 默认设备信息（未配置 game.slot.device_id/device_no 时使用）
+const (
+	defaultSlotDevID = "SLOT001"
+	defaultSlotDevNo = 1
+)
+
+// jackpotRetryLimit 派奖时JP池被并发改动的最多尝试次数
+const jackpotRetryLimit = 3
+
+// jackpotAward 本局派发的彩金
+type jackpotAward struct {
+	Level  string `json:"level"`
+	Amount int64  `json:"amount"`
+}
+
+// jackpotTotal 彩金合计
+func jackpotTotal(awards []jackpotAward) int64 {
+	var total int64
+	for _, award := range awards {
+		total += award.Amount
+	}
+	return total
+}
+
+// SetDevice 设置彩金推送中的设备ID和机台号
+func (h *SlotHandler) SetDevice(devID string, devNo uint32) {
+	h.devID = devID
+	h.devNo = devNo
+}
+
+// triggerJackpots 在旋转事务中读取JP池（已累计本局抽成）并按机台规则判断触发的彩金
+func (h *SlotHandler) triggerJackpots(tx *gorm.DB, config *slot.JackpotConfig, spin slot.JackpotSpin) ([]jackpotAward, error) {
+	if config == nil || spin.BetAmount <= 0 {
+		return nil, nil
+	}
+	jackpots, err := h.jackpotRepo.GetAllJackpotsTx(tx, h.gameID)
+	if err != nil {
+		return nil, fmt.Errorf("获取JP池失败: %w", err)
+	}
+
+	pools := make([]slot.JackpotPool, 0, len(jackpots))
+	amounts := make(map[string]int64, len(jackpots))
+	for _, jp := range jackpots {
+		pools = append(pools, slot.JackpotPool{
+			Level:     jp.Type,
+			Amount:    jp.Amount,
+			MaxAmount: jp.MaxAmount,
+			HitPoint:  repository.JackpotHitPoint(&jp),
+		})
+		amounts[jp.Type] = jp.Amount
+	}
+
+	var awards []jackpotAward
+	for _, level := range config.Triggered(h.jackpotRNG, spin, pools) {
+		awards = append(awards, jackpotAward{Level: level, Amount: amounts[level]})
+	}
+	return awards, nil
+}
+
+// payJackpots 派发彩金：奖池重置为种子金额并写入中奖历史（与本局结果同一事务）
+// 派发金额与判断时读取的奖池不一致时返回错误，整个事务回滚
+func (h *SlotHandler) payJackpots(tx *gorm.DB, userID, resultID uint, awards []jackpotAward) error {
+	for _, award := range awards {
+		amount, err := h.jackpotRepo.WinJackpot(tx, h.gameID, userID, 0, resultID, award.Level)
+		if err != nil {
+			return fmt.Errorf("派发彩金 %s 失败: %w", award.Level, err)
+		}
+		if amount != award.Amount {
+			return fmt.Errorf("派发彩金 %s 失败: %w", award.Level, repository.ErrJackpotChanged)
+		}
+	}
+	return nil
+}
+
+// notifyJackpots 彩金计入落币数并逐个推送中奖通知
+func (h *SlotHandler) notifyJackpots(session *SlotSessionSimple, awards []jackpotAward) {
+	if len(awards) == 0 {
+		return
+	}
+
+	session.mu.Lock()
+	session.TotalDownCoins += jackpotTotal(awards)
+	session.mu.Unlock()
+
+	for _, award := range awards {
+		log.Printf("[SlotHandler] 玩家 %s 中彩金 %s: %d", session.ID, award.Level, award.Amount)
+		notify := &pb.M_1904Toc{
+			DevId: proto.String(h.devID),
+			DevNo: proto.Uint32(h.devNo),
+			Type:  proto.Uint32(slot.JackpotLevelNumber(award.Level)),
+			Jp:    proto.Uint32(uint32(award.Amount)),
+		}
+		if err := h.sendMessage(session, 1904, notify); err != nil {
+			log.Printf("[SlotHandler] 发送彩金通知失败: %v", err)
+		}
+	}
+}
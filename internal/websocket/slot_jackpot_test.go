@@ -0,0 +1,201 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wfunc/slot-game/internal/game/slot"
+	"github.com/wfunc/slot-game/internal/models"
+	pb "github.com/wfunc/slot-game/internal/pb"
+	"github.com/wfunc/slot-game/internal/repository"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+// fixedRandom 固定返回同一个随机数
+type fixedRandom float64
+
+func (r fixedRandom) Next() float64            { return float64(r) }
+func (r fixedRandom) NextInt(min, max int) int { return min }
+func (r fixedRandom) Seed(seed int64)          {}
+
+func TestSlotHandlerJackpotAward(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}, &models.JackpotHistory{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+	handler.SetDevice("SLOT009", 9)
+
+	user := &models.User{Username: "jackpot_user", Nickname: "Jackpot", Phone: "12345678913", Email: "jackpot@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   1000000,
+		GameState: "idle",
+		LastSync:  time.Now(),
+	}
+	slotType := pb.ESlotType_e_slot_type_pharaoh
+	data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+	handler.handleEnterRoom(session, data)
+	session.ClassicEngine.SetRandomGenerator(slot.NewDRBGRandomGenerator(11))
+	spin, _ := proto.Marshal(&pb.M_1902Tos{BetVal: proto.Uint32(160)})
+
+	// 随机触发全部命中：JP1-JP3派发累计本局抽成后的奖池，JPALL未到必中金额
+	handler.jackpotRNG = fixedRandom(0)
+	downCoins := session.TotalDownCoins
+	handler.handleStartGame(session, spin)
+
+	var histories []models.JackpotHistory
+	db.Order("id").Find(&histories)
+	if len(histories) != 3 {
+		t.Fatalf("jackpot histories = %+v", histories)
+	}
+	want := map[string]int64{"JP1": 1016, "JP2": 5008, "JP3": 10008}
+	var result models.GameResult
+	if err := db.First(&result, histories[0].ResultID).Error; err != nil {
+		t.Fatalf("jackpot game result not found: %v", err)
+	}
+	if !result.IsJackpot {
+		t.Error("game result should be marked as jackpot")
+	}
+	var jackpotWin int64
+	for _, history := range histories {
+		var jp models.Jackpot
+		db.First(&jp, history.JackpotID)
+		if history.Amount != want[jp.Type] || history.PoolBefore != want[jp.Type] || history.PoolAfter != jp.MinAmount {
+			t.Errorf("%s history = %+v", jp.Type, history)
+		}
+		if jp.Amount != jp.MinAmount || jp.WinCount != 1 || jp.LastWinner != user.ID {
+			t.Errorf("%s pool after award = %+v", jp.Type, jp)
+		}
+		jackpotWin += history.Amount
+	}
+	if result.WinAmount < jackpotWin {
+		t.Errorf("game result win %d does not include jackpots %d", result.WinAmount, jackpotWin)
+	}
+	if got := session.TotalDownCoins - downCoins; got != result.WinAmount {
+		t.Errorf("down coins increased by %d, want %d", got, result.WinAmount)
+	}
+
+	// 没有随机触发时，JPALL由越过隐藏必中点的那次下注赢得
+	handler.jackpotRNG = fixedRandom(1)
+	var jpAll models.Jackpot
+	db.Where("type = ?", "JPALL").First(&jpAll)
+	hitPoint := repository.JackpotHitPoint(&jpAll)
+	db.Model(&jpAll).Update("amount", hitPoint-1)
+	handler.handleStartGame(session, spin)
+
+	var won models.JackpotHistory
+	db.Where("jackpot_id = ?", jpAll.ID).First(&won)
+	if won.Amount != hitPoint+7 || won.HitPoint != hitPoint || won.HitHash != jpAll.HitHash {
+		t.Errorf("JPALL must-hit history = %+v, hit point %d", won, hitPoint)
+	}
+	db.First(&jpAll, jpAll.ID)
+	if jpAll.Amount != jpAll.MinAmount || jpAll.WinCount != 1 || jpAll.HitHash == won.HitHash {
+		t.Errorf("JPALL after must-hit award = %+v", jpAll)
+	}
+	var count int64
+	db.Model(&models.JackpotHistory{}).Count(&count)
+	if count != 4 {
+		t.Errorf("jackpot histories = %d, want 4", count)
+	}
+
+	// 不满足任何规则时只累计不派发
+	handler.handleStartGame(session, spin)
+	db.Model(&models.JackpotHistory{}).Count(&count)
+	if count != 4 {
+		t.Errorf("jackpot histories = %d after a plain spin", count)
+	}
+
+	// 彩金单独计入旋转统计，不计入赢取
+	var bucket models.SlotStatBucket
+	if err := db.Where("machine_id = ? AND bet_level = ?", "pharaoh", 160).First(&bucket).Error; err != nil {
+		t.Fatalf("slot stat bucket not found: %v", err)
+	}
+	var jackpotSum int64
+	db.Model(&models.JackpotHistory{}).Select("SUM(amount)").Scan(&jackpotSum)
+	if bucket.Spins != 3 || bucket.PaidSpins != 3 || bucket.TotalBet != 480 || bucket.JackpotHits != 4 || bucket.JackpotWin != jackpotSum {
+		t.Errorf("slot stat bucket = %+v", bucket)
+	}
+	if bucket.TotalWin >= jackpotSum || bucket.ExpectedWin <= 0 {
+		t.Errorf("slot stat bucket win = %d, expected %.2f", bucket.TotalWin, bucket.ExpectedWin)
+	}
+}
+
+func TestSlotHandlerJackpotRetry(t *testing.T) {
+	db := setupTestSlotDB(t)
+	if err := db.AutoMigrate(&models.GameState{}, &models.GameResult{}, &models.JackpotHistory{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	handler := NewSlotHandler(db)
+
+	user := &models.User{Username: "jackpot_retry", Nickname: "Retry", Phone: "12345678914", Email: "retry@example.com", Status: "active"}
+	db.Create(user)
+	db.Create(&models.Wallet{UserID: user.ID, Coins: 100000})
+
+	// 第一次派奖前模拟其他机台的事务改动了JP1奖池
+	changed := false
+	err := db.Callback().Update().Before("gorm:update").Register("test:concurrent_jackpot", func(tx *gorm.DB) {
+		updates, ok := tx.Statement.Dest.(map[string]interface{})
+		if changed || tx.Statement.Table != "jackpots" || !ok || updates["last_winner"] == nil {
+			return
+		}
+		changed = true
+		tx.Session(&gorm.Session{NewDB: true}).Model(&models.Jackpot{}).
+			Where("type = ?", "JP1").Update("amount", gorm.Expr("amount + 1"))
+	})
+	if err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	conn := createTestWebSocketConn(t)
+	defer conn.Close()
+	session := &SlotSessionSimple{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Conn:      conn,
+		Codec:     NewProtobufCodec(),
+		Balance:   1000000,
+		GameState: "idle",
+		LastSync:  time.Now(),
+	}
+	slotType := pb.ESlotType_e_slot_type_pharaoh
+	data, _ := proto.Marshal(&pb.M_1901Tos{Type: &slotType})
+	handler.handleEnterRoom(session, data)
+	session.ClassicEngine.SetRandomGenerator(slot.NewDRBGRandomGenerator(11))
+	spin, _ := proto.Marshal(&pb.M_1902Tos{BetVal: proto.Uint32(160)})
+
+	// 奖池变动时整局事务重做，只落库一次
+	handler.jackpotRNG = fixedRandom(0)
+	handler.handleStartGame(session, spin)
+	if !changed {
+		t.Fatal("concurrent jackpot change was not simulated")
+	}
+
+	var histories []models.JackpotHistory
+	db.Order("id").Find(&histories)
+	if len(histories) != 3 {
+		t.Fatalf("jackpot histories after retry = %+v", histories)
+	}
+	var results int64
+	db.Model(&models.GameResult{}).Count(&results)
+	if results != 1 {
+		t.Errorf("game results = %d, want 1", results)
+	}
+	var bucket models.SlotStatBucket
+	if err := db.Where("machine_id = ? AND bet_level = ?", "pharaoh", 160).First(&bucket).Error; err != nil {
+		t.Fatalf("slot stat bucket not found: %v", err)
+	}
+	if bucket.Spins != 1 || bucket.JackpotHits != 3 {
+		t.Errorf("slot stat bucket = %+v", bucket)
+	}
+}